@@ -2,7 +2,6 @@ package clusterinfo
 
 import (
 	"context"
-	"strings"
 
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
@@ -12,21 +11,9 @@ import (
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 )
 
+// WasMasterNodeUpdated returns Y if a control plane node updated during the run, and N otherwise.
 func WasMasterNodeUpdated(events monitorapi.Intervals) string {
-	nodeUpdates := events.Filter(monitorapi.NodeUpdate)
-
-	for _, i := range nodeUpdates {
-		// "locator": "node/ip-10-0-240-32.us-west-1.compute.internal",
-		//            "message": "reason/NodeUpdate phase/Update config/rendered-master-757d729d8565a6f9f4e59913d4731db1 roles/control-plane,master reached desired config roles/control-plane,master",
-		// vs
-		// "locator": "node/ip-10-0-228-209.us-west-1.compute.internal",
-		//            "message": "reason/NodeUpdate phase/Update config/rendered-worker-722803a00bad408ee94572ab244ad3bc roles/worker reached desired config roles/worker",
-		if strings.Contains(i.Message.Annotations[monitorapi.AnnotationRoles], "master") {
-			return "Y"
-		}
-	}
-
-	return "N"
+	return platformidentification.AnalyzeUpdateTopology(events).MasterNodesUpdated()
 }
 
 // TODO this should be taking a client, not a kubeconfig. Can't test a kubeconfig.
@@ -41,7 +28,7 @@ func CollectClusterData(adminKubeConfig *rest.Config, masterNodeUpdated string)
 			e2e.Logf("Error building cluster data: %s", err.Error())
 		}
 		e2e.Logf("Ignoring cluster data due to previous errors: %v", clusterData)
-		return platformidentification.ClusterData{}
+		return platformidentification.ClusterData{SchemaVersion: platformidentification.ClusterDataSchemaVersion}
 	}
 
 	clusterData.MasterNodesUpdated = masterNodeUpdated
@@ -1,6 +1,8 @@
 package disruption
 
 import (
+	poll_dns "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/poll-dns"
+	poll_endpoints "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/poll-endpoints"
 	poll_service "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/poll-service"
 	watch_endpointslice "github.com/openshift/origin/pkg/cmd/openshift-tests/disruption/watch-endpointslice"
 	"github.com/spf13/cobra"
@@ -16,6 +18,8 @@ func NewDisruptionCommand(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(
 		watch_endpointslice.NewWatchEndpointSlice(streams),
 		poll_service.NewPollService(streams),
+		poll_endpoints.NewPollEndpoints(streams),
+		poll_dns.NewPollDNS(streams),
 	)
 	return cmd
 }
@@ -0,0 +1,260 @@
+package poll_dns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// Resolver is the part of net.Resolver the probers use.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+type PollDNSController struct {
+	names             []Name
+	resolver          Resolver
+	interval          time.Duration
+	timeout           time.Duration
+	nodeName          string
+	namespaceName     string
+	stopConfigMapName string
+	recorder          monitorapi.RecorderWriter
+	outFile           io.Writer
+
+	configmapLister corelisters.ConfigMapLister
+
+	informersToSync []cache.InformerSynced
+
+	probersLock sync.Mutex
+	probers     []*dnsProber
+
+	syncHandler func(ctx context.Context, key string) error
+	queue       workqueue.RateLimitingInterface
+}
+
+func NewPollDNSController(
+	names []Name,
+	resolver Resolver,
+	interval time.Duration,
+	timeout time.Duration,
+	nodeName string,
+	namespaceName string,
+	recorder monitorapi.RecorderWriter,
+	outFile io.Writer,
+	stopConfigMapName string,
+	configmapInformer coreinformers.ConfigMapInformer,
+) *PollDNSController {
+
+	c := &PollDNSController{
+		names:             names,
+		resolver:          resolver,
+		interval:          interval,
+		timeout:           timeout,
+		nodeName:          nodeName,
+		namespaceName:     namespaceName,
+		recorder:          recorder,
+		stopConfigMapName: stopConfigMapName,
+		outFile:           outFile,
+
+		configmapLister: configmapInformer.Lister(),
+		informersToSync: []cache.InformerSynced{
+			configmapInformer.Informer().HasSynced,
+		},
+
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "DNSPoller"),
+	}
+
+	c.syncHandler = c.syncDNSProbers
+
+	configmapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.queue.Add("check")
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.queue.Add("check")
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.queue.Add("check")
+		},
+	})
+
+	return c
+}
+
+func (c *PollDNSController) syncDNSProbers(ctx context.Context, key string) error {
+	_, err := c.configmapLister.ConfigMaps(c.namespaceName).Get(c.stopConfigMapName)
+	switch {
+	case err == nil:
+		c.removeAllProbers()
+		return nil
+	case apierrors.IsNotFound(err):
+		// did not find the stopConfigMap
+	case err != nil:
+		return err
+	}
+
+	c.probersLock.Lock()
+	defer c.probersLock.Unlock()
+
+	if len(c.probers) > 0 {
+		return nil
+	}
+	for _, name := range c.names {
+		fmt.Fprintf(c.outFile, "Adding and starting: %v on node/%v\n", name.Hostname, c.nodeName)
+		prober := newDNSProber(name, c.nodeName, c.resolver, c.interval, c.timeout, c.recorder)
+		prober.start(ctx)
+		c.probers = append(c.probers, prober)
+	}
+	return nil
+}
+
+func (c *PollDNSController) removeAllProbers() {
+	c.probersLock.Lock()
+	defer c.probersLock.Unlock()
+
+	if len(c.probers) == 0 {
+		fmt.Fprintf(c.outFile, "No probers running, skipping removal\n")
+		return
+	}
+
+	fmt.Fprintf(c.outFile, "Stopping and removing all probers for node/%v\n", c.nodeName)
+	for _, prober := range c.probers {
+		prober.stop()
+	}
+	c.probers = nil
+	fmt.Fprintf(c.outFile, "Stopped all probers\n")
+}
+
+func (c *PollDNSController) Run(ctx context.Context, finishedCleanup chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+	defer close(finishedCleanup)
+
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting PollDNS controller")
+	defer logger.Info("Shutting down PollDNS controller")
+
+	if !cache.WaitForNamedCacheSync("DNSPoller", ctx.Done(), c.informersToSync...) {
+		return
+	}
+	go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+
+	<-ctx.Done()
+	c.removeAllProbers()
+}
+
+func (c *PollDNSController) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *PollDNSController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.syncHandler(ctx, key.(string))
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("%v failed with : %v", key, err))
+	c.queue.AddRateLimited(key)
+
+	return true
+}
+
+// dnsProber resolves one name on an interval and records an interval for as long as resolution fails.
+type dnsProber struct {
+	name     Name
+	locator  monitorapi.Locator
+	resolver Resolver
+	interval time.Duration
+	timeout  time.Duration
+	recorder monitorapi.RecorderWriter
+
+	cancel   context.CancelFunc
+	finished chan struct{}
+
+	// failing is true while a failure interval is open, failureID is its recorder ID.
+	failing   bool
+	failureID int
+}
+
+func newDNSProber(name Name, nodeName string, resolver Resolver, interval, timeout time.Duration, recorder monitorapi.RecorderWriter) *dnsProber {
+	return &dnsProber{
+		name:     name,
+		locator:  monitorapi.NewLocator().DNSProbe(name.Target, name.Hostname, nodeName),
+		resolver: resolver,
+		interval: interval,
+		timeout:  timeout,
+		recorder: recorder,
+		finished: make(chan struct{}),
+	}
+}
+
+func (p *dnsProber) start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	go func() {
+		defer close(p.finished)
+		wait.UntilWithContext(ctx, p.probe, p.interval)
+		p.endFailure(time.Now())
+	}()
+}
+
+func (p *dnsProber) stop() {
+	p.cancel()
+	<-p.finished
+}
+
+func (p *dnsProber) probe(ctx context.Context) {
+	lookupCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	_, err := p.resolver.LookupHost(lookupCtx, p.name.Hostname)
+	now := time.Now()
+	if ctx.Err() != nil {
+		// stopping, not a failure of DNS.
+		return
+	}
+	if err == nil {
+		p.endFailure(now)
+		return
+	}
+	// one interval per outage, the error changes from lookup to lookup because it names the source port.
+	if p.failing {
+		return
+	}
+
+	p.failing = true
+	p.failureID = p.recorder.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourceDNSProbe, monitorapi.Error).
+			Locator(p.locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DNSResolutionFailedReason).HumanMessagef("failed to resolve %s: %v", p.name.Hostname, err)).
+			Display().
+			Build(now, time.Time{}),
+	)
+}
+
+func (p *dnsProber) endFailure(at time.Time) {
+	if !p.failing {
+		return
+	}
+	p.recorder.EndInterval(p.failureID, at)
+	p.failing = false
+}
@@ -0,0 +1,183 @@
+package poll_dns
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/openshift/origin/pkg/clioptions/iooptions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PollDNSFlags struct {
+	ConfigFlags       *genericclioptions.ConfigFlags
+	OutputFlags       *iooptions.OutputFlags
+	Names             []string
+	MyNodeName        string
+	StopConfigMapName string
+	Interval          time.Duration
+	Timeout           time.Duration
+
+	genericclioptions.IOStreams
+}
+
+func NewPollDNSFlags(streams genericclioptions.IOStreams) *PollDNSFlags {
+	return &PollDNSFlags{
+		ConfigFlags: genericclioptions.NewConfigFlags(false),
+		OutputFlags: iooptions.NewOutputOptions(),
+		Interval:    time.Second,
+		Timeout:     2 * time.Second,
+		IOStreams:   streams,
+	}
+}
+
+func NewPollDNS(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	f := NewPollDNSFlags(ioStreams)
+	cmd := &cobra.Command{
+		Use:   "poll-dns",
+		Short: "Continuously resolve names from inside the cluster and record when resolution fails",
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancelFn := context.WithCancel(context.Background())
+			defer cancelFn()
+			abortCh := make(chan os.Signal, 2)
+			go func() {
+				<-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted, terminating\n")
+				cancelFn()
+
+				sig := <-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted twice, exiting (%s)\n", sig)
+				switch sig {
+				case syscall.SIGINT:
+					os.Exit(130)
+				default:
+					os.Exit(0)
+				}
+			}()
+			signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(ctx)
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *PollDNSFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.StringVar(&f.MyNodeName, "my-node-name", f.MyNodeName, "the name of the node running this pod")
+	flags.StringVar(&f.StopConfigMapName, "stop-configmap", f.StopConfigMapName, "the name of the configmap that indicates that this pod should stop all probes.")
+	flags.StringArrayVar(&f.Names, "name", f.Names, "a name to resolve as <target>=<hostname>, may be repeated")
+	flags.DurationVar(&f.Interval, "interval", f.Interval, "how often to resolve every name")
+	flags.DurationVar(&f.Timeout, "timeout", f.Timeout, "how long a single resolution may take before it counts as failed")
+	f.ConfigFlags.AddFlags(flags)
+	f.OutputFlags.BindFlags(flags)
+}
+
+func (f *PollDNSFlags) Validate() error {
+	if len(f.OutputFlags.OutFile) == 0 {
+		return fmt.Errorf("output-file must be specified")
+	}
+	if len(f.MyNodeName) == 0 {
+		return fmt.Errorf("my-node-name must be specified")
+	}
+	if len(f.Names) == 0 {
+		return fmt.Errorf("at least one name must be specified")
+	}
+	if f.Interval <= 0 || f.Timeout <= 0 {
+		return fmt.Errorf("interval and timeout must be positive")
+	}
+	if _, err := parseNames(f.Names); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *PollDNSFlags) SetIOStreams(streams genericclioptions.IOStreams) {
+	f.IOStreams = streams
+}
+
+func (f *PollDNSFlags) ToOptions() (*PollDNSOptions, error) {
+	originalOutStream := f.IOStreams.Out
+	closeFn, err := f.OutputFlags.ConfigureIOStreams(f.IOStreams, f)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, _, err := f.ConfigFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 {
+		return nil, fmt.Errorf("namespace must be specified")
+	}
+
+	restConfig, err := f.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	names, err := parseNames(f.Names)
+	if err != nil {
+		return nil, err
+	}
+	return &PollDNSOptions{
+		KubeClient:        kubeClient,
+		Namespace:         namespace,
+		OutputFile:        f.OutputFlags.OutFile,
+		Names:             names,
+		Interval:          f.Interval,
+		Timeout:           f.Timeout,
+		StopConfigMapName: f.StopConfigMapName,
+		MyNodeName:        f.MyNodeName,
+		CloseFn:           closeFn,
+
+		OriginalOutFile: originalOutStream,
+		IOStreams:       f.IOStreams,
+	}, nil
+}
+
+// Name is a hostname resolved under a target name.
+type Name struct {
+	Target   string
+	Hostname string
+}
+
+func parseNames(values []string) ([]Name, error) {
+	ret := []Name{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		target, hostname, ok := strings.Cut(value, "=")
+		if !ok || len(target) == 0 || len(hostname) == 0 {
+			return nil, fmt.Errorf("name %q must be <target>=<hostname>", value)
+		}
+		if seen[target] {
+			return nil, fmt.Errorf("target %q is listed more than once", target)
+		}
+		seen[target] = true
+		ret = append(ret, Name{Target: target, Hostname: hostname})
+	}
+	return ret, nil
+}
@@ -0,0 +1,83 @@
+package poll_dns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/openshift/origin/pkg/clioptions/iooptions"
+	"github.com/openshift/origin/pkg/monitor"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PollDNSOptions struct {
+	KubeClient kubernetes.Interface
+	Namespace  string
+	Names      []Name
+
+	Interval time.Duration
+	Timeout  time.Duration
+
+	OutputFile        string
+	MyNodeName        string
+	StopConfigMapName string
+
+	OriginalOutFile io.Writer
+	CloseFn         iooptions.CloseFunc
+	genericclioptions.IOStreams
+}
+
+func (o *PollDNSOptions) Run(ctx context.Context) error {
+	for _, name := range o.Names {
+		fmt.Fprintf(o.OriginalOutFile, "Initializing to resolve %s as %s\n", name.Hostname, name.Target)
+	}
+
+	startingContent, err := os.ReadFile(o.OutputFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(startingContent) > 0 {
+		// print starting content to the log so that we can simply scrape the log to find all entries at the end
+		o.OriginalOutFile.Write(startingContent)
+	}
+
+	recorder := monitor.WrapWithJSONLRecorder(monitor.NewRecorder(), o.IOStreams.Out, nil)
+
+	kubeInformers := informers.NewSharedInformerFactory(o.KubeClient, 0)
+	namespacedScopedCoreInformers := coreinformers.New(kubeInformers, o.Namespace, nil)
+
+	cleanupFinished := make(chan struct{})
+	dnsPoller := NewPollDNSController(
+		o.Names,
+		// every lookup goes to the cluster DNS, the go resolver does not cache.
+		&net.Resolver{PreferGo: true},
+		o.Interval,
+		o.Timeout,
+		o.MyNodeName,
+		o.Namespace,
+		recorder,
+		o.OriginalOutFile,
+		o.StopConfigMapName,
+		namespacedScopedCoreInformers.ConfigMaps(),
+	)
+
+	go dnsPoller.Run(ctx, cleanupFinished)
+	go kubeInformers.Start(ctx.Done())
+
+	fmt.Fprintf(o.OriginalOutFile, "Watching configmaps...\n")
+
+	<-ctx.Done()
+
+	// now wait for the probes to shutdown
+	fmt.Fprintf(o.OriginalOutFile, "Waiting for probes to close...\n")
+	<-cleanupFinished
+	fmt.Fprintf(o.OriginalOutFile, "Exiting...\n")
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package poll_endpoints
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+type PollEndpointsController struct {
+	endpoints         []Endpoint
+	nodeName          string
+	namespaceName     string
+	stopConfigMapName string
+	recorder          monitorapi.RecorderWriter
+	outFile           io.Writer
+
+	configmapLister corelisters.ConfigMapLister
+
+	informersToSync []cache.InformerSynced
+
+	samplersLock sync.Mutex
+	samplers     []*backenddisruption.BackendSampler
+
+	syncHandler func(ctx context.Context, key string) error
+	queue       workqueue.RateLimitingInterface
+}
+
+func NewPollEndpointsController(
+	endpoints []Endpoint,
+	nodeName string,
+	namespaceName string,
+	recorder monitorapi.RecorderWriter,
+	outFile io.Writer,
+	stopConfigMapName string,
+	configmapInformer coreinformers.ConfigMapInformer,
+) *PollEndpointsController {
+
+	c := &PollEndpointsController{
+		endpoints:         endpoints,
+		nodeName:          nodeName,
+		namespaceName:     namespaceName,
+		recorder:          recorder,
+		stopConfigMapName: stopConfigMapName,
+		outFile:           outFile,
+
+		configmapLister: configmapInformer.Lister(),
+		informersToSync: []cache.InformerSynced{
+			configmapInformer.Informer().HasSynced,
+		},
+
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "EndpointPoller"),
+	}
+
+	c.syncHandler = c.syncEndpointPollers
+
+	configmapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.queue.Add("check")
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.queue.Add("check")
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.queue.Add("check")
+		},
+	})
+
+	return c
+}
+
+// newSampler polls one endpoint with one connection type.  The backend is per endpoint and connection type so that
+// every node reports against the same backend, and the interval locator names the node the poller runs on.
+func (c *PollEndpointsController) newSampler(endpoint Endpoint, connectionType monitorapi.BackendConnectionType) *backenddisruption.BackendSampler {
+	backendDisruptionName := fmt.Sprintf("%s-%v-connections", endpoint.BackendPrefix, connectionType)
+	intervalLocator := fmt.Sprintf("%s-from-node-%v", endpoint.BackendPrefix, c.nodeName)
+	host := fmt.Sprintf("%s://%s", endpoint.URL.Scheme, endpoint.URL.Host)
+	return backenddisruption.NewSimpleBackendWithLocator(
+		monitorapi.NewLocator().LocateDisruptionCheck(backendDisruptionName, intervalLocator, connectionType),
+		host,
+		endpoint.URL.RequestURI(),
+		connectionType,
+	).WithUserAgent(fmt.Sprintf("openshift-internal-endpoint-poller-%s-%s", connectionType, endpoint.BackendPrefix))
+}
+
+func (c *PollEndpointsController) syncEndpointPollers(ctx context.Context, key string) error {
+	_, err := c.configmapLister.ConfigMaps(c.namespaceName).Get(c.stopConfigMapName)
+	switch {
+	case err == nil:
+		c.removeAllSamplers()
+		return nil
+	case apierrors.IsNotFound(err):
+		// did not find the stopConfigMap
+	case err != nil:
+		return err
+	}
+
+	c.samplersLock.Lock()
+	defer c.samplersLock.Unlock()
+
+	if len(c.samplers) > 0 {
+		return nil
+	}
+	for _, endpoint := range c.endpoints {
+		fmt.Fprintf(c.outFile, "Adding and starting: %v on node/%v\n", endpoint.URL, c.nodeName)
+		for _, connectionType := range []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType} {
+			sampler := c.newSampler(endpoint, connectionType)
+			if err := sampler.StartEndpointMonitoring(ctx, c.recorder, nil); err != nil {
+				return err
+			}
+			c.samplers = append(c.samplers, sampler)
+		}
+		fmt.Fprintf(c.outFile, "Successfully started: %v on node/%v\n", endpoint.URL, c.nodeName)
+	}
+	return nil
+}
+
+func (c *PollEndpointsController) removeAllSamplers() {
+	c.samplersLock.Lock()
+	defer c.samplersLock.Unlock()
+
+	if len(c.samplers) == 0 {
+		fmt.Fprintf(c.outFile, "No pollers running, skipping removal\n")
+		return
+	}
+
+	fmt.Fprintf(c.outFile, "Stopping and removing all pollers for node/%v\n", c.nodeName)
+	// every sampler has to drain, so stop in parallel
+	wg := sync.WaitGroup{}
+	for i := range c.samplers {
+		wg.Add(1)
+		go func(sampler *backenddisruption.BackendSampler) {
+			defer wg.Done()
+			sampler.Stop()
+		}(c.samplers[i])
+	}
+	wg.Wait()
+	c.samplers = nil
+	fmt.Fprintf(c.outFile, "Stopped all pollers\n")
+}
+
+func (c *PollEndpointsController) Run(ctx context.Context, finishedCleanup chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+	defer close(finishedCleanup)
+
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting PollEndpoints controller")
+	defer logger.Info("Shutting down PollEndpoints controller")
+
+	if !cache.WaitForNamedCacheSync("EndpointPoller", ctx.Done(), c.informersToSync...) {
+		return
+	}
+	go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+
+	<-ctx.Done()
+	c.removeAllSamplers()
+}
+
+func (c *PollEndpointsController) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *PollEndpointsController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.syncHandler(ctx, key.(string))
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("%v failed with : %v", key, err))
+	c.queue.AddRateLimited(key)
+
+	return true
+}
@@ -0,0 +1,179 @@
+package poll_endpoints
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/openshift/origin/pkg/clioptions/iooptions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PollEndpointsFlags struct {
+	ConfigFlags       *genericclioptions.ConfigFlags
+	OutputFlags       *iooptions.OutputFlags
+	Endpoints         []string
+	MyNodeName        string
+	StopConfigMapName string
+
+	genericclioptions.IOStreams
+}
+
+func NewPollEndpointsFlags(streams genericclioptions.IOStreams) *PollEndpointsFlags {
+	return &PollEndpointsFlags{
+		ConfigFlags: genericclioptions.NewConfigFlags(false),
+		OutputFlags: iooptions.NewOutputOptions(),
+		IOStreams:   streams,
+	}
+}
+
+func NewPollEndpoints(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	f := NewPollEndpointsFlags(ioStreams)
+	cmd := &cobra.Command{
+		Use:   "poll-endpoints",
+		Short: "Continuously poll URLs from inside the cluster with new and reused connections",
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancelFn := context.WithCancel(context.Background())
+			defer cancelFn()
+			abortCh := make(chan os.Signal, 2)
+			go func() {
+				<-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted, terminating\n")
+				cancelFn()
+
+				sig := <-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted twice, exiting (%s)\n", sig)
+				switch sig {
+				case syscall.SIGINT:
+					os.Exit(130)
+				default:
+					os.Exit(0)
+				}
+			}()
+			signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			o, err := f.ToOptions()
+			if err != nil {
+				return err
+			}
+			return o.Run(ctx)
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *PollEndpointsFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.StringVar(&f.MyNodeName, "my-node-name", f.MyNodeName, "the name of the node running this pod")
+	flags.StringVar(&f.StopConfigMapName, "stop-configmap", f.StopConfigMapName, "the name of the configmap that indicates that this pod should stop all pollers.")
+	flags.StringArrayVar(&f.Endpoints, "endpoint", f.Endpoints, "an endpoint to poll as <disruption-backend-prefix>=<url>, may be repeated")
+	f.ConfigFlags.AddFlags(flags)
+	f.OutputFlags.BindFlags(flags)
+}
+
+func (f *PollEndpointsFlags) Validate() error {
+	if len(f.OutputFlags.OutFile) == 0 {
+		return fmt.Errorf("output-file must be specified")
+	}
+	if len(f.MyNodeName) == 0 {
+		return fmt.Errorf("my-node-name must be specified")
+	}
+	if len(f.Endpoints) == 0 {
+		return fmt.Errorf("at least one endpoint must be specified")
+	}
+	if _, err := parseEndpoints(f.Endpoints); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *PollEndpointsFlags) SetIOStreams(streams genericclioptions.IOStreams) {
+	f.IOStreams = streams
+}
+
+func (f *PollEndpointsFlags) ToOptions() (*PollEndpointsOptions, error) {
+	originalOutStream := f.IOStreams.Out
+	closeFn, err := f.OutputFlags.ConfigureIOStreams(f.IOStreams, f)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, _, err := f.ConfigFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 {
+		return nil, fmt.Errorf("namespace must be specified")
+	}
+
+	restConfig, err := f.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := parseEndpoints(f.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+	return &PollEndpointsOptions{
+		KubeClient:        kubeClient,
+		Namespace:         namespace,
+		OutputFile:        f.OutputFlags.OutFile,
+		Endpoints:         endpoints,
+		StopConfigMapName: f.StopConfigMapName,
+		MyNodeName:        f.MyNodeName,
+		CloseFn:           closeFn,
+
+		OriginalOutFile: originalOutStream,
+		IOStreams:       f.IOStreams,
+	}, nil
+}
+
+// Endpoint is a URL polled under a disruption backend prefix.
+type Endpoint struct {
+	BackendPrefix string
+	URL           *url.URL
+}
+
+func parseEndpoints(values []string) ([]Endpoint, error) {
+	ret := []Endpoint{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		backendPrefix, rawURL, ok := strings.Cut(value, "=")
+		if !ok || len(backendPrefix) == 0 || len(rawURL) == 0 {
+			return nil, fmt.Errorf("endpoint %q must be <disruption-backend-prefix>=<url>", value)
+		}
+		if seen[backendPrefix] {
+			return nil, fmt.Errorf("disruption backend prefix %q is listed more than once", backendPrefix)
+		}
+		seen[backendPrefix] = true
+		endpointURL, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %q: %w", value, err)
+		}
+		if (endpointURL.Scheme != "http" && endpointURL.Scheme != "https") || len(endpointURL.Host) == 0 {
+			return nil, fmt.Errorf("endpoint %q must be an absolute http or https URL", value)
+		}
+		ret = append(ret, Endpoint{BackendPrefix: backendPrefix, URL: endpointURL})
+	}
+	return ret, nil
+}
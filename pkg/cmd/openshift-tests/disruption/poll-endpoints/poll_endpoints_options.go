@@ -0,0 +1,74 @@
+package poll_endpoints
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift/origin/pkg/clioptions/iooptions"
+	"github.com/openshift/origin/pkg/monitor"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type PollEndpointsOptions struct {
+	KubeClient kubernetes.Interface
+	Namespace  string
+	Endpoints  []Endpoint
+
+	OutputFile        string
+	MyNodeName        string
+	StopConfigMapName string
+
+	OriginalOutFile io.Writer
+	CloseFn         iooptions.CloseFunc
+	genericclioptions.IOStreams
+}
+
+func (o *PollEndpointsOptions) Run(ctx context.Context) error {
+	for _, endpoint := range o.Endpoints {
+		fmt.Fprintf(o.OriginalOutFile, "Initializing to poll %s as %s\n", endpoint.URL, endpoint.BackendPrefix)
+	}
+
+	startingContent, err := os.ReadFile(o.OutputFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(startingContent) > 0 {
+		// print starting content to the log so that we can simply scrape the log to find all entries at the end
+		o.OriginalOutFile.Write(startingContent)
+	}
+
+	recorder := monitor.WrapWithJSONLRecorder(monitor.NewRecorder(), o.IOStreams.Out, nil)
+
+	kubeInformers := informers.NewSharedInformerFactory(o.KubeClient, 0)
+	namespacedScopedCoreInformers := coreinformers.New(kubeInformers, o.Namespace, nil)
+
+	cleanupFinished := make(chan struct{})
+	endpointPoller := NewPollEndpointsController(
+		o.Endpoints,
+		o.MyNodeName,
+		o.Namespace,
+		recorder,
+		o.OriginalOutFile,
+		o.StopConfigMapName,
+		namespacedScopedCoreInformers.ConfigMaps(),
+	)
+
+	go endpointPoller.Run(ctx, cleanupFinished)
+	go kubeInformers.Start(ctx.Done())
+
+	fmt.Fprintf(o.OriginalOutFile, "Watching configmaps...\n")
+
+	<-ctx.Done()
+
+	// now wait for the pollers to shutdown
+	fmt.Fprintf(o.OriginalOutFile, "Waiting for pollers to close...\n")
+	<-cleanupFinished
+	fmt.Fprintf(o.OriginalOutFile, "Exiting...\n")
+
+	return nil
+}
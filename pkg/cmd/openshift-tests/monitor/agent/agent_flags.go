@@ -0,0 +1,123 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/origin/pkg/monitor/nodeagent"
+)
+
+type AgentFlags struct {
+	Port                 int
+	MyNodeName           string
+	DiskDir              string
+	ProcDir              string
+	DNSName              string
+	ProbeInterval        time.Duration
+	DiskLatencyThreshold time.Duration
+	ConntrackThreshold   float64
+
+	genericclioptions.IOStreams
+}
+
+func NewAgentFlags(streams genericclioptions.IOStreams) *AgentFlags {
+	return &AgentFlags{
+		Port:                 9797,
+		DiskDir:              "/var/lib/node-agent",
+		ProcDir:              "/proc",
+		DNSName:              "kubernetes.default.svc.cluster.local",
+		ProbeInterval:        5 * time.Second,
+		DiskLatencyThreshold: 500 * time.Millisecond,
+		ConntrackThreshold:   0.9,
+		IOStreams:            streams,
+	}
+}
+
+func NewAgentCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	f := NewAgentFlags(ioStreams)
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Run node-local probes and serve the intervals they observe to the monitor",
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancelFn := context.WithCancel(context.Background())
+			defer cancelFn()
+			abortCh := make(chan os.Signal, 2)
+			go func() {
+				<-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted, terminating\n")
+				cancelFn()
+
+				sig := <-abortCh
+				fmt.Fprintf(f.ErrOut, "Interrupted twice, exiting (%s)\n", sig)
+				switch sig {
+				case syscall.SIGINT:
+					os.Exit(130)
+				default:
+					os.Exit(0)
+				}
+			}()
+			signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+
+			if err := f.Validate(); err != nil {
+				return err
+			}
+			return f.ToOptions().Run(ctx)
+		},
+	}
+
+	f.BindOptions(cmd.Flags())
+
+	return cmd
+}
+
+func (f *AgentFlags) BindOptions(flags *pflag.FlagSet) {
+	flags.IntVar(&f.Port, "port", f.Port, "the port to serve intervals on")
+	flags.StringVar(&f.MyNodeName, "my-node-name", f.MyNodeName, "the name of the node running this pod")
+	flags.StringVar(&f.DiskDir, "disk-dir", f.DiskDir, "a directory on the disk of the node to time synchronous writes in")
+	flags.StringVar(&f.ProcDir, "proc-dir", f.ProcDir, "the proc filesystem of the network namespace of the node")
+	flags.StringVar(&f.DNSName, "dns-name", f.DNSName, "the name to resolve from the node")
+	flags.DurationVar(&f.ProbeInterval, "probe-interval", f.ProbeInterval, "how often to run every probe")
+	flags.DurationVar(&f.DiskLatencyThreshold, "disk-latency-threshold", f.DiskLatencyThreshold, "how long a synchronous write may take before the disk counts as slow")
+	flags.Float64Var(&f.ConntrackThreshold, "conntrack-threshold", f.ConntrackThreshold, "the fraction of the conntrack table that may be used before it counts as saturated")
+}
+
+func (f *AgentFlags) Validate() error {
+	if len(f.MyNodeName) == 0 {
+		return fmt.Errorf("my-node-name must be specified")
+	}
+	if f.Port <= 0 || f.Port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+	if f.ProbeInterval <= 0 || f.DiskLatencyThreshold <= 0 {
+		return fmt.Errorf("probe-interval and disk-latency-threshold must be positive")
+	}
+	if f.ConntrackThreshold <= 0 || f.ConntrackThreshold > 1 {
+		return fmt.Errorf("conntrack-threshold must be greater than 0 and at most 1")
+	}
+	return nil
+}
+
+func (f *AgentFlags) ToOptions() *AgentOptions {
+	return &AgentOptions{
+		Port:       f.Port,
+		MyNodeName: f.MyNodeName,
+		Probes: []nodeagent.Probe{
+			nodeagent.NewDiskLatencyProbe(f.DiskDir, f.DiskLatencyThreshold),
+			nodeagent.NewConntrackProbe(f.ProcDir, f.ConntrackThreshold),
+			nodeagent.NewDNSProbe(f.DNSName),
+		},
+		ProbeInterval: f.ProbeInterval,
+		IOStreams:     f.IOStreams,
+	}
+}
@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/origin/pkg/monitor/nodeagent"
+)
+
+type AgentOptions struct {
+	Port          int
+	MyNodeName    string
+	Probes        []nodeagent.Probe
+	ProbeInterval time.Duration
+
+	genericclioptions.IOStreams
+}
+
+func (o *AgentOptions) Run(parentCtx context.Context) error {
+	ctx, cancel := context.WithCancel(parentCtx)
+	defer cancel()
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", o.Port))
+	if err != nil {
+		return err
+	}
+	agent := nodeagent.NewAgent(o.MyNodeName, o.Probes, o.ProbeInterval)
+	server := grpc.NewServer(nodeagent.ServerOptions()...)
+	nodeagent.RegisterAgentServer(server, agent)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		agent.Run(ctx)
+	}()
+	go func() {
+		<-ctx.Done()
+		fmt.Fprintf(o.Out, "Stopping the server...\n")
+		// streams only end when their clients go away, don't wait for them.
+		server.Stop()
+	}()
+
+	fmt.Fprintf(o.Out, "Serving intervals of node/%s on %s\n", o.MyNodeName, listener.Addr())
+	err = server.Serve(listener)
+	cancel()
+	wg.Wait()
+	fmt.Fprintf(o.Out, "Exiting...\n")
+	if parentCtx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package analyze
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/replay"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const junitSuiteName = "offline-analysis"
+
+type AnalyzeOptions struct {
+	ArtifactDir         string
+	OutputDir           string
+	ExactMonitorTests   []string
+	DisableMonitorTests []string
+	WarningsToFail      int
+
+	genericclioptions.IOStreams
+}
+
+func NewAnalyzeOptions(streams genericclioptions.IOStreams) *AnalyzeOptions {
+	return &AnalyzeOptions{
+		IOStreams: streams,
+	}
+}
+
+func NewAnalyzeCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewAnalyzeOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Evaluate the monitor tests against the artifacts of an earlier run",
+		Long: templates.LongDesc(`
+		Evaluate the monitor tests of this binary against the artifacts of an earlier run, without a cluster
+
+		The intervals and resources the run recorded are read from every e2e-events and resource file in the artifact
+		directory.  The intervals the run constructed are left out and constructed again, then the tests are evaluated,
+		so changes to interval construction and evaluation can be tried on historical runs.  Only monitor tests that
+		need nothing but the recorded intervals and resources are run, the monitor tests that need what they collected
+		from the cluster during the run are reported as not supported.
+
+		  openshift-tests monitor analyze --artifact-dir=./artifacts/e2e-aws/openshift-e2e-test/artifacts/junit
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run(context.Background())
+		},
+	}
+
+	o.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *AnalyzeOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.ArtifactDir, "artifact-dir", o.ArtifactDir, "The directory the earlier run wrote its monitor artifacts to.")
+	flags.StringVar(&o.OutputDir, "output-dir", o.OutputDir, "The directory the intervals and junits of the analysis are written to.  Defaults to a new directory.")
+	flags.StringSliceVar(&o.ExactMonitorTests, "monitor", o.ExactMonitorTests, "list of exactly which monitors to enable. All others will be disabled.")
+	flags.StringSliceVar(&o.DisableMonitorTests, "disable-monitor", o.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.IntVar(&o.WarningsToFail, "warnings-to-fail", o.WarningsToFail, "The number of warnings from a single monitor test that fail the run.  Zero means warnings never fail the run.")
+}
+
+func (o *AnalyzeOptions) Complete() error {
+	if len(o.OutputDir) == 0 {
+		o.OutputDir = fmt.Sprintf("monitor-analysis-%s", time.Now().UTC().Format("20060102-150405"))
+	}
+	return nil
+}
+
+func (o *AnalyzeOptions) Validate() error {
+	if len(o.ArtifactDir) == 0 {
+		return fmt.Errorf("--artifact-dir must be specified")
+	}
+	// the artifacts of the analysis would be read by the next analysis of the same directory.
+	if relative, err := filepath.Rel(o.ArtifactDir, o.OutputDir); err == nil && !strings.HasPrefix(relative, "..") {
+		return fmt.Errorf("--output-dir must not be inside --artifact-dir")
+	}
+	return nil
+}
+
+func (o *AnalyzeOptions) Run(ctx context.Context) error {
+	replayOptions, err := replay.LoadArtifacts(o.ArtifactDir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Read %d intervals and %d resources from %s.\n", len(replayOptions.Intervals), len(replayOptions.ResourceUpdates), o.ArtifactDir)
+
+	registry, err := defaultmonitortests.NewMonitorTestsFor(monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: monitortestframework.Stable,
+		ExactMonitorTests:          o.ExactMonitorTests,
+		DisableMonitorTests:        o.DisableMonitorTests,
+		FailureBudgetPolicy: &monitortestframework.FailureBudgetPolicy{
+			WarningsToFail: o.WarningsToFail,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	result, err := replay.Replay(ctx, registry, replayOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return err
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(o.OutputDir, "e2e-events_offline-analysis.json"), result.FinalIntervals); err != nil {
+		return fmt.Errorf("unable to write intervals: %w", err)
+	}
+	if err := writeJunit(filepath.Join(o.OutputDir, "e2e-monitor-tests_offline-analysis.xml"), result.Junits); err != nil {
+		return fmt.Errorf("unable to write junits: %w", err)
+	}
+
+	summary, err := monitor.BuildResultsSummary(junitSuiteName, result.Junits, result.FinalIntervals)
+	if err != nil {
+		return err
+	}
+	monitor.WriteConsoleSummary(o.Out, summary)
+	fmt.Fprintf(o.Out, "\nThe intervals and junits of the analysis were written to %s.\n", o.OutputDir)
+	if summary.NumFailed > 0 {
+		return fmt.Errorf("%d monitor tests failed", summary.NumFailed)
+	}
+	return nil
+}
+
+func writeJunit(filename string, junits []*junitapi.JUnitTestCase) error {
+	suite := junitapi.JUnitTestSuite{Name: junitSuiteName}
+	for _, junit := range junits {
+		suite.NumTests++
+		switch {
+		case junit.FailureOutput != nil:
+			suite.NumFailed++
+		case junit.SkipMessage != nil:
+			suite.NumSkipped++
+		}
+		suite.TestCases = append(suite.TestCases, junit)
+	}
+	out, err := xml.MarshalIndent(suite, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, test.StripANSI(out), 0644)
+}
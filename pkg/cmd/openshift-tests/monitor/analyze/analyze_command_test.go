@@ -0,0 +1,100 @@
+package analyze
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func TestAnalyze(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	artifactDir := t.TempDir()
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().HumanMessage("recorded")).
+			Build(start, start.Add(time.Hour)),
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(artifactDir, "e2e-events_20240101-000000.json"), intervals); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	o := NewAnalyzeOptions(genericclioptions.IOStreams{Out: out})
+	o.ArtifactDir = artifactDir
+	o.OutputDir = t.TempDir()
+	o.ExactMonitorTests = []string{"cloud-throttling"}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out.String(), "Read 1 intervals and 0 resources") {
+		t.Errorf("expected the artifacts to be read, got\n%s", out.String())
+	}
+	analyzed, err := monitorserialization.EventsFromFile(filepath.Join(o.OutputDir, "e2e-events_offline-analysis.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analyzed) != 1 {
+		t.Errorf("expected the recorded interval, got %v", analyzed)
+	}
+	junit, err := os.ReadFile(filepath.Join(o.OutputDir, "e2e-monitor-tests_offline-analysis.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(junit), "monitor test cloud-throttling test evaluation") {
+		t.Errorf("expected the tests to be evaluated, got\n%s", junit)
+	}
+}
+
+func TestAnalyzeDefaultRegistry(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	artifactDir := t.TempDir()
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().HumanMessage("recorded")).
+			Build(start, start.Add(time.Hour)),
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(artifactDir, "e2e-events_20240101-000000.json"), intervals); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	o := NewAnalyzeOptions(genericclioptions.IOStreams{Out: out})
+	o.ArtifactDir = artifactDir
+	o.OutputDir = t.TempDir()
+	if err := o.Run(context.Background()); err != nil {
+		t.Fatalf("expected every monitor test of the default registry to pass or be skipped, got %v\n%s", err, out.String())
+	}
+
+	junit, err := os.ReadFile(filepath.Join(o.OutputDir, "e2e-monitor-tests_offline-analysis.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(junit), "panic") {
+		t.Errorf("expected no monitor test to panic, got\n%s", junit)
+	}
+	if !strings.Contains(string(junit), "cannot be run on the artifacts of an earlier run") {
+		t.Errorf("expected the monitor tests that need collection to be reported as not supported, got\n%s", junit)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	o := &AnalyzeOptions{ArtifactDir: "artifacts", OutputDir: "artifacts/analysis"}
+	if err := o.Validate(); err == nil {
+		t.Errorf("expected an output directory inside the artifact directory to be rejected")
+	}
+}
@@ -0,0 +1,124 @@
+package diffintervals
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/monitor/intervaldiff"
+)
+
+const (
+	textOutput = "text"
+	jsonOutput = "json"
+)
+
+type DiffIntervalsOptions struct {
+	Baseline        string
+	Candidate       string
+	Output          string
+	Tolerance       time.Duration
+	MinRegression   time.Duration
+	RegressionRatio float64
+
+	genericclioptions.IOStreams
+}
+
+func NewDiffIntervalsOptions(streams genericclioptions.IOStreams) *DiffIntervalsOptions {
+	return &DiffIntervalsOptions{
+		Output:          textOutput,
+		Tolerance:       intervaldiff.DefaultTolerance,
+		MinRegression:   intervaldiff.DefaultMinRegression,
+		RegressionRatio: intervaldiff.DefaultRegressionRatio,
+		IOStreams:       streams,
+	}
+}
+
+func NewDiffIntervalsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewDiffIntervalsOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "diff-intervals",
+		Short: "Compare the intervals of two runs",
+		Long: templates.LongDesc(`
+		Compare the intervals of a candidate run to those of a baseline run, to triage a regression
+
+		The runs are aligned at their first interval.  Disruption windows and alert firings of the candidate are new
+		when the baseline had none of the same backend or alert within the tolerance of the same time in its run.
+		Warning and error intervals both runs had are duration regressions when they last much longer in the candidate.
+
+		Either run may be an e2e-events file, or a directory whose e2e-events files are read together.
+
+		  openshift-tests monitor diff-intervals --baseline=./passing/artifacts --candidate=./failing/artifacts
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	o.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *DiffIntervalsOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Baseline, "baseline", o.Baseline, "The e2e-events file or artifact directory of the run to compare to.")
+	flags.StringVar(&o.Candidate, "candidate", o.Candidate, "The e2e-events file or artifact directory of the run to triage.")
+	flags.StringVarP(&o.Output, "output", "o", o.Output, fmt.Sprintf("The format to write the differences in, one of %s or %s.", textOutput, jsonOutput))
+	flags.DurationVar(&o.Tolerance, "tolerance", o.Tolerance, "How far apart in their runs windows may be and still be the same window.")
+	flags.DurationVar(&o.MinRegression, "min-regression", o.MinRegression, "The smallest increase in duration reported as a regression.")
+	flags.Float64Var(&o.RegressionRatio, "regression-ratio", o.RegressionRatio, "How many times longer than in the baseline intervals must last to be reported as a regression.")
+}
+
+func (o *DiffIntervalsOptions) Validate() error {
+	if len(o.Baseline) == 0 || len(o.Candidate) == 0 {
+		return fmt.Errorf("--baseline and --candidate must be specified")
+	}
+	switch o.Output {
+	case textOutput, jsonOutput:
+	default:
+		return fmt.Errorf("--output must be one of %s or %s, got %q", textOutput, jsonOutput, o.Output)
+	}
+	if o.Tolerance < 0 || o.MinRegression < 0 {
+		return fmt.Errorf("--tolerance and --min-regression must not be negative")
+	}
+	if o.RegressionRatio < 1 {
+		return fmt.Errorf("--regression-ratio must be at least 1, got %v", o.RegressionRatio)
+	}
+	return nil
+}
+
+func (o *DiffIntervalsOptions) Run() error {
+	baseline, err := intervaldiff.LoadIntervals(o.Baseline)
+	if err != nil {
+		return fmt.Errorf("unable to read the baseline: %w", err)
+	}
+	candidate, err := intervaldiff.LoadIntervals(o.Candidate)
+	if err != nil {
+		return fmt.Errorf("unable to read the candidate: %w", err)
+	}
+
+	diff := intervaldiff.Compare(baseline, candidate, intervaldiff.Options{
+		Tolerance:       o.Tolerance,
+		MinRegression:   o.MinRegression,
+		RegressionRatio: o.RegressionRatio,
+	})
+	if o.Output == jsonOutput {
+		encoder := json.NewEncoder(o.Out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(diff)
+	}
+	diff.WriteText(o.Out)
+	return nil
+}
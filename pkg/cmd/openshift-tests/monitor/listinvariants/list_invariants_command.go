@@ -0,0 +1,147 @@
+package listinvariants
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+const (
+	tableOutput = "table"
+	jsonOutput  = "json"
+)
+
+type ListInvariantsOptions struct {
+	Output                     string
+	ClusterStabilityDuringTest string
+	ExactMonitorTests          []string
+	DisableMonitorTests        []string
+
+	genericclioptions.IOStreams
+}
+
+func NewListInvariantsOptions(streams genericclioptions.IOStreams) *ListInvariantsOptions {
+	return &ListInvariantsOptions{
+		Output:                     tableOutput,
+		ClusterStabilityDuringTest: string(monitortestframework.Stable),
+		IOStreams:                  streams,
+	}
+}
+
+func NewListInvariantsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewListInvariantsOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "list-invariants",
+		Short: "List the monitor tests that will run",
+		Long: templates.LongDesc(`
+		List the monitor tests that will run, with their jira component, the platforms they apply to, the stages they
+		run in, and the tests they produce.
+
+		Platforms and produced tests are only known for monitor tests that describe themselves, the others apply to
+		every platform.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	o.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *ListInvariantsOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.Output, "output", "o", o.Output, fmt.Sprintf("The format to list the monitor tests in, one of %s or %s.", tableOutput, jsonOutput))
+	flags.StringVar(&o.ClusterStabilityDuringTest, "cluster-stability", o.ClusterStabilityDuringTest,
+		fmt.Sprintf("The stability of the cluster the monitor tests run against, one of %s or %s.", monitortestframework.Stable, monitortestframework.Disruptive))
+	flags.StringSliceVar(&o.ExactMonitorTests, "monitor", o.ExactMonitorTests, "list of exactly which monitors to enable. All others will be disabled.")
+	flags.StringSliceVar(&o.DisableMonitorTests, "disable-monitor", o.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+}
+
+func (o *ListInvariantsOptions) Validate() error {
+	switch o.Output {
+	case tableOutput, jsonOutput:
+	default:
+		return fmt.Errorf("--output must be one of %s or %s, got %q", tableOutput, jsonOutput, o.Output)
+	}
+	switch monitortestframework.ClusterStabilityDuringTest(o.ClusterStabilityDuringTest) {
+	case monitortestframework.Stable, monitortestframework.Disruptive:
+	default:
+		return fmt.Errorf("--cluster-stability must be one of %s or %s, got %q", monitortestframework.Stable, monitortestframework.Disruptive, o.ClusterStabilityDuringTest)
+	}
+	return nil
+}
+
+func (o *ListInvariantsOptions) Run() error {
+	registry, err := defaultmonitortests.NewMonitorTestsFor(monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: monitortestframework.ClusterStabilityDuringTest(o.ClusterStabilityDuringTest),
+		ExactMonitorTests:          o.ExactMonitorTests,
+		DisableMonitorTests:        o.DisableMonitorTests,
+	})
+	if err != nil {
+		return err
+	}
+	monitorTests := registry.DescribeMonitorTests()
+
+	if o.Output == jsonOutput {
+		encoder := json.NewEncoder(o.Out)
+		encoder.SetIndent("", "    ")
+		return encoder.Encode(monitorTests)
+	}
+	return writeTable(o.Out, monitorTests)
+}
+
+// writeTable writes a row for every monitor test, and a row for each further test it produces.
+func writeTable(out io.Writer, monitorTests []monitortestframework.MonitorTestMetadata) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tJIRA COMPONENT\tPLATFORMS\tSTAGES\tTESTS")
+	for _, monitorTest := range monitorTests {
+		tests := []string{"-"}
+		switch {
+		case len(monitorTest.TestNames) > 0:
+			tests = monitorTest.TestNames
+		case !monitorTest.Described:
+			tests = []string{"<unknown>"}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", monitorTest.Name, monitorTest.JiraComponent, platforms(monitorTest), stages(monitorTest), tests[0])
+		for _, test := range tests[1:] {
+			fmt.Fprintf(w, "\t\t\t\t%s\n", test)
+		}
+	}
+	return w.Flush()
+}
+
+func platforms(monitorTest monitortestframework.MonitorTestMetadata) string {
+	switch {
+	case len(monitorTest.Platforms) > 0:
+		return strings.Join(monitorTest.Platforms, ",")
+	case len(monitorTest.UnsupportedPlatforms) > 0:
+		return "all but " + strings.Join(monitorTest.UnsupportedPlatforms, ",")
+	}
+	return "all"
+}
+
+func stages(monitorTest monitortestframework.MonitorTestMetadata) string {
+	names := []string{}
+	for _, stage := range monitorTest.Stages {
+		names = append(names, string(stage))
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,51 @@
+package listinvariants
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+func TestListInvariants(t *testing.T) {
+	out := &bytes.Buffer{}
+	o := NewListInvariantsOptions(genericclioptions.IOStreams{Out: out})
+	o.ExactMonitorTests = []string{"cloud-throttling", "etcd-health"}
+
+	o.Output = jsonOutput
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	monitorTests := []monitortestframework.MonitorTestMetadata{}
+	if err := json.Unmarshal(out.Bytes(), &monitorTests); err != nil {
+		t.Fatal(err)
+	}
+	if len(monitorTests) != 2 || monitorTests[0].Name != "cloud-throttling" || monitorTests[1].Name != "etcd-health" {
+		t.Fatalf("expected the selected monitor tests, got %#v", monitorTests)
+	}
+	if strings.Join(monitorTests[0].Platforms, ",") != "AWS,Azure,GCP" {
+		t.Errorf("expected cloud-throttling to apply to the clouds it recognizes, got %v", monitorTests[0].Platforms)
+	}
+
+	out.Reset()
+	o.Output = tableOutput
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header, a row for cloud-throttling, and two for etcd-health, got\n%s", out.String())
+	}
+	for _, expected := range []string{"cloud-throttling", "AWS,Azure,GCP", "StartCollection,ConstructComputedIntervals,EvaluateTestsFromConstructedIntervals"} {
+		if !strings.Contains(lines[1], expected) {
+			t.Errorf("expected %q in %q", expected, lines[1])
+		}
+	}
+	if !strings.Contains(lines[2], "all but HyperShift") || !strings.HasSuffix(lines[3], "[sig-etcd] etcd should not lose quorum") {
+		t.Errorf("expected etcd-health to list its tests, got\n%s", out.String())
+	}
+}
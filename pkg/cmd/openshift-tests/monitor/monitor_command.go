@@ -1,7 +1,12 @@
 package monitor
 
 import (
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/agent"
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/analyze"
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/diffintervals"
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/listinvariants"
 	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/run"
+	"github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/runinvariants"
 	summarize_audit_logs "github.com/openshift/origin/pkg/cmd/openshift-tests/monitor/summarize-audit-logs"
 	"github.com/openshift/origin/pkg/monitor/apiserveravailability"
 	"github.com/spf13/cobra"
@@ -15,8 +20,13 @@ func NewMonitorCommand(streams genericclioptions.IOStreams) *cobra.Command {
 	}
 	cmd.AddCommand(
 		run.NewRunCommand(streams),
+		runinvariants.NewRunInvariantsCommand(streams),
+		listinvariants.NewListInvariantsCommand(streams),
+		analyze.NewAnalyzeCommand(streams),
+		diffintervals.NewDiffIntervalsCommand(streams),
 		summarize_audit_logs.AuditLogSummaryCommand(),
 		apiserveravailability.LogSummaryCommand(),
+		agent.NewAgentCommand(streams),
 	)
 	return cmd
 }
@@ -3,8 +3,10 @@ package run
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -22,26 +24,86 @@ import (
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/artifactstorage"
+	"github.com/openshift/origin/pkg/monitor/contribution"
+	"github.com/openshift/origin/pkg/monitor/jira"
+	"github.com/openshift/origin/pkg/monitor/notifier"
+	"github.com/openshift/origin/pkg/monitortests/controlplane/staticpodrevisions"
+	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/certificateanalyzer"
+	"github.com/openshift/origin/pkg/preflight"
+	"github.com/openshift/origin/pkg/version"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/util/templates"
 )
 
 type RunMonitorFlags struct {
-	ArtifactDir         string
-	DisplayFromNow      bool
-	ExactMonitorTests   []string
-	DisableMonitorTests []string
-	FromRepository      string
+	ArtifactDir            string
+	DisplayFromNow         bool
+	ExactMonitorTests      []string
+	DisableMonitorTests    []string
+	FromRepository         string
+	WarningsToFail         int
+	DuplicateTestNames     string
+	QuarantineFile         string
+	StorageLayout          string
+	StoragePhase           string
+	CertificateExpiry      time.Duration
+	MaxRevisions           int
+	SecondaryClusters      map[string]string
+	Backfill               time.Duration
+	RecorderWAL            string
+	IntervalFile           string
+	IntervalEndpoint       string
+	LokiEndpoint           string
+	LokiLabels             map[string]string
+	LokiTenant             string
+	OpenSearchEndpoint     string
+	OpenSearchIndex        string
+	OpenSearchFields       map[string]string
+	OpenSearchUsername     string
+	OpenSearchPasswordFile string
+	TrackedResources       string
+	MetricRules            string
+	InjectedFaultDir       string
+	ResourcePruning        string
+	ArtifactStorage        string
+	ArtifactPartSize       int64
+	ArtifactRetries        int
+	BigQueryExport         bool
+	NotifyWebhook          string
+	NotifyFormat           string
+	NotifyTitle            string
+	NotifyArtifactsURL     string
+	JiraURL                string
+	JiraProject            string
+	JiraTokenFile          string
+	JiraDryRun             bool
+	KnownFailuresFile      string
+	PRSummary              bool
+	PRSummaryIntervals     int
+	NodeAgent              bool
+	Local                  bool
 
 	genericclioptions.IOStreams
 }
 
 func NewRunMonitorOptions(streams genericclioptions.IOStreams, fromRepository string) *RunMonitorFlags {
 	return &RunMonitorFlags{
-		DisplayFromNow: true,
-		IOStreams:      streams,
-		FromRepository: fromRepository,
+		DisplayFromNow:     true,
+		CertificateExpiry:  certificateanalyzer.DefaultExpiryHorizon,
+		MaxRevisions:       staticpodrevisions.DefaultMaxRevisions,
+		DuplicateTestNames: string(monitortestframework.NamespaceDuplicateTestNames),
+		StorageLayout:      string(monitortestframework.FlatStorageLayout),
+		ArtifactStorage:    os.Getenv(artifactstorage.URLEnvVar),
+		ArtifactPartSize:   artifactstorage.DefaultPartSize,
+		ArtifactRetries:    artifactstorage.DefaultRetries,
+		NotifyFormat:       string(notifier.SlackFormat),
+		PRSummaryIntervals: 10,
+		OpenSearchIndex:    monitor.DefaultOpenSearchIndex,
+		IOStreams:          streams,
+		FromRepository:     fromRepository,
 	}
 }
 
@@ -90,9 +152,125 @@ func (f *RunMonitorFlags) BindFlags(flags *pflag.FlagSet) {
 		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
 	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
 	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
+	flags.IntVar(&f.WarningsToFail, "warnings-to-fail", f.WarningsToFail, "The number of warnings from a single monitor test that fail the run.  Zero means warnings never fail the run.")
+	flags.StringVar(&f.DuplicateTestNames, "duplicate-test-names", f.DuplicateTestNames,
+		fmt.Sprintf("What to do when monitor tests emit test cases with the same name, one of %s or %s.", monitortestframework.NamespaceDuplicateTestNames, monitortestframework.FailOnDuplicateTestNames))
+	flags.StringVar(&f.QuarantineFile, "quarantine-file", f.QuarantineFile, "A yaml file of test name regexes and the bugs tracking them.  Failures of matching tests are reported as flakes.")
+	flags.StringVar(&f.StorageLayout, "storage-layout", f.StorageLayout,
+		fmt.Sprintf("Where monitor tests write their content in the artifact directory, one of %s, %s, or %s.",
+			monitortestframework.FlatStorageLayout, monitortestframework.PerMonitorTestStorageLayout, monitortestframework.PerPhaseStorageLayout))
+	flags.StringVar(&f.StoragePhase, "storage-phase", f.StoragePhase, fmt.Sprintf("The subdirectory to write to for the %s storage layout, for instance pre-upgrade.", monitortestframework.PerPhaseStorageLayout))
+	flags.DurationVar(&f.CertificateExpiry, "certificate-expiry-horizon", f.CertificateExpiry, "Fail when an in-use platform certificate expires within this long of the end of the run.")
+	flags.IntVar(&f.MaxRevisions, "max-static-pod-revisions", f.MaxRevisions, "Fail when a static pod operator rolls out more revisions than this during the run.")
+	flags.StringToStringVar(&f.SecondaryClusters, "secondary-kubeconfig", f.SecondaryClusters,
+		"Kubeconfigs of clusters other than the cluster under test that monitor tests may also collect from, as name=path, for instance management=/path/to/kubeconfig.")
+	flags.DurationVar(&f.Backfill, "backfill", f.Backfill,
+		"Reconstruct intervals for this long before the monitor started from the history of the cluster, for monitors started after the run began.  Zero backfills nothing.")
+	flags.StringVar(&f.RecorderWAL, "recorder-wal", f.RecorderWAL,
+		"A file every interval is appended to as it is recorded.  A monitor restarted after a crash with the same file recovers the intervals recorded before the crash.")
+	flags.StringVar(&f.IntervalFile, "interval-file", f.IntervalFile, "A file every interval is also written to as it is recorded, one JSON object per line, for watching a long run.")
+	flags.StringVar(&f.IntervalEndpoint, "interval-endpoint", f.IntervalEndpoint, "A URL batches of intervals are posted to as they are recorded, as newline delimited JSON, for watching a long run.")
+	flags.StringVar(&f.LokiEndpoint, "loki-endpoint", f.LokiEndpoint,
+		"A Loki URL intervals are pushed to as they are recorded, as JSON log lines labeled with their source, level, and locator keys, for querying intervals across runs.")
+	flags.StringToStringVar(&f.LokiLabels, "loki-label", f.LokiLabels, "Labels added to every interval pushed to Loki, as name=value, for instance job_run=1234.")
+	flags.StringVar(&f.LokiTenant, "loki-tenant", f.LokiTenant, "The tenant intervals are pushed to in a multi-tenant Loki.")
+	flags.StringVar(&f.OpenSearchEndpoint, "opensearch-endpoint", f.OpenSearchEndpoint,
+		"An OpenSearch or Elasticsearch URL intervals are written to as they are recorded, as documents with their locator keys flattened into fields, for dashboards over long running clusters.")
+	flags.StringVar(&f.OpenSearchIndex, "opensearch-index", f.OpenSearchIndex, "The index intervals are written to in --opensearch-endpoint.")
+	flags.StringToStringVar(&f.OpenSearchFields, "opensearch-field", f.OpenSearchFields, "Fields added to every interval written to --opensearch-endpoint, as name=value, for instance cluster=perf-1.")
+	flags.StringVar(&f.OpenSearchUsername, "opensearch-username", f.OpenSearchUsername, "The user intervals are written to --opensearch-endpoint as, with basic authentication.")
+	flags.StringVar(&f.OpenSearchPasswordFile, "opensearch-password-file", f.OpenSearchPasswordFile, "A file holding the password of --opensearch-username.")
+	flags.StringVar(&f.TrackedResources, "tracked-resources-file", f.TrackedResources,
+		"A yaml file of resources to record in addition to the fixed set, by group, version, and resource, with fields to prune.  For instance the custom resources of a layered product's operator.")
+	flags.StringVar(&f.MetricRules, "metric-rules-file", f.MetricRules,
+		"A yaml file of rules that record an interval whenever a PromQL query crosses a threshold, and optionally fail when it stays past it for too long.  For adding metric based invariants without code.")
+	flags.StringVar(&f.InjectedFaultDir, "injected-fault-dir", f.InjectedFaultDir,
+		"A directory chaos tooling drops yaml or json files in declaring the faults it injected, like a node kill or a network partition, so the warnings and errors they cause are not evaluated by invariants.  Faults can also be posted to the live monitor API.")
+	flags.StringVar(&f.ResourcePruning, "resource-pruning-file", f.ResourcePruning,
+		"A yaml file of pruning policies for recorded resources, a default and one per resource type, to keep them within budget.  Without it, only managed fields are pruned.")
+	flags.StringVar(&f.ArtifactStorage, "artifact-storage", f.ArtifactStorage,
+		fmt.Sprintf("Where the artifact directory is uploaded when the monitor finishes, one of file:///path, s3://bucket/prefix, or gs://bucket/prefix.  Defaults to $%s.", artifactstorage.URLEnvVar))
+	flags.Int64Var(&f.ArtifactPartSize, "artifact-storage-part-size", f.ArtifactPartSize, "Artifacts larger than this many bytes are uploaded in parts of this size.")
+	flags.IntVar(&f.ArtifactRetries, "artifact-storage-retries", f.ArtifactRetries, "How many times a failed upload of an artifact is retried.")
+	flags.BoolVar(&f.BigQueryExport, "bigquery-export", f.BigQueryExport,
+		"Also write the intervals, junits, and cluster data as newline delimited JSON for the TRT data pipeline.  Requires $JOB_NAME and $BUILD_ID.")
+	flags.StringVar(&f.NotifyWebhook, "notify-webhook", f.NotifyWebhook,
+		"A URL a summary of the run is posted to once the tests are evaluated: the failed invariant tests, the longest disruptions, and a link to the artifacts.  For runs nobody watches, like soak clusters.")
+	flags.StringVar(&f.NotifyFormat, "notify-webhook-format", f.NotifyFormat,
+		fmt.Sprintf("The body posted to --notify-webhook, one of %s or %s.", notifier.SlackFormat, notifier.JSONFormat))
+	flags.StringVar(&f.NotifyTitle, "notify-title", f.NotifyTitle, "What the summary posted to --notify-webhook calls the run, for instance the name of the soak cluster.")
+	flags.StringVar(&f.NotifyArtifactsURL, "notify-artifacts-url", f.NotifyArtifactsURL, "Where the artifacts of the run can be browsed, linked from the summary posted to --notify-webhook.")
+	flags.StringVar(&f.JiraURL, "jira-url", f.JiraURL,
+		"A JIRA server the monitor test failures that are not in --known-failures-file are filed in, in the jira component of their monitor test.  An open issue already filed for the test is commented on instead.")
+	flags.StringVar(&f.JiraProject, "jira-project", f.JiraProject, "The project of the JIRA server issues are filed in.")
+	flags.StringVar(&f.JiraTokenFile, "jira-token-file", f.JiraTokenFile, "A file holding the personal access token issues are filed with.  Required unless --jira-dry-run is set.")
+	flags.BoolVar(&f.JiraDryRun, "jira-dry-run", f.JiraDryRun, "Only report the issues that would be filed in --jira-url.")
+	flags.StringVar(&f.KnownFailuresFile, "known-failures-file", f.KnownFailuresFile, "A yaml file of the names of tests known to fail, which are not filed in --jira-url.")
+	flags.BoolVar(&f.PRSummary, "pr-summary", f.PRSummary,
+		"Also write the failed monitor tests and the most interesting intervals as markdown, for CI to post to the pull request that triggered the presubmit.")
+	flags.IntVar(&f.PRSummaryIntervals, "pr-summary-intervals", f.PRSummaryIntervals, "How many of the most interesting intervals --pr-summary lists.")
+	flags.BoolVar(&f.Local, "local", f.Local,
+		"Developer mode for iterating on monitor tests against a personal cluster: artifacts are written to a new local directory unless --artifact-dir is set, outputs only CI reads are skipped, failures are reported as flakes, and a summary of the tests is printed.")
+	flags.BoolVar(&f.NodeAgent, "node-agent", f.NodeAgent,
+		"Deploy an agent on every node that probes disk latency, conntrack saturation, and DNS resolution from the node, and streams the intervals back while the monitor runs.")
 }
 
 func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
+	if f.Local && len(f.ArtifactDir) == 0 {
+		f.ArtifactDir = fmt.Sprintf("monitor-%s", time.Now().UTC().Format("20060102-150405"))
+	}
+	if f.PRSummary && f.PRSummaryIntervals <= 0 {
+		return nil, fmt.Errorf("--pr-summary-intervals must be positive, got %d", f.PRSummaryIntervals)
+	}
+	if f.Backfill < 0 {
+		return nil, fmt.Errorf("--backfill must not be negative, got %v", f.Backfill)
+	}
+	if len(f.IntervalEndpoint) > 0 {
+		endpoint, err := url.Parse(f.IntervalEndpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+			return nil, fmt.Errorf("--interval-endpoint must be an http or https URL, got %q", f.IntervalEndpoint)
+		}
+	}
+	if len(f.LokiEndpoint) > 0 {
+		endpoint, err := url.Parse(f.LokiEndpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+			return nil, fmt.Errorf("--loki-endpoint must be an http or https URL, got %q", f.LokiEndpoint)
+		}
+	}
+	openSearchOptions := monitor.OpenSearchSinkOptions{Index: f.OpenSearchIndex, Fields: f.OpenSearchFields, Username: f.OpenSearchUsername}
+	if len(f.OpenSearchEndpoint) > 0 {
+		endpoint, err := url.Parse(f.OpenSearchEndpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+			return nil, fmt.Errorf("--opensearch-endpoint must be an http or https URL, got %q", f.OpenSearchEndpoint)
+		}
+		if len(f.OpenSearchPasswordFile) > 0 {
+			content, err := os.ReadFile(f.OpenSearchPasswordFile)
+			if err != nil {
+				return nil, fmt.Errorf("--opensearch-password-file: %w", err)
+			}
+			openSearchOptions.Password = strings.TrimSpace(string(content))
+		}
+	}
+	switch monitortestframework.DuplicateTestNamePolicy(f.DuplicateTestNames) {
+	case monitortestframework.NamespaceDuplicateTestNames, monitortestframework.FailOnDuplicateTestNames:
+	default:
+		return nil, fmt.Errorf("--duplicate-test-names must be one of %s or %s, got %q",
+			monitortestframework.NamespaceDuplicateTestNames, monitortestframework.FailOnDuplicateTestNames, f.DuplicateTestNames)
+	}
+
+	var storage artifactstorage.Storage
+	// local runs keep their artifacts local.
+	if len(f.ArtifactStorage) > 0 && !f.Local {
+		if len(f.ArtifactDir) == 0 {
+			return nil, fmt.Errorf("--artifact-storage requires --artifact-dir")
+		}
+		var err error
+		storage, err = artifactstorage.New(f.ArtifactStorage, artifactstorage.Options{PartSize: f.ArtifactPartSize, Retries: f.ArtifactRetries})
+		if err != nil {
+			return nil, fmt.Errorf("--artifact-storage: %w", err)
+		}
+	}
+
 	var displayFilterFn monitorapi.EventIntervalMatchesFunc
 	if f.DisplayFromNow {
 		now := time.Now()
@@ -109,20 +287,148 @@ func (f *RunMonitorFlags) ToOptions() (*RunMonitorOptions, error) {
 		return nil, err
 	}
 
+	resourcePruningPolicies := monitor.ResourcePruningPolicies{}
+	if len(f.ResourcePruning) > 0 {
+		resourcePruningPolicies, err = monitor.LoadResourcePruningPolicies(f.ResourcePruning)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &RunMonitorOptions{
-		ArtifactDir:     f.ArtifactDir,
-		DisplayFilterFn: displayFilterFn,
-		MonitorTests:    monitorTestRegistry,
-		IOStreams:       f.IOStreams,
-		FromRepository:  f.FromRepository,
+		ArtifactDir:        f.ArtifactDir,
+		DisplayFilterFn:    displayFilterFn,
+		MonitorTests:       monitorTestRegistry,
+		IOStreams:          f.IOStreams,
+		FromRepository:     f.FromRepository,
+		RecorderWAL:        f.RecorderWAL,
+		IntervalFile:       f.IntervalFile,
+		IntervalEndpoint:   f.IntervalEndpoint,
+		LokiEndpoint:       f.LokiEndpoint,
+		LokiOptions:        monitor.LokiSinkOptions{Labels: f.LokiLabels, Tenant: f.LokiTenant},
+		OpenSearchEndpoint: f.OpenSearchEndpoint,
+		OpenSearchOptions:  openSearchOptions,
+		ResourceBudget:     monitor.NewResourceBudget(resourcePruningPolicies),
+		ArtifactStorage:    storage,
+		Local:              f.Local,
 	}, nil
 }
 
 func (f *RunMonitorFlags) getMonitorTestRegistry() (monitortestframework.MonitorTestRegistry, error) {
+	var quarantineList *monitortestframework.QuarantineList
+	if len(f.QuarantineFile) > 0 {
+		var err error
+		quarantineList, err = monitortestframework.LoadQuarantineList(f.QuarantineFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	storageLayout, err := monitortestframework.NewStorageLayout(monitortestframework.StorageLayoutKind(f.StorageLayout), f.StoragePhase)
+	if err != nil {
+		return nil, err
+	}
+
+	secondaryClusters, err := monitortestframework.LoadSecondaryClusters(f.SecondaryClusters)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackedResources *monitortestframework.TrackedResources
+	if len(f.TrackedResources) > 0 {
+		trackedResources, err = monitortestframework.LoadTrackedResources(f.TrackedResources)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var metricRules *monitortestframework.MetricRules
+	if len(f.MetricRules) > 0 {
+		metricRules, err = monitortestframework.LoadMetricRules(f.MetricRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// test binaries outside of openshift-tests contribute next to the other monitor content.
+	var contributionDir string
+	if len(f.ArtifactDir) > 0 {
+		contributionDir = filepath.Join(f.ArtifactDir, contribution.DirName)
+	}
+
+	var runNotifier monitortestframework.Notifier
+	if len(f.NotifyWebhook) > 0 {
+		runNotifier, err = notifier.NewWebhookNotifier(notifier.WebhookOptions{
+			URL:          f.NotifyWebhook,
+			Format:       notifier.Format(f.NotifyFormat),
+			Title:        f.NotifyTitle,
+			ArtifactsURL: f.NotifyArtifactsURL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("--notify-webhook: %w", err)
+		}
+	}
+
+	var issueFiler monitortestframework.IssueFiler
+	var knownFailures *monitortestframework.KnownFailures
+	if len(f.JiraURL) > 0 {
+		var token string
+		if len(f.JiraTokenFile) > 0 {
+			content, err := os.ReadFile(f.JiraTokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("--jira-token-file: %w", err)
+			}
+			token = strings.TrimSpace(string(content))
+		}
+		issueFiler, err = jira.NewIssueFiler(jira.Options{
+			URL:     f.JiraURL,
+			Project: f.JiraProject,
+			Token:   token,
+			DryRun:  f.JiraDryRun,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("--jira-url: %w", err)
+		}
+		if len(f.KnownFailuresFile) > 0 {
+			knownFailures, err = monitortestframework.LoadKnownFailures(f.KnownFailuresFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var prSummaryIntervals int
+	if f.PRSummary {
+		prSummaryIntervals = f.PRSummaryIntervals
+	}
+
 	monitorTestInfo := monitortestframework.MonitorTestInitializationInfo{
 		ClusterStabilityDuringTest: monitortestframework.Stable,
 		ExactMonitorTests:          f.ExactMonitorTests,
 		DisableMonitorTests:        f.DisableMonitorTests,
+		FailureBudgetPolicy: &monitortestframework.FailureBudgetPolicy{
+			WarningsToFail:     f.WarningsToFail,
+			FailuresAsWarnings: f.Local,
+		},
+		DuplicateTestNamePolicy:  monitortestframework.DuplicateTestNamePolicy(f.DuplicateTestNames),
+		QuarantineList:           quarantineList,
+		StorageLayout:            &storageLayout,
+		CertificateExpiryHorizon: f.CertificateExpiry,
+		MaxStaticPodRevisions:    f.MaxRevisions,
+		SecondaryClusters:        secondaryClusters,
+		BackfillDuration:         f.Backfill,
+		RecorderWALFile:          f.RecorderWAL,
+		TrackedResources:         trackedResources,
+		MetricRules:              metricRules,
+		InjectedFaultDir:         f.InjectedFaultDir,
+		ContributionDir:          contributionDir,
+		BigQueryExport:           f.BigQueryExport,
+		Notifier:                 runNotifier,
+		IssueFiler:               issueFiler,
+		KnownFailures:            knownFailures,
+		PRSummaryIntervals:       prSummaryIntervals,
+		LocalDeveloperMode:       f.Local,
+		NodeAgent:                f.NodeAgent,
 	}
 	return defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
 }
@@ -132,6 +438,22 @@ type RunMonitorOptions struct {
 	DisplayFilterFn monitorapi.EventIntervalMatchesFunc
 	MonitorTests    monitortestframework.MonitorTestRegistry
 	FromRepository  string
+	RecorderWAL     string
+	// IntervalFile and IntervalEndpoint are where intervals are streamed as they are recorded, besides the output.
+	IntervalFile     string
+	IntervalEndpoint string
+	// LokiEndpoint is a Loki intervals are pushed to as they are recorded, with the streams described by LokiOptions.
+	LokiEndpoint string
+	LokiOptions  monitor.LokiSinkOptions
+	// OpenSearchEndpoint is an OpenSearch intervals are written to as they are recorded, in the index described by
+	// OpenSearchOptions.
+	OpenSearchEndpoint string
+	OpenSearchOptions  monitor.OpenSearchSinkOptions
+	ResourceBudget     *monitor.ResourceBudget
+	// ArtifactStorage is where the artifact directory is uploaded when the monitor finishes, if anywhere.
+	ArtifactStorage artifactstorage.Storage
+	// Local prints a summary of the monitor tests for a developer once the results are written.
+	Local bool
 
 	genericclioptions.IOStreams
 }
@@ -152,6 +474,12 @@ func (o *RunMonitorOptions) Run() error {
 
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
+
+	// a monitor of a cluster that cannot be tested only records the cluster being broken.
+	if err := o.runPreflightChecks(ctx, restConfig); err != nil {
+		return err
+	}
+
 	abortCh := make(chan os.Signal, 2)
 	go func() {
 		<-abortCh
@@ -170,7 +498,58 @@ func (o *RunMonitorOptions) Run() error {
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
-	recorder := monitor.WrapWithJSONLRecorder(monitor.NewRecorder(), o.Out, o.DisplayFilterFn)
+	recorder := monitor.NewRecorderWithResourceBudget(o.ResourceBudget)
+	if len(o.RecorderWAL) > 0 {
+		walFile, err := monitor.OpenRecorderWAL(o.RecorderWAL)
+		if err != nil {
+			return fmt.Errorf("unable to open the recorder write-ahead log: %w", err)
+		}
+		defer walFile.Close()
+		recorder = monitor.WrapWithWALRecorder(recorder, walFile)
+	}
+	sinks := []monitor.IntervalSink{monitor.NewJSONLSink(o.Out, o.DisplayFilterFn)}
+	if len(o.IntervalFile) > 0 {
+		intervalFile, err := os.Create(o.IntervalFile)
+		if err != nil {
+			return fmt.Errorf("unable to create the interval file: %w", err)
+		}
+		defer intervalFile.Close()
+		sinks = append(sinks, monitor.NewJSONLSink(intervalFile, nil))
+	}
+	if len(o.IntervalEndpoint) > 0 {
+		httpSink := monitor.NewHTTPSink(o.IntervalEndpoint, monitor.DefaultHTTPSinkBatchSize, monitor.DefaultHTTPSinkFlushInterval)
+		defer func() {
+			closeContext, closeCancel := context.WithTimeout(context.Background(), time.Minute)
+			defer closeCancel()
+			if err := httpSink.Close(closeContext); err != nil {
+				fmt.Fprintf(o.ErrOut, "error: Not every interval reached the interval endpoint: %v\n", err)
+			}
+		}()
+		sinks = append(sinks, httpSink)
+	}
+	if len(o.LokiEndpoint) > 0 {
+		lokiSink := monitor.NewLokiSink(o.LokiEndpoint, o.LokiOptions, monitor.DefaultHTTPSinkBatchSize, monitor.DefaultHTTPSinkFlushInterval)
+		defer func() {
+			closeContext, closeCancel := context.WithTimeout(context.Background(), time.Minute)
+			defer closeCancel()
+			if err := lokiSink.Close(closeContext); err != nil {
+				fmt.Fprintf(o.ErrOut, "error: Not every interval reached Loki: %v\n", err)
+			}
+		}()
+		sinks = append(sinks, lokiSink)
+	}
+	if len(o.OpenSearchEndpoint) > 0 {
+		openSearchSink := monitor.NewOpenSearchSink(o.OpenSearchEndpoint, o.OpenSearchOptions, monitor.DefaultHTTPSinkBatchSize, monitor.DefaultHTTPSinkFlushInterval)
+		defer func() {
+			closeContext, closeCancel := context.WithTimeout(context.Background(), time.Minute)
+			defer closeCancel()
+			if err := openSearchSink.Close(closeContext); err != nil {
+				fmt.Fprintf(o.ErrOut, "error: Not every interval reached OpenSearch: %v\n", err)
+			}
+		}()
+		sinks = append(sinks, openSearchSink)
+	}
+	recorder = monitor.WrapWithSinks(recorder, sinks...)
 	m := monitor.NewMonitor(
 		recorder,
 		restConfig,
@@ -197,6 +576,40 @@ func (o *RunMonitorOptions) Run() error {
 	if err := m.SerializeResults(ctx, "invariants", ""); err != nil {
 		return err
 	}
+	if len(o.ArtifactDir) > 0 {
+		if err := o.ResourceBudget.WriteReport(o.ArtifactDir, ""); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to write the resource budget report: %v\n", err)
+		}
+	}
+	if o.Local {
+		if summary := m.ResultsSummary(); summary != nil {
+			monitor.WriteConsoleSummary(o.Out, summary)
+		}
+		fmt.Fprintf(o.Out, "\nArtifacts were written to %s.\n", o.ArtifactDir)
+	}
+	if o.ArtifactStorage != nil {
+		fmt.Fprintf(o.Out, "Uploading artifacts to %v.\n", o.ArtifactStorage)
+		uploadContext, uploadCancel := context.WithTimeout(context.Background(), time.Hour)
+		defer uploadCancel()
+		if err := artifactstorage.Upload(uploadContext, o.ArtifactStorage, o.ArtifactDir); err != nil {
+			return fmt.Errorf("unable to upload artifacts to %v: %w", o.ArtifactStorage, err)
+		}
+	}
 
 	return nil
 }
+
+func (o *RunMonitorOptions) runPreflightChecks(ctx context.Context, restConfig *rest.Config) error {
+	clients, err := preflight.NewClients(restConfig)
+	if err != nil {
+		return err
+	}
+	report := preflight.Run(ctx, clients, preflight.DefaultChecks(version.Get().GitVersion)...)
+	fmt.Fprint(o.Out, report.String())
+	if len(o.ArtifactDir) > 0 {
+		if err := report.Write(o.ArtifactDir, ""); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to write preflight report: %v\n", err)
+		}
+	}
+	return report.Err()
+}
@@ -0,0 +1,156 @@
+package runinvariants
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/origin/pkg/clioptions/imagesetup"
+	"github.com/openshift/origin/pkg/defaultmonitortests"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+type RunInvariantsFlags struct {
+	ArtifactDir         string
+	Begin               string
+	End                 string
+	ExactMonitorTests   []string
+	DisableMonitorTests []string
+	FromRepository      string
+	WarningsToFail      int
+	QuarantineFile      string
+
+	genericclioptions.IOStreams
+}
+
+func NewRunInvariantsFlags(streams genericclioptions.IOStreams, fromRepository string) *RunInvariantsFlags {
+	return &RunInvariantsFlags{
+		FromRepository: fromRepository,
+		IOStreams:      streams,
+	}
+}
+
+func NewRunInvariantsCommand(streams genericclioptions.IOStreams) *cobra.Command {
+	f := NewRunInvariantsFlags(streams, imagesetup.DefaultTestImageMirrorLocation)
+
+	cmd := &cobra.Command{
+		Use:   "run-invariants",
+		Short: "Evaluate the monitor tests against a window of an already running cluster",
+		Long: templates.LongDesc(`
+		Evaluate the monitor tests against a window of an already running cluster
+
+		The part of the window before the command started is reconstructed from the history of the cluster by the
+		monitor tests able to backfill, the rest is collected live until the end of the window.  The intervals and
+		junits are written to the artifact directory.  For instance, to evaluate the hour an incident began in:
+
+		  openshift-tests monitor run-invariants --begin=2024-01-01T12:00:00Z --end=2024-01-01T13:00:00Z --artifact-dir=incident
+
+		The command fails when a monitor test failed in the window.
+		`),
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o, err := f.ToOptions(time.Now())
+			if err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	f.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (f *RunInvariantsFlags) BindFlags(flags *pflag.FlagSet) {
+	monitorNames := defaultmonitortests.ListAllMonitorTests()
+
+	flags.StringVar(&f.ArtifactDir, "artifact-dir", f.ArtifactDir, "The directory where the intervals and junits of the window will be stored.  Defaults to a new directory named after the window.")
+	flags.StringVar(&f.Begin, "begin", f.Begin,
+		fmt.Sprintf("The beginning of the window, in %s format or as a duration before now, for instance 2h.  A beginning in the future is waited for.", time.RFC3339))
+	flags.StringVar(&f.End, "end", f.End,
+		fmt.Sprintf("The end of the window, in %s format or as a duration before now.  An end in the future is waited for.  Defaults to now.", time.RFC3339))
+	flags.StringSliceVar(&f.ExactMonitorTests, "monitor", f.ExactMonitorTests,
+		fmt.Sprintf("list of exactly which monitors to enable. All others will be disabled.  Current monitors are: [%s]", strings.Join(monitorNames, ", ")))
+	flags.StringSliceVar(&f.DisableMonitorTests, "disable-monitor", f.DisableMonitorTests, "list of monitors to disable.  Defaults for others will be honored.")
+	flags.StringVar(&f.FromRepository, "from-repository", f.FromRepository, "A container image repository to retrieve test images from.")
+	flags.IntVar(&f.WarningsToFail, "warnings-to-fail", f.WarningsToFail, "The number of warnings from a single monitor test that fail the run.  Zero means warnings never fail the run.")
+	flags.StringVar(&f.QuarantineFile, "quarantine-file", f.QuarantineFile, "A yaml file of test name regexes and the bugs tracking them.  Failures of matching tests are reported as flakes.")
+}
+
+// ToOptions resolves the window relative to now.
+func (f *RunInvariantsFlags) ToOptions(now time.Time) (*RunInvariantsOptions, error) {
+	if len(f.Begin) == 0 {
+		return nil, fmt.Errorf("--begin must be specified")
+	}
+	begin, err := parseWindowTime(f.Begin, now)
+	if err != nil {
+		return nil, fmt.Errorf("--begin: %w", err)
+	}
+	end := now
+	if len(f.End) > 0 {
+		end, err = parseWindowTime(f.End, now)
+		if err != nil {
+			return nil, fmt.Errorf("--end: %w", err)
+		}
+	}
+	if !begin.Before(end) {
+		return nil, fmt.Errorf("--begin must be before --end, got %v and %v", begin, end)
+	}
+
+	artifactDir := f.ArtifactDir
+	if len(artifactDir) == 0 {
+		artifactDir = fmt.Sprintf("invariants-%s-%s", begin.UTC().Format("20060102-150405"), end.UTC().Format("20060102-150405"))
+	}
+
+	var quarantineList *monitortestframework.QuarantineList
+	if len(f.QuarantineFile) > 0 {
+		quarantineList, err = monitortestframework.LoadQuarantineList(f.QuarantineFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	monitorTestRegistry, err := defaultmonitortests.NewMonitorTestsFor(monitortestframework.MonitorTestInitializationInfo{
+		ClusterStabilityDuringTest: monitortestframework.Stable,
+		ExactMonitorTests:          f.ExactMonitorTests,
+		DisableMonitorTests:        f.DisableMonitorTests,
+		FailureBudgetPolicy: &monitortestframework.FailureBudgetPolicy{
+			WarningsToFail: f.WarningsToFail,
+		},
+		QuarantineList: quarantineList,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RunInvariantsOptions{
+		ArtifactDir:    artifactDir,
+		Begin:          begin,
+		End:            end,
+		MonitorTests:   monitorTestRegistry,
+		FromRepository: f.FromRepository,
+		IOStreams:      f.IOStreams,
+	}, nil
+}
+
+// parseWindowTime parses a time in RFC3339 format, or a duration before now.
+func parseWindowTime(value string, now time.Time) (time.Time, error) {
+	if ago, err := time.ParseDuration(value); err == nil {
+		if ago < 0 {
+			return time.Time{}, fmt.Errorf("the duration before now must not be negative, got %v", ago)
+		}
+		return now.Add(-ago), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be in %s format or a duration, got %q", time.RFC3339, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,83 @@
+package runinvariants
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToOptionsWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		begin         string
+		end           string
+		expectedBegin time.Time
+		expectedEnd   time.Time
+		expectedErr   string
+	}{
+		{
+			name:          "timestamps",
+			begin:         "2024-01-01T12:00:00Z",
+			end:           "2024-01-01T13:00:00Z",
+			expectedBegin: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "durations before now",
+			begin:         "2h",
+			end:           "30m",
+			expectedBegin: now.Add(-2 * time.Hour),
+			expectedEnd:   now.Add(-30 * time.Minute),
+		},
+		{
+			name:          "until now",
+			begin:         "2024-01-01T12:00:00Z",
+			expectedBegin: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			expectedEnd:   now,
+		},
+		{
+			name:          "in the future",
+			begin:         "2024-01-01T15:00:00Z",
+			end:           "2024-01-01T16:00:00Z",
+			expectedBegin: time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "no beginning",
+			expectedErr: "--begin must be specified",
+		},
+		{
+			name:        "not a time",
+			begin:       "yesterday",
+			expectedErr: "--begin: must be in",
+		},
+		{
+			name:        "ends before it begins",
+			begin:       "1h",
+			end:         "2h",
+			expectedErr: "--begin must be before --end",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &RunInvariantsFlags{Begin: tt.begin, End: tt.end, ExactMonitorTests: []string{"timeline-serializer"}}
+			o, err := f.ToOptions(now)
+			if len(tt.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !o.Begin.Equal(tt.expectedBegin) || !o.End.Equal(tt.expectedEnd) {
+				t.Errorf("expected the window from %v to %v, got %v to %v", tt.expectedBegin, tt.expectedEnd, o.Begin, o.End)
+			}
+			if len(o.ArtifactDir) == 0 {
+				t.Errorf("expected a default artifact directory")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package runinvariants
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/openshift/origin/pkg/clioptions/clusterinfo"
+	"github.com/openshift/origin/pkg/disruption/backend/sampler"
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/test/extended/util/image"
+)
+
+type RunInvariantsOptions struct {
+	ArtifactDir string
+	// Begin and End are the window the monitor tests are evaluated in.
+	Begin          time.Time
+	End            time.Time
+	MonitorTests   monitortestframework.MonitorTestRegistry
+	FromRepository string
+
+	genericclioptions.IOStreams
+}
+
+// Run waits for the beginning of the window if it is in the future, backfills the part of the window that already
+// happened, collects until the end of the window, and writes the intervals and junits of the window.  An interrupted
+// run evaluates the window until it was interrupted.
+func (o *RunInvariantsOptions) Run() error {
+	// set globals so that helpers will create pods with the mapped images if we create them from this process.
+	image.InitializeImages(o.FromRepository)
+
+	restConfig, err := clusterinfo.GetMonitorRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	abortCh := make(chan os.Signal, 2)
+	go func() {
+		<-abortCh
+		fmt.Fprintf(o.ErrOut, "Interrupted, terminating\n")
+		sampler.TearDownInClusterMonitors(restConfig)
+		cancelFn()
+
+		sig := <-abortCh
+		fmt.Fprintf(o.ErrOut, "Interrupted twice, exiting (%s)\n", sig)
+		switch sig {
+		case syscall.SIGINT:
+			os.Exit(130)
+		default:
+			os.Exit(0)
+		}
+	}()
+	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
+
+	if wait := time.Until(o.Begin); wait > 0 {
+		fmt.Fprintf(o.Out, "Waiting %v for the window to begin at %v.\n", wait.Round(time.Second), o.Begin)
+		if !sleep(ctx, wait) {
+			return nil
+		}
+	}
+	// the monitor tests reconstruct the part of the window before they start from the history of the cluster.
+	if backfill := time.Since(o.Begin); backfill > 0 {
+		o.MonitorTests.SetBackfillDuration(backfill)
+	}
+
+	m := monitor.NewMonitorForWindow(monitor.NewRecorder(), restConfig, o.ArtifactDir, o.MonitorTests, o.End)
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+	if wait := time.Until(o.End); wait > 0 {
+		fmt.Fprintf(o.Out, "Monitor started, collecting for %v until the window ends at %v...\n", wait.Round(time.Second), o.End)
+		sleep(ctx, wait)
+	}
+
+	fmt.Fprintf(o.Out, "Evaluating the monitor tests from %v to %v, this may take up to twenty minutes...\n", o.Begin, o.End)
+	cleanupContext, cleanupCancel := context.WithTimeout(context.Background(), 20*time.Minute)
+	defer cleanupCancel()
+	resultState, err := m.Stop(cleanupContext)
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "error cleaning up, still reporting as best as possible: %v\n", err)
+	}
+	if err := m.SerializeResults(cleanupContext, "invariants", ""); err != nil {
+		return err
+	}
+
+	if summary := m.ResultsSummary(); summary != nil {
+		monitor.WriteConsoleSummary(o.Out, summary)
+	}
+	fmt.Fprintf(o.Out, "\nArtifacts were written to %s.\n", o.ArtifactDir)
+	if resultState == monitor.Failed {
+		return fmt.Errorf("monitor tests failed in the window from %v to %v", o.Begin, o.End)
+	}
+	return nil
+}
+
+// sleep waits for d, and returns false if the context finished first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
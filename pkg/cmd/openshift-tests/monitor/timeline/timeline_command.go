@@ -23,10 +23,11 @@ type TimelineOptions struct {
 	PodResourceFilename  string
 	TimelineType         string
 
-	LocatorMatchers []string
-	Namespaces      []string
-	OutputType      string
-	EndDate         string
+	LocatorMatchers  []string
+	LocatorSelectors []string
+	Namespaces       []string
+	OutputType       string
+	EndDate          string
 
 	KnownRenderers map[string]RenderFunc
 	KnownTimelines map[string]monitorapi.EventIntervalMatchesFunc
@@ -96,6 +97,7 @@ func (o *TimelineOptions) Bind(flagset *pflag.FlagSet) error {
 	flagset.StringVar(&o.TimelineType, "type", o.TimelineType, "type of timeline to produce: "+strings.Join(sets.StringKeySet(o.KnownTimelines).List(), ","))
 	flagset.StringVar(&o.PodResourceFilename, "known-pods", o.PodResourceFilename, "resource-pods_<timestamp>.zip filename from openshift-tests.")
 	flagset.StringSliceVarP(&o.LocatorMatchers, "locator", "l", o.LocatorMatchers, "key=value selector for monitor event locators (where value is a regex).  for instance -lpod=openshift-etcd-installer.  The same key listed multiple times means an OR.  Each separate key is logically ANDed.  Precede value with a dash for anti-match")
+	flagset.StringArrayVar(&o.LocatorSelectors, "selector", o.LocatorSelectors, "selector for monitor event locators with set and wildcard support.  for instance --selector='namespace in (openshift-etcd,openshift-kube-apiserver),pod=etcd-*'.  Multiple selectors are logically ORed.")
 	flagset.StringVarP(&o.EndDate, "end-date", "e", o.EndDate, fmt.Sprintf("Stop date (default is one hour after latest event) in RFC3399 format in UTC timezone: %s", time.RFC3339))
 
 	return nil
@@ -129,6 +131,12 @@ func (o *TimelineOptions) Validate() error {
 		}
 	}
 
+	for _, selector := range o.LocatorSelectors {
+		if _, err := monitorapi.ParseLocatorSelector(selector); err != nil {
+			return fmt.Errorf("invalid --selector: %w", err)
+		}
+	}
+
 	if len(o.EndDate) > 0 {
 		_, err := time.ParseInLocation(time.RFC3339, o.EndDate, time.UTC)
 		if err != nil {
@@ -155,6 +163,17 @@ func (o *TimelineOptions) ToTimeline() *Timeline {
 		}
 	}
 
+	var locatorSelector monitorapi.EventIntervalMatchesFunc
+	if len(o.LocatorSelectors) > 0 {
+		selectors := []monitorapi.EventIntervalMatchesFunc{}
+		for _, selector := range o.LocatorSelectors {
+			// validated already
+			matcher, _ := monitorapi.ParseLocatorSelector(selector)
+			selectors = append(selectors, matcher)
+		}
+		locatorSelector = monitorapi.Or(selectors...)
+	}
+
 	var endDateTime = &time.Time{}
 	if len(o.EndDate) > 0 {
 		parsedTime, _ := time.Parse(time.RFC3339, o.EndDate)
@@ -169,6 +188,7 @@ func (o *TimelineOptions) ToTimeline() *Timeline {
 
 		LocatorMatcher:        locatorMatcher,
 		RemovedLocatorMatcher: inverseLocatorMatcher,
+		LocatorSelector:       locatorSelector,
 		Namespaces:            o.Namespaces,
 		EndDate:               endDateTime,
 
@@ -184,6 +204,7 @@ type Timeline struct {
 
 	LocatorMatcher        map[string][]*regexp.Regexp
 	RemovedLocatorMatcher map[string][]*regexp.Regexp
+	LocatorSelector       monitorapi.EventIntervalMatchesFunc
 	Namespaces            []string
 	EndDate               *time.Time
 
@@ -210,6 +231,9 @@ func (o *Timeline) Run() error {
 	if len(o.RemovedLocatorMatcher) > 0 {
 		filteredEvents = filteredEvents.Filter(monitorapi.NotContainsAllParts(o.RemovedLocatorMatcher))
 	}
+	if o.LocatorSelector != nil {
+		filteredEvents = filteredEvents.Filter(o.LocatorSelector)
+	}
 	// compute intervals from raw
 	var to time.Time
 
@@ -4,34 +4,66 @@ import (
 	"fmt"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/intervaltimeline"
 	"github.com/openshift/origin/pkg/monitortests/authentication/legacyauthenticationmonitortests"
+	"github.com/openshift/origin/pkg/monitortests/authentication/loginflowavailability"
+	"github.com/openshift/origin/pkg/monitortests/authentication/podsecurityposture"
 	"github.com/openshift/origin/pkg/monitortests/authentication/requiredsccmonitortests"
+	"github.com/openshift/origin/pkg/monitortests/authentication/securitydrift"
 	azuremetrics "github.com/openshift/origin/pkg/monitortests/cloud/azure/metrics"
+	"github.com/openshift/origin/pkg/monitortests/cloud/cloudthrottling"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/legacycvomonitortests"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/operatorstateanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/terminationmessagepolicy"
+	"github.com/openshift/origin/pkg/monitortests/clusterversionoperator/updatephases"
+	"github.com/openshift/origin/pkg/monitortests/controlplane/leaderelectionanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/controlplane/staticpodrevisions"
+	"github.com/openshift/origin/pkg/monitortests/etcd/etcdbackupreadiness"
+	"github.com/openshift/origin/pkg/monitortests/etcd/etcdhealth"
 	"github.com/openshift/origin/pkg/monitortests/etcd/etcdloganalyzer"
 	"github.com/openshift/origin/pkg/monitortests/etcd/legacyetcdmonitortests"
+	"github.com/openshift/origin/pkg/monitortests/hypershift/hostedcontrolplanehealth"
 	"github.com/openshift/origin/pkg/monitortests/imageregistry/disruptionimageregistry"
+	"github.com/openshift/origin/pkg/monitortests/imageregistry/imageregistryhealth"
+	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/apirequestlatency"
+	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/apiserveravailabilityslo"
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/apiservergracefulrestart"
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/auditloganalyzer"
+	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/certificateanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/disruptionlegacyapiservers"
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/disruptionnewapiserver"
 	"github.com/openshift/origin/pkg/monitortests/kubeapiserver/legacykubeapiservermonitortests"
+	"github.com/openshift/origin/pkg/monitortests/machineconfigoperator/rolloutanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/monitoring/alertmanagernotificationpath"
 	"github.com/openshift/origin/pkg/monitortests/monitoring/disruptionmetricsapi"
 	"github.com/openshift/origin/pkg/monitortests/monitoring/statefulsetsrecreation"
+	"github.com/openshift/origin/pkg/monitortests/network/connectivitymesh"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptioningress"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
 	"github.com/openshift/origin/pkg/monitortests/network/disruptionserviceloadbalancer"
+	"github.com/openshift/origin/pkg/monitortests/network/dnsresolutionhealth"
+	"github.com/openshift/origin/pkg/monitortests/network/ingressreachability"
 	"github.com/openshift/origin/pkg/monitortests/network/legacynetworkmonitortests"
+	"github.com/openshift/origin/pkg/monitortests/network/multushealth"
+	"github.com/openshift/origin/pkg/monitortests/node/clockskew"
+	"github.com/openshift/origin/pkg/monitortests/node/containerrestartanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/node/imagepulllatency"
 	"github.com/openshift/origin/pkg/monitortests/node/kubeletlogcollector"
 	"github.com/openshift/origin/pkg/monitortests/node/legacynodemonitortests"
+	"github.com/openshift/origin/pkg/monitortests/node/nodeagent"
+	"github.com/openshift/origin/pkg/monitortests/node/nodejournalscanner"
 	"github.com/openshift/origin/pkg/monitortests/node/nodestateanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/node/pdbanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/node/resourceconsumption"
+	"github.com/openshift/origin/pkg/monitortests/node/terminationgraceanalyzer"
 	"github.com/openshift/origin/pkg/monitortests/node/watchnodes"
 	"github.com/openshift/origin/pkg/monitortests/node/watchpods"
+	"github.com/openshift/origin/pkg/monitortests/olm/olmhealth"
 	"github.com/openshift/origin/pkg/monitortests/storage/legacystoragemonitortests"
+	"github.com/openshift/origin/pkg/monitortests/storage/volumeoperationlatency"
 	"github.com/openshift/origin/pkg/monitortests/testframework/additionaleventscollector"
 	"github.com/openshift/origin/pkg/monitortests/testframework/alertanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/bigqueryexport"
 	"github.com/openshift/origin/pkg/monitortests/testframework/clusterinfoserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalawscloudservicemonitoring"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalazurecloudservicemonitoring"
@@ -39,13 +71,22 @@ import (
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionexternalservicemonitoring"
 	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/e2etestanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/externalcontributions"
+	"github.com/openshift/origin/pkg/monitortests/testframework/injectedfaults"
 	"github.com/openshift/origin/pkg/monitortests/testframework/intervalserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/knownimagechecker"
 	"github.com/openshift/origin/pkg/monitortests/testframework/legacytestframeworkmonitortests"
+	"github.com/openshift/origin/pkg/monitortests/testframework/loadgeneratoranalyzer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/metricrules"
 	"github.com/openshift/origin/pkg/monitortests/testframework/pathologicaleventanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/prsummary"
+	"github.com/openshift/origin/pkg/monitortests/testframework/resourceleaks"
+	"github.com/openshift/origin/pkg/monitortests/testframework/riskanalysisinput"
+	"github.com/openshift/origin/pkg/monitortests/testframework/runnerresourceusage"
 	"github.com/openshift/origin/pkg/monitortests/testframework/timelineserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/trackedresourcesserializer"
 	"github.com/openshift/origin/pkg/monitortests/testframework/watchclusteroperators"
+	"github.com/openshift/origin/pkg/monitortests/testframework/watchcustomresources"
 	"github.com/openshift/origin/pkg/monitortests/testframework/watchevents"
 	"github.com/openshift/origin/pkg/monitortests/testframework/watchrequestcountscollector"
 	"github.com/sirupsen/logrus"
@@ -83,6 +124,34 @@ func NewMonitorTestsFor(info monitortestframework.MonitorTestInitializationInfo)
 		panic(fmt.Sprintf("unknown cluster stability level: %q", info.ClusterStabilityDuringTest))
 	}
 
+	if info.FailureBudgetPolicy != nil {
+		startingRegistry.SetFailureBudgetPolicy(*info.FailureBudgetPolicy)
+	}
+	if len(info.DuplicateTestNamePolicy) > 0 {
+		startingRegistry.SetDuplicateTestNamePolicy(info.DuplicateTestNamePolicy)
+	}
+	if info.QuarantineList != nil {
+		startingRegistry.SetQuarantineList(info.QuarantineList)
+	}
+	if info.StorageLayout != nil {
+		startingRegistry.SetStorageLayout(*info.StorageLayout)
+	}
+	if info.SecondaryClusters != nil {
+		startingRegistry.SetSecondaryClusters(info.SecondaryClusters)
+	}
+	if info.BackfillDuration > 0 {
+		startingRegistry.SetBackfillDuration(info.BackfillDuration)
+	}
+	if len(info.RecorderWALFile) > 0 {
+		startingRegistry.SetRecorderWAL(info.RecorderWALFile)
+	}
+	if info.Notifier != nil {
+		startingRegistry.SetNotifier(info.Notifier)
+	}
+	if info.IssueFiler != nil {
+		startingRegistry.SetIssueFiler(info.IssueFiler, info.KnownFailures)
+	}
+
 	switch {
 	case len(info.ExactMonitorTests) > 0:
 		return startingRegistry.GetRegistryFor(info.ExactMonitorTests...)
@@ -102,13 +171,29 @@ func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationI
 	monitorTestRegistry.AddRegistryOrDie(newUniversalMonitorTests(info))
 
 	monitorTestRegistry.AddMonitorTestOrDie("image-registry-availability", "Image Registry", disruptionimageregistry.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestOrDie("image-registry-health", "Image Registry", imageregistryhealth.NewImageRegistryHealth())
 
 	monitorTestRegistry.AddMonitorTestOrDie("apiserver-availability", "kube-apiserver", disruptionlegacyapiservers.NewAvailabilityInvariant())
 	monitorTestRegistry.AddMonitorTestOrDie("apiserver-new-disruption-invariant", "kube-apiserver", disruptionnewapiserver.NewDisruptionInvariant())
+	monitorTestRegistry.AddMonitorTestOrDie("apiserver-availability-slo", "kube-apiserver", apiserveravailabilityslo.NewAvailabilitySLOInvariant(info))
+	monitorTestRegistry.AddMonitorTestOrDie("certificate-analyzer", "kube-apiserver", certificateanalyzer.NewCertificateAnalyzer(info))
+	monitorTestRegistry.AddMonitorTestOrDie("static-pod-revisions", "kube-apiserver", staticpodrevisions.NewStaticPodRevisions(info))
+
+	monitorTestRegistry.AddMonitorTestOrDie("login-flow-availability", "apiserver-auth", loginflowavailability.NewLoginFlowAvailability())
+	monitorTestRegistry.AddMonitorTestOrDie("security-drift", "apiserver-auth", securitydrift.NewSecurityDrift(info))
 
 	monitorTestRegistry.AddMonitorTestOrDie("pod-network-avalibility", "Network / ovn-kubernetes", disruptionpodnetwork.NewPodNetworkAvalibilityInvariant(info))
 	monitorTestRegistry.AddMonitorTestOrDie("service-type-load-balancer-availability", "Networking / router", disruptionserviceloadbalancer.NewAvailabilityInvariant())
 	monitorTestRegistry.AddMonitorTestOrDie("ingress-availability", "Networking / router", disruptioningress.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestOrDie("ingress-reachability", "Networking / router", ingressreachability.NewIngressReachability())
+	monitorTestRegistry.AddMonitorTestOrDie("dns-resolution-health", "Networking / DNS", dnsresolutionhealth.NewDNSResolutionHealth(info))
+	monitorTestRegistry.AddMonitorTestOrDie("network-connectivity-mesh", "Network / ovn-kubernetes", connectivitymesh.NewConnectivityMesh(info))
+	monitorTestRegistry.AddMonitorTestOrDie("node-agent", "Node / Kubelet", nodeagent.NewNodeAgent(info))
+
+	monitorTestRegistry.AddMonitorTestOrDie("etcd-health", "etcd", etcdhealth.NewEtcdHealth())
+	monitorTestRegistry.AddMonitorTestOrDie("etcd-backup-readiness", "etcd", etcdbackupreadiness.NewEtcdBackupReadiness())
+
+	monitorTestRegistry.AddMonitorTestOrDie("hosted-control-plane-health", "HyperShift", hostedcontrolplanehealth.NewHostedControlPlaneHealth())
 
 	monitorTestRegistry.AddMonitorTestOrDie("alert-summary-serializer", "Test Framework", alertanalyzer.NewAlertSummarySerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("external-service-availability", "Test Framework", disruptionexternalservicemonitoring.NewAvailabilityInvariant())
@@ -120,6 +205,7 @@ func newDefaultMonitorTests(info monitortestframework.MonitorTestInitializationI
 
 	monitorTestRegistry.AddMonitorTestOrDie("monitoring-statefulsets-recreation", "Monitoring", statefulsetsrecreation.NewStatefulsetsChecker())
 	monitorTestRegistry.AddMonitorTestOrDie("metrics-api-availability", "Monitoring", disruptionmetricsapi.NewAvailabilityInvariant())
+	monitorTestRegistry.AddMonitorTestOrDie("alertmanager-notification-path", "Monitoring", alertmanagernotificationpath.NewAlertmanagerNotificationPath())
 
 	return monitorTestRegistry
 }
@@ -143,11 +229,13 @@ func newUniversalMonitorTests(info monitortestframework.MonitorTestInitializatio
 	monitorTestRegistry := monitortestframework.NewMonitorTestRegistry()
 
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-authentication-invariants", "apiserver-auth", legacyauthenticationmonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestOrDie("pod-security-posture", "apiserver-auth", podsecurityposture.NewAnalyzer())
 
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-cvo-invariants", "Cluster Version Operator", legacycvomonitortests.NewLegacyTests())
 	monitorTestRegistry.AddMonitorTestOrDie("termination-message-policy", "Cluster Version Operator", terminationmessagepolicy.NewAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("operator-state-analyzer", "Cluster Version Operator", operatorstateanalyzer.NewAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("required-scc-annotation-checker", "Cluster Version Operator", requiredsccmonitortests.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("cluster-update-phases", "Cluster Version Operator", updatephases.NewUpdatePhases())
 
 	monitorTestRegistry.AddMonitorTestOrDie("etcd-log-analyzer", "etcd", etcdloganalyzer.NewEtcdLogAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-etcd-invariants", "etcd", legacyetcdmonitortests.NewLegacyTests())
@@ -155,30 +243,66 @@ func newUniversalMonitorTests(info monitortestframework.MonitorTestInitializatio
 	monitorTestRegistry.AddMonitorTestOrDie("audit-log-analyzer", "kube-apiserver", auditloganalyzer.NewAuditLogAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-kube-apiserver-invariants", "kube-apiserver", legacykubeapiservermonitortests.NewLegacyTests())
 	monitorTestRegistry.AddMonitorTestOrDie("graceful-shutdown-analyzer", "kube-apiserver", apiservergracefulrestart.NewGracefulShutdownAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("api-request-latency", "kube-apiserver", apirequestlatency.NewAPIRequestLatency())
 
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-networking-invariants", "Networking / cluster-network-operator", legacynetworkmonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestOrDie("multus-health", "Networking / multus", multushealth.NewMultusHealth())
 
+	monitorTestRegistry.AddMonitorTestOrDie("container-restart-analyzer", "Node / Kubelet", containerrestartanalyzer.NewContainerRestartAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("pdb-analyzer", "kube-controller-manager", pdbanalyzer.NewPDBAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("leader-election-analyzer", "kube-controller-manager", leaderelectionanalyzer.NewLeaderElectionAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("kubelet-log-collector", "Node / Kubelet", kubeletlogcollector.NewKubeletLogCollector())
+	monitorTestRegistry.AddMonitorTestOrDie("node-journal-scanner", "Node / Kubelet", nodejournalscanner.NewNodeJournalScanner())
+	monitorTestRegistry.AddMonitorTestOrDie("image-pull-latency", "Node / Kubelet", imagepulllatency.NewImagePullLatency())
+	monitorTestRegistry.AddMonitorTestOrDie("node-clock-skew", "Node / Kubelet", clockskew.NewClockSkew())
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-node-invariants", "Node / Kubelet", legacynodemonitortests.NewLegacyTests())
 	monitorTestRegistry.AddMonitorTestOrDie("node-state-analyzer", "Node / Kubelet", nodestateanalyzer.NewAnalyzer())
 	monitorTestRegistry.AddMonitorTestOrDie("pod-lifecycle", "Node / Kubelet", watchpods.NewPodWatcher())
 	monitorTestRegistry.AddMonitorTestOrDie("node-lifecycle", "Node / Kubelet", watchnodes.NewNodeWatcher())
+	monitorTestRegistry.AddMonitorTestOrDie("resource-consumption", "Node / Kubelet", resourceconsumption.NewResourceConsumption())
+	monitorTestRegistry.AddMonitorTestOrDie("termination-grace-analyzer", "Node / Kubelet", terminationgraceanalyzer.NewTerminationGraceAnalyzer())
 
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-storage-invariants", "Storage", legacystoragemonitortests.NewLegacyTests())
+	monitorTestRegistry.AddMonitorTestOrDie("volume-operation-latency", "Storage", volumeoperationlatency.NewVolumeOperationLatency())
+
+	monitorTestRegistry.AddMonitorTestOrDie("olm-health", "OLM", olmhealth.NewOLMHealth())
+
+	monitorTestRegistry.AddMonitorTestOrDie("machine-config-rollout-analyzer", "Machine Config Operator", rolloutanalyzer.NewRolloutAnalyzer())
 
 	monitorTestRegistry.AddMonitorTestOrDie("legacy-test-framework-invariants", "Test Framework", legacytestframeworkmonitortests.NewLegacyTests(info))
 	monitorTestRegistry.AddMonitorTestOrDie("timeline-serializer", "Test Framework", timelineserializer.NewTimelineSerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("interval-serializer", "Test Framework", intervalserializer.NewIntervalSerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("tracked-resources-serializer", "Test Framework", trackedresourcesserializer.NewTrackedResourcesSerializer())
-	monitorTestRegistry.AddMonitorTestOrDie("cluster-info-serializer", "Test Framework", clusterinfoserializer.NewClusterInfoSerializer())
+	clusterInfoSerializer := clusterinfoserializer.NewClusterInfoSerializer()
+	monitorTestRegistry.AddMonitorTestOrDie("cluster-info-serializer", "Test Framework", clusterInfoSerializer)
 	monitorTestRegistry.AddMonitorTestOrDie("additional-events-collector", "Test Framework", additionaleventscollector.NewIntervalSerializer())
 	monitorTestRegistry.AddMonitorTestOrDie("known-image-checker", "Test Framework", knownimagechecker.NewEnsureValidImages())
 	monitorTestRegistry.AddMonitorTestOrDie("e2e-test-analyzer", "Test Framework", e2etestanalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("load-generator-analyzer", "Test Framework", loadgeneratoranalyzer.NewAnalyzer())
+	monitorTestRegistry.AddMonitorTestOrDie("runner-resource-usage", "Test Framework", runnerresourceusage.NewRunnerResourceUsage())
+	monitorTestRegistry.AddMonitorTestOrDie("resource-leaks", "Test Framework", resourceleaks.NewResourceLeaks())
 	monitorTestRegistry.AddMonitorTestOrDie("event-collector", "Test Framework", watchevents.NewEventWatcher())
 	monitorTestRegistry.AddMonitorTestOrDie("clusteroperator-collector", "Test Framework", watchclusteroperators.NewOperatorWatcher())
+	monitorTestRegistry.AddMonitorTestOrDie("custom-resource-collector", "Test Framework", watchcustomresources.NewCustomResourceWatcher(info))
+	monitorTestRegistry.AddMonitorTestOrDie("metric-rules", "Test Framework", metricrules.NewMetricRules(info))
+	monitorTestRegistry.AddMonitorTestOrDie("injected-faults", "Test Framework", injectedfaults.NewInjectedFaults(info))
+	monitorTestRegistry.AddMonitorTestOrDie("external-contributions", "Test Framework", externalcontributions.NewExternalContributions(info))
 
 	monitorTestRegistry.AddMonitorTestOrDie("azure-metrics-collector", "Test Framework", azuremetrics.NewAzureMetricsCollector())
+	monitorTestRegistry.AddMonitorTestOrDie("cloud-throttling", "Cloud Compute", cloudthrottling.NewCloudThrottling())
 	monitorTestRegistry.AddMonitorTestOrDie("watch-request-counts-collector", "Test Framework", watchrequestcountscollector.NewWatchRequestCountSerializer())
 
+	monitorTestRegistry.AddRegistryOutputOrDie("interval-timeline", "Test Framework", intervaltimeline.NewTimelineOutput())
+	// outputs only CI reads are left out of local runs.
+	if !info.LocalDeveloperMode {
+		monitorTestRegistry.AddRegistryOutputOrDie("risk-analysis-input", "Test Framework", riskanalysisinput.NewRiskAnalysisInput(clusterInfoSerializer))
+		if info.BigQueryExport {
+			monitorTestRegistry.AddRegistryOutputOrDie("bigquery-export", "Test Framework", bigqueryexport.NewBigQueryExport(clusterInfoSerializer))
+		}
+		if info.PRSummaryIntervals > 0 {
+			monitorTestRegistry.AddRegistryOutputOrDie("pr-summary", "Test Framework", prsummary.NewPRSummary(info.PRSummaryIntervals))
+		}
+	}
+
 	return monitorTestRegistry
 }
@@ -0,0 +1,190 @@
+package artifactstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+)
+
+const (
+	gcsDefaultEndpoint = "https://storage.googleapis.com"
+	// gcsChunkAlignment is the multiple every chunk of a resumable upload but the last must be.
+	gcsChunkAlignment = 256 * 1024
+)
+
+// gcsStorage uploads to a GCS bucket with the JSON API.  Artifacts larger than the part size use a resumable upload,
+// one part at a time.
+type gcsStorage struct {
+	bucket   string
+	prefix   string
+	partSize int64
+	endpoint string
+	// token returns the OAuth access token requests are authorized with.
+	token func(ctx context.Context) (string, error)
+
+	client *http.Client
+}
+
+// newGCSStorageFromEnv authorizes with GOOGLE_OAUTH_ACCESS_TOKEN if it is set, and with the service account of the
+// instance otherwise.  STORAGE_EMULATOR_HOST replaces the GCS endpoint, for emulators.
+func newGCSStorageFromEnv(bucket, prefix string, partSize int64, getenv func(string) string) *gcsStorage {
+	s := &gcsStorage{
+		bucket: bucket,
+		prefix: prefix,
+		// rounding down keeps the part size at least MinPartSize, which is aligned.
+		partSize: partSize - partSize%gcsChunkAlignment,
+		endpoint: gcsDefaultEndpoint,
+		token:    metadataToken,
+		client:   &http.Client{Timeout: 10 * time.Minute},
+	}
+	if token := getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); len(token) > 0 {
+		s.token = func(context.Context) (string, error) {
+			return token, nil
+		}
+	}
+	if emulator := getenv("STORAGE_EMULATOR_HOST"); len(emulator) > 0 {
+		s.endpoint = strings.TrimSuffix(emulator, "/")
+		if !strings.Contains(s.endpoint, "://") {
+			s.endpoint = "http://" + s.endpoint
+		}
+	}
+	return s
+}
+
+// metadataToken reads the access token of the service account of the instance.
+func metadataToken(ctx context.Context) (string, error) {
+	content, err := metadata.Get("instance/service-accounts/default/token")
+	if err != nil {
+		return "", fmt.Errorf("unable to read an access token from the instance metadata, set GOOGLE_OAUTH_ACCESS_TOKEN instead: %w", err)
+	}
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.Unmarshal([]byte(content), &token); err != nil {
+		return "", fmt.Errorf("unable to read an access token from the instance metadata: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+func (s *gcsStorage) String() string {
+	return fmt.Sprintf("gs://%s/%s", s.bucket, s.prefix)
+}
+
+func (s *gcsStorage) Put(ctx context.Context, name, localPath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	token, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	object := objectName(s.prefix, name)
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if len(contentType) == 0 {
+		contentType = "application/octet-stream"
+	}
+	uploadType := "media"
+	if info.Size() > s.partSize {
+		uploadType = "resumable"
+	}
+	query := url.Values{"uploadType": {uploadType}, "name": {object}}
+	target := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucket), query.Encode())
+
+	if uploadType == "media" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, io.NewSectionReader(file, 0, info.Size()))
+		if err != nil {
+			return err
+		}
+		req.ContentLength = info.Size()
+		req.Header.Set("Content-Type", contentType)
+		_, err = s.do(req, token, http.StatusOK)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, http.NoBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Upload-Content-Type", contentType)
+	req.Header.Set("X-Upload-Content-Length", fmt.Sprintf("%d", info.Size()))
+	resp, err := s.do(req, token, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	session := resp.Header.Get("Location")
+	if len(session) == 0 {
+		return fmt.Errorf("unable to start the resumable upload of %q: no session in the response", object)
+	}
+	return s.putChunks(ctx, session, token, file, info.Size())
+}
+
+// putChunks uploads the file to a resumable upload session, a part at a time.  Every part but the last is
+// acknowledged with 308 Resume Incomplete.
+func (s *gcsStorage) putChunks(ctx context.Context, session, token string, file io.ReaderAt, size int64) error {
+	for offset := int64(0); offset < size; offset += s.partSize {
+		partSize := s.partSize
+		if offset+partSize > size {
+			partSize = size - offset
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, session, io.NewSectionReader(file, offset, partSize))
+		if err != nil {
+			return err
+		}
+		req.ContentLength = partSize
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+partSize-1, size))
+		expected := []int{http.StatusPermanentRedirect}
+		if offset+partSize == size {
+			expected = []int{http.StatusOK, http.StatusCreated}
+		}
+		if _, err := s.do(req, token, expected...); err != nil {
+			s.cancelSession(session, token)
+			return fmt.Errorf("unable to upload bytes %d-%d: %w", offset, offset+partSize-1, err)
+		}
+	}
+	return nil
+}
+
+// cancelSession ends a failed resumable upload.  It is best effort, and runs even after the context is done.
+func (s *gcsStorage) cancelSession(session, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, session, http.NoBody)
+	if err != nil {
+		return
+	}
+	_, _ = s.do(req, token, 499)
+}
+
+// do sends the authorized request and returns the response if its status is one of the expected ones.
+func (s *gcsStorage) do(req *http.Request, token string, expectedStatus ...int) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(resp.Body)
+	for _, expected := range expectedStatus {
+		if resp.StatusCode == expected {
+			return resp, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, content)
+}
@@ -0,0 +1,95 @@
+package artifactstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeGCS struct {
+	lock     sync.Mutex
+	url      string
+	requests []string
+	objects  map[string]string
+}
+
+func (f *fakeGCS) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if req.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	query := req.URL.Query()
+	switch {
+	case req.Method == http.MethodPost && query.Get("uploadType") == "media":
+		f.requests = append(f.requests, "media "+query.Get("name"))
+		f.objects[query.Get("name")] = string(body)
+	case req.Method == http.MethodPost && query.Get("uploadType") == "resumable":
+		f.requests = append(f.requests, "resumable "+query.Get("name"))
+		w.Header().Set("Location", f.url+"/session?name="+query.Get("name"))
+	case req.Method == http.MethodPut && req.URL.Path == "/session":
+		contentRange := req.Header.Get("Content-Range")
+		f.requests = append(f.requests, contentRange)
+		f.objects[query.Get("name")] += string(body)
+		if !strings.HasSuffix(contentRange, fmt.Sprintf("-%d/%d", len(f.objects[query.Get("name")])-1, len(f.objects[query.Get("name")]))) {
+			w.WriteHeader(http.StatusPermanentRedirect)
+		}
+	default:
+		http.Error(w, "unexpected", http.StatusBadRequest)
+	}
+}
+
+func TestGCSStorage(t *testing.T) {
+	fake := &fakeGCS{objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	fake.url = server.URL
+
+	storage := newGCSStorageFromEnv("bucket", "jobs/1", MinPartSize, func(name string) string {
+		return map[string]string{
+			"GOOGLE_OAUTH_ACCESS_TOKEN": "token",
+			"STORAGE_EMULATOR_HOST":     server.URL,
+		}[name]
+	})
+	storage.client = server.Client()
+	// parts smaller than GCS allows keep the test small.
+	storage.partSize = 4
+
+	dir := t.TempDir()
+	small, large := filepath.Join(dir, "small"), filepath.Join(dir, "large")
+	if err := os.WriteFile(small, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(large, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put(context.Background(), "small.json", small); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put(context.Background(), "events/large.json", large); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRequests := []string{
+		"media jobs/1/small.json",
+		"resumable jobs/1/events/large.json",
+		"bytes 0-3/10",
+		"bytes 4-7/10",
+		"bytes 8-9/10",
+	}
+	if strings.Join(fake.requests, "\n") != strings.Join(expectedRequests, "\n") {
+		t.Errorf("expected requests\n%s\ngot\n%s", strings.Join(expectedRequests, "\n"), strings.Join(fake.requests, "\n"))
+	}
+	if fake.objects["jobs/1/small.json"] != "abc" || fake.objects["jobs/1/events/large.json"] != "0123456789" {
+		t.Errorf("unexpected objects %v", fake.objects)
+	}
+}
@@ -0,0 +1,306 @@
+package artifactstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Storage uploads to an S3 bucket, or to any store with an S3 compatible API.  Requests are signed with AWS
+// signature version 4.  Artifacts larger than the part size use a multipart upload.
+type s3Storage struct {
+	bucket   string
+	prefix   string
+	partSize int64
+	region   string
+	// endpoint is set for S3 compatible stores, which are addressed path style.  Otherwise buckets are addressed
+	// virtual host style on the regional AWS endpoint.
+	endpoint *url.URL
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+
+	client *http.Client
+	now    func() time.Time
+}
+
+func newS3StorageFromEnv(bucket, prefix string, partSize int64, getenv func(string) string) (*s3Storage, error) {
+	s := &s3Storage{
+		bucket:          bucket,
+		prefix:          prefix,
+		partSize:        partSize,
+		region:          getenv("AWS_REGION"),
+		accessKeyID:     getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    getenv("AWS_SESSION_TOKEN"),
+		client:          &http.Client{Timeout: 10 * time.Minute},
+		now:             time.Now,
+	}
+	if len(s.region) == 0 {
+		s.region = getenv("AWS_DEFAULT_REGION")
+	}
+	if len(s.region) == 0 {
+		s.region = "us-east-1"
+	}
+	if len(s.accessKeyID) == 0 || len(s.secretAccessKey) == 0 {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to store artifacts in s3://%s", bucket)
+	}
+	if endpoint := getenv("AWS_ENDPOINT_URL"); len(endpoint) > 0 {
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return nil, fmt.Errorf("AWS_ENDPOINT_URL must be an http or https URL, got %q", endpoint)
+		}
+		s.endpoint = parsed
+	}
+	return s, nil
+}
+
+func (s *s3Storage) String() string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, s.prefix)
+}
+
+func (s *s3Storage) Put(ctx context.Context, name, localPath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	key := objectName(s.prefix, name)
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if info.Size() <= s.partSize {
+		_, err := s.do(ctx, http.MethodPut, key, nil, io.NewSectionReader(file, 0, info.Size()), info.Size(), contentType)
+		return err
+	}
+	return s.putMultipart(ctx, key, file, info.Size(), contentType)
+}
+
+type initiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (s *s3Storage) putMultipart(ctx context.Context, key string, file io.ReaderAt, size int64, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, 0, contentType)
+	if err != nil {
+		return err
+	}
+	initiated := &initiateMultipartUploadResult{}
+	if err := xml.Unmarshal(resp, initiated); err != nil || len(initiated.UploadID) == 0 {
+		return fmt.Errorf("unable to start the multipart upload of %q: %v", key, err)
+	}
+	uploadID := initiated.UploadID
+
+	complete := completeMultipartUpload{}
+	for offset, partNumber := int64(0), 1; offset < size; offset, partNumber = offset+s.partSize, partNumber+1 {
+		partSize := s.partSize
+		if offset+partSize > size {
+			partSize = size - offset
+		}
+		query := url.Values{"partNumber": {fmt.Sprintf("%d", partNumber)}, "uploadId": {uploadID}}
+		etag, err := s.putPart(ctx, key, query, io.NewSectionReader(file, offset, partSize), partSize)
+		if err != nil {
+			s.abortMultipart(key, uploadID)
+			return fmt.Errorf("unable to upload part %d of %q: %w", partNumber, key, err)
+		}
+		complete.Parts = append(complete.Parts, completedPart{PartNumber: partNumber, ETag: etag})
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+	if _, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, bytes.NewReader(body), int64(len(body)), "application/xml"); err != nil {
+		s.abortMultipart(key, uploadID)
+		return fmt.Errorf("unable to complete the multipart upload of %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *s3Storage) putPart(ctx context.Context, key string, query url.Values, body io.Reader, size int64) (string, error) {
+	req, err := s.newRequest(ctx, http.MethodPut, key, query, body, size, "")
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", resp.Status, content)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// abortMultipart frees the parts of a failed upload, which would otherwise be billed until a lifecycle rule removes
+// them.  It is best effort, and runs even after the context is done.
+func (s *s3Storage) abortMultipart(key, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, _ = s.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, 0, "")
+}
+
+// do sends a signed request and returns the body of a successful response.
+func (s *s3Storage) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, contentType string) ([]byte, error) {
+	req, err := s.newRequest(ctx, method, key, query, body, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: %s: %s", method, req.URL.Path, resp.Status, content)
+	}
+	return content, nil
+}
+
+func (s *s3Storage) newRequest(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, contentType string) (*http.Request, error) {
+	target := &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.region), Path: "/" + key}
+	if s.endpoint != nil {
+		target = &url.URL{Scheme: s.endpoint.Scheme, Host: s.endpoint.Host, Path: strings.TrimSuffix(s.endpoint.Path, "/") + "/" + s.bucket + "/" + key}
+	}
+	// the path is sent exactly as it is signed.
+	target.RawPath = uriEncodePath(target.Path)
+	target.RawQuery = canonicalQuery(query)
+
+	if body == nil {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req)
+	return req, nil
+}
+
+// sign adds AWS signature version 4 headers.  The payload is not signed, which S3 allows for requests over TLS.
+func (s *s3Storage) sign(req *http.Request) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	if len(s.sessionToken) > 0 {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if len(s.sessionToken) > 0 {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	canonicalHeaders := &strings.Builder{}
+	for _, header := range signedHeaders {
+		value := req.Header.Get(header)
+		if header == "host" {
+			value = req.URL.Host
+		}
+		fmt.Fprintf(canonicalHeaders, "%s:%s\n", header, strings.TrimSpace(value))
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		unsignedPayload,
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, s.region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(signingKey(s.secretAccessKey, date, s.region, "s3"), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+func signingKey(secretAccessKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// canonicalQuery sorts the query by key and escapes it the way signature version 4 requires.
+func canonicalQuery(query url.Values) string {
+	keys := []string{}
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := []string{}
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncodePath escapes every segment of the path with uriEncode.
+func uriEncodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = uriEncode(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEncode escapes everything but the unreserved characters of RFC 3986.
+func uriEncode(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func hmacSHA256(key []byte, content string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,107 @@
+package artifactstorage
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSigningKey(t *testing.T) {
+	// the example from the AWS signature version 4 documentation.
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	expected := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+	if actual := hex.EncodeToString(key); actual != expected {
+		t.Errorf("expected %s, got %s", expected, actual)
+	}
+}
+
+type fakeS3 struct {
+	lock     sync.Mutex
+	requests []string
+	parts    map[string]string
+	objects  map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests = append(f.requests, fmt.Sprintf("%s %s?%s", req.Method, req.URL.EscapedPath(), req.URL.RawQuery))
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/20240101/us-west-2/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+		http.Error(w, "unsigned", http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	query := req.URL.Query()
+	switch {
+	case req.Method == http.MethodPost && query.Has("uploads"):
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case req.Method == http.MethodPut && query.Has("partNumber"):
+		f.parts[query.Get("partNumber")] = string(body)
+		w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+	case req.Method == http.MethodPost && query.Has("uploadId"):
+		f.objects[req.URL.Path] = f.parts["1"] + f.parts["2"] + f.parts["3"]
+	case req.Method == http.MethodPut:
+		f.objects[req.URL.Path] = string(body)
+	default:
+		http.Error(w, "unexpected", http.StatusBadRequest)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := &fakeS3{parts: map[string]string{}, objects: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	endpoint, _ := url.Parse(server.URL)
+	storage := &s3Storage{
+		bucket:          "bucket",
+		prefix:          "jobs/1",
+		partSize:        4,
+		region:          "us-west-2",
+		endpoint:        endpoint,
+		accessKeyID:     "key",
+		secretAccessKey: "secret",
+		client:          server.Client(),
+		now:             func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+
+	dir := t.TempDir()
+	small, large := filepath.Join(dir, "small"), filepath.Join(dir, "large")
+	if err := os.WriteFile(small, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(large, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put(context.Background(), "cluster data.json", small); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put(context.Background(), "events/large.json", large); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRequests := []string{
+		"PUT /bucket/jobs/1/cluster%20data.json?",
+		"POST /bucket/jobs/1/events/large.json?uploads=",
+		"PUT /bucket/jobs/1/events/large.json?partNumber=1&uploadId=upload-1",
+		"PUT /bucket/jobs/1/events/large.json?partNumber=2&uploadId=upload-1",
+		"PUT /bucket/jobs/1/events/large.json?partNumber=3&uploadId=upload-1",
+		"POST /bucket/jobs/1/events/large.json?uploadId=upload-1",
+	}
+	if strings.Join(fake.requests, "\n") != strings.Join(expectedRequests, "\n") {
+		t.Errorf("expected requests\n%s\ngot\n%s", strings.Join(expectedRequests, "\n"), strings.Join(fake.requests, "\n"))
+	}
+	if fake.objects["/bucket/jobs/1/cluster data.json"] != "abc" || fake.objects["/bucket/jobs/1/events/large.json"] != "0123456789" {
+		t.Errorf("unexpected objects %v", fake.objects)
+	}
+}
@@ -0,0 +1,208 @@
+package artifactstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// URLEnvVar is the default destination of the artifacts, one of file:///path/to/dir, s3://bucket/prefix, or
+	// gs://bucket/prefix.
+	URLEnvVar = "OPENSHIFT_TESTS_ARTIFACT_STORAGE"
+
+	// DefaultPartSize is the size of the parts large artifacts are uploaded in.
+	DefaultPartSize = 16 * 1024 * 1024
+	// MinPartSize is the smallest part S3 accepts, and a multiple of the chunks GCS requires.
+	MinPartSize = 5 * 1024 * 1024
+	// DefaultRetries is how many times the upload of an artifact is retried before it is given up on.
+	DefaultRetries = 3
+
+	defaultRetryDelay = 2 * time.Second
+)
+
+// Storage is where the artifacts the monitor wrote to its local storage directory are shipped when the monitor
+// finishes, for runs outside of CI, where nothing collects the storage directory.
+type Storage interface {
+	// Put stores the local file under name, a slash separated path relative to the root of the storage.  Putting the
+	// same name again replaces the artifact.
+	Put(ctx context.Context, name, localPath string) error
+	// String describes where the artifacts go, for messages.
+	String() string
+}
+
+// Options tune how artifacts are uploaded.
+type Options struct {
+	// PartSize is the size of the parts artifacts larger than it are uploaded in.  Zero uses DefaultPartSize.
+	PartSize int64
+	// Retries is how many times a failed upload is retried.  Zero uses DefaultRetries, negative never retries.
+	Retries int
+}
+
+func (o Options) withDefaults() (Options, error) {
+	if o.PartSize == 0 {
+		o.PartSize = DefaultPartSize
+	}
+	if o.PartSize < MinPartSize {
+		return Options{}, fmt.Errorf("part size must be at least %d bytes, got %d", MinPartSize, o.PartSize)
+	}
+	switch {
+	case o.Retries == 0:
+		o.Retries = DefaultRetries
+	case o.Retries < 0:
+		o.Retries = 0
+	}
+	return o, nil
+}
+
+// New returns the storage for the URL.  Local directories are file URLs or plain paths, S3 buckets are
+// s3://bucket/prefix, and GCS buckets are gs://bucket/prefix.  Credentials for buckets are read from the standard
+// environment variables of each cloud.
+func New(rawURL string, options Options) (Storage, error) {
+	options, err := options.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	destination, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact storage %q: %w", rawURL, err)
+	}
+
+	var storage Storage
+	switch destination.Scheme {
+	case "", "file":
+		dir := destination.Path
+		if len(destination.Scheme) == 0 {
+			dir = rawURL
+		}
+		if len(dir) == 0 {
+			return nil, fmt.Errorf("invalid artifact storage %q: missing directory", rawURL)
+		}
+		storage = NewLocalDirStorage(dir)
+	case "s3":
+		if len(destination.Host) == 0 {
+			return nil, fmt.Errorf("invalid artifact storage %q: missing bucket", rawURL)
+		}
+		storage, err = newS3StorageFromEnv(destination.Host, strings.Trim(destination.Path, "/"), options.PartSize, os.Getenv)
+		if err != nil {
+			return nil, err
+		}
+	case "gs":
+		if len(destination.Host) == 0 {
+			return nil, fmt.Errorf("invalid artifact storage %q: missing bucket", rawURL)
+		}
+		storage = newGCSStorageFromEnv(destination.Host, strings.Trim(destination.Path, "/"), options.PartSize, os.Getenv)
+	default:
+		return nil, fmt.Errorf("invalid artifact storage %q: scheme must be file, s3, or gs", rawURL)
+	}
+	return &retryingStorage{delegate: storage, retries: options.Retries, delay: defaultRetryDelay}, nil
+}
+
+// Upload puts every file under the storage directory, named by its path relative to the directory.  Every file is
+// attempted, and the error reports the ones that could not be stored.
+func Upload(ctx context.Context, storage Storage, storageDir string) error {
+	errs := []error{}
+	err := filepath.WalkDir(storageDir, func(localPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(storageDir, localPath)
+		if err != nil {
+			return err
+		}
+		if err := storage.Put(ctx, filepath.ToSlash(relativePath), localPath); err != nil {
+			errs = append(errs, err)
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+type retryingStorage struct {
+	delegate Storage
+	retries  int
+	// delay is how long the first retry waits, doubled for every retry after.
+	delay time.Duration
+}
+
+func (s *retryingStorage) Put(ctx context.Context, name, localPath string) error {
+	delay := s.delay
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = s.delegate.Put(ctx, name, localPath); err == nil {
+			return nil
+		}
+		if attempt >= s.retries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("unable to store %q in %v: %w", name, s.delegate, err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return fmt.Errorf("unable to store %q in %v after %d attempts: %w", name, s.delegate, s.retries+1, err)
+}
+
+func (s *retryingStorage) String() string {
+	return s.delegate.String()
+}
+
+type localDirStorage struct {
+	dir string
+}
+
+// NewLocalDirStorage copies artifacts to a directory, for instance a mounted volume.
+func NewLocalDirStorage(dir string) Storage {
+	return &localDirStorage{dir: dir}
+}
+
+func (s *localDirStorage) Put(ctx context.Context, name, localPath string) error {
+	destination := filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+name)))
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return err
+	}
+	source, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	target, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(target, source); err != nil {
+		target.Close()
+		return err
+	}
+	return target.Close()
+}
+
+func (s *localDirStorage) String() string {
+	return s.dir
+}
+
+// objectName joins the prefix of a bucket and the name of an artifact.
+func objectName(prefix, name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if len(prefix) == 0 {
+		return name
+	}
+	return prefix + "/" + name
+}
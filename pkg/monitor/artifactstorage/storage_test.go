@@ -0,0 +1,105 @@
+package artifactstorage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		options     Options
+		expected    string
+		expectedErr string
+	}{
+		{name: "plain path", url: "/tmp/artifacts", expected: "/tmp/artifacts"},
+		{name: "file URL", url: "file:///tmp/artifacts", expected: "/tmp/artifacts"},
+		{name: "gcs", url: "gs://bucket/jobs/1", expected: "gs://bucket/jobs/1"},
+		{name: "missing bucket", url: "gs:///jobs", expectedErr: "missing bucket"},
+		{name: "unknown scheme", url: "ftp://host/dir", expectedErr: "scheme must be file, s3, or gs"},
+		{name: "small parts", url: "/tmp/artifacts", options: Options{PartSize: 1024}, expectedErr: "part size must be at least"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := New(tt.url, tt.options)
+			switch {
+			case len(tt.expectedErr) > 0:
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case storage.String() != tt.expected:
+				t.Errorf("expected %q, got %q", tt.expected, storage.String())
+			}
+		})
+	}
+}
+
+func TestUploadToLocalDir(t *testing.T) {
+	storageDir := t.TempDir()
+	files := map[string]string{
+		"e2e-events.json":           "events",
+		"monitor-test/cluster.json": "cluster",
+	}
+	for name, content := range files {
+		path := filepath.Join(storageDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	destination := t.TempDir()
+	if err := Upload(context.Background(), NewLocalDirStorage(destination), storageDir); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		actual, err := os.ReadFile(filepath.Join(destination, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != content {
+			t.Errorf("expected %q in %s, got %q", content, name, actual)
+		}
+	}
+}
+
+type flakyStorage struct {
+	failures int
+	puts     int
+}
+
+func (s *flakyStorage) Put(ctx context.Context, name, localPath string) error {
+	s.puts++
+	if s.puts <= s.failures {
+		return fmt.Errorf("attempt %d failed", s.puts)
+	}
+	return nil
+}
+
+func (s *flakyStorage) String() string {
+	return "flaky"
+}
+
+func TestRetryingStorage(t *testing.T) {
+	flaky := &flakyStorage{failures: 2}
+	storage := &retryingStorage{delegate: flaky, retries: 2}
+	if err := storage.Put(context.Background(), "name", "path"); err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+
+	flaky = &flakyStorage{failures: 3}
+	storage = &retryingStorage{delegate: flaky, retries: 2}
+	err := storage.Put(context.Background(), "name", "path")
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts: attempt 3 failed") {
+		t.Fatalf("expected the upload to be given up on after 3 attempts, got %v", err)
+	}
+}
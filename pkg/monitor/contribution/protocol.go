@@ -0,0 +1,239 @@
+// Package contribution is how test binaries outside of openshift-tests, like the openshift-tests-extension binaries of
+// components, add intervals and junits to the results of the monitor, so their tests are part of the shared timeline.
+//
+// A contributor appends newline delimited JSON records to a file named <contributor>.ndjson in the contribution
+// directory, which is the external-contributions directory of the monitor storage and is passed to test processes in
+// $OPENSHIFT_TESTS_MONITOR_CONTRIBUTION_DIR.  Every record is one line, holding either an interval in the format of the
+// interval files or a junit:
+//
+//	{"version":1,"interval":{"level":"Error","source":"MyComponent","locator":{...},"message":{...},"from":"...","to":"..."}}
+//	{"version":1,"junit":{"name":"[sig-foo] my component should work","failure":"it did not"}}
+//
+// The monitor reads the files once its monitor tests collect data.  A line that is not terminated by a newline is
+// still being written and is ignored.
+package contribution
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// Version is the version of the records this package reads and writes.
+	Version = 1
+	// DirName is the directory contributions are written to under the storage directory of the monitor.
+	DirName = "external-contributions"
+	// DirEnvVar holds the contribution directory in the environment of test processes.
+	DirEnvVar = "OPENSHIFT_TESTS_MONITOR_CONTRIBUTION_DIR"
+
+	fileSuffix = ".ndjson"
+)
+
+var validContributor = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Record is one line of a contribution file.  Exactly one of Interval and JUnit is set.
+type Record struct {
+	// Version is the version of the protocol the record was written with.  Records of other versions are reported
+	// and skipped.
+	Version int `json:"version"`
+	// Interval is an interval in the format of the interval files.
+	Interval json.RawMessage `json:"interval,omitempty"`
+	JUnit    *JUnit          `json:"junit,omitempty"`
+}
+
+// JUnit is the result of one test.  A flake is a failing and a passing record of the same name.
+type JUnit struct {
+	Name string `json:"name"`
+	// Duration is how long the test ran, in seconds.
+	Duration float64 `json:"duration,omitempty"`
+	// Failure is set to why the test failed.
+	Failure string `json:"failure,omitempty"`
+	// Skipped is set to why the test was skipped.
+	Skipped   string `json:"skipped,omitempty"`
+	SystemOut string `json:"systemOut,omitempty"`
+}
+
+func (j JUnit) toJUnitTestCase() *junitapi.JUnitTestCase {
+	ret := &junitapi.JUnitTestCase{
+		Name:      j.Name,
+		Duration:  j.Duration,
+		SystemOut: j.SystemOut,
+	}
+	switch {
+	case len(j.Failure) > 0:
+		ret.FailureOutput = &junitapi.FailureOutput{Output: j.Failure}
+	case len(j.Skipped) > 0:
+		ret.SkipMessage = &junitapi.SkipMessage{Message: j.Skipped}
+	}
+	return ret
+}
+
+// Writer appends the records of one contributor.  It is safe to use from several goroutines.
+type Writer struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// NewWriter opens the contribution file of contributor in dir.  Records written before, for instance by an earlier
+// process of the same contributor, are kept.
+func NewWriter(dir, contributor string) (*Writer, error) {
+	if !validContributor.MatchString(contributor) {
+		return nil, fmt.Errorf("contributor %q may only contain letters, digits, '_', '.', and '-'", contributor)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, contributor+fileSuffix), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{file: file}, nil
+}
+
+// NewWriterFromEnv opens the contribution file of contributor in the directory of DirEnvVar.  It returns nil without
+// an error when the variable is not set, because the process does not run under the monitor.
+func NewWriterFromEnv(contributor string) (*Writer, error) {
+	dir := os.Getenv(DirEnvVar)
+	if len(dir) == 0 {
+		return nil, nil
+	}
+	return NewWriter(dir, contributor)
+}
+
+func (w *Writer) WriteInterval(interval monitorapi.Interval) error {
+	content, err := monitorserialization.IntervalToOneLineJSON(interval)
+	if err != nil {
+		return err
+	}
+	return w.write(Record{Version: Version, Interval: bytes.TrimSpace(content)})
+}
+
+func (w *Writer) WriteJUnit(junit JUnit) error {
+	return w.write(Record{Version: Version, JUnit: &junit})
+}
+
+// write appends the record as a single write, so a reader sees whole lines.
+func (w *Writer) write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err = w.file.Write(append(line, '\n'))
+	return err
+}
+
+func (w *Writer) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.file.Close()
+}
+
+// Contributions are the records read from a contribution directory.
+type Contributions struct {
+	// Intervals are annotated with the contributor they came from.
+	Intervals monitorapi.Intervals
+	JUnits    []*junitapi.JUnitTestCase
+	// Problems are the records that could not be read, by contributor.
+	Problems map[string][]string
+}
+
+// ReadDir reads the contribution file of every contributor in dir, in name order.  A directory that does not exist
+// has no contributions.  Records that cannot be read are reported in Problems rather than failing the others.
+func ReadDir(dir string) (*Contributions, error) {
+	ret := &Contributions{Problems: map[string][]string{}}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return ret, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), fileSuffix) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		ret.read(strings.TrimSuffix(name, fileSuffix), content)
+	}
+	return ret, nil
+}
+
+func (c *Contributions) read(contributor string, content []byte) {
+	// the last line is still being written unless the content ends with a newline.
+	if i := bytes.LastIndexByte(content, '\n'); i >= 0 {
+		content = content[:i+1]
+	} else {
+		content = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := c.readRecord(contributor, line); err != nil {
+			c.Problems[contributor] = append(c.Problems[contributor], fmt.Sprintf("line %d: %v", lineNumber, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		c.Problems[contributor] = append(c.Problems[contributor], err.Error())
+	}
+}
+
+func (c *Contributions) readRecord(contributor string, line []byte) error {
+	record := Record{}
+	if err := json.Unmarshal(line, &record); err != nil {
+		return err
+	}
+	if record.Version != Version {
+		return fmt.Errorf("unsupported version %d, expected %d", record.Version, Version)
+	}
+
+	switch {
+	case len(record.Interval) > 0 && record.JUnit == nil:
+		interval, err := monitorserialization.IntervalFromJSON(record.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid interval: %w", err)
+		}
+		if interval.From.IsZero() || interval.To.Before(interval.From) {
+			return fmt.Errorf("interval %q must begin and end after it begins", interval.String())
+		}
+		if interval.Message.Annotations == nil {
+			interval.Message.Annotations = map[monitorapi.AnnotationKey]string{}
+		}
+		interval.Message.Annotations[monitorapi.AnnotationContributor] = contributor
+		c.Intervals = append(c.Intervals, *interval)
+	case record.JUnit != nil && len(record.Interval) == 0:
+		if len(record.JUnit.Name) == 0 {
+			return fmt.Errorf("junits must have a name")
+		}
+		c.JUnits = append(c.JUnits, record.JUnit.toJUnitTestCase())
+	default:
+		return fmt.Errorf("records must have exactly one of interval and junit")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package contribution
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestWriteAndReadDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), DirName)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	writer, err := NewWriter(dir, "my-component")
+	if err != nil {
+		t.Fatal(err)
+	}
+	interval := monitorapi.NewInterval(monitorapi.IntervalSource("MyComponent"), monitorapi.Error).
+		Locator(monitorapi.NewLocator().NodeFromName("worker-0")).
+		Message(monitorapi.NewMessage().HumanMessage("component was down")).
+		Build(start, start.Add(time.Minute))
+	if err := writer.WriteInterval(interval); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteJUnit(JUnit{Name: "[sig-foo] my component should work", Failure: "it did not"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a broken contributor, with a record still being written.
+	broken := strings.Join([]string{
+		`{"version":1,"junit":{"name":"[sig-bar] passes"}}`,
+		`not json`,
+		`{"version":2,"junit":{"name":"[sig-bar] from the future"}}`,
+		`{"version":1}`,
+		`{"version":1,"junit":{"name":"[sig-bar] still being wri`,
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "broken.ndjson"), []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	contributions, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contributions.Intervals) != 1 {
+		t.Fatalf("expected one interval, got %v", contributions.Intervals)
+	}
+	got := contributions.Intervals[0]
+	if got.Message.HumanMessage != "component was down" || !got.From.Equal(start) || got.Message.Annotations[monitorapi.AnnotationContributor] != "my-component" {
+		t.Errorf("unexpected interval %v", got)
+	}
+
+	if len(contributions.JUnits) != 2 || contributions.JUnits[0].Name != "[sig-bar] passes" || contributions.JUnits[1].FailureOutput == nil {
+		t.Errorf("expected the junits of both contributors in name order, got %v", contributions.JUnits)
+	}
+	if len(contributions.Problems["broken"]) != 3 || len(contributions.Problems["my-component"]) != 0 {
+		t.Errorf("expected three problems of the broken contributor, got %v", contributions.Problems)
+	}
+}
+
+func TestReadMissingDir(t *testing.T) {
+	contributions, err := ReadDir(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(contributions.Intervals) != 0 || len(contributions.JUnits) != 0 {
+		t.Errorf("expected no contributions, got %v, %v", contributions, err)
+	}
+}
+
+func TestNewWriterValidatesContributor(t *testing.T) {
+	if _, err := NewWriter(t.TempDir(), "../escape"); err == nil {
+		t.Error("expected a contributor with a path to be rejected")
+	}
+}
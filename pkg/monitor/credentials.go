@@ -0,0 +1,345 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// CredentialRefresher provides new credentials when the cluster rejects the ones the monitor is using, for instance
+// because bootstrap certificates were rotated or a short-lived token expired.
+type CredentialRefresher interface {
+	RefreshCredentials(ctx context.Context) (*rest.Config, error)
+}
+
+type kubeconfigCredentialRefresher struct{}
+
+// NewKubeconfigCredentialRefresher reloads the kubeconfig the same way it was originally loaded, from KUBECONFIG or
+// the default locations.  CI rewrites the kubeconfig when it rotates credentials.
+func NewKubeconfigCredentialRefresher() CredentialRefresher {
+	return kubeconfigCredentialRefresher{}
+}
+
+func (kubeconfigCredentialRefresher) RefreshCredentials(ctx context.Context) (*rest.Config, error) {
+	cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	return cfg.ClientConfig()
+}
+
+const (
+	// minRefreshInterval keeps hundreds of concurrent clients that see the same 401 from all reloading credentials.
+	minRefreshInterval = 30 * time.Second
+	refreshTimeout     = 30 * time.Second
+)
+
+// credentialState is shared by every client built from the monitor's rest config.  Once the credentials have been
+// refreshed, every client uses the refreshed transport.  If they cannot be refreshed, the monitor keeps running and
+// everything recorded until authentication succeeds again is marked as low confidence.
+type credentialState struct {
+	refresher CredentialRefresher
+	recorder  monitorapi.RecorderWriter
+	now       func() time.Time
+
+	// refreshes makes concurrent clients that see the same 401 wait for a single refresh, which runs outside of lock
+	// so recording is never blocked on it.
+	refreshes singleflight.Group
+	// expired is read on every recorded interval, so it does not take lock.
+	expired atomic.Bool
+
+	lock            sync.Mutex
+	refreshed       http.RoundTripper
+	lastRefresh     time.Time
+	refreshFailure  error
+	expiredInterval int
+	// expiredCount is how many times the monitor lost the ability to authenticate.
+	expiredCount int
+}
+
+func newCredentialState(refresher CredentialRefresher, recorder monitorapi.RecorderWriter) *credentialState {
+	return &credentialState{
+		refresher: refresher,
+		recorder:  recorder,
+		now:       time.Now,
+	}
+}
+
+// wrapConfig returns a copy of the config whose clients refresh credentials when they are rejected.
+func (c *credentialState) wrapConfig(config *rest.Config) *rest.Config {
+	ret := rest.CopyConfig(config)
+	ret.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &refreshingRoundTripper{state: c, delegate: rt}
+	})
+	return ret
+}
+
+// lowConfidence is true while the monitor cannot authenticate.
+func (c *credentialState) lowConfidence() bool {
+	return c.expired.Load()
+}
+
+const credentialsTestName = "[sig-arch] monitor should be able to authenticate to the cluster for the whole run"
+
+// junits flakes when the monitor lost the ability to authenticate.  Intervals from that time are low confidence, so
+// other failures may be explained by missing data, but the job still has results.
+func (c *credentialState) junits() []*junitapi.JUnitTestCase {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	success := &junitapi.JUnitTestCase{Name: credentialsTestName}
+	if c.expiredCount == 0 {
+		return []*junitapi.JUnitTestCase{success}
+	}
+	failureMsg := fmt.Sprintf("monitor credentials were rejected and could not be refreshed %d times, see %s intervals for when results are low confidence",
+		c.expiredCount, monitorapi.SourceMonitorCredentials)
+	if c.refreshFailure != nil {
+		failureMsg = fmt.Sprintf("%s\nlast refresh failure: %v", failureMsg, c.refreshFailure)
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name:          credentialsTestName,
+			SystemOut:     failureMsg,
+			FailureOutput: &junitapi.FailureOutput{Output: failureMsg},
+		},
+		success,
+	}
+}
+
+func (c *credentialState) currentTransport() http.RoundTripper {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.refreshed
+}
+
+// refresh replaces the transport if nobody else has refreshed it recently.  It returns the transport to retry with,
+// or nil if there is nothing new to retry with.  Clients rejected while a refresh is running wait for its result.
+func (c *credentialState) refresh(ctx context.Context, rejected http.RoundTripper) http.RoundTripper {
+	c.lock.Lock()
+	// another client already refreshed while this request was in flight.
+	if c.refreshed != nil && c.refreshed != rejected {
+		defer c.lock.Unlock()
+		return c.refreshed
+	}
+	c.lock.Unlock()
+
+	result, _, _ := c.refreshes.Do("refresh", func() (interface{}, error) {
+		return c.refreshOnce(ctx, rejected), nil
+	})
+	transport, _ := result.(http.RoundTripper)
+	return transport
+}
+
+// refreshOnce reloads the credentials, holding lock only to read and update the state around the reload.
+func (c *credentialState) refreshOnce(ctx context.Context, rejected http.RoundTripper) http.RoundTripper {
+	c.lock.Lock()
+	if c.refreshed != nil && c.refreshed != rejected {
+		defer c.lock.Unlock()
+		return c.refreshed
+	}
+	now := c.now()
+	if !c.lastRefresh.IsZero() && now.Sub(c.lastRefresh) < minRefreshInterval {
+		defer c.lock.Unlock()
+		c.markExpiredLocked(now, c.refreshFailure)
+		return nil
+	}
+	c.lastRefresh = now
+	c.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+	config, err := c.refresher.RefreshCredentials(ctx)
+	var transport http.RoundTripper
+	if err == nil {
+		transport, err = rest.TransportFor(config)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err == nil {
+		fmt.Fprintf(os.Stderr, "Monitor credentials were rejected, refreshed them.\n")
+		c.refreshed = transport
+		c.refreshFailure = nil
+		return transport
+	}
+
+	fmt.Fprintf(os.Stderr, "Monitor credentials were rejected and could not be refreshed, results are low confidence until they work again: %v\n", err)
+	c.refreshFailure = err
+	c.markExpiredLocked(now, err)
+	return nil
+}
+
+func (c *credentialState) markExpiredLocked(now time.Time, cause error) {
+	if c.expired.Load() {
+		return
+	}
+	c.expired.Store(true)
+	c.expiredCount++
+	message := monitorapi.NewMessage().Reason(monitorapi.MonitorCredentialsExpired).
+		HumanMessage("monitor credentials were rejected by the cluster, intervals are low confidence until authentication succeeds")
+	if cause != nil {
+		message = message.Cause(cause.Error())
+	}
+	c.expiredInterval = c.recorder.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourceMonitorCredentials, monitorapi.Error).
+			Locator(monitorapi.NewLocator().KubeAPIServerWithLB("")).
+			Message(message).
+			Display().
+			Build(now, time.Time{}),
+	)
+}
+
+func (c *credentialState) markExpired(cause error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.markExpiredLocked(c.now(), cause)
+}
+
+func (c *credentialState) markAuthenticated() {
+	// every successful request lands here, so only take the lock if there is something to end.
+	if !c.expired.Load() {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.expired.Load() {
+		return
+	}
+	c.expired.Store(false)
+	c.recorder.EndInterval(c.expiredInterval, c.now())
+}
+
+// refreshingRoundTripper retries requests rejected with a 401 once, using refreshed credentials.
+type refreshingRoundTripper struct {
+	state    *credentialState
+	delegate http.RoundTripper
+}
+
+var _ http.RoundTripper = &refreshingRoundTripper{}
+
+func (rt *refreshingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := rt.state.currentTransport()
+	resp, err := rt.roundTripWith(transport, req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		rt.state.markAuthenticated()
+		return resp, nil
+	}
+
+	// requests with a body that cannot be replayed are returned as is.
+	if req.Body != nil && req.GetBody == nil {
+		rt.state.refresh(req.Context(), transport)
+		return resp, nil
+	}
+	retryTransport := rt.state.refresh(req.Context(), transport)
+	if retryTransport == nil {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	retryResp, err := rt.roundTripWith(retryTransport, retry)
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	if retryResp.StatusCode == http.StatusUnauthorized {
+		rt.state.markExpired(fmt.Errorf("refreshed credentials were also rejected"))
+		return retryResp, nil
+	}
+	rt.state.markAuthenticated()
+	return retryResp, nil
+}
+
+// roundTripWith sends the request with the original transport, or with the refreshed one.  The refreshed transport
+// adds its own credentials, so the stale ones must be removed first.
+func (rt *refreshingRoundTripper) roundTripWith(transport http.RoundTripper, req *http.Request) (*http.Response, error) {
+	if transport == nil {
+		return rt.delegate.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	return transport.RoundTrip(req)
+}
+
+func (rt *refreshingRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
+
+// confidenceRecorder marks intervals recorded while the monitor cannot authenticate as low confidence.
+type confidenceRecorder struct {
+	monitorapi.Recorder
+	credentials *credentialState
+}
+
+func newConfidenceRecorder(recorder monitorapi.Recorder, credentials *credentialState) monitorapi.Recorder {
+	return &confidenceRecorder{
+		Recorder:    recorder,
+		credentials: credentials,
+	}
+}
+
+func (r *confidenceRecorder) Record(conditions ...monitorapi.Condition) {
+	r.RecordAt(r.credentials.now().UTC(), conditions...)
+}
+
+func (r *confidenceRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if !r.credentials.lowConfidence() {
+		r.Recorder.RecordAt(t, conditions...)
+		return
+	}
+	for i := range conditions {
+		conditions[i].Message = withLowConfidence(conditions[i].Message)
+	}
+	r.Recorder.RecordAt(t, conditions...)
+}
+
+func (r *confidenceRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	if !r.credentials.lowConfidence() {
+		r.Recorder.AddIntervals(eventIntervals...)
+		return
+	}
+	marked := make([]monitorapi.Interval, 0, len(eventIntervals))
+	for _, interval := range eventIntervals {
+		interval.Message = withLowConfidence(interval.Message)
+		marked = append(marked, interval)
+	}
+	r.Recorder.AddIntervals(marked...)
+}
+
+func (r *confidenceRecorder) StartInterval(interval monitorapi.Interval) int {
+	if r.credentials.lowConfidence() {
+		interval.Message = withLowConfidence(interval.Message)
+	}
+	return r.Recorder.StartInterval(interval)
+}
+
+// withLowConfidence copies the annotations so that intervals sharing a message are not all marked.
+func withLowConfidence(message monitorapi.Message) monitorapi.Message {
+	annotations := map[monitorapi.AnnotationKey]string{}
+	for k, v := range message.Annotations {
+		annotations[k] = v
+	}
+	annotations[monitorapi.AnnotationConfidence] = monitorapi.LowConfidence
+	message.Annotations = annotations
+	return message
+}
@@ -0,0 +1,201 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type fakeCredentialRefresher struct {
+	config *rest.Config
+	err    error
+	calls  int
+}
+
+func (f *fakeCredentialRefresher) RefreshCredentials(ctx context.Context) (*rest.Config, error) {
+	f.calls++
+	return f.config, f.err
+}
+
+func newTokenServer(t *testing.T, validToken string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, config *rest.Config) int {
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(config.Host + "/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func credentialIntervals(intervals monitorapi.Intervals) monitorapi.Intervals {
+	return intervals.Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceMonitorCredentials
+	})
+}
+
+func TestCredentialRefresh(t *testing.T) {
+	server := newTokenServer(t, "fresh")
+	refresher := &fakeCredentialRefresher{config: &rest.Config{Host: server.URL, BearerToken: "fresh"}}
+	recorder := NewRecorder()
+	credentials := newCredentialState(refresher, recorder)
+	config := credentials.wrapConfig(&rest.Config{Host: server.URL, BearerToken: "stale"})
+
+	if code := get(t, config); code != http.StatusOK {
+		t.Fatalf("expected the request to be retried with refreshed credentials, got %d", code)
+	}
+	if code := get(t, config); code != http.StatusOK {
+		t.Fatalf("expected later requests to use refreshed credentials, got %d", code)
+	}
+	if refresher.calls != 1 {
+		t.Errorf("expected one refresh, got %d", refresher.calls)
+	}
+	if intervals := credentialIntervals(recorder.Intervals(time.Time{}, time.Time{})); len(intervals) != 0 {
+		t.Errorf("expected no credential intervals, got %v", intervals)
+	}
+	if junits := credentials.junits(); len(junits) != 1 || junits[0].FailureOutput != nil {
+		t.Errorf("expected a single passing junit, got %#v", junits)
+	}
+}
+
+func TestCredentialRefreshFailure(t *testing.T) {
+	server := newTokenServer(t, "fresh")
+	refresher := &fakeCredentialRefresher{err: fmt.Errorf("kubeconfig is gone")}
+	recorder := NewRecorder()
+	credentials := newCredentialState(refresher, recorder)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	credentials.now = func() time.Time { return now }
+	config := credentials.wrapConfig(&rest.Config{Host: server.URL, BearerToken: "stale"})
+	confidence := newConfidenceRecorder(recorder, credentials)
+
+	if code := get(t, config); code != http.StatusUnauthorized {
+		t.Fatalf("expected the rejection to be returned, got %d", code)
+	}
+	if !credentials.lowConfidence() {
+		t.Fatal("expected low confidence after a failed refresh")
+	}
+	confidence.AddIntervals(monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().HumanMessage("while expired")).
+		Build(now, now))
+
+	// refreshes are rate limited, so a second rejection does not reload credentials.
+	if code := get(t, config); code != http.StatusUnauthorized {
+		t.Fatalf("expected the rejection to be returned, got %d", code)
+	}
+	if refresher.calls != 1 {
+		t.Errorf("expected one refresh, got %d", refresher.calls)
+	}
+
+	now = now.Add(time.Minute)
+	refresher.err = nil
+	refresher.config = &rest.Config{Host: server.URL, BearerToken: "fresh"}
+	if code := get(t, config); code != http.StatusOK {
+		t.Fatalf("expected the request to be retried with refreshed credentials, got %d", code)
+	}
+	if credentials.lowConfidence() {
+		t.Fatal("expected confidence to recover after a successful refresh")
+	}
+	confidence.AddIntervals(monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().HumanMessage("after refresh")).
+		Build(now, now))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	expired := credentialIntervals(intervals)
+	if len(expired) != 1 {
+		t.Fatalf("expected one credential interval, got %v", expired)
+	}
+	if expired[0].Message.Reason != monitorapi.MonitorCredentialsExpired || !expired[0].To.Equal(now) {
+		t.Errorf("unexpected credential interval %#v", expired[0])
+	}
+	for _, interval := range intervals {
+		switch interval.Message.HumanMessage {
+		case "while expired":
+			if !monitorapi.IsLowConfidence(interval) {
+				t.Errorf("expected %q to be low confidence", interval.Message.HumanMessage)
+			}
+		case "after refresh":
+			if monitorapi.IsLowConfidence(interval) {
+				t.Errorf("expected %q not to be low confidence", interval.Message.HumanMessage)
+			}
+		}
+	}
+
+	junits := credentials.junits()
+	if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil {
+		t.Errorf("expected a flake, got %#v", junits)
+	}
+}
+
+type blockingCredentialRefresher struct {
+	started chan struct{}
+	release chan struct{}
+	config  *rest.Config
+}
+
+func (f *blockingCredentialRefresher) RefreshCredentials(ctx context.Context) (*rest.Config, error) {
+	close(f.started)
+	<-f.release
+	return f.config, nil
+}
+
+func TestCredentialRefreshDoesNotBlockRecording(t *testing.T) {
+	server := newTokenServer(t, "fresh")
+	refresher := &blockingCredentialRefresher{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+		config:  &rest.Config{Host: server.URL, BearerToken: "fresh"},
+	}
+	recorder := NewRecorder()
+	credentials := newCredentialState(refresher, recorder)
+	config := credentials.wrapConfig(&rest.Config{Host: server.URL, BearerToken: "stale"})
+	confidenceRecorder := newConfidenceRecorder(recorder, credentials)
+
+	codes := make(chan int, 2)
+	go func() { codes <- get(t, config) }()
+	<-refresher.started
+	// a second rejected client waits for the refresh in progress instead of starting another.
+	go func() { codes <- get(t, config) }()
+
+	recorded := make(chan struct{})
+	go func() {
+		confidenceRecorder.AddIntervals(monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Info).
+			Message(monitorapi.NewMessage().HumanMessage("during refresh")).
+			Build(time.Now(), time.Now()))
+		close(recorded)
+	}()
+	select {
+	case <-recorded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("recording blocked while credentials were refreshed")
+	}
+
+	close(refresher.release)
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Errorf("expected the requests to be retried with refreshed credentials, got %d", code)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package intervaldiff
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+const (
+	// DefaultTolerance is how far apart windows of the two runs may be, relative to the beginning of their run, and
+	// still be the same window.
+	DefaultTolerance = time.Minute
+	// DefaultMinRegression is the smallest increase of the time spent in warning or error intervals that is reported.
+	DefaultMinRegression = 10 * time.Second
+	// DefaultRegressionRatio is how many times longer than in the baseline warning or error intervals must last in the
+	// candidate to be reported.
+	DefaultRegressionRatio = 1.5
+)
+
+var intervalFileRegex = regexp.MustCompile(`^e2e-events.*\.json$`)
+
+// Options decide which differences are reported.
+type Options struct {
+	// Tolerance widens windows when they are aligned.  If zero, DefaultTolerance is used.
+	Tolerance time.Duration
+	// MinRegression and RegressionRatio are how much longer warning and error intervals must last in the candidate
+	// to be a duration regression.  If zero, DefaultMinRegression and DefaultRegressionRatio are used.
+	MinRegression   time.Duration
+	RegressionRatio float64
+}
+
+// Diff is what the candidate run has that the baseline run did not.
+type Diff struct {
+	// BaselineBeginning and CandidateBeginning are the first intervals of the runs, which the runs are aligned at.
+	BaselineBeginning  time.Time `json:"baselineBeginning"`
+	CandidateBeginning time.Time `json:"candidateBeginning"`
+
+	// NewDisruptions are the disruption windows of the candidate the baseline had no disruption of the same backend
+	// around the same time for.
+	NewDisruptions []Window `json:"newDisruptions"`
+	// NewAlerts are the firing alerts of the candidate that were not firing around the same time in the baseline.
+	NewAlerts []Window `json:"newAlerts"`
+	// DurationRegressions are the warning and error intervals both runs had that last much longer in the candidate.
+	DurationRegressions []DurationRegression `json:"durationRegressions"`
+}
+
+// Window is an interval of the candidate, placed relative to the beginning of its run.
+type Window struct {
+	// Key is what the window was aligned by, the locator of most intervals.
+	Key             string                    `json:"key"`
+	Source          monitorapi.IntervalSource `json:"source"`
+	From            time.Time                 `json:"from"`
+	OffsetSeconds   float64                   `json:"offsetSeconds"`
+	DurationSeconds float64                   `json:"durationSeconds"`
+	Message         string                    `json:"message"`
+}
+
+// DurationRegression is the time spent in the warning or error intervals of a key by both runs.
+type DurationRegression struct {
+	Key              string                    `json:"key"`
+	Source           monitorapi.IntervalSource `json:"source"`
+	BaselineSeconds  float64                   `json:"baselineSeconds"`
+	CandidateSeconds float64                   `json:"candidateSeconds"`
+}
+
+// LoadIntervals reads the intervals of a run from an e2e-events file, or from every e2e-events file under a
+// directory, so the directories of every phase of a run are compared together.
+func LoadIntervals(path string) (monitorapi.Intervals, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return monitorserialization.EventsFromFile(path)
+	}
+
+	ret := monitorapi.Intervals{}
+	// the same interval is in the files of every monitor test that wrote the final intervals.
+	seen := map[string]bool{}
+	err = filepath.WalkDir(path, func(filename string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !intervalFileRegex.MatchString(d.Name()) {
+			return err
+		}
+		intervals, err := monitorserialization.EventsFromFile(filename)
+		if err != nil {
+			return fmt.Errorf("unable to read intervals from %q: %w", filename, err)
+		}
+		for _, interval := range intervals {
+			if seen[interval.ID()] {
+				continue
+			}
+			seen[interval.ID()] = true
+			ret = append(ret, interval)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("no intervals in e2e-events files under %s", path)
+	}
+	sort.Stable(ret)
+	return ret, nil
+}
+
+// Compare aligns the runs at their first interval and returns what the candidate has that the baseline did not.
+// Windows are aligned by key and by their time relative to the beginning of their run.
+func Compare(baseline, candidate monitorapi.Intervals, options Options) *Diff {
+	if options.Tolerance == 0 {
+		options.Tolerance = DefaultTolerance
+	}
+	if options.MinRegression == 0 {
+		options.MinRegression = DefaultMinRegression
+	}
+	if options.RegressionRatio == 0 {
+		options.RegressionRatio = DefaultRegressionRatio
+	}
+
+	ret := &Diff{
+		BaselineBeginning:   beginningOf(baseline),
+		CandidateBeginning:  beginningOf(candidate),
+		DurationRegressions: []DurationRegression{},
+	}
+	ret.NewDisruptions = newWindows(baseline.Filter(isDisruption), ret.BaselineBeginning, candidate.Filter(isDisruption), ret.CandidateBeginning, options.Tolerance)
+	ret.NewAlerts = newWindows(baseline.Filter(isFiringAlert), ret.BaselineBeginning, candidate.Filter(isFiringAlert), ret.CandidateBeginning, options.Tolerance)
+
+	baselineDurations := durationsByKey(baseline.Filter(isWarningOrError))
+	candidateDurations := durationsByKey(candidate.Filter(isWarningOrError))
+	for key, candidateDuration := range candidateDurations {
+		// intervals new in the candidate are not a regression of how long they last.
+		baselineDuration, ok := baselineDurations[key]
+		if !ok {
+			continue
+		}
+		if candidateDuration-baselineDuration < options.MinRegression ||
+			float64(candidateDuration) < float64(baselineDuration)*options.RegressionRatio {
+			continue
+		}
+		ret.DurationRegressions = append(ret.DurationRegressions, DurationRegression{
+			Key:              key.key,
+			Source:           key.source,
+			BaselineSeconds:  baselineDuration.Seconds(),
+			CandidateSeconds: candidateDuration.Seconds(),
+		})
+	}
+	sort.Slice(ret.DurationRegressions, func(i, j int) bool {
+		lhs, rhs := ret.DurationRegressions[i], ret.DurationRegressions[j]
+		if lhsIncrease, rhsIncrease := lhs.CandidateSeconds-lhs.BaselineSeconds, rhs.CandidateSeconds-rhs.BaselineSeconds; lhsIncrease != rhsIncrease {
+			return lhsIncrease > rhsIncrease
+		}
+		return lhs.Key < rhs.Key
+	})
+	return ret
+}
+
+// IsEmpty is whether the candidate has nothing the baseline did not.
+func (d *Diff) IsEmpty() bool {
+	return len(d.NewDisruptions) == 0 && len(d.NewAlerts) == 0 && len(d.DurationRegressions) == 0
+}
+
+// WriteText writes the differences for a person triaging the candidate.
+func (d *Diff) WriteText(out io.Writer) {
+	fmt.Fprintf(out, "Candidate beginning at %s compared to baseline beginning at %s.\n",
+		d.CandidateBeginning.UTC().Format(time.RFC3339), d.BaselineBeginning.UTC().Format(time.RFC3339))
+	if d.IsEmpty() {
+		fmt.Fprintf(out, "\nNo new disruption, no new firing alerts, and no duration regressions.\n")
+		return
+	}
+	writeWindows(out, "New disruption windows", d.NewDisruptions)
+	writeWindows(out, "New alert firings", d.NewAlerts)
+	if len(d.DurationRegressions) > 0 {
+		fmt.Fprintf(out, "\nDuration regressions (%d):\n", len(d.DurationRegressions))
+		for _, regression := range d.DurationRegressions {
+			fmt.Fprintf(out, "  %v -> %v  %s %s\n",
+				seconds(regression.BaselineSeconds), seconds(regression.CandidateSeconds), regression.Source, regression.Key)
+		}
+	}
+}
+
+func writeWindows(out io.Writer, title string, windows []Window) {
+	if len(windows) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\n%s (%d):\n", title, len(windows))
+	for _, window := range windows {
+		fmt.Fprintf(out, "  +%v for %v  %s  %s\n", seconds(window.OffsetSeconds), seconds(window.DurationSeconds), window.Key, window.Message)
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return (time.Duration(s * float64(time.Second))).Round(time.Second)
+}
+
+func isDisruption(interval monitorapi.Interval) bool {
+	return monitorapi.IsDisruptionEvent(interval) && interval.Level == monitorapi.Error
+}
+
+func isFiringAlert(interval monitorapi.Interval) bool {
+	return interval.Source == monitorapi.SourceAlert && monitorapi.AlertFiring()(interval)
+}
+
+func isWarningOrError(interval monitorapi.Interval) bool {
+	return interval.Level >= monitorapi.Warning
+}
+
+type intervalKey struct {
+	source monitorapi.IntervalSource
+	key    string
+}
+
+// keyOf is what intervals are aligned by.  Alerts are aligned by name and namespace, because the pods they are about
+// are named differently in every run.
+func keyOf(interval monitorapi.Interval) intervalKey {
+	if interval.Source == monitorapi.SourceAlert {
+		return intervalKey{
+			source: interval.Source,
+			key: fmt.Sprintf("alert/%s namespace/%s",
+				interval.Locator.Keys[monitorapi.LocatorAlertKey], interval.Locator.Keys[monitorapi.LocatorNamespaceKey]),
+		}
+	}
+	return intervalKey{source: interval.Source, key: interval.Locator.OldLocator()}
+}
+
+func beginningOf(intervals monitorapi.Intervals) time.Time {
+	var ret time.Time
+	for _, interval := range intervals {
+		if !interval.From.IsZero() && (ret.IsZero() || interval.From.Before(ret)) {
+			ret = interval.From
+		}
+	}
+	return ret
+}
+
+// relativeWindow is when an interval happened relative to the beginning of its run.
+type relativeWindow struct {
+	from, to time.Duration
+}
+
+func relativeTo(interval monitorapi.Interval, beginning time.Time) relativeWindow {
+	to := interval.To
+	if to.IsZero() {
+		to = interval.From
+	}
+	return relativeWindow{from: interval.From.Sub(beginning), to: to.Sub(beginning)}
+}
+
+// newWindows returns the candidate intervals no baseline interval of the same key overlaps, with the tolerance.
+func newWindows(baseline monitorapi.Intervals, baselineBeginning time.Time, candidate monitorapi.Intervals, candidateBeginning time.Time, tolerance time.Duration) []Window {
+	baselineWindows := map[intervalKey][]relativeWindow{}
+	for _, interval := range baseline {
+		key := keyOf(interval)
+		baselineWindows[key] = append(baselineWindows[key], relativeTo(interval, baselineBeginning))
+	}
+
+	ret := []Window{}
+	for _, interval := range candidate {
+		key := keyOf(interval)
+		window := relativeTo(interval, candidateBeginning)
+		matched := false
+		for _, baselineWindow := range baselineWindows[key] {
+			if baselineWindow.from-tolerance <= window.to && window.from <= baselineWindow.to+tolerance {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		ret = append(ret, Window{
+			Key:             key.key,
+			Source:          interval.Source,
+			From:            interval.From,
+			OffsetSeconds:   window.from.Seconds(),
+			DurationSeconds: (window.to - window.from).Seconds(),
+			Message:         interval.Message.HumanMessage,
+		})
+	}
+	return ret
+}
+
+// durationsByKey is the total duration of the intervals of every key.
+func durationsByKey(intervals monitorapi.Intervals) map[intervalKey]time.Duration {
+	byKey := map[intervalKey]monitorapi.Intervals{}
+	for _, interval := range intervals {
+		key := keyOf(interval)
+		byKey[key] = append(byKey[key], interval)
+	}
+	ret := map[intervalKey]time.Duration{}
+	for key, keyIntervals := range byKey {
+		ret[key] = keyIntervals.Duration(0)
+	}
+	return ret
+}
@@ -0,0 +1,120 @@
+package intervaldiff
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func disruption(backend string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().DisruptionRequiredOnly(backend, backend+"-new-connections")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage(backend+" stopped responding")).
+		Build(from, to)
+}
+
+func firingAlert(name, pod string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().AlertFromPromSampleStream(&model.SampleStream{
+			Metric: model.Metric{model.AlertNameLabel: model.LabelValue(name), "namespace": "openshift-etcd", "pod": model.LabelValue(pod)},
+		})).
+		Message(monitorapi.NewMessage().WithAnnotation(monitorapi.AnnotationAlertState, "firing").HumanMessage(name+" firing")).
+		Build(from, to)
+}
+
+func TestCompare(t *testing.T) {
+	baselineStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candidateStart := time.Date(2024, 2, 1, 6, 0, 0, 0, time.UTC)
+
+	baseline := monitorapi.Intervals{
+		disruption("kube-api", baselineStart, baselineStart.Add(5*time.Second)),
+		disruption("oauth-api", baselineStart.Add(10*time.Minute), baselineStart.Add(10*time.Minute+5*time.Second)),
+		firingAlert("etcdMembersDown", "etcd-master-0", baselineStart.Add(20*time.Minute), baselineStart.Add(21*time.Minute)),
+	}
+	candidate := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().HumanMessage("first interval of the run")).
+			Build(candidateStart, candidateStart.Add(time.Second)),
+		// the same window, shifted by less than the tolerance.
+		disruption("kube-api", candidateStart.Add(30*time.Second), candidateStart.Add(35*time.Second)),
+		// the same backend, much later in the run.
+		disruption("oauth-api", candidateStart.Add(40*time.Minute), candidateStart.Add(40*time.Minute+5*time.Second)),
+		// the same alert about a pod named differently.
+		firingAlert("etcdMembersDown", "etcd-master-1", candidateStart.Add(20*time.Minute), candidateStart.Add(30*time.Minute)),
+		firingAlert("etcdHighFsyncDurations", "etcd-master-1", candidateStart.Add(25*time.Minute), candidateStart.Add(26*time.Minute)),
+	}
+
+	diff := Compare(baseline, candidate, Options{})
+	if !diff.BaselineBeginning.Equal(baselineStart) || !diff.CandidateBeginning.Equal(candidateStart) {
+		t.Errorf("expected the runs to be aligned at their first interval, got %v and %v", diff.BaselineBeginning, diff.CandidateBeginning)
+	}
+	if len(diff.NewDisruptions) != 1 || !strings.Contains(diff.NewDisruptions[0].Key, "oauth-api") || diff.NewDisruptions[0].OffsetSeconds != 2400 {
+		t.Errorf("expected the later oauth-api disruption to be new, got %#v", diff.NewDisruptions)
+	}
+	if len(diff.NewAlerts) != 1 || diff.NewAlerts[0].Key != "alert/etcdHighFsyncDurations namespace/openshift-etcd" {
+		t.Errorf("expected etcdHighFsyncDurations to be new, got %#v", diff.NewAlerts)
+	}
+	if len(diff.DurationRegressions) != 1 ||
+		diff.DurationRegressions[0].Key != "alert/etcdMembersDown namespace/openshift-etcd" ||
+		diff.DurationRegressions[0].BaselineSeconds != 60 || diff.DurationRegressions[0].CandidateSeconds != 600 {
+		t.Errorf("expected etcdMembersDown to fire longer, got %#v", diff.DurationRegressions)
+	}
+
+	out := &bytes.Buffer{}
+	diff.WriteText(out)
+	for _, expected := range []string{
+		"New disruption windows (1):",
+		"+40m0s for 5s",
+		"New alert firings (1):",
+		"Duration regressions (1):",
+		"1m0s -> 10m0s  Alert alert/etcdMembersDown namespace/openshift-etcd",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in:\n%s", expected, out.String())
+		}
+	}
+
+	if unchanged := Compare(baseline, baseline, Options{}); !unchanged.IsEmpty() {
+		t.Errorf("expected no differences between a run and itself, got %#v", unchanged)
+	}
+}
+
+func TestLoadIntervals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := disruption("kube-api", start, start.Add(time.Second))
+	second := disruption("oauth-api", start.Add(time.Hour), start.Add(time.Hour+time.Second))
+
+	dir := t.TempDir()
+	if err := monitorserialization.EventsToFile(filepath.Join(dir, "e2e-events_20240101-000000.json"), monitorapi.Intervals{first, second}); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(dir, "e2e-events_timeline-serializer.json"), monitorapi.Intervals{second}); err != nil {
+		t.Fatal(err)
+	}
+
+	intervals, err := LoadIntervals(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 2 {
+		t.Errorf("expected every interval of the directory once, got %v", intervals)
+	}
+	intervals, err = LoadIntervals(filepath.Join(dir, "e2e-events_timeline-serializer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 1 {
+		t.Errorf("expected the intervals of the file, got %v", intervals)
+	}
+	if _, err := LoadIntervals(t.TempDir()); err == nil {
+		t.Errorf("expected an error for a directory without intervals")
+	}
+}
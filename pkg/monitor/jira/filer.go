@@ -0,0 +1,280 @@
+// Package jira files the regressions of a monitor run as JIRA issues through the JIRA REST API.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+const (
+	ActionCreated     = "created"
+	ActionUpdated     = "updated"
+	ActionWouldCreate = "would create"
+	ActionWouldUpdate = "would update"
+	// ActionWouldFile is reported by dry runs without a token, which cannot tell whether the issue exists.
+	ActionWouldFile = "would file"
+
+	// RegressionLabel is on every issue filed for a regression.
+	RegressionLabel = "monitor-regression"
+
+	defaultIssueType = "Bug"
+	// maxSummaryLength is the longest summary JIRA accepts.
+	maxSummaryLength = 255
+	// maxDescribedOutput is how much of the failure output the description holds.  The whole output is attached.
+	maxDescribedOutput = 8000
+	payloadFilename    = "regression.json"
+)
+
+// Options describe where and how regressions are filed.
+type Options struct {
+	// URL is the JIRA server, for instance https://issues.redhat.com.
+	URL string
+	// Project is the key of the project issues are filed in.
+	Project string
+	// IssueType is the type of the issues filed.  If empty, Bug is used.
+	IssueType string
+	// Token is a personal access token allowed to search, create, comment on, and attach to issues of the project.  It
+	// is required unless DryRun is set.
+	Token string
+	// DryRun only reports what would be filed.  With a token, the issues already filed are looked up so the report
+	// tells created from updated issues apart.
+	DryRun bool
+}
+
+type issueFiler struct {
+	options Options
+	client  *http.Client
+}
+
+// NewIssueFiler returns a filer that creates an issue in the jira component of every regression, or comments on the
+// open issue already filed for it.  The issue of a regression is found by a label derived from the test name, and the
+// regression is attached as JSON.
+func NewIssueFiler(options Options) (monitortestframework.IssueFiler, error) {
+	endpoint, err := url.Parse(options.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("the JIRA server must be an http or https URL, got %q", options.URL)
+	}
+	options.URL = strings.TrimSuffix(options.URL, "/")
+	if len(options.Project) == 0 {
+		return nil, fmt.Errorf("a JIRA project is required")
+	}
+	if len(options.Token) == 0 && !options.DryRun {
+		return nil, fmt.Errorf("a JIRA token is required unless this is a dry run")
+	}
+	if len(options.IssueType) == 0 {
+		options.IssueType = defaultIssueType
+	}
+	return &issueFiler{
+		options: options,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// IssueLabel is the label of the issues filed for a test.  Test names hold spaces, which labels cannot, so it is
+// derived from a hash of the name.
+func IssueLabel(testName string) string {
+	sum := sha256.Sum256([]byte(testName))
+	return RegressionLabel + "-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// FileRegressions files every regression, and keeps going when one cannot be filed.
+func (f *issueFiler) FileRegressions(ctx context.Context, regressions []monitortestframework.Regression) ([]monitortestframework.Filing, error) {
+	filings := []monitortestframework.Filing{}
+	errs := []error{}
+	for _, regression := range regressions {
+		filing, err := f.file(ctx, regression)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", regression.TestName, err))
+			continue
+		}
+		filings = append(filings, filing)
+	}
+	return filings, utilerrors.NewAggregate(errs)
+}
+
+func (f *issueFiler) file(ctx context.Context, regression monitortestframework.Regression) (monitortestframework.Filing, error) {
+	filing := monitortestframework.Filing{TestName: regression.TestName}
+	if f.options.DryRun && len(f.options.Token) == 0 {
+		filing.Action = ActionWouldFile
+		return filing, nil
+	}
+
+	existing, err := f.findOpenIssue(ctx, regression.TestName)
+	if err != nil {
+		return filing, err
+	}
+	filing.Issue = existing
+	switch {
+	case f.options.DryRun && len(existing) > 0:
+		filing.Action = ActionWouldUpdate
+		return filing, nil
+	case f.options.DryRun:
+		filing.Action = ActionWouldCreate
+		return filing, nil
+	case len(existing) > 0:
+		filing.Action = ActionUpdated
+		if err := f.comment(ctx, existing, regression); err != nil {
+			return filing, err
+		}
+	default:
+		filing.Action = ActionCreated
+		filing.Issue, err = f.create(ctx, regression)
+		if err != nil {
+			return filing, err
+		}
+	}
+	return filing, f.attach(ctx, filing.Issue, regression)
+}
+
+type searchResult struct {
+	Issues []struct {
+		Key string `json:"key"`
+	} `json:"issues"`
+}
+
+// findOpenIssue returns the key of the most recent open issue filed for the test, or empty if there is none.
+func (f *issueFiler) findOpenIssue(ctx context.Context, testName string) (string, error) {
+	jql := fmt.Sprintf(`project = %q AND labels = %q AND statusCategory != Done ORDER BY created DESC`, f.options.Project, IssueLabel(testName))
+	query := url.Values{"jql": {jql}, "maxResults": {"1"}, "fields": {"key"}}
+	result := &searchResult{}
+	if err := f.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), "", nil, result); err != nil {
+		return "", fmt.Errorf("unable to search for the issue: %w", err)
+	}
+	if len(result.Issues) == 0 {
+		return "", nil
+	}
+	return result.Issues[0].Key, nil
+}
+
+type createRequest struct {
+	Fields createFields `json:"fields"`
+}
+
+type createFields struct {
+	Project     keyField    `json:"project"`
+	IssueType   nameField   `json:"issuetype"`
+	Summary     string      `json:"summary"`
+	Description string      `json:"description"`
+	Components  []nameField `json:"components"`
+	Labels      []string    `json:"labels"`
+}
+
+type keyField struct {
+	Key string `json:"key"`
+}
+
+type nameField struct {
+	Name string `json:"name"`
+}
+
+func (f *issueFiler) create(ctx context.Context, regression monitortestframework.Regression) (string, error) {
+	summary := "Monitor test regression: " + regression.TestName
+	if len(summary) > maxSummaryLength {
+		summary = summary[:maxSummaryLength-3] + "..."
+	}
+	request := createRequest{
+		Fields: createFields{
+			Project:     keyField{Key: f.options.Project},
+			IssueType:   nameField{Name: f.options.IssueType},
+			Summary:     summary,
+			Description: describe(regression),
+			Components:  []nameField{{Name: regression.JiraComponent}},
+			Labels:      []string{RegressionLabel, IssueLabel(regression.TestName)},
+		},
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	created := &keyField{}
+	if err := f.do(ctx, http.MethodPost, "/rest/api/2/issue", "application/json", bytes.NewReader(body), created); err != nil {
+		return "", fmt.Errorf("unable to create the issue: %w", err)
+	}
+	return created.Key, nil
+}
+
+func (f *issueFiler) comment(ctx context.Context, issue string, regression monitortestframework.Regression) error {
+	body, err := json.Marshal(map[string]string{"body": "The test failed again.\n\n" + describe(regression)})
+	if err != nil {
+		return err
+	}
+	if err := f.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(issue)+"/comment", "application/json", bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("unable to comment on %s: %w", issue, err)
+	}
+	return nil
+}
+
+// attach attaches the regression as JSON, so tooling can read the failure without parsing the description.
+func (f *issueFiler) attach(ctx context.Context, issue string, regression monitortestframework.Regression) error {
+	payload, err := json.MarshalIndent(regression, "", "  ")
+	if err != nil {
+		return err
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", payloadFilename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(payload); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := f.do(ctx, http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(issue)+"/attachments", writer.FormDataContentType(), body, nil); err != nil {
+		return fmt.Errorf("unable to attach the regression to %s: %w", issue, err)
+	}
+	return nil
+}
+
+// describe is the regression in JIRA wiki markup.
+func describe(regression monitortestframework.Regression) string {
+	output := regression.Output
+	if len(output) > maxDescribedOutput {
+		output = output[:maxDescribedOutput] + "\n... (the whole output is attached as " + payloadFilename + ")"
+	}
+	return fmt.Sprintf("*Test:* %s\n*Monitor test:* %s\n\n{noformat}\n%s\n{noformat}", regression.TestName, regression.MonitorTest, output)
+}
+
+// do sends a request to the JIRA server and decodes the response into result, if it is not nil.
+func (f *issueFiler) do(ctx context.Context, method, path, contentType string, body io.Reader, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, f.options.URL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+f.options.Token)
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// attachments are rejected without it, as protection against cross-site requests.
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("JIRA returned %s: %s", resp.Status, content)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
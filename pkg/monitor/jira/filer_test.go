@@ -0,0 +1,202 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+// fakeJIRA holds open issues by label, and records what was created, commented on, and attached.
+type fakeJIRA struct {
+	lock        sync.Mutex
+	openIssues  map[string]string
+	created     []createRequest
+	comments    []string
+	attachments map[string]monitortestframework.Regression
+}
+
+func (j *fakeJIRA) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if req.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/rest/api/2/search":
+		result := map[string][]keyField{"issues": {}}
+		for label, key := range j.openIssues {
+			if strings.Contains(req.URL.Query().Get("jql"), label) {
+				result["issues"] = append(result["issues"], keyField{Key: key})
+			}
+		}
+		json.NewEncoder(w).Encode(result)
+	case req.Method == http.MethodPost && req.URL.Path == "/rest/api/2/issue":
+		request := createRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		j.created = append(j.created, request)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(keyField{Key: "OCPBUGS-100"})
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/comment"):
+		j.comments = append(j.comments, strings.Split(req.URL.Path, "/")[5])
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/attachments"):
+		if req.Header.Get("X-Atlassian-Token") != "no-check" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		file, _, err := req.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		regression := monitortestframework.Regression{}
+		if err := json.Unmarshal(content, &regression); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		j.attachments[strings.Split(req.URL.Path, "/")[5]] = regression
+		w.Write([]byte("[]"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestFileRegressions(t *testing.T) {
+	regressions := []monitortestframework.Regression{
+		{TestName: "[sig-network] new regression", MonitorTest: "a", JiraComponent: "Networking", Output: "failed"},
+		{TestName: "[sig-etcd] filed regression", MonitorTest: "b", JiraComponent: "Etcd", Output: "failed again"},
+	}
+	tests := []struct {
+		name             string
+		options          Options
+		expectedFilings  []monitortestframework.Filing
+		expectedCreated  int
+		expectedComments []string
+	}{
+		{
+			name:    "files",
+			options: Options{Project: "OCPBUGS", Token: "secret"},
+			expectedFilings: []monitortestframework.Filing{
+				{TestName: "[sig-network] new regression", Issue: "OCPBUGS-100", Action: ActionCreated},
+				{TestName: "[sig-etcd] filed regression", Issue: "OCPBUGS-7", Action: ActionUpdated},
+			},
+			expectedCreated:  1,
+			expectedComments: []string{"OCPBUGS-7"},
+		},
+		{
+			name:    "dry run",
+			options: Options{Project: "OCPBUGS", Token: "secret", DryRun: true},
+			expectedFilings: []monitortestframework.Filing{
+				{TestName: "[sig-network] new regression", Action: ActionWouldCreate},
+				{TestName: "[sig-etcd] filed regression", Issue: "OCPBUGS-7", Action: ActionWouldUpdate},
+			},
+		},
+		{
+			name:    "dry run without a token",
+			options: Options{Project: "OCPBUGS", DryRun: true},
+			expectedFilings: []monitortestframework.Filing{
+				{TestName: "[sig-network] new regression", Action: ActionWouldFile},
+				{TestName: "[sig-etcd] filed regression", Action: ActionWouldFile},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeJIRA{
+				openIssues:  map[string]string{IssueLabel("[sig-etcd] filed regression"): "OCPBUGS-7"},
+				attachments: map[string]monitortestframework.Regression{},
+			}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			tt.options.URL = server.URL + "/"
+			filer, err := NewIssueFiler(tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filings, err := filer.FileRegressions(context.Background(), regressions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tt.expectedFilings, filings) {
+				t.Errorf("expected\n%#v\ngot\n%#v", tt.expectedFilings, filings)
+			}
+
+			if len(fake.created) != tt.expectedCreated {
+				t.Fatalf("expected %d issues created, got %d", tt.expectedCreated, len(fake.created))
+			}
+			for _, created := range fake.created {
+				if created.Fields.Components[0].Name != "Networking" || created.Fields.Project.Key != "OCPBUGS" || created.Fields.IssueType.Name != defaultIssueType {
+					t.Errorf("expected a bug in the component of the regression, got %#v", created.Fields)
+				}
+				if !reflect.DeepEqual(created.Fields.Labels, []string{RegressionLabel, IssueLabel("[sig-network] new regression")}) {
+					t.Errorf("expected the issue to be labeled for the regression, got %v", created.Fields.Labels)
+				}
+			}
+			if !reflect.DeepEqual(tt.expectedComments, fake.comments) {
+				t.Errorf("expected comments on %v, got %v", tt.expectedComments, fake.comments)
+			}
+			for _, filing := range filings {
+				if strings.HasPrefix(filing.Action, "would") {
+					continue
+				}
+				if attached := fake.attachments[filing.Issue]; attached.TestName != filing.TestName {
+					t.Errorf("expected the regression to be attached to %s, got %#v", filing.Issue, attached)
+				}
+			}
+		})
+	}
+}
+
+func TestFileRegressionsKeepsGoing(t *testing.T) {
+	fake := &fakeJIRA{openIssues: map[string]string{}, attachments: map[string]monitortestframework.Regression{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	filer, err := NewIssueFiler(Options{URL: server.URL, Project: "OCPBUGS", Token: "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filings, err := filer.FileRegressions(context.Background(), []monitortestframework.Regression{
+		{TestName: "a", JiraComponent: "A"},
+		{TestName: "b", JiraComponent: "B"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the rejected credentials to be reported, got %v", err)
+	}
+	if len(filings) != 0 {
+		t.Errorf("expected nothing filed, got %v", filings)
+	}
+}
+
+func TestNewIssueFiler(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{name: "not a URL", options: Options{URL: "issues.example.com", Project: "OCPBUGS", Token: "secret"}},
+		{name: "no project", options: Options{URL: "https://issues.example.com", Token: "secret"}},
+		{name: "no token", options: Options{URL: "https://issues.example.com", Project: "OCPBUGS"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewIssueFiler(tt.options); err == nil {
+				t.Errorf("expected %#v to be rejected", tt.options)
+			}
+		})
+	}
+}
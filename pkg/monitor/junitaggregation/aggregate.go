@@ -0,0 +1,247 @@
+package junitaggregation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// ConflictResolution decides the result of a test that ran in more than one phase.  Merging the junit files of every
+// phase naively turns a test that failed before an upgrade and passed after it into a flake, which is rarely what
+// the phases mean.
+type ConflictResolution string
+
+const (
+	// FailIfAnyPhaseFailed fails a test that failed in any phase, even if it passed in another.  A test that only
+	// flaked is still a flake.
+	FailIfAnyPhaseFailed ConflictResolution = "FailIfAnyPhaseFailed"
+
+	// LatestPhaseWins treats later phases as retries of earlier ones, for instance when the monitor was restarted.
+	// A test that passed in the latest phase it ran in, but failed earlier, is a flake.
+	LatestPhaseWins ConflictResolution = "LatestPhaseWins"
+
+	// SeparatePhases keeps every phase as its own set of tests by adding the phase to the test name.
+	SeparatePhases ConflictResolution = "SeparatePhases"
+)
+
+// PhaseProperty is the suite property listing the phases that were aggregated, in order.
+const PhaseProperty = "phase"
+
+// Phase is the junit results of one run of the monitor.
+type Phase struct {
+	// Name labels the phase, for instance "pre-upgrade".
+	Name      string
+	TestCases []*junitapi.JUnitTestCase
+}
+
+// LoadPhase reads the test cases of a junit file.  Both a single testsuite and a testsuites root are accepted, and
+// nested suites are flattened.
+func LoadPhase(name, path string) (Phase, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Phase{}, err
+	}
+
+	suites := []*junitapi.JUnitTestSuite{}
+	multiple := &junitapi.JUnitTestSuites{}
+	if err := xml.Unmarshal(content, multiple); err == nil {
+		suites = multiple.Suites
+	} else {
+		single := &junitapi.JUnitTestSuite{}
+		if err := xml.Unmarshal(content, single); err != nil {
+			return Phase{}, fmt.Errorf("unable to parse junit %q: %w", path, err)
+		}
+		suites = append(suites, single)
+	}
+
+	ret := Phase{Name: name}
+	for len(suites) > 0 {
+		suite := suites[0]
+		suites = append(suites[1:], suite.Children...)
+		ret.TestCases = append(ret.TestCases, suite.TestCases...)
+	}
+	return ret, nil
+}
+
+// outcome is the result of a test within a single phase.
+type outcome int
+
+const (
+	skipped outcome = iota
+	passed
+	flaked
+	failed
+)
+
+func (o outcome) String() string {
+	switch o {
+	case passed:
+		return "passed"
+	case flaked:
+		return "flaked"
+	case failed:
+		return "failed"
+	default:
+		return "skipped"
+	}
+}
+
+// phaseResult is every test case with one name in one phase.
+type phaseResult struct {
+	phase     string
+	testCases []*junitapi.JUnitTestCase
+}
+
+func (r phaseResult) outcome() outcome {
+	hasPass, hasFailure := false, false
+	for _, testCase := range r.testCases {
+		switch {
+		case testCase.FailureOutput != nil:
+			hasFailure = true
+		case testCase.SkipMessage == nil:
+			hasPass = true
+		}
+	}
+	switch {
+	case hasFailure && hasPass:
+		return flaked
+	case hasFailure:
+		return failed
+	case hasPass:
+		return passed
+	default:
+		return skipped
+	}
+}
+
+// Aggregate merges the phases, in the order they ran, into one suite.  Every merged test case records which phase
+// each result came from, and the suite lists the phases as properties.
+func Aggregate(suiteName string, resolution ConflictResolution, phases ...Phase) (*junitapi.JUnitTestSuite, error) {
+	switch resolution {
+	case FailIfAnyPhaseFailed, LatestPhaseWins, SeparatePhases:
+	default:
+		return nil, fmt.Errorf("unknown conflict resolution %q", resolution)
+	}
+
+	suite := &junitapi.JUnitTestSuite{Name: suiteName}
+	phaseNames := sets.NewString()
+	testNames := []string{}
+	resultsByName := map[string][]phaseResult{}
+	for _, phase := range phases {
+		if len(phase.Name) == 0 {
+			return nil, fmt.Errorf("every phase must be named")
+		}
+		if phaseNames.Has(phase.Name) {
+			return nil, fmt.Errorf("phase %q is listed more than once", phase.Name)
+		}
+		phaseNames.Insert(phase.Name)
+		suite.Properties = append(suite.Properties, &junitapi.TestSuiteProperty{Name: PhaseProperty, Value: phase.Name})
+
+		for _, testCase := range phase.TestCases {
+			if resolution == SeparatePhases {
+				labeled := *testCase
+				labeled.Name = fmt.Sprintf("%s [phase:%s]", testCase.Name, phase.Name)
+				suite.TestCases = append(suite.TestCases, &labeled)
+				continue
+			}
+			results, ok := resultsByName[testCase.Name]
+			if !ok {
+				testNames = append(testNames, testCase.Name)
+			}
+			if len(results) == 0 || results[len(results)-1].phase != phase.Name {
+				results = append(results, phaseResult{phase: phase.Name})
+			}
+			results[len(results)-1].testCases = append(results[len(results)-1].testCases, testCase)
+			resultsByName[testCase.Name] = results
+		}
+	}
+
+	for _, name := range testNames {
+		suite.TestCases = append(suite.TestCases, resolve(name, resolution, resultsByName[name])...)
+	}
+	for _, testCase := range suite.TestCases {
+		suite.NumTests++
+		switch {
+		case testCase.FailureOutput != nil:
+			suite.NumFailed++
+		case testCase.SkipMessage != nil:
+			suite.NumSkipped++
+		}
+	}
+	return suite, nil
+}
+
+// resolve returns the test cases for one test name: a single failure, a failure and a success for a flake, a
+// single success, or a single skip.
+func resolve(name string, resolution ConflictResolution, results []phaseResult) []*junitapi.JUnitTestCase {
+	final := skipped
+	switch resolution {
+	case FailIfAnyPhaseFailed:
+		for _, result := range results {
+			if o := result.outcome(); o > final {
+				final = o
+			}
+		}
+	case LatestPhaseWins:
+		for _, result := range results {
+			o := result.outcome()
+			switch {
+			case o == skipped:
+			case o == passed && (final == failed || final == flaked):
+				// an earlier failure passed on retry.
+				final = flaked
+			default:
+				final = o
+			}
+		}
+	}
+
+	summary := []string{}
+	failureOutputs := []string{}
+	systemOuts := []string{}
+	var duration float64
+	for _, result := range results {
+		summary = append(summary, fmt.Sprintf("%s: %v", result.phase, result.outcome()))
+		for _, testCase := range result.testCases {
+			duration += testCase.Duration
+			if testCase.FailureOutput != nil {
+				failureOutputs = append(failureOutputs, fmt.Sprintf("[%s] %s", result.phase, testCase.FailureOutput.Output))
+			}
+			if len(testCase.SystemOut) > 0 {
+				systemOuts = append(systemOuts, fmt.Sprintf("[%s] %s", result.phase, testCase.SystemOut))
+			}
+		}
+	}
+	phaseSummary := fmt.Sprintf("phases: %s", strings.Join(summary, ", "))
+
+	success := &junitapi.JUnitTestCase{
+		Name:      name,
+		Duration:  duration,
+		SystemOut: strings.Join(append([]string{phaseSummary}, systemOuts...), "\n"),
+	}
+	switch final {
+	case skipped:
+		success.SkipMessage = &junitapi.SkipMessage{Message: phaseSummary}
+		return []*junitapi.JUnitTestCase{success}
+	case passed:
+		return []*junitapi.JUnitTestCase{success}
+	}
+
+	failure := &junitapi.JUnitTestCase{
+		Name:      name,
+		Duration:  duration,
+		SystemOut: success.SystemOut,
+		FailureOutput: &junitapi.FailureOutput{
+			Output: strings.Join(append([]string{phaseSummary}, failureOutputs...), "\n"),
+		},
+	}
+	if final == failed {
+		return []*junitapi.JUnitTestCase{failure}
+	}
+	return []*junitapi.JUnitTestCase{failure, success}
+}
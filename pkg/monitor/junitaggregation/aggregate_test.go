@@ -0,0 +1,201 @@
+package junitaggregation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func pass(name string) *junitapi.JUnitTestCase {
+	return &junitapi.JUnitTestCase{Name: name}
+}
+
+func fail(name, output string) *junitapi.JUnitTestCase {
+	return &junitapi.JUnitTestCase{Name: name, FailureOutput: &junitapi.FailureOutput{Output: output}}
+}
+
+func skip(name string) *junitapi.JUnitTestCase {
+	return &junitapi.JUnitTestCase{Name: name, SkipMessage: &junitapi.SkipMessage{Message: "skipped"}}
+}
+
+// outcomes returns "pass", "fail", "flake", or "skip" for every test name in the suite.
+func outcomes(suite *junitapi.JUnitTestSuite) map[string]string {
+	passes, failures, skips := map[string]int{}, map[string]int{}, map[string]int{}
+	for _, testCase := range suite.TestCases {
+		switch {
+		case testCase.FailureOutput != nil:
+			failures[testCase.Name]++
+		case testCase.SkipMessage != nil:
+			skips[testCase.Name]++
+		default:
+			passes[testCase.Name]++
+		}
+	}
+	ret := map[string]string{}
+	for _, testCase := range suite.TestCases {
+		switch name := testCase.Name; {
+		case failures[name] > 0 && passes[name] > 0:
+			ret[name] = "flake"
+		case failures[name] > 0:
+			ret[name] = "fail"
+		case passes[name] > 0:
+			ret[name] = "pass"
+		default:
+			ret[name] = "skip"
+		}
+	}
+	return ret
+}
+
+func TestAggregate(t *testing.T) {
+	preUpgrade := Phase{
+		Name: "pre-upgrade",
+		TestCases: []*junitapi.JUnitTestCase{
+			fail("failed then passed", "before"),
+			pass("passed then failed"),
+			pass("passed twice"),
+			fail("flaked", "first try"), pass("flaked"),
+			skip("skipped then passed"),
+			pass("only before"),
+		},
+	}
+	postUpgrade := Phase{
+		Name: "post-upgrade",
+		TestCases: []*junitapi.JUnitTestCase{
+			pass("failed then passed"),
+			fail("passed then failed", "after"),
+			pass("passed twice"),
+			pass("flaked"),
+			pass("skipped then passed"),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		resolution ConflictResolution
+		expected   map[string]string
+	}{
+		{
+			name:       "fail if any phase failed",
+			resolution: FailIfAnyPhaseFailed,
+			expected: map[string]string{
+				"failed then passed":  "fail",
+				"passed then failed":  "fail",
+				"passed twice":        "pass",
+				"flaked":              "flake",
+				"skipped then passed": "pass",
+				"only before":         "pass",
+			},
+		},
+		{
+			name:       "latest phase wins",
+			resolution: LatestPhaseWins,
+			expected: map[string]string{
+				"failed then passed":  "flake",
+				"passed then failed":  "fail",
+				"passed twice":        "pass",
+				"flaked":              "flake",
+				"skipped then passed": "pass",
+				"only before":         "pass",
+			},
+		},
+		{
+			name:       "separate phases",
+			resolution: SeparatePhases,
+			expected: map[string]string{
+				"failed then passed [phase:pre-upgrade]":   "fail",
+				"failed then passed [phase:post-upgrade]":  "pass",
+				"passed then failed [phase:pre-upgrade]":   "pass",
+				"passed then failed [phase:post-upgrade]":  "fail",
+				"passed twice [phase:pre-upgrade]":         "pass",
+				"passed twice [phase:post-upgrade]":        "pass",
+				"flaked [phase:pre-upgrade]":               "flake",
+				"flaked [phase:post-upgrade]":              "pass",
+				"skipped then passed [phase:pre-upgrade]":  "skip",
+				"skipped then passed [phase:post-upgrade]": "pass",
+				"only before [phase:pre-upgrade]":          "pass",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite, err := Aggregate("monitor", tt.resolution, preUpgrade, postUpgrade)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual := outcomes(suite)
+			if len(actual) != len(tt.expected) {
+				t.Errorf("expected %d tests, got %v", len(tt.expected), actual)
+			}
+			for name, expected := range tt.expected {
+				if actual[name] != expected {
+					t.Errorf("%q: expected %v, got %v", name, expected, actual[name])
+				}
+			}
+			if len(suite.Properties) != 2 || suite.Properties[0].Value != "pre-upgrade" || suite.Properties[1].Value != "post-upgrade" {
+				t.Errorf("expected the phases as properties, got %v", suite.Properties)
+			}
+			if int(suite.NumTests) != len(suite.TestCases) {
+				t.Errorf("expected %d tests counted, got %d", len(suite.TestCases), suite.NumTests)
+			}
+		})
+	}
+}
+
+func TestAggregate_FailureOutputLabelsPhases(t *testing.T) {
+	suite, err := Aggregate("monitor", FailIfAnyPhaseFailed,
+		Phase{Name: "pre-upgrade", TestCases: []*junitapi.JUnitTestCase{fail("test", "before")}},
+		Phase{Name: "post-upgrade", TestCases: []*junitapi.JUnitTestCase{fail("test", "after")}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suite.TestCases) != 1 {
+		t.Fatalf("expected a single failure, got %d test cases", len(suite.TestCases))
+	}
+	expected := "phases: pre-upgrade: failed, post-upgrade: failed\n[pre-upgrade] before\n[post-upgrade] after"
+	if actual := suite.TestCases[0].FailureOutput.Output; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestAggregate_Validation(t *testing.T) {
+	if _, err := Aggregate("monitor", "Unknown"); err == nil {
+		t.Error("expected an unknown resolution to be rejected")
+	}
+	if _, err := Aggregate("monitor", LatestPhaseWins, Phase{}); err == nil {
+		t.Error("expected an unnamed phase to be rejected")
+	}
+	if _, err := Aggregate("monitor", LatestPhaseWins, Phase{Name: "a"}, Phase{Name: "a"}); err == nil {
+		t.Error("expected a repeated phase to be rejected")
+	}
+}
+
+func TestLoadPhase(t *testing.T) {
+	dir := t.TempDir()
+	single := filepath.Join(dir, "single.xml")
+	if err := os.WriteFile(single, []byte(`<testsuite name="monitor"><testcase name="a"></testcase><testsuite name="child"><testcase name="b"><failure>oops</failure></testcase></testsuite></testsuite>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	multiple := filepath.Join(dir, "multiple.xml")
+	if err := os.WriteFile(multiple, []byte(`<testsuites><testsuite name="one"><testcase name="a"></testcase></testsuite><testsuite name="two"><testcase name="b"></testcase></testsuite></testsuites>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{single, multiple} {
+		phase, err := LoadPhase("pre-upgrade", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, testCase := range phase.TestCases {
+			names = append(names, testCase.Name)
+		}
+		if strings.Join(names, ",") != "a,b" {
+			t.Errorf("%s: expected tests a,b, got %v", filepath.Base(path), names)
+		}
+	}
+}
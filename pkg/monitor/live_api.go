@@ -0,0 +1,245 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+// liveAPIListenAddressEnvVar is the address, for instance ":9091", that serves the live monitor API while the
+// monitor runs.  If it is not set, the API is not served.
+const liveAPIListenAddressEnvVar = "OPENSHIFT_TESTS_MONITOR_API_ADDRESS"
+
+const (
+	liveAPIIntervalsPath  = "/api/v1/intervals"
+	liveAPIResourcesPath  = "/api/v1/resources"
+	liveAPIInvariantsPath = "/api/v1/invariants"
+	liveAPIFaultsPath     = "/api/v1/faults"
+)
+
+// liveAPI answers questions about a running monitor, so chaos tooling and debugging sessions do not have to wait for
+// the artifacts of a long job.  Like the live metrics, it reads the recorder on every request.
+type liveAPI struct {
+	recorder monitorapi.Recorder
+	metrics  *liveMetrics
+}
+
+func newLiveAPI(recorder monitorapi.Recorder, metrics *liveMetrics) *liveAPI {
+	return &liveAPI{
+		recorder: recorder,
+		metrics:  metrics,
+	}
+}
+
+// ResourceSummary lists the instances of one resource type the monitor currently knows about.
+type ResourceSummary struct {
+	Type      string                   `json:"type"`
+	Count     int                      `json:"count"`
+	Instances []monitorapi.InstanceKey `json:"instances"`
+}
+
+// ResourceSnapshot is the answer to a resource request.  Items are only filled in when a single type is requested,
+// because the full state of every recorded resource is too large to hand out in one response.
+type ResourceSnapshot struct {
+	Resources []ResourceSummary           `json:"resources"`
+	Items     []unstructured.Unstructured `json:"items,omitempty"`
+}
+
+// InvariantStatus is the state of one monitor test so far.
+type InvariantStatus struct {
+	MonitorTest string `json:"monitorTest"`
+	Healthy     bool   `json:"healthy"`
+	// FailingTests are the junits of the monitor test that have only failed so far.
+	FailingTests []string `json:"failingTests,omitempty"`
+}
+
+func (a *liveAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(liveAPIIntervalsPath, a.serveIntervals)
+	mux.HandleFunc(liveAPIResourcesPath, a.serveResources)
+	mux.HandleFunc(liveAPIInvariantsPath, a.serveInvariants)
+	mux.HandleFunc(liveAPIFaultsPath, a.serveFaults)
+	return mux
+}
+
+// serveIntervals returns the intervals after the optional since parameter, in RFC3339, restricted to the optional
+// source parameter.
+func (a *liveAPI) serveIntervals(w http.ResponseWriter, req *http.Request) {
+	var since time.Time
+	if value := req.URL.Query().Get("since"); len(value) > 0 {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("since must be RFC3339: %v", err), http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+	source := monitorapi.IntervalSource(req.URL.Query().Get("source"))
+
+	intervals := a.recorder.Intervals(since, time.Time{})
+	if len(source) > 0 {
+		intervals = intervals.Filter(func(interval monitorapi.Interval) bool {
+			return interval.Source == source
+		})
+	}
+	body, err := monitorserialization.IntervalsToJSON(intervals)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// serveResources summarizes every recorded resource type, or returns the instances of the type parameter.
+func (a *liveAPI) serveResources(w http.ResponseWriter, req *http.Request) {
+	resourceType := req.URL.Query().Get("type")
+	resources := a.recorder.CurrentResourceState()
+
+	snapshot := ResourceSnapshot{Resources: []ResourceSummary{}}
+	for currType, instances := range resources {
+		if len(resourceType) > 0 && currType != resourceType {
+			continue
+		}
+		summary := ResourceSummary{Type: currType, Count: len(instances), Instances: []monitorapi.InstanceKey{}}
+		for key := range instances {
+			summary.Instances = append(summary.Instances, key)
+		}
+		sort.Slice(summary.Instances, func(i, j int) bool {
+			if summary.Instances[i].Namespace != summary.Instances[j].Namespace {
+				return summary.Instances[i].Namespace < summary.Instances[j].Namespace
+			}
+			return summary.Instances[i].Name < summary.Instances[j].Name
+		})
+		snapshot.Resources = append(snapshot.Resources, summary)
+	}
+	sort.Slice(snapshot.Resources, func(i, j int) bool {
+		return snapshot.Resources[i].Type < snapshot.Resources[j].Type
+	})
+
+	if len(resourceType) > 0 {
+		for _, summary := range snapshot.Resources {
+			for _, key := range summary.Instances {
+				item, err := toUnstructuredWithKind(resources[resourceType][key])
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				snapshot.Items = append(snapshot.Items, *item)
+			}
+		}
+	}
+	writeJSON(w, snapshot)
+}
+
+// toUnstructuredWithKind converts a recorded object so clients can decode it.  Objects recorded from informers have
+// no TypeMeta, so their apiVersion and kind come from the scheme, or from the go type if the scheme does not know it.
+func toUnstructuredWithKind(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	item := &unstructured.Unstructured{Object: content}
+	if len(item.GetKind()) > 0 {
+		return item, nil
+	}
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err == nil && len(gvks) > 0 {
+		item.SetAPIVersion(gvks[0].GroupVersion().String())
+		item.SetKind(gvks[0].Kind)
+		return item, nil
+	}
+	item.SetAPIVersion("v1")
+	item.SetKind(reflect.Indirect(reflect.ValueOf(obj)).Type().Name())
+	return item, nil
+}
+
+// serveInvariants returns the health of every monitor test from the junits produced so far.
+func (a *liveAPI) serveInvariants(w http.ResponseWriter, req *http.Request) {
+	ret := []InvariantStatus{}
+	failures := a.metrics.monitorTestFailures()
+	for monitorTest, failingTests := range failures {
+		ret = append(ret, InvariantStatus{
+			MonitorTest:  monitorTest,
+			Healthy:      len(failingTests) == 0,
+			FailingTests: failingTests,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].MonitorTest < ret[j].MonitorTest
+	})
+	writeJSON(w, ret)
+}
+
+// serveFaults records a fault posted by chaos tooling, so the symptoms that correlate with it are excused.  A fault
+// without a from time began when it was posted.
+func (a *liveAPI) serveFaults(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "faults must be posted", http.StatusMethodNotAllowed)
+		return
+	}
+	fault := monitortestframework.InjectedFault{}
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fault); err != nil {
+		http.Error(w, fmt.Sprintf("invalid fault: %v", err), http.StatusBadRequest)
+		return
+	}
+	if fault.From.IsZero() {
+		fault.From = time.Now()
+	}
+	if err := fault.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.recorder.AddIntervals(fault.Interval())
+	w.WriteHeader(http.StatusCreated)
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	body, err := json.MarshalIndent(obj, "", "    ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// startLiveAPIServer serves the live API on the address in the environment until the returned function is called.
+// When no address is configured, nothing is served.
+func startLiveAPIServer(api *liveAPI) (func(context.Context) error, error) {
+	listenAddress := os.Getenv(liveAPIListenAddressEnvVar)
+	if len(listenAddress) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for the live API on %q: %w", listenAddress, err)
+	}
+	server := &http.Server{Handler: api.handler()}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "error: Live API server stopped: %v\n", err)
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "Serving the live monitor API on %s/api/v1\n", listener.Addr())
+
+	return server.Shutdown, nil
+}
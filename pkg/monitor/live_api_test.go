@@ -0,0 +1,165 @@
+package monitor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestLiveAPI(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	liveRecorder := NewRecorder()
+	liveRecorder.AddIntervals(
+		monitorapi.Interval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Message: monitorapi.Message{HumanMessage: "early"}},
+			Source:    monitorapi.SourcePodState,
+			From:      start,
+			To:        start.Add(time.Second),
+		},
+		monitorapi.Interval{
+			Condition: monitorapi.Condition{Level: monitorapi.Error, Message: monitorapi.Message{HumanMessage: "late pod"}},
+			Source:    monitorapi.SourcePodState,
+			From:      start.Add(time.Minute),
+			To:        start.Add(2 * time.Minute),
+		},
+		monitorapi.Interval{
+			Condition: monitorapi.Condition{Level: monitorapi.Error, Message: monitorapi.Message{HumanMessage: "late disruption"}},
+			Source:    monitorapi.SourceDisruption,
+			From:      start.Add(time.Minute),
+			To:        start.Add(2 * time.Minute),
+		},
+	)
+	liveRecorder.RecordResource("pods", &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "b", Name: "pod", UID: "2"}})
+	liveRecorder.RecordResource("pods", &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: "pod", UID: "1"}})
+	liveRecorder.RecordResource("nodes", &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", UID: "3"}})
+
+	metrics := newLiveMetrics(liveRecorder, sets.NewString("healthy-test", "failing-test"))
+	metrics.recordJunits(
+		&junitapi.JUnitTestCase{Name: "failing", FailureOutput: &junitapi.FailureOutput{}, Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "failing-test"}},
+		&junitapi.JUnitTestCase{Name: "passing", Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "healthy-test"}},
+	)
+	server := httptest.NewServer(newLiveAPI(liveRecorder, metrics).handler())
+	defer server.Close()
+
+	t.Run("intervals since", func(t *testing.T) {
+		body := getLiveAPI(t, server.URL+liveAPIIntervalsPath+"?since="+start.Add(30*time.Second).Format(time.RFC3339)+"&source=PodState", http.StatusOK)
+		intervals, err := monitorserialization.IntervalsFromJSON(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(intervals) != 1 || intervals[0].Message.HumanMessage != "late pod" {
+			t.Errorf("unexpected intervals: %v", intervals)
+		}
+	})
+
+	t.Run("invalid since", func(t *testing.T) {
+		getLiveAPI(t, server.URL+liveAPIIntervalsPath+"?since=yesterday", http.StatusBadRequest)
+	})
+
+	t.Run("resource summary", func(t *testing.T) {
+		snapshot := ResourceSnapshot{}
+		if err := json.Unmarshal(getLiveAPI(t, server.URL+liveAPIResourcesPath, http.StatusOK), &snapshot); err != nil {
+			t.Fatal(err)
+		}
+		expected := []ResourceSummary{
+			{Type: "nodes", Count: 1, Instances: []monitorapi.InstanceKey{{Name: "node", UID: "3"}}},
+			{Type: "pods", Count: 2, Instances: []monitorapi.InstanceKey{{Namespace: "a", Name: "pod", UID: "1"}, {Namespace: "b", Name: "pod", UID: "2"}}},
+		}
+		if !reflect.DeepEqual(expected, snapshot.Resources) {
+			t.Errorf("expected %v, got %v", expected, snapshot.Resources)
+		}
+		if len(snapshot.Items) != 0 {
+			t.Errorf("expected no items without a type, got %d", len(snapshot.Items))
+		}
+	})
+
+	t.Run("resources of a type", func(t *testing.T) {
+		snapshot := ResourceSnapshot{}
+		if err := json.Unmarshal(getLiveAPI(t, server.URL+liveAPIResourcesPath+"?type=pods", http.StatusOK), &snapshot); err != nil {
+			t.Fatal(err)
+		}
+		if len(snapshot.Items) != 2 || snapshot.Items[0].GetNamespace() != "a" || snapshot.Items[1].GetNamespace() != "b" {
+			t.Errorf("unexpected items: %v", snapshot.Items)
+		}
+		for _, item := range snapshot.Items {
+			if item.GetAPIVersion() != "v1" || item.GetKind() != "Pod" {
+				t.Errorf("expected a v1 Pod, got %s %s", item.GetAPIVersion(), item.GetKind())
+			}
+		}
+	})
+
+	t.Run("invariants", func(t *testing.T) {
+		statuses := []InvariantStatus{}
+		if err := json.Unmarshal(getLiveAPI(t, server.URL+liveAPIInvariantsPath, http.StatusOK), &statuses); err != nil {
+			t.Fatal(err)
+		}
+		expected := []InvariantStatus{
+			{MonitorTest: "failing-test", Healthy: false, FailingTests: []string{"failing"}},
+			{MonitorTest: "healthy-test", Healthy: true},
+		}
+		if !reflect.DeepEqual(expected, statuses) {
+			t.Errorf("expected %v, got %v", expected, statuses)
+		}
+	})
+
+	t.Run("post fault", func(t *testing.T) {
+		resp, err := http.Post(server.URL+liveAPIFaultsPath, "application/json",
+			strings.NewReader(`{"name": "kill-worker-0", "kind": "node-kill", "from": "2024-01-01T00:03:00Z", "nodes": ["worker-0"]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+		}
+		faults := liveRecorder.Intervals(time.Time{}, time.Time{}).Filter(func(interval monitorapi.Interval) bool {
+			return interval.Source == monitorapi.SourceInjectedFault
+		})
+		if len(faults) != 1 || faults[0].Locator.Keys[monitorapi.LocatorInjectedFaultKey] != "kill-worker-0" || !faults[0].From.Equal(start.Add(3*time.Minute)) {
+			t.Errorf("unexpected faults: %v", faults)
+		}
+	})
+
+	t.Run("invalid fault", func(t *testing.T) {
+		resp, err := http.Post(server.URL+liveAPIFaultsPath, "application/json", strings.NewReader(`{"kind": "node-kill"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected a fault without a name to be rejected, got %d", resp.StatusCode)
+		}
+		getLiveAPI(t, server.URL+liveAPIFaultsPath, http.StatusMethodNotAllowed)
+	})
+}
+
+func getLiveAPI(t *testing.T, url string, expectedStatus int) []byte {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != expectedStatus {
+		t.Fatalf("expected status %d, got %d: %s", expectedStatus, resp.StatusCode, body)
+	}
+	return body
+}
@@ -0,0 +1,209 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// metricsListenAddressEnvVar is the address, for instance ":9090", that serves /metrics while the monitor runs.
+// If it is not set, no metrics are served.
+const metricsListenAddressEnvVar = "OPENSHIFT_TESTS_MONITOR_METRICS_ADDRESS"
+
+var (
+	intervalsDesc = prometheus.NewDesc(
+		"openshift_tests_monitor_intervals",
+		"Number of intervals recorded by the monitor so far, by source and level.",
+		[]string{"source", "level"}, nil,
+	)
+	disruptionSecondsDesc = prometheus.NewDesc(
+		"openshift_tests_monitor_disruption_seconds",
+		"Seconds of disruption observed so far for each backend, including disruption that is still ongoing.",
+		[]string{"backend"}, nil,
+	)
+	disruptionActiveDesc = prometheus.NewDesc(
+		"openshift_tests_monitor_disruption_active",
+		"1 if the backend is currently disrupted, 0 otherwise.",
+		[]string{"backend"}, nil,
+	)
+	monitorTestHealthyDesc = prometheus.NewDesc(
+		"openshift_tests_monitor_test_healthy",
+		"1 if no stage of the monitor test has failed so far, 0 otherwise.",
+		[]string{"monitor_test"}, nil,
+	)
+)
+
+// liveMetrics exposes the state of a running monitor.  It reads the recorder on every scrape, so the values are
+// as current as the recorder.
+type liveMetrics struct {
+	recorder     monitorapi.Recorder
+	monitorTests sets.String
+	now          func() time.Time
+
+	lock   sync.Mutex
+	junits []*junitapi.JUnitTestCase
+}
+
+var _ prometheus.Collector = &liveMetrics{}
+
+func newLiveMetrics(recorder monitorapi.Recorder, monitorTests sets.String) *liveMetrics {
+	return &liveMetrics{
+		recorder:     recorder,
+		monitorTests: monitorTests,
+		now:          time.Now,
+	}
+}
+
+// recordJunits makes the junits produced by a stage visible to the monitor test health metric.
+func (l *liveMetrics) recordJunits(junits ...*junitapi.JUnitTestCase) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.junits = append(l.junits, junits...)
+}
+
+func (l *liveMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- intervalsDesc
+	ch <- disruptionSecondsDesc
+	ch <- disruptionActiveDesc
+	ch <- monitorTestHealthyDesc
+}
+
+func (l *liveMetrics) Collect(ch chan<- prometheus.Metric) {
+	now := l.now()
+	type sourceLevel struct {
+		source monitorapi.IntervalSource
+		level  monitorapi.IntervalLevel
+	}
+	intervalCounts := map[sourceLevel]int{}
+	disruptionSeconds := map[string]float64{}
+	disruptionActive := map[string]bool{}
+	for _, interval := range l.recorder.Intervals(time.Time{}, time.Time{}) {
+		intervalCounts[sourceLevel{source: interval.Source, level: interval.Level}]++
+
+		if interval.Source != monitorapi.SourceDisruption {
+			continue
+		}
+		backend := interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey]
+		if len(backend) == 0 {
+			continue
+		}
+		if _, ok := disruptionSeconds[backend]; !ok {
+			disruptionSeconds[backend] = 0
+			disruptionActive[backend] = false
+		}
+		if interval.Level != monitorapi.Error {
+			continue
+		}
+		// the disruption sampler leaves the end of an interval empty until the backend is available again.
+		to := interval.To
+		if to.IsZero() {
+			to = now
+			disruptionActive[backend] = true
+		}
+		if to.After(interval.From) {
+			disruptionSeconds[backend] += to.Sub(interval.From).Seconds()
+		}
+	}
+
+	for key, count := range intervalCounts {
+		ch <- prometheus.MustNewConstMetric(intervalsDesc, prometheus.GaugeValue, float64(count), string(key.source), key.level.String())
+	}
+	for backend, seconds := range disruptionSeconds {
+		ch <- prometheus.MustNewConstMetric(disruptionSecondsDesc, prometheus.GaugeValue, seconds, backend)
+		ch <- prometheus.MustNewConstMetric(disruptionActiveDesc, prometheus.GaugeValue, boolToFloat(disruptionActive[backend]), backend)
+	}
+	for monitorTest, healthy := range l.monitorTestHealth() {
+		ch <- prometheus.MustNewConstMetric(monitorTestHealthyDesc, prometheus.GaugeValue, boolToFloat(healthy), monitorTest)
+	}
+}
+
+// monitorTestHealth reports a monitor test as unhealthy if any of its junits only fail.  A junit with the same
+// name that both fails and passes is a flake and does not make the monitor test unhealthy.
+func (l *liveMetrics) monitorTestHealth() map[string]bool {
+	ret := map[string]bool{}
+	for monitorTest, failingTests := range l.monitorTestFailures() {
+		ret[monitorTest] = len(failingTests) == 0
+	}
+	return ret
+}
+
+// monitorTestFailures returns the sorted names of the junits of each monitor test that have only failed so far.
+func (l *liveMetrics) monitorTestFailures() map[string][]string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	ret := map[string][]string{}
+	for _, monitorTest := range l.monitorTests.List() {
+		ret[monitorTest] = nil
+	}
+	failed := map[string]sets.String{}
+	passed := map[string]sets.String{}
+	for _, junit := range l.junits {
+		if junit == nil || junit.Details == nil || len(junit.Details.MonitorTest) == 0 {
+			continue
+		}
+		monitorTest := junit.Details.MonitorTest
+		if _, ok := failed[monitorTest]; !ok {
+			failed[monitorTest] = sets.NewString()
+			passed[monitorTest] = sets.NewString()
+		}
+		if junit.FailureOutput != nil {
+			failed[monitorTest].Insert(junit.Name)
+			continue
+		}
+		passed[monitorTest].Insert(junit.Name)
+	}
+	for monitorTest, failedNames := range failed {
+		ret[monitorTest] = failedNames.Difference(passed[monitorTest]).List()
+	}
+	return ret
+}
+
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// startMetricsServer serves the live metrics on the address in the environment until the returned function is
+// called.  When no address is configured, nothing is served.
+func startMetricsServer(metrics *liveMetrics) (func(context.Context) error, error) {
+	listenAddress := os.Getenv(metricsListenAddressEnvVar)
+	if len(listenAddress) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(metrics); err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for metrics on %q: %w", listenAddress, err)
+	}
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "error: Metrics server stopped: %v\n", err)
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "Serving monitor metrics on %s/metrics\n", listener.Addr())
+
+	return server.Shutdown, nil
+}
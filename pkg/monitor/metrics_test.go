@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestLiveMetrics(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kubeAPILocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeDisruption,
+		Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorBackendDisruptionNameKey: "kube-api-new-connections"},
+	}
+	ingressLocator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeDisruption,
+		Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorBackendDisruptionNameKey: "ingress-reused-connections"},
+	}
+
+	liveRecorder := NewRecorder()
+	liveRecorder.AddIntervals(
+		monitorapi.Interval{
+			Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: kubeAPILocator},
+			Source:    monitorapi.SourceDisruption,
+			From:      start,
+			To:        start.Add(3 * time.Second),
+		},
+		monitorapi.Interval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: kubeAPILocator},
+			Source:    monitorapi.SourceDisruption,
+			From:      start.Add(3 * time.Second),
+		},
+		monitorapi.Interval{
+			Condition: monitorapi.Condition{Level: monitorapi.Error, Locator: ingressLocator},
+			Source:    monitorapi.SourceDisruption,
+			From:      start.Add(5 * time.Second),
+		},
+		monitorapi.Interval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning},
+			Source:    monitorapi.SourcePodState,
+			From:      start,
+			To:        start,
+		},
+	)
+
+	metrics := newLiveMetrics(liveRecorder, sets.NewString("healthy-test", "failing-test", "flaking-test"))
+	metrics.now = func() time.Time { return start.Add(15 * time.Second) }
+	metrics.recordJunits(
+		&junitapi.JUnitTestCase{Name: "failing", FailureOutput: &junitapi.FailureOutput{}, Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "failing-test"}},
+		&junitapi.JUnitTestCase{Name: "flaking", FailureOutput: &junitapi.FailureOutput{}, Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "flaking-test"}},
+		&junitapi.JUnitTestCase{Name: "flaking", Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "flaking-test"}},
+		&junitapi.JUnitTestCase{Name: "passing", Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "healthy-test"}},
+	)
+
+	expected := `
+# HELP openshift_tests_monitor_disruption_active 1 if the backend is currently disrupted, 0 otherwise.
+# TYPE openshift_tests_monitor_disruption_active gauge
+openshift_tests_monitor_disruption_active{backend="ingress-reused-connections"} 1
+openshift_tests_monitor_disruption_active{backend="kube-api-new-connections"} 0
+# HELP openshift_tests_monitor_disruption_seconds Seconds of disruption observed so far for each backend, including disruption that is still ongoing.
+# TYPE openshift_tests_monitor_disruption_seconds gauge
+openshift_tests_monitor_disruption_seconds{backend="ingress-reused-connections"} 10
+openshift_tests_monitor_disruption_seconds{backend="kube-api-new-connections"} 3
+# HELP openshift_tests_monitor_intervals Number of intervals recorded by the monitor so far, by source and level.
+# TYPE openshift_tests_monitor_intervals gauge
+openshift_tests_monitor_intervals{level="Error",source="Disruption"} 2
+openshift_tests_monitor_intervals{level="Info",source="Disruption"} 1
+openshift_tests_monitor_intervals{level="Warning",source="PodState"} 1
+# HELP openshift_tests_monitor_test_healthy 1 if no stage of the monitor test has failed so far, 0 otherwise.
+# TYPE openshift_tests_monitor_test_healthy gauge
+openshift_tests_monitor_test_healthy{monitor_test="failing-test"} 0
+openshift_tests_monitor_test_healthy{monitor_test="flaking-test"} 1
+openshift_tests_monitor_test_healthy{monitor_test="healthy-test"} 1
+`
+	if err := testutil.CollectAndCompare(metrics, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/openshift/origin/pkg/riskanalysis"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
@@ -22,6 +25,7 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
 )
 
 type Monitor struct {
@@ -31,27 +35,105 @@ type Monitor struct {
 
 	recorder monitorapi.Recorder
 	junits   []*junitapi.JUnitTestCase
+	// resultsSummary summarizes the junits once the results are serialized.
+	resultsSummary *ResultsSummary
 
 	lock      sync.Mutex
 	stopFn    context.CancelFunc
 	startTime time.Time
 	stopTime  time.Time
+	// windowEnd, if set, bounds the intervals that are evaluated and written when the monitor stops after it.
+	windowEnd time.Time
+
+	// span covers the whole monitor run, every monitor test stage is a child.
+	span            trace.Span
+	shutdownTracing func(context.Context) error
+
+	// metrics are served while the monitor runs, if a listen address is configured.
+	metrics           *liveMetrics
+	stopMetricsServer func(context.Context) error
+	// stopLiveAPIServer stops serving the live API, which is served while the monitor runs if a listen address is
+	// configured.
+	stopLiveAPIServer func(context.Context) error
+
+	// credentials refreshes rejected credentials for every client built from adminKubeConfig.
+	credentials *credentialState
+	// watchHealth reports watch streams of clients built from adminKubeConfig that disconnect or fall behind.
+	watchHealth *watchHealth
+	// rateLimiter limits the intervals monitor tests record while they collect, so an event storm cannot run the
+	// monitor out of memory.
+	rateLimiter *intervalRateLimiter
+	// clock is the time of the monitor and everything it runs.  Replays use a fake one.
+	clock clock.PassiveClock
 }
 
-// NewMonitor creates a monitor with the default sampling interval.
+// NewMonitor creates a monitor with the default sampling interval.  Rejected credentials are refreshed by reloading
+// the kubeconfig.
 func NewMonitor(
 	recorder monitorapi.Recorder,
 	adminKubeConfig *rest.Config,
 	storageDir string,
 	monitorTestRegistry monitortestframework.MonitorTestRegistry) Interface {
+	return NewMonitorWithCredentialRefresher(recorder, adminKubeConfig, storageDir, monitorTestRegistry, NewKubeconfigCredentialRefresher())
+}
+
+// NewMonitorWithCredentialRefresher creates a monitor that uses the refresher when the cluster rejects its
+// credentials.  If they cannot be refreshed, the monitor keeps running and marks intervals as low confidence.
+func NewMonitorWithCredentialRefresher(
+	recorder monitorapi.Recorder,
+	adminKubeConfig *rest.Config,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry,
+	credentialRefresher CredentialRefresher) Interface {
+	return NewMonitorWithClock(recorder, adminKubeConfig, storageDir, monitorTestRegistry, credentialRefresher, clock.RealClock{})
+}
+
+// NewMonitorWithClock creates a monitor whose start and stop times, and every time its monitor tests read, come from
+// the clock.  The recorder should use the same clock.
+func NewMonitorWithClock(
+	recorder monitorapi.Recorder,
+	adminKubeConfig *rest.Config,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry,
+	credentialRefresher CredentialRefresher,
+	clock clock.PassiveClock) Interface {
+	monitorTestRegistry.SetClock(clock)
+	credentials := newCredentialState(credentialRefresher, recorder)
+	credentials.now = clock.Now
+	watchHealth := newWatchHealth(recorder)
+	watchHealth.now = clock.Now
+	rateLimiter := newIntervalRateLimiter(recorder, DefaultIntervalRateLimit)
+	rateLimiter.now = clock.Now
+	metrics := newLiveMetrics(recorder, monitorTestRegistry.ListMonitorTests())
+	metrics.now = clock.Now
 	return &Monitor{
-		adminKubeConfig:     adminKubeConfig,
-		recorder:            recorder,
+		adminKubeConfig:     watchHealth.wrapConfig(credentials.wrapConfig(adminKubeConfig)),
+		recorder:            newConfidenceRecorder(recorder, credentials),
 		monitorTestRegistry: monitorTestRegistry,
 		storageDir:          storageDir,
+		metrics:             metrics,
+		credentials:         credentials,
+		watchHealth:         watchHealth,
+		rateLimiter:         rateLimiter,
+		clock:               clock,
 	}
 }
 
+// NewMonitorForWindow creates a monitor that evaluates the window of the cluster's history ending at end, for applying
+// the monitor tests to an incident.  The registry should backfill from the beginning of the window until the monitor
+// starts.  A monitor stopped after end only evaluates and writes the intervals until end, so a window in the past can
+// be evaluated by stopping the monitor as soon as it started.
+func NewMonitorForWindow(
+	recorder monitorapi.Recorder,
+	adminKubeConfig *rest.Config,
+	storageDir string,
+	monitorTestRegistry monitortestframework.MonitorTestRegistry,
+	end time.Time) Interface {
+	m := NewMonitor(recorder, adminKubeConfig, storageDir, monitorTestRegistry).(*Monitor)
+	m.windowEnd = end
+	return m
+}
+
 var _ Interface = &Monitor{}
 
 // Start begins monitoring the cluster referenced by the default kube configuration until context is finished.
@@ -62,13 +144,36 @@ func (m *Monitor) Start(ctx context.Context) error {
 		return fmt.Errorf("monitor already started")
 	}
 	ctx, m.stopFn = context.WithCancel(ctx)
-	m.startTime = time.Now()
+	m.startTime = m.clock.Now()
+
+	shutdownTracing, err := startTracing(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting tracing, continuing without traces. %v\n", err)
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+	m.shutdownTracing = shutdownTracing
+	ctx, m.span = otel.Tracer(monitorTracerName).Start(ctx, "monitor")
+
+	stopMetricsServer, err := startMetricsServer(m.metrics)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting metrics server, continuing without metrics. %v\n", err)
+		stopMetricsServer = func(context.Context) error { return nil }
+	}
+	m.stopMetricsServer = stopMetricsServer
+
+	stopLiveAPIServer, err := startLiveAPIServer(newLiveAPI(m.recorder, m.metrics))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting live API server, continuing without it. %v\n", err)
+		stopLiveAPIServer = func(context.Context) error { return nil }
+	}
+	m.stopLiveAPIServer = stopLiveAPIServer
 
-	localJunits, err := m.monitorTestRegistry.StartCollection(ctx, m.adminKubeConfig, m.recorder)
+	go m.rateLimiter.run(ctx)
+	localJunits, err := m.monitorTestRegistry.StartCollection(ctx, m.adminKubeConfig, newRateLimitedRecorder(m.recorder, m.rateLimiter))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error starting data collection, continuing, junit will reflect this. %v\n", err)
 	}
-	m.junits = append(m.junits, localJunits...)
+	m.addJunits(localJunits...)
 	fmt.Printf("All monitor tests started.\n")
 
 	return nil
@@ -84,8 +189,10 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 	}
 	m.stopFn()
 	m.stopFn = nil
+	m.rateLimiter.stop()
+	ctx = trace.ContextWithSpan(ctx, m.span)
 
-	preStopTime := time.Now()
+	preStopTime := m.clock.Now()
 
 	fmt.Fprintf(os.Stderr, "Collecting data.\n")
 	collectedIntervals, collectionJunits, err := m.monitorTestRegistry.CollectData(ctx, m.storageDir, m.startTime, preStopTime)
@@ -94,27 +201,33 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 		fmt.Fprintf(os.Stderr, "Error collecting data, continuing, junit will reflect this. %v\n", err)
 	}
 	m.recorder.AddIntervals(collectedIntervals...)
-	m.junits = append(m.junits, collectionJunits...)
+	m.addJunits(collectionJunits...)
 
 	// set the stop time for after we finished.
-	m.stopTime = time.Now()
+	m.stopTime = m.clock.Now()
+	// a monitor of a window stopped after it does not evaluate what happened since.
+	if !m.windowEnd.IsZero() && m.windowEnd.Before(m.stopTime) {
+		m.stopTime = m.windowEnd
+	}
+	// in backfill mode the run began before the monitor did.
+	runStartTime := m.monitorTestRegistry.BackfilledBeginning(m.startTime)
 
 	fmt.Fprintf(os.Stderr, "Computing intervals.\n")
 	computedIntervals, computedJunit, err := m.monitorTestRegistry.ConstructComputedIntervals(
 		ctx,
 		m.recorder.Intervals(time.Time{}, time.Time{}), // compute intervals based on *all* the intervals.
 		m.recorder.CurrentResourceState(),
-		m.startTime, // still allow computation to understand the beginning and end for bounding.
-		m.stopTime)  // still allow computation to understand the beginning and end for bounding.
+		runStartTime, // still allow computation to understand the beginning and end for bounding.
+		m.stopTime)   // still allow computation to understand the beginning and end for bounding.
 	if err != nil {
 		// these errors are represented as junit, always continue to the next step
 		fmt.Fprintf(os.Stderr, "Error computing intervals, continuing, junit will reflect this. %v\n", err)
 	}
 	m.recorder.AddIntervals(computedIntervals...)
-	m.junits = append(m.junits, computedJunit...)
+	m.addJunits(computedJunit...)
 
 	fmt.Fprintf(os.Stderr, "Evaluating tests.\n")
-	finalEvents := m.recorder.Intervals(m.startTime, m.stopTime)
+	finalEvents := m.monitorTestRegistry.AnnotateIntervals(m.recorder.Intervals(runStartTime, m.stopTime))
 	filename := fmt.Sprintf("events_used_for_junits_%s.json", m.startTime.UTC().Format("20060102-150405"))
 	if err := monitorserialization.EventsToFile(filepath.Join(m.storageDir, filename), finalEvents); err != nil {
 		fmt.Fprintf(os.Stderr, "error: Failed to junit event info: %v\n", err)
@@ -127,7 +240,7 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 		// these errors are represented as junit, always continue to the next step
 		fmt.Fprintf(os.Stderr, "Error evaluating tests, continuing, junit will reflect this. %v\n", err)
 	}
-	m.junits = append(m.junits, monitorTestJunits...)
+	m.addJunits(monitorTestJunits...)
 
 	fmt.Fprintf(os.Stderr, "Cleaning up.\n")
 	cleanupJunits, err := m.monitorTestRegistry.Cleanup(ctx)
@@ -135,7 +248,10 @@ func (m *Monitor) Stop(ctx context.Context) (ResultState, error) {
 		// these errors are represented as junit, always continue to the next step
 		fmt.Fprintf(os.Stderr, "Error cleaning up, continuing, junit will reflect this. %v\n", err)
 	}
-	m.junits = append(m.junits, cleanupJunits...)
+	m.addJunits(cleanupJunits...)
+	m.addJunits(m.credentials.junits()...)
+	m.addJunits(m.watchHealth.junits()...)
+	m.addJunits(m.monitorTestRegistry.FailureBudgetPolicy().Apply(m.rateLimiter.junits())...)
 
 	successfulTestNames := sets.NewString()
 	failedTestNames := sets.NewString()
@@ -159,14 +275,20 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 	fmt.Fprintf(os.Stderr, "Serializing results.\n")
 	m.lock.Lock()
 	defer m.lock.Unlock()
+	defer m.endTracing(ctx)
+	defer m.endMetrics(ctx)
+	if m.span != nil {
+		ctx = trace.ContextWithSpan(ctx, m.span)
+	}
 
 	// We bound the intervals by the monitor stop/start time to limit the scope to when
 	// the monitors run (e.g., during upgrade phase and during e2e phase post upgrade).
 	// The tests will not be able to discover intervals outside of the current phase (e.g.,
 	// tests that check intervals for the e2e phase will not see intervals during upgrade
 	// phase and vice versa).  If it turns out visibility throughout the entire run yields
-	// useful testing, we can comeback and tweak this accordingly.
-	finalIntervals := m.recorder.Intervals(m.startTime, m.stopTime)
+	// useful testing, we can comeback and tweak this accordingly.  In backfill mode the bound starts before the
+	// monitor did, at the beginning of the run the monitor tests reconstructed.
+	finalIntervals := m.monitorTestRegistry.AnnotateIntervals(m.recorder.Intervals(m.monitorTestRegistry.BackfilledBeginning(m.startTime), m.stopTime))
 
 	finalResources := m.recorder.CurrentResourceState()
 	// TODO stop taking timesuffix as an arg and make this authoritative.
@@ -193,7 +315,7 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 		// these errors are represented as junit, always continue to the next step
 		fmt.Fprintf(os.Stderr, "Error writing to storage, continuing, junit will reflect this. %v\n", err)
 	}
-	m.junits = append(m.junits, monitorTestJunits...)
+	m.addJunits(monitorTestJunits...)
 
 	fmt.Fprintf(os.Stderr, "Writing junits.\n")
 	var junitSuite *junitapi.JUnitTestSuite
@@ -202,6 +324,15 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 		return err
 	}
 
+	fmt.Fprintf(os.Stderr, "Writing results summary.\n")
+	resultsSummary, err := BuildResultsSummary(junitSuiteName, m.junits, finalIntervals)
+	m.resultsSummary = resultsSummary
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: Unable to build results summary: %v\n", err)
+	} else if err := writeResultsSummary(m.storageDir, timeSuffix, resultsSummary); err != nil {
+		fmt.Fprintf(os.Stderr, "error: Unable to write results summary: %v\n", err)
+	}
+
 	if err := riskanalysis.WriteJobRunTestFailureSummary(m.storageDir, timeSuffix, junitSuite, "", "_monitor"); err != nil {
 		fmt.Fprintf(os.Stderr, "error: Unable to write e2e job run failures summary: %v", err)
 	}
@@ -209,6 +340,52 @@ func (m *Monitor) SerializeResults(ctx context.Context, junitSuiteName, timeSuff
 	return nil
 }
 
+// ResultsSummary returns the outcome of every monitor test once SerializeResults wrote it, or nil before.
+func (m *Monitor) ResultsSummary() *ResultsSummary {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.resultsSummary
+}
+
+// endTracing ends the monitor span and flushes any spans that have not been exported yet.
+func (m *Monitor) endTracing(ctx context.Context) {
+	if m.span != nil {
+		m.span.End()
+		m.span = nil
+	}
+	if m.shutdownTracing == nil {
+		return
+	}
+	if err := m.shutdownTracing(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "error: Unable to flush traces: %v\n", err)
+	}
+	m.shutdownTracing = nil
+}
+
+// addJunits records junits for the final results and for the live metrics.
+func (m *Monitor) addJunits(junits ...*junitapi.JUnitTestCase) {
+	m.junits = append(m.junits, junits...)
+	if m.metrics != nil {
+		m.metrics.recordJunits(junits...)
+	}
+}
+
+// endMetrics stops serving metrics and the live API.  The results have been written by then, so nothing is lost.
+func (m *Monitor) endMetrics(ctx context.Context) {
+	if m.stopMetricsServer != nil {
+		if err := m.stopMetricsServer(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "error: Unable to stop metrics server: %v\n", err)
+		}
+		m.stopMetricsServer = nil
+	}
+	if m.stopLiveAPIServer != nil {
+		if err := m.stopLiveAPIServer(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "error: Unable to stop live API server: %v\n", err)
+		}
+		m.stopLiveAPIServer = nil
+	}
+}
+
 func (m *Monitor) serializeJunit(ctx context.Context, storageDir, junitSuiteName, fileSuffix string) (*junitapi.JUnitTestSuite, error) {
 	junitSuite := junitapi.JUnitTestSuite{
 		Name:       junitSuiteName,
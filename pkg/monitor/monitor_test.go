@@ -104,7 +104,7 @@ func TestMonitor_Events(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			m := &Monitor{
 				recorder: &recorder{
-					events:            tt.events,
+					store:             monitorapi.NewIntervalStore(tt.events...),
 					recordedResources: monitorapi.ResourcesMap{},
 				},
 			}
@@ -273,6 +273,132 @@ func (b *LocatorBuilder) LocateServer(serverName, nodeName, namespace, podName s
 		Build()
 }
 
+// NetworkPath locates the connections of one kind of path, for instance pod-to-host, from one node to another.
+func (b *LocatorBuilder) NetworkPath(path, fromNodeName, toNodeName string) Locator {
+	b.targetType = LocatorTypeNetworkPath
+	b.annotations[LocatorNetworkPathKey] = path
+	b.annotations[LocatorToNodeKey] = toNodeName
+	return b.withNode(fromNodeName).Build()
+}
+
+// MachineConfigPool locates a pool of nodes the machine config operator updates together.
+func (b *LocatorBuilder) MachineConfigPool(name string) Locator {
+	b.targetType = LocatorTypeMachineConfigPool
+	b.annotations[LocatorMachineConfigPoolKey] = name
+	return b.Build()
+}
+
+// APIRequest locates the requests of a verb to a resource, named resource.group, or only resource for the core group.
+// The scope is resource, namespace, or cluster, and is left out when the requests of every scope are meant.
+func (b *LocatorBuilder) APIRequest(resource, verb, scope string) Locator {
+	b.targetType = LocatorTypeAPIRequest
+	b.annotations[LocatorResourceKey] = resource
+	b.annotations[LocatorVerbKey] = verb
+	if len(scope) > 0 {
+		b.annotations[LocatorScopeKey] = scope
+	}
+	return b.Build()
+}
+
+// StaticPodOperand locates the static pods a static pod operator, for instance kube-apiserver, installs.  The node is
+// left out for what concerns every node, like the rollout of a revision.
+func (b *LocatorBuilder) StaticPodOperand(operand, nodeName string) Locator {
+	b.targetType = LocatorTypeStaticPodOperand
+	b.annotations[LocatorStaticPodOperandKey] = operand
+	if len(nodeName) > 0 {
+		b.withNode(nodeName)
+	}
+	return b.Build()
+}
+
+// IngressTarget locates an endpoint clients outside the cluster reach through ingress, for instance the router canary.
+func (b *LocatorBuilder) IngressTarget(target string) Locator {
+	b.targetType = LocatorTypeIngressTarget
+	b.annotations[LocatorIngressTargetKey] = target
+	return b.Build()
+}
+
+// VolumePlugin locates the operations of a volume plugin, for instance kubernetes.io/csi:ebs.csi.aws.com.
+func (b *LocatorBuilder) VolumePlugin(plugin string) Locator {
+	b.targetType = LocatorTypeVolumePlugin
+	b.annotations[LocatorVolumePluginKey] = plugin
+	return b.Build()
+}
+
+// IntervalSource locates what concerns every interval of a source, for instance intervals dropped by a rate limit.
+func (b *LocatorBuilder) IntervalSource(source IntervalSource) Locator {
+	b.targetType = LocatorTypeIntervalSource
+	b.annotations[LocatorIntervalSourceKey] = string(source)
+	return b.Build()
+}
+
+// MetricRule locates the series of a metric rule past its threshold.  keys are read from the labels of the series, for
+// instance the node or namespace it is about.
+func (b *LocatorBuilder) MetricRule(rule string, keys map[LocatorKey]string) Locator {
+	b.targetType = LocatorTypeMetricRule
+	for key, value := range keys {
+		b.annotations[key] = value
+	}
+	b.annotations[LocatorMetricRuleKey] = rule
+	return b.Build()
+}
+
+// InjectedFault locates a fault injected by chaos tooling.
+func (b *LocatorBuilder) InjectedFault(name string) Locator {
+	b.targetType = LocatorTypeInjectedFault
+	b.annotations[LocatorInjectedFaultKey] = name
+	return b.Build()
+}
+
+// NodeProbe locates what a probe of the node agent checks on one node, for instance its disk latency.
+func (b *LocatorBuilder) NodeProbe(probe, nodeName string) Locator {
+	b.targetType = LocatorTypeNodeProbe
+	b.annotations[LocatorNodeProbeKey] = probe
+	return b.withNode(nodeName).Build()
+}
+
+// DNSProbe locates the resolution of one name, known as targetName, from one node.
+func (b *LocatorBuilder) DNSProbe(targetName, dnsName, nodeName string) Locator {
+	b.targetType = LocatorTypeDNSProbe
+	b.annotations[LocatorTargetKey] = targetName
+	b.annotations[LocatorDNSNameKey] = dnsName
+	return b.withNode(nodeName).Build()
+}
+
+func (b *LocatorBuilder) ImageStreamTag(namespace, imageStreamName, tag string) Locator {
+	b.targetType = LocatorTypeImageStream
+	b.annotations[LocatorImageStreamKey] = imageStreamName
+	b.annotations[LocatorTagKey] = tag
+	return b.withNamespace(namespace).Build()
+}
+
+func (b *LocatorBuilder) PDB(namespace, name string) Locator {
+	b.targetType = LocatorTypePDB
+	b.annotations[LocatorPDBKey] = name
+	return b.withNamespace(namespace).Build()
+}
+
+// Secret locates a secret the same way KubeEvent locates the events about one.
+func (b *LocatorBuilder) Secret(namespace, name string) Locator {
+	b.targetType = LocatorTypeKind
+	b.annotations[LocatorSecretKey] = name
+	return b.withNamespace(namespace).Build()
+}
+
+// KindInNamespace locates any namespaced object the same way KubeEvent locates the events about one.
+func (b *LocatorBuilder) KindInNamespace(kind, namespace, name string) Locator {
+	b.targetType = LocatorTypeKind
+	b.annotations[LocatorKey(strings.ToLower(kind))] = name
+	return b.withNamespace(namespace).Build()
+}
+
+// ClusterScopedKind locates any cluster scoped object, for instance a ClusterRole, the way KindInNamespace does.
+func (b *LocatorBuilder) ClusterScopedKind(kind, name string) Locator {
+	b.targetType = LocatorTypeKind
+	b.annotations[LocatorKey(strings.ToLower(kind))] = name
+	return b.Build()
+}
+
 func (b *LocatorBuilder) withServer(serverName string) *LocatorBuilder {
 	b.annotations[LocatorServerKey] = serverName
 	return b
@@ -0,0 +1,145 @@
+package monitorapi
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// IntervalStore holds intervals indexed by source and by locator key, so that finding the intervals of one source or
+// one node does not scan every interval of the run.  Intervals keep their insertion order in the store, the index
+// returned by Start stays valid until the interval is ended.  It is safe for concurrent use.
+type IntervalStore struct {
+	lock      sync.RWMutex
+	intervals Intervals
+
+	bySource     map[IntervalSource][]int
+	byLocatorKey map[LocatorKey]map[string][]int
+}
+
+// NewIntervalStore returns a store holding the intervals.  The monitor test registry indexes the starting intervals
+// this way once for every monitor test that constructs intervals from a store.
+func NewIntervalStore(intervals ...Interval) *IntervalStore {
+	s := &IntervalStore{
+		bySource:     map[IntervalSource][]int{},
+		byLocatorKey: map[LocatorKey]map[string][]int{},
+	}
+	s.Add(intervals...)
+	return s
+}
+
+// Add stores the intervals.
+func (s *IntervalStore) Add(intervals ...Interval) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, interval := range intervals {
+		s.addLocked(interval)
+	}
+}
+
+// Start stores an interval that is still going on and returns the index to End it with.
+func (s *IntervalStore) Start(interval Interval) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.addLocked(interval)
+}
+
+// End sets the To of the started interval if it is after its From, and returns a copy of the interval.  It returns
+// nil if there is no such interval.
+func (s *IntervalStore) End(startedInterval int, t time.Time) *Interval {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if startedInterval < 0 || startedInterval >= len(s.intervals) {
+		return nil
+	}
+	if s.intervals[startedInterval].From.Before(t) {
+		s.intervals[startedInterval].To = t
+	}
+	ret := s.intervals[startedInterval]
+	return &ret
+}
+
+func (s *IntervalStore) addLocked(interval Interval) int {
+	index := len(s.intervals)
+	s.intervals = append(s.intervals, interval)
+	s.bySource[interval.Source] = append(s.bySource[interval.Source], index)
+	for key, value := range interval.Locator.Keys {
+		values, ok := s.byLocatorKey[key]
+		if !ok {
+			values = map[string][]int{}
+			s.byLocatorKey[key] = values
+		}
+		values[value] = append(values[value], index)
+	}
+	return index
+}
+
+// Len returns how many intervals are stored.
+func (s *IntervalStore) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.intervals)
+}
+
+// Intervals returns a sorted copy of the intervals between from and to.  Zero times leave the range open.
+func (s *IntervalStore) Intervals(from, to time.Time) Intervals {
+	s.lock.RLock()
+	ret := make(Intervals, len(s.intervals))
+	copy(ret, s.intervals)
+	s.lock.RUnlock()
+	return sortedSlice(ret, from, to)
+}
+
+// BySource returns a sorted copy of the intervals of the source between from and to.
+func (s *IntervalStore) BySource(source IntervalSource, from, to time.Time) Intervals {
+	s.lock.RLock()
+	ret := s.collectLocked(s.bySource[source], nil)
+	s.lock.RUnlock()
+	return sortedSlice(ret, from, to)
+}
+
+// ByLocatorKey returns a sorted copy of the intervals whose locator has the key set to value, for instance every
+// interval of a node, between from and to.
+func (s *IntervalStore) ByLocatorKey(key LocatorKey, value string, from, to time.Time) Intervals {
+	s.lock.RLock()
+	ret := s.collectLocked(s.byLocatorKey[key][value], nil)
+	s.lock.RUnlock()
+	return sortedSlice(ret, from, to)
+}
+
+// BySourceAndLocatorKey returns a sorted copy of the intervals of the source whose locator has the key set to value,
+// between from and to.
+func (s *IntervalStore) BySourceAndLocatorKey(source IntervalSource, key LocatorKey, value string, from, to time.Time) Intervals {
+	s.lock.RLock()
+	bySource, byKey := s.bySource[source], s.byLocatorKey[key][value]
+	var ret Intervals
+	// walk the smaller index and check the other condition on each interval.
+	if len(bySource) < len(byKey) {
+		ret = s.collectLocked(bySource, func(interval Interval) bool {
+			actual, ok := interval.Locator.Keys[key]
+			return ok && actual == value
+		})
+	} else {
+		ret = s.collectLocked(byKey, func(interval Interval) bool {
+			return interval.Source == source
+		})
+	}
+	s.lock.RUnlock()
+	return sortedSlice(ret, from, to)
+}
+
+func (s *IntervalStore) collectLocked(indexes []int, matches func(Interval) bool) Intervals {
+	ret := make(Intervals, 0, len(indexes))
+	for _, index := range indexes {
+		if matches != nil && !matches(s.intervals[index]) {
+			continue
+		}
+		ret = append(ret, s.intervals[index])
+	}
+	return ret
+}
+
+func sortedSlice(intervals Intervals, from, to time.Time) Intervals {
+	sort.Sort(intervals)
+	return intervals.Slice(from, to)
+}
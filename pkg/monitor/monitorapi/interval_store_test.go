@@ -0,0 +1,90 @@
+package monitorapi
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func storeTestInterval(source IntervalSource, node string, from, to time.Time) Interval {
+	return NewInterval(source, Info).
+		Locator(NewLocator().NodeFromName(node)).
+		Message(NewMessage().HumanMessage(fmt.Sprintf("%s on %s", source, node))).
+		Build(from, to)
+}
+
+func TestIntervalStore(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewIntervalStore(
+		storeTestInterval(SourceKubeletLog, "node-b", start.Add(3*time.Minute), start.Add(4*time.Minute)),
+		storeTestInterval(SourceKubeletLog, "node-a", start.Add(time.Minute), start.Add(2*time.Minute)),
+		storeTestInterval(SourceNodeMonitor, "node-a", start.Add(5*time.Minute), start.Add(6*time.Minute)),
+	)
+	if store.Len() != 3 {
+		t.Fatalf("expected 3 intervals, got %d", store.Len())
+	}
+
+	kubeletLogs := store.BySource(SourceKubeletLog, time.Time{}, time.Time{})
+	if len(kubeletLogs) != 2 || kubeletLogs[0].Locator.Keys[LocatorNodeKey] != "node-a" {
+		t.Errorf("expected both kubelet logs sorted by time, got %v", kubeletLogs)
+	}
+	if inRange := store.BySource(SourceKubeletLog, start.Add(150*time.Second), time.Time{}); len(inRange) != 1 {
+		t.Errorf("expected a single kubelet log after 2m30s, got %v", inRange)
+	}
+	if nodeA := store.ByLocatorKey(LocatorNodeKey, "node-a", time.Time{}, time.Time{}); len(nodeA) != 2 {
+		t.Errorf("expected two intervals of node-a, got %v", nodeA)
+	}
+	if both := store.BySourceAndLocatorKey(SourceNodeMonitor, LocatorNodeKey, "node-a", time.Time{}, time.Time{}); len(both) != 1 {
+		t.Errorf("expected a single node monitor interval of node-a, got %v", both)
+	}
+	if missing := store.ByLocatorKey(LocatorNodeKey, "node-c", time.Time{}, time.Time{}); len(missing) != 0 {
+		t.Errorf("expected no intervals of node-c, got %v", missing)
+	}
+
+	all := store.Intervals(time.Time{}, start.Add(150*time.Second))
+	if len(all) != 1 || all[0].Locator.Keys[LocatorNodeKey] != "node-a" {
+		t.Errorf("expected the first interval only, got %v", all)
+	}
+	// sorting a result must not reorder the store.
+	all[0].Message.HumanMessage = "changed"
+	if store.Intervals(time.Time{}, time.Time{})[0].Message.HumanMessage == "changed" {
+		t.Errorf("expected results to be copies")
+	}
+}
+
+func TestIntervalStoreStartEnd(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewIntervalStore(storeTestInterval(SourceKubeletLog, "node-a", start, start))
+	index := store.Start(storeTestInterval(SourceNodeMonitor, "node-b", start.Add(time.Minute), time.Time{}))
+	store.Add(storeTestInterval(SourceKubeletLog, "node-c", start, start))
+
+	ended := store.End(index, start.Add(2*time.Minute))
+	if ended == nil || !ended.To.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("expected the started interval to end at 2m, got %v", ended)
+	}
+	if nodeB := store.ByLocatorKey(LocatorNodeKey, "node-b", time.Time{}, time.Time{}); len(nodeB) != 1 || !nodeB[0].To.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected the index to see the ended interval, got %v", nodeB)
+	}
+	if ended := store.End(index, start); ended == nil || !ended.To.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected an end before the start to be ignored, got %v", ended)
+	}
+	if ended := store.End(10, start); ended != nil {
+		t.Errorf("expected no interval at an unknown index, got %v", ended)
+	}
+}
+
+func BenchmarkIntervalStoreBySource(b *testing.B) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewIntervalStore()
+	for i := 0; i < 100000; i++ {
+		source := SourceKubeEvent
+		if i%1000 == 0 {
+			source = SourceKubeletLog
+		}
+		store.Add(storeTestInterval(source, fmt.Sprintf("node-%d", i%20), start.Add(time.Duration(i)*time.Second), start.Add(time.Duration(i)*time.Second)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store.BySource(SourceKubeletLog, time.Time{}, time.Time{})
+	}
+}
@@ -0,0 +1,215 @@
+package monitorapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// LocatorKeyIn matches intervals whose locator has the key set to one of the values.  Values may contain
+// wildcards, see LocatorKeyMatches.
+func LocatorKeyIn(key LocatorKey, values ...string) EventIntervalMatchesFunc {
+	exact := sets.NewString()
+	wildcards := []*regexp.Regexp{}
+	for _, value := range values {
+		if !hasWildcard(value) {
+			exact.Insert(value)
+			continue
+		}
+		wildcards = append(wildcards, wildcardToRegexp(value))
+	}
+
+	return func(eventInterval Interval) bool {
+		actual, ok := eventInterval.Locator.Keys[key]
+		if !ok {
+			return false
+		}
+		if exact.Has(actual) {
+			return true
+		}
+		for _, wildcard := range wildcards {
+			if wildcard.MatchString(actual) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LocatorKeyNotIn matches intervals whose locator does not have the key, or has it set to none of the values.
+func LocatorKeyNotIn(key LocatorKey, values ...string) EventIntervalMatchesFunc {
+	return Not(LocatorKeyIn(key, values...))
+}
+
+// LocatorKeyMatches matches intervals whose locator has the key set to a value matching the pattern.
+// The pattern is a literal, except for '*' which matches any sequence of characters and '?' which
+// matches any single character.
+func LocatorKeyMatches(key LocatorKey, pattern string) EventIntervalMatchesFunc {
+	return LocatorKeyIn(key, pattern)
+}
+
+// LocatorKeyExists matches intervals whose locator has the key, regardless of the value.
+func LocatorKeyExists(key LocatorKey) EventIntervalMatchesFunc {
+	return func(eventInterval Interval) bool {
+		_, ok := eventInterval.Locator.Keys[key]
+		return ok
+	}
+}
+
+// LocatorTypeIn matches intervals whose locator has one of the types.
+func LocatorTypeIn(locatorTypes ...LocatorType) EventIntervalMatchesFunc {
+	return func(eventInterval Interval) bool {
+		for _, locatorType := range locatorTypes {
+			if eventInterval.Locator.Type == locatorType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ParseLocatorSelector parses a comma separated list of requirements on locator keys, all of which must match.
+// The syntax follows label selectors, with values that may contain wildcards:
+//
+//	key=value, key==value, key!=value
+//	key in (value1,value2), key notin (value1,value2)
+//	key, !key
+//
+// For instance: "namespace in (openshift-etcd,openshift-kube-apiserver),pod=etcd-*"
+// The special key "type" matches the locator type.
+func ParseLocatorSelector(selector string) (EventIntervalMatchesFunc, error) {
+	requirements, err := splitSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(requirements) == 0 {
+		return nil, fmt.Errorf("empty locator selector")
+	}
+
+	matchers := []EventIntervalMatchesFunc{}
+	for _, requirement := range requirements {
+		matcher, err := parseLocatorRequirement(requirement)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locator selector %q: %w", selector, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return And(matchers...), nil
+}
+
+// splitSelector splits on commas that are not inside parentheses.
+func splitSelector(selector string) ([]string, error) {
+	ret := []string{}
+	depth := 0
+	start := 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in locator selector %q", selector)
+			}
+		case ',':
+			if depth == 0 {
+				ret = appendNonEmpty(ret, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in locator selector %q", selector)
+	}
+	return appendNonEmpty(ret, selector[start:]), nil
+}
+
+func appendNonEmpty(list []string, value string) []string {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return list
+	}
+	return append(list, value)
+}
+
+func parseLocatorRequirement(requirement string) (EventIntervalMatchesFunc, error) {
+	if fields := strings.Fields(requirement); len(fields) >= 2 && (fields[1] == "in" || fields[1] == "notin") {
+		values, err := parseValueSet(strings.TrimSpace(strings.Join(fields[2:], " ")))
+		if err != nil {
+			return nil, err
+		}
+		matcher := keyMatcher(fields[0], values)
+		if fields[1] == "notin" {
+			return Not(matcher), nil
+		}
+		return matcher, nil
+	}
+
+	for _, operator := range []string{"!=", "==", "="} {
+		if idx := strings.Index(requirement, operator); idx >= 0 {
+			key := strings.TrimSpace(requirement[:idx])
+			value := strings.TrimSpace(requirement[idx+len(operator):])
+			if len(key) == 0 {
+				return nil, fmt.Errorf("missing key in %q", requirement)
+			}
+			matcher := keyMatcher(key, []string{value})
+			if operator == "!=" {
+				return Not(matcher), nil
+			}
+			return matcher, nil
+		}
+	}
+
+	if strings.ContainsAny(requirement, " ()") {
+		return nil, fmt.Errorf("unable to parse %q", requirement)
+	}
+	if strings.HasPrefix(requirement, "!") {
+		return Not(LocatorKeyExists(LocatorKey(strings.TrimPrefix(requirement, "!")))), nil
+	}
+	return LocatorKeyExists(LocatorKey(requirement)), nil
+}
+
+func parseValueSet(valueSet string) ([]string, error) {
+	if !strings.HasPrefix(valueSet, "(") || !strings.HasSuffix(valueSet, ")") {
+		return nil, fmt.Errorf("set of values must be in parentheses, got %q", valueSet)
+	}
+	values := []string{}
+	for _, value := range strings.Split(valueSet[1:len(valueSet)-1], ",") {
+		values = appendNonEmpty(values, value)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("set of values must not be empty")
+	}
+	return values, nil
+}
+
+func keyMatcher(key string, values []string) EventIntervalMatchesFunc {
+	if key != "type" {
+		return LocatorKeyIn(LocatorKey(key), values...)
+	}
+	patterns := []*regexp.Regexp{}
+	for _, value := range values {
+		patterns = append(patterns, wildcardToRegexp(value))
+	}
+	return func(eventInterval Interval) bool {
+		for _, pattern := range patterns {
+			if pattern.MatchString(string(eventInterval.Locator.Type)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func hasWildcard(value string) bool {
+	return strings.ContainsAny(value, "*?")
+}
+
+func wildcardToRegexp(pattern string) *regexp.Regexp {
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.ReplaceAll(expression, `\*`, ".*")
+	expression = strings.ReplaceAll(expression, `\?`, ".")
+	return regexp.MustCompile("^" + expression + "$")
+}
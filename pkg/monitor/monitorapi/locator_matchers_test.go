@@ -0,0 +1,71 @@
+package monitorapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocatorSelector(t *testing.T) {
+	etcdPod := Interval{Condition: Condition{Locator: Locator{
+		Type: LocatorTypePod,
+		Keys: map[LocatorKey]string{LocatorNamespaceKey: "openshift-etcd", LocatorPodKey: "etcd-master-0"},
+	}}}
+	apiserverPod := Interval{Condition: Condition{Locator: Locator{
+		Type: LocatorTypePod,
+		Keys: map[LocatorKey]string{LocatorNamespaceKey: "openshift-kube-apiserver", LocatorPodKey: "kube-apiserver-master-0"},
+	}}}
+	node := Interval{Condition: Condition{Locator: Locator{
+		Type: LocatorTypeNode,
+		Keys: map[LocatorKey]string{LocatorNodeKey: "master-0"},
+	}}}
+	all := []Interval{etcdPod, apiserverPod, node}
+
+	tests := []struct {
+		name     string
+		selector string
+		want     []bool
+		wantErr  bool
+	}{
+		{name: "equals", selector: "namespace=openshift-etcd", want: []bool{true, false, false}},
+		{name: "double equals", selector: "namespace==openshift-etcd", want: []bool{true, false, false}},
+		{name: "not equals", selector: "namespace!=openshift-etcd", want: []bool{false, true, true}},
+		{name: "set", selector: "namespace in (openshift-etcd, openshift-kube-apiserver)", want: []bool{true, true, false}},
+		{name: "not in set", selector: "namespace notin (openshift-etcd)", want: []bool{false, true, true}},
+		{name: "wildcard", selector: "pod=*-master-?", want: []bool{true, true, false}},
+		{name: "wildcard in set", selector: "namespace in (openshift-kube-*),pod", want: []bool{false, true, false}},
+		{name: "exists", selector: "node", want: []bool{false, false, true}},
+		{name: "does not exist", selector: "!node", want: []bool{true, true, false}},
+		{name: "type", selector: "type=Node", want: []bool{false, false, true}},
+		{name: "regex characters are literal", selector: "pod=etcd-master.0", want: []bool{false, false, false}},
+		{name: "empty", selector: " , ", wantErr: true},
+		{name: "unbalanced", selector: "namespace in (a,b", wantErr: true},
+		{name: "missing parentheses", selector: "namespace in a", wantErr: true},
+		{name: "missing key", selector: "=a", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := ParseLocatorSelector(tt.selector)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			for i, interval := range all {
+				assert.Equal(t, tt.want[i], matcher(interval), "interval %d: %v", i, interval.Locator.OldLocator())
+			}
+		})
+	}
+}
+
+func TestLocatorKeyIn(t *testing.T) {
+	interval := Interval{Condition: Condition{Locator: Locator{
+		Keys: map[LocatorKey]string{LocatorNamespaceKey: "openshift-etcd"},
+	}}}
+	assert.True(t, LocatorKeyIn(LocatorNamespaceKey, "openshift-etcd", "openshift-kube-apiserver")(interval))
+	assert.True(t, LocatorKeyIn(LocatorNamespaceKey, "openshift-*")(interval))
+	assert.False(t, LocatorKeyIn(LocatorNodeKey, "*")(interval))
+	assert.True(t, LocatorKeyNotIn(LocatorNodeKey, "*")(interval))
+}
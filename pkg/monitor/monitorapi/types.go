@@ -2,6 +2,7 @@ package monitorapi
 
 import (
 	"fmt"
+	"hash/fnv"
 	"regexp"
 	"sort"
 	"strconv"
@@ -48,6 +49,10 @@ const (
 	// time a resource has been recreated.  The internal cache doesn't remove an entry on delete.
 	// This is useful during post-processing for determining if we have a hot resource.
 	ObservedRecreationCountAnnotation = "monitor.openshift.io/observed-recreation-count"
+
+	// StatusTruncatedAnnotation is an annotation added locally (in the monitor only) to a resource whose status was
+	// dropped before it was recorded because it was too large.  Its value is the serialized size of the status.
+	StatusTruncatedAnnotation = "monitor.openshift.io/status-truncated-bytes"
 )
 
 type IntervalLevel int
@@ -95,18 +100,31 @@ type Condition struct {
 type LocatorType string
 
 const (
-	LocatorTypePod             LocatorType = "Pod"
-	LocatorTypeContainer       LocatorType = "Container"
-	LocatorTypeNode            LocatorType = "Node"
-	LocatorTypeAlert           LocatorType = "Alert"
-	LocatorTypeClusterOperator LocatorType = "ClusterOperator"
-	LocatorTypeDisruption      LocatorType = "Disruption"
-	LocatorTypeKubeEvent       LocatorType = "KubeEvent"
-	LocatorTypeE2ETest         LocatorType = "E2ETest"
-	LocatorTypeAPIServer       LocatorType = "APIServer"
-	LocatorTypeClusterVersion  LocatorType = "ClusterVersion"
-	LocatorTypeKind            LocatorType = "Kind"
-	LocatorTypeCloudMetrics    LocatorType = "CloudMetrics"
+	LocatorTypePod               LocatorType = "Pod"
+	LocatorTypeContainer         LocatorType = "Container"
+	LocatorTypeNode              LocatorType = "Node"
+	LocatorTypeAlert             LocatorType = "Alert"
+	LocatorTypeClusterOperator   LocatorType = "ClusterOperator"
+	LocatorTypeDisruption        LocatorType = "Disruption"
+	LocatorTypeKubeEvent         LocatorType = "KubeEvent"
+	LocatorTypeE2ETest           LocatorType = "E2ETest"
+	LocatorTypeAPIServer         LocatorType = "APIServer"
+	LocatorTypeClusterVersion    LocatorType = "ClusterVersion"
+	LocatorTypeKind              LocatorType = "Kind"
+	LocatorTypeCloudMetrics      LocatorType = "CloudMetrics"
+	LocatorTypeDNSProbe          LocatorType = "DNSProbe"
+	LocatorTypeImageStream       LocatorType = "ImageStream"
+	LocatorTypePDB               LocatorType = "PodDisruptionBudget"
+	LocatorTypeNetworkPath       LocatorType = "NetworkPath"
+	LocatorTypeVolumePlugin      LocatorType = "VolumePlugin"
+	LocatorTypeIngressTarget     LocatorType = "IngressTarget"
+	LocatorTypeMachineConfigPool LocatorType = "MachineConfigPool"
+	LocatorTypeAPIRequest        LocatorType = "APIRequest"
+	LocatorTypeStaticPodOperand  LocatorType = "StaticPodOperand"
+	LocatorTypeIntervalSource    LocatorType = "IntervalSource"
+	LocatorTypeMetricRule        LocatorType = "MetricRule"
+	LocatorTypeNodeProbe         LocatorType = "NodeProbe"
+	LocatorTypeInjectedFault     LocatorType = "InjectedFault"
 )
 
 type LocatorKey string
@@ -137,6 +155,27 @@ const (
 	LocatorRowKey                   LocatorKey = "row"
 	LocatorServerKey                LocatorKey = "server"
 	LocatorMetricKey                LocatorKey = "metric"
+	LocatorDNSNameKey               LocatorKey = "dns-name"
+	LocatorImageStreamKey           LocatorKey = "imagestream"
+	LocatorTagKey                   LocatorKey = "tag"
+	LocatorPDBKey                   LocatorKey = "pdb"
+	LocatorSecretKey                LocatorKey = "secret"
+	LocatorNetworkPathKey           LocatorKey = "network-path"
+	LocatorToNodeKey                LocatorKey = "to-node"
+	LocatorVolumePluginKey          LocatorKey = "volume-plugin"
+	LocatorIngressTargetKey         LocatorKey = "ingress-target"
+	LocatorMachineConfigPoolKey     LocatorKey = "mcp"
+	LocatorResourceKey              LocatorKey = "resource"
+	LocatorVerbKey                  LocatorKey = "verb"
+	LocatorScopeKey                 LocatorKey = "scope"
+	LocatorStaticPodOperandKey      LocatorKey = "static-pod-operand"
+	LocatorIntervalSourceKey        LocatorKey = "interval-source"
+	LocatorMetricRuleKey            LocatorKey = "metric-rule"
+	LocatorNodeProbeKey             LocatorKey = "node-probe"
+	LocatorInjectedFaultKey         LocatorKey = "injected-fault"
+	// LocatorClusterKey names the secondary cluster an interval was observed on.  Intervals of the cluster under test
+	// do not have it.
+	LocatorClusterKey LocatorKey = "cluster"
 )
 
 type Locator struct {
@@ -179,8 +218,14 @@ const (
 	ContainerReasonReady              IntervalReason = "Ready"
 	ContainerReasonRestarted          IntervalReason = "Restarted"
 	ContainerReasonNotReady           IntervalReason = "NotReady"
+	ContainerReasonRestarting         IntervalReason = "Restarting"
 	TerminationStateCleared           IntervalReason = "TerminationStateCleared"
 
+	// ContainerReasonKilling is the kubelet asking the runtime to stop a container within its grace period.
+	ContainerReasonKilling IntervalReason = "Killing"
+	// ContainerReasonUngracefulTermination is a container killed with SIGKILL instead of exiting on its own.
+	ContainerReasonUngracefulTermination IntervalReason = "UngracefulTermination"
+
 	PodReasonDeletedBeforeScheduling IntervalReason = "DeletedBeforeScheduling"
 	PodReasonDeletedAfterCompletion  IntervalReason = "DeletedAfterCompletion"
 
@@ -188,6 +233,10 @@ const (
 	NodeNotReadyReason IntervalReason = "NotReady"
 	NodeFailedLease    IntervalReason = "FailedToUpdateLease"
 
+	KubeletStartedReason     IntervalReason = "KubeletStarted"
+	KubeletStoppedReason     IntervalReason = "KubeletStopped"
+	NodeKubeletRestartReason IntervalReason = "KubeletRestart"
+
 	MachineConfigChangeReason  IntervalReason = "MachineConfigChange"
 	MachineConfigReachedReason IntervalReason = "MachineConfigReached"
 
@@ -196,10 +245,117 @@ const (
 	E2ETestStarted  IntervalReason = "E2ETestStarted"
 	E2ETestFinished IntervalReason = "E2ETestFinished"
 
+	LoadGeneratorStarted IntervalReason = "LoadGeneratorStarted"
+	LoadGeneratorStopped IntervalReason = "LoadGeneratorStopped"
+
 	CloudMetricsExtrenuous                IntervalReason = "CloudMetricsExtrenuous"
 	FailedToDeleteCGroupsPath             IntervalReason = "FailedToDeleteCGroupsPath"
 	FailedToAuthenticateWithOpenShiftUser IntervalReason = "FailedToAuthenticateWithOpenShiftUser"
 	FailedContactingAPIReason             IntervalReason = "FailedContactingAPI"
+
+	MonitorCredentialsExpired IntervalReason = "MonitorCredentialsExpired"
+
+	MonitorWatchStreamDisconnected IntervalReason = "MonitorWatchStreamDisconnected"
+	MonitorWatchStreamResynced     IntervalReason = "MonitorWatchStreamResynced"
+	MonitorWatchStreamStalled      IntervalReason = "MonitorWatchStreamStalled"
+
+	IntervalsRateLimited IntervalReason = "IntervalsRateLimited"
+
+	EtcdLeaderElectionReason      IntervalReason = "EtcdLeaderElection"
+	EtcdQuorumDegradedReason      IntervalReason = "EtcdQuorumDegraded"
+	EtcdQuorumLostReason          IntervalReason = "EtcdQuorumLost"
+	EtcdMemberWithoutLeaderReason IntervalReason = "EtcdMemberWithoutLeader"
+	EtcdSlowFsyncReason           IntervalReason = "EtcdSlowFsync"
+	EtcdSlowCompactionReason      IntervalReason = "EtcdSlowCompaction"
+	EtcdBackupCompletedReason     IntervalReason = "EtcdBackupCompleted"
+	EtcdBackupFailedReason        IntervalReason = "EtcdBackupFailed"
+
+	StaticPodRevisionRolloutReason    IntervalReason = "StaticPodRevisionRollout"
+	StaticPodNodeInstallReason        IntervalReason = "StaticPodNodeInstall"
+	StaticPodInstallFailedReason      IntervalReason = "StaticPodInstallFailed"
+	StaticPodInstallerPodFailedReason IntervalReason = "StaticPodInstallerPodFailed"
+	StaticPodPrunerPodFailedReason    IntervalReason = "StaticPodPrunerPodFailed"
+
+	ImagePullSlowReason       IntervalReason = "ImagePullSlow"
+	ImageMirrorFallbackReason IntervalReason = "ImageMirrorFallback"
+
+	ClusterUpdatePhaseReason IntervalReason = "ClusterUpdatePhase"
+
+	AlertmanagerNotificationPathBrokenReason IntervalReason = "AlertmanagerNotificationPathBroken"
+
+	NodeClockSkewReason           IntervalReason = "NodeClockSkew"
+	NodeClockUnsynchronizedReason IntervalReason = "NodeClockUnsynchronized"
+
+	MetricThresholdCrossedReason IntervalReason = "MetricThresholdCrossed"
+
+	NodeDiskLatencyHighReason    IntervalReason = "DiskLatencyHigh"
+	NodeConntrackSaturatedReason IntervalReason = "ConntrackSaturated"
+	NodeLocalDNSFailedReason     IntervalReason = "LocalDNSFailed"
+
+	FaultInjectedReason IntervalReason = "FaultInjected"
+
+	SecurityConfigDriftReason IntervalReason = "SecurityConfigDrift"
+
+	MultusAttachFailedReason IntervalReason = "MultusAttachFailed"
+	MultusDetachFailedReason IntervalReason = "MultusDetachFailed"
+
+	CommandSucceededReason IntervalReason = "CommandSucceeded"
+	CommandFailedReason    IntervalReason = "CommandFailed"
+
+	AuditTooManyRequestsStormReason IntervalReason = "TooManyRequestsStorm"
+	AuditForbiddenSpikeReason       IntervalReason = "ForbiddenSpike"
+
+	DNSResolutionFailedReason IntervalReason = "DNSResolutionFailed"
+
+	ImageRegistryUnavailableReason IntervalReason = "ImageRegistryUnavailable"
+	ImagePullFailedReason          IntervalReason = "ImagePullFailed"
+	ImageStreamImportFailedReason  IntervalReason = "ImageStreamImportFailed"
+
+	PDBBelowMinAvailableReason        IntervalReason = "BelowMinAvailable"
+	PDBViolatedDuringNodeUpdateReason IntervalReason = "ViolatedDuringNodeUpdate"
+
+	CertificateRotatedReason IntervalReason = "CertificateRotated"
+
+	NetworkPathOutageReason IntervalReason = "NetworkPathOutage"
+
+	VolumeOperationStuckReason IntervalReason = "VolumeOperationStuck"
+	VolumeOperationSlowReason  IntervalReason = "VolumeOperationSlow"
+
+	IngressUnreachableReason IntervalReason = "IngressUnreachable"
+
+	CatalogSourceNotReadyReason        IntervalReason = "CatalogSourceNotReady"
+	SubscriptionResolutionFailedReason IntervalReason = "ResolutionFailed"
+	InstallPlanPendingReason           IntervalReason = "InstallPlanPending"
+	InstallPlanFailedReason            IntervalReason = "InstallPlanFailed"
+
+	MachineConfigPoolConditionChangedReason IntervalReason = "PoolConditionChanged"
+	MachineConfigPoolUpdatingReason         IntervalReason = "PoolUpdating"
+	MachineConfigPoolDegradedReason         IntervalReason = "PoolDegraded"
+	MachineConfigDaemonPhaseReason          IntervalReason = "MachineConfigDaemonPhase"
+	MachineConfigDaemonUpdateReason         IntervalReason = "MachineConfigDaemonUpdate"
+
+	LeaderChangedReason IntervalReason = "LeaderChanged"
+
+	APIRequestSlowReason         IntervalReason = "APIRequestSlow"
+	APIRequestServerErrorsReason IntervalReason = "APIRequestServerErrors"
+
+	ResourceLeakedReason        IntervalReason = "ResourceLeaked"
+	ResourceStuckDeletingReason IntervalReason = "ResourceStuckDeleting"
+
+	NodeCPUSaturatedReason    IntervalReason = "NodeCPUSaturated"
+	NodeMemorySaturatedReason IntervalReason = "NodeMemorySaturated"
+
+	CloudAPIThrottledReason  IntervalReason = "CloudAPIThrottled"
+	CloudQuotaExceededReason IntervalReason = "CloudQuotaExceeded"
+
+	HostedClusterUnavailableReason          IntervalReason = "HostedClusterUnavailable"
+	HostedClusterDegradedReason             IntervalReason = "HostedClusterDegraded"
+	ControlPlaneDeploymentUnavailableReason IntervalReason = "ControlPlaneDeploymentUnavailable"
+	ControlPlaneReplicasUnavailableReason   IntervalReason = "ControlPlaneReplicasUnavailable"
+	ControlPlanePodNotReadyReason           IntervalReason = "ControlPlanePodNotReady"
+
+	NodePoolUpdatedReason         IntervalReason = "NodePoolUpdated"
+	ControlPlaneUnavailableReason IntervalReason = "ControlPlaneUnavailable"
 )
 
 type AnnotationKey string
@@ -231,8 +387,46 @@ const (
 	AnnotationRoles          AnnotationKey = "roles"
 	AnnotationStatus         AnnotationKey = "status"
 	AnnotationCondition      AnnotationKey = "condition"
+	AnnotationUnit           AnnotationKey = "unit"
+	AnnotationOperation      AnnotationKey = "operation"
+	AnnotationHolder         AnnotationKey = "holder"
+	AnnotationPreviousHolder AnnotationKey = "prev-holder"
+	AnnotationErrorRatio     AnnotationKey = "error-ratio"
+	AnnotationFinalizers     AnnotationKey = "finalizers"
+	AnnotationUtilization    AnnotationKey = "utilization"
+	AnnotationGracePeriod    AnnotationKey = "grace-period"
+	AnnotationRevision       AnnotationKey = "revision"
+	AnnotationNetwork        AnnotationKey = "network"
+	AnnotationAttempts       AnnotationKey = "attempts"
+	// AnnotationPeak is the most extreme value a metric reached while it was past its threshold.
+	AnnotationPeak AnnotationKey = "peak"
+	// AnnotationClockSkew is added to intervals located on a node whose clock was off when they began.
+	AnnotationClockSkew AnnotationKey = "clock-skew"
+	// AnnotationConfidence is set to "low" on intervals recorded while the monitor could not authenticate to the
+	// cluster, because those intervals may be missing the data that explains them.
+	AnnotationConfidence AnnotationKey = "confidence"
+	// AnnotationBackfilled is set to "true" on intervals reconstructed from the history of the cluster for the part of
+	// the run before the monitor started, rather than observed as they happened.
+	AnnotationBackfilled AnnotationKey = "backfilled"
+	// AnnotationFaultKind, AnnotationFaultNodes, AnnotationFaultNamespaces, and AnnotationFaultGrace describe an
+	// injected fault: what it was, the comma separated nodes and namespaces it targeted, and how long after it ended
+	// its symptoms are expected.
+	AnnotationFaultKind       AnnotationKey = "fault-kind"
+	AnnotationFaultNodes      AnnotationKey = "fault-nodes"
+	AnnotationFaultNamespaces AnnotationKey = "fault-namespaces"
+	AnnotationFaultGrace      AnnotationKey = "fault-grace"
+	// AnnotationExcusedBy names the injected fault that explains an interval.  Excused intervals are not evaluated by
+	// invariant tests.
+	AnnotationExcusedBy AnnotationKey = "excused-by"
+	// AnnotationDuringLoad names the namespace of the load generator that was running when a warning or error
+	// began.  Disruption and latency evaluation do not count these intervals against their budgets.
+	AnnotationDuringLoad AnnotationKey = "during-load"
+	// AnnotationContributor names the test binary outside of openshift-tests that contributed an interval.
+	AnnotationContributor AnnotationKey = "contributor"
 )
 
+const LowConfidence = "low"
+
 // ConstructionOwner was originally meant to signify that an interval was derived from other intervals.
 // This allowed for the possibility of testing interval generation by feeding in only source intervals,
 // and checking what was generated.
@@ -243,9 +437,23 @@ const (
 type ConstructionOwner string
 
 const (
-	ConstructionOwnerNodeLifecycle = "node-lifecycle-constructor"
-	ConstructionOwnerPodLifecycle  = "pod-lifecycle-constructor"
-	ConstructionOwnerEtcdLifecycle = "etcd-lifecycle-constructor"
+	ConstructionOwnerNodeLifecycle  = "node-lifecycle-constructor"
+	ConstructionOwnerPodLifecycle   = "pod-lifecycle-constructor"
+	ConstructionOwnerEtcdLifecycle  = "etcd-lifecycle-constructor"
+	ConstructionOwnerLoadGenerator  = "load-generator-constructor"
+	ConstructionOwnerRepeatedEvent  = "repeated-event-constructor"
+	ConstructionOwnerImageRegistry  = "image-registry-constructor"
+	ConstructionOwnerPDB            = "pdb-constructor"
+	ConstructionOwnerNetworkMesh    = "network-mesh-constructor"
+	ConstructionOwnerVolumeOps      = "volume-operation-constructor"
+	ConstructionOwnerIngress        = "ingress-reachability-constructor"
+	ConstructionOwnerMachineConfig  = "machine-config-rollout-constructor"
+	ConstructionOwnerResourceLeaks  = "resource-leak-constructor"
+	ConstructionOwnerTermination    = "termination-grace-constructor"
+	ConstructionOwnerCloudThrottle  = "cloud-throttling-constructor"
+	ConstructionOwnerImagePull      = "image-pull-constructor"
+	ConstructionOwnerUpdatePhase    = "cluster-update-phase-constructor"
+	ConstructionOwnerUpdateTopology = "update-topology-constructor"
 )
 
 type Message struct {
@@ -287,6 +495,45 @@ const (
 	SourceNodeState                              = "NodeState"
 	SourcePodState                               = "PodState"
 	SourceCloudMetrics                           = "CloudMetrics"
+	SourceLoadGenerator           IntervalSource = "LoadGenerator"
+	SourceMonitorCredentials      IntervalSource = "MonitorCredentials"
+	SourceEtcdHealth              IntervalSource = "EtcdHealth"
+	SourceAuditLog                IntervalSource = "AuditLog"
+	SourceDNSProbe                IntervalSource = "DNSProbe"
+	SourceImageRegistry           IntervalSource = "ImageRegistry"
+	SourceImageStreamImport       IntervalSource = "ImageStreamImport"
+	SourcePDB                     IntervalSource = "PodDisruptionBudget"
+	SourceCertificateRotation     IntervalSource = "CertificateRotation"
+	SourceNodeJournal             IntervalSource = "NodeJournal"
+	SourceNetworkMesh             IntervalSource = "NetworkMesh"
+	SourceVolumeOperation         IntervalSource = "VolumeOperation"
+	SourceIngressReachability     IntervalSource = "IngressReachability"
+	SourceOLM                     IntervalSource = "OperatorLifecycleManager"
+	SourceMachineConfigPool       IntervalSource = "MachineConfigPool"
+	SourceMachineConfigRollout    IntervalSource = "MachineConfigRollout"
+	SourceLeaderElection          IntervalSource = "LeaderElection"
+	SourceAPIRequestMetrics       IntervalSource = "APIRequestMetrics"
+	SourceResourceLeak            IntervalSource = "ResourceLeak"
+	SourceResourceConsumption     IntervalSource = "ResourceConsumption"
+	SourceTerminationGrace        IntervalSource = "TerminationGrace"
+	SourceCloudThrottling         IntervalSource = "CloudThrottling"
+	SourceHostedControlPlane      IntervalSource = "HostedControlPlane"
+	SourceEtcdBackup              IntervalSource = "EtcdBackup"
+	SourceStaticPodRevision       IntervalSource = "StaticPodRevision"
+	SourceImagePull               IntervalSource = "ImagePull"
+	SourceClusterUpdatePhase      IntervalSource = "ClusterUpdatePhase"
+	SourceAlertmanagerPath        IntervalSource = "AlertmanagerNotificationPath"
+	SourceNodeClock               IntervalSource = "NodeClock"
+	SourceSecurityDrift           IntervalSource = "SecurityDrift"
+	SourceMultus                  IntervalSource = "Multus"
+	SourceCommand                 IntervalSource = "Command"
+	SourceOperatorConditionWait   IntervalSource = "OperatorConditionWait"
+	SourceMonitorWatchStreams     IntervalSource = "MonitorWatchStreams"
+	SourceMonitorRateLimit        IntervalSource = "MonitorRateLimit"
+	SourceUpdateTopology          IntervalSource = "UpdateTopology"
+	SourceMetricRule              IntervalSource = "MetricRule"
+	SourceNodeAgent               IntervalSource = "NodeAgent"
+	SourceInjectedFault           IntervalSource = "InjectedFault"
 )
 
 type Interval struct {
@@ -329,6 +576,34 @@ func (i Interval) String() string {
 		strings.Replace(i.Message.OldMessage(), "\n", "\\n", -1))
 }
 
+// ID returns a stable identifier derived from the content of the interval.  Two intervals with the same
+// source, level, locator, message, and times will have the same ID.  This allows test results to link
+// to the intervals that produced them.
+func (i Interval) ID() string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|%s|%s|%s|%s",
+		i.Source,
+		i.Level,
+		i.Locator.OldLocator(),
+		i.Message.OldMessage(),
+		i.From.UTC().Format(time.RFC3339Nano),
+		i.To.UTC().Format(time.RFC3339Nano),
+	)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// IDs returns the ID of every interval, in order.
+func (intervals Intervals) IDs() []string {
+	if len(intervals) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(intervals))
+	for _, interval := range intervals {
+		ret = append(ret, interval.ID())
+	}
+	return ret
+}
+
 func (i Message) OldMessage() string {
 	keys := sets.NewString()
 	for k := range i.Annotations {
@@ -387,7 +662,7 @@ func sortKeys(keys []string) []string {
 
 	// Ensure these keys appear in this order. Other keys can be mixed in and will appear at the end in alphabetical
 	// order.
-	orderedKeys := []string{"namespace", "node", "pod", "uid", "server", "container", "shutdown", "row"}
+	orderedKeys := []string{"cluster", "namespace", "node", "pod", "uid", "server", "container", "shutdown", "row"}
 
 	// Create a map to store the indices of keys in the orderedKeys array.
 	// This will allow us to efficiently check if a key is in orderedKeys and find its position.
@@ -527,6 +802,11 @@ func IsWarningEvent(eventInterval Interval) bool {
 	return eventInterval.Level == Warning
 }
 
+// IsDuringIntentionalLoad returns true if the eventInterval began while a load generator was running
+func IsDuringIntentionalLoad(eventInterval Interval) bool {
+	return len(eventInterval.Message.Annotations[AnnotationDuringLoad]) > 0
+}
+
 // IsInfoEvent returns true if the eventInterval is an Info
 func IsInfoEvent(eventInterval Interval) bool {
 	return eventInterval.Level == Info
@@ -537,6 +817,11 @@ func IsInE2ENamespace(eventInterval Interval) bool {
 	return strings.HasPrefix(NamespaceFromLocator(eventInterval.Locator), "e2e-")
 }
 
+// IsLowConfidence returns true if the eventInterval was recorded while the monitor could not authenticate
+func IsLowConfidence(eventInterval Interval) bool {
+	return eventInterval.Message.Annotations[AnnotationConfidence] == LowConfidence
+}
+
 func IsForDisruptionBackend(backend string) EventIntervalMatchesFunc {
 	return func(eventInterval Interval) bool {
 		if eventInterval.Locator.Keys[LocatorBackendDisruptionNameKey] == backend {
@@ -547,13 +832,7 @@ func IsForDisruptionBackend(backend string) EventIntervalMatchesFunc {
 }
 
 func IsInNamespaces(namespaces sets.String) EventIntervalMatchesFunc {
-	return func(eventInterval Interval) bool {
-		// For new, structured locators:
-		if ns, ok := eventInterval.Locator.Keys[LocatorNamespaceKey]; ok {
-			return namespaces.Has(ns)
-		}
-		return false
-	}
+	return LocatorKeyIn(LocatorNamespaceKey, namespaces.List()...)
 }
 
 // ContainsAllParts ensures that all listed key match at least one of the values.
@@ -0,0 +1,123 @@
+package nodeagent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+// maxBufferedIntervals is how many intervals an agent holds for clients that reconnect.  Older intervals are dropped,
+// which only loses intervals when the monitor cannot reach the agent for a long time.
+const maxBufferedIntervals = 10000
+
+// Agent runs node-local probes and serves the intervals they observe.
+type Agent struct {
+	id       string
+	nodeName string
+	probes   []*probeRunner
+
+	lock     sync.Mutex
+	sequence uint64
+	buffer   []*IntervalMessage
+	// changed is closed and replaced whenever an interval is recorded, to wake streams.
+	changed chan struct{}
+}
+
+// NewAgent returns an agent running the probes on the node.
+func NewAgent(nodeName string, probes []Probe, interval time.Duration) *Agent {
+	ret := &Agent{
+		id:       uuid.New().String(),
+		nodeName: nodeName,
+		changed:  make(chan struct{}),
+	}
+	for _, probe := range probes {
+		ret.probes = append(ret.probes, newProbeRunner(probe, nodeName, interval, ret.Record))
+	}
+	return ret
+}
+
+// Run probes until the context is done.
+func (a *Agent) Run(ctx context.Context) {
+	wg := sync.WaitGroup{}
+	for _, probe := range a.probes {
+		wg.Add(1)
+		go func(probe *probeRunner) {
+			defer wg.Done()
+			probe.run(ctx)
+		}(probe)
+	}
+	wg.Wait()
+}
+
+// Record buffers the interval for the streams.
+func (a *Agent) Record(interval monitorapi.Interval) {
+	content, err := monitorserialization.IntervalToOneLineJSON(interval)
+	if err != nil {
+		logrus.WithError(err).Error("unable to serialize interval")
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.sequence++
+	a.buffer = append(a.buffer, &IntervalMessage{
+		AgentID:  a.id,
+		Node:     a.nodeName,
+		Sequence: a.sequence,
+		Interval: content,
+	})
+	if len(a.buffer) > maxBufferedIntervals {
+		a.buffer = a.buffer[len(a.buffer)-maxBufferedIntervals:]
+	}
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// after returns the buffered intervals after the sequence, and a channel closed when there are more.
+func (a *Agent) after(sequence uint64) ([]*IntervalMessage, <-chan struct{}) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	ret := []*IntervalMessage{}
+	for _, message := range a.buffer {
+		if message.Sequence > sequence {
+			ret = append(ret, message)
+		}
+	}
+	return ret, a.changed
+}
+
+func (a *Agent) StreamIntervals(request *StreamRequest, stream IntervalStream) error {
+	var sent uint64
+	if request.AgentID == a.id {
+		sent = request.After
+	}
+	for {
+		messages, changed := a.after(sent)
+		for _, message := range messages {
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+			sent = message.Sequence
+		}
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (a *Agent) Flush(ctx context.Context, request *FlushRequest) (*FlushResponse, error) {
+	for _, probe := range a.probes {
+		probe.flush()
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return &FlushResponse{AgentID: a.id, Sequence: a.sequence}, nil
+}
@@ -0,0 +1,201 @@
+package nodeagent
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type fixedProbe struct {
+	result ProbeResult
+}
+
+func (p *fixedProbe) Name() string {
+	return "fixed"
+}
+
+func (p *fixedProbe) Probe(ctx context.Context) ProbeResult {
+	return p.result
+}
+
+func TestProbeRunner(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	recorded := monitorapi.Intervals{}
+	runner := newProbeRunner(&fixedProbe{}, "worker-0", time.Second, func(interval monitorapi.Interval) {
+		recorded = append(recorded, interval)
+	})
+	runner.now = func() time.Time { return now }
+
+	failing := ProbeResult{Failing: true, Reason: monitorapi.NodeLocalDNSFailedReason, Message: "unable to resolve"}
+	for _, result := range []ProbeResult{{}, failing, failing, {}, failing} {
+		runner.observe(result)
+		now = now.Add(time.Second)
+	}
+	runner.flush()
+	runner.flush()
+
+	if len(recorded) != 2 {
+		t.Fatalf("expected a closed window and a flushed window, got %v", recorded)
+	}
+	if !recorded[0].From.Equal(start.Add(time.Second)) || !recorded[0].To.Equal(start.Add(3*time.Second)) {
+		t.Errorf("expected the first window to last two probes, got %v to %v", recorded[0].From, recorded[0].To)
+	}
+	if !recorded[1].From.Equal(start.Add(4*time.Second)) || !recorded[1].To.Equal(start.Add(5*time.Second)) {
+		t.Errorf("expected the flush to end the open window, got %v to %v", recorded[1].From, recorded[1].To)
+	}
+	interval := recorded[0]
+	if interval.Source != monitorapi.SourceNodeAgent || interval.Message.Reason != monitorapi.NodeLocalDNSFailedReason ||
+		interval.Locator.Keys[monitorapi.LocatorNodeKey] != "worker-0" || interval.Locator.Keys[monitorapi.LocatorNodeProbeKey] != "fixed" {
+		t.Errorf("unexpected interval %v", interval)
+	}
+}
+
+func TestConntrackProbe(t *testing.T) {
+	procDir := t.TempDir()
+	netfilterDir := filepath.Join(procDir, "sys/net/netfilter")
+	if err := os.MkdirAll(netfilterDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	probe := NewConntrackProbe(procDir, 0.9)
+	if result := probe.Probe(context.Background()); result.Failing {
+		t.Errorf("expected a node without conntrack to pass, got %v", result)
+	}
+
+	for count, failing := range map[string]bool{"100\n": false, "950\n": true} {
+		if err := os.WriteFile(filepath.Join(netfilterDir, "nf_conntrack_count"), []byte(count), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(netfilterDir, "nf_conntrack_max"), []byte("1000\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if result := probe.Probe(context.Background()); result.Failing != failing {
+			t.Errorf("expected %q of 1000 entries failing to be %v, got %v", count, failing, result)
+		}
+	}
+}
+
+// agentServer serves an agent on a local port that changes every time it is restarted.
+type agentServer struct {
+	lock    sync.Mutex
+	agent   *Agent
+	address string
+	server  *grpc.Server
+}
+
+func (s *agentServer) start(t *testing.T, agent *Agent) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(ServerOptions()...)
+	RegisterAgentServer(server, agent)
+	go server.Serve(listener)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.agent, s.address, s.server = agent, listener.Addr().String(), server
+}
+
+func (s *agentServer) stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.server.Stop()
+}
+
+func (s *agentServer) dial(ctx context.Context) (grpc.ClientConnInterface, func(), error) {
+	s.lock.Lock()
+	address := s.address
+	s.lock.Unlock()
+	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+func nodeInterval(reason monitorapi.IntervalReason) monitorapi.Interval {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return monitorapi.NewInterval(monitorapi.SourceNodeAgent, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeProbe("fixed", "worker-0")).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage("failed")).
+		Build(start, start.Add(time.Second))
+}
+
+func TestCollector(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	server := &agentServer{}
+	server.start(t, NewAgent("worker-0", nil, time.Second))
+	defer server.stop()
+
+	lock := sync.Mutex{}
+	received := []monitorapi.IntervalReason{}
+	collector := NewCollector(server.dial, func(node string, interval monitorapi.Interval) {
+		lock.Lock()
+		defer lock.Unlock()
+		if node != "worker-0" {
+			t.Errorf("expected intervals from worker-0, got %q", node)
+		}
+		received = append(received, interval.Message.Reason)
+	})
+	collector.retryDelay = 10 * time.Millisecond
+	go collector.Run(ctx)
+
+	flush := func() {
+		t.Helper()
+		// the collector may not have connected yet.
+		for {
+			err := collector.Flush(ctx)
+			if err == nil {
+				return
+			}
+			if ctx.Err() != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	agent := server.agent
+	agent.Record(nodeInterval(monitorapi.NodeDiskLatencyHighReason))
+	flush()
+
+	// the same agent behind a new connection resumes after the last interval received.
+	server.stop()
+	agent.Record(nodeInterval(monitorapi.NodeConntrackSaturatedReason))
+	server.start(t, agent)
+	flush()
+
+	// a restarted agent starts over.
+	server.stop()
+	server.start(t, NewAgent("worker-0", nil, time.Second))
+	server.agent.Record(nodeInterval(monitorapi.NodeLocalDNSFailedReason))
+	flush()
+
+	lock.Lock()
+	defer lock.Unlock()
+	expected := []monitorapi.IntervalReason{
+		monitorapi.NodeDiskLatencyHighReason,
+		monitorapi.NodeConntrackSaturatedReason,
+		monitorapi.NodeLocalDNSFailedReason,
+	}
+	if len(received) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, received)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package nodeagent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+// Dialer connects to an agent.  release frees what the connection needs, for instance a port forward, once the
+// connection is no longer used.
+type Dialer func(ctx context.Context) (conn grpc.ClientConnInterface, release func(), err error)
+
+// Collector streams the intervals of one agent to the monitor.  A broken stream is dialed again, and resumes after
+// the last interval received, so every interval is recorded once.
+type Collector struct {
+	dial       Dialer
+	record     func(node string, interval monitorapi.Interval)
+	retryDelay time.Duration
+
+	lock sync.Mutex
+	conn grpc.ClientConnInterface
+	// agentID and lastSequence are where the stream resumes.  They are reset when the agent is restarted.
+	agentID      string
+	lastSequence uint64
+	// received is closed and replaced whenever an interval is received, to wake flushes.
+	received chan struct{}
+}
+
+// NewCollector returns a collector handing the intervals of the agent dial connects to to record.
+func NewCollector(dial Dialer, record func(node string, interval monitorapi.Interval)) *Collector {
+	return &Collector{
+		dial:       dial,
+		record:     record,
+		retryDelay: 5 * time.Second,
+		received:   make(chan struct{}),
+	}
+}
+
+// Run streams until the context is done.
+func (c *Collector) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := c.stream(ctx); err != nil && ctx.Err() == nil {
+			logrus.WithError(err).Warn("node agent stream broke, reconnecting")
+		}
+		select {
+		case <-time.After(c.retryDelay):
+		case <-ctx.Done():
+		}
+	}
+}
+
+func (c *Collector) stream(ctx context.Context) error {
+	conn, release, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	c.lock.Lock()
+	c.conn = conn
+	request := &StreamRequest{AgentID: c.agentID, After: c.lastSequence}
+	c.lock.Unlock()
+	defer func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.conn = nil
+	}()
+
+	return NewAgentClient(conn).StreamIntervals(ctx, request, c.receive)
+}
+
+func (c *Collector) receive(message *IntervalMessage) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if message.AgentID != c.agentID {
+		c.agentID = message.AgentID
+		c.lastSequence = 0
+	}
+	if message.Sequence <= c.lastSequence {
+		return nil
+	}
+	c.lastSequence = message.Sequence
+	close(c.received)
+	c.received = make(chan struct{})
+
+	interval, err := monitorserialization.IntervalFromJSON(message.Interval)
+	if err != nil {
+		logrus.WithError(err).Warnf("dropping invalid interval %d from the agent on node/%s", message.Sequence, message.Node)
+		return nil
+	}
+	c.record(message.Node, *interval)
+	return nil
+}
+
+// Flush asks the agent to end the windows its probes are in, and waits until the stream has delivered them.
+func (c *Collector) Flush(ctx context.Context) error {
+	c.lock.Lock()
+	conn := c.conn
+	c.lock.Unlock()
+	if conn == nil {
+		return fmt.Errorf("not connected to the agent")
+	}
+	flushed, err := NewAgentClient(conn).Flush(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		c.lock.Lock()
+		done := c.agentID == flushed.AgentID && c.lastSequence >= flushed.Sequence
+		received := c.received
+		c.lock.Unlock()
+		// an agent without intervals has nothing to deliver.
+		if done || flushed.Sequence == 0 {
+			return nil
+		}
+		select {
+		case <-received:
+		case <-ctx.Done():
+			return fmt.Errorf("the agent did not deliver interval %d: %w", flushed.Sequence, ctx.Err())
+		}
+	}
+}
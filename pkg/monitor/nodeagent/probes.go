@@ -0,0 +1,221 @@
+package nodeagent
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// Probe checks one thing about the node it runs on.
+type Probe interface {
+	// Name identifies the probe in the locators of its intervals.
+	Name() string
+	// Probe checks the node once.  It must return before the context is done.
+	Probe(ctx context.Context) ProbeResult
+}
+
+// ProbeResult is the outcome of one probe.  Consecutive failing results become one interval.
+type ProbeResult struct {
+	Failing bool
+	Reason  monitorapi.IntervalReason
+	Message string
+}
+
+// probeRunner runs a probe and records an interval for every window in which it failed.
+type probeRunner struct {
+	probe    Probe
+	nodeName string
+	interval time.Duration
+	record   func(monitorapi.Interval)
+	now      func() time.Time
+
+	lock sync.Mutex
+	// failingSince and failure are the beginning and the first result of the window the probe is failing in.
+	failingSince time.Time
+	failure      ProbeResult
+}
+
+func newProbeRunner(probe Probe, nodeName string, interval time.Duration, record func(monitorapi.Interval)) *probeRunner {
+	return &probeRunner{
+		probe:    probe,
+		nodeName: nodeName,
+		interval: interval,
+		record:   record,
+		now:      time.Now,
+	}
+}
+
+func (r *probeRunner) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		probeCtx, cancel := context.WithTimeout(ctx, r.interval)
+		result := r.probe.Probe(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		r.observe(result)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// observe opens a window on the first failing result, and records it on the first passing one.
+func (r *probeRunner) observe(result ProbeResult) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	now := r.now()
+	switch {
+	case result.Failing && r.failingSince.IsZero():
+		r.failingSince = now
+		r.failure = result
+	case !result.Failing && !r.failingSince.IsZero():
+		r.recordWindow(now)
+		r.failingSince = time.Time{}
+	}
+}
+
+// flush records the window the probe is failing in up to now, and starts a new one, so the window is not lost when
+// the agent is stopped before the probe passes again.
+func (r *probeRunner) flush() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	now := r.now()
+	// nothing has been observed since the last flush.
+	if r.failingSince.IsZero() || !now.After(r.failingSince) {
+		return
+	}
+	r.recordWindow(now)
+	r.failingSince = now
+}
+
+func (r *probeRunner) recordWindow(end time.Time) {
+	r.record(monitorapi.NewInterval(monitorapi.SourceNodeAgent, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeProbe(r.probe.Name(), r.nodeName)).
+		Message(monitorapi.NewMessage().Reason(r.failure.Reason).HumanMessage(r.failure.Message)).
+		Display().
+		Build(r.failingSince, end))
+}
+
+// diskLatencyProbe times a small synchronous write, which is what etcd and the kubelet wait on.
+type diskLatencyProbe struct {
+	dir       string
+	threshold time.Duration
+}
+
+// NewDiskLatencyProbe fails when a synchronous 4KiB write to a file in dir takes longer than threshold.
+func NewDiskLatencyProbe(dir string, threshold time.Duration) Probe {
+	return &diskLatencyProbe{dir: dir, threshold: threshold}
+}
+
+func (p *diskLatencyProbe) Name() string {
+	return "disk-latency"
+}
+
+func (p *diskLatencyProbe) Probe(ctx context.Context) ProbeResult {
+	start := time.Now()
+	err := syncWrite(filepath.Join(p.dir, ".node-agent-disk-probe"), make([]byte, 4096))
+	took := time.Since(start)
+	switch {
+	case err != nil:
+		return ProbeResult{Failing: true, Reason: monitorapi.NodeDiskLatencyHighReason, Message: fmt.Sprintf("synchronous write failed: %v", err)}
+	case took > p.threshold:
+		return ProbeResult{Failing: true, Reason: monitorapi.NodeDiskLatencyHighReason, Message: fmt.Sprintf("synchronous 4KiB write took %v, more than %v", took.Round(time.Millisecond), p.threshold)}
+	}
+	return ProbeResult{}
+}
+
+func syncWrite(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// conntrackProbe compares the connections tracked by netfilter to the most it tracks.  New connections are dropped
+// once the table is full.
+type conntrackProbe struct {
+	procDir   string
+	threshold float64
+}
+
+// NewConntrackProbe fails when the conntrack table of the node, read from procDir, is fuller than threshold, a
+// fraction.  The agent must run in the host network namespace to read the table of the node.
+func NewConntrackProbe(procDir string, threshold float64) Probe {
+	return &conntrackProbe{procDir: procDir, threshold: threshold}
+}
+
+func (p *conntrackProbe) Name() string {
+	return "conntrack"
+}
+
+func (p *conntrackProbe) Probe(ctx context.Context) ProbeResult {
+	count, err := readProcInt(filepath.Join(p.procDir, "sys/net/netfilter/nf_conntrack_count"))
+	if os.IsNotExist(err) {
+		// conntrack is not loaded, nothing is tracked.
+		return ProbeResult{}
+	}
+	if err != nil {
+		return ProbeResult{Failing: true, Reason: monitorapi.NodeConntrackSaturatedReason, Message: fmt.Sprintf("unable to read the conntrack count: %v", err)}
+	}
+	max, err := readProcInt(filepath.Join(p.procDir, "sys/net/netfilter/nf_conntrack_max"))
+	if err != nil || max == 0 {
+		return ProbeResult{Failing: true, Reason: monitorapi.NodeConntrackSaturatedReason, Message: fmt.Sprintf("unable to read the conntrack limit: %v", err)}
+	}
+	if usage := float64(count) / float64(max); usage > p.threshold {
+		return ProbeResult{Failing: true, Reason: monitorapi.NodeConntrackSaturatedReason, Message: fmt.Sprintf("conntrack table was %.0f%% full, %d of %d entries", usage*100, count, max)}
+	}
+	return ProbeResult{}
+}
+
+func readProcInt(path string) (int64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// dnsProbe resolves a name the way pods on the node do.
+type dnsProbe struct {
+	name     string
+	resolver *net.Resolver
+}
+
+// NewDNSProbe fails when the name cannot be resolved.
+func NewDNSProbe(name string) Probe {
+	return &dnsProbe{name: name, resolver: net.DefaultResolver}
+}
+
+func (p *dnsProbe) Name() string {
+	return "local-dns"
+}
+
+func (p *dnsProbe) Probe(ctx context.Context) ProbeResult {
+	if _, err := p.resolver.LookupHost(ctx, p.name); err != nil {
+		return ProbeResult{Failing: true, Reason: monitorapi.NodeLocalDNSFailedReason, Message: fmt.Sprintf("unable to resolve %s: %v", p.name, err)}
+	}
+	return ProbeResult{}
+}
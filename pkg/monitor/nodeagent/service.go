@@ -0,0 +1,175 @@
+package nodeagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// The agent service has no generated code.  Its messages are plain structs sent as JSON by a codec both ends are
+// forced to use, which keeps protoc out of the build for two small messages.
+
+const (
+	serviceName           = "openshift.monitor.nodeagent.Agent"
+	streamIntervalsMethod = "StreamIntervals"
+	flushMethod           = "Flush"
+)
+
+// StreamRequest asks an agent for its intervals.
+type StreamRequest struct {
+	// AgentID and After are the agent and the sequence of the last interval the client received.  The agent sends
+	// every interval after it, or every interval it holds when it is a different agent, because it was restarted.
+	AgentID string `json:"agentID,omitempty"`
+	After   uint64 `json:"after,omitempty"`
+}
+
+// IntervalMessage is one interval observed by an agent.
+type IntervalMessage struct {
+	AgentID  string `json:"agentID"`
+	Node     string `json:"node"`
+	Sequence uint64 `json:"sequence"`
+	// Interval is the interval in the format of the interval files.
+	Interval json.RawMessage `json:"interval"`
+}
+
+type FlushRequest struct{}
+
+// FlushResponse is the sequence of the last interval recorded by the flush.  Once a stream has delivered it, the
+// client has every interval the agent observed.
+type FlushResponse struct {
+	AgentID  string `json:"agentID"`
+	Sequence uint64 `json:"sequence"`
+}
+
+// AgentServer is what the agent serves.
+type AgentServer interface {
+	// StreamIntervals sends the intervals after the request, then every new interval until the stream ends.
+	StreamIntervals(request *StreamRequest, stream IntervalStream) error
+	// Flush ends the windows the probes are in, so intervals that have not ended yet can be collected.
+	Flush(ctx context.Context, request *FlushRequest) (*FlushResponse, error)
+}
+
+// IntervalStream sends intervals to a client.
+type IntervalStream interface {
+	Context() context.Context
+	Send(message *IntervalMessage) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+// RegisterAgentServer adds the agent service to the server.  The server must be created with ServerOptions.
+func RegisterAgentServer(server *grpc.Server, agent AgentServer) {
+	server.RegisterService(&agentServiceDesc, agent)
+}
+
+// ServerOptions are the options of a server the agent service is registered with, which read and write the JSON
+// messages.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.ForceServerCodec(jsonCodec{})}
+}
+
+var agentServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*AgentServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: flushMethod,
+			Handler:    flushHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    streamIntervalsMethod,
+			Handler:       streamIntervalsHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+func flushHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	request := &FlushRequest{}
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Flush(ctx, request)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod(flushMethod)}
+	return interceptor(ctx, request, info, func(ctx context.Context, request interface{}) (interface{}, error) {
+		return srv.(AgentServer).Flush(ctx, request.(*FlushRequest))
+	})
+}
+
+func streamIntervalsHandler(srv interface{}, stream grpc.ServerStream) error {
+	request := &StreamRequest{}
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+	return srv.(AgentServer).StreamIntervals(request, &intervalStream{stream})
+}
+
+type intervalStream struct {
+	grpc.ServerStream
+}
+
+func (s *intervalStream) Send(message *IntervalMessage) error {
+	return s.ServerStream.SendMsg(message)
+}
+
+func fullMethod(method string) string {
+	return fmt.Sprintf("/%s/%s", serviceName, method)
+}
+
+// AgentClient calls an agent.
+type AgentClient struct {
+	conn grpc.ClientConnInterface
+}
+
+func NewAgentClient(conn grpc.ClientConnInterface) *AgentClient {
+	return &AgentClient{conn: conn}
+}
+
+func (c *AgentClient) Flush(ctx context.Context) (*FlushResponse, error) {
+	response := &FlushResponse{}
+	if err := c.conn.Invoke(ctx, fullMethod(flushMethod), &FlushRequest{}, response, grpc.ForceCodec(jsonCodec{})); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// StreamIntervals calls recv with every interval the agent sends until the stream ends.
+func (c *AgentClient) StreamIntervals(ctx context.Context, request *StreamRequest, recv func(*IntervalMessage) error) error {
+	stream, err := c.conn.NewStream(ctx, &agentServiceDesc.Streams[0], fullMethod(streamIntervalsMethod), grpc.ForceCodec(jsonCodec{}))
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(request); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		message := &IntervalMessage{}
+		if err := stream.RecvMsg(message); err != nil {
+			return err
+		}
+		if err := recv(message); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+// Format is the body a webhook is posted.
+type Format string
+
+const (
+	// SlackFormat is a Slack incoming webhook message, which chat services with Slack compatible webhooks accept too.
+	SlackFormat Format = "slack"
+	// JSONFormat is the run summary as JSON, for webhooks that process the summary themselves.
+	JSONFormat Format = "json"
+
+	// maxSlackFailedTests is how many failed tests a Slack message lists before it only counts them.
+	maxSlackFailedTests = 10
+)
+
+// WebhookOptions describe where and how the run summary is posted.
+type WebhookOptions struct {
+	// URL is the webhook, an http or https URL.
+	URL string
+	// Format is the body posted.  If empty, SlackFormat is used.
+	Format Format
+	// Title names the run in the message, for instance the soak cluster.  If empty, the message is untitled.
+	Title string
+	// ArtifactsURL is where the artifacts of the run can be browsed.  If empty, the message does not link them.
+	ArtifactsURL string
+}
+
+type webhookNotifier struct {
+	options WebhookOptions
+	client  *http.Client
+}
+
+// NewWebhookNotifier returns a notifier that posts the run summary to a webhook.
+func NewWebhookNotifier(options WebhookOptions) (monitortestframework.Notifier, error) {
+	endpoint, err := url.Parse(options.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("the webhook must be an http or https URL, got %q", options.URL)
+	}
+	switch options.Format {
+	case "":
+		options.Format = SlackFormat
+	case SlackFormat, JSONFormat:
+	default:
+		return nil, fmt.Errorf("the webhook format must be one of %s or %s, got %q", SlackFormat, JSONFormat, options.Format)
+	}
+	return &webhookNotifier{
+		options: options,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// jsonMessage is the body of JSONFormat webhooks.
+type jsonMessage struct {
+	Title        string                          `json:"title,omitempty"`
+	ArtifactsURL string                          `json:"artifactsURL,omitempty"`
+	Summary      monitortestframework.RunSummary `json:"summary"`
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, summary monitortestframework.RunSummary) error {
+	var message interface{}
+	switch n.options.Format {
+	case JSONFormat:
+		message = jsonMessage{Title: n.options.Title, ArtifactsURL: n.options.ArtifactsURL, Summary: summary}
+	default:
+		message = slackMessage{Text: slackText(n.options, summary)}
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook returned %s: %s", resp.Status, content)
+	}
+	return nil
+}
+
+// slackText is the summary in Slack mrkdwn: a headline, the failed tests, the disruption windows, and the link to
+// the artifacts.
+func slackText(options WebhookOptions, summary monitortestframework.RunSummary) string {
+	text := &strings.Builder{}
+	title := "Monitor run"
+	if len(options.Title) > 0 {
+		title = options.Title
+	}
+	switch len(summary.FailedTests) {
+	case 0:
+		fmt.Fprintf(text, ":white_check_mark: *%s*: no invariant failed", slackEscape(title))
+	default:
+		fmt.Fprintf(text, ":x: *%s*: %d invariant tests failed", slackEscape(title), len(summary.FailedTests))
+	}
+	if !summary.Beginning.IsZero() && !summary.End.IsZero() {
+		fmt.Fprintf(text, " (%s to %s)", summary.Beginning.UTC().Format(time.RFC3339), summary.End.UTC().Format(time.RFC3339))
+	}
+	text.WriteString("\n")
+
+	for i, test := range summary.FailedTests {
+		if i == maxSlackFailedTests {
+			fmt.Fprintf(text, "• and %d more\n", len(summary.FailedTests)-maxSlackFailedTests)
+			break
+		}
+		fmt.Fprintf(text, "• %s\n", slackEscape(test.Name))
+	}
+
+	if len(summary.DisruptionWindows) > 0 {
+		text.WriteString("*Longest disruptions*\n")
+		for _, window := range summary.DisruptionWindows {
+			fmt.Fprintf(text, "• %s: %v from %s\n", slackEscape(window.Backend), window.Length, window.From.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if len(options.ArtifactsURL) > 0 {
+		fmt.Fprintf(text, "<%s|Artifacts>\n", options.ArtifactsURL)
+	}
+	return strings.TrimSuffix(text.String(), "\n")
+}
+
+// slackEscape escapes the characters Slack treats as control characters.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
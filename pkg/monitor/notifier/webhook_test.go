@@ -0,0 +1,114 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+func testSummary() monitortestframework.RunSummary {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return monitortestframework.RunSummary{
+		FailedTests: []monitortestframework.FailedTest{
+			{Name: "[sig-etcd] etcd should not <leak>", Output: "failed"},
+		},
+		DisruptionWindows: []monitortestframework.DisruptionWindow{
+			{Backend: "kube-api-new-connections", From: start, To: start.Add(5 * time.Second), Length: 5 * time.Second},
+		},
+		Beginning: start,
+		End:       start.Add(time.Hour),
+	}
+}
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		checkFn func(t *testing.T, body []byte)
+	}{
+		{
+			name: "slack",
+			checkFn: func(t *testing.T, body []byte) {
+				message := slackMessage{}
+				if err := json.Unmarshal(body, &message); err != nil {
+					t.Fatal(err)
+				}
+				expected := strings.Join([]string{
+					":x: *soak-1*: 1 invariant tests failed (2024-01-01T00:00:00Z to 2024-01-01T01:00:00Z)",
+					"• [sig-etcd] etcd should not &lt;leak&gt;",
+					"*Longest disruptions*",
+					"• kube-api-new-connections: 5s from 2024-01-01T00:00:00Z",
+					"<https://artifacts.example.com/soak-1|Artifacts>",
+				}, "\n")
+				if message.Text != expected {
+					t.Errorf("expected\n%s\ngot\n%s", expected, message.Text)
+				}
+			},
+		},
+		{
+			name:   "json",
+			format: JSONFormat,
+			checkFn: func(t *testing.T, body []byte) {
+				message := jsonMessage{}
+				if err := json.Unmarshal(body, &message); err != nil {
+					t.Fatal(err)
+				}
+				if message.Title != "soak-1" || message.ArtifactsURL != "https://artifacts.example.com/soak-1" {
+					t.Errorf("unexpected message %#v", message)
+				}
+				if len(message.Summary.FailedTests) != 1 || len(message.Summary.DisruptionWindows) != 1 {
+					t.Errorf("unexpected summary %#v", message.Summary)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer server.Close()
+
+			notifier, err := NewWebhookNotifier(WebhookOptions{URL: server.URL, Format: tt.format, Title: "soak-1", ArtifactsURL: "https://artifacts.example.com/soak-1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := notifier.Notify(context.Background(), testSummary()); err != nil {
+				t.Fatal(err)
+			}
+			tt.checkFn(t, body)
+		})
+	}
+}
+
+func TestNotifyFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookNotifier(WebhookOptions{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.Notify(context.Background(), testSummary()); err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("expected the response in the error, got %v", err)
+	}
+}
+
+func TestNewWebhookNotifier(t *testing.T) {
+	if _, err := NewWebhookNotifier(WebhookOptions{URL: "ftp://example.com"}); err == nil {
+		t.Error("expected an error for a non http URL")
+	}
+	if _, err := NewWebhookNotifier(WebhookOptions{URL: "https://example.com", Format: "xml"}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -2,7 +2,6 @@ package monitor
 
 import (
 	"fmt"
-	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -10,20 +9,44 @@ import (
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/clock"
 )
 
 type recorder struct {
-	lock   sync.Mutex
-	events monitorapi.Intervals
+	// clock stamps conditions recorded without a time.
+	clock clock.PassiveClock
+
+	// store keeps the intervals in the order they were recorded, StartInterval hands out indexes into it.
+	store *monitorapi.IntervalStore
 
 	recordedResourceLock sync.Mutex
 	recordedResources    monitorapi.ResourcesMap
+	// resourceBudget prunes resources as they are recorded, if it is set.
+	resourceBudget *ResourceBudget
 }
 
 // NewRecorder creates a recorder that can  be used to store events
 func NewRecorder() monitorapi.Recorder {
+	return NewRecorderWithClock(clock.RealClock{})
+}
+
+// NewRecorderWithClock creates a recorder that stamps conditions recorded without a time with the clock.  Replays
+// use a fake clock to record at the time of the original run.
+func NewRecorderWithClock(clock clock.PassiveClock) monitorapi.Recorder {
+	return &recorder{
+		clock:             clock,
+		store:             monitorapi.NewIntervalStore(),
+		recordedResources: monitorapi.ResourcesMap{},
+	}
+}
+
+// NewRecorderWithResourceBudget creates a recorder that prunes resources with the budget as they are recorded.
+func NewRecorderWithResourceBudget(resourceBudget *ResourceBudget) monitorapi.Recorder {
 	return &recorder{
+		clock:             clock.RealClock{},
+		store:             monitorapi.NewIntervalStore(),
 		recordedResources: monitorapi.ResourcesMap{},
+		resourceBudget:    resourceBudget,
 	}
 }
 
@@ -67,6 +90,12 @@ func (m *recorder) RecordResource(resourceType string, obj runtime.Object) {
 	}
 
 	toStore := obj.DeepCopyObject()
+	if m.resourceBudget != nil {
+		_, alreadyRecorded := recordedResource[key]
+		if !m.resourceBudget.prune(resourceType, key, toStore, alreadyRecorded) {
+			return
+		}
+	}
 	// without metadata, just stomp in the new value, we can't add annotations
 	if newMetadata == nil {
 		recordedResource[key] = toStore
@@ -126,38 +155,24 @@ func (m *recorder) RecordResource(resourceType string, obj runtime.Object) {
 // Record captures one or more conditions at the current time. All conditions are recorded
 // in monotonic order as EventInterval objects.
 func (m *recorder) Record(conditions ...monitorapi.Condition) {
-	m.RecordAt(time.Now().UTC(), conditions...)
+	m.RecordAt(m.clock.Now().UTC(), conditions...)
 }
 
 // AddIntervals provides a mechanism to directly inject eventIntervals
 func (m *recorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
-	m.lock.Lock()
-	defer m.lock.Unlock()
-	m.events = append(m.events, eventIntervals...)
+	m.store.Add(eventIntervals...)
 }
 
 // StartInterval inserts a record at time t with the provided condition and returns an opaque
 // locator to the interval. The caller may close the sample at any point by invoking EndInterval().
 func (m *recorder) StartInterval(interval monitorapi.Interval) int {
-	m.lock.Lock()
-	defer m.lock.Unlock()
-	m.events = append(m.events, interval)
-	return len(m.events) - 1
+	return m.store.Start(interval)
 }
 
 // EndInterval updates the To of the interval started by StartInterval if it is greater than
 // the from.
 func (m *recorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
-	m.lock.Lock()
-	defer m.lock.Unlock()
-	if startedInterval < len(m.events) {
-		if m.events[startedInterval].From.Before(t) {
-			m.events[startedInterval].To = t
-		}
-		t := m.events[startedInterval]
-		return &t
-	}
-	return nil
+	return m.store.End(startedInterval, t)
 }
 
 // RecordAt captures one or more conditions at the provided time. All conditions are recorded
@@ -177,19 +192,10 @@ func (m *recorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
 	m.AddIntervals(intervals...)
 }
 
-func (m *recorder) snapshot() monitorapi.Intervals {
-	m.lock.Lock()
-	defer m.lock.Unlock()
-	return m.events
-}
-
 // Intervals returns all events that occur between from and to, including
 // any sampled conditions that were encountered during that period.
 // Intervals are returned in order of their occurrence. The returned slice
 // is a copy of the monitor's state and is safe to update.
 func (m *recorder) Intervals(from, to time.Time) monitorapi.Intervals {
-	events := m.snapshot()
-	// we must sort *before*, we use the slice function
-	sort.Sort(events)
-	return events.Slice(from, to)
+	return m.store.Intervals(from, to)
 }
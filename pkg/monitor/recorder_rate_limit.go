@@ -0,0 +1,229 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// DefaultIntervalRateLimit is how many intervals of one source are recorded each minute.  It is far above what a
+	// healthy cluster produces, it is there so a runaway event storm cannot record millions of intervals and run the
+	// monitor out of memory.
+	DefaultIntervalRateLimit = 20000
+
+	intervalRateLimitWindow = time.Minute
+)
+
+// intervalRateLimiter counts the intervals recorded from each source, and the storms of intervals it dropped.
+type intervalRateLimiter struct {
+	recorder  monitorapi.RecorderWriter
+	perMinute int
+	now       func() time.Time
+
+	lock    sync.Mutex
+	stopped bool
+	sources map[monitorapi.IntervalSource]*sourceRate
+	// dropped are how many intervals of each source were dropped over the whole run.
+	dropped map[monitorapi.IntervalSource]int
+}
+
+type sourceRate struct {
+	window time.Time
+	count  int
+
+	// a storm lasts as long as consecutive windows overflow.
+	storming        bool
+	stormFrom       time.Time
+	stormTo         time.Time
+	stormLastWindow time.Time
+	stormDropped    int
+}
+
+func newIntervalRateLimiter(recorder monitorapi.RecorderWriter, perMinute int) *intervalRateLimiter {
+	return &intervalRateLimiter{
+		recorder:  recorder,
+		perMinute: perMinute,
+		now:       time.Now,
+		sources:   map[monitorapi.IntervalSource]*sourceRate{},
+		dropped:   map[monitorapi.IntervalSource]int{},
+	}
+}
+
+// allow returns false if an interval of the source is over the limit and must be dropped.
+func (l *intervalRateLimiter) allow(source monitorapi.IntervalSource) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.stopped || l.perMinute <= 0 {
+		return true
+	}
+
+	now := l.now()
+	window := now.Truncate(intervalRateLimitWindow)
+	rate, ok := l.sources[source]
+	if !ok {
+		rate = &sourceRate{window: window}
+		l.sources[source] = rate
+	}
+	if !rate.window.Equal(window) {
+		if rate.storming && stormIsOver(rate, window) {
+			l.endStormLocked(source, rate)
+		}
+		rate.window = window
+		rate.count = 0
+	}
+
+	rate.count++
+	if rate.count <= l.perMinute {
+		return true
+	}
+	if !rate.storming {
+		rate.storming = true
+		rate.stormFrom = now
+		rate.stormDropped = 0
+	}
+	rate.stormTo = now
+	rate.stormLastWindow = window
+	rate.stormDropped++
+	l.dropped[source]++
+	return false
+}
+
+// stormIsOver is true once a whole window passed without overflowing.
+func stormIsOver(rate *sourceRate, window time.Time) bool {
+	return rate.stormLastWindow.Before(window.Add(-intervalRateLimitWindow))
+}
+
+// run ends storms of sources that stopped recording altogether, which allow never sees, until the context is done.
+func (l *intervalRateLimiter) run(ctx context.Context) {
+	ticker := time.NewTicker(intervalRateLimitWindow)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.endQuietStorms()
+		}
+	}
+}
+
+// endQuietStorms ends the storms that have not overflowed for a whole window.
+func (l *intervalRateLimiter) endQuietStorms() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.stopped {
+		return
+	}
+	window := l.now().Truncate(intervalRateLimitWindow)
+	for source, rate := range l.sources {
+		if rate.storming && stormIsOver(rate, window) {
+			l.endStormLocked(source, rate)
+		}
+	}
+}
+
+// endStormLocked records a single interval summarizing the intervals of the source dropped during the storm.
+func (l *intervalRateLimiter) endStormLocked(source monitorapi.IntervalSource, rate *sourceRate) {
+	rate.storming = false
+	l.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceMonitorRateLimit, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().IntervalSource(source)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.IntervalsRateLimited).
+				HumanMessagef("dropped %d intervals of source %q, more than %d were recorded per minute", rate.stormDropped, source, l.perMinute)).
+			Display().
+			Build(rate.stormFrom, rate.stormTo),
+	)
+}
+
+// stop records the storms still going on, and stops limiting.  Once collection ends, intervals are recorded in bulk
+// and are not limited.
+func (l *intervalRateLimiter) stop() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.stopped {
+		return
+	}
+	l.stopped = true
+	for source, rate := range l.sources {
+		if rate.storming {
+			l.endStormLocked(source, rate)
+		}
+	}
+}
+
+const intervalRateLimitTestName = "[sig-arch] monitor should record every interval without rate limiting"
+
+// junits warns when intervals were dropped.  Tests reading the dropped sources may be missing data, but the job still
+// has results, so the failure budget policy decides whether this fails the job.
+func (l *intervalRateLimiter) junits() []*junitapi.JUnitTestCase {
+	if l == nil {
+		return nil
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.dropped) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: intervalRateLimitTestName}}
+	}
+	lines := []string{}
+	for source, dropped := range l.dropped {
+		lines = append(lines, fmt.Sprintf("%s: %d intervals dropped", source, dropped))
+	}
+	sort.Strings(lines)
+	failureMsg := fmt.Sprintf("sources recorded more than %d intervals per minute, see %s intervals for when intervals were dropped\n%s",
+		l.perMinute, monitorapi.SourceMonitorRateLimit, strings.Join(lines, "\n"))
+	return []*junitapi.JUnitTestCase{
+		{
+			Name:          intervalRateLimitTestName,
+			SystemOut:     failureMsg,
+			FailureOutput: &junitapi.FailureOutput{Output: failureMsg},
+			Details:       &junitapi.JUnitTestCaseDetails{Severity: junitapi.SeverityWarn},
+		},
+	}
+}
+
+// rateLimitedRecorder drops intervals of sources that are over the limit.  Started intervals are not limited, they are
+// ended by index.
+type rateLimitedRecorder struct {
+	monitorapi.Recorder
+	limiter *intervalRateLimiter
+}
+
+func newRateLimitedRecorder(recorder monitorapi.Recorder, limiter *intervalRateLimiter) monitorapi.Recorder {
+	return &rateLimitedRecorder{
+		Recorder: recorder,
+		limiter:  limiter,
+	}
+}
+
+func (r *rateLimitedRecorder) Record(conditions ...monitorapi.Condition) {
+	r.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt limits conditions as intervals without a source.
+func (r *rateLimitedRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	allowed := make([]monitorapi.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		if r.limiter.allow("") {
+			allowed = append(allowed, condition)
+		}
+	}
+	r.Recorder.RecordAt(t, allowed...)
+}
+
+func (r *rateLimitedRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	allowed := make([]monitorapi.Interval, 0, len(eventIntervals))
+	for _, interval := range eventIntervals {
+		if r.limiter.allow(interval.Source) {
+			allowed = append(allowed, interval)
+		}
+	}
+	r.Recorder.AddIntervals(allowed...)
+}
@@ -0,0 +1,135 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func rateLimitTestInterval(source monitorapi.IntervalSource) monitorapi.Interval {
+	now := time.Now()
+	return monitorapi.NewInterval(source, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("node")).
+		Message(monitorapi.NewMessage().HumanMessage("event")).
+		Build(now, now)
+}
+
+func rateLimitIntervals(recorder monitorapi.Recorder) monitorapi.Intervals {
+	return recorder.Intervals(time.Time{}, time.Time{}).Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceMonitorRateLimit
+	})
+}
+
+func TestRateLimitedRecorder(t *testing.T) {
+	delegate := NewRecorder()
+	limiter := newIntervalRateLimiter(delegate, 3)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	recorder := newRateLimitedRecorder(delegate, limiter)
+
+	// the storm lasts two consecutive windows.
+	for i := 0; i < 5; i++ {
+		recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	}
+	recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceNodeMonitor))
+	now = now.Add(time.Minute)
+	for i := 0; i < 4; i++ {
+		recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	}
+	if limited := rateLimitIntervals(delegate); len(limited) != 0 {
+		t.Fatalf("expected the storm to go on, got %v", limited)
+	}
+
+	// a quiet window ends it.
+	now = now.Add(time.Minute)
+	recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	now = now.Add(time.Minute)
+	recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	limited := rateLimitIntervals(delegate)
+	if len(limited) != 1 {
+		t.Fatalf("expected a single summarizing interval, got %v", limited)
+	}
+	if !strings.Contains(limited[0].Message.HumanMessage, "dropped 3 intervals") {
+		t.Errorf("expected the summary to count the dropped intervals, got %q", limited[0].Message.HumanMessage)
+	}
+	if limited[0].Locator.Keys[monitorapi.LocatorIntervalSourceKey] != string(monitorapi.SourceKubeEvent) {
+		t.Errorf("expected the summary to locate the source, got %v", limited[0].Locator)
+	}
+
+	kubeEvents := delegate.Intervals(time.Time{}, time.Time{}).Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceKubeEvent
+	})
+	if len(kubeEvents) != 8 {
+		t.Errorf("expected 8 recorded intervals, got %d", len(kubeEvents))
+	}
+
+	junits := limiter.junits()
+	if len(junits) != 1 || junits[0].FailureOutput == nil || junits[0].Details.Severity != junitapi.SeverityWarn {
+		t.Fatalf("expected a warning, got %#v", junits)
+	}
+	if !strings.Contains(junits[0].FailureOutput.Output, "KubeEvent: 3 intervals dropped") {
+		t.Errorf("expected the failure to count dropped intervals, got %q", junits[0].FailureOutput.Output)
+	}
+}
+
+func TestRateLimitedRecorderQuietStorm(t *testing.T) {
+	delegate := NewRecorder()
+	limiter := newIntervalRateLimiter(delegate, 1)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	recorder := newRateLimitedRecorder(delegate, limiter)
+
+	recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent), rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	now = now.Add(time.Minute)
+	limiter.endQuietStorms()
+	if limited := rateLimitIntervals(delegate); len(limited) != 0 {
+		t.Fatalf("expected the storm to go on until a whole window is quiet, got %v", limited)
+	}
+
+	// the source stopped recording, so only the check ends the storm.
+	now = now.Add(time.Minute)
+	limiter.endQuietStorms()
+	if limited := rateLimitIntervals(delegate); len(limited) != 1 {
+		t.Fatalf("expected a quiet window to end the storm, got %v", limited)
+	}
+	limiter.stop()
+	if limited := rateLimitIntervals(delegate); len(limited) != 1 {
+		t.Errorf("expected stopping not to end the storm again, got %v", limited)
+	}
+}
+
+func TestRateLimitedRecorderStop(t *testing.T) {
+	delegate := NewRecorder()
+	limiter := newIntervalRateLimiter(delegate, 1)
+	recorder := newRateLimitedRecorder(delegate, limiter)
+
+	recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent), rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	limiter.stop()
+	if limited := rateLimitIntervals(delegate); len(limited) != 1 {
+		t.Fatalf("expected stopping to end the storm, got %v", limited)
+	}
+
+	recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent), rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	if intervals := delegate.Intervals(time.Time{}, time.Time{}); len(intervals) != 4 {
+		t.Errorf("expected intervals not to be limited after stopping, got %v", intervals)
+	}
+}
+
+func TestRateLimitedRecorderWithinLimit(t *testing.T) {
+	delegate := NewRecorder()
+	limiter := newIntervalRateLimiter(delegate, DefaultIntervalRateLimit)
+	recorder := newRateLimitedRecorder(delegate, limiter)
+
+	recorder.AddIntervals(rateLimitTestInterval(monitorapi.SourceKubeEvent))
+	recorder.Record(monitorapi.Condition{Level: monitorapi.Info, Message: monitorapi.NewMessage().HumanMessage("condition").Build()})
+	limiter.stop()
+	if intervals := delegate.Intervals(time.Time{}, time.Time{}); len(intervals) != 2 {
+		t.Errorf("expected every interval to be recorded, got %v", intervals)
+	}
+	if junits := limiter.junits(); len(junits) != 1 || junits[0].FailureOutput != nil {
+		t.Errorf("expected a single passing junit, got %#v", junits)
+	}
+}
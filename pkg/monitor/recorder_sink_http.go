@@ -0,0 +1,248 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// DefaultHTTPSinkBatchSize is how many intervals are posted together.
+	DefaultHTTPSinkBatchSize = 500
+	// DefaultHTTPSinkFlushInterval is how long an interval waits for a batch to fill before it is posted anyway.
+	DefaultHTTPSinkFlushInterval = 5 * time.Second
+
+	// httpSinkBuffer is how many intervals wait to be posted before new ones are dropped.  Recording never waits on
+	// the endpoint.
+	httpSinkBuffer = 10000
+)
+
+// HTTPSink posts intervals to a remote endpoint in batches, as newline delimited JSON, for viewing intervals live
+// during long jobs.  The endpoint being slow or down loses intervals for the viewer, never for the monitor.
+type HTTPSink struct {
+	endpoint      string
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	// header is sent with every post, and encode turns a batch into its body.
+	header http.Header
+	encode batchEncoder
+	policy postPolicy
+
+	lock      sync.Mutex
+	closed    bool
+	dropped   int
+	failed    int
+	intervals chan monitorapi.Interval
+	done      chan struct{}
+}
+
+// batchEncoder returns the body a batch of intervals is posted as.
+type batchEncoder func(batch monitorapi.Intervals) ([]byte, error)
+
+// postPolicy is how the posts of a sink are checked and retried.  The zero policy fails posts that do not return a 2xx
+// status, and does not retry them.
+type postPolicy struct {
+	// check reads the response to a post.  If nil, checkStatus is used.
+	check func(resp *http.Response) error
+	// retries is how many times a post that failed with a retryable postError, or could not be sent, is posted again.
+	retries int
+	// backoff is how long the first retry waits.  Every retry waits twice as long as the one before.
+	backoff time.Duration
+}
+
+// postError is a post the endpoint did not accept.
+type postError struct {
+	// rejected is how many intervals of the batch the endpoint did not accept.  Zero means all of them.
+	rejected int
+	// retryable is set when posting the batch again may succeed, for instance when the endpoint is overloaded.
+	retryable bool
+	err       error
+}
+
+func (e *postError) Error() string {
+	return e.err.Error()
+}
+
+// checkStatus fails the post unless the status is 2xx.  Throttled posts and server errors are retryable.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return &postError{
+		retryable: resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500,
+		err:       fmt.Errorf("unexpected status %s", resp.Status),
+	}
+}
+
+// NewHTTPSink starts posting the intervals written to the sink to endpoint, until it is closed.
+func NewHTTPSink(endpoint string, batchSize int, flushInterval time.Duration) *HTTPSink {
+	return newHTTPSink(endpoint, batchSize, flushInterval, http.Header{"Content-Type": {"application/x-ndjson"}}, encodeNDJSON, postPolicy{})
+}
+
+func newHTTPSink(endpoint string, batchSize int, flushInterval time.Duration, header http.Header, encode batchEncoder, policy postPolicy) *HTTPSink {
+	if batchSize <= 0 {
+		batchSize = DefaultHTTPSinkBatchSize
+	}
+	if flushInterval <= 0 {
+		flushInterval = DefaultHTTPSinkFlushInterval
+	}
+	s := &HTTPSink{
+		endpoint:      endpoint,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		header:        header,
+		encode:        encode,
+		policy:        policy,
+		intervals:     make(chan monitorapi.Interval, httpSinkBuffer),
+		done:          make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+var _ IntervalSink = &HTTPSink{}
+
+func (s *HTTPSink) WriteInterval(interval monitorapi.Interval) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.intervals <- interval:
+	default:
+		s.dropped++
+	}
+}
+
+// Close posts the intervals still waiting and stops the sink.  The error reports intervals that never reached the
+// endpoint.
+func (s *HTTPSink) Close(ctx context.Context) error {
+	s.lock.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.intervals)
+	}
+	s.lock.Unlock()
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return fmt.Errorf("intervals were still being posted to %s: %w", s.endpoint, ctx.Err())
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.dropped > 0 || s.failed > 0 {
+		return fmt.Errorf("%d intervals were dropped because %s could not keep up, and %d could not be posted", s.dropped, s.endpoint, s.failed)
+	}
+	return nil
+}
+
+func (s *HTTPSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := monitorapi.Intervals{}
+	for {
+		select {
+		case interval, ok := <-s.intervals:
+			if !ok {
+				s.post(batch)
+				return
+			}
+			batch = append(batch, interval)
+			if len(batch) >= s.batchSize {
+				s.post(batch)
+				batch = monitorapi.Intervals{}
+			}
+		case <-ticker.C:
+			s.post(batch)
+			batch = monitorapi.Intervals{}
+		}
+	}
+}
+
+func (s *HTTPSink) post(batch monitorapi.Intervals) {
+	if len(batch) == 0 {
+		return
+	}
+
+	body, err := s.encode(batch)
+	if err == nil {
+		err = s.postWithRetries(body)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error posting %d intervals to %s: %v\n", len(batch), s.endpoint, err)
+		failed := len(batch)
+		failure := &postError{}
+		if errors.As(err, &failure) && failure.rejected > 0 {
+			failed = failure.rejected
+		}
+		s.lock.Lock()
+		s.failed += failed
+		s.lock.Unlock()
+	}
+}
+
+// postWithRetries posts the body again while the post may succeed later, up to the retries of the policy.
+func (s *HTTPSink) postWithRetries(body []byte) error {
+	backoff := s.policy.backoff
+	for attempt := 0; ; attempt++ {
+		err := s.postOnce(body)
+		failure := &postError{}
+		retryable := err != nil && (!errors.As(err, &failure) || failure.retryable)
+		if !retryable || attempt >= s.policy.retries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (s *HTTPSink) postOnce(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range s.header {
+		req.Header[key] = values
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	check := s.policy.check
+	if check == nil {
+		check = checkStatus
+	}
+	return check(resp)
+}
+
+// encodeNDJSON writes every interval as a line of JSON.
+func encodeNDJSON(batch monitorapi.Intervals) ([]byte, error) {
+	body := &bytes.Buffer{}
+	for _, interval := range batch {
+		intervalJSON, err := monitorserialization.IntervalToOneLineJSON(interval)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error serializing: %v\n", err)
+			continue
+		}
+		body.Write(intervalJSON)
+		body.WriteString("\n")
+	}
+	return body.Bytes(), nil
+}
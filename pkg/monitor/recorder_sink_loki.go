@@ -0,0 +1,159 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+const lokiPushPath = "/loki/api/v1/push"
+
+// DefaultLokiLabelKeys are the locator keys intervals are labeled with in Loki.  They have few values across a fleet
+// of runs, which keeps the number of streams down.  Keys with many values, like pods, are still queryable from the log
+// line.
+var DefaultLokiLabelKeys = []monitorapi.LocatorKey{
+	monitorapi.LocatorNamespaceKey,
+	monitorapi.LocatorNodeKey,
+	monitorapi.LocatorClusterOperatorKey,
+	monitorapi.LocatorBackendDisruptionNameKey,
+	monitorapi.LocatorAlertKey,
+	monitorapi.LocatorClusterKey,
+}
+
+var invalidLokiLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// LokiSinkOptions describe the streams intervals are pushed to.
+type LokiSinkOptions struct {
+	// Labels are added to every stream, for instance the name of the job and the run, so many runs can share a Loki.
+	Labels map[string]string
+	// LabelKeys are the locator keys that become labels.  If nil, DefaultLokiLabelKeys are used.
+	LabelKeys []monitorapi.LocatorKey
+	// Tenant is the tenant of a multi-tenant Loki.  If empty, no tenant is sent.
+	Tenant string
+}
+
+// NewLokiSink pushes intervals to Loki as JSON log lines at the time they began, labeled with their source, level,
+// locator type and locator keys, so intervals can be queried across runs without downloading artifacts.  It batches
+// and drops intervals the way the HTTPSink does.
+func NewLokiSink(endpoint string, options LokiSinkOptions, batchSize int, flushInterval time.Duration) *HTTPSink {
+	if !strings.HasSuffix(endpoint, lokiPushPath) {
+		endpoint = strings.TrimSuffix(endpoint, "/") + lokiPushPath
+	}
+	labelKeys := options.LabelKeys
+	if labelKeys == nil {
+		labelKeys = DefaultLokiLabelKeys
+	}
+	header := http.Header{"Content-Type": {"application/json"}}
+	if len(options.Tenant) > 0 {
+		header.Set("X-Scope-OrgID", options.Tenant)
+	}
+	encoder := &lokiEncoder{labels: options.Labels, labelKeys: labelKeys}
+	return newHTTPSink(endpoint, batchSize, flushInterval, header, encoder.encode, postPolicy{})
+}
+
+type lokiEncoder struct {
+	labels    map[string]string
+	labelKeys []monitorapi.LocatorKey
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	// Values are pairs of the time in nanoseconds since the epoch, as a string, and the log line.
+	Values [][2]string `json:"values"`
+}
+
+type lokiEntry struct {
+	at   time.Time
+	line string
+}
+
+func (e *lokiEncoder) encode(batch monitorapi.Intervals) ([]byte, error) {
+	streams := map[string]*lokiStream{}
+	entries := map[string][]lokiEntry{}
+	for _, interval := range batch {
+		line, err := monitorserialization.IntervalToOneLineJSON(interval)
+		if err != nil {
+			return nil, err
+		}
+		labels := e.labelsFor(interval)
+		streamKey := lokiStreamKey(labels)
+		if _, ok := streams[streamKey]; !ok {
+			streams[streamKey] = &lokiStream{Stream: labels}
+		}
+		at := interval.From
+		if at.IsZero() {
+			at = interval.To
+		}
+		entries[streamKey] = append(entries[streamKey], lokiEntry{at: at, line: string(line)})
+	}
+
+	push := lokiPush{Streams: []lokiStream{}}
+	streamKeys := []string{}
+	for streamKey := range streams {
+		streamKeys = append(streamKeys, streamKey)
+	}
+	sort.Strings(streamKeys)
+	for _, streamKey := range streamKeys {
+		stream := streams[streamKey]
+		// older versions of Loki reject entries older than the newest one of their stream.
+		sort.SliceStable(entries[streamKey], func(i, j int) bool {
+			return entries[streamKey][i].at.Before(entries[streamKey][j].at)
+		})
+		for _, entry := range entries[streamKey] {
+			stream.Values = append(stream.Values, [2]string{fmt.Sprintf("%d", entry.at.UnixNano()), entry.line})
+		}
+		push.Streams = append(push.Streams, *stream)
+	}
+	return json.Marshal(push)
+}
+
+func (e *lokiEncoder) labelsFor(interval monitorapi.Interval) map[string]string {
+	labels := map[string]string{}
+	for key, value := range e.labels {
+		labels[lokiLabelName(key)] = value
+	}
+	labels["source"] = string(interval.Source)
+	labels["level"] = interval.Level.String()
+	if len(interval.Locator.Type) > 0 {
+		labels["locator_type"] = string(interval.Locator.Type)
+	}
+	for _, key := range e.labelKeys {
+		if value := interval.Locator.Keys[key]; len(value) > 0 {
+			labels[lokiLabelName(string(key))] = value
+		}
+	}
+	return labels
+}
+
+// lokiLabelName replaces the characters Loki does not allow in label names, like the dashes of locator keys.
+func lokiLabelName(name string) string {
+	name = invalidLokiLabelChars.ReplaceAllString(name, "_")
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+func lokiStreamKey(labels map[string]string) string {
+	keys := []string{}
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := []string{}
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func TestLokiSink(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	lock := sync.Mutex{}
+	pushes := []lokiPush{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != lokiPushPath {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if r.Header.Get("X-Scope-OrgID") != "ci" {
+			t.Errorf("unexpected tenant %q", r.Header.Get("X-Scope-OrgID"))
+		}
+		push := lokiPush{}
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			t.Error(err)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		pushes = append(pushes, push)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink := NewLokiSink(server.URL, LokiSinkOptions{Labels: map[string]string{"job-run": "1234"}, Tenant: "ci"}, 10, time.Hour)
+	later := walTestInterval("later", beginning.Add(time.Minute), beginning.Add(time.Minute))
+	earlier := walTestInterval("earlier", beginning, beginning)
+	operator := monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().ClusterOperator("etcd")).
+		Message(monitorapi.NewMessage().HumanMessage("degraded")).
+		Build(beginning, beginning.Add(time.Minute))
+	for _, interval := range []monitorapi.Interval{later, earlier, operator} {
+		sink.WriteInterval(interval)
+	}
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	line := func(interval monitorapi.Interval) string {
+		content, err := monitorserialization.IntervalToOneLineJSON(interval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	expected := []lokiPush{{Streams: []lokiStream{
+		{
+			Stream: map[string]string{"job_run": "1234", "source": string(monitorapi.SourceClusterOperatorMonitor), "level": "Warning",
+				"locator_type": string(monitorapi.LocatorTypeClusterOperator), "clusteroperator": "etcd"},
+			Values: [][2]string{{fmt.Sprintf("%d", beginning.UnixNano()), line(operator)}},
+		},
+		{
+			Stream: map[string]string{"job_run": "1234", "source": string(monitorapi.SourceTestData), "level": "Info",
+				"locator_type": string(monitorapi.LocatorTypeNode), "node": "master-0"},
+			Values: [][2]string{
+				{fmt.Sprintf("%d", beginning.UnixNano()), line(earlier)},
+				{fmt.Sprintf("%d", beginning.Add(time.Minute).UnixNano()), line(later)},
+			},
+		},
+	}}}
+	if !reflect.DeepEqual(expected, pushes) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expected, pushes)
+	}
+}
@@ -0,0 +1,217 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	openSearchBulkPath = "/_bulk"
+
+	// DefaultOpenSearchIndex is the index intervals are written to when none is given.
+	DefaultOpenSearchIndex = "openshift-monitor-intervals"
+	// DefaultOpenSearchRetries is how many times a bulk request that may succeed later is sent again.
+	DefaultOpenSearchRetries = 3
+	// DefaultOpenSearchBackoff is how long the first retry of a bulk request waits.
+	DefaultOpenSearchBackoff = time.Second
+
+	// maxReportedBulkError is how much of the first error of a bulk response is reported.
+	maxReportedBulkError = 512
+)
+
+var invalidOpenSearchFieldChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// OpenSearchSinkOptions describe the index intervals are written to.
+type OpenSearchSinkOptions struct {
+	// Index is the index documents are written to.  If empty, DefaultOpenSearchIndex is used.
+	Index string
+	// Fields are added to every document, for instance the name of the cluster and the run, so many runs can share an
+	// index.
+	Fields map[string]string
+	// Username and Password authenticate with HTTP basic authentication.  If Username is empty, no credentials are
+	// sent.
+	Username string
+	Password string
+	// Retries is how many times a bulk request that failed because OpenSearch was unavailable or throttled, entirely
+	// or for some of its documents, is sent again.  If zero, DefaultOpenSearchRetries is used.
+	Retries int
+	// Backoff is how long the first retry waits.  Every retry waits twice as long.  If zero, DefaultOpenSearchBackoff
+	// is used.
+	Backoff time.Duration
+}
+
+// NewOpenSearchSink writes intervals as documents to an OpenSearch or Elasticsearch index with the bulk API, for
+// dashboards over long running clusters.  The locator keys and annotations of an interval are flattened into fields of
+// its document, like locator_node and annotation_reason.  Documents are identified by the content of the interval, so
+// retried requests do not duplicate them.  It batches and drops intervals the way the HTTPSink does.
+func NewOpenSearchSink(endpoint string, options OpenSearchSinkOptions, batchSize int, flushInterval time.Duration) *HTTPSink {
+	if !strings.HasSuffix(endpoint, openSearchBulkPath) {
+		endpoint = strings.TrimSuffix(endpoint, "/") + openSearchBulkPath
+	}
+	if len(options.Index) == 0 {
+		options.Index = DefaultOpenSearchIndex
+	}
+	if options.Retries == 0 {
+		options.Retries = DefaultOpenSearchRetries
+	}
+	if options.Backoff == 0 {
+		options.Backoff = DefaultOpenSearchBackoff
+	}
+	header := http.Header{"Content-Type": {"application/x-ndjson"}}
+	if len(options.Username) > 0 {
+		credentials := base64.StdEncoding.EncodeToString([]byte(options.Username + ":" + options.Password))
+		header.Set("Authorization", "Basic "+credentials)
+	}
+	encoder := &openSearchEncoder{index: options.Index, fields: options.Fields}
+	policy := postPolicy{
+		check:   checkBulkResponse,
+		retries: options.Retries,
+		backoff: options.Backoff,
+	}
+	return newHTTPSink(endpoint, batchSize, flushInterval, header, encoder.encode, policy)
+}
+
+type openSearchEncoder struct {
+	index  string
+	fields map[string]string
+}
+
+type openSearchAction struct {
+	Index openSearchActionMetadata `json:"index"`
+}
+
+type openSearchActionMetadata struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+// encode writes an index action and the document of every interval, as the bulk API expects.
+func (e *openSearchEncoder) encode(batch monitorapi.Intervals) ([]byte, error) {
+	body := &bytes.Buffer{}
+	encoder := json.NewEncoder(body)
+	for _, interval := range batch {
+		action := openSearchAction{Index: openSearchActionMetadata{Index: e.index, ID: e.documentID(interval)}}
+		if err := encoder.Encode(action); err != nil {
+			return nil, err
+		}
+		if err := encoder.Encode(e.document(interval)); err != nil {
+			return nil, err
+		}
+	}
+	return body.Bytes(), nil
+}
+
+// documentID identifies the interval within the runs sharing the index, which are told apart by their fields.
+func (e *openSearchEncoder) documentID(interval monitorapi.Interval) string {
+	keys := []string{}
+	for key := range e.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	h := fnv.New64a()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s=%s|", key, e.fields[key])
+	}
+	fmt.Fprint(h, interval.ID())
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func (e *openSearchEncoder) document(interval monitorapi.Interval) map[string]interface{} {
+	document := map[string]interface{}{}
+	for key, value := range e.fields {
+		document[openSearchFieldName(key)] = value
+	}
+	at := interval.From
+	if at.IsZero() {
+		at = interval.To
+	}
+	document["@timestamp"] = at
+	document["from"] = interval.From
+	document["to"] = interval.To
+	if !interval.From.IsZero() && !interval.To.IsZero() {
+		document["duration_seconds"] = interval.To.Sub(interval.From).Seconds()
+	}
+	document["source"] = string(interval.Source)
+	document["level"] = interval.Level.String()
+	document["display"] = interval.Display
+	document["locator"] = interval.Locator.OldLocator()
+	if len(interval.Locator.Type) > 0 {
+		document["locator_type"] = string(interval.Locator.Type)
+	}
+	for key, value := range interval.Locator.Keys {
+		document["locator_"+openSearchFieldName(string(key))] = value
+	}
+	document["message"] = interval.Message.HumanMessage
+	if len(interval.Message.Reason) > 0 {
+		document["reason"] = string(interval.Message.Reason)
+	}
+	for key, value := range interval.Message.Annotations {
+		document["annotation_"+openSearchFieldName(string(key))] = value
+	}
+	return document
+}
+
+// openSearchFieldName replaces the characters that are awkward in field names, like the dashes of locator keys and
+// the dots OpenSearch expands into objects.
+func openSearchFieldName(name string) string {
+	return invalidOpenSearchFieldChars.ReplaceAllString(name, "_")
+}
+
+type openSearchBulkResponse struct {
+	Errors bool `json:"errors"`
+	// Items hold the result of every action, keyed by the kind of action.
+	Items []map[string]openSearchBulkItem `json:"items"`
+}
+
+type openSearchBulkItem struct {
+	Status int             `json:"status"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// checkBulkResponse fails the post when OpenSearch rejected any document.  A bulk request succeeds even when some of
+// its documents are rejected, so the results of the documents are read.  Posting again is retryable when a document
+// was throttled or hit a server error, which does not duplicate the documents already written.
+func checkBulkResponse(resp *http.Response) error {
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	response := &openSearchBulkResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return &postError{retryable: true, err: fmt.Errorf("unable to read the bulk response: %w", err)}
+	}
+	if !response.Errors {
+		return nil
+	}
+
+	failure := &postError{}
+	var firstError string
+	for _, item := range response.Items {
+		for _, result := range item {
+			if result.Status >= 200 && result.Status < 300 {
+				continue
+			}
+			failure.rejected++
+			if result.Status == http.StatusTooManyRequests || result.Status >= 500 {
+				failure.retryable = true
+			}
+			if len(firstError) == 0 {
+				firstError = string(result.Error)
+				if len(firstError) > maxReportedBulkError {
+					firstError = firstError[:maxReportedBulkError] + "..."
+				}
+			}
+		}
+	}
+	failure.err = fmt.Errorf("%d of %d documents were rejected, the first with %s", failure.rejected, len(response.Items), firstError)
+	return failure
+}
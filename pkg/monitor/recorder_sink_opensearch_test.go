@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// fakeOpenSearch indexes the documents of bulk requests by id, and rejects documents with the statuses of rejections
+// until it runs out of them.
+type fakeOpenSearch struct {
+	t          *testing.T
+	lock       sync.Mutex
+	requests   int
+	rejections []int
+	documents  map[string]map[string]interface{}
+}
+
+func (o *fakeOpenSearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.requests++
+	if r.URL.Path != openSearchBulkPath {
+		o.t.Errorf("unexpected path %q", r.URL.Path)
+	}
+	if username, password, ok := r.BasicAuth(); !ok || username != "monitor" || password != "secret" {
+		o.t.Errorf("unexpected credentials %q", r.Header.Get("Authorization"))
+	}
+
+	response := openSearchBulkResponse{}
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		action := openSearchAction{}
+		if err := json.Unmarshal(scanner.Bytes(), &action); err != nil {
+			o.t.Fatal(err)
+		}
+		if !scanner.Scan() {
+			o.t.Fatalf("action %v has no document", action)
+		}
+		document := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &document); err != nil {
+			o.t.Fatal(err)
+		}
+		if action.Index.Index != "perf-intervals" {
+			o.t.Errorf("unexpected index %q", action.Index.Index)
+		}
+
+		result := openSearchBulkItem{Status: http.StatusCreated}
+		if len(o.rejections) > 0 {
+			result = openSearchBulkItem{Status: o.rejections[0], Error: json.RawMessage(`{"type":"rejected"}`)}
+			o.rejections = o.rejections[1:]
+			response.Errors = true
+		} else {
+			o.documents[action.Index.ID] = document
+		}
+		response.Items = append(response.Items, map[string]openSearchBulkItem{"index": result})
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func TestOpenSearchSink(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	disruption := monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().DisruptionRequiredOnly("kube-api-new-connections", "kube-api")).
+		Message(monitorapi.NewMessage().Reason("DisruptionBegan").HumanMessage("stopped responding")).
+		Build(beginning, beginning.Add(5*time.Second))
+	node := walTestInterval("rebooted", beginning, beginning)
+
+	tests := []struct {
+		name              string
+		rejections        []int
+		expectedRequests  int
+		expectedDocuments int
+		expectedErr       string
+	}{
+		{
+			name:              "indexed",
+			expectedRequests:  1,
+			expectedDocuments: 2,
+		},
+		{
+			name:              "throttled documents are retried",
+			rejections:        []int{http.StatusTooManyRequests},
+			expectedRequests:  2,
+			expectedDocuments: 2,
+		},
+		{
+			name:              "rejected documents are not retried",
+			rejections:        []int{http.StatusBadRequest},
+			expectedRequests:  1,
+			expectedDocuments: 1,
+			expectedErr:       "and 1 could not be posted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeOpenSearch{t: t, rejections: tt.rejections, documents: map[string]map[string]interface{}{}}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			sink := NewOpenSearchSink(server.URL+"/", OpenSearchSinkOptions{
+				Index:    "perf-intervals",
+				Fields:   map[string]string{"cluster": "perf-1"},
+				Username: "monitor",
+				Password: "secret",
+				Backoff:  time.Millisecond,
+			}, 10, time.Hour)
+			sink.WriteInterval(disruption)
+			sink.WriteInterval(node)
+			err := sink.Close(context.Background())
+			switch {
+			case len(tt.expectedErr) == 0 && err != nil:
+				t.Fatal(err)
+			case len(tt.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)):
+				t.Fatalf("expected an error containing %q, got %v", tt.expectedErr, err)
+			}
+
+			if fake.requests != tt.expectedRequests {
+				t.Errorf("expected %d bulk requests, got %d", tt.expectedRequests, fake.requests)
+			}
+			if len(fake.documents) != tt.expectedDocuments {
+				t.Fatalf("expected %d documents, got %d", tt.expectedDocuments, len(fake.documents))
+			}
+			document, ok := fake.documents[(&openSearchEncoder{fields: map[string]string{"cluster": "perf-1"}}).documentID(node)]
+			if !ok {
+				t.Fatalf("expected the node interval to be indexed, got %v", fake.documents)
+			}
+			expected := map[string]interface{}{
+				"cluster":          "perf-1",
+				"@timestamp":       "2024-01-01T12:00:00Z",
+				"source":           string(monitorapi.SourceTestData),
+				"level":            "Info",
+				"locator_type":     string(monitorapi.LocatorTypeNode),
+				"locator_node":     "master-0",
+				"message":          "rebooted",
+				"duration_seconds": float64(0),
+			}
+			for key, value := range expected {
+				if document[key] != value {
+					t.Errorf("expected %s to be %v, got %v", key, value, document[key])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// IntervalSink receives intervals as they are recorded, so they can be watched while the monitor runs.  Started
+// intervals are written when they end.  WriteInterval is called concurrently and must not block the recorder for long.
+type IntervalSink interface {
+	WriteInterval(interval monitorapi.Interval)
+}
+
+// sinkRecorder stores intervals in the delegate and streams them to every sink.
+type sinkRecorder struct {
+	delegate monitorapi.Recorder
+	sinks    []IntervalSink
+}
+
+// WrapWithSinks streams every interval recorded by delegate to the sinks, for instance a local file and a remote
+// endpoint.  The delegate remains the store intervals are read from.
+func WrapWithSinks(delegate monitorapi.Recorder, sinks ...IntervalSink) monitorapi.Recorder {
+	return &sinkRecorder{
+		delegate: delegate,
+		sinks:    sinks,
+	}
+}
+
+var _ monitorapi.Recorder = &sinkRecorder{}
+
+func (m *sinkRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.delegate.CurrentResourceState()
+}
+
+func (m *sinkRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	m.delegate.RecordResource(resourceType, obj)
+}
+
+// Record captures one or more conditions at the current time. All conditions are recorded
+// in monotonic order as EventInterval objects.
+func (m *sinkRecorder) Record(conditions ...monitorapi.Condition) {
+	m.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt captures one or more conditions at the provided time. All conditions are recorded
+// as EventInterval objects.
+func (m *sinkRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	m.AddIntervals(intervals...)
+}
+
+// AddIntervals provides a mechanism to directly inject eventIntervals
+func (m *sinkRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	for _, curr := range intervals {
+		m.writeInterval(&curr)
+	}
+	m.delegate.AddIntervals(intervals...)
+}
+
+// StartInterval inserts a record at time t with the provided condition and returns an opaque
+// locator to the interval. The caller may close the sample at any point by invoking EndInterval().
+func (m *sinkRecorder) StartInterval(interval monitorapi.Interval) int {
+	return m.delegate.StartInterval(interval)
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than
+// the from.
+func (m *sinkRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	ret := m.delegate.EndInterval(startedInterval, t)
+	m.writeInterval(ret)
+
+	return ret
+}
+
+func (m *sinkRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	return m.delegate.Intervals(from, to)
+}
+
+func (m *sinkRecorder) writeInterval(interval *monitorapi.Interval) {
+	if interval == nil {
+		return
+	}
+	for _, sink := range m.sinks {
+		sink.WriteInterval(*interval)
+	}
+}
@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func TestWrapWithSinks(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	lock := sync.Mutex{}
+	posts := []monitorapi.Intervals{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		batch := monitorapi.Intervals{}
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			interval, err := monitorserialization.IntervalFromJSON(scanner.Bytes())
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			batch = append(batch, *interval)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		posts = append(posts, batch)
+	}))
+	defer server.Close()
+
+	out := &bytes.Buffer{}
+	httpSink := NewHTTPSink(server.URL, 2, time.Hour)
+	recorder := WrapWithSinks(NewRecorder(), NewJSONLSink(out, nil), httpSink)
+	recorder.AddIntervals(walTestInterval("first", beginning, beginning), walTestInterval("second", beginning, beginning))
+	started := recorder.StartInterval(walTestInterval("started", beginning, time.Time{}))
+	recorder.EndInterval(started, beginning.Add(time.Minute))
+
+	if err := httpSink.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// a full batch is posted right away, the rest when the sink is closed.
+	if len(posts) != 2 || len(posts[0]) != 2 || len(posts[1]) != 1 {
+		t.Fatalf("unexpected batches %v", posts)
+	}
+	if posts[1][0].Message.HumanMessage != "started" || !posts[1][0].To.Equal(beginning.Add(time.Minute)) {
+		t.Errorf("expected the started interval once it ended, got %v", posts[1][0])
+	}
+	if lines := bytes.Count(out.Bytes(), []byte("\n")); lines != 3 {
+		t.Errorf("expected every interval written to the file, got %q", out.String())
+	}
+	if intervals := recorder.Intervals(time.Time{}, time.Time{}); len(intervals) != 3 {
+		t.Errorf("expected the intervals to be stored, got %v", intervals)
+	}
+
+	// intervals written once the sink is closed are not posted.
+	recorder.AddIntervals(walTestInterval("late", beginning, beginning))
+	if len(posts) != 2 {
+		t.Errorf("expected nothing posted after close, got %v", posts)
+	}
+}
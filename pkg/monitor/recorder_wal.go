@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// walRecorder appends everything written to the delegate to a write-ahead log before it returns, so the intervals
+// survive the monitor process crashing or being killed for running out of memory.  Every entry is a single write to
+// an unbuffered file, so once the write returns the entry is the kernel's, not the process's.  Recorded resources are
+// not logged, they are read from the cluster again.
+type walRecorder struct {
+	delegate monitorapi.Recorder
+
+	lock    sync.Mutex
+	walFile io.Writer
+}
+
+// OpenRecorderWAL opens the write-ahead log in filename for appending, creating it if needed.  A monitor restarted
+// after a crash appends to the log of the monitor that crashed.
+func OpenRecorderWAL(filename string) (*os.File, error) {
+	return os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+}
+
+// WrapWithWALRecorder logs everything recorded by delegate to walFile.  The log is read back by
+// monitorserialization.IntervalsFromWALFile.
+func WrapWithWALRecorder(delegate monitorapi.Recorder, walFile io.Writer) monitorapi.Recorder {
+	ret := &walRecorder{
+		delegate: delegate,
+		walFile:  walFile,
+	}
+	// the previous recorder may have been killed in the middle of a line.
+	if _, err := walFile.Write([]byte("\n")); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing write-ahead log entry: %v\n", err)
+	}
+	ret.write(monitorserialization.NewWALEntry(monitorserialization.WALOpen, 0, nil))
+	return ret
+}
+
+var _ monitorapi.Recorder = &walRecorder{}
+
+func (m *walRecorder) CurrentResourceState() monitorapi.ResourcesMap {
+	return m.delegate.CurrentResourceState()
+}
+
+func (m *walRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	m.delegate.RecordResource(resourceType, obj)
+}
+
+// Record captures one or more conditions at the current time. All conditions are recorded
+// in monotonic order as EventInterval objects.
+func (m *walRecorder) Record(conditions ...monitorapi.Condition) {
+	m.RecordAt(time.Now().UTC(), conditions...)
+}
+
+// RecordAt captures one or more conditions at the provided time. All conditions are recorded
+// as EventInterval objects.
+func (m *walRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	intervals := monitorapi.Intervals{}
+	for _, condition := range conditions {
+		intervals = append(intervals, monitorapi.Interval{
+			Condition: condition,
+			From:      t,
+			To:        t,
+		})
+	}
+	m.AddIntervals(intervals...)
+}
+
+// AddIntervals provides a mechanism to directly inject eventIntervals
+func (m *walRecorder) AddIntervals(intervals ...monitorapi.Interval) {
+	for i := range intervals {
+		m.write(monitorserialization.NewWALEntry(monitorserialization.WALAdd, 0, &intervals[i]))
+	}
+	m.delegate.AddIntervals(intervals...)
+}
+
+// StartInterval inserts a record at time t with the provided condition and returns an opaque
+// locator to the interval. The caller may close the sample at any point by invoking EndInterval().
+func (m *walRecorder) StartInterval(interval monitorapi.Interval) int {
+	ret := m.delegate.StartInterval(interval)
+	m.write(monitorserialization.NewWALEntry(monitorserialization.WALStart, ret, &interval))
+	return ret
+}
+
+// EndInterval updates the To of the interval started by StartInterval if it is greater than
+// the from.
+func (m *walRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	ret := m.delegate.EndInterval(startedInterval, t)
+	if ret != nil {
+		m.write(monitorserialization.NewWALEntry(monitorserialization.WALEnd, startedInterval, ret))
+	}
+	return ret
+}
+
+func (m *walRecorder) Intervals(from, to time.Time) monitorapi.Intervals {
+	return m.delegate.Intervals(from, to)
+}
+
+func (m *walRecorder) write(entry monitorserialization.WALEntry) {
+	entryJSON, err := monitorserialization.WALEntryToOneLineJSON(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error serializing write-ahead log entry: %v\n", err)
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, err := m.walFile.Write(append(entryJSON, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing write-ahead log entry: %v\n", err)
+	}
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func walTestInterval(message string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+		Message(monitorapi.NewMessage().HumanMessage(message)).
+		Build(from, to)
+}
+
+func TestWALRecorder(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	wal := &bytes.Buffer{}
+
+	// the first monitor is killed while writing a line.
+	crashed := WrapWithWALRecorder(NewRecorder(), wal)
+	crashed.AddIntervals(walTestInterval("added", beginning, beginning))
+	crashed.StartInterval(walTestInterval("never ended", beginning.Add(time.Minute), time.Time{}))
+	wal.WriteString(`{"op":"Add","interval":{"lev`)
+
+	restarted := WrapWithWALRecorder(NewRecorder(), wal)
+	started := restarted.StartInterval(walTestInterval("ended", beginning.Add(2*time.Minute), time.Time{}))
+	restarted.EndInterval(started, beginning.Add(3*time.Minute))
+
+	intervals, err := monitorserialization.IntervalsFromWAL(bytes.NewReader(wal.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 3 {
+		t.Fatalf("expected the intervals of both monitors, got %v", intervals)
+	}
+	if intervals[0].Message.HumanMessage != "added" || !intervals[0].To.Equal(beginning) {
+		t.Errorf("unexpected interval %v", intervals[0])
+	}
+	if intervals[1].Message.HumanMessage != "never ended" || !intervals[1].To.IsZero() {
+		t.Errorf("expected the interval the first monitor started to stay open, got %v", intervals[1])
+	}
+	if intervals[2].Message.HumanMessage != "ended" || !intervals[2].To.Equal(beginning.Add(3*time.Minute)) {
+		t.Errorf("expected the index the restarted monitor handed out to end its own interval, got %v", intervals[2])
+	}
+
+	// an unreadable line in the middle of what a monitor wrote is corruption.
+	corrupt := bytes.NewBufferString("{\"op\":\"Add\"\n{\"op\":\"Open\"}\n{\"op\":\"Ad\n{\"op\":\"End\",\"index\":1,\"to\":\"2024-01-01T12:00:00Z\"}\n")
+	if _, err := monitorserialization.IntervalsFromWAL(corrupt); err == nil {
+		t.Error("expected an error for an unreadable line that is not the last")
+	}
+}
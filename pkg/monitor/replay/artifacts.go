@@ -0,0 +1,76 @@
+package replay
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+var (
+	intervalFileRegex = regexp.MustCompile(`^e2e-events.*\.json$`)
+	resourceFileRegex = regexp.MustCompile(`^resource-.*\.zip$`)
+)
+
+// LoadArtifacts reads the intervals and resources a monitor run wrote to its storage directory, to replay the run with
+// the monitor tests of the current code.  Every e2e-events and resource file under the directory is read, so the
+// directories of every phase of a run are replayed together.  Intervals marked as constructed are left out, the replay
+// constructs them again.
+func LoadArtifacts(storageDir string) (Options, error) {
+	intervalFiles := []string{}
+	resourceFiles := []string{}
+	err := filepath.WalkDir(storageDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+		case intervalFileRegex.MatchString(d.Name()):
+			intervalFiles = append(intervalFiles, path)
+		case resourceFileRegex.MatchString(d.Name()):
+			resourceFiles = append(resourceFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return Options{}, err
+	}
+	if len(intervalFiles) == 0 {
+		return Options{}, fmt.Errorf("no e2e-events files in %s", storageDir)
+	}
+
+	ret := Options{}
+	// the same interval is in the files of every monitor test that wrote the final intervals.
+	seen := map[string]bool{}
+	for _, filename := range intervalFiles {
+		intervals, err := monitorserialization.EventsFromFile(filename)
+		if err != nil {
+			return Options{}, fmt.Errorf("unable to read intervals from %q: %w", filename, err)
+		}
+		for _, interval := range intervals {
+			if len(interval.Message.Annotations[monitorapi.AnnotationConstructed]) > 0 || seen[interval.ID()] {
+				continue
+			}
+			seen[interval.ID()] = true
+			ret.Intervals = append(ret.Intervals, interval)
+		}
+	}
+	sort.Stable(ret.Intervals)
+
+	for _, filename := range resourceFiles {
+		resources, err := monitorserialization.ResourcesFromFile(filename)
+		if err != nil {
+			return Options{}, fmt.Errorf("unable to read resources from %q: %w", filename, err)
+		}
+		for resourceType, instances := range resources {
+			for _, obj := range instances {
+				ret.ResourceUpdates = append(ret.ResourceUpdates, ResourceUpdate{ResourceType: resourceType, Object: obj})
+			}
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,67 @@
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+func TestLoadArtifacts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorded := monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+		Message(monitorapi.NewMessage().HumanMessage("recorded")).
+		Build(start, start.Add(time.Minute))
+	upgraded := monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("master-1")).
+		Message(monitorapi.NewMessage().HumanMessage("recorded after the upgrade")).
+		Build(start.Add(time.Hour), start.Add(2*time.Hour))
+	constructed := monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+		Message(monitorapi.NewMessage().Constructed(monitorapi.ConstructionOwnerNodeLifecycle).HumanMessage("constructed")).
+		Build(start, start.Add(time.Hour))
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "a", UID: types.UID("uid-a")}}
+
+	storageDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(storageDir, "post-upgrade"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(storageDir, "e2e-events_20240101-000000.json"), monitorapi.Intervals{recorded, constructed}); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitorserialization.EventsToFile(filepath.Join(storageDir, "post-upgrade", "e2e-events_20240101-010000.json"), monitorapi.Intervals{recorded, upgraded}); err != nil {
+		t.Fatal(err)
+	}
+	podKey := monitorapi.InstanceKey{Namespace: "ns", Name: "a", UID: "uid-a"}
+	if err := monitorserialization.InstanceMapToFile(filepath.Join(storageDir, "resource-pods_20240101-000000.zip"), "pods", monitorapi.InstanceMap{podKey: pod}); err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := LoadArtifacts(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(options.Intervals) != 2 || options.Intervals[0].Message.HumanMessage != "recorded" || options.Intervals[1].Message.HumanMessage != "recorded after the upgrade" {
+		t.Errorf("expected the recorded intervals of every phase once, got %v", options.Intervals)
+	}
+	if len(options.ResourceUpdates) != 1 || options.ResourceUpdates[0].ResourceType != "pods" {
+		t.Fatalf("expected the recorded pod, got %v", options.ResourceUpdates)
+	}
+	if loaded, ok := options.ResourceUpdates[0].Object.(*corev1.Pod); !ok || loaded.UID != pod.UID {
+		t.Errorf("expected the pod to be read as a pod, got %#v", options.ResourceUpdates[0].Object)
+	}
+}
+
+func TestLoadArtifactsWithoutIntervals(t *testing.T) {
+	if _, err := LoadArtifacts(t.TempDir()); err == nil {
+		t.Errorf("expected a directory without intervals to be rejected")
+	}
+}
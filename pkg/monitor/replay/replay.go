@@ -0,0 +1,174 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// ResourceUpdate is a resource as it was recorded at a point of the original run.
+type ResourceUpdate struct {
+	// At is when the resource was recorded.  Updates without a time are recorded when the replay begins.
+	At           time.Time
+	ResourceType string
+	Object       runtime.Object
+}
+
+// Options are what a replay feeds to the monitor tests.
+type Options struct {
+	// Intervals are the intervals recorded by the original run, before any were computed.
+	Intervals monitorapi.Intervals
+	// ResourceUpdates are the resources recorded by the original run.
+	ResourceUpdates []ResourceUpdate
+	// Speed is how many times faster than the original run intervals and resources are fed.  Zero feeds them without
+	// waiting, which is what unit tests want.
+	Speed float64
+}
+
+// Result is what the monitor tests produced from the replayed run.
+type Result struct {
+	// ComputedIntervals are the intervals constructed by the monitor tests.
+	ComputedIntervals monitorapi.Intervals
+	// FinalIntervals are the replayed and computed intervals, annotated, as the tests were evaluated on them.
+	FinalIntervals monitorapi.Intervals
+	Junits         []*junitapi.JUnitTestCase
+}
+
+type replayEvent struct {
+	at       time.Time
+	interval *monitorapi.Interval
+	resource *ResourceUpdate
+}
+
+// Replay feeds a recorded run to the monitor tests of the registry the way the monitor would have, with a clock that
+// reads the time of the original run, then computes intervals and evaluates tests on them.  Monitor tests that read
+// the time must implement monitortestframework.ClockedMonitorTest for the result to be deterministic.  StartCollection
+// and CollectData are never called, so the registry is put in offline mode and only monitor tests implementing
+// monitortestframework.OfflineMonitorTest run, the others are reported as not supported.
+func Replay(ctx context.Context, registry monitortestframework.MonitorTestRegistry, opts Options) (*Result, error) {
+	if opts.Speed < 0 {
+		return nil, fmt.Errorf("speed must not be negative, got %v", opts.Speed)
+	}
+
+	events := []replayEvent{}
+	for i := range opts.Intervals {
+		events = append(events, replayEvent{at: opts.Intervals[i].From, interval: &opts.Intervals[i]})
+	}
+	for i := range opts.ResourceUpdates {
+		events = append(events, replayEvent{at: opts.ResourceUpdates[i].At, resource: &opts.ResourceUpdates[i]})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].at.Before(events[j].at)
+	})
+
+	beginning, end := replayBounds(opts.Intervals, opts.ResourceUpdates)
+	clock := clocktesting.NewFakePassiveClock(beginning)
+	registry.SetClock(clock)
+	registry.SetOffline(true)
+	recorder := monitor.NewRecorderWithClock(clock)
+
+	for _, event := range events {
+		at := event.at
+		if at.Before(beginning) {
+			at = beginning
+		}
+		if err := waitFor(ctx, at.Sub(clock.Now()), opts.Speed); err != nil {
+			return nil, err
+		}
+		clock.SetTime(at)
+		if event.interval != nil {
+			recorder.AddIntervals(*event.interval)
+			continue
+		}
+		recorder.RecordResource(event.resource.ResourceType, event.resource.Object)
+	}
+	clock.SetTime(end)
+
+	ret := &Result{}
+	computedIntervals, computedJunits, err := registry.ConstructComputedIntervals(
+		ctx,
+		recorder.Intervals(time.Time{}, time.Time{}),
+		recorder.CurrentResourceState(),
+		beginning,
+		end)
+	if err != nil {
+		// like the monitor, errors are represented as junit, continue to evaluate the tests.
+		computedJunits = append(computedJunits, &junitapi.JUnitTestCase{
+			Name:          "replay should construct computed intervals",
+			FailureOutput: &junitapi.FailureOutput{Output: err.Error()},
+		})
+	}
+	recorder.AddIntervals(computedIntervals...)
+	ret.ComputedIntervals = computedIntervals
+	ret.Junits = append(ret.Junits, computedJunits...)
+
+	ret.FinalIntervals = registry.AnnotateIntervals(recorder.Intervals(beginning, end))
+	evaluatedJunits, err := registry.EvaluateTestsFromConstructedIntervals(ctx, ret.FinalIntervals)
+	if err != nil {
+		evaluatedJunits = append(evaluatedJunits, &junitapi.JUnitTestCase{
+			Name:          "replay should evaluate tests",
+			FailureOutput: &junitapi.FailureOutput{Output: err.Error()},
+		})
+	}
+	ret.Junits = append(ret.Junits, evaluatedJunits...)
+
+	return ret, nil
+}
+
+// ReplayFile replays the intervals of an e2e-events file from the artifacts of a run.
+func ReplayFile(ctx context.Context, registry monitortestframework.MonitorTestRegistry, filename string, speed float64) (*Result, error) {
+	intervals, err := monitorserialization.EventsFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read intervals from %q: %w", filename, err)
+	}
+	return Replay(ctx, registry, Options{Intervals: intervals, Speed: speed})
+}
+
+// replayBounds returns when the original run began and ended, as far as what it recorded tells.
+func replayBounds(intervals monitorapi.Intervals, resourceUpdates []ResourceUpdate) (time.Time, time.Time) {
+	var beginning, end time.Time
+	observe := func(t time.Time) {
+		if t.IsZero() {
+			return
+		}
+		if beginning.IsZero() || t.Before(beginning) {
+			beginning = t
+		}
+		if t.After(end) {
+			end = t
+		}
+	}
+	for _, interval := range intervals {
+		observe(interval.From)
+		observe(interval.To)
+	}
+	for _, update := range resourceUpdates {
+		observe(update.At)
+	}
+	return beginning, end
+}
+
+// waitFor waits for the time between two replayed events, shortened by speed.
+func waitFor(ctx context.Context, delta time.Duration, speed float64) error {
+	if speed == 0 || delta <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(time.Duration(float64(delta) / speed))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// overlapCounter computes an interval covering the run, stamped with the time it read from its clock and the number
+// of pods recorded.
+type overlapCounter struct {
+	clock clock.PassiveClock
+}
+
+func (w *overlapCounter) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *overlapCounter) SupportsOffline() bool {
+	return true
+}
+
+func (w *overlapCounter) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *overlapCounter) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *overlapCounter) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().HumanMessagef("computed at %s from %d pods", w.clock.Now().Format(time.RFC3339), len(recordedResources["pods"]))).
+			Build(beginning, end),
+	}, nil
+}
+
+func (w *overlapCounter) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return []*junitapi.JUnitTestCase{{Name: "replayed test"}}, nil
+}
+
+func (w *overlapCounter) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (w *overlapCounter) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := func(from, to time.Duration) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().HumanMessage("recorded")).
+			Build(start.Add(from), start.Add(to))
+	}
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID("uid-" + name)}}
+	}
+
+	registry := monitortestframework.NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("overlap-counter", "Test Framework", &overlapCounter{})
+
+	result, err := Replay(context.Background(), registry, Options{
+		Intervals: monitorapi.Intervals{interval(time.Hour, 2*time.Hour), interval(0, time.Minute)},
+		ResourceUpdates: []ResourceUpdate{
+			{At: start.Add(30 * time.Minute), ResourceType: "pods", Object: pod("a")},
+			{ResourceType: "pods", Object: pod("b")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.ComputedIntervals) != 1 {
+		t.Fatalf("expected one computed interval, got %v", result.ComputedIntervals)
+	}
+	computed := result.ComputedIntervals[0]
+	if !computed.From.Equal(start) || !computed.To.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("expected the computed interval to cover the replayed run, got %s to %s", computed.From, computed.To)
+	}
+	if expected := "computed at 2024-01-01T02:00:00Z from 2 pods"; computed.Message.HumanMessage != expected {
+		t.Errorf("expected %q, got %q", expected, computed.Message.HumanMessage)
+	}
+	if len(result.FinalIntervals) != 3 {
+		t.Errorf("expected the replayed and computed intervals, got %v", result.FinalIntervals)
+	}
+	if len(result.Junits) == 0 {
+		t.Errorf("expected the replayed tests to be evaluated")
+	}
+}
+
+func TestReplayNegativeSpeed(t *testing.T) {
+	if _, err := Replay(context.Background(), monitortestframework.NewMonitorTestRegistry(), Options{Speed: -1}); err == nil {
+		t.Errorf("expected a negative speed to be rejected")
+	}
+}
@@ -0,0 +1,232 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// ResourcePruningPolicy decides how much of a type of resource is recorded.  Recorded resources are serialized with
+// the artifacts, and on big clusters they dominate the size of the artifacts.
+type ResourcePruningPolicy struct {
+	// KeepManagedFields keeps metadata.managedFields, which are dropped otherwise.  No monitor test reads them from the
+	// recorded resources.
+	KeepManagedFields bool `json:"keepManagedFields,omitempty"`
+	// MaxStatusBytes is how large the serialized status of a resource may be.  Larger statuses are dropped and the
+	// resource is annotated with their size.  Zero keeps every status.
+	MaxStatusBytes int `json:"maxStatusBytes,omitempty"`
+	// MaxPerNamespace is how many instances are recorded in each namespace.  Instances beyond it are not recorded,
+	// while the instances already recorded keep being updated.  Zero records every instance.
+	MaxPerNamespace int `json:"maxPerNamespace,omitempty"`
+}
+
+// ResourcePruningPolicies are the policies for every type of resource, by the type they are recorded as.
+type ResourcePruningPolicies struct {
+	// Default applies to the types without a policy of their own.
+	Default   ResourcePruningPolicy            `json:"default"`
+	Resources map[string]ResourcePruningPolicy `json:"resources,omitempty"`
+}
+
+func (p ResourcePruningPolicies) policyFor(resourceType string) ResourcePruningPolicy {
+	if policy, ok := p.Resources[resourceType]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// LoadResourcePruningPolicies reads resource pruning policies from a yaml or json file.
+func LoadResourcePruningPolicies(path string) (ResourcePruningPolicies, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ResourcePruningPolicies{}, err
+	}
+	ret := ResourcePruningPolicies{}
+	if err := yaml.UnmarshalStrict(content, &ret); err != nil {
+		return ResourcePruningPolicies{}, fmt.Errorf("invalid resource pruning policies %q: %w", path, err)
+	}
+	policies := map[string]ResourcePruningPolicy{"default": ret.Default}
+	for resourceType, policy := range ret.Resources {
+		policies[resourceType] = policy
+	}
+	for resourceType, policy := range policies {
+		if policy.MaxStatusBytes < 0 || policy.MaxPerNamespace < 0 {
+			return ResourcePruningPolicies{}, fmt.Errorf("invalid resource pruning policies %q: limits of %q must not be negative", path, resourceType)
+		}
+	}
+	return ret, nil
+}
+
+// ResourceBudget prunes resources as they are recorded and accounts for the size of what is kept.
+type ResourceBudget struct {
+	policies ResourcePruningPolicies
+
+	lock  sync.Mutex
+	types map[string]*resourceTypeBudget
+}
+
+type resourceTypeBudget struct {
+	// instances are the recorded instances, as last recorded.  They are only serialized to account for their size
+	// when a report is asked for, so recording stays cheap.
+	instances map[monitorapi.InstanceKey]runtime.Object
+	// namespaceCounts are how many instances are recorded in each namespace.
+	namespaceCounts map[string]int
+	// dropped are the instances not recorded because their namespace was full.
+	dropped map[monitorapi.InstanceKey]bool
+
+	truncatedStatuses int
+}
+
+// NewResourceBudget returns a budget applying the policies.
+func NewResourceBudget(policies ResourcePruningPolicies) *ResourceBudget {
+	return &ResourceBudget{
+		policies: policies,
+		types:    map[string]*resourceTypeBudget{},
+	}
+}
+
+// prune prunes obj, which the recorder owns, in place.  It returns false if the instance must not be recorded.
+func (b *ResourceBudget) prune(resourceType string, key monitorapi.InstanceKey, obj runtime.Object, alreadyRecorded bool) bool {
+	policy := b.policies.policyFor(resourceType)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	typeBudget, ok := b.types[resourceType]
+	if !ok {
+		typeBudget = &resourceTypeBudget{
+			instances:       map[monitorapi.InstanceKey]runtime.Object{},
+			namespaceCounts: map[string]int{},
+			dropped:         map[monitorapi.InstanceKey]bool{},
+		}
+		b.types[resourceType] = typeBudget
+	}
+
+	if !alreadyRecorded {
+		if policy.MaxPerNamespace > 0 && typeBudget.namespaceCounts[key.Namespace] >= policy.MaxPerNamespace {
+			typeBudget.dropped[key] = true
+			return false
+		}
+		typeBudget.namespaceCounts[key.Namespace]++
+	}
+
+	if !policy.KeepManagedFields {
+		if metadata, err := meta.Accessor(obj); err == nil {
+			metadata.SetManagedFields(nil)
+		}
+	}
+	if policy.MaxStatusBytes > 0 {
+		if statusBytes := truncateStatus(obj, policy.MaxStatusBytes); statusBytes > 0 {
+			typeBudget.truncatedStatuses++
+		}
+	}
+
+	typeBudget.instances[key] = obj
+	return true
+}
+
+// truncateStatus drops the status of obj if it is larger than maxBytes serialized, and returns its size if it did.
+func truncateStatus(obj runtime.Object, maxBytes int) int {
+	var status interface{}
+	clearStatus := func() {}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		s, found := u.Object["status"]
+		if !found {
+			return 0
+		}
+		status = s
+		clearStatus = func() { delete(u.Object, "status") }
+	} else {
+		value := reflect.ValueOf(obj)
+		if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+			return 0
+		}
+		field := value.Elem().FieldByName("Status")
+		if !field.IsValid() || !field.CanSet() {
+			return 0
+		}
+		status = field.Interface()
+		clearStatus = func() { field.Set(reflect.Zero(field.Type())) }
+	}
+
+	serialized, err := json.Marshal(status)
+	if err != nil || len(serialized) <= maxBytes {
+		return 0
+	}
+	clearStatus()
+	if metadata, err := meta.Accessor(obj); err == nil {
+		annotations := metadata.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[monitorapi.StatusTruncatedAnnotation] = fmt.Sprintf("%d", len(serialized))
+		metadata.SetAnnotations(annotations)
+	}
+	return len(serialized)
+}
+
+// ResourceBudgetReport is the size of the recorded resources of each type.
+type ResourceBudgetReport struct {
+	Types []ResourceTypeBudgetReport `json:"types"`
+}
+
+type ResourceTypeBudgetReport struct {
+	ResourceType string                `json:"resourceType"`
+	Policy       ResourcePruningPolicy `json:"policy"`
+	// Instances are how many instances are recorded, and Bytes their serialized size.
+	Instances int `json:"instances"`
+	Bytes     int `json:"bytes"`
+	// TruncatedStatuses are how many times a status was dropped for being too large.
+	TruncatedStatuses int `json:"truncatedStatuses,omitempty"`
+	// DroppedInstances are how many instances were not recorded because their namespace was full.
+	DroppedInstances int `json:"droppedInstances,omitempty"`
+}
+
+// Report returns the size of the recorded resources, largest type first.  It serializes every recorded instance, so
+// it is meant to be called once the run is over.
+func (b *ResourceBudget) Report() ResourceBudgetReport {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	ret := ResourceBudgetReport{Types: []ResourceTypeBudgetReport{}}
+	for resourceType, typeBudget := range b.types {
+		typeReport := ResourceTypeBudgetReport{
+			ResourceType:      resourceType,
+			Policy:            b.policies.policyFor(resourceType),
+			Instances:         len(typeBudget.instances),
+			TruncatedStatuses: typeBudget.truncatedStatuses,
+			DroppedInstances:  len(typeBudget.dropped),
+		}
+		for _, obj := range typeBudget.instances {
+			if serialized, err := json.Marshal(obj); err == nil {
+				typeReport.Bytes += len(serialized)
+			}
+		}
+		ret.Types = append(ret.Types, typeReport)
+	}
+	sort.Slice(ret.Types, func(i, j int) bool {
+		if ret.Types[i].Bytes != ret.Types[j].Bytes {
+			return ret.Types[i].Bytes > ret.Types[j].Bytes
+		}
+		return ret.Types[i].ResourceType < ret.Types[j].ResourceType
+	})
+	return ret
+}
+
+// WriteReport writes the report to resource-budget<suffix>.json in dir.
+func (b *ResourceBudget) WriteReport(dir, suffix string) error {
+	content, err := json.MarshalIndent(b.Report(), "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fmt.Sprintf("resource-budget%s.json", suffix)), content, 0644)
+}
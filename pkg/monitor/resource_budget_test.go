@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func budgetTestPod(namespace, name, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:     namespace,
+			Name:          name,
+			UID:           types.UID(namespace + "-" + name),
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, Message: message},
+	}
+}
+
+func TestResourceBudget(t *testing.T) {
+	budget := NewResourceBudget(ResourcePruningPolicies{
+		Resources: map[string]ResourcePruningPolicy{
+			"pods": {MaxStatusBytes: 100, MaxPerNamespace: 2},
+		},
+	})
+	recorder := NewRecorderWithResourceBudget(budget)
+
+	recorder.RecordResource("pods", budgetTestPod("openshift-etcd", "etcd-0", "short"))
+	recorder.RecordResource("pods", budgetTestPod("openshift-etcd", "etcd-1", strings.Repeat("long", 100)))
+	recorder.RecordResource("pods", budgetTestPod("openshift-etcd", "etcd-2", "short"))
+	// updates of recorded instances are kept when the namespace is full.
+	recorder.RecordResource("pods", budgetTestPod("openshift-etcd", "etcd-0", "updated"))
+	recorder.RecordResource("pods", budgetTestPod("openshift-dns", "dns-0", "short"))
+	recorder.RecordResource("crs", &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Example",
+		"metadata": map[string]interface{}{
+			"name":          "example",
+			"managedFields": []interface{}{map[string]interface{}{"manager": "operator"}},
+		},
+		"status": map[string]interface{}{"history": strings.Repeat("long", 100)},
+	}})
+
+	pods := recorder.CurrentResourceState()["pods"]
+	if len(pods) != 3 {
+		t.Fatalf("expected two pods in openshift-etcd and one in openshift-dns, got %d", len(pods))
+	}
+	for key, obj := range pods {
+		pod := obj.(*corev1.Pod)
+		if len(pod.ManagedFields) > 0 {
+			t.Errorf("expected the managed fields of %v to be pruned", key)
+		}
+		switch pod.Name {
+		case "etcd-0":
+			if pod.Status.Message != "updated" {
+				t.Errorf("expected the update to be recorded, got %q", pod.Status.Message)
+			}
+		case "etcd-1":
+			if pod.Status.Phase != "" || len(pod.Annotations[monitorapi.StatusTruncatedAnnotation]) == 0 {
+				t.Errorf("expected the large status to be dropped, got %#v %v", pod.Status, pod.Annotations)
+			}
+		case "etcd-2":
+			t.Error("expected the third pod in openshift-etcd not to be recorded")
+		}
+	}
+	for _, obj := range recorder.CurrentResourceState()["crs"] {
+		u := obj.(*unstructured.Unstructured)
+		if _, found := u.Object["status"]; !found {
+			t.Error("expected the default policy to keep the status")
+		}
+		if len(u.GetManagedFields()) > 0 {
+			t.Error("expected the default policy to prune managed fields")
+		}
+	}
+
+	report := budget.Report()
+	if len(report.Types) != 2 || report.Types[0].ResourceType != "pods" {
+		t.Fatalf("expected pods to be the largest type, got %#v", report.Types)
+	}
+	podsReport := report.Types[0]
+	if podsReport.Instances != 3 || podsReport.DroppedInstances != 1 || podsReport.TruncatedStatuses != 1 || podsReport.Bytes == 0 {
+		t.Errorf("unexpected report %#v", podsReport)
+	}
+}
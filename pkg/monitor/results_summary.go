@@ -0,0 +1,221 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type TestStatus string
+
+var (
+	TestPassed  TestStatus = "Passed"
+	TestFailed  TestStatus = "Failed"
+	TestFlaked  TestStatus = "Flaked"
+	TestSkipped TestStatus = "Skipped"
+)
+
+// ResultsSummary is a machine-readable summary of every monitor test outcome.  It is written next to the junit
+// so that tooling like risk analysis can consume structured failure reasons instead of scraping failure messages.
+type ResultsSummary struct {
+	SuiteName string `json:"suiteName"`
+
+	NumTests   int `json:"numTests"`
+	NumFailed  int `json:"numFailed"`
+	NumFlaked  int `json:"numFlaked"`
+	NumSkipped int `json:"numSkipped"`
+
+	Tests []TestResult `json:"tests"`
+
+	// Intervals holds every interval linked from a test case, keyed by interval ID.
+	Intervals map[string]json.RawMessage `json:"intervals,omitempty"`
+}
+
+// TestResult is the aggregated outcome of every junit with the same name.  A test that both passed and failed
+// is a flake.
+type TestResult struct {
+	Name          string     `json:"name"`
+	Status        TestStatus `json:"status"`
+	MonitorTest   string     `json:"monitorTest,omitempty"`
+	JiraComponent string     `json:"jiraComponent,omitempty"`
+
+	Cases []TestCaseResult `json:"cases"`
+}
+
+type TestCaseResult struct {
+	Status         TestStatus                `json:"status"`
+	Duration       float64                   `json:"durationSeconds"`
+	FailureMessage string                    `json:"failureMessage,omitempty"`
+	SkipMessage    string                    `json:"skipMessage,omitempty"`
+	Reason         string                    `json:"reason,omitempty"`
+	Severity       junitapi.Severity         `json:"severity,omitempty"`
+	IntervalIDs    []string                  `json:"intervalIDs,omitempty"`
+	Thresholds     []junitapi.JUnitThreshold `json:"thresholds,omitempty"`
+	QuarantineBug  string                    `json:"quarantineBug,omitempty"`
+}
+
+// BuildResultsSummary aggregates junits by name and resolves any linked interval IDs against the provided intervals.
+func BuildResultsSummary(suiteName string, junits []*junitapi.JUnitTestCase, intervals monitorapi.Intervals) (*ResultsSummary, error) {
+	summary := &ResultsSummary{
+		SuiteName: suiteName,
+		Tests:     []TestResult{},
+	}
+
+	testNameToIndex := map[string]int{}
+	linkedIntervalIDs := map[string]bool{}
+	for _, junit := range junits {
+		if junit == nil {
+			continue
+		}
+		currCase := TestCaseResult{
+			Status:   TestPassed,
+			Duration: junit.Duration,
+		}
+		switch {
+		case junit.FailureOutput != nil:
+			currCase.Status = TestFailed
+			currCase.FailureMessage = junit.FailureOutput.Output
+			if len(currCase.FailureMessage) == 0 {
+				currCase.FailureMessage = junit.FailureOutput.Message
+			}
+		case junit.SkipMessage != nil:
+			currCase.Status = TestSkipped
+			currCase.SkipMessage = junit.SkipMessage.Message
+		}
+		if junit.Details != nil {
+			currCase.Reason = junit.Details.Reason
+			currCase.Severity = junit.Details.Severity
+			currCase.IntervalIDs = junit.Details.IntervalIDs
+			currCase.Thresholds = junit.Details.Thresholds
+			currCase.QuarantineBug = junit.Details.QuarantineBug
+			for _, id := range junit.Details.IntervalIDs {
+				linkedIntervalIDs[id] = true
+			}
+		}
+
+		idx, ok := testNameToIndex[junit.Name]
+		if !ok {
+			idx = len(summary.Tests)
+			testNameToIndex[junit.Name] = idx
+			summary.Tests = append(summary.Tests, TestResult{Name: junit.Name})
+		}
+		result := &summary.Tests[idx]
+		if junit.Details != nil {
+			if len(result.MonitorTest) == 0 {
+				result.MonitorTest = junit.Details.MonitorTest
+			}
+			if len(result.JiraComponent) == 0 {
+				result.JiraComponent = junit.Details.JiraComponent
+			}
+		}
+		result.Cases = append(result.Cases, currCase)
+	}
+
+	for i := range summary.Tests {
+		summary.Tests[i].Status = aggregateStatus(summary.Tests[i].Cases)
+		switch summary.Tests[i].Status {
+		case TestFailed:
+			summary.NumFailed++
+		case TestFlaked:
+			summary.NumFlaked++
+		case TestSkipped:
+			summary.NumSkipped++
+		}
+	}
+	summary.NumTests = len(summary.Tests)
+	sort.SliceStable(summary.Tests, func(i, j int) bool {
+		return summary.Tests[i].Name < summary.Tests[j].Name
+	})
+
+	if len(linkedIntervalIDs) == 0 {
+		return summary, nil
+	}
+	summary.Intervals = map[string]json.RawMessage{}
+	for _, interval := range intervals {
+		id := interval.ID()
+		if !linkedIntervalIDs[id] {
+			continue
+		}
+		intervalJSON, err := monitorserialization.IntervalToOneLineJSON(interval)
+		if err != nil {
+			return nil, err
+		}
+		summary.Intervals[id] = intervalJSON
+	}
+
+	return summary, nil
+}
+
+func aggregateStatus(cases []TestCaseResult) TestStatus {
+	passed, failed := false, false
+	for _, currCase := range cases {
+		switch currCase.Status {
+		case TestPassed:
+			passed = true
+		case TestFailed:
+			failed = true
+		}
+	}
+	switch {
+	case passed && failed:
+		return TestFlaked
+	case failed:
+		return TestFailed
+	case passed:
+		return TestPassed
+	default:
+		return TestSkipped
+	}
+}
+
+// maxConsoleFailureLines is how many lines of the output of a failed test the console summary prints.
+const maxConsoleFailureLines = 5
+
+// WriteConsoleSummary prints the failed and flaked tests of the summary with the beginning of their failures, for a
+// developer watching a terminal rather than reading artifacts.
+func WriteConsoleSummary(out io.Writer, summary *ResultsSummary) {
+	fmt.Fprintf(out, "\n%d monitor tests: %d passed, %d failed, %d flaked, %d skipped\n",
+		summary.NumTests, summary.NumTests-summary.NumFailed-summary.NumFlaked-summary.NumSkipped, summary.NumFailed, summary.NumFlaked, summary.NumSkipped)
+	for _, status := range []TestStatus{TestFailed, TestFlaked} {
+		for _, test := range summary.Tests {
+			if test.Status != status {
+				continue
+			}
+			fmt.Fprintf(out, "\n%s: %s\n", strings.ToUpper(string(status)), test.Name)
+			if len(test.MonitorTest) > 0 {
+				fmt.Fprintf(out, "  monitor test %s (%s)\n", test.MonitorTest, test.JiraComponent)
+			}
+			for _, testCase := range test.Cases {
+				if testCase.Status != TestFailed {
+					continue
+				}
+				lines := strings.Split(strings.TrimSpace(testCase.FailureMessage), "\n")
+				if len(lines) > maxConsoleFailureLines {
+					lines = append(lines[:maxConsoleFailureLines], fmt.Sprintf("... %d more lines", len(lines)-maxConsoleFailureLines))
+				}
+				for _, line := range lines {
+					fmt.Fprintf(out, "    %s\n", line)
+				}
+				break
+			}
+		}
+	}
+}
+
+func writeResultsSummary(storageDir, fileSuffix string, summary *ResultsSummary) error {
+	jsonContent, err := json.MarshalIndent(summary, "", "    ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(storageDir, fmt.Sprintf("%s_%s.json", "e2e-monitor-tests-results", fileSuffix))
+	fmt.Fprintf(os.Stderr, "Writing results summary to %s\n", path)
+	return os.WriteFile(path, jsonContent, 0644)
+}
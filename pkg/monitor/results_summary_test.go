@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestBuildResultsSummary(t *testing.T) {
+	interval := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Error).
+		Locator(monitorapi.NewLocator().NodeFromName("foo")).
+		Message(monitorapi.NewMessage().HumanMessage("node went away")).
+		Build(time.Unix(1, 0), time.Unix(10, 0))
+	unlinked := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("bar")).
+		Message(monitorapi.NewMessage().HumanMessage("nothing to see")).
+		Build(time.Unix(1, 0), time.Unix(10, 0))
+
+	junits := []*junitapi.JUnitTestCase{
+		{
+			Name:          "flaky",
+			FailureOutput: &junitapi.FailureOutput{Output: "bad"},
+			Details: &junitapi.JUnitTestCaseDetails{
+				MonitorTest: "node-lifecycle",
+				Reason:      "NodeUnreachable",
+				IntervalIDs: []string{interval.ID()},
+				Thresholds:  []junitapi.JUnitThreshold{{Name: "unreachable-seconds", Limit: 5, Observed: 9, Exceeded: true}},
+			},
+		},
+		{Name: "flaky"},
+		{Name: "failing", FailureOutput: &junitapi.FailureOutput{Output: "bad"}},
+		{Name: "passing"},
+		{Name: "skipped", SkipMessage: &junitapi.SkipMessage{Message: "not here"}},
+	}
+
+	summary, err := BuildResultsSummary("suite", junits, monitorapi.Intervals{interval, unlinked})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.NumTests != 4 || summary.NumFailed != 1 || summary.NumFlaked != 1 || summary.NumSkipped != 1 {
+		t.Errorf("unexpected counts: %#v", summary)
+	}
+	expectedStatus := map[string]TestStatus{
+		"flaky":   TestFlaked,
+		"failing": TestFailed,
+		"passing": TestPassed,
+		"skipped": TestSkipped,
+	}
+	for _, result := range summary.Tests {
+		if result.Status != expectedStatus[result.Name] {
+			t.Errorf("%q: expected %v, got %v", result.Name, expectedStatus[result.Name], result.Status)
+		}
+		if result.Name == "flaky" && result.MonitorTest != "node-lifecycle" {
+			t.Errorf("expected monitor test to be carried, got %q", result.MonitorTest)
+		}
+	}
+	if len(summary.Intervals) != 1 {
+		t.Fatalf("expected only the linked interval, got %d", len(summary.Intervals))
+	}
+	if _, ok := summary.Intervals[interval.ID()]; !ok {
+		t.Errorf("missing linked interval %v", interval.ID())
+	}
+}
+
+func TestWriteConsoleSummary(t *testing.T) {
+	summary, err := BuildResultsSummary("invariants", []*junitapi.JUnitTestCase{
+		{Name: "passes"},
+		{Name: "skipped", SkipMessage: &junitapi.SkipMessage{Message: "not applicable"}},
+		{Name: "flakes", FailureOutput: &junitapi.FailureOutput{Output: "failed once"}},
+		{Name: "flakes"},
+		{
+			Name:          "fails",
+			FailureOutput: &junitapi.FailureOutput{Output: "1\n2\n3\n4\n5\n6\n7"},
+			Details:       &junitapi.JUnitTestCaseDetails{MonitorTest: "node-state-analyzer", JiraComponent: "Node / Kubelet"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	WriteConsoleSummary(out, summary)
+	expected := strings.Join([]string{
+		"",
+		"4 monitor tests: 1 passed, 1 failed, 1 flaked, 1 skipped",
+		"",
+		"FAILED: fails",
+		"  monitor test node-state-analyzer (Node / Kubelet)",
+		"    1",
+		"    2",
+		"    3",
+		"    4",
+		"    5",
+		"    ... 2 more lines",
+		"",
+		"FLAKED: flakes",
+		"    failed once",
+		"",
+	}, "\n")
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
@@ -4,16 +4,28 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/kube-openapi/pkg/util/sets"
 )
 
+// typedResources are the types of the resources monitor tests record without their kind, as informers hand them out.
+var typedResources = map[string]func() runtime.Object{
+	"pods":   func() runtime.Object { return &corev1.Pod{} },
+	"events": func() runtime.Object { return &corev1.Event{} },
+}
+
 func InstanceMapToFile(filename string, resourceType string, instances monitorapi.InstanceMap) error {
 	namespaceToKeys := map[string][]monitorapi.InstanceKey{}
 	for key, obj := range instances {
@@ -73,3 +85,74 @@ func InstanceMapToFile(filename string, resourceType string, instances monitorap
 
 	return ioutil.WriteFile(filename, byteBuffer.Bytes(), 0644)
 }
+
+// ResourcesFromFile reads the resources InstanceMapToFile wrote.  Resources of a type monitor tests expect typed, like
+// pods, or with a kind the client scheme knows are read into their type, the others stay unstructured.
+func ResourcesFromFile(filename string) (monitorapi.ResourcesMap, error) {
+	zipReader, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	ret := monitorapi.ResourcesMap{}
+	for _, file := range zipReader.File {
+		// every namespace is a directory holding a list of the resources of the type.
+		resourceType := strings.TrimSuffix(path.Base(filepath.ToSlash(file.Name)), ".json")
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", file.Name, err)
+		}
+		// the resources usually have no kind, which unstructured objects cannot be read without.
+		list := struct {
+			Items []map[string]interface{} `json:"items"`
+		}{}
+		if err := json.Unmarshal(content, &list); err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", file.Name, err)
+		}
+
+		instances, ok := ret[resourceType]
+		if !ok {
+			instances = monitorapi.InstanceMap{}
+			ret[resourceType] = instances
+		}
+		for i := range list.Items {
+			item := &unstructured.Unstructured{Object: list.Items[i]}
+			obj, err := typedResource(resourceType, item)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s %s/%s: %w", resourceType, item.GetNamespace(), item.GetName(), err)
+			}
+			instances[monitorapi.InstanceKey{
+				Namespace: item.GetNamespace(),
+				Name:      item.GetName(),
+				UID:       string(item.GetUID()),
+			}] = obj
+		}
+	}
+	return ret, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func typedResource(resourceType string, item *unstructured.Unstructured) (runtime.Object, error) {
+	var obj runtime.Object
+	if newObject, ok := typedResources[resourceType]; ok {
+		obj = newObject()
+	} else if gvk := item.GroupVersionKind(); len(gvk.Kind) > 0 && scheme.Scheme.Recognizes(gvk) {
+		obj, _ = scheme.Scheme.New(gvk)
+	}
+	if obj == nil {
+		return item, nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
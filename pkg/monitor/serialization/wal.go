@@ -0,0 +1,167 @@
+package monitorserialization
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WALOperation is what a recorder did, in the order it did it.
+type WALOperation string
+
+const (
+	// WALOpen is written when a recorder starts writing to the log, on a line of its own in case the previous recorder
+	// was killed in the middle of a line.  The indexes of started intervals restart with every recorder, so a log
+	// appended to by a restarted monitor is replayed one recorder at a time.
+	WALOpen WALOperation = "Open"
+	// WALAdd is an interval added to the recorder.
+	WALAdd WALOperation = "Add"
+	// WALStart is an interval started at Index, which a later WALEnd may end.
+	WALStart WALOperation = "Start"
+	// WALEnd sets the To of the interval started at Index.
+	WALEnd WALOperation = "End"
+)
+
+// WALEntry is one line of a recorder write-ahead log.
+type WALEntry struct {
+	Operation WALOperation `json:"op"`
+	Index     int          `json:"index,omitempty"`
+
+	Interval *EventInterval `json:"interval,omitempty"`
+	To       *metav1.Time   `json:"to,omitempty"`
+}
+
+// NewWALEntry returns the entry for the operation on interval, which may be nil for WALOpen and WALEnd.
+func NewWALEntry(operation WALOperation, index int, interval *monitorapi.Interval) WALEntry {
+	entry := WALEntry{
+		Operation: operation,
+		Index:     index,
+	}
+	if interval == nil {
+		return entry
+	}
+	if operation == WALEnd {
+		entry.To = &metav1.Time{Time: interval.To}
+		return entry
+	}
+	serialized := monitorEventIntervalToEventInterval(*interval)
+	entry.Interval = &serialized
+	return entry
+}
+
+// WALEntryToOneLineJSON returns the entry as a single line, without the trailing newline.
+func WALEntryToOneLineJSON(entry WALEntry) ([]byte, error) {
+	spacedBytes, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, spacedBytes); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// IntervalsFromWALFile replays the recorder write-ahead log in filename.  A log that does not exist has no intervals.
+func IntervalsFromWALFile(filename string) (monitorapi.Intervals, error) {
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return IntervalsFromWAL(file)
+}
+
+// IntervalsFromWAL replays a recorder write-ahead log into the intervals the recorders writing it held.  Intervals
+// that were started and never ended keep a zero To, as they would in the recorder.  The process writing the log may
+// have been killed in the middle of a line, so the last line of every recorder is dropped if it cannot be read.
+func IntervalsFromWAL(wal io.Reader) (monitorapi.Intervals, error) {
+	intervals := monitorapi.Intervals{}
+	// started maps the indexes the current recorder handed out to positions in intervals.
+	started := map[int]int{}
+
+	scanner := bufio.NewScanner(wal)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNumber := 0
+	var lineErr error
+	for scanner.Scan() {
+		lineNumber++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := WALEntry{}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			lineErr = fmt.Errorf("line %d of the write-ahead log cannot be read: %w", lineNumber, err)
+			continue
+		}
+		// only the last line a recorder wrote can be torn, an unreadable line anywhere else is corruption.
+		if lineErr != nil && entry.Operation != WALOpen {
+			return nil, lineErr
+		}
+		lineErr = nil
+
+		switch entry.Operation {
+		case WALOpen:
+			started = map[int]int{}
+		case WALAdd, WALStart:
+			if entry.Interval == nil {
+				return nil, fmt.Errorf("line %d of the write-ahead log has no interval", lineNumber)
+			}
+			interval, err := eventIntervalToInterval(*entry.Interval)
+			if err != nil {
+				return nil, fmt.Errorf("line %d of the write-ahead log: %w", lineNumber, err)
+			}
+			if entry.Operation == WALStart {
+				started[entry.Index] = len(intervals)
+			}
+			intervals = append(intervals, interval)
+		case WALEnd:
+			position, ok := started[entry.Index]
+			if !ok || entry.To == nil {
+				return nil, fmt.Errorf("line %d of the write-ahead log ends interval %d, which was not started", lineNumber, entry.Index)
+			}
+			if intervals[position].From.Before(entry.To.Time) {
+				intervals[position].To = entry.To.Time
+			}
+		default:
+			return nil, fmt.Errorf("line %d of the write-ahead log has unknown operation %q", lineNumber, entry.Operation)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return intervals, nil
+}
+
+func eventIntervalToInterval(interval EventInterval) (monitorapi.Interval, error) {
+	level, err := monitorapi.ConditionLevelFromString(interval.Level)
+	if err != nil {
+		return monitorapi.Interval{}, err
+	}
+	return monitorapi.Interval{
+		Source:  monitorapi.IntervalSource(interval.Source),
+		Display: interval.Display,
+		Condition: monitorapi.Condition{
+			Level:   level,
+			Locator: interval.Locator,
+			Message: interval.Message,
+		},
+
+		From: interval.From.Time,
+		To:   interval.To.Time,
+	}, nil
+}
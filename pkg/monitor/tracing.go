@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// tracingEndpointEnvVars are the standard OTLP environment variables.  If none are set, tracing is disabled.
+// The exporter reads these, along with the other OTEL_EXPORTER_OTLP_* variables, directly.
+var tracingEndpointEnvVars = []string{
+	"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+	"OTEL_EXPORTER_OTLP_ENDPOINT",
+}
+
+const monitorTracerName = "github.com/openshift/origin/pkg/monitor"
+
+func tracingEnabled() bool {
+	for _, envVar := range tracingEndpointEnvVars {
+		if len(os.Getenv(envVar)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// startTracing installs a global tracer provider that exports spans over OTLP to the endpoint in the environment.
+// The returned function flushes and stops the exporter.  When tracing is not configured, nothing is installed and
+// the global no-op provider stays in place.
+func startTracing(ctx context.Context) (func(context.Context) error, error) {
+	if !tracingEnabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP trace exporter: %w", err)
+	}
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", "openshift-tests-monitor")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	// w3c trace context lets spans be correlated with cluster side traces.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tracerProvider.Shutdown, nil
+}
@@ -8,6 +8,8 @@ type Interface interface {
 	Start(ctx context.Context) error
 	Stop(ctx context.Context) (ResultState, error)
 	SerializeResults(ctx context.Context, junitSuiteName, timeSuffix string) error
+	// ResultsSummary returns the outcome of every monitor test once SerializeResults wrote it, or nil before.
+	ResultsSummary() *ResultsSummary
 }
 
 type ResultState string
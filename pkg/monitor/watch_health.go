@@ -0,0 +1,392 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// watchStallThreshold is how long a watch that asked for bookmarks may go without receiving anything.  The
+	// apiserver sends a bookmark about every minute, so a longer silence means the watch fell behind.
+	watchStallThreshold = 3 * time.Minute
+	// watchTimeoutSlack is how early a watch may end before the timeout it asked for and still be a normal end.
+	watchTimeoutSlack = 2 * time.Second
+)
+
+// watchHealth follows every watch stream opened by clients built from the monitor's rest config.  When a stream
+// disconnects, the monitor tests reading it miss whatever happened until it reconnects, and a relist afterwards only
+// shows the end state.  Recording that as intervals distinguishes missing intervals from nothing happening, for
+// instance during apiserver disruption.
+type watchHealth struct {
+	recorder monitorapi.RecorderWriter
+	now      func() time.Time
+
+	lock      sync.Mutex
+	resources map[string]*watchedResource
+}
+
+// watchedResource is the health of the watches of a path, which identifies the resource and the namespace.
+type watchedResource struct {
+	locator monitorapi.Locator
+
+	// disconnected is set from when a stream ended unexpectedly until a new stream is established.
+	disconnected         bool
+	disconnectedInterval int
+	// relistPending is set after an unexpected end, the next list of the path is the resync.
+	relistPending bool
+
+	disconnects int
+	resyncs     int
+	stalls      int
+}
+
+func newWatchHealth(recorder monitorapi.RecorderWriter) *watchHealth {
+	return &watchHealth{
+		recorder:  recorder,
+		now:       time.Now,
+		resources: map[string]*watchedResource{},
+	}
+}
+
+// wrapConfig returns a copy of the config whose clients report the health of their watches.
+func (w *watchHealth) wrapConfig(config *rest.Config) *rest.Config {
+	ret := rest.CopyConfig(config)
+	ret.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &watchHealthRoundTripper{health: w, delegate: rt}
+	})
+	return ret
+}
+
+func (w *watchHealth) resourceFor(path string) *watchedResource {
+	resource, ok := w.resources[path]
+	if !ok {
+		resource = &watchedResource{locator: watchLocator(path)}
+		w.resources[path] = resource
+	}
+	return resource
+}
+
+// watchLocator locates the watches of a path like /apis/group/version/namespaces/namespace/resource.
+func watchLocator(path string) monitorapi.Locator {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	group := ""
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		group = segments[1]
+		segments = segments[3:]
+	default:
+		return monitorapi.NewLocator().APIRequest(path, "watch", "")
+	}
+	scope := "cluster"
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		scope = "namespace"
+		segments = segments[2:]
+	}
+	if len(segments) == 0 {
+		return monitorapi.NewLocator().APIRequest(path, "watch", "")
+	}
+	resource := segments[0]
+	if len(group) > 0 {
+		resource = resource + "." + group
+	}
+	return monitorapi.NewLocator().APIRequest(resource, "watch", scope)
+}
+
+// connected ends the disconnection of the path, if there was one.
+func (w *watchHealth) connected(path string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	resource := w.resourceFor(path)
+	if !resource.disconnected {
+		return
+	}
+	resource.disconnected = false
+	w.recorder.EndInterval(resource.disconnectedInterval, w.now())
+}
+
+// disconnected records that a stream of the path ended unexpectedly.
+func (w *watchHealth) disconnected(path string, cause error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	resource := w.resourceFor(path)
+	resource.relistPending = true
+	if resource.disconnected {
+		return
+	}
+	resource.disconnected = true
+	resource.disconnects++
+	message := monitorapi.NewMessage().Reason(monitorapi.MonitorWatchStreamDisconnected).
+		HumanMessage("monitor watch stream disconnected, intervals from it are missing until it reconnects")
+	if cause != nil {
+		message = message.Cause(cause.Error())
+	}
+	resource.disconnectedInterval = w.recorder.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourceMonitorWatchStreams, monitorapi.Warning).
+			Locator(resource.locator).
+			Message(message).
+			Display().
+			Build(w.now(), time.Time{}),
+	)
+}
+
+// listed records the resync of the path, if a stream of it ended unexpectedly since it was last listed.
+func (w *watchHealth) listed(path string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	resource, ok := w.resources[path]
+	if !ok || !resource.relistPending {
+		return
+	}
+	resource.relistPending = false
+	resource.resyncs++
+	now := w.now()
+	w.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceMonitorWatchStreams, monitorapi.Warning).
+			Locator(resource.locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.MonitorWatchStreamResynced).
+				HumanMessage("monitor relisted after its watch stream disconnected, changes in between are only seen as their end state")).
+			Build(now, now),
+	)
+}
+
+// stallStarted records that a stream of the path has received nothing, not even bookmarks, since since.  The stall
+// lasts until stallEnded, or until the end of the run if the stream never receives anything again.
+func (w *watchHealth) stallStarted(path string, since time.Time) int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	resource := w.resourceFor(path)
+	resource.stalls++
+	return w.recorder.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourceMonitorWatchStreams, monitorapi.Warning).
+			Locator(resource.locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.MonitorWatchStreamStalled).
+				HumanMessagef("monitor watch stream received no bookmarks for more than %v, it fell behind", watchStallThreshold)).
+			Display().
+			Build(since, time.Time{}),
+	)
+}
+
+// stallEnded ends a stall started by stallStarted.
+func (w *watchHealth) stallEnded(stallInterval int) {
+	w.recorder.EndInterval(stallInterval, w.now())
+}
+
+// stalled records that a stream of the path received nothing, not even bookmarks, from since until now.
+func (w *watchHealth) stalled(path string, since time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	resource := w.resourceFor(path)
+	resource.stalls++
+	now := w.now()
+	w.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceMonitorWatchStreams, monitorapi.Warning).
+			Locator(resource.locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.MonitorWatchStreamStalled).
+				HumanMessagef("monitor watch stream received no bookmarks for %v, it fell behind", now.Sub(since).Round(time.Second))).
+			Display().
+			Build(since, now),
+	)
+}
+
+const watchHealthTestName = "[sig-arch] monitor watch streams should stay connected for the whole run"
+
+// junits flakes when watch streams disconnected or fell behind.  Intervals may be missing from those times, so other
+// failures may be explained by missing data, but the job still has results.
+func (w *watchHealth) junits() []*junitapi.JUnitTestCase {
+	if w == nil {
+		return nil
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	success := &junitapi.JUnitTestCase{Name: watchHealthTestName}
+	lines := []string{}
+	for _, resource := range w.resources {
+		if resource.disconnects == 0 && resource.stalls == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d disconnects, %d resyncs, %d stalls", resource.locator.OldLocator(), resource.disconnects, resource.resyncs, resource.stalls))
+	}
+	if len(lines) == 0 {
+		return []*junitapi.JUnitTestCase{success}
+	}
+	sort.Strings(lines)
+	failureMsg := fmt.Sprintf("monitor watch streams disconnected or fell behind, see %s intervals for when intervals may be missing\n%s",
+		monitorapi.SourceMonitorWatchStreams, strings.Join(lines, "\n"))
+	return []*junitapi.JUnitTestCase{
+		{
+			Name:          watchHealthTestName,
+			SystemOut:     failureMsg,
+			FailureOutput: &junitapi.FailureOutput{Output: failureMsg},
+		},
+		success,
+	}
+}
+
+// watchHealthRoundTripper follows the watch requests, and the lists that follow a watch ending unexpectedly.
+type watchHealthRoundTripper struct {
+	health   *watchHealth
+	delegate http.RoundTripper
+}
+
+var _ http.RoundTripper = &watchHealthRoundTripper{}
+
+func (rt *watchHealthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if req.Method != http.MethodGet {
+		return resp, err
+	}
+	query := req.URL.Query()
+	watch, _ := strconv.ParseBool(query.Get("watch"))
+	if !watch {
+		if err == nil && resp.StatusCode == http.StatusOK {
+			rt.health.listed(req.URL.Path)
+		}
+		return resp, err
+	}
+	if err != nil || resp.StatusCode != http.StatusOK {
+		// the reflector lists again after failing to watch.
+		return resp, err
+	}
+
+	rt.health.connected(req.URL.Path)
+	body := &watchBody{
+		ReadCloser: resp.Body,
+		health:     rt.health,
+		path:       req.URL.Path,
+		request:    req,
+		started:    rt.health.now(),
+	}
+	body.lastRead = body.started
+	if timeoutSeconds, err := strconv.Atoi(query.Get("timeoutSeconds")); err == nil {
+		body.timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	body.bookmarks, _ = strconv.ParseBool(query.Get("allowWatchBookmarks"))
+	body.lock.Lock()
+	body.armStallTimerLocked()
+	body.lock.Unlock()
+	resp.Body = body
+	return resp, nil
+}
+
+func (rt *watchHealthRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
+
+// watchBody reports how the stream it reads ends, and silences longer than bookmarks allow.
+type watchBody struct {
+	io.ReadCloser
+	health  *watchHealth
+	path    string
+	request *http.Request
+
+	started   time.Time
+	timeout   time.Duration
+	bookmarks bool
+
+	lock     sync.Mutex
+	lastRead time.Time
+	ended    bool
+	// stallTimer fires when a stream that asked for bookmarks receives nothing for longer than watchStallThreshold,
+	// so a stream that hangs forever is reported too.
+	stallTimer    *time.Timer
+	stalling      bool
+	stallInterval int
+}
+
+func (b *watchBody) armStallTimerLocked() {
+	if !b.bookmarks {
+		return
+	}
+	if b.stallTimer != nil {
+		b.stallTimer.Stop()
+	}
+	b.stallTimer = time.AfterFunc(watchStallThreshold, b.checkStall)
+}
+
+// checkStall starts a stall if nothing was read for longer than watchStallThreshold.
+func (b *watchBody) checkStall() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.ended || b.stalling {
+		return
+	}
+	if silence := b.health.now().Sub(b.lastRead); silence <= watchStallThreshold {
+		b.stallTimer = time.AfterFunc(watchStallThreshold-silence, b.checkStall)
+		return
+	}
+	b.stalling = true
+	b.stallInterval = b.health.stallStarted(b.path, b.lastRead)
+}
+
+// endLocked stops following the stream, and ends the stall it is in.
+func (b *watchBody) endLocked() {
+	b.ended = true
+	if b.stallTimer != nil {
+		b.stallTimer.Stop()
+	}
+	if b.stalling {
+		b.stalling = false
+		b.health.stallEnded(b.stallInterval)
+	}
+}
+
+func (b *watchBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	now := b.health.now()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.ended {
+		return n, err
+	}
+	if n > 0 {
+		switch {
+		case b.stalling:
+			b.stalling = false
+			b.health.stallEnded(b.stallInterval)
+		case b.bookmarks && now.Sub(b.lastRead) > watchStallThreshold:
+			// the timer has not fired yet.
+			b.health.stalled(b.path, b.lastRead)
+		}
+		b.lastRead = now
+		b.armStallTimerLocked()
+	}
+	if err == nil {
+		return n, err
+	}
+
+	b.endLocked()
+	switch {
+	case b.request.Context().Err() != nil:
+		// the client stopped watching.
+	case err == io.EOF && b.timeout > 0 && now.Sub(b.started) >= b.timeout-watchTimeoutSlack:
+		// the watch timed out as it asked to.
+	case err == io.EOF:
+		b.health.disconnected(b.path, fmt.Errorf("the server ended the watch after %v", now.Sub(b.started).Round(time.Second)))
+	default:
+		b.health.disconnected(b.path, err)
+	}
+	return n, err
+}
+
+// Close is the client stopping the watch, which is not a disconnection.
+func (b *watchBody) Close() error {
+	b.lock.Lock()
+	b.endLocked()
+	b.lock.Unlock()
+	return b.ReadCloser.Close()
+}
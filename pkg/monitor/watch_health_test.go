@@ -0,0 +1,192 @@
+package monitor
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func newWatchServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		// watches end right away, which is early unless they asked for a short timeout.
+		w.Write([]byte(`{"type":"BOOKMARK"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func readAll(t *testing.T, config *rest.Config, path string) {
+	client, err := rest.HTTPClientFor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(config.Host + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func watchHealthIntervals(recorder monitorapi.Recorder, reason monitorapi.IntervalReason) monitorapi.Intervals {
+	return recorder.Intervals(time.Time{}, time.Time{}).Filter(func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Source == monitorapi.SourceMonitorWatchStreams && eventInterval.Message.Reason == reason
+	})
+}
+
+func TestWatchHealthDisconnect(t *testing.T) {
+	server := newWatchServer(t)
+	recorder := NewRecorder()
+	health := newWatchHealth(recorder)
+	config := health.wrapConfig(&rest.Config{Host: server.URL})
+
+	readAll(t, config, "/api/v1/namespaces/default/pods?watch=true&timeoutSeconds=600")
+	disconnected := watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamDisconnected)
+	if len(disconnected) != 1 {
+		t.Fatalf("expected a disconnection, got %v", disconnected)
+	}
+	if locator := disconnected[0].Locator.OldLocator(); !strings.Contains(locator, "pods") {
+		t.Errorf("expected the disconnection to locate pods, got %q", locator)
+	}
+
+	readAll(t, config, "/api/v1/namespaces/default/pods")
+	if resynced := watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamResynced); len(resynced) != 1 {
+		t.Errorf("expected a resync after the relist, got %v", resynced)
+	}
+	readAll(t, config, "/api/v1/namespaces/default/pods")
+	if resynced := watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamResynced); len(resynced) != 1 {
+		t.Errorf("expected later lists not to be resyncs, got %v", resynced)
+	}
+
+	readAll(t, config, "/api/v1/namespaces/default/pods?watch=true&timeoutSeconds=1")
+	disconnected = watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamDisconnected)
+	if len(disconnected) != 1 || disconnected[0].To.IsZero() {
+		t.Errorf("expected the disconnection to end when the watch reconnected, got %v", disconnected)
+	}
+
+	junits := health.junits()
+	if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil {
+		t.Fatalf("expected a flake, got %#v", junits)
+	}
+	if !strings.Contains(junits[0].FailureOutput.Output, "1 disconnects, 1 resyncs, 0 stalls") {
+		t.Errorf("expected the counts in the failure, got %q", junits[0].FailureOutput.Output)
+	}
+}
+
+func TestWatchHealthTimeout(t *testing.T) {
+	server := newWatchServer(t)
+	recorder := NewRecorder()
+	health := newWatchHealth(recorder)
+	config := health.wrapConfig(&rest.Config{Host: server.URL})
+
+	readAll(t, config, "/apis/apps/v1/deployments?watch=true&timeoutSeconds=1")
+	readAll(t, config, "/apis/apps/v1/deployments")
+	if intervals := recorder.Intervals(time.Time{}, time.Time{}); len(intervals) != 0 {
+		t.Errorf("expected no intervals for a watch that timed out as asked, got %v", intervals)
+	}
+	if junits := health.junits(); len(junits) != 1 || junits[0].FailureOutput != nil {
+		t.Errorf("expected a single passing junit, got %#v", junits)
+	}
+}
+
+func TestWatchHealthStall(t *testing.T) {
+	recorder := NewRecorder()
+	health := newWatchHealth(recorder)
+	now := time.Now()
+	health.now = func() time.Time { return now }
+	body := &watchBody{
+		ReadCloser: io.NopCloser(strings.NewReader(`{"type":"BOOKMARK"}`)),
+		health:     health,
+		path:       "/apis/apps/v1/namespaces/default/deployments",
+		request:    httptest.NewRequest(http.MethodGet, "/", nil),
+		started:    now,
+		lastRead:   now,
+		bookmarks:  true,
+	}
+	now = now.Add(5 * time.Minute)
+	if _, err := body.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	stalled := watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamStalled)
+	if len(stalled) != 1 || stalled[0].To.Sub(stalled[0].From) != 5*time.Minute {
+		t.Fatalf("expected a five minute stall, got %v", stalled)
+	}
+	if _, err := body.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if stalled := watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamStalled); len(stalled) != 1 {
+		t.Errorf("expected reads that keep up not to stall, got %v", stalled)
+	}
+}
+
+func TestWatchHealthHangingStream(t *testing.T) {
+	recorder := NewRecorder()
+	health := newWatchHealth(recorder)
+	now := time.Now()
+	health.now = func() time.Time { return now }
+	body := &watchBody{
+		ReadCloser: io.NopCloser(strings.NewReader(`{"type":"BOOKMARK"}`)),
+		health:     health,
+		path:       "/apis/apps/v1/namespaces/default/deployments",
+		request:    httptest.NewRequest(http.MethodGet, "/", nil),
+		started:    now,
+		lastRead:   now,
+		bookmarks:  true,
+	}
+	start := now
+
+	// the stream received nothing yet, so nothing is recorded before the threshold.
+	now = now.Add(time.Minute)
+	body.checkStall()
+	if stalled := watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamStalled); len(stalled) != 0 {
+		t.Fatalf("expected no stall within the threshold, got %v", stalled)
+	}
+	body.lock.Lock()
+	body.stallTimer.Stop()
+	body.lock.Unlock()
+
+	// nothing is ever read again.
+	now = now.Add(5 * time.Minute)
+	body.checkStall()
+	stalled := watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamStalled)
+	if len(stalled) != 1 || !stalled[0].From.Equal(start) || !stalled[0].To.IsZero() {
+		t.Fatalf("expected a stall that is still going on, got %v", stalled)
+	}
+	if junits := health.junits(); len(junits) != 2 || !strings.Contains(junits[0].FailureOutput.Output, "1 stalls") {
+		t.Errorf("expected the stall to flake, got %#v", junits)
+	}
+
+	// the client gives up on the stream.
+	now = now.Add(time.Minute)
+	if err := body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	stalled = watchHealthIntervals(recorder, monitorapi.MonitorWatchStreamStalled)
+	if len(stalled) != 1 || stalled[0].To.Sub(stalled[0].From) != 7*time.Minute {
+		t.Errorf("expected closing the stream to end the stall, got %v", stalled)
+	}
+}
+
+func TestWatchLocator(t *testing.T) {
+	tests := map[string]string{
+		"/api/v1/pods":                                 "pods",
+		"/api/v1/namespaces/default/pods":              "pods",
+		"/apis/apps/v1/deployments":                    "deployments.apps",
+		"/apis/apps/v1/namespaces/default/deployments": "deployments.apps",
+	}
+	for path, resource := range tests {
+		if locator := watchLocator(path); locator.Keys[monitorapi.LocatorResourceKey] != resource {
+			t.Errorf("%s: expected resource %q, got %v", path, resource, locator.Keys)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package monitortestframework
+
+import (
+	"sort"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalAnnotator may be implemented by a MonitorTest to annotate the intervals of every monitor test once all
+// intervals are constructed, for facts that change how other intervals are read, like the clock of the node an
+// interval was timestamped on being off.
+type IntervalAnnotator interface {
+	// AnnotateInterval returns the annotations to add to the interval, or nil.  Annotations the interval already has
+	// are kept.
+	AnnotateInterval(interval monitorapi.Interval) map[monitorapi.AnnotationKey]string
+}
+
+// AnnotateIntervals returns the intervals with the annotations of every IntervalAnnotator added.  The intervals are
+// copied before they are changed, the recorded intervals stay as they are.  In offline mode, only annotators
+// implementing OfflineMonitorTest annotate.
+func (r *monitorTestRegistry) AnnotateIntervals(intervals monitorapi.Intervals) monitorapi.Intervals {
+	// annotators run in name order, so the first to set an annotation wins on every run.
+	names := []string{}
+	for name, monitorTest := range r.monitorTests {
+		if _, ok := monitorTest.monitorTest.(IntervalAnnotator); ok && !r.notSupportedOffline(monitorTest) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return intervals
+	}
+	sort.Strings(names)
+
+	ret := make(monitorapi.Intervals, 0, len(intervals))
+	for _, interval := range intervals {
+		var annotations map[monitorapi.AnnotationKey]string
+		for _, name := range names {
+			for key, value := range r.monitorTests[name].monitorTest.(IntervalAnnotator).AnnotateInterval(interval) {
+				if _, ok := interval.Message.Annotations[key]; ok {
+					continue
+				}
+				if _, ok := annotations[key]; ok {
+					continue
+				}
+				if annotations == nil {
+					annotations = map[monitorapi.AnnotationKey]string{}
+				}
+				annotations[key] = value
+			}
+		}
+		if len(annotations) > 0 {
+			for key, value := range interval.Message.Annotations {
+				annotations[key] = value
+			}
+			interval.Message.Annotations = annotations
+		}
+		ret = append(ret, interval)
+	}
+	return ret
+}
@@ -0,0 +1,55 @@
+package monitortestframework
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const testAnnotation monitorapi.AnnotationKey = "test-annotation"
+
+// nodeAnnotator annotates the intervals located on a node.
+type nodeAnnotator struct {
+	fileWriter
+	node  string
+	key   monitorapi.AnnotationKey
+	value string
+}
+
+func (a *nodeAnnotator) AnnotateInterval(interval monitorapi.Interval) map[monitorapi.AnnotationKey]string {
+	if interval.Locator.Keys[monitorapi.LocatorNodeKey] != a.node {
+		return nil
+	}
+	return map[monitorapi.AnnotationKey]string{a.key: a.value}
+}
+
+func TestAnnotateIntervals(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("a-annotator", "Test Framework", &nodeAnnotator{node: "worker-0", key: testAnnotation, value: "first"})
+	registry.AddMonitorTestOrDie("b-annotator", "Test Framework", &nodeAnnotator{node: "worker-0", key: testAnnotation, value: "second"})
+	registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{})
+
+	now := time.Now()
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("worker-0")).
+			Message(monitorapi.NewMessage().HumanMessage("annotated")).
+			Build(now, now),
+		monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("worker-1")).
+			Message(monitorapi.NewMessage().HumanMessage("not annotated")).
+			Build(now, now),
+	}
+
+	annotated := registry.AnnotateIntervals(intervals)
+	if value := annotated[0].Message.Annotations[testAnnotation]; value != "first" {
+		t.Errorf("expected the first annotator by name to win, got %q", value)
+	}
+	if _, ok := annotated[1].Message.Annotations[testAnnotation]; ok {
+		t.Errorf("expected no annotation off the node, got %v", annotated[1].Message.Annotations)
+	}
+	if _, ok := intervals[0].Message.Annotations[testAnnotation]; ok {
+		t.Errorf("expected the original intervals to be unchanged, got %v", intervals[0].Message.Annotations)
+	}
+}
@@ -0,0 +1,193 @@
+package monitortestframework
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// DefaultArtifactQuota is how much a single ArtifactDir may hold.  CI uploads every artifact, a runaway writer must
+// not fill the disk of the job or the bucket.
+const DefaultArtifactQuota = 100 * 1024 * 1024
+
+// ErrArtifactQuotaExceeded is returned by writes that would take an ArtifactDir over its quota.
+var ErrArtifactQuotaExceeded = errors.New("artifact quota exceeded")
+
+// ArtifactDir is a directory of its own under an artifact root, like the storage directory or the ARTIFACT_DIR of a
+// CI job, for a single test or monitor test.  Files written through it are limited to its quota and are added to the
+// artifact index of the root when it is closed, attributed to its writer.
+type ArtifactDir struct {
+	root          string
+	dir           string
+	writer        string
+	jiraComponent string
+	quota         int64
+
+	lock sync.Mutex
+	// sizes of the files written through the directory, by path relative to the directory.
+	sizes map[string]int64
+	used  int64
+}
+
+var unsafeArtifactDirCharacters = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// NewArtifactDir creates a directory named after writer under root.  When a directory of that name already exists,
+// for instance because the test ran before, a numeric suffix keeps them apart.  A quota of zero or less means
+// DefaultArtifactQuota.
+func NewArtifactDir(root, writer, jiraComponent string, quota int64) (*ArtifactDir, error) {
+	if quota <= 0 {
+		quota = DefaultArtifactQuota
+	}
+	name := unsafeArtifactDirCharacters.ReplaceAllString(writer, "_")
+	if len(name) > 120 {
+		name = name[:120]
+	}
+	if err := validatePathElement(name); err != nil {
+		return nil, fmt.Errorf("invalid artifact directory for %q: %w", writer, err)
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(root, name)
+	for i := 2; ; i++ {
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		dir = filepath.Join(root, name+"-"+strconv.Itoa(i))
+	}
+
+	return &ArtifactDir{
+		root:          root,
+		dir:           dir,
+		writer:        writer,
+		jiraComponent: jiraComponent,
+		quota:         quota,
+		sizes:         map[string]int64{},
+	}, nil
+}
+
+// Dir returns the directory.  Files written directly to it are indexed when it is closed, but are not limited by
+// the quota.
+func (d *ArtifactDir) Dir() string {
+	return d.dir
+}
+
+// Path returns a writable path under the directory, creating any missing directories.
+func (d *ArtifactDir) Path(elem ...string) (string, error) {
+	return StoragePath(d.dir, elem...)
+}
+
+// WriteFile writes the file under the directory.  Nothing is written when the content would exceed the quota.
+func (d *ArtifactDir) WriteFile(name string, content []byte) error {
+	path, err := d.Path(name)
+	if err != nil {
+		return err
+	}
+	if err := d.reserve(name, int64(len(content)), true); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// Create creates the file under the directory.  Writes that would exceed the quota write what fits and return
+// ErrArtifactQuotaExceeded, so a truncated log is kept rather than nothing.
+func (d *ArtifactDir) Create(name string) (io.WriteCloser, error) {
+	path, err := d.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.reserve(name, 0, true); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &quotaWriter{dir: d, name: name, file: f}, nil
+}
+
+// reserve accounts for size more bytes of the file, or for the file being size bytes long when replace is set.
+func (d *ArtifactDir) reserve(name string, size int64, replace bool) error {
+	_, err := d.reservePartial(name, size, replace, false)
+	return err
+}
+
+// reservePartial is reserve, but when partial is set it reserves and returns as many bytes as fit.
+func (d *ArtifactDir) reservePartial(name string, size int64, replace, partial bool) (int64, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	name = filepath.Clean(name)
+	used := d.used
+	if replace {
+		used -= d.sizes[name]
+	}
+	if used+size <= d.quota {
+		d.used = used + size
+		if replace {
+			d.sizes[name] = size
+		} else {
+			d.sizes[name] += size
+		}
+		return size, nil
+	}
+	if !partial {
+		return 0, fmt.Errorf("%s of %d bytes would exceed the quota of %d bytes for %s: %w", name, size, d.quota, d.writer, ErrArtifactQuotaExceeded)
+	}
+	fits := d.quota - d.used
+	if fits < 0 {
+		fits = 0
+	}
+	d.used += fits
+	d.sizes[name] += fits
+	return fits, fmt.Errorf("%s exceeds the quota of %d bytes for %s: %w", name, d.quota, d.writer, ErrArtifactQuotaExceeded)
+}
+
+type quotaWriter struct {
+	dir  *ArtifactDir
+	name string
+	file *os.File
+}
+
+func (w *quotaWriter) Write(p []byte) (int, error) {
+	fits, quotaErr := w.dir.reservePartial(w.name, int64(len(p)), false, true)
+	n, err := w.file.Write(p[:fits])
+	if err != nil {
+		return n, err
+	}
+	return n, quotaErr
+}
+
+func (w *quotaWriter) Close() error {
+	return w.file.Close()
+}
+
+// Close adds every file in the directory to the artifact index of the root.  Closing again updates the index with
+// files written since.
+func (d *ArtifactDir) Close() error {
+	prefix, err := filepath.Rel(d.root, d.dir)
+	if err != nil {
+		return err
+	}
+	entries := []ArtifactIndexEntry{}
+	for _, entry := range artifactsWrittenBetween(d.dir, nil, snapshotStorage(d.dir), d.writer, d.jiraComponent, nil) {
+		entry.Name = path.Join(filepath.ToSlash(prefix), entry.Name)
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return registerArtifacts(d.root, entries)
+}
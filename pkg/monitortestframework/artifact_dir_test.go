@@ -0,0 +1,87 @@
+package monitortestframework
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArtifactDir(t *testing.T) {
+	root := t.TempDir()
+	first, err := NewArtifactDir(root, "[sig-network] routes should work", "Networking", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewArtifactDir(root, "[sig-network] routes should work", "Networking", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(first.Dir()) != "_sig-network_routes_should_work" || filepath.Base(second.Dir()) != "_sig-network_routes_should_work-2" {
+		t.Errorf("expected directories of their own, got %s and %s", first.Dir(), second.Dir())
+	}
+
+	if err := first.WriteFile("curl.log", []byte("12345678")); err != nil {
+		t.Fatal(err)
+	}
+	// replacing a file only counts its new size.
+	if err := first.WriteFile("curl.log", []byte("123456")); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.WriteFile("big.log", []byte("12345")); !errors.Is(err, ErrArtifactQuotaExceeded) {
+		t.Errorf("expected the quota to be exceeded, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(first.Dir(), "big.log")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing to be written over the quota, got %v", err)
+	}
+	if err := first.WriteFile("../escape.log", []byte("1")); err == nil {
+		t.Error("expected files outside of the directory to be rejected")
+	}
+
+	w, err := first.Create("routes/router.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := w.Write([]byte("defg")); n != 1 || !errors.Is(err, ErrArtifactQuotaExceeded) {
+		t.Errorf("expected what fits to be written, got %d: %v", n, err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(first.Dir(), "routes", "router.log")); string(content) != "abcd" {
+		t.Errorf("expected a truncated file, got %q", content)
+	}
+
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(root, ArtifactIndexFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := &ArtifactIndex{}
+	if err := json.Unmarshal(content, index); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, entry := range index.Artifacts {
+		names = append(names, entry.Name)
+		if entry.Writer != "[sig-network] routes should work" || entry.JiraComponent != "Networking" {
+			t.Errorf("unexpected attribution %#v", entry)
+		}
+	}
+	if actual := strings.Join(names, ","); actual != "_sig-network_routes_should_work/curl.log,_sig-network_routes_should_work/routes/router.log" {
+		t.Errorf("unexpected index entries %s", actual)
+	}
+	if _, err := os.Stat(filepath.Join(root, ArtifactIndexFilename+".lock")); !os.IsNotExist(err) {
+		t.Errorf("expected the index to be unlocked, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package monitortestframework
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ArtifactIndexFilename is written to the root of the storage directory.  Every monitor run that writes to the same
+// storage directory adds to the same index.
+const ArtifactIndexFilename = "index.json"
+
+// ArtifactSchemaDescriber may be implemented by a MonitorTest or a RegistryOutput to describe the format of the
+// files it writes, so that artifact browsers know how to display them.
+type ArtifactSchemaDescriber interface {
+	// ArtifactSchema returns the schema of the file, relative to the storage directory, or empty if unknown.
+	ArtifactSchema(relativePath string) string
+}
+
+// ArtifactIndex enumerates the files written by monitor tests and registry outputs.
+type ArtifactIndex struct {
+	Artifacts []ArtifactIndexEntry `json:"artifacts"`
+}
+
+type ArtifactIndexEntry struct {
+	// Name is the path of the file relative to the storage directory.
+	Name string `json:"name"`
+	// Writer is the name of the monitor test or registry output that wrote the file.
+	Writer string `json:"writer"`
+	// JiraComponent is the component responsible for the writer.
+	JiraComponent string `json:"jiraComponent,omitempty"`
+	Size          int64  `json:"size"`
+	ContentType   string `json:"contentType"`
+	Schema        string `json:"schema,omitempty"`
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// snapshotStorage records every file under the storage directory so that the files a writer creates or modifies
+// can be attributed to it.
+func snapshotStorage(storageDir string) map[string]fileState {
+	ret := map[string]fileState{}
+	// errors only mean we cannot attribute some files, which must not fail writing content.
+	_ = filepath.WalkDir(storageDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		relativePath, err := filepath.Rel(storageDir, path)
+		if err != nil {
+			return nil
+		}
+		ret[filepath.ToSlash(relativePath)] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return ret
+}
+
+// artifactsWrittenBetween returns entries for every file that is new or changed in after.
+func artifactsWrittenBetween(storageDir string, before, after map[string]fileState, writer, jiraComponent string, describer interface{}) []ArtifactIndexEntry {
+	schemaDescriber, _ := describer.(ArtifactSchemaDescriber)
+	ret := []ArtifactIndexEntry{}
+	for name, state := range after {
+		if name == ArtifactIndexFilename || name == ArtifactIndexFilename+".lock" {
+			continue
+		}
+		if previous, ok := before[name]; ok && previous == state {
+			continue
+		}
+		entry := ArtifactIndexEntry{
+			Name:          name,
+			Writer:        writer,
+			JiraComponent: jiraComponent,
+			Size:          state.size,
+			ContentType:   contentTypeOf(filepath.Join(storageDir, filepath.FromSlash(name))),
+		}
+		if schemaDescriber != nil {
+			entry.Schema = schemaDescriber.ArtifactSchema(name)
+		}
+		ret = append(ret, entry)
+	}
+	return ret
+}
+
+func contentTypeOf(path string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); len(contentType) > 0 {
+		return contentType
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "application/octet-stream"
+	}
+	return http.DetectContentType(head[:n])
+}
+
+// registerArtifacts adds the entries to the index in the storage directory, while holding the lock of the index.
+func registerArtifacts(storageDir string, entries []ArtifactIndexEntry) error {
+	unlock, err := lockArtifactIndex(storageDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeArtifactIndex(storageDir, entries)
+}
+
+// lockArtifactIndex keeps the processes of a suite, which close their directories in parallel, from losing each
+// other's entries.
+func lockArtifactIndex(root string) (func(), error) {
+	lockPath := filepath.Join(root, ArtifactIndexFilename+".lock")
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s, remove it if no tests are running", lockPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// writeArtifactIndex adds the entries to the index in the storage directory.  Entries for files that were written
+// again replace the old entries.
+func writeArtifactIndex(storageDir string, entries []ArtifactIndexEntry) error {
+	indexPath := filepath.Join(storageDir, ArtifactIndexFilename)
+	index := &ArtifactIndex{}
+	existing, err := os.ReadFile(indexPath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(existing, index); err != nil {
+			return err
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	byName := map[string]ArtifactIndexEntry{}
+	for _, entry := range index.Artifacts {
+		byName[entry.Name] = entry
+	}
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+	index.Artifacts = make([]ArtifactIndexEntry, 0, len(byName))
+	for _, entry := range byName {
+		index.Artifacts = append(index.Artifacts, entry)
+	}
+	sort.Slice(index.Artifacts, func(i, j int) bool {
+		return index.Artifacts[i].Name < index.Artifacts[j].Name
+	})
+
+	content, err := json.MarshalIndent(index, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(indexPath, content, 0644)
+}
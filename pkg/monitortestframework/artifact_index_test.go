@@ -0,0 +1,114 @@
+package monitortestframework
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// fileWriter is a monitor test that only writes files.
+type fileWriter struct {
+	files  map[string]string
+	schema string
+}
+
+func (w *fileWriter) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *fileWriter) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *fileWriter) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *fileWriter) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (w *fileWriter) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	for name, content := range w.files {
+		path := filepath.Join(storageDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *fileWriter) Cleanup(ctx context.Context) error {
+	return nil
+}
+
+type schemaFileWriter struct {
+	fileWriter
+}
+
+func (w *schemaFileWriter) ArtifactSchema(relativePath string) string {
+	return w.schema
+}
+
+func readArtifactIndex(t *testing.T, storageDir string) []ArtifactIndexEntry {
+	content, err := os.ReadFile(filepath.Join(storageDir, ArtifactIndexFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := &ArtifactIndex{}
+	if err := json.Unmarshal(content, index); err != nil {
+		t.Fatal(err)
+	}
+	return index.Artifacts
+}
+
+func TestWriteContentToStorageArtifactIndex(t *testing.T) {
+	storageDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(storageDir, "already-there.log"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("json-writer", "Networking", &schemaFileWriter{
+		fileWriter: fileWriter{files: map[string]string{"summary_1.json": `{"a": 1}`}, schema: "summary/v1"},
+	})
+	registry.AddMonitorTestOrDie("log-writer", "Node", &fileWriter{
+		files: map[string]string{"nodes/node-a": "plain text log"},
+	})
+	if _, err := registry.WriteContentToStorage(context.Background(), storageDir, "_1", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ArtifactIndexEntry{
+		{Name: "nodes/node-a", Writer: "log-writer", JiraComponent: "Node", Size: 14, ContentType: "text/plain; charset=utf-8"},
+		{Name: "summary_1.json", Writer: "json-writer", JiraComponent: "Networking", Size: 8, ContentType: "application/json", Schema: "summary/v1"},
+	}
+	if actual := readArtifactIndex(t, storageDir); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %#v, got %#v", expected, actual)
+	}
+
+	// a second run into the same storage directory adds to the index.
+	second := NewMonitorTestRegistry()
+	second.AddMonitorTestOrDie("json-writer", "Networking", &fileWriter{
+		files: map[string]string{"summary_2.json": `{}`},
+	})
+	if _, err := second.WriteContentToStorage(context.Background(), storageDir, "_2", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	actual := readArtifactIndex(t, storageDir)
+	if len(actual) != 3 || actual[2].Name != "summary_2.json" {
+		t.Errorf("expected the second run to be added to the index, got %#v", actual)
+	}
+}
@@ -0,0 +1,100 @@
+package monitortestframework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// BackfillMonitorTest may be implemented by a MonitorTest that can reconstruct the intervals it would have recorded
+// before it was started from durable sources, like events, the status history of operators, node journals, or
+// Prometheus.  In backfill mode, the registry asks for the intervals of the part of the run before collection began,
+// so a monitor attached late, for instance only for the phase after an upgrade, still evaluates the whole run.
+type BackfillMonitorTest interface {
+	// BackfillData is called during CollectData, with the client config StartCollection was handed.  It returns the
+	// intervals from beginning until end, which is when collection began.  The registry marks them with
+	// AnnotationBackfilled.  Sources that do not reach back to beginning should return what they have.
+	BackfillData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
+}
+
+// BackfilledBeginning returns the beginning of the run covered by a collection that began at beginning, which is
+// earlier in backfill mode or when CollectData recovered intervals of an earlier monitor process.
+func (r *monitorTestRegistry) BackfilledBeginning(beginning time.Time) time.Time {
+	if r.backfillDuration > 0 {
+		beginning = beginning.Add(-r.backfillDuration)
+	}
+	if !r.recoveredBeginning.IsZero() && r.recoveredBeginning.Before(beginning) {
+		beginning = r.recoveredBeginning
+	}
+	return beginning
+}
+
+// backfill asks the monitor test for the intervals before beginning, if it is able to and backfill mode is on.
+func (r *monitorTestRegistry) backfill(ctx context.Context, monitorTest *monitorTesttItem, storageDir string, beginning time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase) {
+	backfiller, ok := monitorTest.monitorTest.(BackfillMonitorTest)
+	if !ok || r.backfillDuration <= 0 {
+		return nil, nil
+	}
+
+	testName := monitorTest.stageTestName(BackfillDataStage)
+	log := logrus.WithField("monitorTest", monitorTest.name)
+	backfillBeginning := beginning.Add(-r.backfillDuration)
+	log.Infof("  Backfilling from %v to %v", backfillBeginning, beginning)
+
+	start := r.clock.Now()
+	spanCtx, span := startMonitorTestSpan(ctx, "backfill", monitorTest)
+	intervals, junits, err := backfillDataWithPanicProtection(spanCtx, backfiller, storageDir, backfillBeginning, beginning)
+	endMonitorTestSpan(span, err)
+	duration := r.clock.Since(start)
+
+	for i := range intervals {
+		annotations := map[monitorapi.AnnotationKey]string{}
+		for key, value := range intervals[i].Message.Annotations {
+			annotations[key] = value
+		}
+		annotations[monitorapi.AnnotationBackfilled] = "true"
+		intervals[i].Message.Annotations = annotations
+	}
+
+	junits = monitorTest.withJunitDetails(junits)
+	if err != nil {
+		var nsErr *NotSupportedError
+		if errors.As(err, &nsErr) {
+			return intervals, append(junits, &junitapi.JUnitTestCase{
+				Name:     testName,
+				Details:  monitorTest.newJunitDetails(),
+				Duration: duration.Seconds(),
+				SkipMessage: &junitapi.SkipMessage{
+					Message: nsErr.Reason,
+				},
+			})
+		}
+
+		log.WithError(err).Error("failed during backfill")
+		junits = append(junits, &junitapi.JUnitTestCase{
+			Name:     testName,
+			Details:  monitorTest.newJunitDetails(),
+			Duration: duration.Seconds(),
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("failed during backfill\n%v", err),
+			},
+			SystemOut: fmt.Sprintf("failed during backfill\n%v", err),
+		})
+		var flakeErr *FlakeError
+		if !errors.As(err, &flakeErr) {
+			return intervals, junits
+		}
+	}
+
+	return intervals, append(junits, &junitapi.JUnitTestCase{
+		Name:     testName,
+		Details:  monitorTest.newJunitDetails(),
+		Duration: duration.Seconds(),
+	})
+}
@@ -0,0 +1,81 @@
+package monitortestframework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type backfiller struct {
+	fileWriter
+	beginning, end time.Time
+}
+
+func (w *backfiller) BackfillData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	w.beginning, w.end = beginning, end
+	return monitorapi.Intervals{nodeInterval()}, nil, nil
+}
+
+func TestRegistryBackfill(t *testing.T) {
+	ctx := context.Background()
+	beginning := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	filler := &backfiller{}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("filler", "Test Framework", filler)
+	registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{})
+
+	// backfill mode is off by default.
+	if actual := registry.BackfilledBeginning(beginning); !actual.Equal(beginning) {
+		t.Errorf("expected the run to begin with collection, got %v", actual)
+	}
+	intervals, _, err := registry.CollectData(ctx, t.TempDir(), beginning, beginning.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 0 || !filler.beginning.IsZero() {
+		t.Errorf("expected nothing to be backfilled, got %v", intervals)
+	}
+
+	registry.SetBackfillDuration(2 * time.Hour)
+	if actual := registry.BackfilledBeginning(beginning); !actual.Equal(beginning.Add(-2 * time.Hour)) {
+		t.Errorf("expected the run to begin two hours earlier, got %v", actual)
+	}
+	intervals, junits, err := registry.CollectData(ctx, t.TempDir(), beginning, beginning.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filler.beginning.Equal(beginning.Add(-2*time.Hour)) || !filler.end.Equal(beginning) {
+		t.Errorf("expected the two hours before collection to be backfilled, got %v to %v", filler.beginning, filler.end)
+	}
+	if len(intervals) != 1 || intervals[0].Message.Annotations[monitorapi.AnnotationBackfilled] != "true" {
+		t.Errorf("expected the backfilled interval to be marked, got %v", intervals)
+	}
+	backfillJunits := 0
+	for _, junit := range junits {
+		if junit.Name == `[Jira:"Test Framework"] monitor test filler backfill` {
+			backfillJunits++
+			if junit.FailureOutput != nil || junit.SkipMessage != nil {
+				t.Errorf("expected the backfill to pass, got %#v", junit)
+			}
+		}
+		if junit.Name == `[Jira:"Test Framework"] monitor test writer backfill` {
+			t.Errorf("expected no backfill for monitor tests that cannot, got %#v", junit)
+		}
+	}
+	if backfillJunits != 1 {
+		t.Errorf("expected a junit for the backfill, got %d", backfillJunits)
+	}
+
+	// the duration is kept for the registry of selected monitor tests.
+	selected, err := registry.GetRegistryFor("filler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := selected.BackfilledBeginning(beginning); !actual.Equal(beginning.Add(-2 * time.Hour)) {
+		t.Errorf("expected the backfill duration to be kept, got %v", actual)
+	}
+}
@@ -0,0 +1,92 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// FailureBudgetPolicy decides how the failing junits of a single monitor test affect the job, based on the severity
+// in their details.  Failures without a severity always fail.
+type FailureBudgetPolicy struct {
+	// WarningsToFail is the number of warn severity failures from a single monitor test that escalate all of its
+	// warnings to failures.  Zero means warnings never fail the job.
+	WarningsToFail int
+	// FailuresAsWarnings treats every failure without info severity as a warning, so nothing fails the job unless
+	// WarningsToFail is reached.  It is meant for iterating on invariants against a cluster that is not a CI cluster.
+	FailuresAsWarnings bool
+}
+
+// DefaultFailureBudgetPolicy never fails the job for info or warn severity failures.
+var DefaultFailureBudgetPolicy = FailureBudgetPolicy{}
+
+// Apply rewrites the junits of a single monitor test.
+//  1. info failures become passes, the failure is kept in the system out.
+//  2. warn failures become flakes by adding a passing junit with the same name, unless the budget is exhausted.
+//  3. everything else is unchanged.
+func (p FailureBudgetPolicy) Apply(junits []*junitapi.JUnitTestCase) []*junitapi.JUnitTestCase {
+	numWarnings := 0
+	for _, junit := range junits {
+		if p.severityOf(junit) == junitapi.SeverityWarn {
+			numWarnings++
+		}
+	}
+	escalate := p.WarningsToFail > 0 && numWarnings >= p.WarningsToFail
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, junit := range junits {
+		switch p.severityOf(junit) {
+		case junitapi.SeverityInfo:
+			passed := *junit
+			passed.FailureOutput = nil
+			passed.SystemOut = strings.TrimSpace(strings.Join([]string{junit.SystemOut, failureText(junit)}, "\n"))
+			ret = append(ret, &passed)
+
+		case junitapi.SeverityWarn:
+			if escalate {
+				failed := *junit
+				failureOutput := *junit.FailureOutput
+				failureOutput.Output = fmt.Sprintf("%d warnings reached the failure budget of %d\n%s", numWarnings, p.WarningsToFail, failureOutput.Output)
+				failed.FailureOutput = &failureOutput
+				ret = append(ret, &failed)
+				continue
+			}
+			ret = append(ret, junit, &junitapi.JUnitTestCase{
+				Name:    junit.Name,
+				Details: junit.Details,
+			})
+
+		default:
+			ret = append(ret, junit)
+		}
+	}
+	return ret
+}
+
+// severityOf returns the severity of a failing junit under the policy, or empty if the junit did not fail.
+func (p FailureBudgetPolicy) severityOf(junit *junitapi.JUnitTestCase) junitapi.Severity {
+	severity := severityOf(junit)
+	if p.FailuresAsWarnings && severity == junitapi.SeverityFail {
+		return junitapi.SeverityWarn
+	}
+	return severity
+}
+
+// severityOf returns the severity of a failing junit, or empty if the junit did not fail.
+func severityOf(junit *junitapi.JUnitTestCase) junitapi.Severity {
+	if junit == nil || junit.FailureOutput == nil {
+		return ""
+	}
+	if junit.Details == nil || len(junit.Details.Severity) == 0 {
+		return junitapi.SeverityFail
+	}
+	return junit.Details.Severity
+}
+
+func failureText(junit *junitapi.JUnitTestCase) string {
+	if len(junit.FailureOutput.Output) > 0 {
+		return junit.FailureOutput.Output
+	}
+	return junit.FailureOutput.Message
+}
@@ -0,0 +1,92 @@
+package monitortestframework
+
+import (
+	"testing"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func failingJunit(name string, severity junitapi.Severity) *junitapi.JUnitTestCase {
+	return &junitapi.JUnitTestCase{
+		Name:          name,
+		FailureOutput: &junitapi.FailureOutput{Output: "bad"},
+		Details:       &junitapi.JUnitTestCaseDetails{Severity: severity},
+	}
+}
+
+func countOutcomes(junits []*junitapi.JUnitTestCase) (passed, failed map[string]int) {
+	passed, failed = map[string]int{}, map[string]int{}
+	for _, junit := range junits {
+		if junit.FailureOutput != nil {
+			failed[junit.Name]++
+			continue
+		}
+		passed[junit.Name]++
+	}
+	return passed, failed
+}
+
+func TestFailureBudgetPolicy_Apply(t *testing.T) {
+	junits := []*junitapi.JUnitTestCase{
+		failingJunit("info", junitapi.SeverityInfo),
+		failingJunit("warn-1", junitapi.SeverityWarn),
+		failingJunit("warn-2", junitapi.SeverityWarn),
+		failingJunit("fail", junitapi.SeverityFail),
+		failingJunit("unset", ""),
+		{Name: "pass"},
+	}
+
+	tests := []struct {
+		name         string
+		policy       FailureBudgetPolicy
+		expectFailed map[string]int
+		expectPassed map[string]int
+	}{
+		{
+			name:         "warnings never fail",
+			policy:       DefaultFailureBudgetPolicy,
+			expectFailed: map[string]int{"warn-1": 1, "warn-2": 1, "fail": 1, "unset": 1},
+			expectPassed: map[string]int{"info": 1, "warn-1": 1, "warn-2": 1, "pass": 1},
+		},
+		{
+			name:         "budget not exhausted",
+			policy:       FailureBudgetPolicy{WarningsToFail: 3},
+			expectFailed: map[string]int{"warn-1": 1, "warn-2": 1, "fail": 1, "unset": 1},
+			expectPassed: map[string]int{"info": 1, "warn-1": 1, "warn-2": 1, "pass": 1},
+		},
+		{
+			name:         "budget exhausted",
+			policy:       FailureBudgetPolicy{WarningsToFail: 2},
+			expectFailed: map[string]int{"warn-1": 1, "warn-2": 1, "fail": 1, "unset": 1},
+			expectPassed: map[string]int{"info": 1, "pass": 1},
+		},
+		{
+			name:         "failures as warnings",
+			policy:       FailureBudgetPolicy{FailuresAsWarnings: true},
+			expectFailed: map[string]int{"warn-1": 1, "warn-2": 1, "fail": 1, "unset": 1},
+			expectPassed: map[string]int{"info": 1, "warn-1": 1, "warn-2": 1, "fail": 1, "unset": 1, "pass": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, failed := countOutcomes(tt.policy.Apply(junits))
+			for name, count := range tt.expectFailed {
+				if failed[name] != count {
+					t.Errorf("%q: expected %d failures, got %d", name, count, failed[name])
+				}
+			}
+			for name, count := range tt.expectPassed {
+				if passed[name] != count {
+					t.Errorf("%q: expected %d passes, got %d", name, count, passed[name])
+				}
+			}
+			if len(failed) != len(tt.expectFailed) || len(passed) != len(tt.expectPassed) {
+				t.Errorf("unexpected outcomes: passed=%v failed=%v", passed, failed)
+			}
+		})
+	}
+
+	if junits[0].FailureOutput == nil {
+		t.Errorf("input junits must not be modified")
+	}
+}
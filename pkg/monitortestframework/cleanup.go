@@ -0,0 +1,119 @@
+package monitortestframework
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// DefaultCleanupTimeout is how long a cleanup pushed without a timeout may run.
+const DefaultCleanupTimeout = 5 * time.Minute
+
+// CleanupStack holds the cleanups of a monitor test, or of a test, and runs them last in first out, the way defers
+// do.  Every cleanup runs, even when earlier ones fail, panic, or the context they were run with is done, so a single
+// failure does not leave the rest of the cluster dirty.
+type CleanupStack struct {
+	lock    sync.Mutex
+	entries []cleanupEntry
+}
+
+type cleanupEntry struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) error
+}
+
+// CleanupStackMonitorTest may be implemented by a MonitorTest to get a CleanupStack when it is added to a registry.
+// The registry runs the stack after the Cleanup of the monitor test, and reports its failures as failures of the
+// cleanup.
+type CleanupStackMonitorTest interface {
+	SetCleanupStack(cleanups *CleanupStack)
+}
+
+// NewCleanupStack returns an empty stack.
+func NewCleanupStack() *CleanupStack {
+	return &CleanupStack{}
+}
+
+// Push adds a cleanup that may run for DefaultCleanupTimeout.
+func (s *CleanupStack) Push(name string, fn func(ctx context.Context) error) {
+	s.PushWithTimeout(name, DefaultCleanupTimeout, fn)
+}
+
+// PushWithTimeout adds a cleanup that may run for timeout.  The context passed to fn is done when the timeout
+// expires.
+func (s *CleanupStack) PushWithTimeout(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.entries = append(s.entries, cleanupEntry{name: name, timeout: timeout, fn: fn})
+}
+
+// Len returns the number of cleanups that have not run yet.
+func (s *CleanupStack) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.entries)
+}
+
+// Run runs every cleanup, last pushed first, until the stack is empty.  Cleanups pushed while the stack runs are run
+// next.  Each cleanup gets its own timeout; the deadline and cancellation of ctx are ignored, so cleanups run after a
+// test timed out still get their time.  The errors of every failed cleanup are returned together.
+func (s *CleanupStack) Run(ctx context.Context) error {
+	ctx = context.WithoutCancel(ctx)
+	errs := []error{}
+	for {
+		entry, ok := s.pop()
+		if !ok {
+			break
+		}
+		if err := runCleanupWithPanicProtection(ctx, entry); err != nil {
+			logrus.WithError(err).WithField("cleanup", entry.name).Error("cleanup failed")
+			errs = append(errs, fmt.Errorf("cleanup %q failed: %w", entry.name, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (s *CleanupStack) pop() (cleanupEntry, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.entries) == 0 {
+		return cleanupEntry{}, false
+	}
+	entry := s.entries[len(s.entries)-1]
+	s.entries = s.entries[:len(s.entries)-1]
+	return entry, true
+}
+
+func runCleanupWithPanicProtection(ctx context.Context, entry cleanupEntry) error {
+	timeout := entry.timeout
+	if timeout <= 0 {
+		timeout = DefaultCleanupTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// a cleanup that ignores its context must not hold up the ones after it.
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logrus.Error("recovering from panic")
+				fmt.Print(debug.Stack())
+				done <- fmt.Errorf("caught panic: %v", r)
+			}
+		}()
+		done <- entry.fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("did not finish within %v: %w", timeout, ctx.Err())
+	}
+}
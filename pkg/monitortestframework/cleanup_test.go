@@ -0,0 +1,130 @@
+package monitortestframework
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestCleanupStack(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// cleanups run after the test gave up.
+	cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	lock := sync.Mutex{}
+	order := []string{}
+	ran := func(name string) {
+		lock.Lock()
+		defer lock.Unlock()
+		order = append(order, name)
+	}
+	cleanups := NewCleanupStack()
+	cleanups.Push("first", func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			t.Errorf("expected the cleanup to get a live context, got %v", ctx.Err())
+		}
+		ran("first")
+		return nil
+	})
+	cleanups.Push("failing", func(ctx context.Context) error {
+		ran("failing")
+		return errors.New("namespace is stuck terminating")
+	})
+	cleanups.PushWithTimeout("hanging", 10*time.Millisecond, func(ctx context.Context) error {
+		ran("hanging")
+		<-ctx.Done()
+		// ignores its context, which must not hold up the next cleanup.
+		<-release
+		return nil
+	})
+	cleanups.Push("panicking", func(ctx context.Context) error {
+		ran("panicking")
+		panic("boom")
+	})
+	cleanups.Push("pushing", func(ctx context.Context) error {
+		ran("pushing")
+		cleanups.Push("pushed", func(ctx context.Context) error {
+			ran("pushed")
+			return nil
+		})
+		return nil
+	})
+
+	err := cleanups.Run(ctx)
+	lock.Lock()
+	defer lock.Unlock()
+	if expected := []string{"pushing", "pushed", "panicking", "hanging", "failing", "first"}; strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+	if err == nil {
+		t.Fatal("expected the failures to be returned")
+	}
+	for _, expected := range []string{`"failing"`, "stuck terminating", `"hanging"`, "did not finish", `"panicking"`, "caught panic: boom"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in %v", expected, err)
+		}
+	}
+	if cleanups.Len() != 0 {
+		t.Errorf("expected every cleanup to run, %d are left", cleanups.Len())
+	}
+	if err := cleanups.Run(ctx); err != nil {
+		t.Errorf("expected an empty stack to succeed, got %v", err)
+	}
+}
+
+type cleanupPusher struct {
+	fileWriter
+	cleanups *CleanupStack
+	ran      []string
+}
+
+func (w *cleanupPusher) SetCleanupStack(cleanups *CleanupStack) {
+	w.cleanups = cleanups
+}
+
+func (w *cleanupPusher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.cleanups.Push("delete namespace", func(ctx context.Context) error {
+		w.ran = append(w.ran, "delete namespace")
+		return nil
+	})
+	w.cleanups.Push("delete webhook", func(ctx context.Context) error {
+		w.ran = append(w.ran, "delete webhook")
+		return errors.New("webhook is still referenced")
+	})
+	return nil
+}
+
+func (w *cleanupPusher) Cleanup(ctx context.Context) error {
+	return &NotSupportedError{Reason: "nothing else to clean up"}
+}
+
+func TestRegistryRunsCleanupStack(t *testing.T) {
+	ctx := context.Background()
+	pusher := &cleanupPusher{}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("pusher", "Test Framework", pusher)
+
+	if _, err := registry.StartCollection(ctx, &rest.Config{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	junits, err := registry.Cleanup(ctx)
+	if err == nil || !strings.Contains(err.Error(), "webhook is still referenced") {
+		t.Errorf("expected the failed cleanup to be returned, got %v", err)
+	}
+	if strings.Join(pusher.ran, ",") != "delete webhook,delete namespace" {
+		t.Errorf("expected both cleanups to run in reverse, got %v", pusher.ran)
+	}
+	if len(junits) != 1 || junits[0].FailureOutput == nil || junits[0].SkipMessage != nil {
+		t.Fatalf("expected the cleanup to fail rather than skip, got %#v", junits)
+	}
+}
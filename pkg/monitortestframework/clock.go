@@ -0,0 +1,21 @@
+package monitortestframework
+
+import (
+	"k8s.io/utils/clock"
+)
+
+// ClockedMonitorTest may be implemented by a MonitorTest that timestamps what it records with the current time.  The
+// registry hands it the clock of the run when it is added and whenever the clock is replaced, so replays and unit
+// tests can drive the monitor test with a fake clock.
+type ClockedMonitorTest interface {
+	SetClock(clock clock.PassiveClock)
+}
+
+func (r *monitorTestRegistry) SetClock(clock clock.PassiveClock) {
+	r.clock = clock
+	for _, monitorTest := range r.monitorTests {
+		if clocked, ok := monitorTest.monitorTest.(ClockedMonitorTest); ok {
+			clocked.SetClock(clock)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package monitortestframework
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+type clockedWriter struct {
+	fileWriter
+	clock clock.PassiveClock
+}
+
+func (w *clockedWriter) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func TestRegistrySetClock(t *testing.T) {
+	before := &clockedWriter{}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("before", "Test Framework", before)
+	if _, ok := before.clock.(clock.RealClock); !ok {
+		t.Errorf("expected monitor tests to get the real clock by default, got %T", before.clock)
+	}
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	registry.SetClock(fakeClock)
+	after := &clockedWriter{}
+	registry.AddMonitorTestOrDie("after", "Test Framework", after)
+	if before.clock != fakeClock || after.clock != fakeClock {
+		t.Errorf("expected every clocked monitor test to get the registry clock")
+	}
+}
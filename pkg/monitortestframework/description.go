@@ -0,0 +1,167 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Stage is a stage of the monitor test lifecycle the registry runs a monitor test in.
+type Stage string
+
+const (
+	StartCollectionStage            Stage = "StartCollection"
+	StartSecondaryCollectionStage   Stage = "StartSecondaryCollection"
+	BackfillDataStage               Stage = "BackfillData"
+	CollectDataStage                Stage = "CollectData"
+	ConstructComputedIntervalsStage Stage = "ConstructComputedIntervals"
+	AnnotateIntervalsStage          Stage = "AnnotateIntervals"
+	EvaluateTestsStage              Stage = "EvaluateTestsFromConstructedIntervals"
+	WriteContentToStorageStage      Stage = "WriteContentToStorage"
+	CleanupStage                    Stage = "Cleanup"
+)
+
+// stages are every stage, in the order they run.
+var stages = []Stage{
+	StartCollectionStage,
+	StartSecondaryCollectionStage,
+	BackfillDataStage,
+	CollectDataStage,
+	ConstructComputedIntervalsStage,
+	AnnotateIntervalsStage,
+	EvaluateTestsStage,
+	WriteContentToStorageStage,
+	CleanupStage,
+}
+
+// monitorTestStages are the stages of MonitorTest, the others are the stages of optional interfaces.
+var monitorTestStages = []Stage{
+	StartCollectionStage,
+	CollectDataStage,
+	ConstructComputedIntervalsStage,
+	EvaluateTestsStage,
+	WriteContentToStorageStage,
+	CleanupStage,
+}
+
+// stageTestNames name the test the registry reports every monitor test's run of a stage as.  Stages without one are
+// not reported on their own.
+var stageTestNames = map[Stage]string{
+	StartCollectionStage:            "setup",
+	BackfillDataStage:               "backfill",
+	CollectDataStage:                "collection",
+	ConstructComputedIntervalsStage: "interval construction",
+	EvaluateTestsStage:              "test evaluation",
+	WriteContentToStorageStage:      "writing to storage",
+	CleanupStage:                    "cleanup",
+}
+
+// DescribedMonitorTest may be implemented by a MonitorTest to describe where it applies and what it produces, so the
+// monitor tests that will run can be listed without running them.
+type DescribedMonitorTest interface {
+	Describe() MonitorTestDescription
+}
+
+type MonitorTestDescription struct {
+	// Platforms are the only platforms the monitor test applies to, like AWS or MicroShift.  If empty, it applies to
+	// every platform but the UnsupportedPlatforms.
+	Platforms []string
+	// UnsupportedPlatforms are the platforms the monitor test reports itself as not supported on.
+	UnsupportedPlatforms []string
+	// Stages are the stages of MonitorTest the monitor test does work in.  If empty, every stage is assumed.  The
+	// stages of the optional interfaces it implements are added by the registry.
+	Stages []Stage
+	// TestNames are the tests the monitor test reports, besides the test of every stage.
+	TestNames []string
+}
+
+// MonitorTestMetadata describes a monitor test of a registry.
+type MonitorTestMetadata struct {
+	Name          string `json:"name"`
+	JiraComponent string `json:"jiraComponent"`
+	// Platforms are the only platforms the monitor test applies to.  If empty, it applies to every platform but the
+	// UnsupportedPlatforms.
+	Platforms            []string `json:"platforms,omitempty"`
+	UnsupportedPlatforms []string `json:"unsupportedPlatforms,omitempty"`
+	// Stages are the stages the monitor test runs in, in the order they run.
+	Stages []Stage `json:"stages"`
+	// StageTestNames are the tests the registry reports the stages of the monitor test as.
+	StageTestNames []string `json:"stageTestNames"`
+	// TestNames are the tests the monitor test produces itself.  They are only known for monitor tests implementing
+	// DescribedMonitorTest.
+	TestNames []string `json:"testNames"`
+	// Described is whether the monitor test implements DescribedMonitorTest.
+	Described bool `json:"described"`
+}
+
+// stageTestName is the name of the test the monitor test's run of the stage is reported as.
+func (m *monitorTesttItem) stageTestName(stage Stage) string {
+	return fmt.Sprintf("[Jira:%q] monitor test %v %s", m.jiraComponent, m.name, stageTestNames[stage])
+}
+
+// describe returns the metadata of the monitor test, from its description if it has one.
+func (m *monitorTesttItem) describe() MonitorTestMetadata {
+	description := MonitorTestDescription{}
+	describer, described := m.monitorTest.(DescribedMonitorTest)
+	if described {
+		description = describer.Describe()
+	}
+
+	implemented := map[Stage]bool{}
+	for _, stage := range description.Stages {
+		implemented[stage] = true
+	}
+	if len(description.Stages) == 0 {
+		for _, stage := range monitorTestStages {
+			implemented[stage] = true
+		}
+	}
+	if _, ok := m.monitorTest.(SecondaryClusterMonitorTest); ok {
+		implemented[StartSecondaryCollectionStage] = true
+	}
+	if _, ok := m.monitorTest.(BackfillMonitorTest); ok {
+		implemented[BackfillDataStage] = true
+	}
+	if _, ok := m.monitorTest.(IntervalAnnotator); ok {
+		implemented[AnnotateIntervalsStage] = true
+	}
+
+	ret := MonitorTestMetadata{
+		Name:                 m.name,
+		JiraComponent:        m.jiraComponent,
+		Platforms:            description.Platforms,
+		UnsupportedPlatforms: description.UnsupportedPlatforms,
+		Stages:               []Stage{},
+		StageTestNames:       []string{},
+		TestNames:            []string{},
+		Described:            described,
+	}
+	for _, stage := range stages {
+		if implemented[stage] {
+			ret.Stages = append(ret.Stages, stage)
+		}
+	}
+	// the registry reports every stage with a test, whether or not the monitor test does work in it.
+	for _, stage := range stages {
+		if _, ok := stageTestNames[stage]; !ok {
+			continue
+		}
+		if stage == BackfillDataStage && !implemented[BackfillDataStage] {
+			continue
+		}
+		ret.StageTestNames = append(ret.StageTestNames, m.stageTestName(stage))
+	}
+	ret.TestNames = append(ret.TestNames, description.TestNames...)
+	return ret
+}
+
+// DescribeMonitorTests returns the metadata of every monitor test, sorted by name.
+func (r *monitorTestRegistry) DescribeMonitorTests() []MonitorTestMetadata {
+	ret := []MonitorTestMetadata{}
+	for _, monitorTest := range r.monitorTests {
+		ret = append(ret, monitorTest.describe())
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
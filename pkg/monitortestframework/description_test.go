@@ -0,0 +1,69 @@
+package monitortestframework
+
+import (
+	"reflect"
+	"testing"
+)
+
+type describedMonitorTest struct {
+	fileWriter
+}
+
+func (w *describedMonitorTest) Describe() MonitorTestDescription {
+	return MonitorTestDescription{
+		Platforms: []string{"AWS"},
+		Stages:    []Stage{StartCollectionStage, EvaluateTestsStage, CleanupStage},
+		TestNames: []string{"[sig-cloud] throttling should not happen"},
+	}
+}
+
+func TestDescribeMonitorTests(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("described", "Cloud", &describedMonitorTest{})
+	registry.AddMonitorTestOrDie("filler", "Test Framework", &backfiller{})
+
+	expected := []MonitorTestMetadata{
+		{
+			Name:          "described",
+			JiraComponent: "Cloud",
+			Platforms:     []string{"AWS"},
+			Stages:        []Stage{StartCollectionStage, EvaluateTestsStage, CleanupStage},
+			StageTestNames: []string{
+				`[Jira:"Cloud"] monitor test described setup`,
+				`[Jira:"Cloud"] monitor test described collection`,
+				`[Jira:"Cloud"] monitor test described interval construction`,
+				`[Jira:"Cloud"] monitor test described test evaluation`,
+				`[Jira:"Cloud"] monitor test described writing to storage`,
+				`[Jira:"Cloud"] monitor test described cleanup`,
+			},
+			TestNames: []string{"[sig-cloud] throttling should not happen"},
+			Described: true,
+		},
+		{
+			Name:          "filler",
+			JiraComponent: "Test Framework",
+			Stages: []Stage{
+				StartCollectionStage,
+				BackfillDataStage,
+				CollectDataStage,
+				ConstructComputedIntervalsStage,
+				EvaluateTestsStage,
+				WriteContentToStorageStage,
+				CleanupStage,
+			},
+			StageTestNames: []string{
+				`[Jira:"Test Framework"] monitor test filler setup`,
+				`[Jira:"Test Framework"] monitor test filler backfill`,
+				`[Jira:"Test Framework"] monitor test filler collection`,
+				`[Jira:"Test Framework"] monitor test filler interval construction`,
+				`[Jira:"Test Framework"] monitor test filler test evaluation`,
+				`[Jira:"Test Framework"] monitor test filler writing to storage`,
+				`[Jira:"Test Framework"] monitor test filler cleanup`,
+			},
+			TestNames: []string{},
+		},
+	}
+	if actual := registry.DescribeMonitorTests(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expected, actual)
+	}
+}
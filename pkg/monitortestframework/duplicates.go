@@ -0,0 +1,126 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// DuplicateTestNamePolicy decides what happens when more than one monitor test emits test cases with the same name.
+// CI treats a failing and a passing test case with the same name as a flake, so silent duplicates hide failures.
+type DuplicateTestNamePolicy string
+
+const (
+	// NamespaceDuplicateTestNames keeps the name for the first monitor test that emitted it and adds the monitor test
+	// name to the test cases of every other monitor test.
+	NamespaceDuplicateTestNames DuplicateTestNamePolicy = "Namespace"
+
+	// FailOnDuplicateTestNames keeps the duplicate names and fails the stage with a test case listing the conflicts.
+	FailOnDuplicateTestNames DuplicateTestNamePolicy = "Fail"
+)
+
+const duplicateTestNamesTestName = `[Jira:"Test Framework"] monitor tests must not emit test cases with the same name`
+
+// junitNameOwners remembers which monitor test first emitted each test name, across every stage of a registry.
+type junitNameOwners struct {
+	lock   sync.Mutex
+	owners map[string]string
+}
+
+func newJunitNameOwners() *junitNameOwners {
+	return &junitNameOwners{
+		owners: map[string]string{},
+	}
+}
+
+// resolve applies the policy to the junits of a single stage.  Within a stage, monitor tests claim names in
+// alphabetical order so the result does not depend on which monitor test finished first.  If the policy is to fail,
+// a failing junit and an error describing the conflicts are returned.
+func (o *junitNameOwners) resolve(policy DuplicateTestNamePolicy, junits []*junitapi.JUnitTestCase) ([]*junitapi.JUnitTestCase, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	namesByMonitorTest := map[string]sets.String{}
+	for _, junit := range junits {
+		monitorTest := monitorTestOf(junit)
+		if len(monitorTest) == 0 {
+			continue
+		}
+		if _, ok := namesByMonitorTest[monitorTest]; !ok {
+			namesByMonitorTest[monitorTest] = sets.NewString()
+		}
+		namesByMonitorTest[monitorTest].Insert(junit.Name)
+	}
+
+	monitorTests := sets.StringKeySet(namesByMonitorTest).List()
+	// conflicts maps a test name to every other monitor test that emitted it.
+	conflicts := map[string]sets.String{}
+	for _, monitorTest := range monitorTests {
+		for _, name := range namesByMonitorTest[monitorTest].List() {
+			owner, ok := o.owners[name]
+			if !ok {
+				o.owners[name] = monitorTest
+				continue
+			}
+			if owner == monitorTest {
+				continue
+			}
+			if _, ok := conflicts[name]; !ok {
+				conflicts[name] = sets.NewString()
+			}
+			conflicts[name].Insert(monitorTest)
+		}
+	}
+	if len(conflicts) == 0 {
+		return junits, nil
+	}
+
+	conflictMessages := []string{}
+	for name, monitorTests := range conflicts {
+		conflictMessages = append(conflictMessages, fmt.Sprintf("%q emitted by monitor test %v and also by %v", name, o.owners[name], strings.Join(monitorTests.List(), ", ")))
+	}
+	sort.Strings(conflictMessages)
+
+	if policy == FailOnDuplicateTestNames {
+		failureMsg := strings.Join(conflictMessages, "\n")
+		return append(junits, &junitapi.JUnitTestCase{
+			Name:          duplicateTestNamesTestName,
+			SystemOut:     failureMsg,
+			FailureOutput: &junitapi.FailureOutput{Output: failureMsg},
+		}), fmt.Errorf("monitor tests emitted duplicate test names:\n%v", failureMsg)
+	}
+
+	for _, message := range conflictMessages {
+		logrus.Warnf("Namespacing duplicate test name, %s", message)
+	}
+	ret := make([]*junitapi.JUnitTestCase, 0, len(junits))
+	for _, junit := range junits {
+		monitorTest := monitorTestOf(junit)
+		if !conflicts[junit.Name].Has(monitorTest) {
+			ret = append(ret, junit)
+			continue
+		}
+		renamed := *junit
+		renamed.Name = namespacedTestName(junit.Name, monitorTest)
+		ret = append(ret, &renamed)
+	}
+	return ret, nil
+}
+
+// namespacedTestName keeps the sig prefix of the name so the test case is still attributed to the same team.
+func namespacedTestName(name, monitorTest string) string {
+	return fmt.Sprintf("%s [MonitorTest:%s]", name, monitorTest)
+}
+
+func monitorTestOf(junit *junitapi.JUnitTestCase) string {
+	if junit == nil || junit.Details == nil {
+		return ""
+	}
+	return junit.Details.MonitorTest
+}
@@ -0,0 +1,98 @@
+package monitortestframework
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func junitFrom(monitorTest, name string, failed bool) *junitapi.JUnitTestCase {
+	junit := &junitapi.JUnitTestCase{
+		Name:    name,
+		Details: &junitapi.JUnitTestCaseDetails{MonitorTest: monitorTest},
+	}
+	if failed {
+		junit.FailureOutput = &junitapi.FailureOutput{Output: "bad"}
+	}
+	return junit
+}
+
+func junitNames(junits []*junitapi.JUnitTestCase) []string {
+	ret := []string{}
+	for _, junit := range junits {
+		ret = append(ret, junit.Name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestJunitNameOwners_Namespace(t *testing.T) {
+	owners := newJunitNameOwners()
+
+	collected, err := owners.resolve(NamespaceDuplicateTestNames, []*junitapi.JUnitTestCase{
+		junitFrom("beta", "[sig-node] shared", true),
+		junitFrom("alpha", "[sig-node] shared", false),
+		// the same monitor test emitting a name twice is how flakes are reported, it is not a conflict.
+		junitFrom("alpha", "[sig-node] flaky", true),
+		junitFrom("alpha", "[sig-node] flaky", false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"[sig-node] flaky",
+		"[sig-node] flaky",
+		"[sig-node] shared",
+		"[sig-node] shared [MonitorTest:beta]",
+	}
+	if actual := junitNames(collected); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	// names claimed in an earlier stage keep their owner.
+	evaluated, err := owners.resolve(NamespaceDuplicateTestNames, []*junitapi.JUnitTestCase{
+		junitFrom("gamma", "[sig-node] flaky", true),
+		junitFrom("alpha", "[sig-node] shared", false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		"[sig-node] flaky [MonitorTest:gamma]",
+		"[sig-node] shared",
+	}
+	if actual := junitNames(evaluated); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestJunitNameOwners_Fail(t *testing.T) {
+	owners := newJunitNameOwners()
+
+	junits, err := owners.resolve(FailOnDuplicateTestNames, []*junitapi.JUnitTestCase{
+		junitFrom("beta", "[sig-node] shared", true),
+		junitFrom("alpha", "[sig-node] shared", false),
+		{Name: "registry output without a monitor test"},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), `"[sig-node] shared" emitted by monitor test alpha and also by beta`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := []string{
+		duplicateTestNamesTestName,
+		"[sig-node] shared",
+		"[sig-node] shared",
+		"registry output without a monitor test",
+	}
+	if actual := junitNames(junits); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if junits[len(junits)-1].FailureOutput == nil {
+		t.Errorf("expected the duplicate test name junit to fail")
+	}
+}
@@ -13,13 +13,40 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
 )
 
 type monitorTestRegistry struct {
-	monitorTests map[string]*monitorTesttItem
+	monitorTests    map[string]*monitorTesttItem
+	registryOutputs map[string]*registryOutputItem
+
+	failureBudgetPolicy     FailureBudgetPolicy
+	duplicateTestNamePolicy DuplicateTestNamePolicy
+	junitNameOwners         *junitNameOwners
+	quarantineList          *QuarantineList
+	storageLayout           StorageLayout
+	secondaryClusters       SecondaryClusters
+	backfillDuration        time.Duration
+	recorderWALFile         string
+	notifier                Notifier
+	issueFiler              IssueFiler
+	knownFailures           *KnownFailures
+	// clock times the stages of the monitor tests, and is handed to monitor tests implementing ClockedMonitorTest.
+	clock clock.PassiveClock
+	// offline is set when the registry runs on the artifacts of an earlier run, monitor tests that do not implement
+	// OfflineMonitorTest are reported as not supported instead of being called.
+	offline bool
+
+	// recorder is the recorder collection was started with, read when recovering from the write-ahead log.
+	recorder monitorapi.RecorderWriter
+	// recoveredBeginning is the earliest interval recovered from the write-ahead log of an earlier monitor process.
+	recoveredBeginning time.Time
+	// testOutcomes are the junits returned by every stage so far, handed to registry outputs implementing
+	// TestOutcomeRegistryOutput.
+	testOutcomes []*junitapi.JUnitTestCase
 }
 
 type monitorTesttItem struct {
@@ -27,11 +54,25 @@ type monitorTesttItem struct {
 	jiraComponent string
 
 	monitorTest MonitorTest
+	cleanups    *CleanupStack
+}
+
+type registryOutputItem struct {
+	name          string
+	jiraComponent string
+
+	output RegistryOutput
 }
 
 func NewMonitorTestRegistry() MonitorTestRegistry {
 	return &monitorTestRegistry{
-		monitorTests: map[string]*monitorTesttItem{},
+		monitorTests:            map[string]*monitorTesttItem{},
+		registryOutputs:         map[string]*registryOutputItem{},
+		failureBudgetPolicy:     DefaultFailureBudgetPolicy,
+		duplicateTestNamePolicy: NamespaceDuplicateTestNames,
+		junitNameOwners:         newJunitNameOwners(),
+		storageLayout:           DefaultStorageLayout,
+		clock:                   clock.RealClock{},
 	}
 }
 
@@ -39,11 +80,19 @@ func (r *monitorTestRegistry) AddMonitorTest(name, jiraComponent string, monitor
 	if _, ok := r.monitorTests[name]; ok {
 		return fmt.Errorf("%q is already registered", name)
 	}
-	r.monitorTests[name] = &monitorTesttItem{
+	item := &monitorTesttItem{
 		name:          name,
 		jiraComponent: jiraComponent,
 		monitorTest:   monitorTest,
 	}
+	if withCleanups, ok := monitorTest.(CleanupStackMonitorTest); ok {
+		item.cleanups = NewCleanupStack()
+		withCleanups.SetCleanupStack(item.cleanups)
+	}
+	if clocked, ok := monitorTest.(ClockedMonitorTest); ok {
+		clocked.SetClock(r.clock)
+	}
+	r.monitorTests[name] = item
 
 	return nil
 }
@@ -55,8 +104,45 @@ func (r *monitorTestRegistry) AddMonitorTestOrDie(name, jiraComponent string, mo
 	}
 }
 
+func (r *monitorTestRegistry) AddRegistryOutput(name, jiraComponent string, output RegistryOutput) error {
+	if _, ok := r.registryOutputs[name]; ok {
+		return fmt.Errorf("registry output %q is already registered", name)
+	}
+	r.registryOutputs[name] = &registryOutputItem{
+		name:          name,
+		jiraComponent: jiraComponent,
+		output:        output,
+	}
+
+	return nil
+}
+
+func (r *monitorTestRegistry) AddRegistryOutputOrDie(name, jiraComponent string, output RegistryOutput) {
+	err := r.AddRegistryOutput(name, jiraComponent, output)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// GetRegistryFor returns a registry containing only the named monitor tests.  Registry outputs are always kept
+// because they operate on whatever the selected monitor tests produce.
 func (r *monitorTestRegistry) GetRegistryFor(names ...string) (MonitorTestRegistry, error) {
 	ret := NewMonitorTestRegistry().(*monitorTestRegistry)
+	ret.failureBudgetPolicy = r.failureBudgetPolicy
+	ret.duplicateTestNamePolicy = r.duplicateTestNamePolicy
+	ret.quarantineList = r.quarantineList
+	ret.storageLayout = r.storageLayout
+	ret.secondaryClusters = r.secondaryClusters
+	ret.backfillDuration = r.backfillDuration
+	ret.recorderWALFile = r.recorderWALFile
+	ret.notifier = r.notifier
+	ret.issueFiler = r.issueFiler
+	ret.knownFailures = r.knownFailures
+	ret.clock = r.clock
+	ret.offline = r.offline
+	for name, registryOutput := range r.registryOutputs {
+		ret.registryOutputs[name] = registryOutput
+	}
 
 	missingNames := []string{}
 	for _, name := range names {
@@ -74,11 +160,53 @@ func (r *monitorTestRegistry) GetRegistryFor(names ...string) (MonitorTestRegist
 	return ret, nil
 }
 
+func (r *monitorTestRegistry) SetFailureBudgetPolicy(policy FailureBudgetPolicy) {
+	r.failureBudgetPolicy = policy
+}
+
+func (r *monitorTestRegistry) FailureBudgetPolicy() FailureBudgetPolicy {
+	return r.failureBudgetPolicy
+}
+
+func (r *monitorTestRegistry) SetDuplicateTestNamePolicy(policy DuplicateTestNamePolicy) {
+	r.duplicateTestNamePolicy = policy
+}
+
+func (r *monitorTestRegistry) SetQuarantineList(quarantineList *QuarantineList) {
+	r.quarantineList = quarantineList
+}
+
+func (r *monitorTestRegistry) SetStorageLayout(layout StorageLayout) {
+	r.storageLayout = layout
+}
+
+func (r *monitorTestRegistry) SetSecondaryClusters(clusters SecondaryClusters) {
+	r.secondaryClusters = clusters
+}
+
+func (r *monitorTestRegistry) SetBackfillDuration(duration time.Duration) {
+	r.backfillDuration = duration
+}
+
+func (r *monitorTestRegistry) SetRecorderWAL(filename string) {
+	r.recorderWALFile = filename
+}
+
+func (r *monitorTestRegistry) SetNotifier(notifier Notifier) {
+	r.notifier = notifier
+}
+
+func (r *monitorTestRegistry) SetIssueFiler(issueFiler IssueFiler, knownFailures *KnownFailures) {
+	r.issueFiler = issueFiler
+	r.knownFailures = knownFailures
+}
+
 func (r *monitorTestRegistry) ListMonitorTests() sets.String {
 	return sets.StringKeySet(r.monitorTests)
 }
 
 func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) ([]*junitapi.JUnitTestCase, error) {
+	r.recorder = recorder
 	wg := sync.WaitGroup{}
 	junitCh := make(chan *junitapi.JUnitTestCase, 2*len(r.monitorTests))
 	errCh := make(chan error, len(r.monitorTests))
@@ -88,18 +216,24 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 		go func(ctx context.Context, invariant *monitorTesttItem) {
 			defer wg.Done()
 
-			testName := fmt.Sprintf("[Jira:%q] monitor test %v setup", invariant.jiraComponent, invariant.name)
+			testName := invariant.stageTestName(StartCollectionStage)
 			logrus.Infof("  Starting %v for %v", invariant.name, invariant.jiraComponent)
 
-			start := time.Now()
-			err := startCollectionWithPanicProtection(ctx, invariant.monitorTest, adminRESTConfig, recorder)
-			end := time.Now()
+			start := r.clock.Now()
+			spanCtx, span := startMonitorTestSpan(ctx, "setup", invariant)
+			err := startCollectionWithPanicProtection(spanCtx, invariant.monitorTest, adminRESTConfig, recorder)
+			if err == nil {
+				err = r.startSecondaryCollection(spanCtx, invariant.monitorTest, recorder)
+			}
+			endMonitorTestSpan(span, err)
+			end := r.clock.Now()
 			duration := end.Sub(start)
 			if err != nil {
 				var nsErr *NotSupportedError
 				if errors.As(err, &nsErr) {
 					junitCh <- &junitapi.JUnitTestCase{
 						Name:     testName,
+						Details:  invariant.newJunitDetails(),
 						Duration: duration.Seconds(),
 						SkipMessage: &junitapi.SkipMessage{
 							Message: nsErr.Reason,
@@ -110,6 +244,7 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 				errCh <- err
 				junitCh <- &junitapi.JUnitTestCase{
 					Name:     testName,
+					Details:  invariant.newJunitDetails(),
 					Duration: duration.Seconds(),
 					FailureOutput: &junitapi.FailureOutput{
 						Output: fmt.Sprintf("failed during setup\n%v", err),
@@ -124,6 +259,7 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 
 			junitCh <- &junitapi.JUnitTestCase{
 				Name:     testName,
+				Details:  invariant.newJunitDetails(),
 				Duration: duration.Seconds(),
 			}
 		}(ctx, r.monitorTests[i])
@@ -143,13 +279,13 @@ func (r *monitorTestRegistry) StartCollection(ctx context.Context, adminRESTConf
 		errs = append(errs, curr)
 	}
 
-	return junits, utilerrors.NewAggregate(errs)
+	return r.recordTestOutcomes(r.quarantineList.Apply(junits)), utilerrors.NewAggregate(errs)
 }
 
 func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	wg := sync.WaitGroup{}
-	intervalsCh := make(chan monitorapi.Intervals, len(r.monitorTests))
-	junitCh := make(chan []*junitapi.JUnitTestCase, 3*len(r.monitorTests))
+	intervalsCh := make(chan monitorapi.Intervals, 2*len(r.monitorTests))
+	junitCh := make(chan []*junitapi.JUnitTestCase, 4*len(r.monitorTests))
 	errCh := make(chan error, len(r.monitorTests))
 
 	logrus.Infof("Starting CollectData for all monitor tests")
@@ -157,14 +293,20 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 		wg.Add(1)
 		go func(ctx context.Context, monitorTest *monitorTesttItem) {
 			defer wg.Done()
-			testName := fmt.Sprintf("[Jira:%q] monitor test %v collection", monitorTest.jiraComponent, monitorTest.name)
+			testName := monitorTest.stageTestName(CollectDataStage)
+
+			backfilledIntervals, backfillJunits := r.backfill(ctx, monitorTest, storageDir, beginning)
+			intervalsCh <- backfilledIntervals
+			junitCh <- backfillJunits
 
-			start := time.Now()
+			start := r.clock.Now()
 			logrus.Infof("  Starting CollectData for %s", testName)
-			localIntervals, localJunits, err := collectDataWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, beginning, end)
+			spanCtx, span := startMonitorTestSpan(ctx, "collection", monitorTest)
+			localIntervals, localJunits, err := collectDataWithPanicProtection(spanCtx, monitorTest.monitorTest, storageDir, beginning, end)
+			endMonitorTestSpan(span, err)
 			intervalsCh <- localIntervals
-			junitCh <- localJunits
-			end := time.Now()
+			junitCh <- monitorTest.withJunitDetails(localJunits)
+			end := r.clock.Now()
 			duration := end.Sub(start)
 			if err != nil {
 				var nsErr *NotSupportedError
@@ -172,6 +314,7 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 					junitCh <- []*junitapi.JUnitTestCase{
 						{
 							Name:     testName,
+							Details:  monitorTest.newJunitDetails(),
 							Duration: duration.Seconds(),
 							SkipMessage: &junitapi.SkipMessage{
 								Message: nsErr.Reason,
@@ -184,6 +327,7 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 				junitCh <- []*junitapi.JUnitTestCase{
 					{
 						Name:     testName,
+						Details:  monitorTest.newJunitDetails(),
 						Duration: duration.Seconds(),
 						FailureOutput: &junitapi.FailureOutput{
 							Output: fmt.Sprintf("failed during collection\n%v", err),
@@ -201,6 +345,7 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 			junitCh <- []*junitapi.JUnitTestCase{
 				{
 					Name:     testName,
+					Details:  monitorTest.newJunitDetails(),
 					Duration: duration.Seconds(),
 				},
 			}
@@ -225,29 +370,54 @@ func (r *monitorTestRegistry) CollectData(ctx context.Context, storageDir string
 	for curr := range errCh {
 		errs = append(errs, curr)
 	}
+	junits, err := r.junitNameOwners.resolve(r.duplicateTestNamePolicy, junits)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	recoveredIntervals, err := r.recoverFromWAL()
+	if err != nil {
+		logrus.WithError(err).Error("Unable to recover intervals from the recorder write-ahead log")
+		errs = append(errs, err)
+	}
+	intervals = append(intervals, recoveredIntervals...)
 
 	logrus.Infof("Finished CollectData for all monitor tests")
-	return intervals, junits, utilerrors.NewAggregate(errs)
+	return intervals, r.recordTestOutcomes(r.quarantineList.Apply(junits)), utilerrors.NewAggregate(errs)
 }
 
 func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
 	intervals := monitorapi.Intervals{}
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
+	startingStore := &lazyIntervalStore{intervals: startingIntervals}
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v interval construction", monitorTest.jiraComponent, monitorTest.name)
+		testName := monitorTest.stageTestName(ConstructComputedIntervalsStage)
+		if r.notSupportedOffline(monitorTest) {
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name:    testName,
+				Details: monitorTest.newJunitDetails(),
+				SkipMessage: &junitapi.SkipMessage{
+					Message: notSupportedOfflineReason,
+				},
+			})
+			continue
+		}
 
-		start := time.Now()
-		localIntervals, err := constructComputedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, startingIntervals, recordedResources, beginning, end)
+		start := r.clock.Now()
+		spanCtx, span := startMonitorTestSpan(ctx, "interval construction", monitorTest)
+		localIntervals, err := constructComputedIntervalsWithPanicProtection(spanCtx, monitorTest.monitorTest, startingIntervals, startingStore, recordedResources, beginning, end)
+		recordIntervalSpans(spanCtx, localIntervals)
+		endMonitorTestSpan(span, err)
 		intervals = append(intervals, localIntervals...)
-		end := time.Now()
+		end := r.clock.Now()
 		duration := end.Sub(start)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
 				junits = append(junits, &junitapi.JUnitTestCase{
 					Name:     testName,
+					Details:  monitorTest.newJunitDetails(),
 					Duration: duration.Seconds(),
 					SkipMessage: &junitapi.SkipMessage{
 						Message: nsErr.Reason,
@@ -259,6 +429,7 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 			errs = append(errs, err)
 			junits = append(junits, &junitapi.JUnitTestCase{
 				Name:     testName,
+				Details:  monitorTest.newJunitDetails(),
 				Duration: duration.Seconds(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: fmt.Sprintf("failed during interval construction\n%v", err),
@@ -273,30 +444,46 @@ func (r *monitorTestRegistry) ConstructComputedIntervals(ctx context.Context, st
 
 		junits = append(junits, &junitapi.JUnitTestCase{
 			Name:     testName,
+			Details:  monitorTest.newJunitDetails(),
 			Duration: duration.Seconds(),
 		})
 	}
 
-	return intervals, junits, utilerrors.NewAggregate(errs)
+	return intervals, r.recordTestOutcomes(r.quarantineList.Apply(junits)), utilerrors.NewAggregate(errs)
 }
 
 func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
+	// symptoms of injected faults are expected, invariants only see what the faults do not explain.
+	finalIntervals = withoutExcusedIntervals(finalIntervals)
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v test evaluation", monitorTest.jiraComponent, monitorTest.name)
+		testName := monitorTest.stageTestName(EvaluateTestsStage)
+		if r.notSupportedOffline(monitorTest) {
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name:    testName,
+				Details: monitorTest.newJunitDetails(),
+				SkipMessage: &junitapi.SkipMessage{
+					Message: notSupportedOfflineReason,
+				},
+			})
+			continue
+		}
 
-		start := time.Now()
-		localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(ctx, monitorTest.monitorTest, finalIntervals)
-		junits = append(junits, localJunits...)
-		end := time.Now()
+		start := r.clock.Now()
+		spanCtx, span := startMonitorTestSpan(ctx, "test evaluation", monitorTest)
+		localJunits, err := evaluateTestsFromConstructedIntervalsWithPanicProtection(spanCtx, monitorTest.monitorTest, finalIntervals)
+		endMonitorTestSpan(span, err)
+		junits = append(junits, r.failureBudgetPolicy.Apply(monitorTest.withJunitDetails(localJunits))...)
+		end := r.clock.Now()
 		duration := end.Sub(start)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
 				junits = append(junits, &junitapi.JUnitTestCase{
 					Name:     testName,
+					Details:  monitorTest.newJunitDetails(),
 					Duration: duration.Seconds(),
 					SkipMessage: &junitapi.SkipMessage{
 						Message: nsErr.Reason,
@@ -308,6 +495,7 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 			errs = append(errs, err)
 			junits = append(junits, &junitapi.JUnitTestCase{
 				Name:     testName,
+				Details:  monitorTest.newJunitDetails(),
 				Duration: duration.Seconds(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: fmt.Sprintf("failed during test evaluation\n%v", err),
@@ -322,9 +510,18 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 
 		junits = append(junits, &junitapi.JUnitTestCase{
 			Name:     testName,
+			Details:  monitorTest.newJunitDetails(),
 			Duration: duration.Seconds(),
 		})
 	}
+	junits, err := r.junitNameOwners.resolve(r.duplicateTestNamePolicy, junits)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	junits = r.recordTestOutcomes(r.quarantineList.Apply(junits))
+	junits = append(junits, r.recordTestOutcomes(r.notify(ctx, finalIntervals))...)
+	junits = append(junits, r.recordTestOutcomes(r.fileRegressions(ctx))...)
 
 	return junits, utilerrors.NewAggregate(errs)
 }
@@ -332,11 +529,14 @@ func (r *monitorTestRegistry) EvaluateTestsFromConstructedIntervals(ctx context.
 func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error) {
 	junits := []*junitapi.JUnitTestCase{}
 	errs := []error{}
+	// monitor tests write one at a time, so new and changed files can be attributed to the one that just wrote.
+	artifacts := []ArtifactIndexEntry{}
+	storageState := snapshotStorage(storageDir)
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v writing to storage", monitorTest.jiraComponent, monitorTest.name)
+		testName := monitorTest.stageTestName(WriteContentToStorageStage)
 
-		start := time.Now()
+		start := r.clock.Now()
 
 		var finalIntervalLength = len(finalIntervals)
 		fmt.Fprintf(os.Stderr, "Processing monitorTest: %s\n", monitorTest.name)
@@ -346,14 +546,24 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 			fmt.Fprintf(os.Stderr, "  last interval time: From = %s; To = %s\n", finalIntervals[finalIntervalLength-1].From, finalIntervals[finalIntervalLength-1].To)
 		}
 
-		err := writeContentToStorageWithPanicProtection(ctx, monitorTest.monitorTest, storageDir, timeSuffix, finalIntervals, finalResourceState)
-		end := time.Now()
+		spanCtx, span := startMonitorTestSpan(ctx, "writing to storage", monitorTest)
+		monitorTestStorageDir, err := r.storageLayout.prepareDir(storageDir, monitorTest.name)
+		if err == nil {
+			err = writeContentToStorageWithPanicProtection(spanCtx, monitorTest.monitorTest, monitorTestStorageDir, timeSuffix, finalIntervals, finalResourceState)
+			removeIfEmpty(storageDir, monitorTestStorageDir)
+		}
+		endMonitorTestSpan(span, err)
+		end := r.clock.Now()
+		newStorageState := snapshotStorage(storageDir)
+		artifacts = append(artifacts, artifactsWrittenBetween(storageDir, storageState, newStorageState, monitorTest.name, monitorTest.jiraComponent, monitorTest.monitorTest)...)
+		storageState = newStorageState
 		duration := end.Sub(start)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
 				junits = append(junits, &junitapi.JUnitTestCase{
 					Name:     testName,
+					Details:  monitorTest.newJunitDetails(),
 					Duration: duration.Seconds(),
 					SkipMessage: &junitapi.SkipMessage{
 						Message: nsErr.Reason,
@@ -365,6 +575,7 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 			errs = append(errs, err)
 			junits = append(junits, &junitapi.JUnitTestCase{
 				Name:     testName,
+				Details:  monitorTest.newJunitDetails(),
 				Duration: duration.Seconds(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: fmt.Sprintf("failed during test evaluation\n%v", err),
@@ -379,11 +590,64 @@ func (r *monitorTestRegistry) WriteContentToStorage(ctx context.Context, storage
 
 		junits = append(junits, &junitapi.JUnitTestCase{
 			Name:     testName,
+			Details:  monitorTest.newJunitDetails(),
 			Duration: duration.Seconds(),
 		})
 	}
 
-	return junits, utilerrors.NewAggregate(errs)
+	// registry outputs see the junits of monitor tests writing to storage the way they will be returned.
+	testOutcomes := append(append([]*junitapi.JUnitTestCase{}, r.testOutcomes...), r.quarantineList.Apply(junits)...)
+	// registry outputs are written in the order of their names, so their junits and artifacts are in a stable order.
+	for _, name := range sets.StringKeySet(r.registryOutputs).List() {
+		registryOutput := r.registryOutputs[name]
+		testName := fmt.Sprintf("[Jira:%q] monitor test registry output %v writing to storage", registryOutput.jiraComponent, registryOutput.name)
+
+		start := r.clock.Now()
+		registryOutputStorageDir, err := r.storageLayout.prepareDir(storageDir, registryOutput.name)
+		if err == nil {
+			setTestOutcomes(registryOutput.output, testOutcomes)
+			err = writeRegistryOutputWithPanicProtection(ctx, registryOutput.output, registryOutputStorageDir, timeSuffix, finalIntervals, finalResourceState)
+			removeIfEmpty(storageDir, registryOutputStorageDir)
+		}
+		end := r.clock.Now()
+		newStorageState := snapshotStorage(storageDir)
+		artifacts = append(artifacts, artifactsWrittenBetween(storageDir, storageState, newStorageState, registryOutput.name, registryOutput.jiraComponent, registryOutput.output)...)
+		storageState = newStorageState
+		duration := end.Sub(start)
+		if err != nil {
+			errs = append(errs, err)
+			junits = append(junits, &junitapi.JUnitTestCase{
+				Name:     testName,
+				Duration: duration.Seconds(),
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("failed writing registry output\n%v", err),
+				},
+				SystemOut: fmt.Sprintf("failed writing registry output\n%v", err),
+			})
+			continue
+		}
+
+		junits = append(junits, &junitapi.JUnitTestCase{
+			Name:     testName,
+			Duration: duration.Seconds(),
+		})
+	}
+
+	indexTestName := fmt.Sprintf("[Jira:%q] monitor test registry artifact index writing to storage", "Test Framework")
+	if err := registerArtifacts(storageDir, artifacts); err != nil {
+		errs = append(errs, err)
+		junits = append(junits, &junitapi.JUnitTestCase{
+			Name: indexTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("failed writing artifact index\n%v", err),
+			},
+			SystemOut: fmt.Sprintf("failed writing artifact index\n%v", err),
+		})
+	} else {
+		junits = append(junits, &junitapi.JUnitTestCase{Name: indexTestName})
+	}
+
+	return r.quarantineList.Apply(junits), utilerrors.NewAggregate(errs)
 }
 
 func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTestCase, error) {
@@ -391,19 +655,32 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 	errs := []error{}
 
 	for _, monitorTest := range r.monitorTests {
-		testName := fmt.Sprintf("[Jira:%q] monitor test %v cleanup", monitorTest.jiraComponent, monitorTest.name)
+		testName := monitorTest.stageTestName(CleanupStage)
 		log := logrus.WithField("monitorTest", monitorTest.name)
 
-		start := time.Now()
+		start := r.clock.Now()
 		log.Info("beginning cleanup")
-		err := cleanupWithPanicProtection(ctx, monitorTest.monitorTest)
-		end := time.Now()
+		spanCtx, span := startMonitorTestSpan(ctx, "cleanup", monitorTest)
+		err := cleanupWithPanicProtection(spanCtx, monitorTest.monitorTest)
+		if monitorTest.cleanups != nil {
+			// the stack runs even when Cleanup failed, a skip is only a skip when the stack succeeded too.
+			if stackErr := monitorTest.cleanups.Run(spanCtx); stackErr != nil {
+				if err == nil {
+					err = stackErr
+				} else {
+					err = utilerrors.NewAggregate([]error{err, stackErr})
+				}
+			}
+		}
+		endMonitorTestSpan(span, err)
+		end := r.clock.Now()
 		duration := end.Sub(start)
 		if err != nil {
 			var nsErr *NotSupportedError
 			if errors.As(err, &nsErr) {
 				junits = append(junits, &junitapi.JUnitTestCase{
 					Name:     testName,
+					Details:  monitorTest.newJunitDetails(),
 					Duration: duration.Seconds(),
 					SkipMessage: &junitapi.SkipMessage{
 						Message: nsErr.Reason,
@@ -416,6 +693,7 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 			errs = append(errs, err)
 			junits = append(junits, &junitapi.JUnitTestCase{
 				Name:     testName,
+				Details:  monitorTest.newJunitDetails(),
 				Duration: duration.Seconds(),
 				FailureOutput: &junitapi.FailureOutput{
 					Output: fmt.Sprintf("failed during cleanup\n%v", err),
@@ -430,19 +708,55 @@ func (r *monitorTestRegistry) Cleanup(ctx context.Context) ([]*junitapi.JUnitTes
 
 		junits = append(junits, &junitapi.JUnitTestCase{
 			Name:     testName,
+			Details:  monitorTest.newJunitDetails(),
 			Duration: duration.Seconds(),
 		})
 	}
 
-	return junits, utilerrors.NewAggregate(errs)
+	return r.quarantineList.Apply(junits), utilerrors.NewAggregate(errs)
 }
 
 func (r *monitorTestRegistry) AddRegistryOrDie(registry MonitorTestRegistry) {
 	for _, v := range registry.getMonitorTests() {
 		r.AddMonitorTestOrDie(v.name, v.jiraComponent, v.monitorTest)
 	}
+	for _, v := range registry.getRegistryOutputs() {
+		r.AddRegistryOutputOrDie(v.name, v.jiraComponent, v.output)
+	}
 }
 
 func (r *monitorTestRegistry) getMonitorTests() map[string]*monitorTesttItem {
 	return r.monitorTests
 }
+
+func (r *monitorTestRegistry) getRegistryOutputs() map[string]*registryOutputItem {
+	return r.registryOutputs
+}
+
+func (i *monitorTesttItem) newJunitDetails() *junitapi.JUnitTestCaseDetails {
+	return &junitapi.JUnitTestCaseDetails{
+		MonitorTest:   i.name,
+		JiraComponent: i.jiraComponent,
+	}
+}
+
+// withJunitDetails records which monitor test produced each junit so that machine-readable summaries
+// can attribute results without parsing test names.  Details already set by the monitor test are preserved.
+func (i *monitorTesttItem) withJunitDetails(junits []*junitapi.JUnitTestCase) []*junitapi.JUnitTestCase {
+	for _, junit := range junits {
+		if junit == nil {
+			continue
+		}
+		if junit.Details == nil {
+			junit.Details = i.newJunitDetails()
+			continue
+		}
+		if len(junit.Details.MonitorTest) == 0 {
+			junit.Details.MonitorTest = i.name
+		}
+		if len(junit.Details.JiraComponent) == 0 {
+			junit.Details.JiraComponent = i.jiraComponent
+		}
+	}
+	return junits
+}
@@ -0,0 +1,31 @@
+package monitortestframework
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IndexedIntervalsMonitorTest may be implemented by a MonitorTest that looks up the starting intervals by source or
+// by locator key.  The registry indexes the starting intervals once per run and hands the same store to every such
+// monitor test, instead of each of them scanning every interval of the run.
+type IndexedIntervalsMonitorTest interface {
+	// ConstructComputedIntervalsFromStore is called instead of ConstructComputedIntervals.  startingStore holds the
+	// same intervals as startingIntervals.  It is shared between monitor tests and must not be added to.
+	ConstructComputedIntervalsFromStore(ctx context.Context, startingIntervals monitorapi.Intervals, startingStore *monitorapi.IntervalStore, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error)
+}
+
+// lazyIntervalStore indexes the starting intervals the first time a monitor test asks for them, so runs without an
+// IndexedIntervalsMonitorTest do not pay for the index.
+type lazyIntervalStore struct {
+	intervals monitorapi.Intervals
+	store     *monitorapi.IntervalStore
+}
+
+func (l *lazyIntervalStore) get() *monitorapi.IntervalStore {
+	if l.store == nil {
+		l.store = monitorapi.NewIntervalStore(l.intervals...)
+	}
+	return l.store
+}
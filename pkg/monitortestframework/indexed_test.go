@@ -0,0 +1,39 @@
+package monitortestframework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type indexedReader struct {
+	fileWriter
+	stores []*monitorapi.IntervalStore
+}
+
+func (w *indexedReader) ConstructComputedIntervalsFromStore(ctx context.Context, startingIntervals monitorapi.Intervals, startingStore *monitorapi.IntervalStore, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	w.stores = append(w.stores, startingStore)
+	return startingStore.ByLocatorKey(monitorapi.LocatorNodeKey, "master-0", time.Time{}, time.Time{}), nil
+}
+
+func TestRegistryIndexedIntervals(t *testing.T) {
+	first, second := &indexedReader{}, &indexedReader{}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("first", "Test Framework", first)
+	registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{})
+	registry.AddMonitorTestOrDie("second", "Test Framework", second)
+
+	now := time.Now()
+	intervals, _, err := registry.ConstructComputedIntervals(context.Background(), monitorapi.Intervals{nodeInterval()}, monitorapi.ResourcesMap{}, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 2 {
+		t.Errorf("expected both indexed monitor tests to find the node interval, got %v", intervals)
+	}
+	if len(first.stores) != 1 || len(second.stores) != 1 || first.stores[0] != second.stores[0] {
+		t.Errorf("expected the starting intervals to be indexed once and shared")
+	}
+}
@@ -0,0 +1,232 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// defaultFaultGrace is how long after an injected fault ends its symptoms are excused when the fault does not say.
+const defaultFaultGrace = 5 * time.Minute
+
+// InjectedFaults is the content of a file chaos tooling drops to declare the faults it injected.
+type InjectedFaults struct {
+	Faults []InjectedFault `json:"faults"`
+}
+
+// InjectedFault is a fault injected on purpose, like killing a node or partitioning the network.  Warning and error
+// intervals that correlate with it are excused, so invariants only fail on symptoms the fault does not explain.
+type InjectedFault struct {
+	// Name identifies the fault, for instance the name of the chaos experiment.
+	Name string `json:"name"`
+	// Kind is what was injected, for instance node-kill or network-partition.
+	Kind    string    `json:"kind,omitempty"`
+	Message string    `json:"message,omitempty"`
+	From    time.Time `json:"from"`
+	// To is when the fault was lifted.  Faults that happen at once, like killing a node, leave it unset.
+	To *time.Time `json:"to,omitempty"`
+	// Nodes and Namespaces are what the fault targeted, for instance both sides of a partition.  Symptoms located on
+	// other nodes and namespaces are not excused.  A fault without either targets the whole cluster.
+	Nodes      []string `json:"nodes,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Grace is how long after the fault ends its symptoms are still excused, for instance while a killed node
+	// reboots.  Defaults to 5m.
+	Grace *metav1.Duration `json:"grace,omitempty"`
+}
+
+// LoadInjectedFaultDir reads the faults of every yaml or json file in dir, in name order.  A directory that does not
+// exist has no faults, tooling may create it only when it injects one.
+func LoadInjectedFaultDir(dir string) ([]InjectedFault, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []InjectedFault{}
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		faults, err := ParseInjectedFaults(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid injected faults %q: %w", path, err)
+		}
+		ret = append(ret, faults...)
+	}
+	return ret, nil
+}
+
+// ParseInjectedFaults parses and validates the faults of a file.
+func ParseInjectedFaults(content []byte) ([]InjectedFault, error) {
+	ret := &InjectedFaults{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	for _, fault := range ret.Faults {
+		if err := fault.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return ret.Faults, nil
+}
+
+func (f InjectedFault) Validate() error {
+	if len(f.Name) == 0 {
+		return fmt.Errorf("injected faults must have a name")
+	}
+	if f.From.IsZero() {
+		return fmt.Errorf("injected fault %q must have a from time", f.Name)
+	}
+	if f.To != nil && f.To.Before(f.From) {
+		return fmt.Errorf("injected fault %q must end after it begins", f.Name)
+	}
+	if f.Grace != nil && f.Grace.Duration < 0 {
+		return fmt.Errorf("injected fault %q must not have a negative grace", f.Name)
+	}
+	return nil
+}
+
+// end is when the fault was lifted, which is when it began for faults that happen at once.
+func (f InjectedFault) end() time.Time {
+	if f.To == nil {
+		return f.From
+	}
+	return *f.To
+}
+
+func (f InjectedFault) grace() time.Duration {
+	if f.Grace == nil {
+		return defaultFaultGrace
+	}
+	return f.Grace.Duration
+}
+
+// Interval records the fault as the cause of its symptoms.  The targets and grace are annotations, so the fault can
+// be read back from the intervals with InjectedFaultFromInterval.
+func (f InjectedFault) Interval() monitorapi.Interval {
+	message := monitorapi.NewMessage().Reason(monitorapi.FaultInjectedReason).
+		WithAnnotation(monitorapi.AnnotationFaultGrace, f.grace().String())
+	if len(f.Kind) > 0 {
+		message = message.WithAnnotation(monitorapi.AnnotationFaultKind, f.Kind)
+	}
+	if len(f.Nodes) > 0 {
+		message = message.WithAnnotation(monitorapi.AnnotationFaultNodes, strings.Join(f.Nodes, ","))
+	}
+	if len(f.Namespaces) > 0 {
+		message = message.WithAnnotation(monitorapi.AnnotationFaultNamespaces, strings.Join(f.Namespaces, ","))
+	}
+	humanMessage := f.Message
+	if len(humanMessage) == 0 {
+		humanMessage = fmt.Sprintf("injected %s", f.Name)
+	}
+
+	return monitorapi.NewInterval(monitorapi.SourceInjectedFault, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().InjectedFault(f.Name)).
+		Message(message.HumanMessage(humanMessage)).
+		Display().
+		Build(f.From, f.end())
+}
+
+// InjectedFaultFromInterval returns the fault an interval recorded, or false if it is not an injected fault.
+func InjectedFaultFromInterval(interval monitorapi.Interval) (InjectedFault, bool) {
+	if interval.Source != monitorapi.SourceInjectedFault {
+		return InjectedFault{}, false
+	}
+	annotations := interval.Message.Annotations
+	ret := InjectedFault{
+		Name:       interval.Locator.Keys[monitorapi.LocatorInjectedFaultKey],
+		Kind:       annotations[monitorapi.AnnotationFaultKind],
+		Message:    interval.Message.HumanMessage,
+		From:       interval.From,
+		Nodes:      splitList(annotations[monitorapi.AnnotationFaultNodes]),
+		Namespaces: splitList(annotations[monitorapi.AnnotationFaultNamespaces]),
+	}
+	if interval.To.After(interval.From) {
+		to := interval.To
+		ret.To = &to
+	}
+	if grace, err := time.ParseDuration(annotations[monitorapi.AnnotationFaultGrace]); err == nil {
+		ret.Grace = &metav1.Duration{Duration: grace}
+	}
+	return ret, len(ret.Name) > 0
+}
+
+func splitList(value string) []string {
+	if len(value) == 0 {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// Excuses returns true when the interval is a warning or error that overlaps the fault or its grace, and is located
+// on what the fault targeted.
+func (f InjectedFault) Excuses(interval monitorapi.Interval) bool {
+	if interval.Source == monitorapi.SourceInjectedFault {
+		return false
+	}
+	if interval.Level != monitorapi.Warning && interval.Level != monitorapi.Error {
+		return false
+	}
+	if !interval.From.Before(f.end().Add(f.grace())) {
+		return false
+	}
+	if !interval.To.IsZero() && interval.To.Before(f.From) {
+		return false
+	}
+	if len(f.Nodes) == 0 && len(f.Namespaces) == 0 {
+		return true
+	}
+	return contains(f.Nodes, interval.Locator.Keys[monitorapi.LocatorNodeKey]) ||
+		contains(f.Namespaces, interval.Locator.Keys[monitorapi.LocatorNamespaceKey])
+}
+
+func contains(values []string, value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for _, curr := range values {
+		if curr == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SortInjectedFaults orders faults by when they began, then by name, so the earliest fault excuses a symptom first.
+func SortInjectedFaults(faults []InjectedFault) {
+	sort.SliceStable(faults, func(i, j int) bool {
+		if !faults[i].From.Equal(faults[j].From) {
+			return faults[i].From.Before(faults[j].From)
+		}
+		return faults[i].Name < faults[j].Name
+	})
+}
+
+// withoutExcusedIntervals drops the intervals an injected fault excused, so invariants evaluate the rest.
+func withoutExcusedIntervals(intervals monitorapi.Intervals) monitorapi.Intervals {
+	return intervals.Filter(func(interval monitorapi.Interval) bool {
+		_, excused := interval.Message.Annotations[monitorapi.AnnotationExcusedBy]
+		return !excused
+	})
+}
@@ -0,0 +1,188 @@
+package monitortestframework
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestParseInjectedFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			content: `
+faults:
+- name: partition-workers
+  kind: network-partition
+  from: 2024-01-01T00:00:00Z
+  to: 2024-01-01T00:05:00Z
+  nodes: [worker-0, worker-1]
+  grace: 2m
+`,
+		},
+		{
+			name:    "missing name",
+			content: "faults:\n- from: 2024-01-01T00:00:00Z\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing from",
+			content: "faults:\n- name: kill\n",
+			wantErr: true,
+		},
+		{
+			name:    "ends before it begins",
+			content: "faults:\n- name: kill\n  from: 2024-01-01T00:05:00Z\n  to: 2024-01-01T00:00:00Z\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			content: "faults:\n- name: kill\n  from: 2024-01-01T00:00:00Z\n  node: worker-0\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseInjectedFaults([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadInjectedFaultDir(t *testing.T) {
+	faults, err := LoadInjectedFaultDir(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(faults) != 0 {
+		t.Fatalf("expected no faults in a missing directory, got %v, %v", faults, err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.json":    `{"faults": [{"name": "second", "from": "2024-01-01T00:00:00Z"}]}`,
+		"a.yaml":    "faults:\n- name: first\n  from: 2024-01-01T00:00:00Z\n",
+		"notes.txt": "not a fault",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	faults, err = LoadInjectedFaultDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(faults) != 2 || faults[0].Name != "first" || faults[1].Name != "second" {
+		t.Errorf("expected the faults of the yaml and json files in name order, got %v", faults)
+	}
+}
+
+func TestInjectedFaultInterval(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	fault := InjectedFault{
+		Name:       "partition-workers",
+		Kind:       "network-partition",
+		Message:    "partitioned worker-0 from worker-1",
+		From:       from,
+		To:         &to,
+		Nodes:      []string{"worker-0", "worker-1"},
+		Namespaces: []string{"e2e-test"},
+		Grace:      &metav1.Duration{Duration: 2 * time.Minute},
+	}
+	roundTripped, ok := InjectedFaultFromInterval(fault.Interval())
+	if !ok {
+		t.Fatal("expected the interval to be an injected fault")
+	}
+	if !reflect.DeepEqual(fault, roundTripped) {
+		t.Errorf("expected %#v, got %#v", fault, roundTripped)
+	}
+
+	if _, ok := InjectedFaultFromInterval(monitorapi.Interval{Source: monitorapi.SourceDisruption}); ok {
+		t.Error("expected a disruption interval not to be an injected fault")
+	}
+}
+
+func TestInjectedFaultExcuses(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kill := InjectedFault{Name: "kill-worker-0", From: from, Nodes: []string{"worker-0"}, Grace: &metav1.Duration{Duration: 5 * time.Minute}}
+	symptom := func(level monitorapi.IntervalLevel, node string, start, end time.Duration) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceNodeState, level).
+			Locator(monitorapi.NewLocator().NodeFromName(node)).
+			Message(monitorapi.NewMessage().HumanMessage("not ready")).
+			Build(from.Add(start), from.Add(end))
+	}
+
+	tests := []struct {
+		name     string
+		fault    InjectedFault
+		interval monitorapi.Interval
+		excused  bool
+	}{
+		{
+			name:     "error on the node within the grace",
+			fault:    kill,
+			interval: symptom(monitorapi.Error, "worker-0", time.Minute, 10*time.Minute),
+			excused:  true,
+		},
+		{
+			name:     "error on the node after the grace",
+			fault:    kill,
+			interval: symptom(monitorapi.Error, "worker-0", 6*time.Minute, 10*time.Minute),
+		},
+		{
+			name:     "error on the node before the fault",
+			fault:    kill,
+			interval: symptom(monitorapi.Error, "worker-0", -2*time.Minute, -time.Minute),
+		},
+		{
+			name:     "error on another node",
+			fault:    kill,
+			interval: symptom(monitorapi.Error, "worker-1", time.Minute, 2*time.Minute),
+		},
+		{
+			name:     "info on the node",
+			fault:    kill,
+			interval: symptom(monitorapi.Info, "worker-0", time.Minute, 2*time.Minute),
+		},
+		{
+			name:     "cluster wide fault",
+			fault:    InjectedFault{Name: "kill-apiserver", From: from},
+			interval: symptom(monitorapi.Warning, "worker-1", time.Minute, 2*time.Minute),
+			excused:  true,
+		},
+		{
+			name:     "the fault itself",
+			fault:    InjectedFault{Name: "kill-apiserver", From: from},
+			interval: InjectedFault{Name: "kill-apiserver", From: from}.Interval(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if excused := tt.fault.Excuses(tt.interval); excused != tt.excused {
+				t.Errorf("expected excused %v, got %v", tt.excused, excused)
+			}
+		})
+	}
+}
+
+func TestWithoutExcusedIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		{Condition: monitorapi.Condition{Message: monitorapi.Message{HumanMessage: "symptom", Annotations: map[monitorapi.AnnotationKey]string{monitorapi.AnnotationExcusedBy: "kill-worker-0"}}}},
+		{Condition: monitorapi.Condition{Message: monitorapi.Message{HumanMessage: "unrelated"}}},
+	}
+	remaining := withoutExcusedIntervals(intervals)
+	if len(remaining) != 1 || remaining[0].Message.HumanMessage != "unrelated" {
+		t.Errorf("expected only the unrelated interval to remain, got %v", remaining)
+	}
+}
@@ -0,0 +1,161 @@
+package monitortestframework
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const issueFilingTestName = `[Jira:"Test Framework"] monitor test regressions should be filed`
+
+// IssueFiler files an issue for every regression once the registry has evaluated the tests, or updates the issue
+// already filed for it.
+type IssueFiler interface {
+	FileRegressions(ctx context.Context, regressions []Regression) ([]Filing, error)
+}
+
+// Regression is a monitor test failure that is not known to fail.  It is the payload attached to the issue.
+type Regression struct {
+	TestName      string `json:"testName"`
+	MonitorTest   string `json:"monitorTest"`
+	JiraComponent string `json:"jiraComponent"`
+	// Output is the whole failure output.
+	Output    string `json:"output"`
+	SystemOut string `json:"systemOut,omitempty"`
+}
+
+// Filing is what an IssueFiler did for a regression.
+type Filing struct {
+	TestName string `json:"testName"`
+	// Issue is the key of the issue filed or updated.  It is empty when a dry run would create one.
+	Issue string `json:"issue,omitempty"`
+	// Action is created or updated, or in a dry run, would create or would update.
+	Action string `json:"action"`
+}
+
+// KnownFailures are the tests known to fail, for instance exported from the failure history of the job.  Their
+// failures are not regressions and are not filed.
+type KnownFailures struct {
+	Failures []KnownFailure `json:"knownFailures"`
+}
+
+type KnownFailure struct {
+	TestName string `json:"testName"`
+	// Issue is the issue tracking the failure, if there is one.
+	Issue string `json:"issue,omitempty"`
+}
+
+// LoadKnownFailures reads known failures from a yaml or json file.
+func LoadKnownFailures(path string) (*KnownFailures, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := ParseKnownFailures(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid known failures %q: %w", path, err)
+	}
+	return ret, nil
+}
+
+// ParseKnownFailures parses and validates known failures.
+func ParseKnownFailures(content []byte) (*KnownFailures, error) {
+	ret := &KnownFailures{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	for _, failure := range ret.Failures {
+		if len(failure.TestName) == 0 {
+			return nil, fmt.Errorf("known failures must have a test name")
+		}
+	}
+	return ret, nil
+}
+
+func (k *KnownFailures) contains(testName string) bool {
+	if k == nil {
+		return false
+	}
+	for _, failure := range k.Failures {
+		if failure.TestName == testName {
+			return true
+		}
+	}
+	return false
+}
+
+// FindRegressions returns the monitor tests that failed and never passed, and are not known failures, ordered by
+// name.  Flakes are left out, and so are junits no monitor test owns, because there is no component to file them in.
+func FindRegressions(junits []*junitapi.JUnitTestCase, knownFailures *KnownFailures) []Regression {
+	passed := map[string]bool{}
+	for _, junit := range junits {
+		if junit.FailureOutput == nil && junit.SkipMessage == nil {
+			passed[junit.Name] = true
+		}
+	}
+
+	ret := []Regression{}
+	found := map[string]bool{}
+	for _, junit := range junits {
+		if junit.FailureOutput == nil || passed[junit.Name] || found[junit.Name] {
+			continue
+		}
+		if junit.Details == nil || len(junit.Details.JiraComponent) == 0 || knownFailures.contains(junit.Name) {
+			continue
+		}
+		found[junit.Name] = true
+		ret = append(ret, Regression{
+			TestName:      junit.Name,
+			MonitorTest:   junit.Details.MonitorTest,
+			JiraComponent: junit.Details.JiraComponent,
+			Output:        junit.FailureOutput.Output,
+			SystemOut:     junit.SystemOut,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].TestName < ret[j].TestName
+	})
+	return ret
+}
+
+// fileRegressions files the regressions of the run.  Filing that fails flakes, because it says nothing about the
+// cluster.
+func (r *monitorTestRegistry) fileRegressions(ctx context.Context) []*junitapi.JUnitTestCase {
+	if r.issueFiler == nil {
+		return nil
+	}
+	regressions := FindRegressions(r.testOutcomes, r.knownFailures)
+	if len(regressions) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: issueFilingTestName, SystemOut: "no regressions"}}
+	}
+
+	filings, err := r.issueFiler.FileRegressions(ctx, regressions)
+	summary := &strings.Builder{}
+	for _, filing := range filings {
+		if len(filing.Issue) == 0 {
+			fmt.Fprintf(summary, "%s: %s\n", filing.Action, filing.TestName)
+			continue
+		}
+		fmt.Fprintf(summary, "%s %s: %s\n", filing.Action, filing.Issue, filing.TestName)
+	}
+	success := &junitapi.JUnitTestCase{Name: issueFilingTestName, SystemOut: summary.String()}
+	if err != nil {
+		return []*junitapi.JUnitTestCase{
+			{
+				Name:      issueFilingTestName,
+				SystemOut: summary.String(),
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("unable to file %d regressions: %v", len(regressions), err),
+				},
+			},
+			success,
+		}
+	}
+	return []*junitapi.JUnitTestCase{success}
+}
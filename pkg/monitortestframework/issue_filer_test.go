@@ -0,0 +1,135 @@
+package monitortestframework
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type recordingIssueFiler struct {
+	regressions [][]Regression
+	err         error
+}
+
+func (f *recordingIssueFiler) FileRegressions(ctx context.Context, regressions []Regression) ([]Filing, error) {
+	f.regressions = append(f.regressions, regressions)
+	filings := []Filing{}
+	for _, regression := range regressions {
+		filings = append(filings, Filing{TestName: regression.TestName, Issue: "OCPBUGS-1", Action: "created"})
+	}
+	return filings, f.err
+}
+
+// failingInvariant fails the invariant it is named for.
+type failingInvariant struct {
+	fileWriter
+	testName string
+}
+
+func (w *failingInvariant) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return []*junitapi.JUnitTestCase{
+		{Name: w.testName, FailureOutput: &junitapi.FailureOutput{Output: "violated"}},
+	}, nil
+}
+
+func TestParseKnownFailures(t *testing.T) {
+	knownFailures, err := ParseKnownFailures([]byte(`
+knownFailures:
+- testName: "[sig-network] known"
+  issue: OCPBUGS-2
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !knownFailures.contains("[sig-network] known") || knownFailures.contains("[sig-network] unknown") {
+		t.Errorf("expected only the listed test to be known, got %#v", knownFailures)
+	}
+
+	if _, err := ParseKnownFailures([]byte(`knownFailures: [{issue: OCPBUGS-2}]`)); err == nil {
+		t.Errorf("expected known failures without a test name to be rejected")
+	}
+	if _, err := ParseKnownFailures([]byte(`knownFailures: [{testName: a, bug: OCPBUGS-2}]`)); err == nil {
+		t.Errorf("expected unknown fields to be rejected")
+	}
+}
+
+func TestFindRegressions(t *testing.T) {
+	owned := func() *junitapi.JUnitTestCaseDetails {
+		return &junitapi.JUnitTestCaseDetails{MonitorTest: "a", JiraComponent: "A"}
+	}
+	junits := []*junitapi.JUnitTestCase{
+		{Name: "passes", Details: owned()},
+		{Name: "flakes", FailureOutput: &junitapi.FailureOutput{Output: "failed"}, Details: owned()},
+		{Name: "flakes", Details: owned()},
+		{Name: "regressed", FailureOutput: &junitapi.FailureOutput{Output: "failed"}, SystemOut: "details", Details: owned()},
+		{Name: "regressed", FailureOutput: &junitapi.FailureOutput{Output: "again"}, Details: owned()},
+		{Name: "known", FailureOutput: &junitapi.FailureOutput{Output: "failed"}, Details: owned()},
+		{Name: "unowned", FailureOutput: &junitapi.FailureOutput{Output: "failed"}},
+	}
+	knownFailures := &KnownFailures{Failures: []KnownFailure{{TestName: "known"}}}
+
+	expected := []Regression{
+		{TestName: "regressed", MonitorTest: "a", JiraComponent: "A", Output: "failed", SystemOut: "details"},
+	}
+	if actual := FindRegressions(junits, knownFailures); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expected, actual)
+	}
+}
+
+func TestRegistryFilesRegressions(t *testing.T) {
+	tests := []struct {
+		name           string
+		fileErr        error
+		knownFailures  *KnownFailures
+		expectedFiled  int
+		expectedFailed bool
+	}{
+		{name: "filed", expectedFiled: 1},
+		{name: "known failures are not filed", knownFailures: &KnownFailures{Failures: []KnownFailure{{TestName: "[sig-foo] invariant"}}}},
+		{name: "failing to file flakes", fileErr: errors.New("unauthorized"), expectedFiled: 1, expectedFailed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewMonitorTestRegistry()
+			registry.AddMonitorTestOrDie("invariant", "Networking", &failingInvariant{testName: "[sig-foo] invariant"})
+			filer := &recordingIssueFiler{err: tt.fileErr}
+			registry.SetIssueFiler(filer, tt.knownFailures)
+
+			junits, err := registry.EvaluateTestsFromConstructedIntervals(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filed := 0
+			for _, regressions := range filer.regressions {
+				filed += len(regressions)
+				for _, regression := range regressions {
+					if regression.JiraComponent != "Networking" {
+						t.Errorf("expected the regression to be filed in the component of its monitor test, got %q", regression.JiraComponent)
+					}
+				}
+			}
+			if filed != tt.expectedFiled {
+				t.Errorf("expected %d regressions filed, got %d", tt.expectedFiled, filed)
+			}
+
+			passed, failed := 0, 0
+			for _, junit := range junits {
+				if junit.Name != issueFilingTestName {
+					continue
+				}
+				if junit.FailureOutput != nil {
+					failed++
+				} else {
+					passed++
+				}
+			}
+			if passed != 1 || (failed == 1) != tt.expectedFailed {
+				t.Errorf("expected filing to pass once and fail %v, got %d passes and %d failures", tt.expectedFailed, passed, failed)
+			}
+		})
+	}
+}
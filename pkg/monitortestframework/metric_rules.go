@@ -0,0 +1,128 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// DefaultMetricRuleStep is how far apart the samples of a metric rule are when the rule does not say.
+const DefaultMetricRuleStep = 30 * time.Second
+
+// MetricRules turn the series of PromQL queries into intervals whenever they cross a threshold, so invariants on
+// metrics can be added as configuration rather than as a monitor test.
+type MetricRules struct {
+	Rules []MetricRule `json:"rules"`
+}
+
+type MetricRule struct {
+	// Name identifies the rule.  It is the metric-rule key of the locators of its intervals.
+	Name string `json:"name"`
+	// Query is the PromQL range query the rule watches.  Every series it returns is watched separately.
+	Query string `json:"query"`
+	// Above and Below are the threshold.  Exactly one must be set, and a series crosses it when its value is
+	// greater than Above or less than Below.
+	Above *float64 `json:"above,omitempty"`
+	Below *float64 `json:"below,omitempty"`
+	// Step is how far apart the samples are.  If zero, DefaultMetricRuleStep is used.
+	Step metav1.Duration `json:"step,omitempty"`
+	// Level is the level of the intervals, Info, Warning, or Error.  If empty, Warning is used.
+	Level string `json:"level,omitempty"`
+	// Locator maps locator keys to the labels of the series they are read from, for instance node: instance.
+	Locator map[string]string `json:"locator,omitempty"`
+	// Message is the human message of the intervals.  If empty, it describes the threshold.
+	Message string `json:"message,omitempty"`
+	// FailAfter makes the rule an invariant: a test fails when a series stays past the threshold for longer.  If
+	// nil, the rule only records intervals.
+	FailAfter *metav1.Duration `json:"failAfter,omitempty"`
+	// TestName is the name of the test of an invariant.  If empty, it is derived from the name of the rule.
+	TestName string `json:"testName,omitempty"`
+}
+
+// IntervalLevel returns the level of the intervals of the rule.
+func (r MetricRule) IntervalLevel() monitorapi.IntervalLevel {
+	if len(r.Level) == 0 {
+		return monitorapi.Warning
+	}
+	// the level was validated when the rules were parsed.
+	level, _ := monitorapi.ConditionLevelFromString(r.Level)
+	return level
+}
+
+// SampleStep returns how far apart the samples of the rule are.
+func (r MetricRule) SampleStep() time.Duration {
+	if r.Step.Duration == 0 {
+		return DefaultMetricRuleStep
+	}
+	return r.Step.Duration
+}
+
+// Crossed returns whether the value is past the threshold of the rule.
+func (r MetricRule) Crossed(value float64) bool {
+	if r.Above != nil {
+		return value > *r.Above
+	}
+	return value < *r.Below
+}
+
+// Threshold describes the threshold of the rule, for instance "above 0.5".
+func (r MetricRule) Threshold() string {
+	if r.Above != nil {
+		return fmt.Sprintf("above %v", *r.Above)
+	}
+	return fmt.Sprintf("below %v", *r.Below)
+}
+
+// LoadMetricRules reads the metric rules from a yaml or json file.
+func LoadMetricRules(path string) (*MetricRules, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := ParseMetricRules(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric rules %q: %w", path, err)
+	}
+	return ret, nil
+}
+
+// ParseMetricRules parses and validates the metric rules.
+func ParseMetricRules(content []byte) (*MetricRules, error) {
+	ret := &MetricRules{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, rule := range ret.Rules {
+		if len(rule.Name) == 0 || len(rule.Query) == 0 {
+			return nil, fmt.Errorf("metric rule %q must have a name and a query", rule.Name)
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("metric rule %q is defined more than once", rule.Name)
+		}
+		seen[rule.Name] = true
+		if (rule.Above == nil) == (rule.Below == nil) {
+			return nil, fmt.Errorf("metric rule %q must have exactly one of above or below", rule.Name)
+		}
+		if rule.Step.Duration < 0 {
+			return nil, fmt.Errorf("metric rule %q must not have a negative step", rule.Name)
+		}
+		if len(rule.Level) > 0 {
+			if _, err := monitorapi.ConditionLevelFromString(rule.Level); err != nil {
+				return nil, fmt.Errorf("metric rule %q must have a level of Info, Warning, or Error, got %q", rule.Name, rule.Level)
+			}
+		}
+		if rule.FailAfter != nil && rule.FailAfter.Duration < 0 {
+			return nil, fmt.Errorf("metric rule %q must not have a negative failAfter", rule.Name)
+		}
+		if _, ok := rule.Locator[string(monitorapi.LocatorMetricRuleKey)]; ok {
+			return nil, fmt.Errorf("metric rule %q must not map the %s locator key, it is the name of the rule", rule.Name, monitorapi.LocatorMetricRuleKey)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,103 @@
+package monitortestframework
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestParseMetricRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			content: `
+rules:
+- name: etcd-slow-fsync
+  query: histogram_quantile(0.99, sum by (instance, le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket[5m])))
+  above: 0.5
+  step: 1m
+  level: Error
+  locator:
+    node: instance
+  failAfter: 10m
+- name: few-ready-routers
+  query: sum(kube_deployment_status_replicas_available{namespace="openshift-ingress"})
+  below: 2
+`,
+		},
+		{
+			name:        "missing query",
+			content:     `rules: [{name: a, above: 1}]`,
+			expectedErr: "must have a name and a query",
+		},
+		{
+			name:        "defined twice",
+			content:     `rules: [{name: a, query: up, above: 1}, {name: a, query: up, above: 1}]`,
+			expectedErr: "defined more than once",
+		},
+		{
+			name:        "no threshold",
+			content:     `rules: [{name: a, query: up}]`,
+			expectedErr: "exactly one of above or below",
+		},
+		{
+			name:        "both thresholds",
+			content:     `rules: [{name: a, query: up, above: 1, below: 0}]`,
+			expectedErr: "exactly one of above or below",
+		},
+		{
+			name:        "invalid level",
+			content:     `rules: [{name: a, query: up, above: 1, level: Critical}]`,
+			expectedErr: "must have a level of Info, Warning, or Error",
+		},
+		{
+			name:        "rule key mapped",
+			content:     `rules: [{name: a, query: up, above: 1, locator: {metric-rule: job}}]`,
+			expectedErr: "must not map the metric-rule locator key",
+		},
+		{
+			name:        "unknown field",
+			content:     `rules: [{name: a, query: up, above: 1, threshold: 2}]`,
+			expectedErr: "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMetricRules([]byte(tt.content))
+			switch {
+			case len(tt.expectedErr) == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case len(tt.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)):
+				t.Fatalf("expected an error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestMetricRuleDefaults(t *testing.T) {
+	rules, err := ParseMetricRules([]byte(`rules: [{name: a, query: up, below: 1}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := rules.Rules[0]
+	if rule.IntervalLevel() != monitorapi.Warning {
+		t.Errorf("expected Warning, got %v", rule.IntervalLevel())
+	}
+	if rule.SampleStep() != DefaultMetricRuleStep {
+		t.Errorf("expected %v, got %v", DefaultMetricRuleStep, rule.SampleStep())
+	}
+	if !rule.Crossed(0) || rule.Crossed(1) {
+		t.Errorf("expected only values below 1 to cross %s", rule.Threshold())
+	}
+	if step := (MetricRule{Step: metav1.Duration{Duration: time.Minute}}).SampleStep(); step != time.Minute {
+		t.Errorf("expected 1m, got %v", step)
+	}
+}
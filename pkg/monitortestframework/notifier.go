@@ -0,0 +1,136 @@
+package monitortestframework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// maxNotifiedDisruptionWindows is how many of the longest disruption windows a run summary holds.
+	maxNotifiedDisruptionWindows = 5
+	// maxNotifiedFailureOutput is how much of the output of a failed test a run summary holds.
+	maxNotifiedFailureOutput = 500
+
+	notificationTestName = `[Jira:"Test Framework"] monitor test notification should be delivered`
+)
+
+// Notifier is told how the run went once the registry has evaluated the tests, for runs nobody watches, like
+// scheduled soak clusters outside of CI.
+type Notifier interface {
+	Notify(ctx context.Context, summary RunSummary) error
+}
+
+// RunSummary is a concise account of a run, small enough for a chat message.
+type RunSummary struct {
+	// FailedTests are the tests that failed and never passed, ordered by name.  Flakes are left out.
+	FailedTests []FailedTest `json:"failedTests"`
+	// DisruptionWindows are the longest disruptions of the run, longest first.
+	DisruptionWindows []DisruptionWindow `json:"disruptionWindows"`
+	// Beginning and End are when the earliest interval began and the latest interval ended.
+	Beginning time.Time `json:"beginning"`
+	End       time.Time `json:"end"`
+}
+
+type FailedTest struct {
+	Name          string `json:"name"`
+	MonitorTest   string `json:"monitorTest,omitempty"`
+	JiraComponent string `json:"jiraComponent,omitempty"`
+	// Output is the beginning of the failure output.
+	Output string `json:"output"`
+}
+
+type DisruptionWindow struct {
+	// Backend is the backend disruption name, for instance kube-api-new-connections.
+	Backend string        `json:"backend"`
+	From    time.Time     `json:"from"`
+	To      time.Time     `json:"to"`
+	Length  time.Duration `json:"length"`
+}
+
+// BuildRunSummary summarizes the junits and intervals of a run.
+func BuildRunSummary(junits []*junitapi.JUnitTestCase, finalIntervals monitorapi.Intervals) RunSummary {
+	summary := RunSummary{
+		FailedTests:       []FailedTest{},
+		DisruptionWindows: []DisruptionWindow{},
+	}
+
+	passed := map[string]bool{}
+	for _, junit := range junits {
+		if junit.FailureOutput == nil && junit.SkipMessage == nil {
+			passed[junit.Name] = true
+		}
+	}
+	failed := map[string]bool{}
+	for _, junit := range junits {
+		if junit.FailureOutput == nil || passed[junit.Name] || failed[junit.Name] {
+			continue
+		}
+		failed[junit.Name] = true
+		test := FailedTest{Name: junit.Name, Output: junit.FailureOutput.Output}
+		if len(test.Output) > maxNotifiedFailureOutput {
+			test.Output = test.Output[:maxNotifiedFailureOutput] + "..."
+		}
+		if junit.Details != nil {
+			test.MonitorTest = junit.Details.MonitorTest
+			test.JiraComponent = junit.Details.JiraComponent
+		}
+		summary.FailedTests = append(summary.FailedTests, test)
+	}
+	sort.Slice(summary.FailedTests, func(i, j int) bool {
+		return summary.FailedTests[i].Name < summary.FailedTests[j].Name
+	})
+
+	for _, interval := range finalIntervals {
+		if !interval.From.IsZero() && (summary.Beginning.IsZero() || interval.From.Before(summary.Beginning)) {
+			summary.Beginning = interval.From
+		}
+		if interval.To.After(summary.End) {
+			summary.End = interval.To
+		}
+		if interval.Source != monitorapi.SourceDisruption || interval.Level != monitorapi.Error || interval.To.IsZero() {
+			continue
+		}
+		summary.DisruptionWindows = append(summary.DisruptionWindows, DisruptionWindow{
+			Backend: interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey],
+			From:    interval.From,
+			To:      interval.To,
+			Length:  interval.To.Sub(interval.From),
+		})
+	}
+	sort.SliceStable(summary.DisruptionWindows, func(i, j int) bool {
+		if summary.DisruptionWindows[i].Length != summary.DisruptionWindows[j].Length {
+			return summary.DisruptionWindows[i].Length > summary.DisruptionWindows[j].Length
+		}
+		return summary.DisruptionWindows[i].From.Before(summary.DisruptionWindows[j].From)
+	})
+	if len(summary.DisruptionWindows) > maxNotifiedDisruptionWindows {
+		summary.DisruptionWindows = summary.DisruptionWindows[:maxNotifiedDisruptionWindows]
+	}
+	return summary
+}
+
+// notify tells the notifier how the run went.  A notification that cannot be delivered flakes, because it says
+// nothing about the cluster.
+func (r *monitorTestRegistry) notify(ctx context.Context, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	if r.notifier == nil {
+		return nil
+	}
+	success := &junitapi.JUnitTestCase{Name: notificationTestName}
+	if err := r.notifier.Notify(ctx, BuildRunSummary(r.testOutcomes, finalIntervals)); err != nil {
+		return []*junitapi.JUnitTestCase{
+			{
+				Name: notificationTestName,
+				FailureOutput: &junitapi.FailureOutput{
+					Output: fmt.Sprintf("unable to deliver the notification: %v", err),
+				},
+			},
+			success,
+		}
+	}
+	return []*junitapi.JUnitTestCase{success}
+}
@@ -0,0 +1,117 @@
+package monitortestframework
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type recordingNotifier struct {
+	summaries []RunSummary
+	err       error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, summary RunSummary) error {
+	n.summaries = append(n.summaries, summary)
+	return n.err
+}
+
+func disruption(backend string, level monitorapi.IntervalLevel, from time.Time, length time.Duration) monitorapi.Interval {
+	return monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level: level,
+			Locator: monitorapi.Locator{
+				Type: monitorapi.LocatorTypeDisruption,
+				Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorBackendDisruptionNameKey: backend},
+			},
+		},
+		Source: monitorapi.SourceDisruption,
+		From:   from,
+		To:     from.Add(length),
+	}
+}
+
+func TestBuildRunSummary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	junits := []*junitapi.JUnitTestCase{
+		{Name: "passes"},
+		{Name: "flakes", FailureOutput: &junitapi.FailureOutput{Output: "failed"}},
+		{Name: "flakes"},
+		{Name: "fails", FailureOutput: &junitapi.FailureOutput{Output: strings.Repeat("x", maxNotifiedFailureOutput+1)}, Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "a", JiraComponent: "A"}},
+		{Name: "fails", FailureOutput: &junitapi.FailureOutput{Output: "again"}},
+		{Name: "also fails", FailureOutput: &junitapi.FailureOutput{Output: "failed"}},
+		{Name: "skipped", SkipMessage: &junitapi.SkipMessage{Message: "not applicable"}},
+	}
+	intervals := monitorapi.Intervals{}
+	for i := 0; i < maxNotifiedDisruptionWindows+1; i++ {
+		intervals = append(intervals, disruption("kube-api", monitorapi.Error, start.Add(time.Duration(i)*time.Minute), time.Duration(i+1)*time.Second))
+	}
+	intervals = append(intervals, disruption("kube-api", monitorapi.Info, start, time.Hour))
+
+	summary := BuildRunSummary(junits, intervals)
+
+	expectedTests := []FailedTest{
+		{Name: "also fails", Output: "failed"},
+		{Name: "fails", MonitorTest: "a", JiraComponent: "A", Output: strings.Repeat("x", maxNotifiedFailureOutput) + "..."},
+	}
+	if !reflect.DeepEqual(expectedTests, summary.FailedTests) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expectedTests, summary.FailedTests)
+	}
+	if len(summary.DisruptionWindows) != maxNotifiedDisruptionWindows {
+		t.Fatalf("expected %d disruption windows, got %d", maxNotifiedDisruptionWindows, len(summary.DisruptionWindows))
+	}
+	if longest := summary.DisruptionWindows[0]; longest.Length != time.Duration(maxNotifiedDisruptionWindows+1)*time.Second || longest.Backend != "kube-api" {
+		t.Errorf("expected the longest disruption first, got %#v", longest)
+	}
+	if !summary.Beginning.Equal(start) || !summary.End.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected the run to span %v to %v, got %v to %v", start, start.Add(time.Hour), summary.Beginning, summary.End)
+	}
+}
+
+func TestRegistryNotifies(t *testing.T) {
+	tests := []struct {
+		name           string
+		notifyErr      error
+		expectedFailed bool
+	}{
+		{name: "delivered"},
+		{name: "undelivered flakes", notifyErr: errors.New("connection refused"), expectedFailed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewMonitorTestRegistry()
+			registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{})
+			notifier := &recordingNotifier{err: tt.notifyErr}
+			registry.SetNotifier(notifier)
+
+			junits, err := registry.EvaluateTestsFromConstructedIntervals(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(notifier.summaries) != 1 {
+				t.Fatalf("expected one notification, got %d", len(notifier.summaries))
+			}
+
+			passed, failed := 0, 0
+			for _, junit := range junits {
+				if junit.Name != notificationTestName {
+					continue
+				}
+				if junit.FailureOutput != nil {
+					failed++
+				} else {
+					passed++
+				}
+			}
+			if passed != 1 || (failed == 1) != tt.expectedFailed {
+				t.Errorf("expected the notification to pass once and fail %v, got %d passes and %d failures", tt.expectedFailed, passed, failed)
+			}
+		})
+	}
+}
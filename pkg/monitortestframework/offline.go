@@ -0,0 +1,25 @@
+package monitortestframework
+
+// OfflineMonitorTest may be implemented by a MonitorTest that constructs intervals and evaluates tests from the
+// intervals and resources of a run alone, so it can be run on the artifacts of an earlier run, when StartCollection
+// and CollectData never ran.  In offline mode, monitor tests that do not implement it are reported as not supported.
+type OfflineMonitorTest interface {
+	// SupportsOffline is whether the monitor test, as configured, needs nothing from StartCollection and CollectData.
+	SupportsOffline() bool
+}
+
+const notSupportedOfflineReason = "the monitor test needs what it collects from the cluster during the run, it cannot be run on the artifacts of an earlier run"
+
+func (r *monitorTestRegistry) SetOffline(offline bool) {
+	r.offline = offline
+}
+
+// notSupportedOffline is whether the monitor test must not be called because the registry is offline and the monitor
+// test needs collection.
+func (r *monitorTestRegistry) notSupportedOffline(monitorTest *monitorTesttItem) bool {
+	if !r.offline {
+		return false
+	}
+	offline, ok := monitorTest.monitorTest.(OfflineMonitorTest)
+	return !ok || !offline.SupportsOffline()
+}
@@ -0,0 +1,84 @@
+package monitortestframework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// offlineWriter needs nothing from collection.
+type offlineWriter struct {
+	fileWriter
+}
+
+func (w *offlineWriter) SupportsOffline() bool {
+	return true
+}
+
+// collectingAnnotator reads what StartCollection set up, and panics when it never ran.
+type collectingAnnotator struct {
+	fileWriter
+	started *bool
+}
+
+func (w *collectingAnnotator) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	_ = *w.started
+	return nil, nil
+}
+
+func (w *collectingAnnotator) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	_ = *w.started
+	return nil, nil
+}
+
+func (w *collectingAnnotator) AnnotateInterval(interval monitorapi.Interval) map[monitorapi.AnnotationKey]string {
+	_ = *w.started
+	return map[monitorapi.AnnotationKey]string{testAnnotation: "collected"}
+}
+
+func TestRegistryOffline(t *testing.T) {
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("collecting", "Test Framework", &collectingAnnotator{})
+	registry.AddMonitorTestOrDie("offline", "Test Framework", &offlineWriter{})
+	registry.SetOffline(true)
+
+	now := time.Now()
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("worker-0")).
+			Message(monitorapi.NewMessage().HumanMessage("recorded")).
+			Build(now, now),
+	}
+	_, constructJunits, err := registry.ConstructComputedIntervals(context.Background(), intervals, monitorapi.ResourcesMap{}, now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotated := registry.AnnotateIntervals(intervals)
+	if _, ok := annotated[0].Message.Annotations[testAnnotation]; ok {
+		t.Errorf("expected annotators that need collection not to annotate offline, got %v", annotated[0].Message.Annotations)
+	}
+	evaluateJunits, err := registry.EvaluateTestsFromConstructedIntervals(context.Background(), annotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, junit := range append(constructJunits, evaluateJunits...) {
+		switch junit.Details.MonitorTest {
+		case "collecting":
+			if junit.SkipMessage == nil || !strings.Contains(junit.SkipMessage.Message, "earlier run") {
+				t.Errorf("expected %q to be skipped as not supported offline, got %#v", junit.Name, junit)
+			}
+		case "offline":
+			if junit.SkipMessage != nil || junit.FailureOutput != nil {
+				t.Errorf("expected %q to pass offline, got %#v", junit.Name, junit)
+			}
+		}
+	}
+	if len(constructJunits) != 2 || len(evaluateJunits) != 2 {
+		t.Errorf("expected a junit per monitor test and stage, got %d and %d", len(constructJunits), len(evaluateJunits))
+	}
+}
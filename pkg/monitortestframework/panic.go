@@ -26,6 +26,19 @@ func startCollectionWithPanicProtection(ctx context.Context, monitortest Monitor
 	return
 }
 
+func startSecondaryCollectionWithPanicProtection(ctx context.Context, monitortest SecondaryClusterMonitorTest, clusterName string, restConfig *rest.Config, recorder monitorapi.RecorderWriter) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("caught panic: %v", r)
+			logrus.Error("recovering from panic")
+			fmt.Print(debug.Stack())
+		}
+	}()
+
+	err = monitortest.StartSecondaryCollection(ctx, clusterName, restConfig, recorder)
+	return
+}
+
 func collectDataWithPanicProtection(ctx context.Context, monitortest MonitorTest, storageDir string, beginning, end time.Time) (intervals monitorapi.Intervals, junit []*junitapi.JUnitTestCase, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -39,7 +52,7 @@ func collectDataWithPanicProtection(ctx context.Context, monitortest MonitorTest
 	return
 }
 
-func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitortest MonitorTest, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (intervals monitorapi.Intervals, err error) {
+func backfillDataWithPanicProtection(ctx context.Context, monitortest BackfillMonitorTest, storageDir string, beginning, end time.Time) (intervals monitorapi.Intervals, junit []*junitapi.JUnitTestCase, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("caught panic: %v", r)
@@ -48,6 +61,23 @@ func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitort
 		}
 	}()
 
+	intervals, junit, err = monitortest.BackfillData(ctx, storageDir, beginning, end)
+	return
+}
+
+func constructComputedIntervalsWithPanicProtection(ctx context.Context, monitortest MonitorTest, startingIntervals monitorapi.Intervals, startingStore *lazyIntervalStore, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (intervals monitorapi.Intervals, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("caught panic: %v", r)
+			logrus.Error("recovering from panic")
+			fmt.Print(debug.Stack())
+		}
+	}()
+
+	if indexed, ok := monitortest.(IndexedIntervalsMonitorTest); ok {
+		intervals, err = indexed.ConstructComputedIntervalsFromStore(ctx, startingIntervals, startingStore.get(), recordedResources, beginning, end)
+		return
+	}
 	intervals, err = monitortest.ConstructComputedIntervals(ctx, startingIntervals, recordedResources, beginning, end)
 	return
 }
@@ -90,3 +120,16 @@ func cleanupWithPanicProtection(ctx context.Context, monitortest MonitorTest) (e
 	err = monitortest.Cleanup(ctx)
 	return
 }
+
+func writeRegistryOutputWithPanicProtection(ctx context.Context, output RegistryOutput, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("caught panic: %v", r)
+			logrus.Error("recovering from panic")
+			fmt.Print(debug.Stack())
+		}
+	}()
+
+	err = output.WriteRegistryOutput(ctx, storageDir, timeSuffix, finalIntervals, finalResourceState)
+	return
+}
@@ -0,0 +1,110 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// QuarantineList is a list of known failing tests, each linked to the bug tracking the failure.  Failures of
+// quarantined tests become flakes, so the job passes while the bug is worked, and the failure is still reported.
+type QuarantineList struct {
+	Entries []QuarantineEntry `json:"quarantine"`
+}
+
+type QuarantineEntry struct {
+	// TestNameRegex matches the names of the quarantined tests.
+	TestNameRegex string `json:"testNameRegex"`
+	// Bug links to the bug tracking the failure.  It is required so that nothing is quarantined without an owner.
+	Bug string `json:"bug"`
+
+	testNameRegex *regexp.Regexp
+}
+
+// LoadQuarantineList reads a quarantine list from a yaml or json file.
+func LoadQuarantineList(path string) (*QuarantineList, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := ParseQuarantineList(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quarantine list %q: %w", path, err)
+	}
+	return ret, nil
+}
+
+// ParseQuarantineList parses and validates a quarantine list.
+func ParseQuarantineList(content []byte) (*QuarantineList, error) {
+	ret := &QuarantineList{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	for i := range ret.Entries {
+		entry := &ret.Entries[i]
+		if len(entry.Bug) == 0 {
+			return nil, fmt.Errorf("quarantine entry %q must link a bug", entry.TestNameRegex)
+		}
+		testNameRegex, err := regexp.Compile(entry.TestNameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("quarantine entry %q: %w", entry.TestNameRegex, err)
+		}
+		entry.testNameRegex = testNameRegex
+	}
+	return ret, nil
+}
+
+// bugFor returns the bug of the first entry matching the test name, or empty if the test is not quarantined.
+func (q *QuarantineList) bugFor(testName string) string {
+	if q == nil {
+		return ""
+	}
+	for _, entry := range q.Entries {
+		if entry.testNameRegex.MatchString(testName) {
+			return entry.Bug
+		}
+	}
+	return ""
+}
+
+// Apply turns the failures of quarantined tests into flakes annotated with the bug.  Passing and skipped tests are
+// unchanged.
+func (q *QuarantineList) Apply(junits []*junitapi.JUnitTestCase) []*junitapi.JUnitTestCase {
+	if q == nil || len(q.Entries) == 0 {
+		return junits
+	}
+
+	ret := make([]*junitapi.JUnitTestCase, 0, len(junits))
+	for _, junit := range junits {
+		if junit == nil || junit.FailureOutput == nil {
+			ret = append(ret, junit)
+			continue
+		}
+		bug := q.bugFor(junit.Name)
+		if len(bug) == 0 {
+			ret = append(ret, junit)
+			continue
+		}
+
+		quarantined := *junit
+		failureOutput := *junit.FailureOutput
+		failureOutput.Output = fmt.Sprintf("quarantined, see %s\n%s", bug, failureOutput.Output)
+		quarantined.FailureOutput = &failureOutput
+		details := junitapi.JUnitTestCaseDetails{}
+		if junit.Details != nil {
+			details = *junit.Details
+		}
+		details.QuarantineBug = bug
+		quarantined.Details = &details
+
+		ret = append(ret, &quarantined, &junitapi.JUnitTestCase{
+			Name:    junit.Name,
+			Details: &details,
+		})
+	}
+	return ret
+}
@@ -0,0 +1,97 @@
+package monitortestframework
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestParseQuarantineList(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			content: `
+quarantine:
+- testNameRegex: '^\[sig-network\] pods should not fail'
+  bug: https://issues.redhat.com/browse/OCPBUGS-1
+`,
+		},
+		{
+			name: "missing bug",
+			content: `
+quarantine:
+- testNameRegex: 'anything'
+`,
+			expectedErr: "must link a bug",
+		},
+		{
+			name: "invalid regex",
+			content: `
+quarantine:
+- testNameRegex: '('
+  bug: https://issues.redhat.com/browse/OCPBUGS-1
+`,
+			expectedErr: "missing closing )",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuarantineList([]byte(tt.content))
+			switch {
+			case len(tt.expectedErr) == 0 && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case len(tt.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)):
+				t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestQuarantineList_Apply(t *testing.T) {
+	quarantineList, err := ParseQuarantineList([]byte(`
+quarantine:
+- testNameRegex: '^\[sig-network\] '
+  bug: https://issues.redhat.com/browse/OCPBUGS-1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	junits := quarantineList.Apply([]*junitapi.JUnitTestCase{
+		failingJunit("[sig-network] quarantined", junitapi.SeverityFail),
+		failingJunit("[sig-node] not quarantined", junitapi.SeverityFail),
+		{Name: "[sig-network] passing"},
+	})
+
+	passed, failed := countOutcomes(junits)
+	if failed["[sig-network] quarantined"] != 1 || passed["[sig-network] quarantined"] != 1 {
+		t.Errorf("expected the quarantined failure to flake, got passed=%v failed=%v", passed, failed)
+	}
+	if failed["[sig-node] not quarantined"] != 1 || passed["[sig-node] not quarantined"] != 0 {
+		t.Errorf("expected the other failure to still fail, got passed=%v failed=%v", passed, failed)
+	}
+	if passed["[sig-network] passing"] != 1 {
+		t.Errorf("expected the passing test to be unchanged, got passed=%v failed=%v", passed, failed)
+	}
+
+	quarantined := junits[0]
+	if quarantined.Details.QuarantineBug != "https://issues.redhat.com/browse/OCPBUGS-1" {
+		t.Errorf("expected the bug in the details, got %#v", quarantined.Details)
+	}
+	if !strings.HasPrefix(quarantined.FailureOutput.Output, "quarantined, see https://issues.redhat.com/browse/OCPBUGS-1\n") {
+		t.Errorf("expected the bug in the failure output, got %q", quarantined.FailureOutput.Output)
+	}
+}
+
+func TestQuarantineList_ApplyNil(t *testing.T) {
+	var quarantineList *QuarantineList
+	junits := []*junitapi.JUnitTestCase{failingJunit("[sig-network] failing", junitapi.SeverityFail)}
+	if actual := quarantineList.Apply(junits); len(actual) != 1 || actual[0].FailureOutput == nil {
+		t.Errorf("expected nothing to change, got %#v", actual)
+	}
+}
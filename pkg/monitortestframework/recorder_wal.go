@@ -0,0 +1,58 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+// recoverFromWAL returns the intervals in the recorder write-ahead log that the recorder collection was started with
+// does not hold.  Those were recorded by a monitor process that crashed before this one started, and the run is
+// considered to have begun with the earliest of them.
+func (r *monitorTestRegistry) recoverFromWAL() (monitorapi.Intervals, error) {
+	if len(r.recorderWALFile) == 0 {
+		return nil, nil
+	}
+	walIntervals, err := monitorserialization.IntervalsFromWALFile(r.recorderWALFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the recorder write-ahead log %q: %w", r.recorderWALFile, err)
+	}
+
+	// the log holds intervals more than once when the recorder did, so count them.
+	inMemory := map[string]int{}
+	if reader, ok := r.recorder.(monitorapi.RecorderReader); ok {
+		for _, interval := range reader.Intervals(time.Time{}, time.Time{}) {
+			inMemory[walIntervalKey(interval)]++
+		}
+	}
+
+	recovered := monitorapi.Intervals{}
+	for _, interval := range walIntervals {
+		key := walIntervalKey(interval)
+		if inMemory[key] > 0 {
+			inMemory[key]--
+			continue
+		}
+		recovered = append(recovered, interval)
+		if !interval.From.IsZero() && (r.recoveredBeginning.IsZero() || interval.From.Before(r.recoveredBeginning)) {
+			r.recoveredBeginning = interval.From
+		}
+	}
+	if len(recovered) > 0 {
+		logrus.Infof("Recovered %d intervals from the recorder write-ahead log %q, the earliest from %v", len(recovered), r.recorderWALFile, r.recoveredBeginning)
+	}
+	return recovered, nil
+}
+
+// walIntervalKey identifies an interval as the write-ahead log stores it, which drops the sub-second part of times.
+func walIntervalKey(interval monitorapi.Interval) string {
+	key, err := monitorserialization.IntervalToOneLineJSON(interval)
+	if err != nil {
+		return interval.String()
+	}
+	return string(key)
+}
@@ -0,0 +1,76 @@
+package monitortestframework
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+)
+
+// intervalHolder holds intervals the way the monitor's recorder does.
+type intervalHolder struct {
+	monitorapi.RecorderWriter
+	intervals monitorapi.Intervals
+}
+
+func (h *intervalHolder) Intervals(from, to time.Time) monitorapi.Intervals {
+	return h.intervals
+}
+
+func (h *intervalHolder) CurrentResourceState() monitorapi.ResourcesMap {
+	return monitorapi.ResourcesMap{}
+}
+
+func TestRegistryRecoverFromWAL(t *testing.T) {
+	ctx := context.Background()
+	beginning := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	interval := func(message string, from time.Time) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().HumanMessage(message)).
+			Build(from, from)
+	}
+	beforeCrash := interval("before the crash", beginning.Add(-time.Hour))
+	afterRestart := interval("after the restart", beginning.Add(time.Minute))
+
+	walFile := filepath.Join(t.TempDir(), "recorder-wal.jsonl")
+	wal := ""
+	for _, entry := range []monitorserialization.WALEntry{
+		monitorserialization.NewWALEntry(monitorserialization.WALOpen, 0, nil),
+		monitorserialization.NewWALEntry(monitorserialization.WALAdd, 0, &beforeCrash),
+		monitorserialization.NewWALEntry(monitorserialization.WALOpen, 0, nil),
+		monitorserialization.NewWALEntry(monitorserialization.WALAdd, 0, &afterRestart),
+	} {
+		line, err := monitorserialization.WALEntryToOneLineJSON(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wal += string(line) + "\n"
+	}
+	if err := os.WriteFile(walFile, []byte(wal), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{})
+	registry.SetRecorderWAL(walFile)
+	recorder := &intervalHolder{intervals: monitorapi.Intervals{afterRestart}}
+	if _, err := registry.StartCollection(ctx, nil, recorder); err != nil {
+		t.Fatal(err)
+	}
+
+	intervals, _, err := registry.CollectData(ctx, t.TempDir(), beginning, beginning.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 1 || intervals[0].Message.HumanMessage != "before the crash" {
+		t.Errorf("expected only the interval the recorder does not hold, got %v", intervals)
+	}
+	if actual := registry.BackfilledBeginning(beginning); !actual.Equal(beginning.Add(-time.Hour)) {
+		t.Errorf("expected the run to begin with the recovered interval, got %v", actual)
+	}
+}
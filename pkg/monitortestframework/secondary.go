@@ -0,0 +1,123 @@
+package monitortestframework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// SecondaryClusters are the clusters other than the cluster under test that monitor tests may collect from, by name.
+// For instance the management cluster of a hosted cluster, or the cluster running an external etcd.
+type SecondaryClusters map[string]*rest.Config
+
+// LoadSecondaryClusters reads the kubeconfig file of every named secondary cluster.
+func LoadSecondaryClusters(kubeconfigs map[string]string) (SecondaryClusters, error) {
+	ret := SecondaryClusters{}
+	for name, kubeconfig := range kubeconfigs {
+		if len(name) == 0 {
+			return nil, fmt.Errorf("the secondary cluster with kubeconfig %q has no name", kubeconfig)
+		}
+		restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the kubeconfig of secondary cluster %q: %w", name, err)
+		}
+		ret[name] = restConfig
+	}
+	return ret, nil
+}
+
+// SecondaryClusterMonitorTest may be implemented by a MonitorTest to also collect data from secondary clusters.
+// Intervals the monitor test writes to the recorder it gets for a secondary cluster have the cluster in their
+// locator.  Intervals returned from CollectData are not labeled by the registry, use WithCluster for those.
+type SecondaryClusterMonitorTest interface {
+	// SecondaryClusterNames returns the names of the secondary clusters the monitor test collects from.  Clusters
+	// that were not configured for the run are not started.
+	SecondaryClusterNames() []string
+
+	// StartSecondaryCollection is called after StartCollection succeeded, once for every configured secondary cluster
+	// the monitor test named.  Errors are handled like those of StartCollection.
+	StartSecondaryCollection(ctx context.Context, clusterName string, restConfig *rest.Config, recorder monitorapi.RecorderWriter) error
+}
+
+// WithCluster returns the intervals with the secondary cluster they were observed on in their locator.
+func WithCluster(clusterName string, intervals monitorapi.Intervals) monitorapi.Intervals {
+	ret := make(monitorapi.Intervals, 0, len(intervals))
+	for _, interval := range intervals {
+		ret = append(ret, withCluster(clusterName, interval))
+	}
+	return ret
+}
+
+func withCluster(clusterName string, interval monitorapi.Interval) monitorapi.Interval {
+	keys := map[monitorapi.LocatorKey]string{monitorapi.LocatorClusterKey: clusterName}
+	for key, value := range interval.Locator.Keys {
+		keys[key] = value
+	}
+	interval.Locator.Keys = keys
+	return interval
+}
+
+// startSecondaryCollection starts the monitor test on every secondary cluster it named that is configured.
+func (r *monitorTestRegistry) startSecondaryCollection(ctx context.Context, monitorTest MonitorTest, recorder monitorapi.RecorderWriter) error {
+	secondary, ok := monitorTest.(SecondaryClusterMonitorTest)
+	if !ok {
+		return nil
+	}
+	names := secondary.SecondaryClusterNames()
+	sort.Strings(names)
+	for _, name := range names {
+		restConfig, ok := r.secondaryClusters[name]
+		if !ok {
+			logrus.Infof("  Secondary cluster %q is not configured, not collecting from it", name)
+			continue
+		}
+		if err := startSecondaryCollectionWithPanicProtection(ctx, secondary, name, rest.CopyConfig(restConfig), &clusterRecorder{RecorderWriter: recorder, clusterName: name}); err != nil {
+			return fmt.Errorf("secondary cluster %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// clusterRecorder adds the secondary cluster to the locator of everything recorded, and keeps the resources of the
+// secondary cluster apart from those of the cluster under test.
+type clusterRecorder struct {
+	monitorapi.RecorderWriter
+	clusterName string
+}
+
+func (r *clusterRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	r.RecorderWriter.RecordResource(r.clusterName+"/"+resourceType, obj)
+}
+
+func (r *clusterRecorder) Record(conditions ...monitorapi.Condition) {
+	r.RecorderWriter.Record(r.withCluster(conditions)...)
+}
+
+func (r *clusterRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	r.RecorderWriter.RecordAt(t, r.withCluster(conditions)...)
+}
+
+func (r *clusterRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	r.RecorderWriter.AddIntervals(WithCluster(r.clusterName, eventIntervals)...)
+}
+
+func (r *clusterRecorder) StartInterval(interval monitorapi.Interval) int {
+	return r.RecorderWriter.StartInterval(withCluster(r.clusterName, interval))
+}
+
+func (r *clusterRecorder) withCluster(conditions []monitorapi.Condition) []monitorapi.Condition {
+	ret := make([]monitorapi.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		condition.Locator = withCluster(r.clusterName, monitorapi.Interval{Condition: condition}).Locator
+		ret = append(ret, condition)
+	}
+	return ret
+}
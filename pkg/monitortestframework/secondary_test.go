@@ -0,0 +1,167 @@
+package monitortestframework
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// intervalRecorder keeps what is recorded to it.
+type intervalRecorder struct {
+	resourceTypes []string
+	intervals     monitorapi.Intervals
+}
+
+func (r *intervalRecorder) RecordResource(resourceType string, obj runtime.Object) {
+	r.resourceTypes = append(r.resourceTypes, resourceType)
+}
+
+func (r *intervalRecorder) Record(conditions ...monitorapi.Condition) {
+	r.RecordAt(time.Now(), conditions...)
+}
+
+func (r *intervalRecorder) RecordAt(t time.Time, conditions ...monitorapi.Condition) {
+	for _, condition := range conditions {
+		r.intervals = append(r.intervals, monitorapi.Interval{Condition: condition, From: t, To: t})
+	}
+}
+
+func (r *intervalRecorder) AddIntervals(eventIntervals ...monitorapi.Interval) {
+	r.intervals = append(r.intervals, eventIntervals...)
+}
+
+func (r *intervalRecorder) StartInterval(interval monitorapi.Interval) int {
+	r.intervals = append(r.intervals, interval)
+	return len(r.intervals) - 1
+}
+
+func (r *intervalRecorder) EndInterval(startedInterval int, t time.Time) *monitorapi.Interval {
+	r.intervals[startedInterval].To = t
+	return &r.intervals[startedInterval]
+}
+
+// secondaryCollector records an interval on every cluster it is started on.
+type secondaryCollector struct {
+	fileWriter
+	names   []string
+	started map[string]string
+}
+
+func (c *secondaryCollector) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	c.started = map[string]string{"": adminRESTConfig.Host}
+	recorder.AddIntervals(nodeInterval())
+	return nil
+}
+
+func (c *secondaryCollector) SecondaryClusterNames() []string {
+	return c.names
+}
+
+func (c *secondaryCollector) StartSecondaryCollection(ctx context.Context, clusterName string, restConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	c.started[clusterName] = restConfig.Host
+	recorder.AddIntervals(nodeInterval())
+	recorder.StartInterval(nodeInterval())
+	recorder.RecordResource("pods", nil)
+	return nil
+}
+
+func nodeInterval() monitorapi.Interval {
+	now := time.Now()
+	return monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+		Message(monitorapi.NewMessage().HumanMessage("observed")).
+		Build(now, now)
+}
+
+func TestStartSecondaryCollection(t *testing.T) {
+	collector := &secondaryCollector{names: []string{"management", "external-etcd"}}
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("collector", "Test Framework", collector)
+	registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{})
+	registry.SetSecondaryClusters(SecondaryClusters{
+		"management": &rest.Config{Host: "https://management.example.com"},
+		"unused":     &rest.Config{Host: "https://unused.example.com"},
+	})
+	registry, err := registry.GetRegistryFor("collector", "writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := &intervalRecorder{}
+	if _, err := registry.StartCollection(context.Background(), &rest.Config{Host: "https://primary.example.com"}, recorder); err != nil {
+		t.Fatal(err)
+	}
+
+	// external-etcd was not configured, and unused was not asked for.
+	expected := map[string]string{"": "https://primary.example.com", "management": "https://management.example.com"}
+	if len(collector.started) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, collector.started)
+	}
+	for name, host := range expected {
+		if collector.started[name] != host {
+			t.Errorf("expected %q to be started on %v, got %v", name, host, collector.started[name])
+		}
+	}
+
+	if len(recorder.intervals) != 3 {
+		t.Fatalf("expected three intervals, got %v", recorder.intervals)
+	}
+	for i, curr := range recorder.intervals {
+		cluster, ok := curr.Locator.Keys[monitorapi.LocatorClusterKey]
+		switch {
+		case i == 0 && ok:
+			t.Errorf("expected no cluster on the interval of the cluster under test, got %v", curr.Locator)
+		case i > 0 && cluster != "management":
+			t.Errorf("expected the management cluster on %v", curr.Locator)
+		}
+		if curr.Locator.Keys[monitorapi.LocatorNodeKey] != "master-0" {
+			t.Errorf("expected the node to be kept, got %v", curr.Locator)
+		}
+	}
+	if len(recorder.resourceTypes) != 1 || recorder.resourceTypes[0] != "management/pods" {
+		t.Errorf("expected the resources of the management cluster apart, got %v", recorder.resourceTypes)
+	}
+}
+
+func TestLoadSecondaryClusters(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	content := `apiVersion: v1
+kind: Config
+clusters:
+- name: management
+  cluster:
+    server: https://management.example.com:6443
+contexts:
+- name: management
+  context:
+    cluster: management
+    user: admin
+current-context: management
+users:
+- name: admin
+  user:
+    token: secret
+`
+	if err := os.WriteFile(kubeconfig, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := LoadSecondaryClusters(map[string]string{"management": kubeconfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host := clusters["management"].Host; host != "https://management.example.com:6443" {
+		t.Errorf("expected the server of the kubeconfig, got %v", host)
+	}
+
+	if _, err := LoadSecondaryClusters(map[string]string{"missing": filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Errorf("expected an error for a missing kubeconfig")
+	}
+}
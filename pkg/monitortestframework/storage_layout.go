@@ -0,0 +1,109 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StorageLayoutKind decides where in the storage directory each monitor test writes its content.
+type StorageLayoutKind string
+
+const (
+	// FlatStorageLayout writes every monitor test to the root of the storage directory.
+	FlatStorageLayout StorageLayoutKind = "Flat"
+
+	// PerMonitorTestStorageLayout writes every monitor test to a subdirectory named after the monitor test.
+	PerMonitorTestStorageLayout StorageLayoutKind = "PerMonitorTest"
+
+	// PerPhaseStorageLayout writes every monitor test to a subdirectory named after the phase, for instance
+	// "pre-upgrade", so that the content of every phase of a job is kept apart.
+	PerPhaseStorageLayout StorageLayoutKind = "PerPhase"
+)
+
+// StorageLayout is selected when the registry is constructed.  Monitor tests are handed the directory the layout
+// picks for them, so artifacts can be reorganized without changing any monitor test.
+type StorageLayout struct {
+	Kind StorageLayoutKind
+	// Phase names the subdirectory for PerPhaseStorageLayout.
+	Phase string
+}
+
+// DefaultStorageLayout keeps the layout CI tooling expects.
+var DefaultStorageLayout = StorageLayout{Kind: FlatStorageLayout}
+
+// NewStorageLayout validates the layout.  An empty kind is the flat layout.
+func NewStorageLayout(kind StorageLayoutKind, phase string) (StorageLayout, error) {
+	ret := StorageLayout{Kind: kind, Phase: phase}
+	switch kind {
+	case "":
+		ret.Kind = FlatStorageLayout
+	case FlatStorageLayout, PerMonitorTestStorageLayout:
+	case PerPhaseStorageLayout:
+		if err := validatePathElement(phase); err != nil {
+			return StorageLayout{}, fmt.Errorf("invalid phase for storage layout %q: %w", kind, err)
+		}
+	default:
+		return StorageLayout{}, fmt.Errorf("unknown storage layout %q, must be one of %v, %v, or %v",
+			kind, FlatStorageLayout, PerMonitorTestStorageLayout, PerPhaseStorageLayout)
+	}
+	return ret, nil
+}
+
+// DirFor returns the directory the named monitor test or registry output writes to.
+func (l StorageLayout) DirFor(storageDir, name string) string {
+	switch l.Kind {
+	case PerMonitorTestStorageLayout:
+		return filepath.Join(storageDir, name)
+	case PerPhaseStorageLayout:
+		return filepath.Join(storageDir, l.Phase)
+	default:
+		return storageDir
+	}
+}
+
+// prepareDir creates the directory for the named monitor test or registry output, so that writers that predate
+// layouts and assume their directory exists keep working.
+func (l StorageLayout) prepareDir(storageDir, name string) (string, error) {
+	ret := l.DirFor(storageDir, name)
+	if err := os.MkdirAll(ret, 0755); err != nil {
+		return "", fmt.Errorf("unable to create storage directory for %v: %w", name, err)
+	}
+	return ret, nil
+}
+
+// removeIfEmpty removes a directory prepared for a writer that wrote nothing.  The storage directory itself is kept.
+func removeIfEmpty(storageDir, dir string) {
+	if filepath.Clean(dir) == filepath.Clean(storageDir) {
+		return
+	}
+	// only succeeds on empty directories, which is exactly what we want.
+	_ = os.Remove(dir)
+}
+
+// StoragePath returns a writable path under the storage directory a monitor test was handed, creating any missing
+// directories.  Monitor tests use it instead of joining paths themselves so that their files stay inside the
+// directory the layout picked for them.
+func StoragePath(storageDir string, elem ...string) (string, error) {
+	ret := filepath.Join(append([]string{storageDir}, elem...)...)
+	if rel, err := filepath.Rel(storageDir, ret); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%q is outside of the storage directory %q", filepath.Join(elem...), storageDir)
+	}
+	if err := os.MkdirAll(filepath.Dir(ret), 0755); err != nil {
+		return "", err
+	}
+	return ret, nil
+}
+
+func validatePathElement(name string) error {
+	switch {
+	case len(name) == 0:
+		return fmt.Errorf("must not be empty")
+	case name == "." || name == "..":
+		return fmt.Errorf("%q is not a directory name", name)
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("%q must not contain a path separator", name)
+	}
+	return nil
+}
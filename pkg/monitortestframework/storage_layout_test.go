@@ -0,0 +1,108 @@
+package monitortestframework
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewStorageLayout(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      StorageLayoutKind
+		phase     string
+		expectErr bool
+	}{
+		{name: "empty is flat", kind: ""},
+		{name: "flat", kind: FlatStorageLayout},
+		{name: "per monitor test", kind: PerMonitorTestStorageLayout},
+		{name: "per phase", kind: PerPhaseStorageLayout, phase: "pre-upgrade"},
+		{name: "per phase without a phase", kind: PerPhaseStorageLayout, expectErr: true},
+		{name: "per phase with a path", kind: PerPhaseStorageLayout, phase: "../elsewhere", expectErr: true},
+		{name: "unknown", kind: "Nested", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewStorageLayout(tt.kind, tt.phase)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.expectErr, err)
+			}
+			if err == nil && len(layout.Kind) == 0 {
+				t.Errorf("expected a layout kind")
+			}
+		})
+	}
+}
+
+func TestStoragePath(t *testing.T) {
+	storageDir := t.TempDir()
+	path, err := StoragePath(storageDir, "nested", "dir", "file.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatalf("expected a writable path, got %v", err)
+	}
+	if _, err := StoragePath(storageDir, "..", "escaped.json"); err == nil {
+		t.Errorf("expected paths outside of the storage directory to be rejected")
+	}
+}
+
+func TestWriteContentToStorageLayouts(t *testing.T) {
+	tests := []struct {
+		name          string
+		layout        StorageLayout
+		expectedFiles []string
+		expectedGone  []string
+	}{
+		{
+			name:          "flat",
+			layout:        DefaultStorageLayout,
+			expectedFiles: []string{"summary.json"},
+		},
+		{
+			name:          "per monitor test",
+			layout:        StorageLayout{Kind: PerMonitorTestStorageLayout},
+			expectedFiles: []string{"writer/summary.json"},
+			expectedGone:  []string{"silent"},
+		},
+		{
+			name:          "per phase",
+			layout:        StorageLayout{Kind: PerPhaseStorageLayout, Phase: "post-upgrade"},
+			expectedFiles: []string{"post-upgrade/summary.json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageDir := t.TempDir()
+			registry := NewMonitorTestRegistry()
+			registry.SetStorageLayout(tt.layout)
+			registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{files: map[string]string{"summary.json": "{}"}})
+			registry.AddMonitorTestOrDie("silent", "Test Framework", &fileWriter{})
+			if _, err := registry.WriteContentToStorage(context.Background(), storageDir, "", nil, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range tt.expectedFiles {
+				if _, err := os.Stat(filepath.Join(storageDir, name)); err != nil {
+					t.Errorf("expected %v to be written: %v", name, err)
+				}
+			}
+			for _, name := range tt.expectedGone {
+				if _, err := os.Stat(filepath.Join(storageDir, name)); !os.IsNotExist(err) {
+					t.Errorf("expected %v to be removed because nothing was written to it, got %v", name, err)
+				}
+			}
+			indexed := map[string]bool{}
+			for _, entry := range readArtifactIndex(t, storageDir) {
+				indexed[entry.Name] = true
+			}
+			for _, name := range tt.expectedFiles {
+				if !indexed[name] {
+					t.Errorf("expected %v in the artifact index, got %v", name, indexed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package monitortestframework
+
+import (
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// TestOutcomeRegistryOutput may be implemented by a RegistryOutput that reports on the outcome of the monitor tests.
+// Before the output is written, the registry hands it every junit its stages have returned, including the junits of
+// monitor tests writing to storage.
+type TestOutcomeRegistryOutput interface {
+	SetTestOutcomes(junits []*junitapi.JUnitTestCase)
+}
+
+// recordTestOutcomes remembers the junits a stage returns, so they can be handed to registry outputs.
+func (r *monitorTestRegistry) recordTestOutcomes(junits []*junitapi.JUnitTestCase) []*junitapi.JUnitTestCase {
+	r.testOutcomes = append(r.testOutcomes, junits...)
+	return junits
+}
+
+func setTestOutcomes(output RegistryOutput, junits []*junitapi.JUnitTestCase) {
+	if outcomeOutput, ok := output.(TestOutcomeRegistryOutput); ok {
+		outcomeOutput.SetTestOutcomes(junits)
+	}
+}
@@ -0,0 +1,77 @@
+package monitortestframework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type outcomeOutput struct {
+	junits []*junitapi.JUnitTestCase
+}
+
+func (o *outcomeOutput) SetTestOutcomes(junits []*junitapi.JUnitTestCase) {
+	o.junits = junits
+}
+
+func (o *outcomeOutput) WriteRegistryOutput(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func TestRegistryOutputTestOutcomes(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMonitorTestRegistry()
+	registry.AddMonitorTestOrDie("writer", "Test Framework", &fileWriter{})
+	output := &outcomeOutput{}
+	registry.AddRegistryOutputOrDie("outcomes", "Test Framework", output)
+
+	if _, err := registry.EvaluateTestsFromConstructedIntervals(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.WriteContentToStorage(ctx, t.TempDir(), "", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, junit := range output.junits {
+		names = append(names, junit.Name)
+	}
+	expected := []string{
+		`[Jira:"Test Framework"] monitor test writer test evaluation`,
+		`[Jira:"Test Framework"] monitor test writer writing to storage`,
+	}
+	if strings.Join(names, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the registry output to see\n%v\ngot\n%v", strings.Join(expected, "\n"), strings.Join(names, "\n"))
+	}
+}
+
+func TestRegistryOutputsWrittenInNameOrder(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMonitorTestRegistry()
+	for _, name := range []string{"zeta", "alpha", "mu", "beta"} {
+		registry.AddRegistryOutputOrDie(name, "Test Framework", &outcomeOutput{})
+	}
+
+	junits, err := registry.WriteContentToStorage(ctx, t.TempDir(), "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, junit := range junits {
+		if strings.Contains(junit.Name, "registry output") {
+			names = append(names, junit.Name)
+		}
+	}
+	expected := []string{
+		`[Jira:"Test Framework"] monitor test registry output alpha writing to storage`,
+		`[Jira:"Test Framework"] monitor test registry output beta writing to storage`,
+		`[Jira:"Test Framework"] monitor test registry output mu writing to storage`,
+		`[Jira:"Test Framework"] monitor test registry output zeta writing to storage`,
+	}
+	if strings.Join(names, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the registry outputs in name order\n%v\ngot\n%v", strings.Join(expected, "\n"), strings.Join(names, "\n"))
+	}
+}
@@ -0,0 +1,79 @@
+package monitortestframework
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// tracerName identifies the spans produced by the registry.  The global tracer provider is a no-op unless the
+// process configures one, so tracing costs nothing by default.
+const tracerName = "github.com/openshift/origin/pkg/monitortestframework"
+
+// startMonitorTestSpan starts a span for a single stage of a single monitor test.
+func startMonitorTestSpan(ctx context.Context, stage string, monitorTest *monitorTesttItem) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "monitor test "+stage,
+		trace.WithAttributes(
+			attribute.String("monitortest.name", monitorTest.name),
+			attribute.String("monitortest.jira_component", monitorTest.jiraComponent),
+			attribute.String("monitortest.stage", stage),
+		),
+	)
+}
+
+// endMonitorTestSpan ends the span, recording the outcome the same way the junit does.
+func endMonitorTestSpan(span trace.Span, err error) {
+	defer span.End()
+	if err == nil {
+		return
+	}
+
+	var nsErr *NotSupportedError
+	var flakeErr *FlakeError
+	switch {
+	case errors.As(err, &nsErr):
+		span.SetAttributes(attribute.String("monitortest.result", "skipped"))
+	case errors.As(err, &flakeErr):
+		span.SetAttributes(attribute.String("monitortest.result", "flaked"))
+		span.RecordError(err)
+	default:
+		span.SetAttributes(attribute.String("monitortest.result", "failed"))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// recordIntervalSpans records every interval as a child span of ctx, using the interval bounds as the span bounds.
+// Intervals that have not ended are recorded as instants.
+func recordIntervalSpans(ctx context.Context, intervals monitorapi.Intervals) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return
+	}
+	tracer := otel.Tracer(tracerName)
+	for _, interval := range intervals {
+		to := interval.To
+		if to.IsZero() || to.Before(interval.From) {
+			to = interval.From
+		}
+		_, span := tracer.Start(ctx, string(interval.Source),
+			trace.WithTimestamp(interval.From),
+			trace.WithAttributes(
+				attribute.String("interval.source", string(interval.Source)),
+				attribute.String("interval.level", interval.Level.String()),
+				attribute.String("interval.locator", interval.Locator.OldLocator()),
+				attribute.String("interval.reason", string(interval.Message.Reason)),
+				attribute.String("interval.message", interval.Message.HumanMessage),
+			),
+		)
+		if interval.Level == monitorapi.Error {
+			span.SetStatus(codes.Error, interval.Message.HumanMessage)
+		}
+		span.End(trace.WithTimestamp(to))
+	}
+}
@@ -0,0 +1,64 @@
+package monitortestframework
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type memoryExporter struct {
+	lock  sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *memoryExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func TestMonitorTestSpans(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracerProvider)
+	defer otel.SetTracerProvider(previous)
+
+	interval := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Error).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().HumanMessage("unreachable")).
+		Build(time.Unix(100, 0), time.Unix(200, 0))
+
+	ctx, span := startMonitorTestSpan(context.Background(), "interval construction", &monitorTesttItem{name: "node-lifecycle", jiraComponent: "Node"})
+	recordIntervalSpans(ctx, monitorapi.Intervals{interval})
+	endMonitorTestSpan(span, &NotSupportedError{Reason: "skip"})
+
+	if len(exporter.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(exporter.spans))
+	}
+	intervalSpan, stageSpan := exporter.spans[0], exporter.spans[1]
+	if intervalSpan.Parent().SpanID() != stageSpan.SpanContext().SpanID() {
+		t.Errorf("expected interval span to be a child of the stage span")
+	}
+	if !intervalSpan.StartTime().Equal(interval.From) || !intervalSpan.EndTime().Equal(interval.To) {
+		t.Errorf("expected interval span to match interval bounds, got %v-%v", intervalSpan.StartTime(), intervalSpan.EndTime())
+	}
+	if intervalSpan.Status().Code != codes.Error {
+		t.Errorf("expected error intervals to have an error status")
+	}
+	if stageSpan.Status().Code == codes.Error {
+		t.Errorf("expected not supported errors to not be span errors")
+	}
+}
@@ -0,0 +1,78 @@
+package monitortestframework
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// TrackedResources lists resources beyond the fixed set the monitor records, for instance the custom resources of a
+// layered product's operator, so invariants can read them from the recorded resources without changing the monitor.
+type TrackedResources struct {
+	Resources []TrackedResource `json:"resources"`
+}
+
+type TrackedResource struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// Namespace limits tracking to one namespace.  If empty, the resource is tracked in every namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// PruneFields are dotted paths of fields removed before a resource is recorded, for instance status.history, to
+	// keep large or noisy fields out of the recorded resources.  metadata.managedFields is always removed.
+	PruneFields []string `json:"pruneFields,omitempty"`
+}
+
+// GroupVersionResource returns the resource that is tracked.
+func (t TrackedResource) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: t.Group, Version: t.Version, Resource: t.Resource}
+}
+
+// ResourceType returns the type the resource is recorded as, resource.group, or resource for the core group.
+func (t TrackedResource) ResourceType() string {
+	if len(t.Group) == 0 {
+		return t.Resource
+	}
+	return t.Resource + "." + t.Group
+}
+
+// LoadTrackedResources reads the tracked resources from a yaml or json file.
+func LoadTrackedResources(path string) (*TrackedResources, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := ParseTrackedResources(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracked resources %q: %w", path, err)
+	}
+	return ret, nil
+}
+
+// ParseTrackedResources parses and validates the tracked resources.
+func ParseTrackedResources(content []byte) (*TrackedResources, error) {
+	ret := &TrackedResources{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, resource := range ret.Resources {
+		if len(resource.Version) == 0 || len(resource.Resource) == 0 {
+			return nil, fmt.Errorf("tracked resource %q must have a version and a resource", resource.ResourceType())
+		}
+		key := resource.GroupVersionResource().String() + "/" + resource.Namespace
+		if seen[key] {
+			return nil, fmt.Errorf("%q is tracked more than once", resource.GroupVersionResource())
+		}
+		seen[key] = true
+		for _, field := range resource.PruneFields {
+			if len(field) == 0 || strings.HasPrefix(field, ".") || strings.HasSuffix(field, ".") || strings.Contains(field, "..") {
+				return nil, fmt.Errorf("tracked resource %q has invalid prune field %q", resource.ResourceType(), field)
+			}
+		}
+	}
+	return ret, nil
+}
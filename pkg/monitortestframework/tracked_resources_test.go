@@ -0,0 +1,82 @@
+package monitortestframework
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTrackedResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			content: `
+resources:
+- group: operators.coreos.com
+  version: v1alpha1
+  resource: subscriptions
+  pruneFields:
+  - status.catalogHealth
+- version: v1
+  resource: configmaps
+  namespace: openshift-gitops
+`,
+		},
+		{
+			name: "missing version",
+			content: `
+resources:
+- group: operators.coreos.com
+  resource: subscriptions
+`,
+			expectedErr: "must have a version and a resource",
+		},
+		{
+			name: "tracked twice",
+			content: `
+resources:
+- {group: operators.coreos.com, version: v1alpha1, resource: subscriptions}
+- {group: operators.coreos.com, version: v1alpha1, resource: subscriptions}
+`,
+			expectedErr: "tracked more than once",
+		},
+		{
+			name: "invalid prune field",
+			content: `
+resources:
+- {group: operators.coreos.com, version: v1alpha1, resource: subscriptions, pruneFields: [status..conditions]}
+`,
+			expectedErr: "invalid prune field",
+		},
+		{
+			name:        "unknown field",
+			content:     `resource: []`,
+			expectedErr: "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTrackedResources([]byte(tt.content))
+			switch {
+			case len(tt.expectedErr) == 0 && err != nil:
+				t.Fatal(err)
+			case len(tt.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)):
+				t.Fatalf("expected %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+	trackedResources, err := ParseTrackedResources([]byte(tests[0].content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := trackedResources.Resources[0].ResourceType(); actual != "subscriptions.operators.coreos.com" {
+		t.Errorf("unexpected resource type %q", actual)
+	}
+	if actual := trackedResources.Resources[1].ResourceType(); actual != "configmaps" {
+		t.Errorf("unexpected resource type for the core group %q", actual)
+	}
+}
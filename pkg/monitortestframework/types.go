@@ -7,6 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -34,6 +35,80 @@ type MonitorTestInitializationInfo struct {
 
 	// DisableMonitorTests will remove any monitor tests contained in the provided list
 	DisableMonitorTests []string
+
+	// FailureBudgetPolicy decides how failing test cases with a severity affect the job.
+	// If nil, DefaultFailureBudgetPolicy is used.
+	FailureBudgetPolicy *FailureBudgetPolicy
+
+	// DuplicateTestNamePolicy decides what happens when more than one monitor test emits test cases with the same name.
+	// If empty, NamespaceDuplicateTestNames is used.
+	DuplicateTestNamePolicy DuplicateTestNamePolicy
+
+	// QuarantineList lists known failing tests whose failures are reported as flakes.  If nil, nothing is quarantined.
+	QuarantineList *QuarantineList
+
+	// StorageLayout decides which directory each monitor test writes its content to.  If nil, DefaultStorageLayout
+	// is used.
+	StorageLayout *StorageLayout
+
+	// CertificateExpiryHorizon is how long before they expire in-use platform certificates must have been rotated.
+	// If zero, the certificate analyzer uses its default.
+	CertificateExpiryHorizon time.Duration
+
+	// MaxStaticPodRevisions is how many revisions each static pod operator may roll out during the run.  If zero, the
+	// static pod revision monitor uses its default.
+	MaxStaticPodRevisions int
+
+	// SecondaryClusters are the clusters other than the cluster under test that monitor tests implementing
+	// SecondaryClusterMonitorTest collect from, by name.  If nil, monitor tests only collect from the cluster under test.
+	SecondaryClusters SecondaryClusters
+
+	// BackfillDuration is how long before collection began monitor tests implementing BackfillMonitorTest reconstruct
+	// intervals for.  If zero, nothing is backfilled.
+	BackfillDuration time.Duration
+
+	// RecorderWALFile is the write-ahead log the recorder appends to.  Intervals in it that the recorder does not hold,
+	// because an earlier monitor process crashed, are recovered during CollectData.  If empty, nothing is recovered.
+	RecorderWALFile string
+
+	// TrackedResources are resources recorded in addition to the fixed set the monitor records.  If nil, only the
+	// fixed set is recorded.
+	TrackedResources *TrackedResources
+
+	// MetricRules turn prometheus series into intervals when they cross a threshold.  If nil, no series are watched.
+	MetricRules *MetricRules
+
+	// BigQueryExport writes the intervals, junits, and cluster data as newline delimited JSON for the TRT data
+	// pipeline.  It requires the JOB_NAME and BUILD_ID variables prow sets.
+	BigQueryExport bool
+
+	// Notifier is told how the run went once the tests are evaluated.  If nil, nobody is notified.
+	Notifier Notifier
+
+	// IssueFiler files the monitor test failures that are not KnownFailures in the jira component of their monitor
+	// test.  If nil, nothing is filed.
+	IssueFiler    IssueFiler
+	KnownFailures *KnownFailures
+
+	// InjectedFaultDir is where chaos tooling drops files declaring the faults it injected, so their symptoms are
+	// excused.  If empty, faults can only be declared through the live monitor API.
+	InjectedFaultDir string
+
+	// ContributionDir is where test binaries outside of openshift-tests write the intervals and junits they add to the
+	// results, see the contribution package.  If empty, nothing is contributed.
+	ContributionDir string
+
+	// LocalDeveloperMode leaves out the registry outputs only CI reads, like the risk analysis input, for running the
+	// monitor tests against a personal cluster.
+	LocalDeveloperMode bool
+
+	// PRSummaryIntervals writes the failed monitor tests and this many of the most interesting intervals as markdown
+	// CI can post to the pull request that triggered a presubmit.  If zero, no summary is written.
+	PRSummaryIntervals int
+
+	// NodeAgent deploys an agent on every node that streams the intervals of node-local probes, like disk latency and
+	// conntrack saturation, to the recorder.
+	NodeAgent bool
 }
 
 type MonitorTest interface {
@@ -55,6 +130,8 @@ type MonitorTest interface {
 
 	// EvaluateTestsFromConstructedIntervals is called after all Intervals are known and can produce
 	// junit tests for reporting purposes.
+	// Failing junits may set Details.Severity to let the registry decide whether the failure fails the job,
+	// instead of emitting their own flakes.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error)
 
@@ -74,6 +151,14 @@ type MonitorTest interface {
 	Cleanup(ctx context.Context) error
 }
 
+// RegistryOutput produces an artifact from the combined results of every monitor test in a registry.
+// Outputs are written by the registry after every monitor test has written its own content to storage.
+type RegistryOutput interface {
+	// WriteRegistryOutput writes content to the storage directory that is collected by openshift CI.
+	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
+	WriteRegistryOutput(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error
+}
+
 type MonitorTestRegistry interface {
 	AddRegistryOrDie(registry MonitorTestRegistry)
 
@@ -83,9 +168,71 @@ type MonitorTestRegistry interface {
 
 	AddMonitorTestOrDie(name, jiraComponent string, monitorTest MonitorTest)
 
+	// AddRegistryOutput adds an output with a particular name that is written after all monitor tests have
+	// written their content to storage.
+	AddRegistryOutput(name, jiraComponent string, output RegistryOutput) error
+
+	AddRegistryOutputOrDie(name, jiraComponent string, output RegistryOutput)
+
 	GetRegistryFor(names ...string) (MonitorTestRegistry, error)
+
+	// SetFailureBudgetPolicy replaces the policy applied to the junits returned by EvaluateTestsFromConstructedIntervals.
+	SetFailureBudgetPolicy(policy FailureBudgetPolicy)
+
+	// FailureBudgetPolicy returns the policy applied to the junits returned by EvaluateTestsFromConstructedIntervals.
+	FailureBudgetPolicy() FailureBudgetPolicy
+
+	// SetDuplicateTestNamePolicy replaces the policy applied when more than one monitor test emits test cases with
+	// the same name.  The default is NamespaceDuplicateTestNames.
+	SetDuplicateTestNamePolicy(policy DuplicateTestNamePolicy)
+
+	// SetQuarantineList replaces the list of known failing tests whose failures are reported as flakes by every stage.
+	SetQuarantineList(quarantineList *QuarantineList)
+
+	// SetStorageLayout replaces the layout that decides which directory each monitor test and registry output writes
+	// its content to.  The default is DefaultStorageLayout.
+	SetStorageLayout(layout StorageLayout)
+
+	// SetSecondaryClusters replaces the clusters other than the cluster under test that monitor tests implementing
+	// SecondaryClusterMonitorTest are started on.
+	SetSecondaryClusters(clusters SecondaryClusters)
+
+	// SetBackfillDuration turns on backfill mode: CollectData also asks monitor tests implementing
+	// BackfillMonitorTest for the intervals of this long before collection began.  Zero turns it off.
+	SetBackfillDuration(duration time.Duration)
+
+	// SetClock replaces the clock the registry times stages with, and hands it to the monitor tests implementing
+	// ClockedMonitorTest.  The default is the real clock.
+	SetClock(clock clock.PassiveClock)
+
+	// SetOffline turns on offline mode, for running on the artifacts of an earlier run when StartCollection and
+	// CollectData never ran: only monitor tests implementing OfflineMonitorTest construct intervals, annotate them,
+	// and evaluate tests, the others are reported as not supported.
+	SetOffline(offline bool)
+
+	// SetRecorderWAL sets the write-ahead log the recorder appends to.  CollectData returns the intervals in it that the
+	// recorder passed to StartCollection does not hold.
+	SetRecorderWAL(filename string)
+
+	// SetNotifier sets the notifier told how the run went at the end of EvaluateTestsFromConstructedIntervals.  A
+	// notification that cannot be delivered flakes.
+	SetNotifier(notifier Notifier)
+
+	// SetIssueFiler sets the filer the regressions of the run are filed with at the end of
+	// EvaluateTestsFromConstructedIntervals.  Failures of knownFailures are not regressions.  Filing that fails flakes.
+	SetIssueFiler(issueFiler IssueFiler, knownFailures *KnownFailures)
+
+	// BackfilledBeginning returns the beginning of the run covered by a collection that began at beginning.  It is
+	// earlier than beginning in backfill mode or when intervals were recovered from an earlier monitor process, and
+	// intervals should be evaluated from then.
+	BackfilledBeginning(beginning time.Time) time.Time
+
 	ListMonitorTests() sets.String
 
+	// DescribeMonitorTests returns the name, jira component, applicable platforms, stages, and produced tests of every
+	// monitor test, from the description of those implementing DescribedMonitorTest.
+	DescribeMonitorTests() []MonitorTestMetadata
+
 	// StartCollection is responsible for setting up all resources required for collection of data on the cluster.
 	// An error will not stop execution, but will cause a junit failure that will cause the job run to fail.
 	// This allows us to know when setups fail.
@@ -101,8 +248,14 @@ type MonitorTestRegistry interface {
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error)
 
+	// AnnotateIntervals is called after all Intervals are known and returns them with the annotations of every
+	// monitor test implementing IntervalAnnotator added.  The annotated intervals are the ones evaluated and stored.
+	AnnotateIntervals(finalIntervals monitorapi.Intervals) monitorapi.Intervals
+
 	// EvaluateTestsFromConstructedIntervals is called after all Intervals are known and can produce
 	// junit tests for reporting purposes.
+	// The FailureBudgetPolicy is applied to the junits of each monitor test.
+	// Intervals annotated with monitorapi.AnnotationExcusedBy are symptoms of injected faults and are not passed on.
 	// Errors reported will be indicated as junit test failure and will cause job runs to fail.
 	EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error)
 
@@ -113,6 +266,8 @@ type MonitorTestRegistry interface {
 	// 3. tracked resources.  Those are written by some default monitorTests.
 	// You *may* choose to store state in CollectData that you later persist via this method. An example might be
 	// code that scans audit logs and reports summaries of top actors.
+	// Every RegistryOutput is written after the monitor tests.
+	// Finally, every file written is listed in ArtifactIndexFilename, attributed to the monitor test or output that wrote it.
 	WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error)
 
 	// Cleanup must be idempotent and it may be called multiple times in any scenario.  Multiple defers, multi-registered
@@ -121,4 +276,5 @@ type MonitorTestRegistry interface {
 	Cleanup(ctx context.Context) ([]*junitapi.JUnitTestCase, error)
 
 	getMonitorTests() map[string]*monitorTesttItem
+	getRegistryOutputs() map[string]*registryOutputItem
 }
@@ -73,9 +73,13 @@ func (a *watchdogAlertTest) TestAlert(ctx context.Context, prometheusClient prom
 	return nil
 }
 
+var isWatchdogAlert = monitorapi.And(
+	monitorapi.LocatorKeyIn(monitorapi.LocatorAlertKey, "Watchdog"),
+	monitorapi.LocatorKeyIn(monitorapi.LocatorNamespaceKey, "openshift-monitoring"),
+)
+
 func IsWatchdogAlert(eventInterval monitorapi.Interval) bool {
-	return eventInterval.Locator.Keys[monitorapi.LocatorAlertKey] == "Watchdog" &&
-		eventInterval.Locator.Keys[monitorapi.LocatorNamespaceKey] == "openshift-monitoring"
+	return isWatchdogAlert(eventInterval)
 }
 
 func (a *watchdogAlertTest) InvariantCheck(alertIntervals monitorapi.Intervals, _ monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error) {
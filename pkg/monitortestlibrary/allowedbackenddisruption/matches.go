@@ -3,6 +3,7 @@ package allowedbackenddisruption
 import (
 	"time"
 
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
 	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 )
 
@@ -19,6 +20,11 @@ const (
 // GetAllowedDisruption uses the backend and information about the cluster to choose the best historical p99 to
 // operate against.
 // We enforce "don't get worse" for disruption by watching the aggregate data in CI over many runs.
+// A baseline configured in the environment replaces the data compiled into the binary.
 func GetAllowedDisruption(backendName string, jobType platformidentification.JobType) (*time.Duration, string, error) {
-	return GetCurrentResults().BestMatchP99(backendName, jobType)
+	provider, err := historicaldata.BaselineProviderOr(GetCurrentResults())
+	if err != nil {
+		return nil, "", err
+	}
+	return historicaldata.BaselineP99(provider, backendName, platformidentification.ClusterData{JobType: jobType})
 }
@@ -0,0 +1,65 @@
+package allowedupdatephases
+
+import (
+	_ "embed"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+const (
+	// p99Query reads the phase durations the update-phases monitor test writes to the junit of every upgrade job.
+	// BackendName is cluster-update-phase-<phase> so the results can be read with the disruption matcher.
+	p99Query = `
+SELECT
+	CONCAT("cluster-update-phase-", Phase) AS BackendName,
+	Release,
+	FromRelease,
+	Platform,
+	Architecture,
+	Network,
+	Topology,
+	COUNT(*) AS JobRuns,
+	CAST(APPROX_QUANTILES(DurationSeconds, 100)[OFFSET(95)] AS STRING) AS P95,
+	CAST(APPROX_QUANTILES(DurationSeconds, 100)[OFFSET(99)] AS STRING) AS P99,
+FROM
+	openshift-ci-data-analysis.ci_data.ClusterUpdatePhases
+WHERE
+	JobRunStartTime > TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL 21 DAY)
+GROUP BY
+	Phase, Release, FromRelease, Platform, Architecture, Network, Topology
+`
+)
+
+//go:embed query_results.json
+var queryResults []byte
+
+var (
+	readResults    sync.Once
+	historicalData *historicaldata.DisruptionBestMatcher
+)
+
+func GetCurrentResults() *historicaldata.DisruptionBestMatcher {
+	readResults.Do(
+		func() {
+			var err error
+			historicalData, err = historicaldata.NewDisruptionMatcher(queryResults)
+			if err != nil {
+				panic(err)
+			}
+		})
+
+	return historicalData
+}
+
+// GetAllowedPhaseDuration returns the historical P99 of the update phase for this kind of job, or nil when we have
+// too few runs to know it.  A baseline configured in the environment replaces the data compiled into the binary.
+func GetAllowedPhaseDuration(phase string, jobType platformidentification.JobType) (*time.Duration, string, error) {
+	provider, err := historicaldata.BaselineProviderOr(GetCurrentResults())
+	if err != nil {
+		return nil, "", err
+	}
+	return historicaldata.BaselineP99(provider, "cluster-update-phase-"+phase, platformidentification.ClusterData{JobType: jobType})
+}
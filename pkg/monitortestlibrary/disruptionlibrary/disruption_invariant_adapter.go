@@ -129,6 +129,15 @@ func createDisruptionJunit(
 		}
 	}
 
+	// disruption while a load generator was running is expected, it is listed but not counted against the budget.
+	duringLoad := disruptedIntervals.Filter(monitorapi.IsDuringIntentionalLoad)
+	disruptedIntervals = disruptedIntervals.Filter(monitorapi.Not(monitorapi.IsDuringIntentionalLoad))
+	duringLoadDetails := ""
+	if len(duringLoad) > 0 {
+		duringLoadDetails = fmt.Sprintf("not counting %s of disruption during intentional load:\n%s",
+			duringLoad.Duration(1*time.Second).Round(time.Second), strings.Join(duringLoad.Strings(), "\n"))
+	}
+
 	disruptionDuration := disruptedIntervals.Duration(1 * time.Second)
 	roundedDisruptionDuration := disruptionDuration.Round(time.Second)
 
@@ -161,7 +170,8 @@ func createDisruptionJunit(
 
 	if roundedDisruptionDuration <= finalAllowedDisruption {
 		return &junitapi.JUnitTestCase{
-			Name: testName,
+			Name:      testName,
+			SystemOut: duringLoadDetails,
 		}
 	}
 
@@ -171,6 +181,9 @@ func createDisruptionJunit(
 		roundedDisruptionDuration, finalAllowedDisruption,
 		strings.Join(allowedDetails, "\n"),
 		strings.Join(describe, "\n"))
+	if len(duringLoadDetails) > 0 {
+		failureMessage = fmt.Sprintf("%s\n\n%s", failureMessage, duringLoadDetails)
+	}
 
 	return &junitapi.JUnitTestCase{
 		Name: testName,
@@ -0,0 +1,151 @@
+package historicaldata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+const (
+	// BaselineFileEnvVar is a local file of historical percentiles, in the format of the query_results.json files,
+	// that replaces the data compiled into the binary.  Air-gapped runs use it to supply their own baseline.
+	BaselineFileEnvVar = "OPENSHIFT_TESTS_BASELINE_FILE"
+	// BaselineURLEnvVar is a URL serving historical percentiles in the same format.  It is ignored if
+	// BaselineFileEnvVar is set.
+	BaselineURLEnvVar = "OPENSHIFT_TESTS_BASELINE_URL"
+
+	baselineFetchTimeout = time.Minute
+)
+
+// BaselineProvider answers historical percentiles of a named measurement, like a disruption backend or an update
+// phase, for the kind of cluster a job runs on.
+type BaselineProvider interface {
+	// Percentiles returns the closest match for the cluster with at least minJobRuns, and why it is not an exact
+	// match if it is not.  An empty StatisticalDuration means there is too little data, and the test should be skipped.
+	Percentiles(name string, clusterData platformidentification.ClusterData, minJobRuns int) (StatisticalDuration, string, error)
+}
+
+var _ BaselineProvider = &DisruptionBestMatcher{}
+
+func (b *DisruptionBestMatcher) Percentiles(name string, clusterData platformidentification.ClusterData, minJobRuns int) (StatisticalDuration, string, error) {
+	return b.BestMatchDuration(name, clusterData.JobType, minJobRuns)
+}
+
+// BaselineP99 returns the P99 of the closest match with enough job runs for a P99 to be meaningful, or nil when there
+// is none.
+func BaselineP99(provider BaselineProvider, name string, clusterData platformidentification.ClusterData) (*time.Duration, string, error) {
+	percentiles, details, err := provider.Percentiles(name, clusterData, defaultMinJobRuns)
+	if percentiles == (StatisticalDuration{}) {
+		return nil, details, err
+	}
+	return &percentiles.P99, details, err
+}
+
+// NewFileBaselineProvider reads the historical percentiles from a file.
+func NewFileBaselineProvider(filename string) (BaselineProvider, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	matcher, err := NewDisruptionMatcher(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline %q: %w", filename, err)
+	}
+	return matcher, nil
+}
+
+// httpBaselineProvider fetches the historical percentiles the first time they are asked for.
+type httpBaselineProvider struct {
+	url    string
+	client *http.Client
+
+	fetch   sync.Once
+	matcher *DisruptionBestMatcher
+	err     error
+}
+
+// NewHTTPBaselineProvider fetches the historical percentiles from the URL when they are first needed.  If they cannot
+// be fetched, every query returns the error.
+func NewHTTPBaselineProvider(url string) BaselineProvider {
+	return &httpBaselineProvider{
+		url:    url,
+		client: &http.Client{Timeout: baselineFetchTimeout},
+	}
+}
+
+func (p *httpBaselineProvider) Percentiles(name string, clusterData platformidentification.ClusterData, minJobRuns int) (StatisticalDuration, string, error) {
+	p.fetch.Do(func() {
+		p.matcher, p.err = p.fetchMatcher(context.Background())
+	})
+	if p.err != nil {
+		return StatisticalDuration{}, "", p.err
+	}
+	return p.matcher.Percentiles(name, clusterData, minJobRuns)
+}
+
+func (p *httpBaselineProvider) fetchMatcher(ctx context.Context) (*DisruptionBestMatcher, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch baseline from %q: %w", p.url, err)
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch baseline from %q: %w", p.url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch baseline from %q: %s: %s", p.url, resp.Status, content)
+	}
+	matcher, err := NewDisruptionMatcher(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid baseline from %q: %w", p.url, err)
+	}
+	return matcher, nil
+}
+
+var (
+	configuredBaseline         sync.Once
+	configuredBaselineProvider BaselineProvider
+	configuredBaselineErr      error
+)
+
+// ConfiguredBaselineProvider returns the provider configured in the environment, or nil if the data compiled into
+// the binary should be used.
+func ConfiguredBaselineProvider() (BaselineProvider, error) {
+	configuredBaseline.Do(func() {
+		configuredBaselineProvider, configuredBaselineErr = baselineProviderFromEnv(os.Getenv)
+	})
+	return configuredBaselineProvider, configuredBaselineErr
+}
+
+func baselineProviderFromEnv(getenv func(string) string) (BaselineProvider, error) {
+	if filename := getenv(BaselineFileEnvVar); len(filename) > 0 {
+		return NewFileBaselineProvider(filename)
+	}
+	if url := getenv(BaselineURLEnvVar); len(url) > 0 {
+		return NewHTTPBaselineProvider(url), nil
+	}
+	return nil, nil
+}
+
+// BaselineProviderOr returns the provider configured in the environment, or the fallback compiled into the binary.
+func BaselineProviderOr(fallback BaselineProvider) (BaselineProvider, error) {
+	provider, err := ConfiguredBaselineProvider()
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return fallback, nil
+	}
+	return provider, nil
+}
@@ -0,0 +1,100 @@
+package historicaldata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+const testBaseline = `[
+	{"BackendName": "kube-api-new-connections", "Release": "4.16", "FromRelease": "", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "1.0", "P99": "2.5", "JobRuns": 200},
+	{"BackendName": "kube-api-reused-connections", "Release": "4.16", "FromRelease": "", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "1.0", "P99": "2.5", "JobRuns": 3}
+]`
+
+var testClusterData = platformidentification.ClusterData{
+	JobType: platformidentification.JobType{Release: "4.16", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"},
+}
+
+func checkBaseline(t *testing.T, provider BaselineProvider) {
+	t.Helper()
+	p99, details, err := BaselineP99(provider, "kube-api-new-connections", testClusterData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p99 == nil || *p99 != 2500*time.Millisecond {
+		t.Errorf("expected a P99 of 2.5s, got %v %s", p99, details)
+	}
+	if p99, _, err := BaselineP99(provider, "kube-api-reused-connections", testClusterData); err != nil || p99 != nil {
+		t.Errorf("expected no P99 with too few job runs, got %v %v", p99, err)
+	}
+}
+
+func TestFileBaselineProvider(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(filename, []byte(testBaseline), 0644); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := NewFileBaselineProvider(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBaseline(t, provider)
+
+	if _, err := NewFileBaselineProvider(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("expected a missing baseline file to be an error")
+	}
+}
+
+func TestHTTPBaselineProvider(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Write([]byte(testBaseline))
+	}))
+	defer server.Close()
+
+	checkBaseline(t, NewHTTPBaselineProvider(server.URL))
+	if requests != 1 {
+		t.Errorf("expected the baseline to be fetched once, got %d requests", requests)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	if _, _, err := BaselineP99(NewHTTPBaselineProvider(failing.URL), "kube-api-new-connections", testClusterData); err == nil {
+		t.Errorf("expected a baseline that cannot be fetched to be an error")
+	}
+}
+
+func TestBaselineProviderFromEnv(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(filename, []byte(testBaseline), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	if provider, err := baselineProviderFromEnv(getenv); provider != nil || err != nil {
+		t.Errorf("expected no provider without configuration, got %v %v", provider, err)
+	}
+
+	env[BaselineURLEnvVar] = "http://example.com/baseline.json"
+	if provider, err := baselineProviderFromEnv(getenv); err != nil {
+		t.Fatal(err)
+	} else if _, ok := provider.(*httpBaselineProvider); !ok {
+		t.Errorf("expected an HTTP provider, got %T", provider)
+	}
+
+	env[BaselineFileEnvVar] = filename
+	provider, err := baselineProviderFromEnv(getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBaseline(t, provider)
+}
@@ -0,0 +1,38 @@
+package intervaltimeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+type timelineOutput struct {
+	lanes []Lane
+}
+
+// NewTimelineOutput returns a registry output that writes a self-contained html timeline of the final intervals
+// so that a run can be triaged without loading artifacts into a separate viewer.
+func NewTimelineOutput() monitortestframework.RegistryOutput {
+	return &timelineOutput{
+		lanes: DefaultLanes,
+	}
+}
+
+func (o *timelineOutput) WriteRegistryOutput(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	title := "Interval Timeline"
+	// the suffix starts with an underscore to join file names, for instance _20240101-000000.
+	if suffix := strings.TrimLeft(timeSuffix, "_"); len(suffix) > 0 {
+		title = fmt.Sprintf("%s %s", title, suffix)
+	}
+	html, err := RenderHTML(title, o.lanes, finalIntervals, time.Time{}, time.Time{})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(storageDir, fmt.Sprintf("e2e-interval-timeline%s.html", timeSuffix)), html, 0644)
+}
@@ -0,0 +1,140 @@
+package intervaltimeline
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+//go:embed timeline-template.html
+var timelineTemplateHTML string
+
+var timelineTemplate = template.Must(template.New("timeline").Parse(timelineTemplateHTML))
+
+// Lane is a swimlane in the rendered timeline.  Every interval is placed into at most one lane.
+type Lane struct {
+	Name    string
+	Matches monitorapi.EventIntervalMatchesFunc
+}
+
+// DefaultLanes are the swimlanes used to triage a run: nodes, operators, disruption, and alerts.
+var DefaultLanes = []Lane{
+	{Name: "Nodes", Matches: isLocatorType(monitorapi.LocatorTypeNode)},
+	{Name: "Operators", Matches: monitorapi.Or(isLocatorType(monitorapi.LocatorTypeClusterOperator), isLocatorType(monitorapi.LocatorTypeClusterVersion))},
+	{Name: "Disruption", Matches: isLocatorType(monitorapi.LocatorTypeDisruption)},
+	{Name: "Alerts", Matches: isLocatorType(monitorapi.LocatorTypeAlert)},
+}
+
+func isLocatorType(locatorType monitorapi.LocatorType) monitorapi.EventIntervalMatchesFunc {
+	return func(eventInterval monitorapi.Interval) bool {
+		return eventInterval.Locator.Type == locatorType
+	}
+}
+
+// these types are serialized into the page.  Times are milliseconds since the epoch to keep the javascript simple.
+type timeline struct {
+	Start int64          `json:"start"`
+	End   int64          `json:"end"`
+	Lanes []timelineLane `json:"lanes"`
+}
+
+type timelineLane struct {
+	Name string        `json:"name"`
+	Rows []timelineRow `json:"rows"`
+}
+
+type timelineRow struct {
+	Locator   string        `json:"locator"`
+	Intervals []timelineBar `json:"intervals"`
+}
+
+type timelineBar struct {
+	From    int64  `json:"from"`
+	To      int64  `json:"to"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// RenderHTML produces a self-contained, interactive html page with one swimlane per lane and one row per locator.
+// The page has no external dependencies, so it can be opened directly from the artifacts of a run.
+// Intervals that do not end are drawn until the end of the timeline.
+func RenderHTML(title string, lanes []Lane, intervals monitorapi.Intervals, beginning, end time.Time) ([]byte, error) {
+	data := buildTimeline(lanes, intervals, beginning, end)
+
+	out := &bytes.Buffer{}
+	if err := timelineTemplate.Execute(out, struct {
+		Title    string
+		Timeline timeline
+	}{
+		Title:    title,
+		Timeline: data,
+	}); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func buildTimeline(lanes []Lane, intervals monitorapi.Intervals, beginning, end time.Time) timeline {
+	computeBeginning, computeEnd := beginning.IsZero(), end.IsZero()
+	for _, interval := range intervals {
+		if computeBeginning && !interval.From.IsZero() && (beginning.IsZero() || interval.From.Before(beginning)) {
+			beginning = interval.From
+		}
+		if computeEnd && interval.To.After(end) {
+			end = interval.To
+		}
+	}
+
+	ret := timeline{
+		Start: beginning.UnixMilli(),
+		End:   end.UnixMilli(),
+	}
+	laneRows := make([]map[string]*timelineRow, len(lanes))
+	for i := range lanes {
+		laneRows[i] = map[string]*timelineRow{}
+	}
+
+	for _, interval := range intervals {
+		for i, lane := range lanes {
+			if !lane.Matches(interval) {
+				continue
+			}
+			from, to := interval.From, interval.To
+			if from.IsZero() || from.Before(beginning) {
+				from = beginning
+			}
+			if to.IsZero() || to.After(end) {
+				to = end
+			}
+			locator := interval.Locator.OldLocator()
+			row, ok := laneRows[i][locator]
+			if !ok {
+				row = &timelineRow{Locator: locator}
+				laneRows[i][locator] = row
+			}
+			row.Intervals = append(row.Intervals, timelineBar{
+				From:    from.UnixMilli(),
+				To:      to.UnixMilli(),
+				Level:   interval.Level.String(),
+				Message: interval.Message.OldMessage(),
+			})
+			break
+		}
+	}
+
+	for i, lane := range lanes {
+		currLane := timelineLane{Name: lane.Name, Rows: []timelineRow{}}
+		for _, row := range laneRows[i] {
+			currLane.Rows = append(currLane.Rows, *row)
+		}
+		sort.Slice(currLane.Rows, func(i, j int) bool {
+			return currLane.Rows[i].Locator < currLane.Rows[j].Locator
+		})
+		ret.Lanes = append(ret.Lanes, currLane)
+	}
+	return ret
+}
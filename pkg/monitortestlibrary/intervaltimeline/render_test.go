@@ -0,0 +1,63 @@
+package intervaltimeline
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	start := time.Unix(100, 0)
+	nodeInterval := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().HumanMessage("not ready")).
+		Build(start, start.Add(time.Minute))
+	openInterval := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Error).
+		Locator(monitorapi.NewLocator().NodeFromName("node-b")).
+		Message(monitorapi.NewMessage().HumanMessage("unreachable")).
+		Build(start.Add(time.Second), time.Time{})
+	unmatched := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("node-c")).
+		Message(monitorapi.NewMessage().HumanMessage("ignored")).
+		Build(start, start.Add(2*time.Minute))
+	unmatched.Locator.Type = monitorapi.LocatorType("Other")
+
+	actual := buildTimeline(DefaultLanes, monitorapi.Intervals{openInterval, nodeInterval, unmatched}, time.Time{}, time.Time{})
+
+	if actual.Start != start.UnixMilli() {
+		t.Errorf("expected start %v, got %v", start.UnixMilli(), actual.Start)
+	}
+	if expected := start.Add(2 * time.Minute).UnixMilli(); actual.End != expected {
+		t.Errorf("expected end %v, got %v", expected, actual.End)
+	}
+	if len(actual.Lanes) != len(DefaultLanes) {
+		t.Fatalf("expected %d lanes, got %d", len(DefaultLanes), len(actual.Lanes))
+	}
+	nodeRows := actual.Lanes[0].Rows
+	if len(nodeRows) != 2 {
+		t.Fatalf("expected 2 node rows, got %d", len(nodeRows))
+	}
+	if !strings.Contains(nodeRows[0].Locator, "node-a") {
+		t.Errorf("expected rows sorted by locator, got %q first", nodeRows[0].Locator)
+	}
+	if to := nodeRows[1].Intervals[0].To; to != actual.End {
+		t.Errorf("expected open interval to extend to the end, got %v", to)
+	}
+}
+
+func TestRenderHTMLEscapesContent(t *testing.T) {
+	interval := monitorapi.NewInterval(monitorapi.SourceTestData, monitorapi.Error).
+		Locator(monitorapi.NewLocator().NodeFromName("node-a")).
+		Message(monitorapi.NewMessage().HumanMessage("</script><script>alert(1)</script>")).
+		Build(time.Unix(100, 0), time.Unix(200, 0))
+
+	out, err := RenderHTML("test", DefaultLanes, monitorapi.Intervals{interval}, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(out), "<script>alert(1)") {
+		t.Errorf("interval message was not escaped")
+	}
+}
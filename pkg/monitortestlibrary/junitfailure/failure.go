@@ -0,0 +1,200 @@
+package junitfailure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// SystemOutMarker prefixes the line of system-out that holds the structured failure as JSON.  Everything after the
+// marker, up to the end of the line, is a StructuredFailure.
+const SystemOutMarker = "structured-failure: "
+
+// maxIntervalsInMessage bounds the human readable message.  Every interval is still in the structured form.
+const maxIntervalsInMessage = 20
+
+// StructuredFailure is the machine readable form of a failure, embedded in system-out so that automated triage
+// does not have to parse failure messages.
+type StructuredFailure struct {
+	Reason     string                    `json:"reason"`
+	Message    string                    `json:"message"`
+	Thresholds []junitapi.JUnitThreshold `json:"thresholds,omitempty"`
+	Fields     map[string]interface{}    `json:"fields,omitempty"`
+	Intervals  []FailureInterval         `json:"intervals,omitempty"`
+}
+
+// FailureInterval is an offending interval, identified the same way as in the interval files.
+type FailureInterval struct {
+	ID      string    `json:"id"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Locator string    `json:"locator"`
+	Message string    `json:"message"`
+}
+
+// TemplateData is what failure templates are rendered with.
+type TemplateData struct {
+	Reason     string
+	Thresholds []junitapi.JUnitThreshold
+	// Threshold is the first threshold, which is the only one for most failures.
+	Threshold junitapi.JUnitThreshold
+	Fields    map[string]interface{}
+	Intervals monitorapi.Intervals
+	// IntervalList is the offending intervals, one per line, truncated for readability.
+	IntervalList string
+}
+
+// Template is a parsed failure message template.  Templates are text/template, missing keys are errors.
+type Template struct {
+	tmpl *template.Template
+}
+
+// MustParseTemplate parses a template at package initialization.  Invalid templates are coding errors.
+func MustParseTemplate(name, text string) *Template {
+	return &Template{
+		tmpl: template.Must(template.New(name).Option("missingkey=error").Parse(text)),
+	}
+}
+
+// FailureBuilder collects the structured fields of a failure before rendering them with a template.
+type FailureBuilder struct {
+	template   *Template
+	reason     string
+	thresholds []junitapi.JUnitThreshold
+	fields     map[string]interface{}
+	intervals  monitorapi.Intervals
+}
+
+// NewFailure starts a failure that is rendered with the template.  The reason is a short, machine-readable reason,
+// for instance "DisruptionExceeded".
+func NewFailure(template *Template, reason string) *FailureBuilder {
+	return &FailureBuilder{
+		template: template,
+		reason:   reason,
+		fields:   map[string]interface{}{},
+	}
+}
+
+// Threshold records a limit that was evaluated.
+func (b *FailureBuilder) Threshold(threshold junitapi.JUnitThreshold) *FailureBuilder {
+	b.thresholds = append(b.thresholds, threshold)
+	return b
+}
+
+// Field records a value that is available to the template as .Fields.<key> and is kept in the structured form.
+// Values must be serializable to JSON.
+func (b *FailureBuilder) Field(key string, value interface{}) *FailureBuilder {
+	b.fields[key] = value
+	return b
+}
+
+// Intervals records the intervals that caused the failure.
+func (b *FailureBuilder) Intervals(intervals ...monitorapi.Interval) *FailureBuilder {
+	b.intervals = append(b.intervals, intervals...)
+	return b
+}
+
+// Build renders the template and returns the structured failure.
+func (b *FailureBuilder) Build() (*StructuredFailure, error) {
+	data := TemplateData{
+		Reason:       b.reason,
+		Thresholds:   b.thresholds,
+		Fields:       b.fields,
+		Intervals:    b.intervals,
+		IntervalList: intervalList(b.intervals),
+	}
+	if len(b.thresholds) > 0 {
+		data.Threshold = b.thresholds[0]
+	}
+	out := &bytes.Buffer{}
+	if err := b.template.tmpl.Execute(out, data); err != nil {
+		return nil, fmt.Errorf("unable to render failure template %q: %w", b.template.tmpl.Name(), err)
+	}
+
+	ret := &StructuredFailure{
+		Reason:     b.reason,
+		Message:    strings.TrimSpace(out.String()),
+		Thresholds: b.thresholds,
+	}
+	if len(b.fields) > 0 {
+		ret.Fields = b.fields
+	}
+	for _, interval := range b.intervals {
+		ret.Intervals = append(ret.Intervals, FailureInterval{
+			ID:      interval.ID(),
+			From:    interval.From,
+			To:      interval.To,
+			Locator: interval.Locator.OldLocator(),
+			Message: interval.Message.OldMessage(),
+		})
+	}
+	return ret, nil
+}
+
+// TestCase returns a failing test case with the rendered message as the failure output, the structured failure in
+// system-out, and the reason, thresholds, and intervals in the details.  If the template cannot be rendered, the
+// test case still fails, with the rendering error and the structured fields as the message.
+func (b *FailureBuilder) TestCase(testName string) *junitapi.JUnitTestCase {
+	failure, err := b.Build()
+	if err != nil {
+		failure = &StructuredFailure{
+			Reason:     b.reason,
+			Message:    fmt.Sprintf("%v: fields=%v", err, b.fields),
+			Thresholds: b.thresholds,
+		}
+	}
+	return &junitapi.JUnitTestCase{
+		Name:      testName,
+		SystemOut: failure.systemOut(),
+		FailureOutput: &junitapi.FailureOutput{
+			Output: failure.Message,
+		},
+		Details: &junitapi.JUnitTestCaseDetails{
+			Reason:      b.reason,
+			Thresholds:  b.thresholds,
+			IntervalIDs: b.intervals.IDs(),
+		},
+	}
+}
+
+func (f *StructuredFailure) systemOut() string {
+	content, err := json.Marshal(f)
+	if err != nil {
+		// only possible with fields that cannot be serialized, keep the message.
+		return f.Message
+	}
+	return fmt.Sprintf("%s\n%s%s", f.Message, SystemOutMarker, content)
+}
+
+// ParseSystemOut returns the structured failure embedded in system-out, or nil if there is none.
+func ParseSystemOut(systemOut string) (*StructuredFailure, error) {
+	for _, line := range strings.Split(systemOut, "\n") {
+		if !strings.HasPrefix(line, SystemOutMarker) {
+			continue
+		}
+		ret := &StructuredFailure{}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, SystemOutMarker)), ret); err != nil {
+			return nil, fmt.Errorf("unable to parse structured failure: %w", err)
+		}
+		return ret, nil
+	}
+	return nil, nil
+}
+
+func intervalList(intervals monitorapi.Intervals) string {
+	lines := []string{}
+	for i, interval := range intervals {
+		if i == maxIntervalsInMessage {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(intervals)-maxIntervalsInMessage))
+			break
+		}
+		lines = append(lines, interval.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,96 @@
+package junitfailure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var disruptionTemplate = MustParseTemplate("disruption",
+	`{{.Fields.backend}} was unreachable for {{.Threshold.Observed}}s, more than the allowed {{.Threshold.Limit}}s:
+{{.IntervalList}}`)
+
+func TestTestCase(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().KubeAPIServerWithLB("")).
+		Message(monitorapi.NewMessage().HumanMessage("connection refused")).
+		Build(from, from.Add(5*time.Second))
+	threshold := junitapi.JUnitThreshold{Name: "disruption-seconds", Limit: 2, Observed: 5, Unit: "seconds", Exceeded: true}
+
+	junit := NewFailure(disruptionTemplate, "DisruptionExceeded").
+		Threshold(threshold).
+		Field("backend", "kube-api-new-connections").
+		Intervals(interval).
+		TestCase("[sig-api-machinery] disruption")
+
+	if junit.FailureOutput == nil {
+		t.Fatal("expected a failure")
+	}
+	expectedMessage := "kube-api-new-connections was unreachable for 5s, more than the allowed 2s:\n" + interval.String()
+	if junit.FailureOutput.Output != expectedMessage {
+		t.Errorf("expected %q, got %q", expectedMessage, junit.FailureOutput.Output)
+	}
+	if junit.Details.Reason != "DisruptionExceeded" || !reflect.DeepEqual(junit.Details.IntervalIDs, []string{interval.ID()}) {
+		t.Errorf("unexpected details %#v", junit.Details)
+	}
+
+	structured, err := ParseSystemOut(junit.SystemOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &StructuredFailure{
+		Reason:     "DisruptionExceeded",
+		Message:    expectedMessage,
+		Thresholds: []junitapi.JUnitThreshold{threshold},
+		Fields:     map[string]interface{}{"backend": "kube-api-new-connections"},
+		Intervals: []FailureInterval{
+			{
+				ID:      interval.ID(),
+				From:    interval.From,
+				To:      interval.To,
+				Locator: interval.Locator.OldLocator(),
+				Message: interval.Message.OldMessage(),
+			},
+		},
+	}
+	if !reflect.DeepEqual(structured, expected) {
+		t.Errorf("expected %#v, got %#v", expected, structured)
+	}
+}
+
+func TestTestCaseWithMissingField(t *testing.T) {
+	junit := NewFailure(disruptionTemplate, "DisruptionExceeded").TestCase("[sig-api-machinery] disruption")
+	if junit.FailureOutput == nil {
+		t.Fatal("expected a failure even when the template cannot be rendered")
+	}
+	if !strings.Contains(junit.FailureOutput.Output, `unable to render failure template "disruption"`) {
+		t.Errorf("expected the rendering error in the message, got %q", junit.FailureOutput.Output)
+	}
+}
+
+func TestIntervalListIsTruncated(t *testing.T) {
+	intervals := monitorapi.Intervals{}
+	for i := 0; i < maxIntervalsInMessage+5; i++ {
+		from := time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC)
+		intervals = append(intervals, monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(monitorapi.NewLocator().KubeAPIServerWithLB("")).
+			Message(monitorapi.NewMessage().HumanMessage("connection refused")).
+			Build(from, from))
+	}
+	lines := strings.Split(intervalList(intervals), "\n")
+	if len(lines) != maxIntervalsInMessage+1 || lines[maxIntervalsInMessage] != "... and 5 more" {
+		t.Errorf("unexpected interval list %v", lines)
+	}
+}
+
+func TestParseSystemOutWithoutStructuredFailure(t *testing.T) {
+	structured, err := ParseSystemOut("just a message")
+	if err != nil || structured != nil {
+		t.Errorf("expected nothing, got %v, %v", structured, err)
+	}
+}
@@ -0,0 +1,69 @@
+package nodeaccess
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetNodeJournal returns the journal of the last day for a particular systemd unit on a given node.
+// We're count on these logs to fit into some reasonable memory size.
+func GetNodeJournal(ctx context.Context, client kubernetes.Interface, nodeName, systemdUnitName string) ([]byte, error) {
+	path := client.CoreV1().RESTClient().Get().
+		Namespace("").Name(nodeName).
+		Resource("nodes").SubResource("proxy", "logs").Suffix("journal").URL().Path
+
+	req := client.CoreV1().RESTClient().Get().RequestURI(path).
+		SetHeader("Accept", "text/plain, */*")
+	req.Param("since", "-1d")
+	req.Param("unit", systemdUnitName)
+
+	in, err := req.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	return ioutil.ReadAll(in)
+}
+
+var journalTimeRegex = regexp.MustCompile(`^(?P<MONTH>\S+)\s(?P<DAY>\S+)\s(?P<TIME>\S+)`)
+
+// SystemdJournalLogTime returns Now if there is trouble reading the time.  This will stack the event intervals without
+// parsable times at the end of the run, which will be more clearly visible as a problem than not reporting them.
+func SystemdJournalLogTime(logLine string) time.Time {
+	if !journalTimeRegex.MatchString(logLine) {
+		return time.Now()
+	}
+
+	month := ""
+	day := ""
+	year := fmt.Sprintf("%d", time.Now().Year())
+	timeOfDay := ""
+	subMatches := journalTimeRegex.FindStringSubmatch(logLine)
+	subNames := journalTimeRegex.SubexpNames()
+	for i, name := range subNames {
+		switch name {
+		case "MONTH":
+			month = subMatches[i]
+		case "DAY":
+			day = subMatches[i]
+		case "TIME":
+			timeOfDay = subMatches[i]
+		}
+	}
+
+	timeString := fmt.Sprintf("%s %s %s %s UTC", day, month, year, timeOfDay)
+	ret, err := time.Parse("02 Jan 2006 15:04:05.999999999 MST", timeString)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failure parsing time format: %v for %q\n", err, timeString)
+		return time.Now()
+	}
+
+	return ret
+}
@@ -0,0 +1,165 @@
+package pathologicaleventlibrary
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const RepeatedEventsTestName = "[sig-arch] events should not repeat pathologically within the run"
+
+// RepeatedEvent is every recording of the same event, identified by its locator, reason, and message, during the run.
+type RepeatedEvent struct {
+	// Representative is the recording with the highest count, with its count raised to Times.
+	Representative monitorapi.Interval
+	// Times is how often the event happened: the highest count the event reported, or the number of times it was
+	// recorded when the event was re-created instead of updated.
+	Times int
+}
+
+// Namespace returns the namespace the event was recorded in, empty for cluster scoped events.
+func (r RepeatedEvent) Namespace() string {
+	return r.Representative.Locator.Keys[monitorapi.LocatorNamespaceKey]
+}
+
+// FindRepeatedEvents groups the kube events by locator, reason, and message and returns the groups that happened
+// more than DuplicateEventThreshold times, sorted by their first recording.
+func FindRepeatedEvents(events monitorapi.Intervals) []RepeatedEvent {
+	type group struct {
+		representative monitorapi.Interval
+		maxCount       int
+		recordedAt     map[int64]bool
+		from, to       time.Time
+	}
+
+	groups := map[string]*group{}
+	keys := []string{}
+	for _, event := range events {
+		if event.Source != monitorapi.SourceKubeEvent {
+			continue
+		}
+		key := fmt.Sprintf("%s - reason/%s %s", event.Locator.OldLocator(), event.Message.Reason, event.Message.HumanMessage)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{representative: event, recordedAt: map[int64]bool{}, from: event.From, to: event.To}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		// the same recording may be present more than once after it was marked pathological.
+		g.recordedAt[event.From.UnixNano()] = true
+		if count := GetTimesAnEventHappened(event.Message); count > g.maxCount {
+			g.maxCount = count
+			g.representative = event
+		}
+		if event.From.Before(g.from) {
+			g.from = event.From
+		}
+		if event.To.After(g.to) {
+			g.to = event.To
+		}
+	}
+
+	ret := []RepeatedEvent{}
+	for _, key := range keys {
+		g := groups[key]
+		times := g.maxCount
+		if len(g.recordedAt) > times {
+			times = len(g.recordedAt)
+		}
+		if times <= DuplicateEventThreshold {
+			continue
+		}
+
+		representative := g.representative.DeepCopy()
+		if representative.Message.Annotations == nil {
+			representative.Message.Annotations = map[monitorapi.AnnotationKey]string{}
+		}
+		representative.Message.Annotations[monitorapi.AnnotationCount] = fmt.Sprintf("%d", times)
+		representative.From = g.from
+		representative.To = g.to
+		ret = append(ret, RepeatedEvent{Representative: *representative, Times: times})
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Representative.From.Before(ret[j].Representative.From)
+	})
+	return ret
+}
+
+// RepeatedEventIntervals marks the period between the first and the last recording of every pathologically
+// repeating event so they can be charted together.
+func RepeatedEventIntervals(repeated []RepeatedEvent) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, r := range repeated {
+		to := r.Representative.To
+		if !to.After(r.Representative.From) {
+			to = r.Representative.From.Add(time.Second)
+		}
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourcePathologicalEventMarker, monitorapi.Warning).
+			Locator(r.Representative.Locator).
+			Message(monitorapi.NewMessage().Reason(r.Representative.Message.Reason).
+				HumanMessage(fmt.Sprintf("event repeated %d times: %s", r.Times, r.Representative.Message.HumanMessage)).
+				WithAnnotation(monitorapi.AnnotationPathological, "true").
+				WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%d", r.Times)).
+				Constructed(monitorapi.ConstructionOwnerRepeatedEvent)).
+			Display().
+			Build(r.Representative.From, to))
+	}
+	return ret
+}
+
+var repeatedEventsTemplate = junitfailure.MustParseTemplate("repeated-events",
+	`{{len .Intervals}} events repeated more than {{.Threshold.Limit}} times during the run without a known exception.  Something keeps failing and retrying; the most repeated event happened {{.Threshold.Observed}} times.
+{{.IntervalList}}`)
+
+// EvaluateRepeatedEvents produces a test for every known namespace, plus one for cluster scoped events, failing for
+// the pathologically repeating events the registry does not allow.  Events in other namespaces, most of them created
+// by e2e tests that repeat events on purpose, are not evaluated.
+func EvaluateRepeatedEvents(repeated []RepeatedEvent, registry *AllowedPathologicalEventRegistry, topology v1.TopologyMode) []*junitapi.JUnitTestCase {
+	namespaces := getNamespacesForJUnits()
+
+	disallowedByNamespace := map[string]monitorapi.Intervals{}
+	mostByNamespace := map[string]int{}
+	for _, r := range repeated {
+		namespace := r.Namespace()
+		if !namespaces.Has(namespace) {
+			continue
+		}
+		if allowed, _ := registry.AllowedByAny(r.Representative, topology); allowed {
+			continue
+		}
+		disallowedByNamespace[namespace] = append(disallowedByNamespace[namespace], r.Representative)
+		if r.Times > mostByNamespace[namespace] {
+			mostByNamespace[namespace] = r.Times
+		}
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, namespace := range namespaces.List() {
+		testName := getJUnitName(RepeatedEventsTestName, namespace)
+		disallowed, ok := disallowedByNamespace[namespace]
+		if !ok {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+
+		junit := junitfailure.NewFailure(repeatedEventsTemplate, "RepeatedEvents").
+			Threshold(junitapi.JUnitThreshold{
+				Name:     "repeats",
+				Observed: float64(mostByNamespace[namespace]),
+				Limit:    DuplicateEventThreshold,
+				Unit:     "events",
+			}).
+			Field("namespace", namespace).
+			Intervals(disallowed...).
+			TestCase(testName)
+		// a warning while the exceptions are brought over from the legacy duplicated event tests.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
@@ -0,0 +1,76 @@
+package pathologicaleventlibrary
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatedEvents(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recordedAt := func(i monitorapi.Interval, offset time.Duration) monitorapi.Interval {
+		i.From = start.Add(offset)
+		i.To = start.Add(offset + time.Second)
+		return i
+	}
+
+	events := monitorapi.Intervals{
+		// counted by the event itself.
+		recordedAt(BuildTestDupeKubeEvent("openshift-etcd", "etcd-0", "Unhealthy", "Readiness probe failed", 5), time.Minute),
+		recordedAt(BuildTestDupeKubeEvent("openshift-etcd", "etcd-0", "Unhealthy", "Readiness probe failed", 30), 10*time.Minute),
+		// below the threshold.
+		recordedAt(BuildTestDupeKubeEvent("openshift-etcd", "etcd-1", "Unhealthy", "Readiness probe failed", 20), 2*time.Minute),
+		// allowed below.
+		recordedAt(BuildTestDupeKubeEvent("openshift-etcd", "etcd-2", "BackOff", "Back-off restarting failed container", 50), 3*time.Minute),
+		// e2e namespaces are not evaluated.
+		recordedAt(BuildTestDupeKubeEvent("e2e-test-1234", "pod", "Unhealthy", "Readiness probe failed", 50), 4*time.Minute),
+	}
+	// re-created rather than counted.
+	for i := 0; i < 25; i++ {
+		events = append(events, recordedAt(BuildTestDupeKubeEvent("openshift-etcd", "etcd-3", "Pulled", "Image pulled", 1), time.Duration(i)*time.Second))
+	}
+	// the same recording marked pathological is not counted again.
+	for i := 0; i < 15; i++ {
+		events = append(events, recordedAt(BuildTestDupeKubeEvent("openshift-etcd", "etcd-4", "Pulled", "Image pulled", 1), time.Duration(i)*time.Second))
+		events = append(events, recordedAt(BuildTestDupeKubeEvent("openshift-etcd", "etcd-4", "Pulled", "Image pulled", 1), time.Duration(i)*time.Second))
+	}
+
+	repeated := FindRepeatedEvents(events)
+	require.Len(t, repeated, 4)
+	assert.Equal(t, "etcd-3", repeated[0].Representative.Locator.Keys[monitorapi.LocatorPodKey])
+	assert.Equal(t, 25, repeated[0].Times)
+	assert.Equal(t, "etcd-0", repeated[1].Representative.Locator.Keys[monitorapi.LocatorPodKey])
+	assert.Equal(t, 30, repeated[1].Times)
+	assert.Equal(t, start.Add(time.Minute), repeated[1].Representative.From)
+	assert.Equal(t, start.Add(10*time.Minute+time.Second), repeated[1].Representative.To)
+
+	intervals := RepeatedEventIntervals(repeated)
+	require.Len(t, intervals, 4)
+	for _, interval := range intervals {
+		assert.Equal(t, monitorapi.SourcePathologicalEventMarker, interval.Source)
+		assert.Equal(t, "true", interval.Message.Annotations[monitorapi.AnnotationPathological])
+	}
+
+	registry := &AllowedPathologicalEventRegistry{matchers: map[string]EventMatcher{}}
+	registry.AddPathologicalEventMatcherOrDie(AllowBackOffRestartingFailedContainer)
+
+	junits := EvaluateRepeatedEvents(repeated, registry, v1.HighlyAvailableTopologyMode)
+	var etcdJUnit *junitapi.JUnitTestCase
+	for _, junit := range junits {
+		if junit.Name == getJUnitName(RepeatedEventsTestName, "openshift-etcd") {
+			etcdJUnit = junit
+			continue
+		}
+		assert.Nil(t, junit.FailureOutput, "unexpected failure for %s", junit.Name)
+	}
+	require.NotNil(t, etcdJUnit)
+	require.NotNil(t, etcdJUnit.FailureOutput)
+	assert.Equal(t, junitapi.SeverityWarn, etcdJUnit.Details.Severity)
+	assert.Len(t, etcdJUnit.Details.IntervalIDs, 2)
+	assert.Equal(t, float64(30), etcdJUnit.Details.Thresholds[0].Observed)
+}
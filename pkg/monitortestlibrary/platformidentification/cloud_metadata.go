@@ -0,0 +1,106 @@
+package platformidentification
+
+import (
+	"context"
+	"sort"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machineclient "github.com/openshift/client-go/machine/clientset/versioned/typed/machine/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	machineAPINamespace = "openshift-machine-api"
+
+	// these labels are set by the machine controllers once the instance of a machine is provisioned.
+	machineRoleLabel          = "machine.openshift.io/cluster-api-machine-role"
+	machineInstanceTypeLabel  = "machine.openshift.io/instance-type"
+	machineRegionLabel        = "machine.openshift.io/region"
+	machineZoneLabel          = "machine.openshift.io/zone"
+	machineInterruptibleLabel = "machine.openshift.io/interruptible-instance"
+)
+
+// CloudMetadata is how the machines of the cluster are spread over the cloud, read from the machine API.  Clusters
+// without the machine API, like hosted clusters and clusters installed on user provisioned infrastructure, leave it
+// empty.
+type CloudMetadata struct {
+	// Regions are the regions machines run in, sorted.  There is more than one only for unusual installs.
+	Regions []string
+	// Zones is how many machines run in every availability zone, by zone.
+	Zones map[string]int
+	// Roles are the machines of every role, sorted by role.
+	Roles []MachineRole
+}
+
+// MachineRole is how the machines of one role, like master or worker, run.
+type MachineRole struct {
+	Role string
+	// InstanceTypes is how many machines of the role run on every instance type, by instance type.
+	InstanceTypes map[string]int
+	// SpotMachines run on spot or otherwise interruptible instances, OnDemandMachines on the rest.
+	SpotMachines     int
+	OnDemandMachines int
+}
+
+// addCloudMetadata fills in the cloud metadata from the machines.  Clusters without the machine API leave it empty
+// instead of failing.
+func addCloudMetadata(ctx context.Context, machineClient machineclient.MachineV1beta1Interface, clusterData *ClusterData) []error {
+	machines, err := machineClient.Machines(machineAPINamespace).List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return []error{err}
+	}
+	clusterData.CloudMetadata = cloudMetadataFor(machines.Items)
+	return nil
+}
+
+// cloudMetadataFor reads the labels of the machines.  Machines without an instance yet are skipped.
+func cloudMetadataFor(machines []machinev1beta1.Machine) CloudMetadata {
+	regions := map[string]bool{}
+	zones := map[string]int{}
+	roles := map[string]*MachineRole{}
+	for _, machine := range machines {
+		instanceType := machine.Labels[machineInstanceTypeLabel]
+		if len(instanceType) == 0 {
+			continue
+		}
+		if region := machine.Labels[machineRegionLabel]; len(region) > 0 {
+			regions[region] = true
+		}
+		if zone := machine.Labels[machineZoneLabel]; len(zone) > 0 {
+			zones[zone]++
+		}
+
+		roleName := machine.Labels[machineRoleLabel]
+		role, ok := roles[roleName]
+		if !ok {
+			role = &MachineRole{Role: roleName, InstanceTypes: map[string]int{}}
+			roles[roleName] = role
+		}
+		role.InstanceTypes[instanceType]++
+		if _, interruptible := machine.Labels[machineInterruptibleLabel]; interruptible {
+			role.SpotMachines++
+		} else {
+			role.OnDemandMachines++
+		}
+	}
+
+	if len(roles) == 0 {
+		return CloudMetadata{}
+	}
+	ret := CloudMetadata{Regions: []string{}, Zones: zones, Roles: []MachineRole{}}
+	for region := range regions {
+		ret.Regions = append(ret.Regions, region)
+	}
+	sort.Strings(ret.Regions)
+	for _, role := range roles {
+		ret.Roles = append(ret.Roles, *role)
+	}
+	sort.Slice(ret.Roles, func(i, j int) bool {
+		return ret.Roles[i].Role < ret.Roles[j].Role
+	})
+	return ret
+}
@@ -0,0 +1,73 @@
+package platformidentification
+
+import (
+	"reflect"
+	"testing"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func machineWithLabels(name string, labels map[string]string) machinev1beta1.Machine {
+	return machinev1beta1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: machineAPINamespace, Name: name, Labels: labels}}
+}
+
+func TestCloudMetadataFor(t *testing.T) {
+	machine := func(name, role, instanceType, zone string, spot bool) machinev1beta1.Machine {
+		labels := map[string]string{
+			machineRoleLabel:         role,
+			machineInstanceTypeLabel: instanceType,
+			machineRegionLabel:       "us-east-1",
+			machineZoneLabel:         zone,
+		}
+		if spot {
+			labels[machineInterruptibleLabel] = ""
+		}
+		return machineWithLabels(name, labels)
+	}
+
+	tests := []struct {
+		name     string
+		machines []machinev1beta1.Machine
+		expected CloudMetadata
+	}{
+		{
+			name:     "no machine API",
+			expected: CloudMetadata{},
+		},
+		{
+			name: "machines without instances are skipped",
+			machines: []machinev1beta1.Machine{
+				machineWithLabels("provisioning", map[string]string{machineRoleLabel: "worker"}),
+			},
+			expected: CloudMetadata{},
+		},
+		{
+			name: "roles and zones",
+			machines: []machinev1beta1.Machine{
+				machine("master-0", "master", "m6a.xlarge", "us-east-1a", false),
+				machine("master-1", "master", "m6a.xlarge", "us-east-1b", false),
+				machine("master-2", "master", "m6a.xlarge", "us-east-1c", false),
+				machine("worker-a", "worker", "m6a.large", "us-east-1a", false),
+				machine("worker-b", "worker", "m6a.large", "us-east-1b", true),
+				machine("worker-c", "worker", "c6a.large", "us-east-1b", true),
+			},
+			expected: CloudMetadata{
+				Regions: []string{"us-east-1"},
+				Zones:   map[string]int{"us-east-1a": 2, "us-east-1b": 3, "us-east-1c": 1},
+				Roles: []MachineRole{
+					{Role: "master", InstanceTypes: map[string]int{"m6a.xlarge": 3}, OnDemandMachines: 3},
+					{Role: "worker", InstanceTypes: map[string]int{"m6a.large": 2, "c6a.large": 1}, SpotMachines: 2, OnDemandMachines: 1},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := cloudMetadataFor(tt.machines)
+			if !reflect.DeepEqual(tt.expected, actual) {
+				t.Errorf("expected %#v, got %#v", tt.expected, actual)
+			}
+		})
+	}
+}
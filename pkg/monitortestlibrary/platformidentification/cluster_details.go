@@ -0,0 +1,140 @@
+package platformidentification
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const installConfigName = "cluster-config-v1"
+
+// InstallTopology is the shape of the cluster as the install config asked for it.
+type InstallTopology struct {
+	ControlPlaneReplicas int64
+	// ComputeReplicas is the sum of the replicas of every compute pool.
+	ComputeReplicas int64
+	// Publish is how the cluster endpoints are published, External or Internal.
+	Publish string
+}
+
+// installConfig is the subset of openshift-install's InstallConfig the cluster data is built from.
+type installConfig struct {
+	FIPS         bool                `json:"fips,omitempty"`
+	Publish      string              `json:"publish,omitempty"`
+	ControlPlane *installConfigPool  `json:"controlPlane,omitempty"`
+	Compute      []installConfigPool `json:"compute,omitempty"`
+}
+
+type installConfigPool struct {
+	Name     string `json:"name"`
+	Replicas *int64 `json:"replicas,omitempty"`
+}
+
+// addClusterDetails fills in the capabilities, feature gates, install topology, FIPS mode and cgroup version.  Clusters
+// without one of the resources they come from, like hosted clusters without an install config, leave those fields
+// empty instead of failing.
+func addClusterDetails(ctx context.Context, configClient configclient.ConfigV1Interface, kubeClient kubernetes.Interface, clusterData *ClusterData) []error {
+	errs := []error{}
+
+	desiredVersion := ""
+	clusterVersion, err := configClient.ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		errs = append(errs, err)
+	default:
+		clusterData.Capabilities = enabledCapabilities(clusterVersion)
+		desiredVersion = clusterVersion.Status.Desired.Version
+	}
+
+	featureGate, err := configClient.FeatureGates().Get(ctx, "cluster", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		errs = append(errs, err)
+	default:
+		clusterData.FeatureSet = string(featureGate.Spec.FeatureSet)
+		clusterData.EnabledFeatureGates, clusterData.DisabledFeatureGates = featureGatesFor(featureGate, desiredVersion)
+	}
+
+	nodeConfig, err := configClient.Nodes().Get(ctx, "cluster", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		errs = append(errs, err)
+	default:
+		clusterData.CgroupVersion = string(nodeConfig.Spec.CgroupMode)
+	}
+
+	installConfigMap, err := kubeClient.CoreV1().ConfigMaps("kube-system").Get(ctx, installConfigName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		errs = append(errs, err)
+	default:
+		config := &installConfig{}
+		if err := yaml.Unmarshal([]byte(installConfigMap.Data["install-config"]), config); err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse the install config in kube-system/%s: %w", installConfigName, err))
+			break
+		}
+		clusterData.FIPS = config.FIPS
+		clusterData.InstallTopology = installTopologyFor(config)
+	}
+
+	return errs
+}
+
+func enabledCapabilities(clusterVersion *configv1.ClusterVersion) []string {
+	ret := []string{}
+	for _, capability := range clusterVersion.Status.Capabilities.EnabledCapabilities {
+		ret = append(ret, string(capability))
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// featureGatesFor returns the feature gates of the version the cluster is running, or of the first version listed
+// if the cluster version is not known.
+func featureGatesFor(featureGate *configv1.FeatureGate, version string) ([]string, []string) {
+	if len(featureGate.Status.FeatureGates) == 0 {
+		return nil, nil
+	}
+	details := featureGate.Status.FeatureGates[0]
+	for _, curr := range featureGate.Status.FeatureGates {
+		if curr.Version == version {
+			details = curr
+			break
+		}
+	}
+
+	enabled, disabled := []string{}, []string{}
+	for _, gate := range details.Enabled {
+		enabled = append(enabled, string(gate.Name))
+	}
+	for _, gate := range details.Disabled {
+		disabled = append(disabled, string(gate.Name))
+	}
+	sort.Strings(enabled)
+	sort.Strings(disabled)
+	return enabled, disabled
+}
+
+func installTopologyFor(config *installConfig) InstallTopology {
+	ret := InstallTopology{Publish: config.Publish}
+	if config.ControlPlane != nil && config.ControlPlane.Replicas != nil {
+		ret.ControlPlaneReplicas = *config.ControlPlane.Replicas
+	}
+	for _, pool := range config.Compute {
+		if pool.Replicas != nil {
+			ret.ComputeReplicas += *pool.Replicas
+		}
+	}
+	return ret
+}
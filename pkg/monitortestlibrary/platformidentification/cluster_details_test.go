@@ -0,0 +1,88 @@
+package platformidentification
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAddClusterDetails(t *testing.T) {
+	configClient := configfake.NewSimpleClientset(
+		&configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "version"},
+			Status: configv1.ClusterVersionStatus{
+				Desired: configv1.Release{Version: "4.16.1"},
+				Capabilities: configv1.ClusterVersionCapabilitiesStatus{
+					EnabledCapabilities: []configv1.ClusterVersionCapability{"marketplace", "Console"},
+				},
+			},
+		},
+		&configv1.FeatureGate{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       configv1.FeatureGateSpec{FeatureGateSelection: configv1.FeatureGateSelection{FeatureSet: configv1.TechPreviewNoUpgrade}},
+			Status: configv1.FeatureGateStatus{
+				FeatureGates: []configv1.FeatureGateDetails{
+					{Version: "4.15.9", Enabled: []configv1.FeatureGateAttributes{{Name: "Old"}}},
+					{
+						Version:  "4.16.1",
+						Enabled:  []configv1.FeatureGateAttributes{{Name: "Zeta"}, {Name: "Alpha"}},
+						Disabled: []configv1.FeatureGateAttributes{{Name: "Off"}},
+					},
+				},
+			},
+		},
+		&configv1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       configv1.NodeSpec{CgroupMode: configv1.CgroupModeV2},
+		},
+	)
+	kubeClient := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: installConfigName},
+		Data: map[string]string{"install-config": `
+fips: true
+publish: Internal
+controlPlane:
+  name: master
+  replicas: 3
+compute:
+- name: worker
+  replicas: 2
+- name: edge
+  replicas: 1
+`},
+	})
+
+	clusterData := ClusterData{}
+	if errs := addClusterDetails(context.TODO(), configClient.ConfigV1(), kubeClient, &clusterData); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	expected := ClusterData{
+		Capabilities:         []string{"Console", "marketplace"},
+		FeatureSet:           "TechPreviewNoUpgrade",
+		EnabledFeatureGates:  []string{"Alpha", "Zeta"},
+		DisabledFeatureGates: []string{"Off"},
+		InstallTopology:      InstallTopology{ControlPlaneReplicas: 3, ComputeReplicas: 3, Publish: "Internal"},
+		FIPS:                 true,
+		CgroupVersion:        "v2",
+	}
+	if !reflect.DeepEqual(expected, clusterData) {
+		t.Errorf("expected %#v, got %#v", expected, clusterData)
+	}
+}
+
+func TestAddClusterDetailsMissingResources(t *testing.T) {
+	clusterData := ClusterData{}
+	errs := addClusterDetails(context.TODO(), configfake.NewSimpleClientset().ConfigV1(), kubefake.NewSimpleClientset(), &clusterData)
+	if len(errs) > 0 {
+		t.Fatalf("expected missing resources to be tolerated, got %v", errs)
+	}
+	if !reflect.DeepEqual(ClusterData{}, clusterData) {
+		t.Errorf("expected no details, got %#v", clusterData)
+	}
+}
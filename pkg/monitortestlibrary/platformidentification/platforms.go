@@ -0,0 +1,168 @@
+package platformidentification
+
+import (
+	"fmt"
+	"sync"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// PlatformDetector returns true if the cluster described by the infrastructure runs on its platform.
+type PlatformDetector func(infrastructure *configv1.Infrastructure) bool
+
+// TopologyDetector returns the topology of the cluster described by the infrastructure, or false if it does not
+// recognize it.
+type TopologyDetector func(infrastructure *configv1.Infrastructure) (string, bool)
+
+// PlatformThresholds are allowances that differ by platform.  Monitor tests look them up instead of switching on the
+// platform name, so a new platform only has to register its own.
+type PlatformThresholds struct {
+	// TolerateSlowOSUpdateStaging flakes instead of failing when nodes are slow to stage OS updates.
+	TolerateSlowOSUpdateStaging bool
+}
+
+// Platform identifies the clusters of one platform and holds its thresholds.
+type Platform struct {
+	// Name is the platform of the JobType, which keys historical data.  Consider it immutable once jobs report it.
+	Name       string
+	Detect     PlatformDetector
+	Thresholds PlatformThresholds
+}
+
+type platformRegistry struct {
+	lock       sync.RWMutex
+	platforms  []Platform
+	topologies []TopologyDetector
+}
+
+var platforms = newPlatformRegistry()
+
+func newPlatformRegistry() *platformRegistry {
+	ret := &platformRegistry{}
+	for _, platform := range []Platform{
+		{Name: "aws", Detect: platformTypeIs(configv1.AWSPlatformType)},
+		{Name: "gcp", Detect: platformTypeIs(configv1.GCPPlatformType)},
+		{Name: "azure", Detect: platformTypeIs(configv1.AzurePlatformType)},
+		{Name: "vsphere", Detect: platformTypeIs(configv1.VSpherePlatformType)},
+		{Name: "metal", Detect: platformTypeIs(configv1.BareMetalPlatformType), Thresholds: PlatformThresholds{TolerateSlowOSUpdateStaging: true}},
+		{Name: "ovirt", Detect: platformTypeIs(configv1.OvirtPlatformType), Thresholds: PlatformThresholds{TolerateSlowOSUpdateStaging: true}},
+		{Name: "openstack", Detect: platformTypeIs(configv1.OpenStackPlatformType)},
+		{Name: "libvirt", Detect: platformTypeIs(configv1.LibvirtPlatformType)},
+	} {
+		ret.mustRegisterPlatform(platform)
+	}
+	for _, topology := range []struct {
+		mode configv1.TopologyMode
+		name string
+	}{
+		{mode: configv1.HighlyAvailableTopologyMode, name: "ha"},
+		{mode: configv1.SingleReplicaTopologyMode, name: "single"},
+		{mode: configv1.ExternalTopologyMode, name: "external"},
+	} {
+		ret.registerTopology(controlPlaneTopologyIs(topology.mode, topology.name))
+	}
+	return ret
+}
+
+// RegisterPlatform adds a platform.  Platforms registered later are detected first, so a platform can claim clusters
+// that a more generic one, like the external platform, would otherwise identify.
+func RegisterPlatform(platform Platform) error {
+	return platforms.registerPlatform(platform)
+}
+
+// RegisterTopology adds a topology detector.  Detectors registered later are tried first.
+func RegisterTopology(detector TopologyDetector) {
+	platforms.registerTopology(detector)
+}
+
+// ThresholdsFor returns the thresholds of the named platform, or the defaults for an unknown platform.
+func ThresholdsFor(platformName string) PlatformThresholds {
+	return platforms.thresholdsFor(platformName)
+}
+
+func (r *platformRegistry) registerPlatform(platform Platform) error {
+	if len(platform.Name) == 0 {
+		return fmt.Errorf("platform must have a name")
+	}
+	if platform.Detect == nil {
+		return fmt.Errorf("platform %q must have a detector", platform.Name)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, existing := range r.platforms {
+		if existing.Name == platform.Name {
+			return fmt.Errorf("platform %q is already registered", platform.Name)
+		}
+	}
+	r.platforms = append(r.platforms, platform)
+	return nil
+}
+
+func (r *platformRegistry) mustRegisterPlatform(platform Platform) {
+	if err := r.registerPlatform(platform); err != nil {
+		panic(err)
+	}
+}
+
+func (r *platformRegistry) registerTopology(detector TopologyDetector) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.topologies = append(r.topologies, detector)
+}
+
+// platformFor returns the name of the platform of the cluster, or empty if no platform recognizes it.
+func (r *platformRegistry) platformFor(infrastructure *configv1.Infrastructure) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for i := len(r.platforms) - 1; i >= 0; i-- {
+		if r.platforms[i].Detect(infrastructure) {
+			return r.platforms[i].Name
+		}
+	}
+	return ""
+}
+
+// topologyFor returns the topology of the cluster, or empty if no detector recognizes it.
+func (r *platformRegistry) topologyFor(infrastructure *configv1.Infrastructure) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for i := len(r.topologies) - 1; i >= 0; i-- {
+		if topology, ok := r.topologies[i](infrastructure); ok {
+			return topology
+		}
+	}
+	return ""
+}
+
+func (r *platformRegistry) thresholdsFor(platformName string) PlatformThresholds {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for _, platform := range r.platforms {
+		if platform.Name == platformName {
+			return platform.Thresholds
+		}
+	}
+	return PlatformThresholds{}
+}
+
+// PlatformType returns the platform type of the infrastructure, falling back to the deprecated status field that
+// clusters installed before platformStatus existed still rely on.
+func PlatformType(infrastructure *configv1.Infrastructure) configv1.PlatformType {
+	if infrastructure.Status.PlatformStatus != nil {
+		return infrastructure.Status.PlatformStatus.Type
+	}
+	return infrastructure.Status.Platform
+}
+
+func platformTypeIs(platformType configv1.PlatformType) PlatformDetector {
+	return func(infrastructure *configv1.Infrastructure) bool {
+		return PlatformType(infrastructure) == platformType
+	}
+}
+
+func controlPlaneTopologyIs(mode configv1.TopologyMode, name string) TopologyDetector {
+	return func(infrastructure *configv1.Infrastructure) (string, bool) {
+		return name, infrastructure.Status.ControlPlaneTopology == mode
+	}
+}
@@ -0,0 +1,70 @@
+package platformidentification
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func externalInfrastructure(platformName string) *configv1.Infrastructure {
+	return &configv1.Infrastructure{
+		Status: configv1.InfrastructureStatus{
+			ControlPlaneTopology: configv1.HighlyAvailableTopologyMode,
+			PlatformStatus: &configv1.PlatformStatus{
+				Type:     configv1.ExternalPlatformType,
+				External: &configv1.ExternalPlatformStatus{},
+			},
+		},
+		Spec: configv1.InfrastructureSpec{
+			PlatformSpec: configv1.PlatformSpec{
+				Type:     configv1.ExternalPlatformType,
+				External: &configv1.ExternalPlatformSpec{PlatformName: platformName},
+			},
+		},
+	}
+}
+
+func TestPlatformRegistry(t *testing.T) {
+	registry := newPlatformRegistry()
+
+	aws := &configv1.Infrastructure{Status: configv1.InfrastructureStatus{Platform: configv1.AWSPlatformType, ControlPlaneTopology: configv1.SingleReplicaTopologyMode}}
+	if platform := registry.platformFor(aws); platform != "aws" {
+		t.Errorf("expected clusters without a platform status to be identified from the deprecated platform, got %q", platform)
+	}
+	if topology := registry.topologyFor(aws); topology != "single" {
+		t.Errorf("expected single, got %q", topology)
+	}
+	if platform := registry.platformFor(externalInfrastructure("oci")); platform != "" {
+		t.Errorf("expected an unregistered platform to be unknown, got %q", platform)
+	}
+
+	isExternal := platformTypeIs(configv1.ExternalPlatformType)
+	if err := registry.registerPlatform(Platform{Name: "external", Detect: isExternal}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.registerPlatform(Platform{
+		Name: "oci",
+		Detect: func(infrastructure *configv1.Infrastructure) bool {
+			return isExternal(infrastructure) && infrastructure.Spec.PlatformSpec.External.PlatformName == "oci"
+		},
+		Thresholds: PlatformThresholds{TolerateSlowOSUpdateStaging: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if platform := registry.platformFor(externalInfrastructure("oci")); platform != "oci" {
+		t.Errorf("expected the later registered platform to claim the cluster, got %q", platform)
+	}
+	if platform := registry.platformFor(externalInfrastructure("other")); platform != "external" {
+		t.Errorf("expected the generic platform for other clusters, got %q", platform)
+	}
+	if !registry.thresholdsFor("oci").TolerateSlowOSUpdateStaging || registry.thresholdsFor("aws").TolerateSlowOSUpdateStaging {
+		t.Errorf("expected the thresholds of each platform")
+	}
+
+	if err := registry.registerPlatform(Platform{Name: "aws", Detect: isExternal}); err == nil {
+		t.Errorf("expected a platform to be registered only once")
+	}
+	if err := registry.registerPlatform(Platform{Name: "nodetector"}); err == nil {
+		t.Errorf("expected a platform without a detector to be rejected")
+	}
+}
@@ -0,0 +1,84 @@
+package platformidentification
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ClusterDataSchemaVersion is the schema version of the cluster data this code writes.  Bump it whenever the fields of
+// ClusterData change, and teach LoadClusterData to read the previous version.
+//
+//	1: the job type, network stack, cloud region and zone, cluster version history and whether masters updated.
+//	2: adds capabilities, feature gates, install topology, FIPS mode and cgroup version.
+//	3: adds the update topology.
+//	4: adds the cloud metadata.
+const ClusterDataSchemaVersion = 4
+
+var (
+	releasePattern     = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+	knownTopologies    = sets.NewString("", "ha", "single", "external")
+	knownNetworkStacks = sets.NewString("", "IPv4", "IPv6")
+	knownMasterUpdates = sets.NewString("", "Y", "N")
+)
+
+// Validate returns an error for every field that the consumers of cluster-data.json would misread.  Empty fields are
+// valid, since the cluster data is empty when it could not be collected.
+func (d ClusterData) Validate() error {
+	errs := []error{}
+	if d.SchemaVersion != ClusterDataSchemaVersion {
+		errs = append(errs, fmt.Errorf("SchemaVersion must be %d, got %d", ClusterDataSchemaVersion, d.SchemaVersion))
+	}
+	if len(d.Release) > 0 && !releasePattern.MatchString(d.Release) {
+		errs = append(errs, fmt.Errorf("Release must be major.minor, got %q", d.Release))
+	}
+	if len(d.FromRelease) > 0 && !releasePattern.MatchString(d.FromRelease) {
+		errs = append(errs, fmt.Errorf("FromRelease must be major.minor, got %q", d.FromRelease))
+	}
+	if len(d.FromRelease) > 0 && len(d.Release) == 0 {
+		errs = append(errs, fmt.Errorf("FromRelease %q is set without a Release", d.FromRelease))
+	}
+	if !knownTopologies.Has(d.Topology) {
+		errs = append(errs, fmt.Errorf("Topology must be one of %v, got %q", knownTopologies.List(), d.Topology))
+	}
+	if !knownNetworkStacks.Has(d.NetworkStack) {
+		errs = append(errs, fmt.Errorf("NetworkStack must be one of %v, got %q", knownNetworkStacks.List(), d.NetworkStack))
+	}
+	if !knownMasterUpdates.Has(d.MasterNodesUpdated) {
+		errs = append(errs, fmt.Errorf("MasterNodesUpdated must be one of %v, got %q", knownMasterUpdates.List(), d.MasterNodesUpdated))
+	}
+	if d.InstallTopology.ControlPlaneReplicas < 0 || d.InstallTopology.ComputeReplicas < 0 {
+		errs = append(errs, fmt.Errorf("InstallTopology replicas must not be negative, got %+v", d.InstallTopology))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// LoadClusterData reads cluster-data.json written by this or an older version of the code, and returns it in the
+// current schema.  Data written by a newer version is rejected instead of silently losing the fields it added.
+func LoadClusterData(content []byte) (ClusterData, error) {
+	versioned := struct {
+		SchemaVersion *int
+	}{}
+	if err := json.Unmarshal(content, &versioned); err != nil {
+		return ClusterData{}, fmt.Errorf("unable to read cluster data: %w", err)
+	}
+	// files written before the schema was versioned have no version.
+	schemaVersion := 1
+	if versioned.SchemaVersion != nil {
+		schemaVersion = *versioned.SchemaVersion
+	}
+	if schemaVersion < 1 || schemaVersion > ClusterDataSchemaVersion {
+		return ClusterData{}, fmt.Errorf("unsupported cluster data schema version %d, this code reads up to %d", schemaVersion, ClusterDataSchemaVersion)
+	}
+
+	ret := ClusterData{}
+	if err := json.Unmarshal(content, &ret); err != nil {
+		return ClusterData{}, fmt.Errorf("unable to read cluster data with schema version %d: %w", schemaVersion, err)
+	}
+	// versions 2 through 4 only added fields, which are left empty for older versions.
+	ret.SchemaVersion = ClusterDataSchemaVersion
+	return ret, nil
+}
@@ -0,0 +1,105 @@
+package platformidentification
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClusterDataValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterData ClusterData
+		expectedErr string
+	}{
+		{
+			name:        "empty",
+			clusterData: ClusterData{SchemaVersion: ClusterDataSchemaVersion},
+		},
+		{
+			name: "valid",
+			clusterData: ClusterData{
+				SchemaVersion:      ClusterDataSchemaVersion,
+				JobType:            JobType{Release: "4.16", FromRelease: "4.15", Platform: "aws", Topology: "ha"},
+				NetworkStack:       "IPv4",
+				MasterNodesUpdated: "Y",
+			},
+		},
+		{
+			name:        "unversioned",
+			clusterData: ClusterData{},
+			expectedErr: "SchemaVersion must be 4",
+		},
+		{
+			name:        "full release version",
+			clusterData: ClusterData{SchemaVersion: ClusterDataSchemaVersion, JobType: JobType{Release: "4.16.1"}},
+			expectedErr: `Release must be major.minor, got "4.16.1"`,
+		},
+		{
+			name:        "from release without release",
+			clusterData: ClusterData{SchemaVersion: ClusterDataSchemaVersion, JobType: JobType{FromRelease: "4.15"}},
+			expectedErr: "set without a Release",
+		},
+		{
+			name:        "unknown master update",
+			clusterData: ClusterData{SchemaVersion: ClusterDataSchemaVersion, MasterNodesUpdated: "yes"},
+			expectedErr: "MasterNodesUpdated must be one of",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.clusterData.Validate()
+			switch {
+			case len(tt.expectedErr) == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case len(tt.expectedErr) > 0 && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)):
+				t.Errorf("expected an error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadClusterData(t *testing.T) {
+	t.Run("unversioned", func(t *testing.T) {
+		clusterData, err := LoadClusterData([]byte(`{"Release": "4.15", "Platform": "gcp", "NetworkStack": "IPv4", "MasterNodesUpdated": "N"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := ClusterData{
+			SchemaVersion:      ClusterDataSchemaVersion,
+			JobType:            JobType{Release: "4.15", Platform: "gcp"},
+			NetworkStack:       "IPv4",
+			MasterNodesUpdated: "N",
+		}
+		if !reflect.DeepEqual(expected, clusterData) {
+			t.Errorf("expected %#v, got %#v", expected, clusterData)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		expected := ClusterData{
+			SchemaVersion: ClusterDataSchemaVersion,
+			JobType:       JobType{Release: "4.16", Platform: "aws"},
+			Capabilities:  []string{"Console"},
+			FIPS:          true,
+		}
+		content, err := json.Marshal(expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clusterData, err := LoadClusterData(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expected, clusterData) {
+			t.Errorf("expected %#v, got %#v", expected, clusterData)
+		}
+	})
+
+	t.Run("newer schema", func(t *testing.T) {
+		if _, err := LoadClusterData([]byte(`{"SchemaVersion": 99}`)); err == nil {
+			t.Errorf("expected a newer schema to be rejected")
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	machineclient "github.com/openshift/client-go/machine/clientset/versioned/typed/machine/v1beta1"
 	exutil "github.com/openshift/origin/test/extended/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -30,12 +31,29 @@ type JobType struct {
 // can be added to as needed
 // to collect more data
 type ClusterData struct {
+	// SchemaVersion is the ClusterDataSchemaVersion the data was written with.
+	SchemaVersion         int
 	JobType               `json:",inline"`
 	NetworkStack          string
 	CloudRegion           string
 	CloudZone             string
 	ClusterVersionHistory []string
 	MasterNodesUpdated    string
+	// Capabilities are the enabled cluster capabilities, sorted.
+	Capabilities []string
+	// FeatureSet is the feature set of the cluster, empty for the default one.
+	FeatureSet string
+	// EnabledFeatureGates and DisabledFeatureGates are the feature gates of the version the cluster runs, sorted.
+	EnabledFeatureGates  []string
+	DisabledFeatureGates []string
+	InstallTopology      InstallTopology
+	FIPS                 bool
+	// CgroupVersion is the cgroup mode configured for nodes, empty when nodes keep their own default.
+	CgroupVersion string
+	// UpdateTopology is which node pools updated during the run.  MasterNodesUpdated is derived from it.
+	UpdateTopology UpdateTopology
+	// CloudMetadata is the regions, zones and instance types of the machines.
+	CloudMetadata CloudMetadata
 }
 
 const (
@@ -67,7 +85,7 @@ func BuildClusterData(ctx context.Context, clientConfig *rest.Config) (ClusterDa
 		errors = append(errors, err)
 	}
 
-	clusterData := ClusterData{}
+	clusterData := ClusterData{SchemaVersion: ClusterDataSchemaVersion}
 
 	if jobType != nil {
 		clusterData.Topology = jobType.Topology
@@ -116,6 +134,14 @@ func BuildClusterData(ctx context.Context, clientConfig *rest.Config) (ClusterDa
 		clusterData.CloudRegion = kNodes.Items[0].Labels[`topology.kubernetes.io/region`]
 		clusterData.CloudZone = kNodes.Items[0].Labels[`topology.kubernetes.io/zone`]
 	}
+	errors = append(errors, addClusterDetails(ctx, configClient, kubeClient, &clusterData)...)
+
+	machineClient, err := machineclient.NewForConfig(clientConfig)
+	if err != nil {
+		errors = append(errors, err)
+		return clusterData, &errors
+	}
+	errors = append(errors, addCloudMetadata(ctx, machineClient, &clusterData)...)
 	if len(errors) == 0 {
 		return clusterData, nil
 	}
@@ -178,25 +204,7 @@ func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error
 		fromRelease = VersionFromHistory(clusterVersion.Status.History[1])
 	}
 
-	platform := ""
-	switch infrastructure.Status.PlatformStatus.Type {
-	case configv1.AWSPlatformType:
-		platform = "aws"
-	case configv1.GCPPlatformType:
-		platform = "gcp"
-	case configv1.AzurePlatformType:
-		platform = "azure"
-	case configv1.VSpherePlatformType:
-		platform = "vsphere"
-	case configv1.BareMetalPlatformType:
-		platform = "metal"
-	case configv1.OvirtPlatformType:
-		platform = "ovirt"
-	case configv1.OpenStackPlatformType:
-		platform = "openstack"
-	case configv1.LibvirtPlatformType:
-		platform = "libvirt"
-	}
+	platform := platforms.platformFor(infrastructure)
 
 	networkType := ""
 	switch network.Status.NetworkType {
@@ -206,15 +214,7 @@ func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error
 		networkType = "ovn"
 	}
 
-	topology := ""
-	switch infrastructure.Status.ControlPlaneTopology {
-	case configv1.HighlyAvailableTopologyMode:
-		topology = "ha"
-	case configv1.SingleReplicaTopologyMode:
-		topology = "single"
-	case configv1.ExternalTopologyMode:
-		topology = "external"
-	}
+	topology := platforms.topologyFor(infrastructure)
 
 	return &JobType{
 		Release:      release,
@@ -0,0 +1,189 @@
+package platformidentification
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const masterPool = "master"
+
+// NodePoolUpdate is how one pool of nodes updated during the run.
+type NodePoolUpdate struct {
+	Pool string
+	// Nodes is how many nodes of the pool updated.
+	Nodes int
+	// Reboots is how many times nodes of the pool rebooted to update.
+	Reboots int
+}
+
+// UpdateTopology is which nodes updated during the run and what it cost the control plane.
+type UpdateTopology struct {
+	// UpdatedPools are the pools with at least one node that updated, by pool name.
+	UpdatedPools []NodePoolUpdate
+	// ControlPlaneUnavailableSeconds is how long at least one control plane node was not ready, in total.
+	ControlPlaneUnavailableSeconds float64
+}
+
+// MasterNodesUpdated is the Y or N of ClusterData.MasterNodesUpdated.
+func (t UpdateTopology) MasterNodesUpdated() string {
+	for _, pool := range t.UpdatedPools {
+		if pool.Pool == masterPool {
+			return "Y"
+		}
+	}
+	return "N"
+}
+
+type poolUpdate struct {
+	NodePoolUpdate
+	nodes    map[string]bool
+	from, to time.Time
+}
+
+type updateTopologyAnalysis struct {
+	pools map[string]*poolUpdate
+	// controlPlaneUnavailable are the merged windows during which at least one control plane node was not ready.
+	controlPlaneUnavailable []timeWindow
+}
+
+type timeWindow struct {
+	from, to time.Time
+}
+
+// AnalyzeUpdateTopology reads the node update and not ready intervals of the node lifecycle.
+func AnalyzeUpdateTopology(intervals monitorapi.Intervals) UpdateTopology {
+	analysis := analyzeUpdateTopology(intervals)
+
+	ret := UpdateTopology{UpdatedPools: []NodePoolUpdate{}}
+	for _, pool := range analysis.sortedPools() {
+		ret.UpdatedPools = append(ret.UpdatedPools, pool.NodePoolUpdate)
+	}
+	for _, window := range analysis.controlPlaneUnavailable {
+		ret.ControlPlaneUnavailableSeconds += window.to.Sub(window.from).Seconds()
+	}
+	return ret
+}
+
+// UpdateTopologyIntervals returns an interval covering the update of every pool, and one for every window during
+// which at least one control plane node was not ready.
+func UpdateTopologyIntervals(intervals monitorapi.Intervals) monitorapi.Intervals {
+	analysis := analyzeUpdateTopology(intervals)
+
+	ret := monitorapi.Intervals{}
+	for _, pool := range analysis.sortedPools() {
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceUpdateTopology, monitorapi.Info).
+			Locator(monitorapi.NewLocator().MachineConfigPool(pool.Pool)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodePoolUpdatedReason).
+				Constructed(monitorapi.ConstructionOwnerUpdateTopology).
+				WithAnnotation(monitorapi.AnnotationCount, fmt.Sprintf("%d", pool.Nodes)).
+				HumanMessagef("%d nodes updated with %d reboots", pool.Nodes, pool.Reboots)).
+			Display().
+			Build(pool.from, pool.to))
+	}
+	for _, window := range analysis.controlPlaneUnavailable {
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceUpdateTopology, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().MachineConfigPool(masterPool)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.ControlPlaneUnavailableReason).
+				Constructed(monitorapi.ConstructionOwnerUpdateTopology).
+				HumanMessage("at least one control plane node was not ready")).
+			Display().
+			Build(window.from, window.to))
+	}
+	return ret
+}
+
+func analyzeUpdateTopology(intervals monitorapi.Intervals) *updateTopologyAnalysis {
+	analysis := &updateTopologyAnalysis{pools: map[string]*poolUpdate{}}
+	notReady := []timeWindow{}
+	for _, interval := range intervals {
+		node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+		if len(node) == 0 {
+			continue
+		}
+		roles := monitorapi.GetNodeRoles(interval)
+		switch {
+		case monitorapi.NodeUpdate(interval):
+			pool := analysis.pool(poolFromRoles(roles))
+			pool.nodes[node] = true
+			pool.Nodes = len(pool.nodes)
+			if interval.Message.Annotations[monitorapi.AnnotationPhase] == "Reboot" {
+				pool.Reboots++
+			}
+			if pool.from.IsZero() || interval.From.Before(pool.from) {
+				pool.from = interval.From
+			}
+			if interval.To.After(pool.to) {
+				pool.to = interval.To
+			}
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeNotReadyReason:
+			if poolFromRoles(roles) == masterPool && interval.To.After(interval.From) {
+				notReady = append(notReady, timeWindow{from: interval.From, to: interval.To})
+			}
+		}
+	}
+	analysis.controlPlaneUnavailable = mergeWindows(notReady)
+	return analysis
+}
+
+func (a *updateTopologyAnalysis) pool(name string) *poolUpdate {
+	pool, ok := a.pools[name]
+	if !ok {
+		pool = &poolUpdate{NodePoolUpdate: NodePoolUpdate{Pool: name}, nodes: map[string]bool{}}
+		a.pools[name] = pool
+	}
+	return pool
+}
+
+func (a *updateTopologyAnalysis) sortedPools() []*poolUpdate {
+	ret := []*poolUpdate{}
+	for _, pool := range a.pools {
+		ret = append(ret, pool)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Pool < ret[j].Pool
+	})
+	return ret
+}
+
+// poolFromRoles names the pool of a node the way the machine config operator does for the default pools: control
+// plane nodes are in master, nodes with a role besides worker are in the pool of that role, and the rest in worker.
+func poolFromRoles(roles string) string {
+	other := ""
+	for _, role := range strings.Split(roles, ",") {
+		switch role {
+		case masterPool, "control-plane":
+			return masterPool
+		case "worker", "":
+		default:
+			if len(other) == 0 {
+				other = role
+			}
+		}
+	}
+	if len(other) > 0 {
+		return other
+	}
+	return "worker"
+}
+
+// mergeWindows returns the union of the windows, ordered.
+func mergeWindows(windows []timeWindow) []timeWindow {
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].from.Before(windows[j].from)
+	})
+	ret := []timeWindow{}
+	for _, window := range windows {
+		if len(ret) > 0 && !window.from.After(ret[len(ret)-1].to) {
+			if window.to.After(ret[len(ret)-1].to) {
+				ret[len(ret)-1].to = window.to
+			}
+			continue
+		}
+		ret = append(ret, window)
+	}
+	return ret
+}
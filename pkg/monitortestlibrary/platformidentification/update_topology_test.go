@@ -0,0 +1,64 @@
+package platformidentification
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestAnalyzeUpdateTopology(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nodeInterval := func(node, roles string, reason monitorapi.IntervalReason, phase string, from, to time.Duration) monitorapi.Interval {
+		message := monitorapi.NewMessage().Reason(reason).HumanMessage("node").WithAnnotation(monitorapi.AnnotationRoles, roles)
+		if len(phase) > 0 {
+			message = message.WithAnnotation(monitorapi.AnnotationPhase, phase)
+		}
+		return monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName(node)).
+			Message(message).
+			Build(start.Add(from), start.Add(to))
+	}
+	intervals := monitorapi.Intervals{
+		nodeInterval("master-0", "control-plane,master", monitorapi.NodeUpdateReason, "Update", 0, 10*time.Minute),
+		nodeInterval("master-0", "control-plane,master", monitorapi.NodeUpdateReason, "Reboot", 5*time.Minute, 8*time.Minute),
+		nodeInterval("master-1", "control-plane,master", monitorapi.NodeUpdateReason, "Update", 10*time.Minute, 20*time.Minute),
+		nodeInterval("master-1", "control-plane,master", monitorapi.NodeUpdateReason, "Reboot", 15*time.Minute, 18*time.Minute),
+		nodeInterval("infra-0", "infra,worker", monitorapi.NodeUpdateReason, "Update", 30*time.Minute, 40*time.Minute),
+		// overlapping not ready windows of the control plane count once.
+		nodeInterval("master-0", "control-plane,master", monitorapi.NodeNotReadyReason, "", 5*time.Minute, 8*time.Minute),
+		nodeInterval("master-1", "control-plane,master", monitorapi.NodeNotReadyReason, "", 7*time.Minute, 9*time.Minute),
+		nodeInterval("master-1", "control-plane,master", monitorapi.NodeNotReadyReason, "", 15*time.Minute, 16*time.Minute),
+		nodeInterval("worker-0", "worker", monitorapi.NodeNotReadyReason, "", 30*time.Minute, 40*time.Minute),
+	}
+
+	topology := AnalyzeUpdateTopology(intervals)
+	expected := UpdateTopology{
+		UpdatedPools: []NodePoolUpdate{
+			{Pool: "infra", Nodes: 1},
+			{Pool: "master", Nodes: 2, Reboots: 2},
+		},
+		ControlPlaneUnavailableSeconds: (5 * time.Minute).Seconds(),
+	}
+	if !reflect.DeepEqual(expected, topology) {
+		t.Errorf("expected %#v, got %#v", expected, topology)
+	}
+	if topology.MasterNodesUpdated() != "Y" {
+		t.Errorf("expected master nodes to be updated")
+	}
+	if AnalyzeUpdateTopology(nil).MasterNodesUpdated() != "N" {
+		t.Errorf("expected no master nodes to be updated without intervals")
+	}
+
+	computed := UpdateTopologyIntervals(intervals)
+	if len(computed) != 4 {
+		t.Fatalf("expected an interval per pool and per control plane unavailability, got %v", computed)
+	}
+	if master := computed[1]; master.Message.Reason != monitorapi.NodePoolUpdatedReason || !master.From.Equal(start) || !master.To.Equal(start.Add(20*time.Minute)) {
+		t.Errorf("unexpected master pool interval %v", master)
+	}
+	if unavailable := computed[2]; unavailable.Message.Reason != monitorapi.ControlPlaneUnavailableReason || !unavailable.From.Equal(start.Add(5*time.Minute)) || !unavailable.To.Equal(start.Add(9*time.Minute)) {
+		t.Errorf("unexpected control plane unavailability %v", unavailable)
+	}
+}
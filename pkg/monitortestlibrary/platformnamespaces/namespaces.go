@@ -0,0 +1,10 @@
+package platformnamespaces
+
+import "strings"
+
+// IsPlatformNamespace matches the namespaces our own components run in: openshift, openshift-*, kube-*, and default.
+// The dynamically generated openshift-must-gather-* namespaces are not platform namespaces.
+func IsPlatformNamespace(namespace string) bool {
+	isPermanentOpenShiftNamespace := (namespace == "openshift" || strings.HasPrefix(namespace, "openshift-")) && !strings.HasPrefix(namespace, "openshift-must-gather-")
+	return strings.HasPrefix(namespace, "kube-") || namespace == "default" || isPermanentOpenShiftNamespace
+}
@@ -0,0 +1,21 @@
+package platformnamespaces
+
+import "testing"
+
+func TestIsPlatformNamespace(t *testing.T) {
+	tests := map[string]bool{
+		"openshift":                   true,
+		"openshift-etcd":              true,
+		"kube-system":                 true,
+		"default":                     true,
+		"openshift-must-gather-x7k2p": false,
+		"e2e-test-pod-security-ab12c": false,
+		"openshiftish":                false,
+		"kubernetes-dashboard":        false,
+	}
+	for namespace, expected := range tests {
+		if actual := IsPlatformNamespace(namespace); actual != expected {
+			t.Errorf("%q: expected %v, got %v", namespace, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package prometheusaccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/test/library/metrics"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ErrMonitoringNotInstalled is returned when the cluster has no in-cluster monitoring stack to query.  Monitor tests
+// usually turn it into a NotSupportedError.
+var ErrMonitoringNotInstalled = errors.New("openshift-monitoring is not installed")
+
+// NewPrometheusClient returns a client for the in-cluster thanos querier.
+func NewPrometheusClient(ctx context.Context, restConfig *rest.Config) (prometheusv1.API, error) {
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	routeClient, err := routeclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-monitoring", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, ErrMonitoringNotInstalled
+	case err != nil:
+		return nil, err
+	}
+
+	return metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
+}
+
+// QueryRange runs a range query that must return a matrix.  Warnings are logged, not returned.
+func QueryRange(ctx context.Context, client prometheusv1.API, query string, timeRange prometheusv1.Range) (prometheustypes.Matrix, error) {
+	result, warningsForQuery, err := client.QueryRange(ctx, query, timeRange)
+	if err != nil {
+		return nil, fmt.Errorf("query %q failed: %w", query, err)
+	}
+	if len(warningsForQuery) > 0 {
+		fmt.Printf("#### warnings for %q\n\t%v\n", query, strings.Join(warningsForQuery, "\n\t"))
+	}
+	matrix, ok := result.(prometheustypes.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("query %q: expecting a matrix type, got %q", query, result.Type().String())
+	}
+	return matrix, nil
+}
+
+// Window is a span of time in which every sample of a series matched.
+type Window struct {
+	From time.Time
+	To   time.Time
+	// Peak is the largest value seen in the window.
+	Peak float64
+}
+
+// Windows returns the spans of time in which consecutive samples match.  Each matching sample is assumed to hold
+// for one step, and missing samples end a window, because a series without samples says nothing about the state.
+func Windows(samples []prometheustypes.SamplePair, step time.Duration, matches func(value float64) bool) []Window {
+	ret := []Window{}
+	var current *Window
+	var lastSample time.Time
+	for _, sample := range samples {
+		sampleTime := sample.Timestamp.Time().UTC()
+		value := float64(sample.Value)
+		if current != nil && (!matches(value) || sampleTime.Sub(lastSample) > step) {
+			ret = append(ret, *current)
+			current = nil
+		}
+		lastSample = sampleTime
+		if !matches(value) {
+			continue
+		}
+		if current == nil {
+			current = &Window{From: sampleTime, Peak: value}
+		}
+		current.To = sampleTime.Add(step)
+		if value > current.Peak {
+			current.Peak = value
+		}
+	}
+	if current != nil {
+		ret = append(ret, *current)
+	}
+	return ret
+}
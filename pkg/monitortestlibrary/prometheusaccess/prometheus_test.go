@@ -0,0 +1,56 @@
+package prometheusaccess
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+)
+
+func TestWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	step := 30 * time.Second
+	sample := func(offset time.Duration, value float64) prometheustypes.SamplePair {
+		return prometheustypes.SamplePair{
+			Timestamp: prometheustypes.TimeFromUnixNano(start.Add(offset).UnixNano()),
+			Value:     prometheustypes.SampleValue(value),
+		}
+	}
+	aboveOne := func(value float64) bool { return value > 1 }
+
+	tests := []struct {
+		name     string
+		samples  []prometheustypes.SamplePair
+		expected []Window
+	}{
+		{
+			name:     "nothing matches",
+			samples:  []prometheustypes.SamplePair{sample(0, 0), sample(step, 1)},
+			expected: []Window{},
+		},
+		{
+			name:    "one window with a peak",
+			samples: []prometheustypes.SamplePair{sample(0, 0), sample(step, 2), sample(2*step, 5), sample(3*step, 0)},
+			expected: []Window{
+				{From: start.Add(step), To: start.Add(3 * step), Peak: 5},
+			},
+		},
+		{
+			name:    "missing samples end the window",
+			samples: []prometheustypes.SamplePair{sample(0, 2), sample(step, 2), sample(5*step, 3)},
+			expected: []Window{
+				{From: start, To: start.Add(2 * step), Peak: 2},
+				{From: start.Add(5 * step), To: start.Add(6 * step), Peak: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Windows(tt.samples, step, aboveOne)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
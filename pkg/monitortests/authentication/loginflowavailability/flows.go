@@ -0,0 +1,205 @@
+package loginflowavailability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// loginFlow is a sequence of requests a user's browser or CLI makes to log in, probed end to end through a route.
+type loginFlow struct {
+	name           string
+	owner          string
+	routeNamespace string
+	routeName      string
+	// capability is the cluster capability the flow needs, if any.
+	capability string
+	// availabilitySLO is the fraction of the run the whole flow must succeed for.  Every step goes through ingress, so
+	// the flows are held to the SLO of the routes they use.
+	availabilitySLO float64
+	steps           []flowStep
+}
+
+// flowStep makes one request of the flow.  The client does not follow redirects, so that every hop is checked.
+type flowStep struct {
+	name string
+	run  func(ctx context.Context, client *http.Client, host string) error
+}
+
+var (
+	// oauthFlow is the start of `oc login`.  The authorize endpoint must challenge for credentials and the token
+	// endpoint must reject a bogus code.  Issuing a token needs an identity provider, which CI clusters do not have,
+	// but both requests go through the whole handler chain of the oauth server.
+	oauthFlow = loginFlow{
+		name:            "oauth",
+		owner:           "sig-auth",
+		routeNamespace:  "openshift-authentication",
+		routeName:       "oauth-openshift",
+		availabilitySLO: 0.995,
+		steps: []flowStep{
+			{name: "authorize challenge", run: checkAuthorizeChallenge},
+			{name: "token endpoint", run: checkTokenEndpoint},
+		},
+	}
+	// consoleFlow is a browser opening the console.  The console must send the browser to the oauth server to log in,
+	// serve its index page, and serve the static assets the index page loads.
+	consoleFlow = loginFlow{
+		name:            "console",
+		owner:           "sig-network-edge",
+		routeNamespace:  "openshift-console",
+		routeName:       "console",
+		capability:      "Console",
+		availabilitySLO: 0.995,
+		steps: []flowStep{
+			{name: "login redirect", run: checkConsoleLoginRedirect},
+			{name: "index and static asset", run: checkConsoleStaticAsset},
+		},
+	}
+)
+
+var loginFlows = []loginFlow{oauthFlow, consoleFlow}
+
+// backendName is the disruption backend the flow is reported under.  It is distinct from the ingress-to-oauth-server
+// and ingress-to-console backends, so that a broken login is told apart from an unreachable route.
+func (f loginFlow) backendName() string {
+	return fmt.Sprintf("login-flow-%s-%v-connections", f.name, monitorapi.NewConnectionType)
+}
+
+func (f loginFlow) sloTestName() string {
+	return fmt.Sprintf("[%s] disruption/login-flow-%s should meet the login availability SLO", f.owner, f.name)
+}
+
+// run makes every request of the flow in turn, stopping at the first that fails.
+func (f loginFlow) run(ctx context.Context, client *http.Client, host string) error {
+	for _, step := range f.steps {
+		if err := step.run(ctx, client, host); err != nil {
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+	}
+	return nil
+}
+
+const challengingClientID = "openshift-challenging-client"
+
+func checkAuthorizeChallenge(ctx context.Context, client *http.Client, host string) error {
+	query := url.Values{"client_id": {challengingClientID}, "response_type": {"token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+"/oauth/authorize?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	// the oauth server only challenges requests that cannot come from a browser form.
+	req.Header.Set("X-CSRF-Token", "1")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("expected %d, got %d", http.StatusUnauthorized, resp.StatusCode)
+	}
+	if challenge := resp.Header.Get("WWW-Authenticate"); !strings.HasPrefix(challenge, "Basic") {
+		return fmt.Errorf("expected a Basic challenge, got %q", challenge)
+	}
+	return nil
+}
+
+func checkTokenEndpoint(ctx context.Context, client *http.Client, host string) error {
+	form := url.Values{"grant_type": {"authorization_code"}, "code": {"login-flow-probe"}, "client_id": {challengingClientID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("expected the code to be rejected, got %d", resp.StatusCode)
+	}
+	tokenError := struct {
+		Error string `json:"error"`
+	}{}
+	if err := json.Unmarshal(body, &tokenError); err != nil || len(tokenError.Error) == 0 {
+		return fmt.Errorf("expected an oauth error, got %d: %.100q", resp.StatusCode, body)
+	}
+	return nil
+}
+
+func checkConsoleLoginRedirect(ctx context.Context, client *http.Client, host string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+"/auth/login", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return fmt.Errorf("expected a redirect, got %d", resp.StatusCode)
+	}
+	if location := resp.Header.Get("Location"); !strings.Contains(location, "/oauth/authorize") {
+		return fmt.Errorf("expected a redirect to the oauth server, got %q", location)
+	}
+	return nil
+}
+
+// staticAssetRegex finds the scripts and stylesheets the console index page loads.
+var staticAssetRegex = regexp.MustCompile(`(?:src|href)="(/?static/[^"]+)"`)
+
+func checkConsoleStaticAsset(ctx context.Context, client *http.Client, host string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected the index page, got %d", resp.StatusCode)
+	}
+	match := staticAssetRegex.FindSubmatch(body)
+	if match == nil {
+		return fmt.Errorf("the index page does not load any static asset")
+	}
+
+	asset := "/" + strings.TrimPrefix(string(match[1]), "/")
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, host+asset, nil)
+	if err != nil {
+		return err
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected %s, got %d", asset, resp.StatusCode)
+	}
+	return nil
+}
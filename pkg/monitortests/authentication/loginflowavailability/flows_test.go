@@ -0,0 +1,105 @@
+package loginflowavailability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func noRedirectClient() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func fakeOAuthServer(tokenStatus int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/authorize", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="openshift"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(tokenStatus)
+		fmt.Fprint(w, `{"error":"invalid_grant","error_description":"The provided authorization grant is invalid"}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func fakeConsole(assetStatus int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://oauth-openshift.apps.example.com/oauth/authorize?client_id=console", http.StatusSeeOther)
+	})
+	mux.HandleFunc("/static/main-chunk.min.js", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(assetStatus)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><script src="/static/main-chunk.min.js"></script></head></html>`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestLoginFlows(t *testing.T) {
+	tests := []struct {
+		name    string
+		flow    loginFlow
+		server  *httptest.Server
+		wantErr bool
+	}{
+		{name: "oauth serving", flow: oauthFlow, server: fakeOAuthServer(http.StatusBadRequest)},
+		{name: "oauth token endpoint failing", flow: oauthFlow, server: fakeOAuthServer(http.StatusServiceUnavailable), wantErr: true},
+		{name: "console serving", flow: consoleFlow, server: fakeConsole(http.StatusOK)},
+		{name: "console static assets missing", flow: consoleFlow, server: fakeConsole(http.StatusNotFound), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer tt.server.Close()
+			err := tt.flow.run(context.Background(), noRedirectClient(), tt.server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestProberObserve(t *testing.T) {
+	recorder := monitor.NewRecorder()
+	p := newProber(consoleFlow, nil, recorder, clock.RealClock{})
+	p.observe(start, nil)
+	p.observe(start.Add(5*time.Second), nil)
+	p.observe(start.Add(10*time.Second), errors.New("login redirect: expected a redirect, got 500"))
+	p.observe(start.Add(15*time.Second), errors.New("login redirect: expected a redirect, got 500"))
+	p.observe(start.Add(20*time.Second), nil)
+	recorder.EndInterval(p.previousIntervalID, start.Add(25*time.Second))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 3 {
+		t.Fatalf("expected intervals for the success, the failure and the recovery, got %v", intervals)
+	}
+	failure := intervals[1]
+	if failure.Level != monitorapi.Error || !failure.From.Equal(start.Add(10*time.Second)) || !failure.To.Equal(start.Add(20*time.Second)) {
+		t.Errorf("expected the failure to last until the flow recovered, got %v", failure)
+	}
+
+	junits := evaluateSLO([]loginFlow{consoleFlow}, intervals, start, start.Add(25*time.Second))
+	if len(junits) != 1 || junits[0].FailureOutput == nil {
+		t.Fatalf("expected the console to miss its SLO, got %v", junits)
+	}
+	if junits[0].Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", junits[0].Details.Severity)
+	}
+}
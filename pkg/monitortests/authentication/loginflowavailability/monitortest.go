@@ -0,0 +1,179 @@
+package loginflowavailability
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type loginFlowAvailability struct {
+	notSupportedReason error
+
+	flows  []loginFlow
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+
+	beginning, end time.Time
+	clock          clock.PassiveClock
+}
+
+// NewLoginFlowAvailability logs in through the oauth and console routes the way users do, and holds the login flows
+// to their availability SLO apart from the reachability of the routes.
+func NewLoginFlowAvailability() monitortestframework.MonitorTest {
+	return &loginFlowAvailability{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &loginFlowAvailability{}
+
+func (w *loginFlowAvailability) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *loginFlowAvailability) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, flow := range loginFlows {
+		testNames = append(testNames, flow.sloTestName())
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *loginFlowAvailability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+
+	capabilities, err := enabledCapabilities(ctx, adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	routeClient, err := routeclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	for _, flow := range loginFlows {
+		if len(flow.capability) > 0 && !capabilities[flow.capability] {
+			continue
+		}
+		_, err := routeClient.RouteV1().Routes(flow.routeNamespace).Get(ctx, flow.routeName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return err
+		}
+		w.flows = append(w.flows, flow)
+	}
+	if len(w.flows) == 0 {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the cluster has neither the oauth nor the console route"}
+		return w.notSupportedReason
+	}
+
+	probeCtx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	for _, flow := range w.flows {
+		p := newProber(flow, backenddisruption.NewRouteHostGetter(adminRESTConfig, flow.routeNamespace, flow.routeName), recorder, w.clock)
+		w.done.Add(1)
+		go func() {
+			defer w.done.Done()
+			p.run(probeCtx)
+		}()
+	}
+	return nil
+}
+
+// enabledCapabilities returns the capabilities of the cluster.  Clusters without a cluster version have every
+// capability.
+func enabledCapabilities(ctx context.Context, adminRESTConfig *rest.Config) (map[string]bool, error) {
+	configAvailable, err := exutil.DoesApiResourceExist(adminRESTConfig, "clusterversions", "config.openshift.io")
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]bool{}
+	if !configAvailable {
+		for _, flow := range loginFlows {
+			ret[flow.capability] = true
+		}
+		return ret, nil
+	}
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	clusterVersion, err := configClient.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, capability := range clusterVersion.Status.Capabilities.EnabledCapabilities {
+		ret[string(capability)] = true
+	}
+	return ret, nil
+}
+
+func (w *loginFlowAvailability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	w.beginning, w.end = beginning, end
+
+	// the probers record directly, they only have to stop.
+	w.cancel()
+	w.done.Wait()
+	return nil, nil, nil
+}
+
+func (w *loginFlowAvailability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *loginFlowAvailability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateSLO(w.flows, finalIntervals, w.beginning, w.end), nil
+}
+
+func (w *loginFlowAvailability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *loginFlowAvailability) Cleanup(ctx context.Context) error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	return w.notSupportedReason
+}
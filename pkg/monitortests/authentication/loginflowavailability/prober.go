@@ -0,0 +1,127 @@
+package loginflowavailability
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// probeInterval is how often a flow is run.  A flow is several requests, so it runs less often than the single
+	// request disruption samplers.
+	probeInterval = 5 * time.Second
+	probeTimeout  = 15 * time.Second
+)
+
+// prober runs a login flow every probeInterval and records when it starts and stops failing, the way the disruption
+// samplers do.
+type prober struct {
+	flow       loginFlow
+	hostGetter backenddisruption.HostGetter
+	recorder   monitorapi.RecorderWriter
+	clock      clock.PassiveClock
+
+	previousError      error
+	previousIntervalID int
+	lastProbe          time.Time
+}
+
+func newProber(flow loginFlow, hostGetter backenddisruption.HostGetter, recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *prober {
+	return &prober{
+		flow:               flow,
+		hostGetter:         hostGetter,
+		recorder:           recorder,
+		clock:              clock,
+		previousIntervalID: -1,
+	}
+}
+
+func (p *prober) locator() monitorapi.Locator {
+	return monitorapi.NewLocator().LocateDisruptionCheck(p.flow.backendName(), p.flow.backendName(), monitorapi.NewConnectionType)
+}
+
+// run probes until the context is done, then closes the open interval.
+func (p *prober) run(ctx context.Context) {
+	client := &http.Client{
+		Timeout: probeTimeout,
+		Transport: &http.Transport{
+			// every flow starts over on new connections, like a user logging in.
+			DisableKeepAlives: true,
+			// routes are served with the certificate of the ingress operator, which the test does not trust.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			Proxy:           http.ProxyFromEnvironment,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	defer func() {
+		if p.previousIntervalID != -1 {
+			p.recorder.EndInterval(p.previousIntervalID, p.lastProbe.Add(probeInterval))
+		}
+	}()
+
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+	for {
+		start := p.clock.Now()
+		host, err := p.hostGetter.GetHost()
+		if err == nil {
+			err = p.flow.run(ctx, client, host)
+		}
+		// a flow cut short by the end of the run did not fail.
+		if ctx.Err() != nil {
+			return
+		}
+		p.observe(start, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// observe records the result of the probe started at the time.  Like the disruption samplers, a new interval is
+// started whenever the flow starts or stops failing or fails differently.
+func (p *prober) observe(at time.Time, err error) {
+	first := p.lastProbe.IsZero()
+	p.lastProbe = at
+	previousError := p.previousError
+	p.previousError = err
+
+	switch {
+	case err == nil && previousError == nil && !first:
+		return
+	case err != nil && previousError != nil && err.Error() == previousError.Error():
+		return
+	}
+
+	if p.previousIntervalID != -1 {
+		p.recorder.EndInterval(p.previousIntervalID, at)
+	}
+	if err == nil {
+		p.previousIntervalID = p.recorder.StartInterval(
+			monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Info).
+				Locator(p.locator()).
+				Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionEndedEventReason).
+					HumanMessagef("%s login flow succeeded", p.flow.name)).
+				Build(at, time.Time{}))
+		return
+	}
+	p.previousIntervalID = p.recorder.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(p.locator()).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).
+				HumanMessagef("%s login flow failed: %v", p.flow.name, err)).
+			Display().
+			Build(at, time.Time{}))
+}
@@ -0,0 +1,70 @@
+package loginflowavailability
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var sloTemplate = junitfailure.MustParseTemplate("login-flow-availability-slo",
+	`the {{.Fields.flow}} login flow succeeded for {{printf "%.3f" .Threshold.Observed}}% of the run, below the {{printf "%.3f" .Threshold.Limit}}% SLO.  It failed for {{.Fields.disruption}} of {{.Fields.duration}}.
+{{.IntervalList}}`)
+
+// evaluateSLO returns one test per flow.  A flow without any samples is skipped, the prober logs explain why.
+func evaluateSLO(flows []loginFlow, finalIntervals monitorapi.Intervals, beginning, end time.Time) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, flow := range flows {
+		ret = append(ret, evaluateFlowSLO(flow, finalIntervals, end.Sub(beginning)))
+	}
+	return ret
+}
+
+func evaluateFlowSLO(flow loginFlow, finalIntervals monitorapi.Intervals, runDuration time.Duration) *junitapi.JUnitTestCase {
+	testName := flow.sloTestName()
+	backend := flow.backendName()
+	samples := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceDisruption && interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey] == backend
+	})
+	if len(samples) == 0 || runDuration <= 0 {
+		return &junitapi.JUnitTestCase{
+			Name:        testName,
+			SkipMessage: &junitapi.SkipMessage{Message: fmt.Sprintf("no samples were recorded for %s", backend)},
+		}
+	}
+
+	failed := samples.Filter(monitorapi.IsErrorEvent)
+	disruption := failed.Duration(1 * time.Second)
+	if disruption > runDuration {
+		disruption = runDuration
+	}
+
+	observed := 100 * (1 - disruption.Seconds()/runDuration.Seconds())
+	threshold := junitapi.JUnitThreshold{
+		Name:     "availability",
+		Limit:    100 * flow.availabilitySLO,
+		Observed: observed,
+		Unit:     "percent",
+		Exceeded: observed < 100*flow.availabilitySLO,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:      testName,
+			SystemOut: fmt.Sprintf("failed for %s of %s", disruption.Round(time.Second), runDuration.Round(time.Second)),
+			Details:   &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+
+	junit := junitfailure.NewFailure(sloTemplate, "LoginFlowSLOMissed").
+		Threshold(threshold).
+		Field("flow", flow.name).
+		Field("disruption", disruption.Round(time.Second).String()).
+		Field("duration", runDuration.Round(time.Second).String()).
+		Intervals(failed...).
+		TestCase(testName)
+	// a warning until we know how the login flows hold up during upgrades.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
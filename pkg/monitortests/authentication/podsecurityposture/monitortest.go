@@ -0,0 +1,127 @@
+package podsecurityposture
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformnamespaces"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type podSecurityPostureChecker struct {
+	kubeClient kubernetes.Interface
+	policy     *policy
+}
+
+// NewAnalyzer checks platform pods against a per-namespace policy for default service account tokens and privileges.
+func NewAnalyzer() monitortestframework.MonitorTest {
+	return &podSecurityPostureChecker{}
+}
+
+func (w *podSecurityPostureChecker) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+		},
+		// the tests are named after the platform namespaces of the cluster, so they are not known ahead of the run.
+	}
+}
+
+func (w *podSecurityPostureChecker) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.policy, err = parsePolicy(defaultPolicyYAML)
+	if err != nil {
+		return err
+	}
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (w *podSecurityPostureChecker) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.kubeClient == nil {
+		return nil, nil, nil
+	}
+
+	namespaces, err := w.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	junits := []*junitapi.JUnitTestCase{}
+	for _, ns := range namespaces.Items {
+		if !platformnamespaces.IsPlatformNamespace(ns.Name) {
+			continue
+		}
+
+		pods, err := w.kubeClient.CoreV1().Pods(ns.Name).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		allowed := w.policy.forNamespace(ns.Name)
+		tokenFailures := []string{}
+		privilegeFailures := []string{}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if failure := defaultServiceAccountTokenViolation(pod, allowed); len(failure) > 0 {
+				tokenFailures = append(tokenFailures, failure)
+			}
+			privilegeFailures = append(privilegeFailures, privilegeViolations(pod, allowed)...)
+		}
+
+		junits = append(junits,
+			postureJunit(fmt.Sprintf("[sig-auth] platform pods in ns/%s must not automount the default service account token", ns.Name), "DefaultServiceAccountTokenAutomounted", tokenFailures),
+			postureJunit(fmt.Sprintf("[sig-auth] platform pods in ns/%s must not use privileges beyond the namespace policy", ns.Name), "PrivilegesBeyondPolicy", privilegeFailures),
+		)
+	}
+
+	return nil, junits, nil
+}
+
+// postureJunit reports violations with warn severity so that hardening regressions are visible in CI without
+// failing jobs while components catch up with the policy.
+func postureJunit(testName, reason string, failures []string) *junitapi.JUnitTestCase {
+	if len(failures) == 0 {
+		return &junitapi.JUnitTestCase{Name: testName}
+	}
+
+	failureMsg := strings.Join(failures, "\n")
+	return &junitapi.JUnitTestCase{
+		Name:          testName,
+		SystemOut:     failureMsg,
+		FailureOutput: &junitapi.FailureOutput{Output: failureMsg},
+		Details: &junitapi.JUnitTestCaseDetails{
+			Reason:   reason,
+			Severity: junitapi.SeverityWarn,
+		},
+	}
+}
+
+func (w *podSecurityPostureChecker) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *podSecurityPostureChecker) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (w *podSecurityPostureChecker) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (w *podSecurityPostureChecker) Cleanup(ctx context.Context) error {
+	return nil
+}
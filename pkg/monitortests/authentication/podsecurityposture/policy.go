@@ -0,0 +1,137 @@
+package podsecurityposture
+
+import (
+	_ "embed"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed policy.yaml
+var defaultPolicyYAML []byte
+
+type policy struct {
+	Namespaces []namespacePolicy `json:"namespaces"`
+}
+
+// namespacePolicy lists the settings platform pods in a namespace are allowed to use.
+type namespacePolicy struct {
+	Namespace string `json:"namespace"`
+
+	AllowDefaultServiceAccountToken bool `json:"allowDefaultServiceAccountToken"`
+	AllowPrivileged                 bool `json:"allowPrivileged"`
+	AllowPrivilegeEscalation        bool `json:"allowPrivilegeEscalation"`
+	AllowUnconfinedSeccomp          bool `json:"allowUnconfinedSeccomp"`
+	AllowHostNamespaces             bool `json:"allowHostNamespaces"`
+}
+
+func parsePolicy(content []byte) (*policy, error) {
+	ret := &policy{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	for _, namespacePolicy := range ret.Namespaces {
+		if _, err := path.Match(namespacePolicy.Namespace, ""); err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern %q: %w", namespacePolicy.Namespace, err)
+		}
+	}
+	return ret, nil
+}
+
+// forNamespace returns the first matching policy.  If nothing matches, nothing is allowed.
+func (p *policy) forNamespace(namespace string) namespacePolicy {
+	for _, namespacePolicy := range p.Namespaces {
+		if matches, _ := path.Match(namespacePolicy.Namespace, namespace); matches {
+			return namespacePolicy
+		}
+	}
+	return namespacePolicy{Namespace: namespace}
+}
+
+// defaultServiceAccountTokenViolation returns a description if the pod runs as the default service account with its
+// token mounted.  The default service account is shared by everything in the namespace, so its token should not be
+// available to platform pods that did not ask for it.
+func defaultServiceAccountTokenViolation(pod *corev1.Pod, allowed namespacePolicy) string {
+	if allowed.AllowDefaultServiceAccountToken {
+		return ""
+	}
+	if len(pod.Spec.ServiceAccountName) > 0 && pod.Spec.ServiceAccountName != "default" {
+		return ""
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Projected == nil || !strings.HasPrefix(volume.Name, "kube-api-access-") {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if source.ServiceAccountToken != nil {
+				return fmt.Sprintf("pod '%s'%s automounts the default service account token", pod.Name, ownerReferences(pod))
+			}
+		}
+	}
+	return ""
+}
+
+// privilegeViolations returns a description of every setting on the pod that the policy does not allow.
+func privilegeViolations(pod *corev1.Pod, allowed namespacePolicy) []string {
+	ret := []string{}
+	prefix := fmt.Sprintf("pod '%s'%s", pod.Name, ownerReferences(pod))
+
+	if !allowed.AllowHostNamespaces {
+		hostNamespaces := []string{}
+		if pod.Spec.HostNetwork {
+			hostNamespaces = append(hostNamespaces, "hostNetwork")
+		}
+		if pod.Spec.HostPID {
+			hostNamespaces = append(hostNamespaces, "hostPID")
+		}
+		if pod.Spec.HostIPC {
+			hostNamespaces = append(hostNamespaces, "hostIPC")
+		}
+		if len(hostNamespaces) > 0 {
+			ret = append(ret, fmt.Sprintf("%s uses %s", prefix, strings.Join(hostNamespaces, ", ")))
+		}
+	}
+
+	podSeccompUnconfined := pod.Spec.SecurityContext != nil && isUnconfined(pod.Spec.SecurityContext.SeccompProfile)
+	if podSeccompUnconfined && !allowed.AllowUnconfinedSeccomp {
+		ret = append(ret, fmt.Sprintf("%s has an unconfined seccomp profile", prefix))
+	}
+
+	allContainers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range allContainers {
+		securityContext := container.SecurityContext
+		if securityContext == nil {
+			continue
+		}
+		if !allowed.AllowPrivileged && securityContext.Privileged != nil && *securityContext.Privileged {
+			ret = append(ret, fmt.Sprintf("%s container '%s' is privileged", prefix, container.Name))
+		}
+		if !allowed.AllowPrivilegeEscalation && securityContext.AllowPrivilegeEscalation != nil && *securityContext.AllowPrivilegeEscalation {
+			ret = append(ret, fmt.Sprintf("%s container '%s' allows privilege escalation", prefix, container.Name))
+		}
+		if !allowed.AllowUnconfinedSeccomp && !podSeccompUnconfined && isUnconfined(securityContext.SeccompProfile) {
+			ret = append(ret, fmt.Sprintf("%s container '%s' has an unconfined seccomp profile", prefix, container.Name))
+		}
+	}
+	return ret
+}
+
+func isUnconfined(profile *corev1.SeccompProfile) bool {
+	return profile != nil && profile.Type == corev1.SeccompProfileTypeUnconfined
+}
+
+func ownerReferences(pod *corev1.Pod) string {
+	ownerRefs := make([]string, len(pod.OwnerReferences))
+	for i, or := range pod.OwnerReferences {
+		ownerRefs[i] = fmt.Sprintf("%s/%s", strings.ToLower(or.Kind), or.Name)
+	}
+
+	if len(ownerRefs) > 0 {
+		return fmt.Sprintf(" (owners: %s)", strings.Join(ownerRefs, ", "))
+	}
+
+	return ""
+}
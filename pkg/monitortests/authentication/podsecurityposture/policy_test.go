@@ -0,0 +1,206 @@
+package podsecurityposture
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	defaultPolicy, err := parsePolicy(defaultPolicyYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if allowed := defaultPolicy.forNamespace("openshift-ovn-kubernetes"); !allowed.AllowPrivileged || !allowed.AllowHostNamespaces {
+		t.Errorf("expected openshift-ovn-kubernetes to allow privileged host pods, got %#v", allowed)
+	}
+	if allowed := defaultPolicy.forNamespace("openshift-authentication"); allowed.AllowPrivileged || allowed.AllowDefaultServiceAccountToken {
+		t.Errorf("expected openshift-authentication to allow nothing, got %#v", allowed)
+	}
+}
+
+func TestForNamespace(t *testing.T) {
+	p, err := parsePolicy([]byte(`
+namespaces:
+- namespace: openshift-etcd
+  allowHostNamespaces: true
+- namespace: openshift-*
+  allowPrivileged: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		namespace string
+		want      namespacePolicy
+	}{
+		{namespace: "openshift-etcd", want: namespacePolicy{Namespace: "openshift-etcd", AllowHostNamespaces: true}},
+		{namespace: "openshift-dns", want: namespacePolicy{Namespace: "openshift-*", AllowPrivileged: true}},
+		{namespace: "kube-system", want: namespacePolicy{Namespace: "kube-system"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			if got := p.forNamespace(tt.namespace); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("forNamespace() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePolicyRejectsUnknownFields(t *testing.T) {
+	_, err := parsePolicy([]byte(`
+namespaces:
+- namespace: openshift-etcd
+  allowEverything: true
+`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func tokenVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: "kube-api-access-abcde",
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"}},
+				},
+			},
+		},
+	}
+}
+
+func TestDefaultServiceAccountTokenViolation(t *testing.T) {
+	tests := []struct {
+		name    string
+		pod     *corev1.Pod
+		allowed namespacePolicy
+		want    string
+	}{
+		{
+			name: "default service account with token",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec:       corev1.PodSpec{ServiceAccountName: "default", Volumes: []corev1.Volume{tokenVolume()}},
+			},
+			want: "pod 'foo' automounts the default service account token",
+		},
+		{
+			name: "empty service account with token",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "foo",
+					OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "foo-123"}},
+				},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{tokenVolume()}},
+			},
+			want: "pod 'foo' (owners: replicaset/foo-123) automounts the default service account token",
+		},
+		{
+			name: "default service account without token",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec:       corev1.PodSpec{ServiceAccountName: "default"},
+			},
+		},
+		{
+			name: "dedicated service account",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec:       corev1.PodSpec{ServiceAccountName: "foo-operator", Volumes: []corev1.Volume{tokenVolume()}},
+			},
+		},
+		{
+			name: "allowed by policy",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec:       corev1.PodSpec{ServiceAccountName: "default", Volumes: []corev1.Volume{tokenVolume()}},
+			},
+			allowed: namespacePolicy{AllowDefaultServiceAccountToken: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultServiceAccountTokenViolation(tt.pod, tt.allowed); got != tt.want {
+				t.Errorf("defaultServiceAccountTokenViolation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivilegeViolations(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: corev1.PodSpec{
+			HostNetwork: true,
+			HostPID:     true,
+			InitContainers: []corev1.Container{
+				{Name: "init", SecurityContext: &corev1.SecurityContext{Privileged: pointer.Bool(true)}},
+			},
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: pointer.Bool(true),
+						SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined},
+					},
+				},
+				{
+					Name: "sidecar",
+					SecurityContext: &corev1.SecurityContext{
+						Privileged:               pointer.Bool(false),
+						AllowPrivilegeEscalation: pointer.Bool(false),
+						SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		allowed namespacePolicy
+		want    []string
+	}{
+		{
+			name: "nothing allowed",
+			want: []string{
+				"pod 'foo' uses hostNetwork, hostPID",
+				"pod 'foo' container 'init' is privileged",
+				"pod 'foo' container 'main' allows privilege escalation",
+				"pod 'foo' container 'main' has an unconfined seccomp profile",
+			},
+		},
+		{
+			name: "everything allowed",
+			allowed: namespacePolicy{
+				AllowPrivileged:          true,
+				AllowPrivilegeEscalation: true,
+				AllowUnconfinedSeccomp:   true,
+				AllowHostNamespaces:      true,
+			},
+			want: []string{},
+		},
+		{
+			name:    "host namespaces allowed",
+			allowed: namespacePolicy{AllowHostNamespaces: true, AllowUnconfinedSeccomp: true},
+			want: []string{
+				"pod 'foo' container 'init' is privileged",
+				"pod 'foo' container 'main' allows privilege escalation",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := privilegeViolations(pod, tt.allowed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("privilegeViolations() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package securitydrift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	changeAdded    = "added"
+	changeRemoved  = "removed"
+	changeModified = "modified"
+)
+
+const driftTestName = "[sig-auth] SCCs and platform RBAC should not change unexpectedly during the run"
+
+// upgradeManagers are the field managers that change security objects during an upgrade: the CVO applies the
+// manifests of the new payload, and the kube-apiserver reconciles its bootstrap roles.
+var upgradeManagers = map[string]bool{
+	"cluster-version-operator": true,
+	"kube-apiserver":           true,
+}
+
+var driftTemplate = junitfailure.MustParseTemplate("security-drift",
+	`{{len .Intervals}} SCCs, platform roles or bindings changed during the run.  Tests must restore what they change, and operators must not loosen security outside of an upgrade.
+{{.Fields.diffs}}`)
+
+// drift is a security object that is not the same at the end of the run as at the start.
+type drift struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Change    string    `json:"change"`
+	Managers  []string  `json:"managers,omitempty"`
+	Expected  bool      `json:"expected"`
+	LastWrite time.Time `json:"lastWrite,omitempty"`
+	Diff      string    `json:"diff,omitempty"`
+}
+
+func (d drift) String() string {
+	name := d.Name
+	if len(d.Namespace) > 0 {
+		name = d.Namespace + "/" + d.Name
+	}
+	managers := "unknown"
+	if len(d.Managers) > 0 {
+		managers = strings.Join(d.Managers, ", ")
+	}
+	ret := fmt.Sprintf("%s %s %s by %s", d.Kind, name, d.Change, managers)
+	if len(d.Diff) > 0 {
+		ret += "\n" + d.Diff
+	}
+	return ret
+}
+
+// compare returns the drift between the snapshots, sorted by object.  Outside an upgrade every drift is unexpected;
+// during one, changes only upgradeManagers made are expected.
+func compare(before, after snapshot, beginning time.Time, upgrade bool) []drift {
+	ret := []drift{}
+	for key, curr := range after {
+		previous, existed := before[key]
+		d := drift{Kind: curr.kind, Namespace: curr.namespace, Name: curr.name}
+		switch {
+		case !existed:
+			d.Change = changeAdded
+		case !cmp.Equal(previous.content, curr.content):
+			d.Change = changeModified
+			d.Diff = cmp.Diff(previous.content, curr.content)
+		default:
+			continue
+		}
+		d.Managers = curr.managersSince(beginning)
+		for _, manager := range d.Managers {
+			if at := curr.writes[manager]; at.After(d.LastWrite) {
+				d.LastWrite = at
+			}
+		}
+		d.Expected = upgrade && len(d.Managers) > 0
+		for _, manager := range d.Managers {
+			if !upgradeManagers[manager] {
+				d.Expected = false
+			}
+		}
+		ret = append(ret, d)
+	}
+	for key, previous := range before {
+		if _, ok := after[key]; ok {
+			continue
+		}
+		// the CVO deletes the manifests a payload no longer has.  Who deleted an object is not known.
+		ret = append(ret, drift{Kind: previous.kind, Namespace: previous.namespace, Name: previous.name, Change: changeRemoved, Expected: upgrade})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Kind != ret[j].Kind {
+			return ret[i].Kind < ret[j].Kind
+		}
+		if ret[i].Namespace != ret[j].Namespace {
+			return ret[i].Namespace < ret[j].Namespace
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// driftIntervals returns an interval for every drift, at its last write when it is known.
+func driftIntervals(drifts []drift, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, d := range drifts {
+		level := monitorapi.Error
+		if d.Expected {
+			level = monitorapi.Info
+		}
+		locator := monitorapi.NewLocator().ClusterScopedKind(d.Kind, d.Name)
+		if len(d.Namespace) > 0 {
+			locator = monitorapi.NewLocator().KindInNamespace(d.Kind, d.Namespace, d.Name)
+		}
+		at := d.LastWrite
+		if at.IsZero() {
+			at = end
+		}
+		managers := "unknown managers"
+		if len(d.Managers) > 0 {
+			managers = strings.Join(d.Managers, ", ")
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceSecurityDrift, level).
+				Locator(locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.SecurityConfigDriftReason).
+					HumanMessagef("%s %s during the run by %s", strings.ToLower(d.Kind), d.Change, managers)).
+				Display().
+				Build(at, at.Add(time.Second)),
+		)
+	}
+	return ret
+}
+
+// evaluateDrift fails with the diff of every unexpected drift.
+func evaluateDrift(drifts []drift, finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	diffs := []string{}
+	for _, d := range drifts {
+		if !d.Expected {
+			diffs = append(diffs, d.String())
+		}
+	}
+	if len(diffs) == 0 {
+		return &junitapi.JUnitTestCase{Name: driftTestName}
+	}
+	unexpected := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceSecurityDrift &&
+			interval.Message.Reason == monitorapi.SecurityConfigDriftReason &&
+			interval.Level == monitorapi.Error
+	})
+	junit := junitfailure.NewFailure(driftTemplate, "SecurityConfigDrift").
+		Field("diffs", strings.Join(diffs, "\n\n")).
+		Intervals(unexpected...).
+		TestCase(driftTestName)
+	// a warning until we know which tests change platform security objects without restoring them.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
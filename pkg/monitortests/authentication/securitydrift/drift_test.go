@@ -0,0 +1,86 @@
+package securitydrift
+
+import (
+	"testing"
+	"time"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func writtenBy(manager string, at time.Time) []metav1.ManagedFieldsEntry {
+	writeTime := metav1.NewTime(at)
+	return []metav1.ManagedFieldsEntry{{Manager: manager, Operation: metav1.ManagedFieldsOperationUpdate, Time: &writeTime}}
+}
+
+func scc(name string, privileged bool, managedFields []metav1.ManagedFieldsEntry) object {
+	s := &securityv1.SecurityContextConstraints{
+		ObjectMeta:               metav1.ObjectMeta{Name: name, ManagedFields: managedFields},
+		AllowPrivilegedContainer: privileged,
+	}
+	return newObject("SecurityContextConstraints", s.ObjectMeta, sccContent(s))
+}
+
+func binding(namespace, name string, subjects []string, managedFields []metav1.ManagedFieldsEntry) object {
+	content := bindingContent{RoleRef: rbacv1.RoleRef{Kind: "Role", Name: "edit"}}
+	for _, subject := range subjects {
+		content.Subjects = append(content.Subjects, rbacv1.Subject{Kind: "ServiceAccount", Namespace: namespace, Name: subject})
+	}
+	return newObject("RoleBinding", metav1.ObjectMeta{Namespace: namespace, Name: name, ManagedFields: managedFields}, content)
+}
+
+func snapshotOf(objects ...object) snapshot {
+	ret := snapshot{}
+	for _, o := range objects {
+		ret[o.key()] = o
+	}
+	return ret
+}
+
+func TestCompare(t *testing.T) {
+	before := snapshotOf(
+		scc("restricted-v2", false, writtenBy("cluster-version-operator", start.Add(-time.Hour))),
+		scc("anyuid", false, nil),
+		binding("openshift-monitoring", "prometheus", []string{"prometheus-k8s"}, nil),
+		binding("openshift-config", "removed", []string{"someone"}, nil),
+	)
+	after := snapshotOf(
+		scc("restricted-v2", true, writtenBy("openshift-tests", start.Add(time.Minute))),
+		scc("anyuid", false, writtenBy("cluster-version-operator", start.Add(time.Minute))),
+		binding("openshift-monitoring", "prometheus", []string{"prometheus-k8s", "attacker"}, writtenBy("cluster-version-operator", start.Add(2*time.Minute))),
+		binding("openshift-config", "added", []string{"someone"}, writtenBy("cluster-version-operator", start.Add(3*time.Minute))),
+	)
+
+	drifts := compare(before, after, start, false)
+	if len(drifts) != 4 {
+		t.Fatalf("expected four drifts, got %v", drifts)
+	}
+	for _, d := range drifts {
+		if d.Expected {
+			t.Errorf("expected every drift outside an upgrade to be unexpected, got %v", d)
+		}
+	}
+	if d := drifts[3]; d.Name != "restricted-v2" || d.Change != changeModified || len(d.Diff) == 0 || len(d.Managers) != 1 || d.Managers[0] != "openshift-tests" {
+		t.Errorf("expected restricted-v2 to be modified by openshift-tests, got %v", d)
+	}
+
+	expected := map[string]bool{}
+	for _, d := range compare(before, after, start, true) {
+		expected[d.Name] = d.Expected
+	}
+	if !expected["prometheus"] || !expected["added"] || !expected["removed"] || expected["restricted-v2"] {
+		t.Errorf("expected only the changes of the CVO to be expected during an upgrade, got %v", expected)
+	}
+
+	intervals := driftIntervals(drifts, start.Add(time.Hour))
+	junit := evaluateDrift(drifts, intervals)
+	if junit.FailureOutput == nil || len(junit.Details.IntervalIDs) != 4 {
+		t.Errorf("expected the drift to fail, got %#v", junit)
+	}
+	if junit := evaluateDrift(nil, nil); junit.FailureOutput != nil {
+		t.Errorf("expected no drift to pass, got %v", junit.FailureOutput.Output)
+	}
+}
@@ -0,0 +1,115 @@
+package securitydrift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	securityclient "github.com/openshift/client-go/security/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type securityDrift struct {
+	upgrade bool
+
+	kubeClient     kubernetes.Interface
+	securityClient securityclient.Interface
+
+	start  snapshot
+	drifts []drift
+}
+
+// NewSecurityDrift snapshots the SCCs, the platform cluster roles and bindings, and the role bindings of the platform
+// namespaces when the run starts and when it ends, and fails with the diff of whatever changed unexpectedly.
+func NewSecurityDrift(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &securityDrift{
+		upgrade: len(info.UpgradeTargetPayloadImagePullSpec) > 0,
+	}
+}
+
+func (w *securityDrift) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.WriteContentToStorageStage,
+		},
+		TestNames: []string{driftTestName},
+	}
+}
+
+func (w *securityDrift) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.securityClient, err = securityclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.start, err = takeSnapshot(ctx, w.kubeClient, w.securityClient)
+	if err != nil {
+		return fmt.Errorf("unable to snapshot the security objects: %w", err)
+	}
+	return nil
+}
+
+func (w *securityDrift) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.start == nil {
+		return nil, nil, nil
+	}
+	final, err := takeSnapshot(ctx, w.kubeClient, w.securityClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to snapshot the security objects: %w", err)
+	}
+	w.drifts = compare(w.start, final, beginning, w.upgrade)
+	return driftIntervals(w.drifts, end), nil, nil
+}
+
+func (w *securityDrift) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *securityDrift) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.start == nil {
+		return nil, nil
+	}
+	return []*junitapi.JUnitTestCase{evaluateDrift(w.drifts, finalIntervals)}, nil
+}
+
+func (w *securityDrift) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.start == nil {
+		return nil
+	}
+	content, err := json.MarshalIndent(w.drifts, "", "    ")
+	if err != nil {
+		return err
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("security-drift%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
+
+func (w *securityDrift) ArtifactSchema(relativePath string) string {
+	if strings.HasPrefix(path.Base(relativePath), "security-drift") {
+		return "security-drift/v1"
+	}
+	return ""
+}
+
+func (w *securityDrift) Cleanup(ctx context.Context) error {
+	return nil
+}
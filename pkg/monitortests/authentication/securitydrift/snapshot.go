@@ -0,0 +1,167 @@
+package securitydrift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	securityclient "github.com/openshift/client-go/security/clientset/versioned"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformnamespaces"
+)
+
+// object is the part of a security object that decides what it allows, with who last wrote it.
+type object struct {
+	kind      string
+	namespace string
+	name      string
+	content   interface{}
+	// writes is when each field manager last wrote the object.
+	writes map[string]time.Time
+}
+
+func (o object) key() string {
+	if len(o.namespace) == 0 {
+		return fmt.Sprintf("%s/%s", o.kind, o.name)
+	}
+	return fmt.Sprintf("%s/%s/%s", o.kind, o.namespace, o.name)
+}
+
+// managersSince returns the field managers that wrote the object after the instant.
+func (o object) managersSince(since time.Time) []string {
+	ret := []string{}
+	for manager, at := range o.writes {
+		if !at.Before(since) {
+			ret = append(ret, manager)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// snapshot is every security object watched, by key.
+type snapshot map[string]object
+
+func newObject(kind string, meta metav1.ObjectMeta, content interface{}) object {
+	writes := map[string]time.Time{}
+	for _, entry := range meta.ManagedFields {
+		if entry.Time == nil {
+			continue
+		}
+		if previous, ok := writes[entry.Manager]; !ok || entry.Time.After(previous) {
+			writes[entry.Manager] = entry.Time.Time
+		}
+	}
+	return object{kind: kind, namespace: meta.Namespace, name: meta.Name, content: content, writes: writes}
+}
+
+// isTestObject matches the objects tests and monitor tests create on purpose and delete when they finish.
+func isTestObject(meta metav1.ObjectMeta) bool {
+	return strings.HasPrefix(meta.Name, "e2e-")
+}
+
+// isPlatformClusterObject matches the cluster roles and bindings of kubernetes, the bootstrap roles, and the ones
+// the release payload manages.
+func isPlatformClusterObject(meta metav1.ObjectMeta) bool {
+	if strings.HasPrefix(meta.Name, "system:") || meta.Labels["kubernetes.io/bootstrapping"] == "rbac-defaults" {
+		return true
+	}
+	for key := range meta.Annotations {
+		if strings.HasPrefix(key, "include.release.openshift.io/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sccContent drops the metadata of the SCC, only its fields allow anything.
+func sccContent(scc *securityv1.SecurityContextConstraints) interface{} {
+	ret := scc.DeepCopy()
+	ret.TypeMeta = metav1.TypeMeta{}
+	ret.ObjectMeta = metav1.ObjectMeta{}
+	return ret
+}
+
+// clusterRoleContent is the aggregation rule of aggregated roles, the controller rewrites their rules whenever an
+// aggregated role changes, and the rules of the others.
+func clusterRoleContent(role *rbacv1.ClusterRole) interface{} {
+	if role.AggregationRule != nil {
+		return role.AggregationRule.DeepCopy()
+	}
+	return append([]rbacv1.PolicyRule{}, role.Rules...)
+}
+
+type bindingContent struct {
+	RoleRef  rbacv1.RoleRef
+	Subjects []rbacv1.Subject
+}
+
+// takeSnapshot reads the SCCs, the platform cluster roles and cluster role bindings, and the role bindings of the
+// platform namespaces.
+func takeSnapshot(ctx context.Context, kubeClient kubernetes.Interface, securityClient securityclient.Interface) (snapshot, error) {
+	ret := snapshot{}
+	add := func(o object) {
+		ret[o.key()] = o
+	}
+
+	sccs, err := securityClient.SecurityV1().SecurityContextConstraints().List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		// clusters without the openshift API server, like MicroShift, have no SCCs to drift.
+	case err != nil:
+		return nil, err
+	default:
+		for i := range sccs.Items {
+			scc := &sccs.Items[i]
+			if isTestObject(scc.ObjectMeta) {
+				continue
+			}
+			add(newObject("SecurityContextConstraints", scc.ObjectMeta, sccContent(scc)))
+		}
+	}
+
+	clusterRoles, err := kubeClient.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range clusterRoles.Items {
+		role := &clusterRoles.Items[i]
+		if !isPlatformClusterObject(role.ObjectMeta) {
+			continue
+		}
+		add(newObject("ClusterRole", role.ObjectMeta, clusterRoleContent(role)))
+	}
+
+	clusterRoleBindings, err := kubeClient.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range clusterRoleBindings.Items {
+		binding := &clusterRoleBindings.Items[i]
+		if !isPlatformClusterObject(binding.ObjectMeta) {
+			continue
+		}
+		add(newObject("ClusterRoleBinding", binding.ObjectMeta, bindingContent{RoleRef: binding.RoleRef, Subjects: binding.Subjects}))
+	}
+
+	roleBindings, err := kubeClient.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range roleBindings.Items {
+		binding := &roleBindings.Items[i]
+		if !platformnamespaces.IsPlatformNamespace(binding.Namespace) || isTestObject(binding.ObjectMeta) {
+			continue
+		}
+		add(newObject("RoleBinding", binding.ObjectMeta, bindingContent{RoleRef: binding.RoleRef, Subjects: binding.Subjects}))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,64 @@
+package cloudthrottling
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const throttlingTestName = "[sig-cloud-provider] cloud API requests should not be throttled or exceed quota"
+
+var throttlingTemplate = junitfailure.MustParseTemplate("cloud-api-throttled",
+	`The cloud API throttled the cluster or refused requests for lack of quota {{len .Intervals}} times.  Disruption of these backends overlapped the throttled windows and may be caused by the cloud rather than the product: {{.Fields.disruptedBackends}}.
+{{.IntervalList}}`)
+
+// evaluateThrottling fails when the cloud throttled the cluster, naming the disrupted backends that overlapped the
+// throttled windows so their disruption can be attributed to the cloud account rather than a regression.
+func evaluateThrottling(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	windows, disruptions := monitorapi.Intervals{}, monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		switch {
+		case interval.Source == monitorapi.SourceCloudThrottling:
+			windows = append(windows, interval)
+		case interval.Source == monitorapi.SourceDisruption && interval.Level == monitorapi.Error:
+			disruptions = append(disruptions, interval)
+		}
+	}
+	if len(windows) == 0 {
+		return &junitapi.JUnitTestCase{Name: throttlingTestName}
+	}
+
+	junit := junitfailure.NewFailure(throttlingTemplate, "CloudAPIThrottled").
+		Field("disruptedBackends", disruptedBackends(windows, disruptions)).
+		Intervals(windows...).
+		TestCase(throttlingTestName)
+	// a warning, throttling is a property of the CI cloud accounts and explains failures rather than being one.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+// disruptedBackends names the backends that were disrupted during one of the windows.
+func disruptedBackends(windows, disruptions monitorapi.Intervals) string {
+	backends := map[string]bool{}
+	for _, disruption := range disruptions {
+		for _, window := range windows {
+			if disruption.To.Before(window.From) || disruption.From.After(window.To) {
+				continue
+			}
+			backends[disruption.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey]] = true
+			break
+		}
+	}
+	if len(backends) == 0 {
+		return "none"
+	}
+	ret := []string{}
+	for backend := range backends {
+		ret = append(ret, backend)
+	}
+	sort.Strings(ret)
+	return strings.Join(ret, ", ")
+}
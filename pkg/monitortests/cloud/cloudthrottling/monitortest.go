@@ -0,0 +1,91 @@
+package cloudthrottling
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type cloudThrottling struct {
+	notSupportedReason error
+	platform           configv1.PlatformType
+}
+
+// NewCloudThrottling finds the windows the cloud provider throttled the cluster or refused requests for lack of
+// quota, from the events and cluster operator conditions reporting the cloud API errors.
+func NewCloudThrottling() monitortestframework.MonitorTest {
+	return &cloudThrottling{}
+}
+
+func (w *cloudThrottling) Describe() monitortestframework.MonitorTestDescription {
+	platforms := []string{}
+	for platform := range throttlingSignals {
+		platforms = append(platforms, string(platform))
+	}
+	sort.Strings(platforms)
+	return monitortestframework.MonitorTestDescription{
+		Platforms: platforms,
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{throttlingTestName},
+	}
+}
+
+func (w *cloudThrottling) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	infra, err := configClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if infra.Status.PlatformStatus != nil {
+		w.platform = infra.Status.PlatformStatus.Type
+	}
+	if len(throttlingSignals[w.platform]) == 0 {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("cloud API throttling is not recognized on platform %q", w.platform),
+		}
+	}
+	return w.notSupportedReason
+}
+
+func (w *cloudThrottling) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, w.notSupportedReason
+}
+
+func (w *cloudThrottling) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return throttledWindowIntervals(w.platform, startingIntervals, end), nil
+}
+
+func (w *cloudThrottling) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return []*junitapi.JUnitTestCase{evaluateThrottling(finalIntervals)}, nil
+}
+
+func (w *cloudThrottling) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (*cloudThrottling) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,175 @@
+package cloudthrottling
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// mergeGap is how far apart signals of the same component may be and still be one throttled window.  Controllers back
+// off between retries while they are throttled, so their events and conditions are minutes apart.
+const mergeGap = 5 * time.Minute
+
+// throttlingSignal is an error the cloud API returns when the account is rate limited or out of quota.
+type throttlingSignal struct {
+	// name is recorded as the cause of the interval.
+	name   string
+	reason monitorapi.IntervalReason
+	regex  *regexp.Regexp
+}
+
+var throttlingSignals = map[configv1.PlatformType][]throttlingSignal{
+	configv1.AWSPlatformType: {
+		{name: "RequestLimitExceeded", reason: monitorapi.CloudAPIThrottledReason, regex: regexp.MustCompile(`RequestLimitExceeded`)},
+		{name: "Throttling", reason: monitorapi.CloudAPIThrottledReason, regex: regexp.MustCompile(`ThrottlingException|Throttling: Rate exceeded`)},
+		{name: "LimitExceeded", reason: monitorapi.CloudQuotaExceededReason, regex: regexp.MustCompile(`(Instance|Vcpu|Address|VolumeLimit|LoadBalancer)LimitExceeded|TooManyLoadBalancers`)},
+	},
+	configv1.AzurePlatformType: {
+		{name: "TooManyRequests", reason: monitorapi.CloudAPIThrottledReason, regex: regexp.MustCompile(`TooManyRequests|StatusCode=429`)},
+		{name: "RequestsThrottled", reason: monitorapi.CloudAPIThrottledReason, regex: regexp.MustCompile(`(Subscription|ResourceGroup|Tenant)RequestsThrottled`)},
+		{name: "QuotaExceeded", reason: monitorapi.CloudQuotaExceededReason, regex: regexp.MustCompile(`QuotaExceeded|OperationNotAllowed.*[Qq]uota`)},
+	},
+	configv1.GCPPlatformType: {
+		{name: "rateLimitExceeded", reason: monitorapi.CloudAPIThrottledReason, regex: regexp.MustCompile(`(?i)rate_?limit_?exceeded|Error 429`)},
+		{name: "quotaExceeded", reason: monitorapi.CloudQuotaExceededReason, regex: regexp.MustCompile(`(?i)quota_?exceeded`)},
+	},
+}
+
+// matchSignal returns the first signal of the platform the text matches, or nil.
+func matchSignal(platform configv1.PlatformType, text string) *throttlingSignal {
+	for i := range throttlingSignals[platform] {
+		signal := &throttlingSignals[platform][i]
+		if signal.regex.MatchString(text) {
+			return signal
+		}
+	}
+	return nil
+}
+
+// signalSpan is the time one event or operator condition reported a signal.
+type signalSpan struct {
+	locator  monitorapi.Locator
+	signal   *throttlingSignal
+	from, to time.Time
+}
+
+// signalSpans finds the kube events and cluster operator conditions reporting that the cloud throttled the cluster
+// or refused a request for lack of quota.  A condition reports the signal until it next changes.
+func signalSpans(platform configv1.PlatformType, startingIntervals monitorapi.Intervals, end time.Time) []signalSpan {
+	ret := []signalSpan{}
+	type conditionKey struct {
+		operator  string
+		condition string
+	}
+	openConditions := map[conditionKey]*signalSpan{}
+	openOrder := []conditionKey{}
+	for _, interval := range startingIntervals {
+		switch interval.Source {
+		case monitorapi.SourceKubeEvent:
+			signal := matchSignal(platform, string(interval.Message.Reason)+" "+interval.Message.HumanMessage)
+			if signal == nil {
+				continue
+			}
+			to := interval.To
+			if to.Before(interval.From) {
+				to = interval.From
+			}
+			ret = append(ret, signalSpan{locator: interval.Locator, signal: signal, from: interval.From, to: to})
+
+		case monitorapi.SourceClusterOperatorMonitor:
+			condition := interval.Message.Annotations[monitorapi.AnnotationCondition]
+			if len(condition) == 0 {
+				continue
+			}
+			key := conditionKey{operator: interval.Locator.Keys[monitorapi.LocatorClusterOperatorKey], condition: condition}
+			if open, ok := openConditions[key]; ok {
+				open.to = interval.From
+				ret = append(ret, *open)
+				delete(openConditions, key)
+			}
+			if signal := matchSignal(platform, interval.Message.HumanMessage); signal != nil {
+				openConditions[key] = &signalSpan{locator: interval.Locator, signal: signal, from: interval.From}
+				openOrder = append(openOrder, key)
+			}
+		}
+	}
+	for _, key := range openOrder {
+		if open, ok := openConditions[key]; ok {
+			open.to = end
+			ret = append(ret, *open)
+			delete(openConditions, key)
+		}
+	}
+	return ret
+}
+
+// throttledWindowIntervals merges the signals reported for the same object into windows.
+func throttledWindowIntervals(platform configv1.PlatformType, startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	type windowKey struct {
+		locator string
+		reason  monitorapi.IntervalReason
+	}
+	spansByKey := map[windowKey][]signalSpan{}
+	keys := []windowKey{}
+	for _, span := range signalSpans(platform, startingIntervals, end) {
+		key := windowKey{locator: span.locator.OldLocator(), reason: span.signal.reason}
+		if _, ok := spansByKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		spansByKey[key] = append(spansByKey[key], span)
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, key := range keys {
+		spans := spansByKey[key]
+		sort.Slice(spans, func(i, j int) bool { return spans[i].from.Before(spans[j].from) })
+		window := []signalSpan{spans[0]}
+		for _, span := range spans[1:] {
+			if span.from.After(lastTo(window).Add(mergeGap)) {
+				ret = append(ret, throttledWindowInterval(window))
+				window = nil
+			}
+			window = append(window, span)
+		}
+		ret = append(ret, throttledWindowInterval(window))
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func lastTo(spans []signalSpan) time.Time {
+	ret := spans[0].to
+	for _, span := range spans[1:] {
+		if span.to.After(ret) {
+			ret = span.to
+		}
+	}
+	return ret
+}
+
+func throttledWindowInterval(spans []signalSpan) monitorapi.Interval {
+	first := spans[0]
+	humanMessage := "cloud API throttled requests"
+	if first.signal.reason == monitorapi.CloudQuotaExceededReason {
+		humanMessage = "cloud API refused requests for lack of quota"
+	}
+	to := lastTo(spans)
+	if !to.After(first.from) {
+		// one second so the window is charted.
+		to = first.from.Add(time.Second)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceCloudThrottling, monitorapi.Warning).
+		Locator(first.locator).
+		Message(monitorapi.NewMessage().Reason(first.signal.reason).
+			Cause(first.signal.name).
+			Constructed(monitorapi.ConstructionOwnerCloudThrottle).
+			WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(len(spans))).
+			HumanMessagef("%s, reported %d times", humanMessage, len(spans))).
+		Display().
+		Build(first.from, to)
+}
@@ -0,0 +1,95 @@
+package cloudthrottling
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func event(namespace, reason, message string, at time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().PodFromNames(namespace, "controller-abcde", "uid")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.IntervalReason(reason)).HumanMessage(message)).
+		Build(start.Add(at), start.Add(at+time.Second))
+}
+
+func operatorCondition(operator string, condition configv1.ClusterStatusConditionType, status configv1.ConditionStatus, message string, at time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().ClusterOperator(operator)).
+		Message(monitorapi.NewMessage().
+			WithAnnotation(monitorapi.AnnotationCondition, string(condition)).
+			WithAnnotation(monitorapi.AnnotationStatus, string(status)).
+			HumanMessage(message)).
+		Build(start.Add(at), start.Add(at))
+}
+
+func TestThrottledWindowIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		// two throttled events of the same controller minutes apart are one window.
+		event("openshift-machine-api", "FailedCreate", "RequestLimitExceeded: Request limit exceeded.", time.Minute),
+		event("openshift-machine-api", "FailedCreate", "RequestLimitExceeded: Request limit exceeded.", 4*time.Minute),
+		// an hour later is another.
+		event("openshift-machine-api", "FailedCreate", "RequestLimitExceeded: Request limit exceeded.", 70*time.Minute),
+		// a quota error of the same controller is a separate window.
+		event("openshift-machine-api", "FailedCreate", "VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit", 2*time.Minute),
+		// not a cloud error.
+		event("openshift-machine-api", "FailedCreate", "connection refused", 3*time.Minute),
+		// degraded by throttling until the condition changes.
+		operatorCondition("storage", configv1.OperatorDegraded, configv1.ConditionTrue, "AWSEBSCSIDriverOperatorDegraded: ThrottlingException: Rate exceeded", 10*time.Minute),
+		operatorCondition("storage", configv1.OperatorDegraded, configv1.ConditionFalse, "", 15*time.Minute),
+	}
+
+	windows := throttledWindowIntervals(configv1.AWSPlatformType, intervals, start.Add(2*time.Hour))
+	if len(windows) != 4 {
+		t.Fatalf("expected four throttled windows, got %v", windows)
+	}
+	first := windows[0]
+	if first.Message.Reason != monitorapi.CloudAPIThrottledReason || !first.From.Equal(start.Add(time.Minute)) || !first.To.Equal(start.Add(4*time.Minute+time.Second)) {
+		t.Errorf("expected the first two events to be one throttled window, got %v", first)
+	}
+	if windows[1].Message.Reason != monitorapi.CloudQuotaExceededReason {
+		t.Errorf("expected the quota error to be its own window, got %v", windows[1])
+	}
+	operator := windows[2]
+	if operator.Locator.Keys[monitorapi.LocatorClusterOperatorKey] != "storage" || !operator.To.Equal(start.Add(15*time.Minute)) {
+		t.Errorf("expected the storage operator to be throttled until its condition changed, got %v", operator)
+	}
+
+	if windows := throttledWindowIntervals(configv1.GCPPlatformType, intervals, start.Add(2*time.Hour)); len(windows) != 0 {
+		t.Errorf("expected AWS errors not to be recognized on GCP, got %v", windows)
+	}
+}
+
+func TestEvaluateThrottling(t *testing.T) {
+	if junit := evaluateThrottling(monitorapi.Intervals{}); junit.FailureOutput != nil {
+		t.Errorf("expected no throttling to pass, got %v", junit.FailureOutput.Output)
+	}
+
+	finalIntervals := throttledWindowIntervals(configv1.AzurePlatformType, monitorapi.Intervals{
+		event("openshift-cloud-controller-manager", "SyncLoadBalancerFailed", "Retriable: true, RetryAfter: 5s, HTTPStatusCode: 429, RawError: TooManyRequests", time.Minute),
+	}, start.Add(time.Hour))
+	finalIntervals = append(finalIntervals,
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(monitorapi.NewLocator().LocateDisruptionCheck("service-load-balancer-with-pdb-new-connections", "service-load-balancer-with-pdb-new-connections", monitorapi.NewConnectionType)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason)).
+			Build(start.Add(time.Minute), start.Add(2*time.Minute)),
+	)
+
+	junit := evaluateThrottling(finalIntervals)
+	if junit.FailureOutput == nil {
+		t.Fatalf("expected throttling to fail")
+	}
+	if junit.Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", junit.Details.Severity)
+	}
+	if !strings.Contains(junit.FailureOutput.Output, "service-load-balancer-with-pdb-new-connections") {
+		t.Errorf("expected the failure to name the disrupted backend, got %q", junit.FailureOutput.Output)
+	}
+}
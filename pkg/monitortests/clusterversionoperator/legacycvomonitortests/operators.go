@@ -337,7 +337,7 @@ func testOperatorOSUpdateStaged(events monitorapi.Intervals, clientConfig *rest.
 	if failTest {
 		// If an error occurs getting the platform, we're just going to let the test result stand.
 		jobType, err := platformidentification2.GetJobType(context.TODO(), clientConfig)
-		if err == nil && (jobType.Platform == "ovirt" || jobType.Platform == "metal") {
+		if err == nil && platformidentification2.ThresholdsFor(jobType.Platform).TolerateSlowOSUpdateStaging {
 			failTest = false
 		}
 	}
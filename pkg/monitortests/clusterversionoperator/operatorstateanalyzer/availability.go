@@ -0,0 +1,130 @@
+package operatorstateanalyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var unavailableOutsideUpdateTemplate = junitfailure.MustParseTemplate("operator-unavailable-outside-update",
+	`clusteroperator/{{index .Fields "operator"}} went unavailable {{len .Intervals}} times while neither the cluster nor the operator was updating.  Operators are expected to stay available unless they are rolling out a new version.
+{{.IntervalList}}`)
+
+// clusterUpdateWindows returns the periods the cluster version was updating, from the UpgradeStarted or
+// UpgradeRollback event to the cluster reaching the new version, or to the end of the run if it never did.
+func clusterUpdateWindows(intervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	var updateStarted *monitorapi.Interval
+	for i := range intervals {
+		interval := intervals[i]
+		if interval.Locator.Keys[monitorapi.LocatorClusterVersionKey] == "" {
+			continue
+		}
+		switch {
+		case interval.Source == monitorapi.SourceKubeEvent &&
+			(interval.Message.Reason == "UpgradeStarted" || interval.Message.Reason == "UpgradeRollback"):
+			if updateStarted == nil {
+				updateStarted = &interval
+			}
+		case interval.Source == monitorapi.SourceClusterOperatorMonitor &&
+			strings.HasPrefix(interval.Message.HumanMessage, "cluster reached "):
+			if updateStarted != nil {
+				ret = append(ret, updateWindow(*updateStarted, interval.From))
+			}
+			updateStarted = nil
+		}
+	}
+	if updateStarted != nil {
+		ret = append(ret, updateWindow(*updateStarted, end))
+	}
+	return ret
+}
+
+func updateWindow(started monitorapi.Interval, to time.Time) monitorapi.Interval {
+	window := started
+	window.To = to
+	return window
+}
+
+// evaluateUnavailableOutsideUpdates produces a test for every known operator, failing when the operator went
+// Available=False or Unknown without the cluster updating and without the operator itself Progressing=True, the
+// two times rolling out new operands is allowed to cost availability.
+func evaluateUnavailableOutsideUpdates(finalIntervals monitorapi.Intervals, end time.Time) []*junitapi.JUnitTestCase {
+	updates := clusterUpdateWindows(finalIntervals, end)
+
+	unavailableByOperator := map[string]monitorapi.Intervals{}
+	progressingByOperator := map[string]monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceOperatorState {
+			continue
+		}
+		operatorName := interval.Locator.Keys[monitorapi.LocatorClusterOperatorKey]
+		if len(operatorName) == 0 {
+			continue
+		}
+		status := configv1.ConditionStatus(interval.Message.Annotations[monitorapi.AnnotationStatus])
+		switch configv1.ClusterStatusConditionType(interval.Message.Annotations[monitorapi.AnnotationCondition]) {
+		case configv1.OperatorAvailable:
+			if status != configv1.ConditionTrue {
+				unavailableByOperator[operatorName] = append(unavailableByOperator[operatorName], interval)
+			}
+		case configv1.OperatorProgressing:
+			if status == configv1.ConditionTrue {
+				progressingByOperator[operatorName] = append(progressingByOperator[operatorName], interval)
+			}
+		}
+	}
+
+	operatorNames := platformidentification.KnownOperators.Clone()
+	for operatorName := range unavailableByOperator {
+		operatorNames.Insert(operatorName)
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, operatorName := range operatorNames.List() {
+		bzComponent := platformidentification.GetBugzillaComponentForOperator(operatorName)
+		if bzComponent == "Unknown" {
+			bzComponent = operatorName
+		}
+		testName := fmt.Sprintf("[bz-%v] clusteroperator/%v should only be unavailable while updating", bzComponent, operatorName)
+
+		outsideUpdates := monitorapi.Intervals{}
+		for _, unavailable := range unavailableByOperator[operatorName] {
+			if !startedDuring(unavailable, updates) && !startedDuring(unavailable, progressingByOperator[operatorName]) {
+				outsideUpdates = append(outsideUpdates, unavailable)
+			}
+		}
+		if len(outsideUpdates) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+
+		sort.Sort(outsideUpdates)
+		junit := junitfailure.NewFailure(unavailableOutsideUpdateTemplate, "OperatorUnavailableOutsideUpdate").
+			Field("operator", operatorName).
+			Intervals(outsideUpdates...).
+			TestCase(testName)
+		// a warning until we have seen how often operators report unavailable for reasons that are not updates.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
+
+// startedDuring returns true if the window started while any of the explanations was in progress.  The operator
+// reports Progressing=True and Available=False in the same status update, so the start is inclusive.
+func startedDuring(window monitorapi.Interval, explanations monitorapi.Intervals) bool {
+	for _, explanation := range explanations {
+		if !window.From.Before(explanation.From) && !window.From.After(explanation.To) {
+			return true
+		}
+	}
+	return false
+}
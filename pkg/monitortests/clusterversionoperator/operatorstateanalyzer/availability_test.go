@@ -0,0 +1,81 @@
+package operatorstateanalyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestEvaluateUnavailableOutsideUpdates(t *testing.T) {
+	start := timeFor("2024-01-01T00:00:00Z")
+	end := start.Add(2 * time.Hour)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	operatorState := func(operator string, condition configv1.ClusterStatusConditionType, status configv1.ConditionStatus, from, to time.Duration) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceOperatorState, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().ClusterOperator(operator)).
+			Message(monitorapi.NewMessage().Reason("Testing").HumanMessage("testing").
+				WithAnnotation(monitorapi.AnnotationCondition, string(condition)).
+				WithAnnotation(monitorapi.AnnotationStatus, string(status))).
+			Build(at(from), at(to))
+	}
+	cvLocator := monitorapi.Locator{Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorClusterVersionKey: "cluster"}}
+
+	intervals := monitorapi.Intervals{
+		// the cluster updates between 10m and 60m.
+		monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+			Locator(cvLocator).
+			Message(monitorapi.NewMessage().Reason("UpgradeStarted").HumanMessage("Cluster version operator starting upgrade")).
+			Build(at(10*time.Minute), at(10*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, monitorapi.Warning).
+			Locator(cvLocator).
+			Message(monitorapi.NewMessage().HumanMessage("cluster reached 4.16.0")).
+			Build(at(60*time.Minute), at(60*time.Minute)),
+
+		// unavailable during the cluster update.
+		operatorState("authentication", configv1.OperatorAvailable, configv1.ConditionFalse, 20*time.Minute, 25*time.Minute),
+		// unavailable while the operator was progressing after the update.
+		operatorState("console", configv1.OperatorProgressing, configv1.ConditionTrue, 70*time.Minute, 80*time.Minute),
+		operatorState("console", configv1.OperatorAvailable, configv1.ConditionFalse, 70*time.Minute, 72*time.Minute),
+		// unavailable with no update at all.
+		operatorState("dns", configv1.OperatorAvailable, configv1.ConditionUnknown, 90*time.Minute, 91*time.Minute),
+		// degraded is not unavailable.
+		operatorState("etcd", configv1.OperatorDegraded, configv1.ConditionTrue, 90*time.Minute, 91*time.Minute),
+	}
+
+	junits := evaluateUnavailableOutsideUpdates(intervals, end)
+	failures := map[string]*junitapi.JUnitTestCase{}
+	for _, junit := range junits {
+		if junit.FailureOutput != nil {
+			failures[junit.Name] = junit
+		}
+	}
+	require.Len(t, failures, 1)
+	for name, junit := range failures {
+		assert.True(t, strings.Contains(name, "clusteroperator/dns "), "unexpected failure %s", name)
+		assert.Equal(t, junitapi.SeverityWarn, junit.Details.Severity)
+		assert.Len(t, junit.Details.IntervalIDs, 1)
+	}
+}
+
+func TestClusterUpdateWindowsWithoutCompletion(t *testing.T) {
+	start := timeFor("2024-01-01T00:00:00Z")
+	cvLocator := monitorapi.Locator{Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorClusterVersionKey: "cluster"}}
+	intervals := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+			Locator(cvLocator).
+			Message(monitorapi.NewMessage().Reason("UpgradeStarted").HumanMessage("starting upgrade")).
+			Build(start, start),
+	}
+
+	windows := clusterUpdateWindows(intervals, start.Add(time.Hour))
+	require.Len(t, windows, 1)
+	assert.Equal(t, start.Add(time.Hour), windows[0].To)
+}
@@ -12,12 +12,20 @@ import (
 )
 
 type operatorStateChecker struct {
+	// end is the end of the run, when an update that never completed is considered to stop.
+	end time.Time
 }
 
 func NewAnalyzer() monitortestframework.MonitorTest {
 	return &operatorStateChecker{}
 }
 
+var _ monitortestframework.OfflineMonitorTest = &operatorStateChecker{}
+
+func (*operatorStateChecker) SupportsOffline() bool {
+	return true
+}
+
 func (w *operatorStateChecker) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	return nil
 }
@@ -26,7 +34,9 @@ func (w *operatorStateChecker) CollectData(ctx context.Context, storageDir strin
 	return nil, nil, nil
 }
 
-func (*operatorStateChecker) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+func (w *operatorStateChecker) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	w.end = end
+
 	ret := monitorapi.Intervals{}
 	ret = append(ret, intervalsFromEvents_OperatorAvailable(startingIntervals, nil, beginning, end)...)
 	ret = append(ret, intervalsFromEvents_OperatorProgressing(startingIntervals, nil, beginning, end)...)
@@ -35,8 +45,8 @@ func (*operatorStateChecker) ConstructComputedIntervals(ctx context.Context, sta
 	return ret, nil
 }
 
-func (*operatorStateChecker) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
+func (w *operatorStateChecker) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return evaluateUnavailableOutsideUpdates(finalIntervals, w.end), nil
 }
 
 func (*operatorStateChecker) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
@@ -0,0 +1,96 @@
+package updatephases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedupdatephases"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// defaultPhaseDurations are used for jobs without enough historical runs.  They are generous, since without history a
+// slow phase is only a warning.
+var defaultPhaseDurations = map[string]time.Duration{
+	phaseAccept:            10 * time.Minute,
+	phaseControlPlane:      40 * time.Minute,
+	phasePlatformOperators: 40 * time.Minute,
+	phaseFinalize:          20 * time.Minute,
+}
+
+func phaseTestName(name string) string {
+	return fmt.Sprintf("[sig-cluster-lifecycle] cluster update %s phase should not take longer than usual", name)
+}
+
+var slowPhaseTemplate = junitfailure.MustParseTemplate("cluster-update-phase",
+	`the {{.Fields.phase}} phase of the update took {{printf "%.0f" .Threshold.Observed}}s, longer than the {{printf "%.0f" .Threshold.Limit}}s allowed {{.Fields.source}}.
+{{.IntervalList}}`)
+
+// evaluatePhases returns a test for every phase seen during the run, failing when the phase of any update took longer
+// than the historical P99 for this kind of job.
+func evaluatePhases(finalIntervals monitorapi.Intervals, jobType platformidentification.JobType) ([]*junitapi.JUnitTestCase, error) {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, name := range phases {
+		phaseIntervals := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+			return interval.Source == monitorapi.SourceClusterUpdatePhase &&
+				interval.Message.Reason == monitorapi.ClusterUpdatePhaseReason &&
+				interval.Message.Annotations[monitorapi.AnnotationPhase] == name
+		})
+		if len(phaseIntervals) == 0 {
+			continue
+		}
+
+		allowed, details, err := allowedupdatephases.GetAllowedPhaseDuration(name, jobType)
+		if err != nil {
+			return nil, err
+		}
+		historical := allowed != nil
+		source := fmt.Sprintf("by the historical P99 %s", details)
+		if !historical {
+			defaultDuration := defaultPhaseDurations[name]
+			allowed = &defaultDuration
+			source = "by default, there is no historical data for this job"
+		}
+
+		var longest time.Duration
+		slow := monitorapi.Intervals{}
+		for _, interval := range phaseIntervals {
+			duration := interval.To.Sub(interval.From)
+			if duration > longest {
+				longest = duration
+			}
+			if duration > *allowed {
+				slow = append(slow, interval)
+			}
+		}
+
+		threshold := junitapi.JUnitThreshold{
+			Name:     fmt.Sprintf("cluster-update-phase-%s-seconds", name),
+			Limit:    allowed.Seconds(),
+			Observed: longest.Seconds(),
+			Unit:     "seconds",
+			Exceeded: len(slow) > 0,
+		}
+		if !threshold.Exceeded {
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:    phaseTestName(name),
+				Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+			})
+			continue
+		}
+		junit := junitfailure.NewFailure(slowPhaseTemplate, "ClusterUpdatePhaseTooSlow").
+			Threshold(threshold).
+			Field("phase", name).
+			Field("source", source).
+			Intervals(slow...).
+			TestCase(phaseTestName(name))
+		if !historical {
+			// the defaults are a guess, only the historical data is a regression.
+			junit.Details.Severity = junitapi.SeverityWarn
+		}
+		ret = append(ret, junit)
+	}
+	return ret, nil
+}
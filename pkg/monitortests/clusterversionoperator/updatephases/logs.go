@@ -0,0 +1,78 @@
+package updatephases
+
+import (
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+)
+
+var (
+	// runningSyncRegex matches the line the CVO logs every time its sync worker starts applying the payload.
+	runningSyncRegex = regexp.MustCompile(`Running sync (\S+) \(force=\w+\) on generation \d+ in state (\w+)`)
+	// doneSyncingRegex matches the line the CVO logs when a clusteroperator in the manifest graph reports the
+	// payload version.
+	doneSyncingRegex = regexp.MustCompile(`Done syncing for clusteroperator "([^"]+)"`)
+)
+
+type syncStart struct {
+	at      time.Time
+	version string
+	state   string
+}
+
+type operatorDone struct {
+	at   time.Time
+	name string
+}
+
+// cvoLogs keeps the lines of the CVO log that mark the progress of an update through the manifest graph.  The CVO
+// pod is replaced during an update, so lines arrive from more than one pod.
+type cvoLogs struct {
+	lock  sync.Mutex
+	syncs []syncStart
+	dones []operatorDone
+}
+
+func (l *cvoLogs) HandleLogLine(logLine podaccess.LogLineContent) {
+	if m := runningSyncRegex.FindStringSubmatch(logLine.Line); m != nil {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.syncs = append(l.syncs, syncStart{at: logLine.Instant, version: m[1], state: m[2]})
+		return
+	}
+	if m := doneSyncingRegex.FindStringSubmatch(logLine.Line); m != nil {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.dones = append(l.dones, operatorDone{at: logLine.Instant, name: m[1]})
+	}
+}
+
+// firstSync returns when the CVO first started to apply the version as an update after the instant.
+func (l *cvoLogs) firstSync(version string, after time.Time) (time.Time, bool) {
+	var ret time.Time
+	for _, curr := range l.syncs {
+		if curr.version != version || curr.state != "Updating" || curr.at.Before(after) {
+			continue
+		}
+		if ret.IsZero() || curr.at.Before(ret) {
+			ret = curr.at
+		}
+	}
+	return ret, !ret.IsZero()
+}
+
+// firstDone returns when the CVO first finished syncing the clusteroperator after the instant.
+func (l *cvoLogs) firstDone(name string, after time.Time) (time.Time, bool) {
+	var ret time.Time
+	for _, curr := range l.dones {
+		if curr.name != name || curr.at.Before(after) {
+			continue
+		}
+		if ret.IsZero() || curr.at.Before(ret) {
+			ret = curr.at
+		}
+	}
+	return ret, !ret.IsZero()
+}
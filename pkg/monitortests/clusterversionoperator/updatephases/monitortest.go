@@ -0,0 +1,155 @@
+package updatephases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const cvoNamespace = "openshift-cluster-version"
+
+// errNotStarted is returned when the run is evaluated without collection having been started, for instance when the
+// artifacts of an earlier run are analyzed, because the phases are compared to the history of the job type.
+var errNotStarted = &monitortestframework.NotSupportedError{
+	Reason: "the job type is unknown because collection was not started",
+}
+
+type updatePhases struct {
+	notSupportedReason error
+
+	adminRESTConfig *rest.Config
+	jobType         *platformidentification.JobType
+	logs            *cvoLogs
+	clusterVersion  *configv1.ClusterVersion
+
+	stopCollection     context.CancelFunc
+	finishedCollecting chan struct{}
+}
+
+// NewUpdatePhases splits the ClusterVersion updates of the run into phases of the manifest graph, using the CVO logs
+// and the update history, and compares how long each phase took to its historical duration.
+func NewUpdatePhases() monitortestframework.MonitorTest {
+	return &updatePhases{
+		logs:               &cvoLogs{},
+		finishedCollecting: make(chan struct{}),
+	}
+}
+
+func (w *updatePhases) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, name := range phases {
+		testNames = append(testNames, phaseTestName(name))
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"HyperShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *updatePhases) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.jobType, err = platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		return fmt.Errorf("unable to determine job type: %v", err)
+	}
+	// the CVO of a hosted cluster runs on the management cluster.
+	if w.jobType.Topology == "external" {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "topology external not supported"}
+		return w.notSupportedReason
+	}
+
+	kubeInformers := informers.NewSharedInformerFactory(kubeClient, 0)
+	cvoLabel, err := labels.NewRequirement("k8s-app", selection.Equals, []string{"cluster-version-operator"})
+	if err != nil {
+		return err
+	}
+	ctx, w.stopCollection = context.WithCancel(ctx)
+	podStreamer := podaccess.NewPodsStreamer(
+		kubeClient,
+		labels.NewSelector().Add(*cvoLabel),
+		cvoNamespace,
+		"cluster-version-operator",
+		w.logs,
+		coreinformers.New(kubeInformers, cvoNamespace, nil).Pods(),
+	)
+
+	go kubeInformers.Start(ctx.Done())
+	go podStreamer.Run(ctx, w.finishedCollecting)
+
+	return nil
+}
+
+func (w *updatePhases) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	if w.jobType == nil {
+		return nil, nil, errNotStarted
+	}
+	w.stopCollection()
+
+	// wait until we're drained
+	<-w.finishedCollecting
+
+	configClient, err := configclient.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.clusterVersion, err = configClient.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil, nil
+	}
+	return nil, nil, err
+}
+
+func (w *updatePhases) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return phaseIntervals(w.logs, w.clusterVersion, beginning, end), nil
+}
+
+func (w *updatePhases) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	if w.jobType == nil {
+		return nil, errNotStarted
+	}
+	return evaluatePhases(finalIntervals, *w.jobType)
+}
+
+func (w *updatePhases) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *updatePhases) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
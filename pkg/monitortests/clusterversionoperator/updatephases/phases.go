@@ -0,0 +1,114 @@
+package updatephases
+
+import (
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	phaseAccept            = "accept"
+	phaseControlPlane      = "control-plane"
+	phasePlatformOperators = "platform-operators"
+	phaseFinalize          = "finalize"
+)
+
+// phases are the phases of an update, in the order they happen.
+var phases = []string{phaseAccept, phaseControlPlane, phasePlatformOperators, phaseFinalize}
+
+// controlPlaneOperators are the clusteroperators of the first run levels of the manifest graph.  The control plane
+// has updated once all of them report the new version.
+var controlPlaneOperators = []string{
+	"etcd",
+	"kube-apiserver",
+	"kube-controller-manager",
+	"kube-scheduler",
+	"openshift-apiserver",
+}
+
+// platformOperatorsDone is the clusteroperator of the last run level of the manifest graph.  It reports the new
+// version once every node has been rebooted into it.
+const platformOperatorsDone = "machine-config"
+
+// phase is a part of an update that starts where the previous part ended.
+type phase struct {
+	name     string
+	version  string
+	from, to time.Time
+}
+
+// entryPhases splits an entry of the ClusterVersion history into phases: the CVO accepting the payload, updating the
+// control plane, updating the rest of the operators and the nodes, and the CVO confirming every manifest is applied.
+// It stops at the first phase the logs do not show ending, since every later phase would start at an unknown point.
+func entryPhases(logs *cvoLogs, entry configv1.UpdateHistory) []phase {
+	ret := []phase{}
+	prev := entry.StartedTime.Time
+	add := func(name string, to time.Time) {
+		// the CVO can log an operator done before the previous phase ended when it repeats a sync.
+		if to.Before(prev) {
+			to = prev
+		}
+		ret = append(ret, phase{name: name, version: entry.Version, from: prev, to: to})
+		prev = to
+	}
+
+	accepted, ok := logs.firstSync(entry.Version, entry.StartedTime.Time)
+	if !ok {
+		return ret
+	}
+	add(phaseAccept, accepted)
+
+	var controlPlaneDone time.Time
+	for _, name := range controlPlaneOperators {
+		done, ok := logs.firstDone(name, accepted)
+		if !ok {
+			return ret
+		}
+		if done.After(controlPlaneDone) {
+			controlPlaneDone = done
+		}
+	}
+	add(phaseControlPlane, controlPlaneDone)
+
+	done, ok := logs.firstDone(platformOperatorsDone, controlPlaneDone)
+	if !ok {
+		return ret
+	}
+	add(phasePlatformOperators, done)
+
+	if entry.State != configv1.CompletedUpdate || entry.CompletionTime == nil {
+		return ret
+	}
+	add(phaseFinalize, entry.CompletionTime.Time)
+	return ret
+}
+
+// phaseIntervals returns an interval for every phase of the updates that started during the run.
+func phaseIntervals(logs *cvoLogs, clusterVersion *configv1.ClusterVersion, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	if clusterVersion == nil {
+		return ret
+	}
+	logs.lock.Lock()
+	defer logs.lock.Unlock()
+	for _, entry := range clusterVersion.Status.History {
+		if entry.StartedTime.Time.Before(beginning) || (!end.IsZero() && entry.StartedTime.Time.After(end)) {
+			continue
+		}
+		for _, curr := range entryPhases(logs, entry) {
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceClusterUpdatePhase, monitorapi.Info).
+					Locator(monitorapi.NewLocator().ClusterVersion(clusterVersion)).
+					Message(monitorapi.NewMessage().Reason(monitorapi.ClusterUpdatePhaseReason).
+						Constructed(monitorapi.ConstructionOwnerUpdatePhase).
+						WithAnnotation(monitorapi.AnnotationPhase, curr.name).
+						HumanMessagef("update to %s spent %v in the %s phase", curr.version, curr.to.Sub(curr.from).Round(time.Second), curr.name)).
+					Display().
+					Build(curr.from, curr.to),
+			)
+		}
+	}
+	return ret
+}
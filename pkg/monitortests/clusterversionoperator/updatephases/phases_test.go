@@ -0,0 +1,94 @@
+package updatephases
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func logLine(offset time.Duration, line string) podaccess.LogLineContent {
+	return podaccess.LogLineContent{Instant: start.Add(offset), Line: line}
+}
+
+func clusterVersion(state configv1.UpdateState, completed *metav1.Time) *configv1.ClusterVersion {
+	return &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status: configv1.ClusterVersionStatus{
+			History: []configv1.UpdateHistory{
+				{State: state, Version: "4.16.1", StartedTime: metav1.NewTime(start.Add(time.Minute)), CompletionTime: completed},
+				{State: configv1.CompletedUpdate, Version: "4.16.0", StartedTime: metav1.NewTime(start.Add(-time.Hour))},
+			},
+		},
+	}
+}
+
+func TestPhaseIntervals(t *testing.T) {
+	logs := &cvoLogs{}
+	for _, line := range []podaccess.LogLineContent{
+		logLine(0, `I0101 00:00:00.000000       1 sync_worker.go:900] Running sync 4.16.0 (force=false) on generation 1 in state Reconciling at attempt 0`),
+		logLine(3*time.Minute, `I0101 00:03:00.000000       1 sync_worker.go:900] Running sync 4.16.1 (force=false) on generation 2 in state Updating at attempt 0`),
+		logLine(10*time.Minute, `I0101 00:10:00.000000       1 sync_worker.go:1000] Done syncing for clusteroperator "etcd" (10 of 800)`),
+		logLine(20*time.Minute, `I0101 00:20:00.000000       1 sync_worker.go:1000] Done syncing for clusteroperator "kube-apiserver" (20 of 800)`),
+		logLine(21*time.Minute, `I0101 00:21:00.000000       1 sync_worker.go:1000] Done syncing for clusteroperator "kube-controller-manager" (30 of 800)`),
+		logLine(22*time.Minute, `I0101 00:22:00.000000       1 sync_worker.go:1000] Done syncing for clusteroperator "kube-scheduler" (40 of 800)`),
+		logLine(25*time.Minute, `I0101 00:25:00.000000       1 sync_worker.go:1000] Done syncing for clusteroperator "openshift-apiserver" (50 of 800)`),
+		logLine(80*time.Minute, `I0101 01:20:00.000000       1 sync_worker.go:1000] Done syncing for clusteroperator "machine-config" (790 of 800)`),
+	} {
+		logs.HandleLogLine(line)
+	}
+
+	// a partial update stops at the last phase the logs show ending.
+	intervals := phaseIntervals(logs, clusterVersion(configv1.PartialUpdate, nil), start, start.Add(2*time.Hour))
+	if len(intervals) != 3 {
+		t.Fatalf("expected three phases, got %v", intervals)
+	}
+
+	completed := metav1.NewTime(start.Add(85 * time.Minute))
+	intervals = phaseIntervals(logs, clusterVersion(configv1.CompletedUpdate, &completed), start, start.Add(2*time.Hour))
+	expected := []struct {
+		name     string
+		from, to time.Duration
+	}{
+		{phaseAccept, time.Minute, 3 * time.Minute},
+		{phaseControlPlane, 3 * time.Minute, 25 * time.Minute},
+		{phasePlatformOperators, 25 * time.Minute, 80 * time.Minute},
+		{phaseFinalize, 80 * time.Minute, 85 * time.Minute},
+	}
+	if len(intervals) != len(expected) {
+		t.Fatalf("expected %d phases, got %v", len(expected), intervals)
+	}
+	for i, curr := range expected {
+		interval := intervals[i]
+		if interval.Message.Annotations[monitorapi.AnnotationPhase] != curr.name || !interval.From.Equal(start.Add(curr.from)) || !interval.To.Equal(start.Add(curr.to)) {
+			t.Errorf("expected %s from %v to %v, got %v", curr.name, curr.from, curr.to, interval)
+		}
+	}
+
+	// without historical data the platform operators phase is over the default, and only a warning.
+	junits, err := evaluatePhases(intervals, platformidentification.JobType{Release: "4.16", FromRelease: "4.16", Platform: "aws", Topology: "ha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(junits) != 4 {
+		t.Fatalf("expected a test per phase, got %v", junits)
+	}
+	for _, junit := range junits {
+		if junit.Name == phaseTestName(phasePlatformOperators) {
+			if junit.FailureOutput == nil || junit.Details.Severity == "" {
+				t.Errorf("expected the platform operators phase to warn, got %#v", junit)
+			}
+			continue
+		}
+		if junit.FailureOutput != nil {
+			t.Errorf("expected %s to pass, got %v", junit.Name, junit.FailureOutput.Output)
+		}
+	}
+}
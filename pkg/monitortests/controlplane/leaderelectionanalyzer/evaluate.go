@@ -0,0 +1,111 @@
+package leaderelectionanalyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// electionGrace is how long after a node update or an operator rollout ends elections are still explained by it.
+// The new leader may only acquire the lease once the lease of the previous one expires.
+const electionGrace = 2 * time.Minute
+
+// explanations are the times leadership is expected to move between the members of a component.
+type explanations struct {
+	// controlPlaneUpdates are the updates of the nodes the control plane runs on.
+	controlPlaneUpdates monitorapi.Intervals
+	// rolloutsByOperator are the times each cluster operator was progressing, restarting its operands.
+	rolloutsByOperator map[string]monitorapi.Intervals
+}
+
+func explanationsFrom(finalIntervals monitorapi.Intervals) explanations {
+	ret := explanations{rolloutsByOperator: map[string]monitorapi.Intervals{}}
+	for _, interval := range finalIntervals {
+		switch {
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeUpdateReason:
+			// node state intervals do not always carry the roles, an update of a node of unknown role may be a control plane one.
+			roles := monitorapi.GetNodeRoles(interval)
+			if len(roles) > 0 && !strings.Contains(roles, "master") && !strings.Contains(roles, "control-plane") {
+				continue
+			}
+			ret.controlPlaneUpdates = append(ret.controlPlaneUpdates, interval)
+
+		case interval.Source == monitorapi.SourceOperatorState:
+			if interval.Message.Annotations[monitorapi.AnnotationCondition] == string(configv1.OperatorProgressing) &&
+				interval.Message.Annotations[monitorapi.AnnotationStatus] == string(configv1.ConditionTrue) {
+				operator := interval.Locator.Keys[monitorapi.LocatorClusterOperatorKey]
+				ret.rolloutsByOperator[operator] = append(ret.rolloutsByOperator[operator], interval)
+			}
+		}
+	}
+	return ret
+}
+
+// explain returns whether a control plane node update or a rollout of the operator of the lease explains the election.
+func (e explanations) explain(lease trackedLease, election monitorapi.Interval) bool {
+	return startedDuring(election, e.controlPlaneUpdates) || startedDuring(election, e.rolloutsByOperator[lease.operator])
+}
+
+func startedDuring(election monitorapi.Interval, windows monitorapi.Intervals) bool {
+	for _, window := range windows {
+		if !election.From.Before(window.From) && !election.From.After(window.To.Add(electionGrace)) {
+			return true
+		}
+	}
+	return false
+}
+
+func electionTestName(lease trackedLease) string {
+	return fmt.Sprintf("[sig-api-machinery] %s leader elections should only happen during control plane node updates or operator rollouts", lease.component)
+}
+
+var unexplainedElectionTemplate = junitfailure.MustParseTemplate("unexplained-leader-election",
+	`{{len .Intervals}} leader elections of {{.Fields.lease}} happened while no control plane node was updating and the {{.Fields.operator}} operator was not rolling out.
+{{.IntervalList}}`)
+
+// evaluateElections produces a test for every tracked lease, failing for elections nothing explains.
+func evaluateElections(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	explanations := explanationsFrom(finalIntervals)
+
+	electionsByLease := map[leaseKey]monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceLeaderElection || interval.Message.Reason != monitorapi.LeaderChangedReason {
+			continue
+		}
+		key := leaseKey{
+			namespace: interval.Locator.Keys[monitorapi.LocatorNamespaceKey],
+			name:      interval.Locator.Keys[leaseLocatorKey],
+		}
+		electionsByLease[key] = append(electionsByLease[key], interval)
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, lease := range trackedLeases {
+		testName := electionTestName(lease)
+		unexplained := monitorapi.Intervals{}
+		for _, election := range electionsByLease[leaseKey{namespace: lease.namespace, name: lease.name}] {
+			if !explanations.explain(lease, election) {
+				unexplained = append(unexplained, election)
+			}
+		}
+		if len(unexplained) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+		junit := junitfailure.NewFailure(unexplainedElectionTemplate, "UnexplainedLeaderElection").
+			Field("lease", lease.namespace+"/"+lease.name).
+			Field("operator", lease.operator).
+			Intervals(unexplained...).
+			TestCase(testName)
+		// a warning until we know how often leaders lose their leases outside of rollouts.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
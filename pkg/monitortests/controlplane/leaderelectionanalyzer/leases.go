@@ -0,0 +1,133 @@
+package leaderelectionanalyzer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// trackedLease is the leader election lease of a control plane component.
+type trackedLease struct {
+	namespace, name string
+	// component names the component in test names.
+	component string
+	// operator rolls out the component, restarting it and handing leadership over while it is progressing.
+	operator string
+}
+
+var trackedLeases = []trackedLease{
+	{namespace: "kube-system", name: "kube-controller-manager", component: "kube-controller-manager", operator: "kube-controller-manager"},
+	{namespace: "openshift-kube-controller-manager", name: "cluster-policy-controller-lock", component: "cluster-policy-controller", operator: "kube-controller-manager"},
+	{namespace: "openshift-kube-scheduler", name: "kube-scheduler", component: "kube-scheduler", operator: "kube-scheduler"},
+	{namespace: "openshift-controller-manager", name: "openshift-master-controllers", component: "openshift-controller-manager", operator: "openshift-controller-manager"},
+	{namespace: "openshift-route-controller-manager", name: "openshift-route-controllers", component: "route-controller-manager", operator: "openshift-controller-manager"},
+}
+
+// leaseLocatorKey locates the lease of an election, see LocatorBuilder.KindInNamespace.
+var leaseLocatorKey = monitorapi.LocatorKey("lease")
+
+type leaseKey struct {
+	namespace, name string
+}
+
+type observedLeader struct {
+	holder string
+	// lastRenew is the last time the holder renewed the lease, when leadership was last known to be held.
+	lastRenew time.Time
+}
+
+// leaseTracker records an interval for every change of the holder of a tracked lease, from the last renewal of the
+// previous holder to the new holder acquiring the lease, the time nobody was leading.
+type leaseTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock    sync.Mutex
+	leaders map[leaseKey]observedLeader
+}
+
+func newLeaseTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *leaseTracker {
+	return &leaseTracker{
+		recorder: recorder,
+		clock:    clock,
+		leaders:  map[leaseKey]observedLeader{},
+	}
+}
+
+// startLeaseMonitoring watches only the tracked leases, every node heartbeat is a lease too.
+func startLeaseMonitoring(ctx context.Context, tracker *leaseTracker, client kubernetes.Interface) {
+	for _, lease := range trackedLeases {
+		name := lease.name
+		kubeInformers := informers.NewSharedInformerFactoryWithOptions(client, 0,
+			informers.WithNamespace(lease.namespace),
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+			}))
+		handler := func(obj interface{}) {
+			if lease, ok := obj.(*coordinationv1.Lease); ok {
+				tracker.observe(lease, tracker.clock.Now())
+			}
+		}
+		kubeInformers.Coordination().V1().Leases().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    handler,
+			UpdateFunc: func(_, obj interface{}) { handler(obj) },
+		})
+		kubeInformers.Start(ctx.Done())
+	}
+}
+
+func (t *leaseTracker) observe(lease *coordinationv1.Lease, now time.Time) {
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+	// a released lease has no holder until the next leader acquires it.
+	if len(holder) == 0 {
+		return
+	}
+	renew := now
+	if lease.Spec.RenewTime != nil {
+		renew = lease.Spec.RenewTime.Time
+	}
+
+	key := leaseKey{namespace: lease.Namespace, name: lease.Name}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	previous, known := t.leaders[key]
+	t.leaders[key] = observedLeader{holder: holder, lastRenew: renew}
+	if !known || previous.holder == holder {
+		return
+	}
+
+	acquired := now
+	if lease.Spec.AcquireTime != nil {
+		acquired = lease.Spec.AcquireTime.Time
+	}
+	from := previous.lastRenew
+	if from.After(acquired) {
+		from = acquired
+	}
+	t.recorder.AddIntervals(leaderChangedInterval(lease.Namespace, lease.Name, previous.holder, holder, from, acquired))
+}
+
+func leaderChangedInterval(namespace, name, previousHolder, holder string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceLeaderElection, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().KindInNamespace("Lease", namespace, name)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.LeaderChangedReason).
+			WithAnnotation(monitorapi.AnnotationHolder, holder).
+			WithAnnotation(monitorapi.AnnotationPreviousHolder, previousHolder).
+			HumanMessage(fmt.Sprintf("leadership moved from %s to %s", previousHolder, holder))).
+		Display().
+		Build(from, to)
+}
@@ -0,0 +1,97 @@
+package leaderelectionanalyzer
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func lease(holder string, acquired, renewed time.Duration) *coordinationv1.Lease {
+	acquireTime := metav1.NewMicroTime(start.Add(acquired))
+	renewTime := metav1.NewMicroTime(start.Add(renewed))
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-scheduler", Name: "kube-scheduler"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: &holder,
+			AcquireTime:    &acquireTime,
+			RenewTime:      &renewTime,
+		},
+	}
+}
+
+func TestLeaseTracker(t *testing.T) {
+	recorder := monitor.NewRecorder()
+	tracker := newLeaseTracker(recorder, clock.RealClock{})
+
+	tracker.observe(lease("master-0", 0, time.Minute), start.Add(time.Minute))
+	tracker.observe(lease("master-0", 0, 2*time.Minute), start.Add(2*time.Minute))
+	tracker.observe(lease("", 0, 2*time.Minute), start.Add(2*time.Minute))
+	tracker.observe(lease("master-1", 3*time.Minute, 3*time.Minute), start.Add(3*time.Minute))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 1 {
+		t.Fatalf("expected only the change of holder to be an election, got %v", intervals)
+	}
+	election := intervals[0]
+	if !election.From.Equal(start.Add(2*time.Minute)) || !election.To.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("expected the election to last from the last renewal to the acquisition, got %v to %v", election.From, election.To)
+	}
+	if annotations := election.Message.Annotations; annotations[monitorapi.AnnotationHolder] != "master-1" || annotations[monitorapi.AnnotationPreviousHolder] != "master-0" {
+		t.Errorf("expected the election to name both holders, got %v", annotations)
+	}
+}
+
+func TestEvaluateElections(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		// explained by the update of a control plane node.
+		leaderChangedInterval("openshift-kube-scheduler", "kube-scheduler", "master-0", "master-1", start.Add(10*time.Minute), start.Add(11*time.Minute)),
+		// explained by the rollout of the kube-controller-manager operator.
+		leaderChangedInterval("kube-system", "kube-controller-manager", "master-0", "master-1", start.Add(31*time.Minute), start.Add(32*time.Minute)),
+		// a worker update does not explain it.
+		leaderChangedInterval("openshift-controller-manager", "openshift-master-controllers", "a", "b", start.Add(41*time.Minute), start.Add(42*time.Minute)),
+
+		monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason)).
+			Build(start.Add(5*time.Minute), start.Add(9*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("worker-0")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason).WithAnnotation(monitorapi.AnnotationRoles, "worker")).
+			Build(start.Add(40*time.Minute), start.Add(45*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceOperatorState, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().ClusterOperator("kube-controller-manager")).
+			Message(monitorapi.NewMessage().Reason("NodeInstaller").
+				WithAnnotation(monitorapi.AnnotationCondition, string(configv1.OperatorProgressing)).
+				WithAnnotation(monitorapi.AnnotationStatus, string(configv1.ConditionTrue))).
+			Build(start.Add(30*time.Minute), start.Add(35*time.Minute)),
+	}
+
+	junits := evaluateElections(intervals)
+	if len(junits) != len(trackedLeases) {
+		t.Fatalf("expected a test per lease, got %v", junits)
+	}
+	for _, junit := range junits {
+		switch junit.Name {
+		case "[sig-api-machinery] openshift-controller-manager leader elections should only happen during control plane node updates or operator rollouts":
+			if junit.FailureOutput == nil {
+				t.Errorf("expected the election during the worker update to fail")
+			} else if junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning, got %q", junit.Details.Severity)
+			}
+		default:
+			if junit.FailureOutput != nil {
+				t.Errorf("expected %q to pass, got %v", junit.Name, junit.FailureOutput.Output)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package leaderelectionanalyzer
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type leaderElectionAnalyzer struct {
+	clock clock.PassiveClock
+}
+
+// NewLeaderElectionAnalyzer records every change of leader of the control plane components and fails for elections
+// that happen while neither a control plane node is updating nor the operator of the component is rolling out.
+func NewLeaderElectionAnalyzer() monitortestframework.MonitorTest {
+	return &leaderElectionAnalyzer{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &leaderElectionAnalyzer{}
+
+func (w *leaderElectionAnalyzer) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *leaderElectionAnalyzer) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, lease := range trackedLeases {
+		testNames = append(testNames, electionTestName(lease))
+	}
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+var _ monitortestframework.OfflineMonitorTest = &leaderElectionAnalyzer{}
+
+func (*leaderElectionAnalyzer) SupportsOffline() bool {
+	return true
+}
+
+func (w *leaderElectionAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	startLeaseMonitoring(ctx, newLeaseTracker(recorder, w.clock), kubeClient)
+	return nil
+}
+
+func (w *leaderElectionAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// the elections are in the recorder.
+	return nil, nil, nil
+}
+
+func (w *leaderElectionAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *leaderElectionAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return evaluateElections(finalIntervals), nil
+}
+
+func (w *leaderElectionAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (w *leaderElectionAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,74 @@
+package staticpodrevisions
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// DefaultMaxRevisions allows for the revisions an upgrade creates, a new image and the config and certificate
+// changes that come with it, with room for a certificate rotation.  Revisions past that are the operator reacting to
+// its own changes.
+const DefaultMaxRevisions = 10
+
+func revisionTestName(operand operand) string {
+	return fmt.Sprintf("%s %s static pod revisions should not thrash", operand.sig, operand.name)
+}
+
+var thrashTemplate = junitfailure.MustParseTemplate("static-pod-revisions",
+	`{{.Fields.operand}} rolled out {{printf "%.0f" .Threshold.Observed}} revisions, more than the {{printf "%.0f" .Threshold.Limit}} allowed.  Every revision restarts the {{.Fields.operand}} pod on every control plane node; {{.Fields.failedInstalls}} installs of a revision failed.
+{{.IntervalList}}`)
+
+// evaluateRevisions returns a test for every operand, failing when it created more revisions during the run than
+// allowed.
+func evaluateRevisions(finalIntervals monitorapi.Intervals, maxRevisions int) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, curr := range operands {
+		operandIntervals := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+			return interval.Source == monitorapi.SourceStaticPodRevision &&
+				interval.Locator.Keys[monitorapi.LocatorStaticPodOperandKey] == curr.name
+		})
+		rollouts := operandIntervals.Filter(func(interval monitorapi.Interval) bool {
+			return interval.Message.Reason == monitorapi.StaticPodRevisionRolloutReason
+		})
+		failedInstalls := operandIntervals.Filter(func(interval monitorapi.Interval) bool {
+			return interval.Message.Reason == monitorapi.StaticPodInstallFailedReason
+		})
+
+		// a rollout counts every revision created since the previous one was seen, which is more than one when the
+		// operator creates revisions faster than it is watched.
+		revisions := 0
+		for _, rollout := range rollouts {
+			count, err := strconv.Atoi(rollout.Message.Annotations[monitorapi.AnnotationCount])
+			if err != nil || count < 1 {
+				count = 1
+			}
+			revisions += count
+		}
+
+		threshold := junitapi.JUnitThreshold{
+			Name:     fmt.Sprintf("%s-static-pod-revisions", curr.name),
+			Limit:    float64(maxRevisions),
+			Observed: float64(revisions),
+			Unit:     "revisions",
+			Exceeded: revisions > maxRevisions,
+		}
+		if !threshold.Exceeded {
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:    revisionTestName(curr),
+				Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+			})
+			continue
+		}
+		ret = append(ret, junitfailure.NewFailure(thrashTemplate, "StaticPodRevisionThrash").
+			Threshold(threshold).
+			Field("operand", curr.name).
+			Field("failedInstalls", len(failedInstalls)).
+			Intervals(rollouts...).
+			TestCase(revisionTestName(curr)))
+	}
+	return ret
+}
@@ -0,0 +1,119 @@
+package staticpodrevisions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type staticPodRevisions struct {
+	maxRevisions       int
+	notSupportedReason error
+	tracker            *revisionTracker
+	clock              clock.PassiveClock
+}
+
+// NewStaticPodRevisions follows the revisions the etcd, kube-apiserver, kube-controller-manager, and kube-scheduler
+// operators roll out to the control plane nodes, and fails when an operator creates more revisions than allowed.
+func NewStaticPodRevisions(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	maxRevisions := info.MaxStaticPodRevisions
+	if maxRevisions == 0 {
+		maxRevisions = DefaultMaxRevisions
+	}
+	return &staticPodRevisions{
+		clock:        clock.RealClock{},
+		maxRevisions: maxRevisions,
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &staticPodRevisions{}
+
+func (w *staticPodRevisions) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *staticPodRevisions) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, curr := range operands {
+		testNames = append(testNames, revisionTestName(curr))
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift", "HyperShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *staticPodRevisions) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	if jobType.Topology == "external" {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the control plane is not run by static pod operators on clusters with an external control plane"}
+		return w.notSupportedReason
+	}
+	dynamicClient, err := dynamic.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	w.tracker = newRevisionTracker(recorder, w.clock)
+	startRevisionMonitoring(ctx, w.tracker, dynamicClient, kubeClient)
+	return nil
+}
+
+func (w *staticPodRevisions) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// the intervals are in the recorder, only the rollouts and installs still open have to be closed.
+	w.tracker.finish(end)
+	return nil, nil, nil
+}
+
+func (w *staticPodRevisions) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *staticPodRevisions) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateRevisions(finalIntervals, w.maxRevisions), nil
+}
+
+func (w *staticPodRevisions) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *staticPodRevisions) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
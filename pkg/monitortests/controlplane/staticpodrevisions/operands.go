@@ -0,0 +1,52 @@
+package staticpodrevisions
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// operand is a control plane component a static pod operator installs on every control plane node, one revision at a
+// time.
+type operand struct {
+	name      string
+	namespace string
+	// resource is the operator resource, named cluster, whose status has the revisions of every node.
+	resource schema.GroupVersionResource
+	// sig owns the test for the operand.
+	sig string
+}
+
+var operands = []operand{
+	{
+		name:      "etcd",
+		namespace: "openshift-etcd",
+		resource:  schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "etcds"},
+		sig:       "[sig-etcd]",
+	},
+	{
+		name:      "kube-apiserver",
+		namespace: "openshift-kube-apiserver",
+		resource:  schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "kubeapiservers"},
+		sig:       "[sig-api-machinery]",
+	},
+	{
+		name:      "kube-controller-manager",
+		namespace: "openshift-kube-controller-manager",
+		resource:  schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "kubecontrollermanagers"},
+		sig:       "[sig-apps]",
+	},
+	{
+		name:      "kube-scheduler",
+		namespace: "openshift-kube-scheduler",
+		resource:  schema.GroupVersionResource{Group: "operator.openshift.io", Version: "v1", Resource: "kubeschedulers"},
+		sig:       "[sig-scheduling]",
+	},
+}
+
+func operandForNamespace(namespace string) (operand, bool) {
+	for _, curr := range operands {
+		if curr.namespace == namespace {
+			return curr, true
+		}
+	}
+	return operand{}, false
+}
@@ -0,0 +1,303 @@
+package staticpodrevisions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// installerPodSelector matches the pods the static pod operators run to install a revision on a node and to prune
+// old revisions from it.
+const installerPodSelector = "app in (installer,pruner)"
+
+type openInterval struct {
+	revision int32
+	id       int
+}
+
+type failedRevision struct {
+	revision int32
+	count    int
+}
+
+type operandState struct {
+	observed       bool
+	latestRevision int32
+	rollout        *openInterval
+	installs       map[string]openInterval
+	lastFailed     map[string]failedRevision
+}
+
+// revisionTracker records the rollout of every revision a static pod operator creates during the run, from when the
+// revision is created until every node runs it, and the install of a revision on each node.  Failed installs and
+// failed installer and pruner pods are recorded when they are first seen.
+type revisionTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock       sync.Mutex
+	operands   map[string]*operandState
+	failedPods map[types.UID]bool
+}
+
+func newRevisionTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *revisionTracker {
+	return &revisionTracker{
+		recorder:   recorder,
+		clock:      clock,
+		operands:   map[string]*operandState{},
+		failedPods: map[types.UID]bool{},
+	}
+}
+
+// startRevisionMonitoring watches the operator resource of every operand and the installer and pruner pods in their
+// namespaces.
+func startRevisionMonitoring(ctx context.Context, tracker *revisionTracker, dynamicClient dynamic.Interface, kubeClient kubernetes.Interface) {
+	operatorInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 0, "", func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", "cluster").String()
+	})
+	for _, curr := range operands {
+		curr := curr
+		observe := func(obj interface{}) {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				return
+			}
+			status, err := staticPodOperatorStatus(u)
+			if err != nil {
+				fmt.Printf("unable to read the status of %s: %v\n", curr.name, err)
+				return
+			}
+			tracker.observeStatus(curr, status, tracker.clock.Now())
+		}
+		operatorInformers.ForResource(curr.resource).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    observe,
+			UpdateFunc: func(_, obj interface{}) { observe(obj) },
+		})
+
+		podInformers := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+			informers.WithNamespace(curr.namespace),
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.LabelSelector = installerPodSelector
+			}))
+		observePod := func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				tracker.observePod(pod, tracker.clock.Now())
+			}
+		}
+		podInformers.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    observePod,
+			UpdateFunc: func(_, obj interface{}) { observePod(obj) },
+		})
+		podInformers.Start(ctx.Done())
+	}
+	operatorInformers.Start(ctx.Done())
+}
+
+func staticPodOperatorStatus(obj *unstructured.Unstructured) (*operatorv1.StaticPodOperatorStatus, error) {
+	status, _, err := unstructured.NestedMap(obj.Object, "status")
+	if err != nil {
+		return nil, err
+	}
+	ret := &operatorv1.StaticPodOperatorStatus{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(status, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (t *revisionTracker) observeStatus(operand operand, status *operatorv1.StaticPodOperatorStatus, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	state, ok := t.operands[operand.name]
+	if !ok {
+		state = &operandState{
+			installs:   map[string]openInterval{},
+			lastFailed: map[string]failedRevision{},
+		}
+		t.operands[operand.name] = state
+	}
+
+	// the revisions and failures from before the run are not counted, installs in progress are still recorded.
+	if !state.observed {
+		state.observed = true
+		state.latestRevision = status.LatestAvailableRevision
+		for _, node := range status.NodeStatuses {
+			state.lastFailed[node.NodeName] = failedRevision{revision: node.LastFailedRevision, count: node.LastFailedCount}
+		}
+	}
+
+	if status.LatestAvailableRevision > state.latestRevision {
+		if state.rollout != nil {
+			// superseded before every node ran it.
+			t.recorder.EndInterval(state.rollout.id, now)
+		}
+		created := status.LatestAvailableRevision - state.latestRevision
+		state.rollout = &openInterval{
+			revision: status.LatestAvailableRevision,
+			id: t.recorder.StartInterval(
+				monitorapi.NewInterval(monitorapi.SourceStaticPodRevision, monitorapi.Info).
+					Locator(monitorapi.NewLocator().StaticPodOperand(operand.name, "")).
+					Message(monitorapi.NewMessage().Reason(monitorapi.StaticPodRevisionRolloutReason).
+						WithAnnotation(monitorapi.AnnotationRevision, strconv.Itoa(int(status.LatestAvailableRevision))).
+						WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(int(created))).
+						HumanMessagef("rolling out revision %d to every node", status.LatestAvailableRevision)).
+					Display().
+					Build(now, time.Time{}),
+			),
+		}
+		state.latestRevision = status.LatestAvailableRevision
+	}
+	if state.rollout != nil && rolledOut(status, state.rollout.revision) {
+		t.recorder.EndInterval(state.rollout.id, now)
+		state.rollout = nil
+	}
+
+	for _, node := range status.NodeStatuses {
+		t.observeNode(operand, state, node, now)
+	}
+}
+
+// rolledOut is true when every node runs the revision, or a newer one.
+func rolledOut(status *operatorv1.StaticPodOperatorStatus, revision int32) bool {
+	for _, node := range status.NodeStatuses {
+		if node.CurrentRevision < revision {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *revisionTracker) observeNode(operand operand, state *operandState, node operatorv1.NodeStatus, now time.Time) {
+	existing, installing := state.installs[node.NodeName]
+	switch {
+	case node.TargetRevision > node.CurrentRevision:
+		if installing && existing.revision == node.TargetRevision {
+			break
+		}
+		if installing {
+			t.recorder.EndInterval(existing.id, now)
+		}
+		state.installs[node.NodeName] = openInterval{
+			revision: node.TargetRevision,
+			id: t.recorder.StartInterval(
+				monitorapi.NewInterval(monitorapi.SourceStaticPodRevision, monitorapi.Info).
+					Locator(monitorapi.NewLocator().StaticPodOperand(operand.name, node.NodeName)).
+					Message(monitorapi.NewMessage().Reason(monitorapi.StaticPodNodeInstallReason).
+						WithAnnotation(monitorapi.AnnotationRevision, strconv.Itoa(int(node.TargetRevision))).
+						HumanMessagef("installing revision %d, replacing revision %d", node.TargetRevision, node.CurrentRevision)).
+					Display().
+					Build(now, time.Time{}),
+			),
+		}
+	case installing:
+		t.recorder.EndInterval(existing.id, now)
+		delete(state.installs, node.NodeName)
+	}
+
+	failed := failedRevision{revision: node.LastFailedRevision, count: node.LastFailedCount}
+	if failed.revision == 0 || failed == state.lastFailed[node.NodeName] {
+		return
+	}
+	state.lastFailed[node.NodeName] = failed
+	from := now
+	if node.LastFailedTime != nil && node.LastFailedTime.Time.Before(now) {
+		from = node.LastFailedTime.Time
+	}
+	message := fmt.Sprintf("installing revision %d failed", node.LastFailedRevision)
+	if len(node.LastFailedRevisionErrors) > 0 {
+		message = fmt.Sprintf("%s: %s", message, strings.Join(node.LastFailedRevisionErrors, "; "))
+	}
+	t.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceStaticPodRevision, monitorapi.Error).
+			Locator(monitorapi.NewLocator().StaticPodOperand(operand.name, node.NodeName)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.StaticPodInstallFailedReason).
+				Cause(node.LastFailedReason).
+				WithAnnotation(monitorapi.AnnotationRevision, strconv.Itoa(int(node.LastFailedRevision))).
+				HumanMessage(message)).
+			Display().
+			Build(from, now.Add(time.Second)),
+	)
+}
+
+// observePod records installer and pruner pods that failed.  The installer is retried, so a failed installer pod is
+// not a failed install until the operator gives up on the revision.
+func (t *revisionTracker) observePod(pod *corev1.Pod, now time.Time) {
+	if pod.Status.Phase != corev1.PodFailed {
+		return
+	}
+	operand, ok := operandForNamespace(pod.Namespace)
+	if !ok {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.failedPods[pod.UID] {
+		return
+	}
+	t.failedPods[pod.UID] = true
+
+	level, reason, kind := monitorapi.Error, monitorapi.StaticPodInstallerPodFailedReason, "installer"
+	if pod.Labels["app"] == "pruner" {
+		level, reason, kind = monitorapi.Warning, monitorapi.StaticPodPrunerPodFailedReason, "pruner"
+	}
+	message := fmt.Sprintf("%s pod for %s failed", kind, operand.name)
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && len(terminated.Message) > 0 {
+			message = fmt.Sprintf("%s: %s", message, lastLine(terminated.Message))
+		}
+	}
+	from := pod.CreationTimestamp.Time
+	if from.IsZero() || !from.Before(now) {
+		from = now
+	}
+	t.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceStaticPodRevision, level).
+			Locator(monitorapi.NewLocator().PodFromPod(pod)).
+			Message(monitorapi.NewMessage().Reason(reason).Node(pod.Spec.NodeName).HumanMessage(message)).
+			Display().
+			Build(from, now),
+	)
+}
+
+// lastLine is the end of a termination message, which for the installer is the log that explains the failure.
+func lastLine(message string) string {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	return lines[len(lines)-1]
+}
+
+// finish ends the rollouts and installs still open at the end of the run.
+func (t *revisionTracker) finish(end time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, state := range t.operands {
+		if state.rollout != nil {
+			t.recorder.EndInterval(state.rollout.id, end)
+			state.rollout = nil
+		}
+		for node, install := range state.installs {
+			t.recorder.EndInterval(install.id, end)
+			delete(state.installs, node)
+		}
+	}
+}
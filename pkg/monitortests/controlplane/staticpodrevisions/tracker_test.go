@@ -0,0 +1,112 @@
+package staticpodrevisions
+
+import (
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func status(latest int32, nodes ...operatorv1.NodeStatus) *operatorv1.StaticPodOperatorStatus {
+	return &operatorv1.StaticPodOperatorStatus{LatestAvailableRevision: latest, NodeStatuses: nodes}
+}
+
+func node(name string, current, target int32) operatorv1.NodeStatus {
+	return operatorv1.NodeStatus{NodeName: name, CurrentRevision: current, TargetRevision: target}
+}
+
+func reasons(intervals monitorapi.Intervals, reason monitorapi.IntervalReason) monitorapi.Intervals {
+	return intervals.Filter(func(interval monitorapi.Interval) bool { return interval.Message.Reason == reason })
+}
+
+func TestTracker(t *testing.T) {
+	kubeAPIServer := operands[1]
+	recorder := monitor.NewRecorder()
+	tracker := newRevisionTracker(recorder, clock.RealClock{})
+
+	failedBeforeTheRun := node("master-1", 4, 0)
+	failedBeforeTheRun.LastFailedRevision = 3
+	// revision 4 was already rolled out when the run started.
+	tracker.observeStatus(kubeAPIServer, status(4, node("master-0", 4, 0), failedBeforeTheRun), start)
+	// revision 5 is created and installed one node at a time.
+	tracker.observeStatus(kubeAPIServer, status(5, node("master-0", 4, 5), failedBeforeTheRun), start.Add(time.Minute))
+	tracker.observeStatus(kubeAPIServer, status(5, node("master-0", 5, 0), failedBeforeTheRun), start.Add(2*time.Minute))
+	failed := node("master-1", 4, 5)
+	failed.LastFailedRevision = 5
+	failed.LastFailedCount = 1
+	failed.LastFailedRevisionErrors = []string{"timed out waiting for the pod"}
+	tracker.observeStatus(kubeAPIServer, status(5, node("master-0", 5, 0), failed), start.Add(3*time.Minute))
+	tracker.observeStatus(kubeAPIServer, status(5, node("master-0", 5, 0), failed), start.Add(4*time.Minute))
+	tracker.observeStatus(kubeAPIServer, status(5, node("master-0", 5, 0), node("master-1", 5, 0)), start.Add(5*time.Minute))
+	// revisions 6 and 7 are created between two updates and are never rolled out before the run ends.
+	tracker.observeStatus(kubeAPIServer, status(7, node("master-0", 5, 7), node("master-1", 5, 0)), start.Add(6*time.Minute))
+	tracker.finish(start.Add(time.Hour))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	rollouts := reasons(intervals, monitorapi.StaticPodRevisionRolloutReason)
+	if len(rollouts) != 2 {
+		t.Fatalf("expected the rollouts of revisions 5 and 7, got %v", rollouts)
+	}
+	if !rollouts[0].From.Equal(start.Add(time.Minute)) || !rollouts[0].To.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("expected revision 5 to roll out until the last node ran it, got %v to %v", rollouts[0].From, rollouts[0].To)
+	}
+	if count := rollouts[1].Message.Annotations[monitorapi.AnnotationCount]; count != "2" {
+		t.Errorf("expected the rollout of revision 7 to count two revisions, got %v", count)
+	}
+	if installs := reasons(intervals, monitorapi.StaticPodNodeInstallReason); len(installs) != 3 {
+		t.Errorf("expected three node installs, got %v", installs)
+	}
+	if failures := reasons(intervals, monitorapi.StaticPodInstallFailedReason); len(failures) != 1 {
+		t.Errorf("expected only the failure during the run, got %v", failures)
+	}
+
+	for _, junit := range evaluateRevisions(intervals, 3) {
+		if junit.FailureOutput != nil {
+			t.Errorf("expected %q to pass with three revisions allowed, got %v", junit.Name, junit.FailureOutput.Output)
+		}
+		if junit.Name == revisionTestName(kubeAPIServer) && junit.Details.Thresholds[0].Observed != 3 {
+			t.Errorf("expected three revisions, got %v", junit.Details.Thresholds[0].Observed)
+		}
+	}
+	for _, junit := range evaluateRevisions(intervals, 2) {
+		failed := junit.FailureOutput != nil
+		if expected := junit.Name == revisionTestName(kubeAPIServer); failed != expected {
+			t.Errorf("expected %q to fail=%v, got %v", junit.Name, expected, junit.FailureOutput)
+		}
+	}
+}
+
+func TestObservePod(t *testing.T) {
+	recorder := monitor.NewRecorder()
+	tracker := newRevisionTracker(recorder, clock.RealClock{})
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "openshift-etcd",
+			Name:              "revision-pruner-7-master-0",
+			UID:               "uid",
+			Labels:            map[string]string{"app": "pruner"},
+			CreationTimestamp: metav1.NewTime(start),
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	tracker.observePod(pod, start.Add(time.Second))
+	pod.Status.Phase = corev1.PodFailed
+	tracker.observePod(pod, start.Add(time.Minute))
+	tracker.observePod(pod, start.Add(2*time.Minute))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 1 || intervals[0].Message.Reason != monitorapi.StaticPodPrunerPodFailedReason {
+		t.Fatalf("expected one failed pruner, got %v", intervals)
+	}
+	if !intervals[0].To.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected the failure to end when it was first seen, got %v", intervals[0].To)
+	}
+}
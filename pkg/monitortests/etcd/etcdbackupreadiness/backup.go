@@ -0,0 +1,185 @@
+package etcdbackupreadiness
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	backupTestName = "[sig-etcd] etcd backup should produce a snapshot that can be restored"
+
+	backupFilePrefix      = "backup-file: "
+	snapshotStatusPrefix  = "snapshot-status: "
+	snapshotRestorePrefix = "snapshot-restore: "
+
+	// maxBackupDuration is long enough for a large etcd on a slow disk.  A backup that takes longer usually means the
+	// script is waiting on an image pull or on an unreachable member.
+	maxBackupDuration = 5 * time.Minute
+)
+
+// snapshotStatus is the output of etcdutl snapshot status.
+type snapshotStatus struct {
+	Hash      uint32 `json:"hash"`
+	Revision  int64  `json:"revision"`
+	TotalKey  int    `json:"totalKey"`
+	TotalSize int64  `json:"totalSize"`
+	Version   string `json:"version,omitempty"`
+}
+
+// backupResult is what the backup pod reported, and is written as the artifact.
+type backupResult struct {
+	Node     string    `json:"node,omitempty"`
+	Started  time.Time `json:"started,omitempty"`
+	Finished time.Time `json:"finished,omitempty"`
+	// Files maps the files cluster-backup.sh wrote to their size in bytes.
+	Files          map[string]int64 `json:"files,omitempty"`
+	Snapshot       *snapshotStatus  `json:"snapshot,omitempty"`
+	RestoreChecked bool             `json:"restoreChecked"`
+	ExitCode       int32            `json:"exitCode"`
+	// Problems are the reasons the backup is not usable, empty when it is.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// parseBackupLog reads the lines the backup pod logged.  Everything else in the log is the output of the backup
+// script and is ignored.
+func parseBackupLog(log string) (*backupResult, error) {
+	ret := &backupResult{Files: map[string]int64{}}
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, backupFilePrefix):
+			name, size, ok := strings.Cut(strings.TrimPrefix(line, backupFilePrefix), " ")
+			if !ok {
+				return nil, fmt.Errorf("unable to parse backup file %q", line)
+			}
+			bytes, err := strconv.ParseInt(size, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse the size of backup file %q: %w", name, err)
+			}
+			ret.Files[name] = bytes
+
+		case strings.HasPrefix(line, snapshotStatusPrefix):
+			status := &snapshotStatus{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, snapshotStatusPrefix)), status); err != nil {
+				return nil, fmt.Errorf("unable to parse snapshot status: %w", err)
+			}
+			ret.Snapshot = status
+
+		case strings.HasPrefix(line, snapshotRestorePrefix):
+			ret.RestoreChecked = strings.TrimPrefix(line, snapshotRestorePrefix) == "ok"
+		}
+	}
+	return ret, scanner.Err()
+}
+
+// problems lists what makes the backup unusable for a restore.  cluster-backup.sh writes the snapshot and a tarball
+// of the static pod resources, and a restore needs both.
+func (r *backupResult) problems() []string {
+	ret := []string{}
+	if r.ExitCode != 0 {
+		ret = append(ret, fmt.Sprintf("the backup pod exited with %d", r.ExitCode))
+	}
+	hasSnapshot, hasStaticResources := false, false
+	for name, size := range r.Files {
+		switch {
+		case strings.HasPrefix(name, "snapshot_") && strings.HasSuffix(name, ".db"):
+			hasSnapshot = true
+			if size == 0 {
+				ret = append(ret, fmt.Sprintf("the snapshot %s is empty", name))
+			}
+		case strings.HasPrefix(name, "static_kuberesources_") && strings.HasSuffix(name, ".tar.gz"):
+			hasStaticResources = true
+		}
+	}
+	if !hasSnapshot {
+		ret = append(ret, "cluster-backup.sh did not write a snapshot")
+	}
+	if !hasStaticResources {
+		ret = append(ret, "cluster-backup.sh did not write the static pod resources")
+	}
+	switch {
+	case r.Snapshot == nil:
+		ret = append(ret, "etcdutl could not read the snapshot status")
+	case r.Snapshot.TotalKey == 0 || r.Snapshot.Revision == 0:
+		ret = append(ret, fmt.Sprintf("the snapshot has %d keys at revision %d, a cluster always has keys", r.Snapshot.TotalKey, r.Snapshot.Revision))
+	}
+	if !r.RestoreChecked {
+		ret = append(ret, "etcdutl could not restore the snapshot, its hash may not match its content")
+	}
+	if duration := r.Finished.Sub(r.Started); duration > maxBackupDuration {
+		ret = append(ret, fmt.Sprintf("the backup took %v, more than %v", duration.Round(time.Second), maxBackupDuration))
+	}
+	return ret
+}
+
+func backupInterval(result *backupResult) monitorapi.Interval {
+	level, reason := monitorapi.Info, monitorapi.EtcdBackupCompletedReason
+	message := "etcd backup completed"
+	if len(result.Problems) > 0 {
+		level, reason = monitorapi.Error, monitorapi.EtcdBackupFailedReason
+		message = fmt.Sprintf("etcd backup failed: %s", strings.Join(result.Problems, ", "))
+	} else if result.Snapshot != nil {
+		message = fmt.Sprintf("etcd backup completed with %d keys at revision %d, %d bytes", result.Snapshot.TotalKey, result.Snapshot.Revision, result.Snapshot.TotalSize)
+	}
+	to := result.Finished
+	if !to.After(result.Started) {
+		to = result.Started.Add(time.Second)
+	}
+	return monitorapi.NewInterval(monitorapi.SourceEtcdBackup, level).
+		Locator(monitorapi.NewLocator().NodeFromName(result.Node)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage(message)).
+		Display().
+		Build(result.Started, to)
+}
+
+var backupTemplate = junitfailure.MustParseTemplate("etcd-backup",
+	`the etcd backup on {{.Fields.node}} cannot be restored from: {{.Fields.problems}}.  The backup is what disaster recovery starts from, see the etcd-backup-readiness artifact and the backup pod logs below.
+{{.IntervalList}}`)
+
+// evaluateBackup fails when the backup taken during the run is not usable for a restore.
+func evaluateBackup(finalIntervals monitorapi.Intervals, result *backupResult, podLog string) *junitapi.JUnitTestCase {
+	if result == nil {
+		return &junitapi.JUnitTestCase{
+			Name:      backupTestName,
+			SystemOut: podLog,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: "the etcd backup pod did not finish",
+			},
+		}
+	}
+	threshold := junitapi.JUnitThreshold{
+		Name:     "etcd-backup-duration",
+		Limit:    maxBackupDuration.Seconds(),
+		Observed: result.Finished.Sub(result.Started).Seconds(),
+		Unit:     "seconds",
+		Exceeded: result.Finished.Sub(result.Started) > maxBackupDuration,
+	}
+	if len(result.Problems) == 0 {
+		return &junitapi.JUnitTestCase{
+			Name:      backupTestName,
+			SystemOut: podLog,
+			Details:   &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+
+	failed := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceEtcdBackup && interval.Message.Reason == monitorapi.EtcdBackupFailedReason
+	})
+	junit := junitfailure.NewFailure(backupTemplate, "EtcdBackupUnusable").
+		Threshold(threshold).
+		Field("node", result.Node).
+		Field("problems", strings.Join(result.Problems, ", ")).
+		Intervals(failed...).
+		TestCase(backupTestName)
+	junit.SystemOut = fmt.Sprintf("%s\n\n%s", junit.SystemOut, podLog)
+	return junit
+}
@@ -0,0 +1,131 @@
+package etcdbackupreadiness
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const healthyLog = `etcdctl is already installed
+{"level":"info","ts":"2024-01-01T00:00:10.000Z","caller":"snapshot/v3_snapshot.go:65","msg":"created temporary db file","path":"/var/tmp/e2e-etcd-backup-readiness/snapshot_2024-01-01_000010.db.part"}
+Snapshot saved at /var/tmp/e2e-etcd-backup-readiness/snapshot_2024-01-01_000010.db
+backup-file: snapshot_2024-01-01_000010.db 104857632
+backup-file: static_kuberesources_2024-01-01_000010.tar.gz 81234
+snapshot-status: {"hash":3120483954,"revision":81234,"totalKey":9876,"totalSize":104857600,"version":"3.5.0"}
+snapshot-restore: ok
+`
+
+func healthyResult(t *testing.T) *backupResult {
+	result, err := parseBackupLog(healthyLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.Node = "master-0"
+	result.Started = start
+	result.Finished = start.Add(30 * time.Second)
+	return result
+}
+
+func TestParseBackupLog(t *testing.T) {
+	result := healthyResult(t)
+	if len(result.Files) != 2 || result.Files["snapshot_2024-01-01_000010.db"] != 104857632 {
+		t.Errorf("unexpected files %v", result.Files)
+	}
+	if result.Snapshot == nil || result.Snapshot.TotalKey != 9876 || result.Snapshot.Revision != 81234 {
+		t.Errorf("unexpected snapshot status %#v", result.Snapshot)
+	}
+	if !result.RestoreChecked {
+		t.Error("expected the restore to be checked")
+	}
+	if problems := result.problems(); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	if _, err := parseBackupLog("snapshot-status: {not json"); err == nil {
+		t.Error("expected an error for a snapshot status that is not json")
+	}
+}
+
+func TestProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(*backupResult)
+		expected string
+	}{
+		{
+			name:     "script failed",
+			mutate:   func(r *backupResult) { r.ExitCode = 1 },
+			expected: "exited with 1",
+		},
+		{
+			name: "no snapshot",
+			mutate: func(r *backupResult) {
+				delete(r.Files, "snapshot_2024-01-01_000010.db")
+			},
+			expected: "did not write a snapshot",
+		},
+		{
+			name: "no static resources",
+			mutate: func(r *backupResult) {
+				delete(r.Files, "static_kuberesources_2024-01-01_000010.tar.gz")
+			},
+			expected: "static pod resources",
+		},
+		{
+			name:     "empty snapshot",
+			mutate:   func(r *backupResult) { r.Snapshot.TotalKey = 0 },
+			expected: "0 keys",
+		},
+		{
+			name:     "restore failed",
+			mutate:   func(r *backupResult) { r.RestoreChecked = false },
+			expected: "could not restore",
+		},
+		{
+			name:     "slow",
+			mutate:   func(r *backupResult) { r.Finished = r.Started.Add(maxBackupDuration + time.Minute) },
+			expected: "the backup took 6m0s",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := healthyResult(t)
+			test.mutate(result)
+			problems := result.problems()
+			if len(problems) != 1 || !strings.Contains(problems[0], test.expected) {
+				t.Errorf("expected one problem containing %q, got %v", test.expected, problems)
+			}
+		})
+	}
+}
+
+func TestEvaluateBackup(t *testing.T) {
+	result := healthyResult(t)
+	result.Problems = result.problems()
+	healthy := evaluateBackup(monitorapi.Intervals{backupInterval(result)}, result, healthyLog)
+	if healthy.FailureOutput != nil {
+		t.Errorf("expected a usable backup to pass, got %v", healthy.FailureOutput.Output)
+	}
+
+	result.RestoreChecked = false
+	result.Problems = result.problems()
+	interval := backupInterval(result)
+	if interval.Message.Reason != monitorapi.EtcdBackupFailedReason {
+		t.Errorf("expected a failed backup interval, got %v", interval.Message.Reason)
+	}
+	failed := evaluateBackup(monitorapi.Intervals{interval}, result, healthyLog)
+	if failed.FailureOutput == nil || !strings.Contains(failed.FailureOutput.Output, "master-0") {
+		t.Errorf("expected the failure to name the node, got %#v", failed.FailureOutput)
+	}
+	if len(failed.Details.IntervalIDs) != 1 {
+		t.Errorf("expected the failed backup interval in the details, got %v", failed.Details.IntervalIDs)
+	}
+
+	if unfinished := evaluateBackup(nil, nil, ""); unfinished.FailureOutput == nil {
+		t.Error("expected a backup that did not finish to fail")
+	}
+}
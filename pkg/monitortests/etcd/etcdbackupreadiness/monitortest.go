@@ -0,0 +1,256 @@
+package etcdbackupreadiness
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace *corev1.Namespace
+	backupPod *corev1.Pod
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	backupPod = resourceread.ReadPodV1OrDie(yamlOrDie("backup-pod.yaml"))
+}
+
+// backupTimeout is how long CollectData waits for a backup that has not finished yet.  The backup starts with the
+// run, so it has usually finished long before.
+const backupTimeout = 10 * time.Minute
+
+type etcdBackupReadiness struct {
+	notSupportedReason error
+
+	kubeClient    kubernetes.Interface
+	namespaceName string
+
+	result *backupResult
+	podLog string
+}
+
+// NewEtcdBackupReadiness takes an etcd backup with cluster-backup.sh on a control plane node while the tests run,
+// checks the snapshot offline with etcdutl, and fails when the backup could not be restored from.
+func NewEtcdBackupReadiness() monitortestframework.MonitorTest {
+	return &etcdBackupReadiness{}
+}
+
+func (w *etcdBackupReadiness) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		// etcd runs on the management cluster of hosted control planes.
+		UnsupportedPlatforms: []string{"MicroShift", "HyperShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.WriteContentToStorageStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: []string{backupTestName},
+	}
+}
+
+func (w *etcdBackupReadiness) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	if jobType.Topology == "external" {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "etcd is not part of clusters with an external control plane"}
+		return w.notSupportedReason
+	}
+
+	etcdImage, err := w.etcdImagePullSpec(ctx)
+	if err != nil {
+		return err
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	pod := backupPod.DeepCopy()
+	pod.Spec.Containers[0].Image = etcdImage
+	if _, err := w.kubeClient.CoreV1().Pods(w.namespaceName).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// etcdImagePullSpec is the image of the running etcd members, so the etcdutl that checks the snapshot is the one a
+// restore would use.
+func (w *etcdBackupReadiness) etcdImagePullSpec(ctx context.Context) (string, error) {
+	pods, err := w.kubeClient.CoreV1().Pods("openshift-etcd").List(ctx, metav1.ListOptions{LabelSelector: "app=etcd"})
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == "etcd" {
+				return container.Image, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no etcd pods found in openshift-etcd")
+}
+
+func (w *etcdBackupReadiness) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	var pod *corev1.Pod
+	err := wait.PollUntilContextTimeout(ctx, 10*time.Second, backupTimeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		pod, err = w.kubeClient.CoreV1().Pods(w.namespaceName).Get(ctx, backupPod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if pod != nil && len(pod.Spec.NodeName) > 0 {
+		w.podLog, _ = w.backupPodLog(ctx)
+	}
+	if err != nil {
+		// the test fails for a backup that did not finish, rather than the whole monitor.
+		klog.Errorf("etcd backup pod did not finish: %v", err)
+		return nil, nil, nil
+	}
+
+	w.result, err = parseBackupLog(w.podLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.result.Node = pod.Spec.NodeName
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil {
+			w.result.Started = terminated.StartedAt.Time
+			w.result.Finished = terminated.FinishedAt.Time
+			w.result.ExitCode = terminated.ExitCode
+		}
+	}
+	w.result.Problems = w.result.problems()
+
+	return monitorapi.Intervals{backupInterval(w.result)}, nil, nil
+}
+
+func (w *etcdBackupReadiness) backupPodLog(ctx context.Context) (string, error) {
+	logStream, err := w.kubeClient.CoreV1().Pods(w.namespaceName).GetLogs(backupPod.Name, &corev1.PodLogOptions{}).Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer logStream.Close()
+	content, err := io.ReadAll(logStream)
+	return string(content), err
+}
+
+func (w *etcdBackupReadiness) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *etcdBackupReadiness) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return []*junitapi.JUnitTestCase{evaluateBackup(finalIntervals, w.result, w.podLog)}, nil
+}
+
+func (w *etcdBackupReadiness) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.notSupportedReason != nil {
+		return w.notSupportedReason
+	}
+	if w.result == nil {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(w.result, "", "    ")
+	if err != nil {
+		return err
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("etcd-backup-readiness%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
+
+// ArtifactSchema describes the backup result for the artifact index.
+func (w *etcdBackupReadiness) ArtifactSchema(relativePath string) string {
+	if strings.HasPrefix(path.Base(relativePath), "etcd-backup-readiness") {
+		return "etcd-backup-readiness/v1"
+	}
+	return ""
+}
+
+func (w *etcdBackupReadiness) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+	return false, nil
+}
+
+func (w *etcdBackupReadiness) Cleanup(ctx context.Context) error {
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		if err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted); err != nil {
+			return err
+		}
+	}
+	return w.notSupportedReason
+}
@@ -0,0 +1,232 @@
+package etcdhealth
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/podaccess"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	electionsTestName = "[sig-etcd] etcd leader elections should not exceed the platform threshold"
+	quorumTestName    = "[sig-etcd] etcd should not lose quorum"
+)
+
+// maxElectionsByPlatform allows for the elections caused by rolling every member during an upgrade, plus the
+// elections slower disks cause on some platforms.
+var maxElectionsByPlatform = map[string]int{
+	"aws":     10,
+	"gcp":     10,
+	"azure":   15,
+	"metal":   15,
+	"vsphere": 15,
+}
+
+const defaultMaxElections = 12
+
+func maxElectionsFor(platform string) int {
+	if limit, ok := maxElectionsByPlatform[platform]; ok {
+		return limit
+	}
+	return defaultMaxElections
+}
+
+func maxValue(matrix prometheustypes.Matrix) int {
+	ret := 0
+	for _, series := range matrix {
+		for _, sample := range series.Values {
+			if int(sample.Value) > ret {
+				ret = int(sample.Value)
+			}
+		}
+	}
+	return ret
+}
+
+// quorumIntervals reports when fewer than every member saw a leader as degraded, and when fewer than a majority did
+// as lost.
+func quorumIntervals(membersWithLeader prometheustypes.Matrix, members int, step time.Duration) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	if members == 0 {
+		return ret
+	}
+	quorum := members/2 + 1
+	for _, series := range membersWithLeader {
+		// lost windows are inside degraded windows, so both are reported.
+		for _, window := range prometheusaccess.Windows(series.Values, step, func(value float64) bool { return int(value) < members }) {
+			ret = append(ret, quorumInterval(window, monitorapi.EtcdQuorumDegradedReason, monitorapi.Warning,
+				fmt.Sprintf("fewer than all %d etcd members saw a leader, etcd cannot tolerate another member failing", members)))
+		}
+		for _, window := range prometheusaccess.Windows(series.Values, step, func(value float64) bool { return int(value) < quorum }) {
+			ret = append(ret, quorumInterval(window, monitorapi.EtcdQuorumLostReason, monitorapi.Error,
+				fmt.Sprintf("fewer than %d of %d etcd members saw a leader, etcd lost quorum", quorum, members)))
+		}
+	}
+	return ret
+}
+
+func quorumInterval(window prometheusaccess.Window, reason monitorapi.IntervalReason, level monitorapi.IntervalLevel, message string) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceEtcdHealth, level).
+		Locator(monitorapi.NewLocator().LocateNamespace("openshift-etcd")).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage(message)).
+		Display().
+		Build(window.From, window.To)
+}
+
+// perMemberIntervals reports the windows in which each member's series matches.
+func perMemberIntervals(matrix prometheustypes.Matrix, step time.Duration, matches func(float64) bool, reason monitorapi.IntervalReason, level monitorapi.IntervalLevel, message func(peak float64) string) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, series := range matrix {
+		pod := string(series.Metric["pod"])
+		for _, window := range prometheusaccess.Windows(series.Values, step, matches) {
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceEtcdHealth, level).
+					Locator(monitorapi.NewLocator().PodFromNames("openshift-etcd", pod, "")).
+					Message(monitorapi.NewMessage().Reason(reason).HumanMessage(message(window.Peak))).
+					Display().
+					Build(window.From, window.To),
+			)
+		}
+	}
+	return ret
+}
+
+// electionIntervals finds elections in the leadership intervals from the etcd logs.  An election starts when a
+// member loses its leader, or when a new term is first seen if nobody reported the loss, and ends when a leader is
+// found at a newer term.  Every member logs the same election, so only the first report of each term counts.
+func electionIntervals(startingIntervals monitorapi.Intervals) monitorapi.Intervals {
+	leadership := monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourceEtcdLeadership {
+			continue
+		}
+		switch interval.Message.Reason {
+		case "LeaderLost", "LeaderMissing", "LeaderFound", "LeaderElected":
+			leadership = append(leadership, interval)
+		}
+	}
+	sort.SliceStable(leadership, func(i, j int) bool {
+		return leadership[i].From.Before(leadership[j].From)
+	})
+
+	podsToNode := podaccess.NonUniquePodToNode(startingIntervals)
+	etcdMemberIDToPod := podaccess.NonUniqueEtcdMemberToPod(startingIntervals)
+
+	ret := monitorapi.Intervals{}
+	currentTerm := 0
+	var electionStart *time.Time
+	for _, interval := range leadership {
+		term, _ := strconv.Atoi(interval.Message.Annotations[monitorapi.AnnotationEtcdTerm])
+		switch interval.Message.Reason {
+		case "LeaderLost", "LeaderMissing":
+			if electionStart == nil {
+				from := interval.From
+				electionStart = &from
+			}
+			continue
+		}
+
+		leader := interval.Message.Annotations[monitorapi.AnnotationEtcdLeader]
+		switch {
+		case term < currentTerm || len(leader) == 0:
+			continue
+		case term == currentTerm:
+			// the leader is reachable again without an election.
+			electionStart = nil
+			continue
+		case currentTerm == 0 && electionStart == nil:
+			// the first leader seen was elected before the monitor started.
+			currentTerm = term
+			continue
+		}
+
+		from := interval.From
+		if electionStart != nil {
+			from = *electionStart
+		}
+		to := interval.From
+		if !to.After(from) {
+			to = from.Add(time.Second)
+		}
+		leaderNode := podsToNode[etcdMemberIDToPod[leader]]
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceEtcdLeadership, monitorapi.Warning).
+				Locator(monitorapi.NewLocator().EtcdMemberFromNames(leaderNode, leader)).
+				Message(monitorapi.NewMessage().
+					Reason(monitorapi.EtcdLeaderElectionReason).
+					Constructed(monitorapi.ConstructionOwnerEtcdLifecycle).
+					WithAnnotation(monitorapi.AnnotationEtcdLeader, leader).
+					WithAnnotation(monitorapi.AnnotationEtcdTerm, strconv.Itoa(term)).
+					HumanMessage(fmt.Sprintf("etcd elected %v at term %d", leader, term))).
+				Display().
+				Build(from, to),
+		)
+		currentTerm = term
+		electionStart = nil
+	}
+	return ret
+}
+
+var electionsTemplate = junitfailure.MustParseTemplate("etcd-elections",
+	`etcd held {{printf "%.0f" .Threshold.Observed}} leader elections, more than the {{printf "%.0f" .Threshold.Limit}} allowed on {{.Fields.platform}}.  Elections block writes until a leader is elected and usually point at slow disks or networking between control plane nodes.
+{{.IntervalList}}`)
+
+// evaluateElections is a warning until we know the thresholds hold across platforms; the failure budget decides
+// whether it fails the job.
+func evaluateElections(platform string, finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	elections := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Message.Reason == monitorapi.EtcdLeaderElectionReason
+	})
+	limit := maxElectionsFor(platform)
+	threshold := junitapi.JUnitThreshold{
+		Name:     "etcd-leader-elections",
+		Limit:    float64(limit),
+		Observed: float64(len(elections)),
+		Unit:     "elections",
+		Exceeded: len(elections) > limit,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    electionsTestName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+
+	if len(platform) == 0 {
+		platform = "this platform"
+	}
+	junit := junitfailure.NewFailure(electionsTemplate, "EtcdLeaderElectionsExceeded").
+		Threshold(threshold).
+		Field("platform", platform).
+		Intervals(elections...).
+		TestCase(electionsTestName)
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+var quorumTemplate = junitfailure.MustParseTemplate("etcd-quorum",
+	`etcd lost quorum {{len .Intervals}} times, writes to the cluster failed while it was lost.
+{{.IntervalList}}`)
+
+func evaluateQuorum(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	lost := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceEtcdHealth && interval.Message.Reason == monitorapi.EtcdQuorumLostReason
+	})
+	if len(lost) == 0 {
+		return &junitapi.JUnitTestCase{Name: quorumTestName}
+	}
+	junit := junitfailure.NewFailure(quorumTemplate, "EtcdQuorumLost").
+		Intervals(lost...).
+		TestCase(quorumTestName)
+	// a warning until we know scrape gaps do not look like lost members.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,121 @@
+package etcdhealth
+
+import (
+	"testing"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func leadership(offset time.Duration, reason, leader, term string) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceEtcdLeadership, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().PodFromNames("openshift-etcd", "etcd-master-0", "")).
+		Message(monitorapi.NewMessage().
+			Reason(monitorapi.IntervalReason(reason)).
+			WithAnnotation(monitorapi.AnnotationEtcdLeader, leader).
+			WithAnnotation(monitorapi.AnnotationEtcdTerm, term).
+			HumanMessage(reason)).
+		Build(start.Add(offset), start.Add(offset+time.Second))
+}
+
+func TestElectionIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		// the leader when the monitor started is not an election.
+		leadership(0, "LeaderFound", "aaa", "2"),
+		leadership(time.Second, "LeaderFound", "aaa", "2"),
+		// an election with a reported loss, seen by two members.
+		leadership(time.Minute, "LeaderLost", "", "2"),
+		leadership(time.Minute+5*time.Second, "LeaderElected", "bbb", "3"),
+		leadership(time.Minute+6*time.Second, "LeaderFound", "bbb", "3"),
+		// a loss that recovers without an election.
+		leadership(2*time.Minute, "LeaderMissing", "", "3"),
+		leadership(2*time.Minute+time.Second, "LeaderFound", "bbb", "3"),
+		// an election nobody reported the loss for.
+		leadership(3*time.Minute, "LeaderFound", "ccc", "4"),
+	}
+
+	elections := electionIntervals(intervals)
+	if len(elections) != 2 {
+		t.Fatalf("expected 2 elections, got %d: %v", len(elections), elections)
+	}
+	if from, to := elections[0].From, elections[0].To; !from.Equal(start.Add(time.Minute)) || !to.Equal(start.Add(time.Minute+5*time.Second)) {
+		t.Errorf("expected the first election from the loss to the new leader, got %v to %v", from, to)
+	}
+	if leader := elections[1].Message.Annotations[monitorapi.AnnotationEtcdLeader]; leader != "ccc" {
+		t.Errorf("expected the second election to elect ccc, got %v", leader)
+	}
+	for _, election := range elections {
+		if election.Message.Reason != monitorapi.EtcdLeaderElectionReason {
+			t.Errorf("unexpected reason %v", election.Message.Reason)
+		}
+	}
+}
+
+func TestQuorumIntervals(t *testing.T) {
+	sample := func(offset time.Duration, value float64) prometheustypes.SamplePair {
+		return prometheustypes.SamplePair{
+			Timestamp: prometheustypes.TimeFromUnixNano(start.Add(offset).UnixNano()),
+			Value:     prometheustypes.SampleValue(value),
+		}
+	}
+	membersWithLeader := prometheustypes.Matrix{
+		{Values: []prometheustypes.SamplePair{
+			sample(0, 3),
+			sample(queryStep, 2),
+			sample(2*queryStep, 1),
+			sample(3*queryStep, 2),
+			sample(4*queryStep, 3),
+		}},
+	}
+
+	intervals := quorumIntervals(membersWithLeader, 3, queryStep)
+	degraded, lost := 0, 0
+	for _, interval := range intervals {
+		switch interval.Message.Reason {
+		case monitorapi.EtcdQuorumDegradedReason:
+			degraded++
+			if !interval.From.Equal(start.Add(queryStep)) || !interval.To.Equal(start.Add(4*queryStep)) {
+				t.Errorf("unexpected degraded window %v to %v", interval.From, interval.To)
+			}
+		case monitorapi.EtcdQuorumLostReason:
+			lost++
+			if !interval.From.Equal(start.Add(2*queryStep)) || !interval.To.Equal(start.Add(3*queryStep)) {
+				t.Errorf("unexpected lost window %v to %v", interval.From, interval.To)
+			}
+		}
+	}
+	if degraded != 1 || lost != 1 {
+		t.Errorf("expected one degraded and one lost window, got %d and %d", degraded, lost)
+	}
+}
+
+func TestEvaluateElections(t *testing.T) {
+	elections := monitorapi.Intervals{}
+	for i := 0; i < 11; i++ {
+		elections = append(elections,
+			monitorapi.NewInterval(monitorapi.SourceEtcdLeadership, monitorapi.Warning).
+				Locator(monitorapi.NewLocator().EtcdMemberFromNames("master-0", "aaa")).
+				Message(monitorapi.NewMessage().Reason(monitorapi.EtcdLeaderElectionReason).HumanMessage("elected")).
+				Build(start.Add(time.Duration(i)*time.Minute), start.Add(time.Duration(i)*time.Minute+time.Second)),
+		)
+	}
+
+	if junit := evaluateElections("azure", elections); junit.FailureOutput != nil {
+		t.Errorf("expected 11 elections to be allowed on azure, got %v", junit.FailureOutput.Output)
+	}
+	junit := evaluateElections("aws", elections)
+	if junit.FailureOutput == nil {
+		t.Fatalf("expected 11 elections to fail on aws")
+	}
+	if junit.Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %v", junit.Details.Severity)
+	}
+	if len(junit.Details.IntervalIDs) != len(elections) {
+		t.Errorf("expected the elections to be linked, got %v", junit.Details.IntervalIDs)
+	}
+}
@@ -0,0 +1,148 @@
+package etcdhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	queryStep = 30 * time.Second
+
+	// members that see a leader.  Members that cannot be scraped have no samples, so they do not count.
+	membersWithLeaderQuery = `sum(etcd_server_has_leader{job="etcd"})`
+	membersQuery           = `count(up{job="etcd"})`
+	memberHasLeaderQuery   = `etcd_server_has_leader{job="etcd"}`
+	fsyncQuery             = `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket{job="etcd"}[2m])))`
+	compactionQuery        = `histogram_quantile(0.99, sum by (pod, le) (rate(etcd_debugging_mvcc_db_compaction_total_duration_milliseconds_bucket{job="etcd"}[5m])))`
+
+	// slowFsyncSeconds matches the etcdHighFsyncDurations alert.
+	slowFsyncSeconds = 0.5
+	// slowCompactionMilliseconds is when compaction starts to noticeably block writes.
+	slowCompactionMilliseconds = 1000
+)
+
+type etcdHealth struct {
+	adminRESTConfig    *rest.Config
+	platform           string
+	notSupportedReason error
+}
+
+// NewEtcdHealth tracks etcd member health, quorum, slow disks, and leader elections.
+func NewEtcdHealth() monitortestframework.MonitorTest {
+	return &etcdHealth{}
+}
+
+func (w *etcdHealth) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		// etcd runs on the management cluster of hosted control planes.
+		UnsupportedPlatforms: []string{"HyperShift"},
+		TestNames:            []string{electionsTestName, quorumTestName},
+	}
+}
+
+func (w *etcdHealth) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		// the default thresholds are used.
+		fmt.Printf("unable to determine the platform for etcd thresholds: %v\n", err)
+		return nil
+	}
+	if jobType.Topology == "external" {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "etcd is not part of clusters with an external control plane"}
+		return nil
+	}
+	w.platform = jobType.Platform
+	return nil
+}
+
+func (w *etcdHealth) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, w.adminRESTConfig)
+	if errors.Is(err, prometheusaccess.ErrMonitoringNotInstalled) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: err.Error()}
+		return nil, nil, w.notSupportedReason
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeRange := prometheusv1.Range{Start: beginning, End: end, Step: queryStep}
+	ret := monitorapi.Intervals{}
+
+	members, err := prometheusaccess.QueryRange(ctx, prometheusClient, membersQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	membersWithLeader, err := prometheusaccess.QueryRange(ctx, prometheusClient, membersWithLeaderQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret = append(ret, quorumIntervals(membersWithLeader, maxValue(members), queryStep)...)
+
+	memberHasLeader, err := prometheusaccess.QueryRange(ctx, prometheusClient, memberHasLeaderQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret = append(ret, perMemberIntervals(memberHasLeader, queryStep, func(value float64) bool { return value == 0 },
+		monitorapi.EtcdMemberWithoutLeaderReason, monitorapi.Error, func(peak float64) string {
+			return "etcd member did not see a leader"
+		})...)
+
+	fsync, err := prometheusaccess.QueryRange(ctx, prometheusClient, fsyncQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret = append(ret, perMemberIntervals(fsync, queryStep, func(value float64) bool { return value > slowFsyncSeconds },
+		monitorapi.EtcdSlowFsyncReason, monitorapi.Warning, func(peak float64) string {
+			return fmt.Sprintf("99th percentile WAL fsync peaked at %.3fs, more than %vs", peak, slowFsyncSeconds)
+		})...)
+
+	compaction, err := prometheusaccess.QueryRange(ctx, prometheusClient, compactionQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret = append(ret, perMemberIntervals(compaction, queryStep, func(value float64) bool { return value > slowCompactionMilliseconds },
+		monitorapi.EtcdSlowCompactionReason, monitorapi.Warning, func(peak float64) string {
+			return fmt.Sprintf("99th percentile compaction peaked at %.0fms, more than %vms", peak, slowCompactionMilliseconds)
+		})...)
+
+	return ret, nil, nil
+}
+
+func (*etcdHealth) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return electionIntervals(startingIntervals), nil
+}
+
+func (w *etcdHealth) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return []*junitapi.JUnitTestCase{
+		evaluateElections(w.platform, finalIntervals),
+		evaluateQuorum(finalIntervals),
+	}, nil
+}
+
+func (*etcdHealth) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*etcdHealth) Cleanup(ctx context.Context) error {
+	return nil
+}
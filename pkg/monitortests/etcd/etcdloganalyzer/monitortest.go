@@ -37,6 +37,12 @@ func NewEtcdLogAnalyzer() monitortestframework.MonitorTest {
 	}
 }
 
+var _ monitortestframework.OfflineMonitorTest = &etcdLogAnalyzer{}
+
+func (*etcdLogAnalyzer) SupportsOffline() bool {
+	return true
+}
+
 func (w *etcdLogAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	logToIntervalConverter := newEtcdRecorder(recorder)
 	w.adminRESTConfig = adminRESTConfig
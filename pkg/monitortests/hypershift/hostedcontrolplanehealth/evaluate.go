@@ -0,0 +1,55 @@
+package hostedcontrolplanehealth
+
+import (
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// controlPlaneCheck is one test, failing for any interval of its reason.  Replicas missing during rollouts and pods
+// not ready are only recorded, to explain disruption the guest cluster sees.
+type controlPlaneCheck struct {
+	testName    string
+	reason      monitorapi.IntervalReason
+	explanation string
+}
+
+var controlPlaneChecks = []controlPlaneCheck{
+	{
+		testName:    "[sig-hypershift] hosted control plane deployments should stay available",
+		reason:      monitorapi.ControlPlaneDeploymentUnavailableReason,
+		explanation: "The guest cluster loses the control plane component while its deployment is unavailable, which monitoring inside the guest cluster cannot see.",
+	},
+	{
+		testName:    "[sig-hypershift] HostedCluster should stay available",
+		reason:      monitorapi.HostedClusterUnavailableReason,
+		explanation: "HyperShift reports the hosted control plane as unavailable.",
+	},
+}
+
+var controlPlaneTemplate = junitfailure.MustParseTemplate("hosted-control-plane-health",
+	`{{len .Intervals}} times {{.Fields.reason}} on the management cluster.  {{.Fields.explanation}}
+{{.IntervalList}}`)
+
+// evaluateControlPlane returns a test for every check.
+func evaluateControlPlane(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, check := range controlPlaneChecks {
+		failures := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+			return interval.Source == monitorapi.SourceHostedControlPlane && interval.Message.Reason == check.reason
+		})
+		if len(failures) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: check.testName})
+			continue
+		}
+		junit := junitfailure.NewFailure(controlPlaneTemplate, string(check.reason)).
+			Field("reason", string(check.reason)).
+			Field("explanation", check.explanation).
+			Intervals(failures...).
+			TestCase(check.testName)
+		// a warning until we know how hosted control planes behave during management cluster upgrades.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
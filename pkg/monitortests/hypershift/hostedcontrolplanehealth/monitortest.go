@@ -0,0 +1,130 @@
+package hostedcontrolplanehealth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type hostedControlPlaneHealth struct {
+	notSupportedReason error
+	tracker            *controlPlaneTracker
+	clock              clock.PassiveClock
+}
+
+// NewHostedControlPlaneHealth watches the HostedCluster and the control plane namespace on the management cluster of
+// a hosted control plane, and records the control plane disruption the guest cluster cannot see.
+func NewHostedControlPlaneHealth() monitortestframework.MonitorTest {
+	return &hostedControlPlaneHealth{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &hostedControlPlaneHealth{}
+
+func (w *hostedControlPlaneHealth) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *hostedControlPlaneHealth) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, check := range controlPlaneChecks {
+		testNames = append(testNames, check.testName)
+	}
+	return monitortestframework.MonitorTestDescription{
+		Platforms: []string{"HyperShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *hostedControlPlaneHealth) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	infra, err := configClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if infra.Status.ControlPlaneTopology != configv1.ExternalTopologyMode {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the control plane is not hosted"}
+		return w.notSupportedReason
+	}
+
+	kubeconfig, controlPlaneNamespace, err := exutil.GetHypershiftManagementClusterConfigAndNamespace()
+	if err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: fmt.Sprintf("the management cluster is not known: %v", err)}
+		return w.notSupportedReason
+	}
+	managementConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(managementConfig)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(managementConfig)
+	if err != nil {
+		return err
+	}
+
+	// without the annotation only the control plane namespace is watched.
+	namespace, err := kubeClient.CoreV1().Namespaces().Get(ctx, controlPlaneNamespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	hostedClusterNamespace, hostedClusterName, _ := strings.Cut(namespace.Annotations[hostedClusterAnnotation], "/")
+
+	w.tracker = newControlPlaneTracker(recorder, w.clock)
+	startControlPlaneMonitoring(ctx, w.tracker, dynamicClient, controlPlaneNamespace, hostedClusterNamespace, hostedClusterName)
+	return nil
+}
+
+func (w *hostedControlPlaneHealth) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// the intervals are in the recorder, only the problems still open have to be closed.
+	w.tracker.finish(end)
+	return nil, nil, nil
+}
+
+func (w *hostedControlPlaneHealth) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *hostedControlPlaneHealth) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateControlPlane(finalIntervals), nil
+}
+
+func (w *hostedControlPlaneHealth) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *hostedControlPlaneHealth) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,99 @@
+package hostedcontrolplanehealth
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+var (
+	hostedClusterResource = schema.GroupVersionResource{Group: "hypershift.openshift.io", Version: "v1beta1", Resource: "hostedclusters"}
+	deploymentResource    = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	podResource           = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+)
+
+// hostedClusterAnnotation is set by HyperShift on the control plane namespace to the namespace/name of its
+// HostedCluster.
+const hostedClusterAnnotation = "hypershift.openshift.io/hosted-cluster"
+
+// problem is what is wrong with a control plane object, recorded as an interval for as long as it lasts.
+type problem struct {
+	reason  monitorapi.IntervalReason
+	level   monitorapi.IntervalLevel
+	message string
+}
+
+// problemFunc returns what is wrong with an object, or nil when it is healthy.
+type problemFunc func(obj *unstructured.Unstructured) *problem
+
+var problemFuncs = map[schema.GroupVersionResource]problemFunc{
+	hostedClusterResource: hostedClusterProblem,
+	deploymentResource:    deploymentProblem,
+	podResource:           podProblem,
+}
+
+// condition returns the status and message of the condition of the type, or empty strings without one.
+func condition(obj *unstructured.Unstructured, conditionType string) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, curr := range conditions {
+		c, ok := curr.(map[string]interface{})
+		if !ok || c["type"] != conditionType {
+			continue
+		}
+		status, _ := c["status"].(string)
+		message, _ := c["message"].(string)
+		return status, message
+	}
+	return "", ""
+}
+
+// hostedClusterProblem reports a hosted cluster whose control plane is unavailable or degraded, as HyperShift sees it.
+func hostedClusterProblem(obj *unstructured.Unstructured) *problem {
+	if status, message := condition(obj, "Available"); status == "False" {
+		return &problem{reason: monitorapi.HostedClusterUnavailableReason, level: monitorapi.Error, message: message}
+	}
+	if status, message := condition(obj, "Degraded"); status == "True" {
+		return &problem{reason: monitorapi.HostedClusterDegradedReason, level: monitorapi.Warning, message: message}
+	}
+	return nil
+}
+
+// deploymentProblem reports a control plane deployment below its minimum availability, or with some replicas
+// unavailable.  During a rollout replicas are replaced one at a time, so a missing replica is not yet an outage.
+func deploymentProblem(obj *unstructured.Unstructured) *problem {
+	if status, message := condition(obj, "Available"); status == "False" {
+		return &problem{reason: monitorapi.ControlPlaneDeploymentUnavailableReason, level: monitorapi.Error, message: message}
+	}
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+	if available < replicas {
+		return &problem{
+			reason:  monitorapi.ControlPlaneReplicasUnavailableReason,
+			level:   monitorapi.Warning,
+			message: fmt.Sprintf("%d of %d replicas available", available, replicas),
+		}
+	}
+	return nil
+}
+
+// podProblem reports a running control plane pod that is not ready, which takes it out of its service.  Completed
+// pods, for instance of jobs, are not expected to be ready.
+func podProblem(obj *unstructured.Unstructured) *problem {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	if phase != "Running" {
+		return nil
+	}
+	if status, message := condition(obj, "Ready"); status != "True" {
+		if len(message) == 0 {
+			message = "pod is not ready"
+		}
+		return &problem{reason: monitorapi.ControlPlanePodNotReadyReason, level: monitorapi.Warning, message: message}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package hostedcontrolplanehealth
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func object(spec, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec, "status": status}}
+}
+
+func conditions(conditions ...map[string]interface{}) []interface{} {
+	ret := []interface{}{}
+	for _, c := range conditions {
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+func TestProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		problem  problemFunc
+		obj      *unstructured.Unstructured
+		expected monitorapi.IntervalReason
+	}{
+		{
+			name:    "hosted cluster available",
+			problem: hostedClusterProblem,
+			obj: object(nil, map[string]interface{}{"conditions": conditions(
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": "Degraded", "status": "False"},
+			)}),
+		},
+		{
+			name:    "hosted cluster unavailable",
+			problem: hostedClusterProblem,
+			obj: object(nil, map[string]interface{}{"conditions": conditions(
+				map[string]interface{}{"type": "Available", "status": "False", "message": "kube-apiserver is not available"},
+			)}),
+			expected: monitorapi.HostedClusterUnavailableReason,
+		},
+		{
+			name:    "hosted cluster degraded",
+			problem: hostedClusterProblem,
+			obj: object(nil, map[string]interface{}{"conditions": conditions(
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": "Degraded", "status": "True", "message": "etcd is degraded"},
+			)}),
+			expected: monitorapi.HostedClusterDegradedReason,
+		},
+		{
+			name:    "deployment available",
+			problem: deploymentProblem,
+			obj:     object(map[string]interface{}{"replicas": int64(3)}, map[string]interface{}{"availableReplicas": int64(3)}),
+		},
+		{
+			name:     "deployment rolling out",
+			problem:  deploymentProblem,
+			obj:      object(map[string]interface{}{"replicas": int64(3)}, map[string]interface{}{"availableReplicas": int64(2)}),
+			expected: monitorapi.ControlPlaneReplicasUnavailableReason,
+		},
+		{
+			name:    "deployment unavailable",
+			problem: deploymentProblem,
+			obj: object(map[string]interface{}{"replicas": int64(3)}, map[string]interface{}{"conditions": conditions(
+				map[string]interface{}{"type": "Available", "status": "False", "message": "Deployment does not have minimum availability."},
+			)}),
+			expected: monitorapi.ControlPlaneDeploymentUnavailableReason,
+		},
+		{
+			name:    "pod ready",
+			problem: podProblem,
+			obj: object(nil, map[string]interface{}{"phase": "Running", "conditions": conditions(
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			)}),
+		},
+		{
+			name:    "pod not ready",
+			problem: podProblem,
+			obj: object(nil, map[string]interface{}{"phase": "Running", "conditions": conditions(
+				map[string]interface{}{"type": "Ready", "status": "False", "message": "containers with unready status: [kube-apiserver]"},
+			)}),
+			expected: monitorapi.ControlPlanePodNotReadyReason,
+		},
+		{
+			name:    "pod completed",
+			problem: podProblem,
+			obj:     object(nil, map[string]interface{}{"phase": "Succeeded"}),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.problem(test.obj)
+			switch {
+			case len(test.expected) == 0 && actual != nil:
+				t.Errorf("expected no problem, got %v", actual.reason)
+			case len(test.expected) > 0 && actual == nil:
+				t.Errorf("expected %v, got no problem", test.expected)
+			case len(test.expected) > 0 && actual.reason != test.expected:
+				t.Errorf("expected %v, got %v", test.expected, actual.reason)
+			}
+		})
+	}
+}
+
+func TestTrackerAndEvaluate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deployment := func(available int64, availableCondition string) *unstructured.Unstructured {
+		obj := object(map[string]interface{}{"replicas": int64(2)}, map[string]interface{}{
+			"availableReplicas": available,
+			"conditions":        conditions(map[string]interface{}{"type": "Available", "status": availableCondition}),
+		})
+		obj.SetKind("Deployment")
+		obj.SetNamespace("clusters-example")
+		obj.SetName("kube-apiserver")
+		return obj
+	}
+
+	recorder := monitor.NewRecorder()
+	tracker := newControlPlaneTracker(recorder, clock.RealClock{})
+	tracker.observe(deploymentResource, deployment(2, "True"), start)
+	tracker.observe(deploymentResource, deployment(1, "True"), start.Add(time.Minute))
+	tracker.observe(deploymentResource, deployment(0, "False"), start.Add(2*time.Minute))
+	tracker.observe(deploymentResource, deployment(2, "True"), start.Add(3*time.Minute))
+	tracker.finish(start.Add(time.Hour))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 2 {
+		t.Fatalf("expected a missing replica and an unavailable interval, got %v", intervals)
+	}
+	unavailable := intervals[1]
+	if unavailable.Message.Reason != monitorapi.ControlPlaneDeploymentUnavailableReason || !unavailable.To.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("expected the deployment to be unavailable until it recovered, got %v", unavailable)
+	}
+
+	for _, junit := range evaluateControlPlane(intervals) {
+		failed := junit.FailureOutput != nil
+		if expected := junit.Name == "[sig-hypershift] hosted control plane deployments should stay available"; failed != expected {
+			t.Errorf("expected %q to fail=%v, got %v", junit.Name, expected, junit.FailureOutput)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package hostedcontrolplanehealth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type objectKey struct {
+	resource        schema.GroupVersionResource
+	namespace, name string
+}
+
+type openProblem struct {
+	reason monitorapi.IntervalReason
+	id     int
+}
+
+// controlPlaneTracker records an interval for as long as a control plane object on the management cluster has a
+// problem.  A problem that changes, for instance a deployment going from missing a replica to unavailable, ends one
+// interval and starts the next.
+type controlPlaneTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock sync.Mutex
+	open map[objectKey]openProblem
+}
+
+func newControlPlaneTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *controlPlaneTracker {
+	return &controlPlaneTracker{
+		recorder: recorder,
+		clock:    clock,
+		open:     map[objectKey]openProblem{},
+	}
+}
+
+// startControlPlaneMonitoring watches the deployments and pods of the control plane namespace and, when it is known,
+// the HostedCluster.
+func startControlPlaneMonitoring(ctx context.Context, tracker *controlPlaneTracker, client dynamic.Interface, controlPlaneNamespace, hostedClusterNamespace, hostedClusterName string) {
+	controlPlaneInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, controlPlaneNamespace, nil)
+	for _, resource := range []schema.GroupVersionResource{deploymentResource, podResource} {
+		addEventHandler(controlPlaneInformers.ForResource(resource).Informer(), tracker, resource)
+	}
+	controlPlaneInformers.Start(ctx.Done())
+
+	if len(hostedClusterName) == 0 {
+		return
+	}
+	hostedClusterInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, hostedClusterNamespace, func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", hostedClusterName).String()
+	})
+	addEventHandler(hostedClusterInformers.ForResource(hostedClusterResource).Informer(), tracker, hostedClusterResource)
+	hostedClusterInformers.Start(ctx.Done())
+}
+
+func addEventHandler(informer cache.SharedIndexInformer, tracker *controlPlaneTracker, resource schema.GroupVersionResource) {
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				tracker.observe(resource, u, tracker.clock.Now())
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				tracker.observe(resource, u, tracker.clock.Now())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				tracker.end(objectKey{resource: resource, namespace: u.GetNamespace(), name: u.GetName()}, tracker.clock.Now())
+			}
+		},
+	})
+}
+
+func (t *controlPlaneTracker) observe(resource schema.GroupVersionResource, obj *unstructured.Unstructured, now time.Time) {
+	key := objectKey{resource: resource, namespace: obj.GetNamespace(), name: obj.GetName()}
+	current := problemFuncs[resource](obj)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if existing, ok := t.open[key]; ok {
+		if current != nil && current.reason == existing.reason {
+			return
+		}
+		t.recorder.EndInterval(existing.id, now)
+		delete(t.open, key)
+	}
+	if current == nil {
+		return
+	}
+	t.open[key] = openProblem{
+		reason: current.reason,
+		id: t.recorder.StartInterval(
+			monitorapi.NewInterval(monitorapi.SourceHostedControlPlane, current.level).
+				Locator(monitorapi.NewLocator().KindInNamespace(obj.GetKind(), obj.GetNamespace(), obj.GetName())).
+				Message(monitorapi.NewMessage().Reason(current.reason).
+					HumanMessagef("on the management cluster: %s", current.message)).
+				Display().
+				Build(now, time.Time{}),
+		),
+	}
+}
+
+func (t *controlPlaneTracker) end(key objectKey, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if existing, ok := t.open[key]; ok {
+		t.recorder.EndInterval(existing.id, now)
+		delete(t.open, key)
+	}
+}
+
+// finish ends the intervals of problems still open at the end of the run.
+func (t *controlPlaneTracker) finish(end time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for key, existing := range t.open {
+		t.recorder.EndInterval(existing.id, end)
+		delete(t.open, key)
+	}
+}
@@ -0,0 +1,199 @@
+package imageregistryhealth
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformnamespaces"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	registryOutagePullTestName = "[sig-imageregistry] image pulls should not fail because the internal registry is unavailable"
+	platformPullTestName       = "[sig-imageregistry] platform pods should not fail to pull images"
+	platformImportTestName     = "[sig-imageregistry] imagestream imports in platform namespaces should not fail"
+
+	// outageGrace is how long after the registry comes back a failed pull is still blamed on it.  The kubelet reports
+	// a pull failure once the pull times out, after the outage that caused it may have ended.
+	outageGrace = time.Minute
+
+	// causes of a failed image pull.
+	registryOutageCause   = "RegistryOutage"
+	internalRegistryCause = "InternalRegistry"
+	externalRegistryCause = "ExternalRegistry"
+)
+
+// internalRegistryHosts are the names pods pull images from the internal registry by.
+var internalRegistryHosts = []string{
+	"image-registry.openshift-image-registry.svc:5000/",
+	"image-registry.openshift-image-registry.svc.cluster.local:5000/",
+	"default-route-openshift-image-registry.",
+}
+
+func isInternalRegistryImage(image string) bool {
+	for _, host := range internalRegistryHosts {
+		if strings.HasPrefix(image, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// registryOutageIntervals merges the times the new and reused connection backends of the image-registry availability
+// poller were disrupted into the windows the internal registry was down.
+func registryOutageIntervals(intervals monitorapi.Intervals) monitorapi.Intervals {
+	down := monitorapi.Intervals{}
+	for _, interval := range intervals {
+		if interval.Source == monitorapi.SourceDisruption &&
+			interval.Message.Reason == monitorapi.DisruptionBeganEventReason &&
+			strings.HasPrefix(interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey], "image-registry-") {
+			down = append(down, interval)
+		}
+	}
+	sort.Slice(down, func(i, j int) bool { return down[i].From.Before(down[j].From) })
+
+	ret := monitorapi.Intervals{}
+	for _, interval := range down {
+		if len(ret) > 0 && !interval.From.After(ret[len(ret)-1].To) {
+			if interval.To.After(ret[len(ret)-1].To) {
+				ret[len(ret)-1].To = interval.To
+			}
+			continue
+		}
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceImageRegistry, monitorapi.Error).
+			Locator(monitorapi.NewLocator().ClusterOperator("image-registry")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.ImageRegistryUnavailableReason).
+				HumanMessage("internal image registry unavailable").
+				Constructed(monitorapi.ConstructionOwnerImageRegistry)).
+			Display().
+			Build(interval.From, interval.To))
+	}
+	return ret
+}
+
+// pulledImage returns the image of a kubelet event reporting a failed pull.
+func pulledImage(interval monitorapi.Interval) (string, bool) {
+	if interval.Source != monitorapi.SourceKubeEvent || interval.Message.Reason != "Failed" {
+		return "", false
+	}
+	rest, ok := strings.CutPrefix(interval.Message.HumanMessage, `Failed to pull image "`)
+	if !ok {
+		return "", false
+	}
+	image, _, ok := strings.Cut(rest, `"`)
+	return image, ok
+}
+
+// pullFailureIntervals turns every failed pull into an interval whose cause tells a registry outage apart from a
+// pull that would have failed anyway, like a missing tag or an unreachable external registry.
+func pullFailureIntervals(intervals, outages monitorapi.Intervals) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, interval := range intervals {
+		image, ok := pulledImage(interval)
+		if !ok {
+			continue
+		}
+		cause := externalRegistryCause
+		if isInternalRegistryImage(image) {
+			cause = internalRegistryCause
+			if duringOutage(interval, outages) {
+				cause = registryOutageCause
+			}
+		}
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceImageRegistry, monitorapi.Warning).
+			Locator(interval.Locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.ImagePullFailedReason).
+				Cause(cause).
+				WithAnnotation(monitorapi.AnnotationImage, image).
+				HumanMessage(interval.Message.HumanMessage).
+				Constructed(monitorapi.ConstructionOwnerImageRegistry)).
+			Display().
+			Build(interval.From, interval.To))
+	}
+	return ret
+}
+
+func duringOutage(interval monitorapi.Interval, outages monitorapi.Intervals) bool {
+	for _, outage := range outages {
+		if !interval.From.Before(outage.From) && !interval.From.After(outage.To.Add(outageGrace)) {
+			return true
+		}
+	}
+	return false
+}
+
+var registryOutagePullTemplate = junitfailure.MustParseTemplate("image-pull-registry-outage",
+	`{{len .Intervals}} image pulls from the internal registry failed while it was unavailable for {{.Fields.outage}}.  The registry must stay available for the pods that pull from it.
+{{.IntervalList}}`)
+
+var platformPullTemplate = junitfailure.MustParseTemplate("platform-image-pull-failed",
+	`{{len .Intervals}} image pulls of platform pods failed while the internal registry was available.  Platform images must be pullable throughout the run.
+{{.IntervalList}}`)
+
+var platformImportTemplate = junitfailure.MustParseTemplate("platform-imagestream-import-failed",
+	`{{len .Intervals}} imagestream tags in platform namespaces failed to import.
+{{.IntervalList}}`)
+
+// evaluateRegistryHealth fails pulls that the registry being down explains separately from platform pulls and imports
+// failing for other reasons.  Test namespaces pull images that do not exist on purpose, so their failures that the
+// registry does not explain only show up as intervals.
+func evaluateRegistryHealth(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	outages := monitorapi.Intervals{}
+	outagePulls := monitorapi.Intervals{}
+	platformPulls := monitorapi.Intervals{}
+	platformImports := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		namespace := interval.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		switch {
+		case interval.Source == monitorapi.SourceImageRegistry && interval.Message.Reason == monitorapi.ImageRegistryUnavailableReason:
+			outages = append(outages, interval)
+		case interval.Source == monitorapi.SourceImageRegistry && interval.Message.Reason == monitorapi.ImagePullFailedReason:
+			if interval.Message.Annotations[monitorapi.AnnotationCause] == registryOutageCause {
+				outagePulls = append(outagePulls, interval)
+			} else if platformnamespaces.IsPlatformNamespace(namespace) {
+				platformPulls = append(platformPulls, interval)
+			}
+		case interval.Source == monitorapi.SourceImageStreamImport && interval.Message.Reason == monitorapi.ImageStreamImportFailedReason:
+			if platformnamespaces.IsPlatformNamespace(namespace) {
+				platformImports = append(platformImports, interval)
+			}
+		}
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	if len(outagePulls) == 0 {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: registryOutagePullTestName})
+	} else {
+		junit := junitfailure.NewFailure(registryOutagePullTemplate, "ImagePullFailedDuringRegistryOutage").
+			Field("outage", outages.Duration(time.Second).String()).
+			Intervals(outagePulls...).
+			TestCase(registryOutagePullTestName)
+		// a warning until we know how often registry outages cost pulls today.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+
+	if len(platformPulls) == 0 {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: platformPullTestName})
+	} else {
+		junit := junitfailure.NewFailure(platformPullTemplate, "PlatformImagePullFailed").
+			Intervals(platformPulls...).
+			TestCase(platformPullTestName)
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+
+	if len(platformImports) == 0 {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: platformImportTestName})
+	} else {
+		junit := junitfailure.NewFailure(platformImportTemplate, "PlatformImageStreamImportFailed").
+			Intervals(platformImports...).
+			TestCase(platformImportTestName)
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
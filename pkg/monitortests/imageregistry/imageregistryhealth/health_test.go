@@ -0,0 +1,138 @@
+package imageregistryhealth
+
+import (
+	"testing"
+	"time"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func disrupted(backend string, from, duration time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().LocateDisruptionCheck(backend, "openshift-tests", monitorapi.NewConnectionType)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")).
+		Build(start.Add(from), start.Add(from+duration))
+}
+
+func pullFailed(namespace, image string, at time.Duration) monitorapi.Interval {
+	event := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: "pod"},
+		Message:        `Failed to pull image "` + image + `": rpc error: code = Unknown`,
+	}
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().KubeEvent(event)).
+		Message(monitorapi.NewMessage().Reason("Failed").HumanMessage(event.Message)).
+		Build(start.Add(at), start.Add(at))
+}
+
+func TestRegistryHealth(t *testing.T) {
+	const internalImage = "image-registry.openshift-image-registry.svc:5000/e2e-test/app:latest"
+	startingIntervals := monitorapi.Intervals{
+		disrupted("image-registry-new-connections", 10*time.Minute, time.Minute),
+		disrupted("image-registry-reused-connections", 10*time.Minute+30*time.Second, time.Minute),
+		// another backend.
+		disrupted("ingress-to-console-new-connections", time.Minute, time.Hour),
+
+		// during the outage, and shortly after it.
+		pullFailed("e2e-test", internalImage, 10*time.Minute+10*time.Second),
+		pullFailed("openshift-monitoring", internalImage, 12*time.Minute),
+		// the registry was up, the tag does not exist.
+		pullFailed("e2e-test", internalImage, 30*time.Minute),
+		pullFailed("openshift-monitoring", "quay.io/openshift/missing:latest", 30*time.Minute),
+		pullFailed("e2e-test", "quay.io/openshift/missing:latest", 30*time.Minute),
+	}
+
+	outages := registryOutageIntervals(startingIntervals)
+	if len(outages) != 1 {
+		t.Fatalf("expected the disruption of both backends to merge into one outage, got %v", outages)
+	}
+	if from, to := outages[0].From, outages[0].To; !from.Equal(start.Add(10*time.Minute)) || !to.Equal(start.Add(11*time.Minute+30*time.Second)) {
+		t.Errorf("expected the outage to span both backends, got %v to %v", from, to)
+	}
+
+	pulls := pullFailureIntervals(startingIntervals, outages)
+	causes := map[string]int{}
+	for _, pull := range pulls {
+		causes[pull.Message.Annotations[monitorapi.AnnotationCause]]++
+	}
+	if causes[registryOutageCause] != 2 || causes[internalRegistryCause] != 1 || causes[externalRegistryCause] != 2 {
+		t.Errorf("unexpected causes %v", causes)
+	}
+
+	finalIntervals := append(append(startingIntervals, outages...), pulls...)
+	junits := evaluateRegistryHealth(finalIntervals)
+	if len(junits) != 3 {
+		t.Fatalf("expected three tests, got %d", len(junits))
+	}
+	for _, junit := range junits {
+		switch junit.Name {
+		case registryOutagePullTestName, platformPullTestName:
+			if junit.FailureOutput == nil {
+				t.Errorf("expected %q to fail", junit.Name)
+				continue
+			}
+			if junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected %q to be a warning, got %q", junit.Name, junit.Details.Severity)
+			}
+			// the outage pulls of both namespaces, and only the platform pull the registry does not explain.
+			expected := map[string]int{registryOutagePullTestName: 2, platformPullTestName: 1}[junit.Name]
+			if got := len(junit.Details.IntervalIDs); got != expected {
+				t.Errorf("expected %d intervals for %q, got %d", expected, junit.Name, got)
+			}
+		case platformImportTestName:
+			if junit.FailureOutput != nil {
+				t.Errorf("expected %q to pass, got %v", junit.Name, junit.FailureOutput.Output)
+			}
+		}
+	}
+}
+
+func TestImportTracker(t *testing.T) {
+	recorder := monitor.NewRecorder()
+	tracker := newImportTracker(recorder, clock.RealClock{})
+
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift", Name: "cli"},
+		Status: imagev1.ImageStreamStatus{
+			Tags: []imagev1.NamedTagEventList{
+				{Tag: "latest", Conditions: []imagev1.TagEventCondition{
+					{Type: imagev1.ImportSuccess, Status: corev1.ConditionFalse, Reason: "InternalError", Message: "registry unreachable"},
+				}},
+				{Tag: "stable"},
+			},
+		},
+	}
+	tracker.observe(imageStream, start)
+	// still failing.
+	tracker.observe(imageStream, start.Add(time.Minute))
+
+	imported := imageStream.DeepCopy()
+	imported.Status.Tags[0].Conditions = nil
+	tracker.observe(imported, start.Add(2*time.Minute))
+
+	tracker.observe(imageStream, start.Add(3*time.Minute))
+	tracker.finish(start.Add(4 * time.Minute))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 2 {
+		t.Fatalf("expected two import failures, got %v", intervals)
+	}
+	if to := intervals[0].To; !to.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("expected the first failure to end when the tag imported, got %v", to)
+	}
+	if to := intervals[1].To; !to.Equal(start.Add(4 * time.Minute)) {
+		t.Errorf("expected the second failure to last until the end, got %v", to)
+	}
+	if tag := intervals[0].Locator.Keys[monitorapi.LocatorTagKey]; tag != "latest" {
+		t.Errorf("expected the failing tag, got %q", tag)
+	}
+}
@@ -0,0 +1,140 @@
+package imageregistryhealth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imageclient "github.com/openshift/client-go/image/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type imageStreamTag struct {
+	namespace, name, tag string
+}
+
+// importTracker records an interval for as long as a tag of an imagestream fails to import.
+type importTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock sync.Mutex
+	// failing maps a tag that fails to import to the recorder ID of its open interval.
+	failing map[imageStreamTag]int
+}
+
+func newImportTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *importTracker {
+	return &importTracker{
+		recorder: recorder,
+		clock:    clock,
+		failing:  map[imageStreamTag]int{},
+	}
+}
+
+func startImageStreamMonitoring(ctx context.Context, tracker *importTracker, client imageclient.Interface) {
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.ImageV1().ImageStreams("").List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.ImageV1().ImageStreams("").Watch(ctx, options)
+			},
+		},
+		&imagev1.ImageStream{},
+		time.Hour,
+		nil,
+	)
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if imageStream, ok := obj.(*imagev1.ImageStream); ok {
+				tracker.observe(imageStream, tracker.clock.Now())
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if imageStream, ok := obj.(*imagev1.ImageStream); ok {
+				tracker.observe(imageStream, tracker.clock.Now())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if imageStream, ok := obj.(*imagev1.ImageStream); ok {
+				tracker.deleted(imageStream.Namespace, imageStream.Name, tracker.clock.Now())
+			}
+		},
+	})
+
+	go informer.Run(ctx.Done())
+}
+
+// failedImport returns the condition explaining why the last import of a tag failed, nil if it did not.
+func failedImport(tag imagev1.NamedTagEventList) *imagev1.TagEventCondition {
+	for i := range tag.Conditions {
+		condition := &tag.Conditions[i]
+		if condition.Type == imagev1.ImportSuccess && condition.Status == corev1.ConditionFalse {
+			return condition
+		}
+	}
+	return nil
+}
+
+func (t *importTracker) observe(imageStream *imagev1.ImageStream, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stillFailing := map[imageStreamTag]bool{}
+	for _, tag := range imageStream.Status.Tags {
+		key := imageStreamTag{namespace: imageStream.Namespace, name: imageStream.Name, tag: tag.Tag}
+		condition := failedImport(tag)
+		if condition == nil {
+			continue
+		}
+		stillFailing[key] = true
+		if _, ok := t.failing[key]; ok {
+			continue
+		}
+		t.failing[key] = t.recorder.StartInterval(
+			monitorapi.NewInterval(monitorapi.SourceImageStreamImport, monitorapi.Warning).
+				Locator(monitorapi.NewLocator().ImageStreamTag(key.namespace, key.name, key.tag)).
+				Message(monitorapi.NewMessage().Reason(monitorapi.ImageStreamImportFailedReason).
+					Cause(condition.Reason).
+					HumanMessagef("import failed: %s", condition.Message)).
+				Display().
+				Build(now, time.Time{}),
+		)
+	}
+
+	for key, id := range t.failing {
+		if key.namespace != imageStream.Namespace || key.name != imageStream.Name || stillFailing[key] {
+			continue
+		}
+		t.recorder.EndInterval(id, now)
+		delete(t.failing, key)
+	}
+}
+
+func (t *importTracker) deleted(namespace, name string, now time.Time) {
+	t.observe(&imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}, now)
+}
+
+// finish ends the intervals of tags still failing to import at the end of the run.
+func (t *importTracker) finish(end time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for key, id := range t.failing {
+		t.recorder.EndInterval(id, end)
+		delete(t.failing, key)
+	}
+}
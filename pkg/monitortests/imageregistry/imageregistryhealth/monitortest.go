@@ -0,0 +1,110 @@
+package imageregistryhealth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	imageclient "github.com/openshift/client-go/image/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type imageRegistryHealth struct {
+	notSupportedReason error
+	importTracker      *importTracker
+	clock              clock.PassiveClock
+}
+
+// NewImageRegistryHealth watches imagestream imports and attributes failed image pulls to internal registry outages,
+// as the registry availability poller reports them, or to the images themselves.
+func NewImageRegistryHealth() monitortestframework.MonitorTest {
+	return &imageRegistryHealth{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &imageRegistryHealth{}
+
+func (w *imageRegistryHealth) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *imageRegistryHealth) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{registryOutagePullTestName, platformPullTestName, platformImportTestName},
+	}
+}
+
+func (w *imageRegistryHealth) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: "platform MicroShift not supported",
+		}
+		return w.notSupportedReason
+	}
+
+	imageClient, err := imageclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.importTracker = newImportTracker(recorder, w.clock)
+	startImageStreamMonitoring(ctx, w.importTracker, imageClient)
+	return nil
+}
+
+func (w *imageRegistryHealth) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// the import intervals are in the recorder, only the imports still failing have to be closed.
+	w.importTracker.finish(end)
+	return nil, nil, nil
+}
+
+func (w *imageRegistryHealth) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	outages := registryOutageIntervals(startingIntervals)
+	ret := monitorapi.Intervals{}
+	ret = append(ret, outages...)
+	ret = append(ret, pullFailureIntervals(startingIntervals, outages)...)
+	return ret, nil
+}
+
+func (w *imageRegistryHealth) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateRegistryHealth(finalIntervals), nil
+}
+
+func (w *imageRegistryHealth) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *imageRegistryHealth) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,95 @@
+package apirequestlatency
+
+import (
+	"time"
+)
+
+// latencyBudget is the 99th percentile latency a class of requests is held to.  The defaults follow the upstream API
+// call latency SLO, with exceptions for resources whose requests wait on something outside the apiserver.
+type latencyBudget struct {
+	// name completes the test name, "p99 latency of ... should be within budget".
+	name string
+	// resources limits the budget to requests to these resources, named resource.group.  Empty matches any resource.
+	resources []string
+	// verbs limits the budget to these verbs.  Empty matches any verb.
+	verbs []string
+	// scopes limits the budget to requests of these scopes.  Empty matches any scope.
+	scopes []string
+	budget time.Duration
+}
+
+// latencyBudgets are matched in order, the first matching budget applies.  Requests no budget matches, for instance
+// deletecollection, are not evaluated.
+var latencyBudgets = []latencyBudget{
+	{
+		// imports reach out to the image registries the image streams point to.
+		name:      "image stream imports",
+		resources: []string{"imagestreamimports.image.openshift.io"},
+		budget:    30 * time.Second,
+	},
+	{
+		// reviews are answered by the authorizers and authenticators, which may call webhooks.
+		name: "access and token reviews",
+		resources: []string{
+			"subjectaccessreviews.authorization.k8s.io",
+			"localsubjectaccessreviews.authorization.k8s.io",
+			"selfsubjectaccessreviews.authorization.k8s.io",
+			"tokenreviews.authentication.k8s.io",
+		},
+		budget: 5 * time.Second,
+	},
+	{
+		name:   "single object reads",
+		verbs:  []string{"GET"},
+		scopes: []string{"resource"},
+		budget: time.Second,
+	},
+	{
+		name:   "namespace scoped lists",
+		verbs:  []string{"GET", "LIST"},
+		scopes: []string{"namespace"},
+		budget: 5 * time.Second,
+	},
+	{
+		name:   "cluster scoped lists",
+		verbs:  []string{"GET", "LIST"},
+		scopes: []string{"cluster"},
+		budget: 30 * time.Second,
+	},
+	{
+		name:   "mutating requests",
+		verbs:  []string{"POST", "PUT", "PATCH", "APPLY", "DELETE"},
+		budget: time.Second,
+	},
+}
+
+func matchesOrEmpty(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, curr := range values {
+		if curr == value {
+			return true
+		}
+	}
+	return false
+}
+
+// budgetFor returns the budget of the requests of a verb to a resource in a scope, or nil if none applies.
+func budgetFor(resource, verb, scope string) *latencyBudget {
+	for i := range latencyBudgets {
+		budget := &latencyBudgets[i]
+		if matchesOrEmpty(budget.resources, resource) && matchesOrEmpty(budget.verbs, verb) && matchesOrEmpty(budget.scopes, scope) {
+			return budget
+		}
+	}
+	return nil
+}
+
+// resourceName names a resource of an API group the way budgets do, the core group has no suffix.
+func resourceName(group, resource string) string {
+	if len(group) == 0 {
+		return resource
+	}
+	return resource + "." + group
+}
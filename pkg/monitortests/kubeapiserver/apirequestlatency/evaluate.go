@@ -0,0 +1,132 @@
+package apirequestlatency
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const serverErrorsTestName = "[sig-api-machinery] kube-apiserver should not fail more than 1% of the requests to a resource with server errors"
+
+func latencyTestName(budget *latencyBudget) string {
+	return fmt.Sprintf("[sig-api-machinery] p99 latency of %s should be within budget", budget.name)
+}
+
+// peakDuration is the peak latency the slow interval recorded, or zero if it recorded none.
+func peakDuration(interval monitorapi.Interval) time.Duration {
+	duration, err := time.ParseDuration(interval.Message.Annotations[monitorapi.AnnotationDuration])
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+var overBudgetTemplate = junitfailure.MustParseTemplate("api-request-latency-over-budget",
+	`The 99th percentile latency of {{.Fields.requests}} was over the {{.Fields.budget}} budget {{len .Intervals}} times, peaking at {{.Fields.peak}}.
+{{.IntervalList}}`)
+
+// evaluateLatencyBudgets produces a test for every budget, failing when the latency of any requests it covers
+// exceeded it.  Latency that began while a load generator was running is expected and not held to the budgets.
+func evaluateLatencyBudgets(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	slowByBudget := map[*latencyBudget]monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceAPIRequestMetrics || interval.Message.Reason != monitorapi.APIRequestSlowReason {
+			continue
+		}
+		if monitorapi.IsDuringIntentionalLoad(interval) {
+			continue
+		}
+		keys := interval.Locator.Keys
+		if budget := budgetFor(keys[monitorapi.LocatorResourceKey], keys[monitorapi.LocatorVerbKey], keys[monitorapi.LocatorScopeKey]); budget != nil {
+			slowByBudget[budget] = append(slowByBudget[budget], interval)
+		}
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for i := range latencyBudgets {
+		budget := &latencyBudgets[i]
+		testName := latencyTestName(budget)
+		slow := slowByBudget[budget]
+
+		var peak time.Duration
+		for _, interval := range slow {
+			if duration := peakDuration(interval); duration > peak {
+				peak = duration
+			}
+		}
+		threshold := junitapi.JUnitThreshold{
+			Name:     "api-request-p99-seconds",
+			Limit:    budget.budget.Seconds(),
+			Observed: peak.Seconds(),
+			Unit:     "seconds",
+			Exceeded: len(slow) > 0,
+		}
+		if !threshold.Exceeded {
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:    testName,
+				Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+			})
+			continue
+		}
+
+		junit := junitfailure.NewFailure(overBudgetTemplate, "APIRequestLatencyOverBudget").
+			Threshold(threshold).
+			Field("requests", budget.name).
+			Field("budget", budget.budget.String()).
+			Field("peak", peak.String()).
+			Intervals(slow...).
+			TestCase(testName)
+		// a warning until we know the budgets hold across platforms and upgrades.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
+
+var serverErrorsTemplate = junitfailure.MustParseTemplate("api-request-server-errors",
+	`More than {{.Fields.threshold}} of the requests to a resource failed with server errors {{len .Intervals}} times.
+{{.IntervalList}}`)
+
+// evaluateServerErrors fails when the server errors of any resource were over the threshold, outside of intentional
+// load.
+func evaluateServerErrors(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	failing := monitorapi.Intervals{}
+	var peak float64
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceAPIRequestMetrics || interval.Message.Reason != monitorapi.APIRequestServerErrorsReason {
+			continue
+		}
+		if monitorapi.IsDuringIntentionalLoad(interval) {
+			continue
+		}
+		failing = append(failing, interval)
+		if ratio, err := strconv.ParseFloat(interval.Message.Annotations[monitorapi.AnnotationErrorRatio], 64); err == nil && ratio > peak {
+			peak = ratio
+		}
+	}
+
+	threshold := junitapi.JUnitThreshold{
+		Name:     "api-request-server-error-ratio",
+		Limit:    serverErrorRatioThreshold,
+		Observed: peak,
+		Exceeded: len(failing) > 0,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    serverErrorsTestName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+	junit := junitfailure.NewFailure(serverErrorsTemplate, "APIRequestServerErrors").
+		Threshold(threshold).
+		Field("threshold", fmt.Sprintf("%.0f%%", serverErrorRatioThreshold*100)).
+		Intervals(failing...).
+		TestCase(serverErrorsTestName)
+	// a warning until we know how often the apiserver fails requests during upgrades.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,86 @@
+package apirequestlatency
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+)
+
+const (
+	queryStep = 30 * time.Second
+
+	// requestDurationQuery leaves out long running requests, which last as long as the client wants them to.
+	requestDurationQuery = `histogram_quantile(0.99, sum by (le, group, resource, verb, scope) (rate(apiserver_request_duration_seconds_bucket{job="apiserver", verb!~"WATCH|CONNECT", subresource!~"log|exec|portforward|attach|proxy"}[5m])))`
+
+	// serverErrorRatioQuery only considers resources requested often enough for the ratio to mean something.
+	serverErrorRatioQuery = `sum by (group, resource, verb) (rate(apiserver_request_total{job="apiserver", verb!~"WATCH|CONNECT", code=~"5.."}[5m]))
+  / sum by (group, resource, verb) (rate(apiserver_request_total{job="apiserver", verb!~"WATCH|CONNECT"}[5m]))
+  and on (group, resource, verb) sum by (group, resource, verb) (rate(apiserver_request_total{job="apiserver", verb!~"WATCH|CONNECT"}[5m])) > 0.1`
+
+	// largestBucket is the largest finite bucket of apiserver_request_duration_seconds.  The quantile is infinite
+	// when the slowest requests are in the +Inf bucket, and those took at least this long.
+	largestBucket = 60 * time.Second
+
+	// serverErrorRatioThreshold is the share of the requests to a resource that may fail with a server error.
+	serverErrorRatioThreshold = 0.01
+)
+
+// slowRequestIntervals reports the windows in which the 99th percentile latency of the requests of a verb to a
+// resource was over its budget.
+func slowRequestIntervals(matrix prometheustypes.Matrix, step time.Duration) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, series := range matrix {
+		resource := resourceName(string(series.Metric["group"]), string(series.Metric["resource"]))
+		verb := string(series.Metric["verb"])
+		scope := string(series.Metric["scope"])
+		budget := budgetFor(resource, verb, scope)
+		if budget == nil {
+			continue
+		}
+		overBudget := func(value float64) bool { return value > budget.budget.Seconds() }
+		for _, window := range prometheusaccess.Windows(series.Values, step, overBudget) {
+			peak := time.Duration(window.Peak * float64(time.Second))
+			if math.IsInf(window.Peak, 1) {
+				peak = largestBucket
+			}
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceAPIRequestMetrics, monitorapi.Warning).
+					Locator(monitorapi.NewLocator().APIRequest(resource, verb, scope)).
+					Message(monitorapi.NewMessage().Reason(monitorapi.APIRequestSlowReason).
+						WithAnnotation(monitorapi.AnnotationDuration, fmt.Sprintf("%.3fs", peak.Seconds())).
+						HumanMessagef("99th percentile latency of %s %s requests peaked at %s, more than the %s budget", scope, verb, peak.Round(time.Millisecond), budget.budget)).
+					Display().
+					Build(window.From, window.To),
+			)
+		}
+	}
+	return ret
+}
+
+// serverErrorIntervals reports the windows in which too many of the requests of a verb to a resource failed with a
+// server error.
+func serverErrorIntervals(matrix prometheustypes.Matrix, step time.Duration) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, series := range matrix {
+		resource := resourceName(string(series.Metric["group"]), string(series.Metric["resource"]))
+		verb := string(series.Metric["verb"])
+		overThreshold := func(value float64) bool { return value > serverErrorRatioThreshold }
+		for _, window := range prometheusaccess.Windows(series.Values, step, overThreshold) {
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceAPIRequestMetrics, monitorapi.Error).
+					Locator(monitorapi.NewLocator().APIRequest(resource, verb, "")).
+					Message(monitorapi.NewMessage().Reason(monitorapi.APIRequestServerErrorsReason).
+						WithAnnotation(monitorapi.AnnotationErrorRatio, fmt.Sprintf("%.4f", window.Peak)).
+						HumanMessagef("%.1f%% of %s requests failed with server errors", window.Peak*100, verb)).
+					Display().
+					Build(window.From, window.To),
+			)
+		}
+	}
+	return ret
+}
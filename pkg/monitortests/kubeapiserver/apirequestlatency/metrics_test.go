@@ -0,0 +1,102 @@
+package apirequestlatency
+
+import (
+	"testing"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func series(labels prometheustypes.Metric, values ...float64) *prometheustypes.SampleStream {
+	ret := &prometheustypes.SampleStream{Metric: labels}
+	for i, value := range values {
+		ret.Values = append(ret.Values, prometheustypes.SamplePair{
+			Timestamp: prometheustypes.TimeFromUnixNano(start.Add(time.Duration(i) * queryStep).UnixNano()),
+			Value:     prometheustypes.SampleValue(value),
+		})
+	}
+	return ret
+}
+
+func TestBudgetFor(t *testing.T) {
+	tests := []struct {
+		resource, verb, scope string
+		expected              string
+	}{
+		{resource: "imagestreamimports.image.openshift.io", verb: "POST", scope: "namespace", expected: "image stream imports"},
+		{resource: "pods", verb: "GET", scope: "resource", expected: "single object reads"},
+		{resource: "pods", verb: "LIST", scope: "namespace", expected: "namespace scoped lists"},
+		{resource: "nodes", verb: "LIST", scope: "cluster", expected: "cluster scoped lists"},
+		{resource: "configmaps", verb: "PATCH", scope: "resource", expected: "mutating requests"},
+		{resource: "pods", verb: "DELETECOLLECTION", scope: "namespace"},
+	}
+	for _, test := range tests {
+		budget := budgetFor(test.resource, test.verb, test.scope)
+		switch {
+		case budget == nil && len(test.expected) > 0:
+			t.Errorf("expected %s %s %s to have the %q budget, got none", test.scope, test.verb, test.resource, test.expected)
+		case budget != nil && budget.name != test.expected:
+			t.Errorf("expected %s %s %s to have the %q budget, got %q", test.scope, test.verb, test.resource, test.expected, budget.name)
+		}
+	}
+}
+
+func TestEvaluateLatencyAndErrors(t *testing.T) {
+	durations := prometheustypes.Matrix{
+		// within the budget of single object reads.
+		series(prometheustypes.Metric{"group": "", "resource": "pods", "verb": "GET", "scope": "resource"}, 0.1, 0.5, 0.2),
+		// over the budget of cluster scoped lists for two samples.
+		series(prometheustypes.Metric{"group": "", "resource": "secrets", "verb": "LIST", "scope": "cluster"}, 10, 45, 50, 20),
+		// not evaluated.
+		series(prometheustypes.Metric{"group": "", "resource": "pods", "verb": "DELETECOLLECTION", "scope": "namespace"}, 120),
+	}
+	errorRatios := prometheustypes.Matrix{
+		series(prometheustypes.Metric{"group": "apps", "resource": "deployments", "verb": "PUT"}, 0, 0.05, 0),
+	}
+
+	intervals := append(slowRequestIntervals(durations, queryStep), serverErrorIntervals(errorRatios, queryStep)...)
+	if len(intervals) != 2 {
+		t.Fatalf("expected one slow and one server error interval, got %v", intervals)
+	}
+	slow := intervals[0]
+	if slow.Locator.Keys[monitorapi.LocatorResourceKey] != "secrets" || !slow.From.Equal(start.Add(queryStep)) || !slow.To.Equal(start.Add(3*queryStep)) {
+		t.Errorf("expected the slow secrets list for two samples, got %v", slow)
+	}
+	if resource := intervals[1].Locator.Keys[monitorapi.LocatorResourceKey]; resource != "deployments.apps" {
+		t.Errorf("expected the resource to be named with its group, got %q", resource)
+	}
+
+	junits := evaluateLatencyBudgets(intervals)
+	if len(junits) != len(latencyBudgets) {
+		t.Fatalf("expected a test per budget, got %v", junits)
+	}
+	for _, junit := range junits {
+		switch junit.Name {
+		case "[sig-api-machinery] p99 latency of cluster scoped lists should be within budget":
+			if junit.FailureOutput == nil {
+				t.Fatalf("expected the slow list to fail")
+			}
+			if junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning, got %q", junit.Details.Severity)
+			}
+			if threshold := junit.Details.Thresholds[0]; threshold.Observed != 50 || threshold.Limit != 30 {
+				t.Errorf("expected the peak to be compared to the budget, got %+v", threshold)
+			}
+		default:
+			if junit.FailureOutput != nil {
+				t.Errorf("expected %q to pass, got %v", junit.Name, junit.FailureOutput.Output)
+			}
+		}
+	}
+
+	if junit := evaluateServerErrors(intervals); junit.FailureOutput == nil {
+		t.Errorf("expected the server errors to fail")
+	} else if threshold := junit.Details.Thresholds[0]; threshold.Observed != 0.05 {
+		t.Errorf("expected the peak error ratio, got %+v", threshold)
+	}
+}
@@ -0,0 +1,92 @@
+package apirequestlatency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type apiRequestLatency struct {
+	adminRESTConfig    *rest.Config
+	notSupportedReason error
+}
+
+// NewAPIRequestLatency samples the request latency and server errors of the kube-apiserver over the run, and holds
+// the 99th percentile latency of each resource to its budget.
+func NewAPIRequestLatency() monitortestframework.MonitorTest {
+	return &apiRequestLatency{}
+}
+
+func (w *apiRequestLatency) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{serverErrorsTestName}
+	for i := range latencyBudgets {
+		testNames = append(testNames, latencyTestName(&latencyBudgets[i]))
+	}
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *apiRequestLatency) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *apiRequestLatency) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, w.adminRESTConfig)
+	if errors.Is(err, prometheusaccess.ErrMonitoringNotInstalled) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: err.Error()}
+		return nil, nil, w.notSupportedReason
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeRange := prometheusv1.Range{Start: beginning, End: end, Step: queryStep}
+	durations, err := prometheusaccess.QueryRange(ctx, prometheusClient, requestDurationQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	errorRatios, err := prometheusaccess.QueryRange(ctx, prometheusClient, serverErrorRatioQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ret := slowRequestIntervals(durations, queryStep)
+	ret = append(ret, serverErrorIntervals(errorRatios, queryStep)...)
+	return ret, nil, nil
+}
+
+func (w *apiRequestLatency) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *apiRequestLatency) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	ret := evaluateLatencyBudgets(finalIntervals)
+	ret = append(ret, evaluateServerErrors(finalIntervals))
+	return ret, nil
+}
+
+func (w *apiRequestLatency) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *apiRequestLatency) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,387 @@
+package apiserveravailabilityslo
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace         *corev1.Namespace
+	pollerRoleBinding *rbacv1.RoleBinding
+	pollerDeployment  *appsv1.Deployment
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	pollerRoleBinding = resourceread.ReadRoleBindingV1OrDie(yamlOrDie("poller-rolebinding.yaml"))
+	pollerDeployment = resourceread.ReadDeploymentV1OrDie(yamlOrDie("poller-deployment.yaml"))
+}
+
+const (
+	stopConfigMapName  = "stop-collecting"
+	pollerLogsTestName = "[sig-api-machinery] can collect in-cluster apiserver availability poller pod logs"
+)
+
+// inClusterURLs are reached over the service network.  The pollers have no credentials, so the kube-apiserver is
+// polled on readyz, which anonymous clients may read.
+var inClusterURLs = map[string]string{
+	kubeAPITarget.name: "https://kubernetes.default.svc/readyz",
+	oauthTarget.name:   "https://oauth-openshift.openshift-authentication.svc/healthz",
+	consoleTarget.name: "https://console.openshift-console.svc/health",
+}
+
+type apiserverAvailabilitySLO struct {
+	payloadImagePullSpec string
+	notSupportedReason   error
+
+	targets          []target
+	externalSamplers []*backenddisruption.BackendSampler
+
+	kubeClient    kubernetes.Interface
+	namespaceName string
+
+	beginning, end time.Time
+}
+
+// NewAvailabilitySLOInvariant polls the kube-apiserver, oauth, and the console both from the test process and from a
+// deployment inside the cluster, and holds each to an availability SLO over the run.
+func NewAvailabilitySLOInvariant(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &apiserverAvailabilitySLO{
+		payloadImagePullSpec: info.UpgradeTargetPayloadImagePullSpec,
+	}
+}
+
+func (w *apiserverAvailabilitySLO) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{pollerLogsTestName}
+	for _, p := range []poller{externalPoller, inClusterPoller} {
+		for _, t := range []target{kubeAPITarget, oauthTarget, consoleTarget} {
+			for _, connectionType := range connectionTypes {
+				testNames = append(testNames, sloTestName(p, t, connectionType))
+			}
+		}
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *apiserverAvailabilitySLO) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: "platform MicroShift not supported",
+		}
+		return w.notSupportedReason
+	}
+
+	w.targets, err = availableTargets(ctx, adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := w.startExternalPollers(ctx, adminRESTConfig, recorder); err != nil {
+		return err
+	}
+	return w.startInClusterPollers(ctx, adminRESTConfig)
+}
+
+// availableTargets drops oauth and the console on clusters that disable them.
+func availableTargets(ctx context.Context, adminRESTConfig *rest.Config) ([]target, error) {
+	routeClient, err := routeclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	ret := []target{kubeAPITarget}
+	for _, route := range []struct {
+		target          target
+		namespace, name string
+	}{
+		{target: oauthTarget, namespace: "openshift-authentication", name: "oauth-openshift"},
+		{target: consoleTarget, namespace: "openshift-console", name: "console"},
+	} {
+		_, err := routeClient.RouteV1().Routes(route.namespace).Get(ctx, route.name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return nil, err
+		default:
+			ret = append(ret, route.target)
+		}
+	}
+	return ret, nil
+}
+
+func (w *apiserverAvailabilitySLO) startExternalPollers(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	for _, t := range w.targets {
+		for _, connectionType := range connectionTypes {
+			var sampler *backenddisruption.BackendSampler
+			switch t.name {
+			case kubeAPITarget.name:
+				kubeAPISampler, err := backenddisruption.NewAPIServerBackend(adminRESTConfig, backendPrefix(externalPoller, t), "/api/v1/namespaces/default", connectionType)
+				if err != nil {
+					return err
+				}
+				sampler = kubeAPISampler
+			case oauthTarget.name:
+				sampler = backenddisruption.NewRouteBackend(adminRESTConfig, "openshift-authentication", "oauth-openshift", backendPrefix(externalPoller, t), "/healthz", connectionType).
+					WithExpectedBody("ok")
+			case consoleTarget.name:
+				sampler = backenddisruption.NewRouteBackend(adminRESTConfig, "openshift-console", "console", backendPrefix(externalPoller, t), "/healthz", connectionType).
+					WithExpectedBodyRegex(`(Red Hat OpenShift|OKD)`)
+			}
+			sampler = sampler.WithUserAgent(fmt.Sprintf("openshift-external-backend-sampler-%s-%s", connectionType, backendPrefix(externalPoller, t)))
+			if err := sampler.StartEndpointMonitoring(ctx, recorder, nil); err != nil {
+				return err
+			}
+			w.externalSamplers = append(w.externalSamplers, sampler)
+		}
+	}
+	return nil
+}
+
+func (w *apiserverAvailabilitySLO) startInClusterPollers(ctx context.Context, adminRESTConfig *rest.Config) error {
+	openshiftTestsImagePullSpec, err := disruptionpodnetwork.GetOpenshiftTestsImagePullSpec(ctx, adminRESTConfig, w.payloadImagePullSpec, nil)
+	if err != nil {
+		// the external pollers are still useful without the in-cluster ones.
+		klog.Errorf("unable to determine openshift-tests image, not starting in-cluster pollers: %v", err)
+		return nil
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	if _, err := w.kubeClient.RbacV1().RoleBindings(w.namespaceName).Create(ctx, pollerRoleBinding, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	// our pods tolerate masters, so create one for each node.
+	nodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	numNodes := int32(len(nodes.Items))
+
+	deployment := pollerDeployment.DeepCopy()
+	deployment.Spec.Replicas = &numNodes
+	container := &deployment.Spec.Template.Spec.Containers[0]
+	container.Image = openshiftTestsImagePullSpec
+	for _, t := range w.targets {
+		container.Command = append(container.Command, fmt.Sprintf("--endpoint=%s=%s", backendPrefix(inClusterPoller, t), inClusterURLs[t.name]))
+	}
+	for i, env := range container.Env {
+		if env.Name == "DEPLOYMENT_ID" {
+			container.Env[i].Value = uuid.New().String()
+		}
+	}
+	if _, err := w.kubeClient.AppsV1().Deployments(w.namespaceName).Create(ctx, deployment, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (w *apiserverAvailabilitySLO) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	w.beginning, w.end = beginning, end
+
+	// the external samplers record directly, they only have to drain.  Stop in parallel.
+	wg := sync.WaitGroup{}
+	for i := range w.externalSamplers {
+		wg.Add(1)
+		go func(sampler *backenddisruption.BackendSampler) {
+			defer wg.Done()
+			sampler.Stop()
+		}(w.externalSamplers[i])
+	}
+	wg.Wait()
+
+	if len(w.namespaceName) == 0 {
+		return nil, nil, nil
+	}
+
+	// create the stop collecting configmap and wait for 30s for the pollers to have stopped.  the 30s is just a guess
+	if _, err := w.kubeClient.CoreV1().ConfigMaps(w.namespaceName).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: stopConfigMapName},
+	}, metav1.CreateOptions{}); err != nil {
+		return nil, nil, err
+	}
+	select {
+	case <-time.After(30 * time.Second):
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	return w.collectInClusterIntervals(ctx)
+}
+
+// collectInClusterIntervals scrapes the intervals the pollers logged.  A poller that logged nothing is reported,
+// since its node would otherwise silently not count towards the SLO.
+func (w *apiserverAvailabilitySLO) collectInClusterIntervals(ctx context.Context) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	pollerPods, err := w.kubeClient.CoreV1().Pods(w.namespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: "apiserver.openshift.io/disruption-actor=poller",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retIntervals := monitorapi.Intervals{}
+	errs := []error{}
+	logs := &strings.Builder{}
+	podsWithoutIntervals := []string{}
+	for _, pollerPod := range pollerPods.Items {
+		fmt.Fprintf(logs, "\n\nLogs for -n %v pod/%v\n", pollerPod.Namespace, pollerPod.Name)
+		logStream, err := w.kubeClient.CoreV1().Pods(w.namespaceName).GetLogs(pollerPod.Name, &corev1.PodLogOptions{}).Stream(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		foundInterval := false
+		scanner := bufio.NewScanner(logStream)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			logs.Write(line)
+			logs.WriteString("\n")
+			if len(line) == 0 {
+				continue
+			}
+			// not all lines are json, ignore errors.
+			if currInterval, err := monitorserialization.IntervalFromJSON(line); err == nil {
+				retIntervals = append(retIntervals, *currInterval)
+				foundInterval = true
+			}
+		}
+		logStream.Close()
+		if !foundInterval {
+			podsWithoutIntervals = append(podsWithoutIntervals, pollerPod.Name)
+		}
+	}
+
+	logJunit := &junitapi.JUnitTestCase{
+		Name:      pollerLogsTestName,
+		SystemOut: logs.String(),
+	}
+	failures := []string{}
+	if len(pollerPods.Items) == 0 {
+		failures = append(failures, "no in-cluster poller pods found")
+	}
+	if len(podsWithoutIntervals) > 0 {
+		failures = append(failures, fmt.Sprintf("%d pods lacked poller output: [%v]", len(podsWithoutIntervals), strings.Join(podsWithoutIntervals, ", ")))
+	}
+	if len(failures) > 0 {
+		logJunit.FailureOutput = &junitapi.FailureOutput{
+			Output: strings.Join(failures, "\n"),
+		}
+	}
+
+	return retIntervals, []*junitapi.JUnitTestCase{logJunit}, utilerrors.NewAggregate(errs)
+}
+
+func (w *apiserverAvailabilitySLO) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *apiserverAvailabilitySLO) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+
+	pollers := []poller{externalPoller}
+	if len(w.namespaceName) > 0 {
+		pollers = append(pollers, inClusterPoller)
+	}
+	return evaluateSLO(pollers, w.targets, finalIntervals, w.beginning, w.end), nil
+}
+
+func (w *apiserverAvailabilitySLO) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *apiserverAvailabilitySLO) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (w *apiserverAvailabilitySLO) Cleanup(ctx context.Context) error {
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		if err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted); err != nil {
+			return err
+		}
+
+		klog.Infof("Deleting namespace: %s took %.2f seconds", w.namespaceName, time.Now().Sub(startTime).Seconds())
+	}
+	return w.notSupportedReason
+}
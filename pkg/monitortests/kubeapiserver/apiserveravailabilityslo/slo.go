@@ -0,0 +1,139 @@
+package apiserveravailabilityslo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// poller is where requests come from.  External pollers see the load balancers and routes clients outside the
+// cluster use, in-cluster pollers see the service network.
+type poller string
+
+const (
+	externalPoller  poller = "external"
+	inClusterPoller poller = "in-cluster"
+)
+
+// target is an endpoint both pollers check, and the fraction of the run it must be available for.
+type target struct {
+	name            string
+	owner           string
+	availabilitySLO float64
+}
+
+var (
+	kubeAPITarget = target{name: "kube-api", owner: "sig-api-machinery", availabilitySLO: 0.999}
+	oauthTarget   = target{name: "oauth", owner: "sig-auth", availabilitySLO: 0.995}
+	// the console is a single deployment behind ingress, so it is held to the same SLO as oauth.
+	consoleTarget = target{name: "console", owner: "sig-network-edge", availabilitySLO: 0.995}
+)
+
+var connectionTypes = []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType}
+
+// backendPrefix is the prefix of the disruption backend a poller reports a target under.  The samplers append the
+// connection type.
+func backendPrefix(p poller, t target) string {
+	return fmt.Sprintf("%s-%s", p, t.name)
+}
+
+func backendName(p poller, t target, connectionType monitorapi.BackendConnectionType) string {
+	return fmt.Sprintf("%s-%v-connections", backendPrefix(p, t), connectionType)
+}
+
+func sloTestName(p poller, t target, connectionType monitorapi.BackendConnectionType) string {
+	return fmt.Sprintf("[%s] disruption/%s poller/%s connection/%s should meet the availability SLO", t.owner, t.name, p, connectionType)
+}
+
+var sloTemplate = junitfailure.MustParseTemplate("apiserver-availability-slo",
+	`{{.Fields.target}} was available for {{printf "%.3f" .Threshold.Observed}}% of the run from {{.Fields.instance}} over {{.Fields.connection}} connections, below the {{printf "%.3f" .Threshold.Limit}}% SLO.  It was unreachable for {{.Fields.disruption}} of {{.Fields.duration}}.
+{{.IntervalList}}`)
+
+// evaluateSLO returns one test per poller, target, and connection type.  In-cluster pollers run on every node, each
+// reporting under its own instance, and the least available instance decides, so that a single node that cannot
+// reach the target is not averaged away.  A target without any samples is skipped, the poller logs explain why.
+func evaluateSLO(pollers []poller, targets []target, finalIntervals monitorapi.Intervals, beginning, end time.Time) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	runDuration := end.Sub(beginning)
+	for _, p := range pollers {
+		for _, t := range targets {
+			for _, connectionType := range connectionTypes {
+				ret = append(ret, evaluateBackendSLO(p, t, connectionType, finalIntervals, runDuration))
+			}
+		}
+	}
+	return ret
+}
+
+func evaluateBackendSLO(p poller, t target, connectionType monitorapi.BackendConnectionType, finalIntervals monitorapi.Intervals, runDuration time.Duration) *junitapi.JUnitTestCase {
+	testName := sloTestName(p, t, connectionType)
+	backend := backendName(p, t, connectionType)
+	byInstance := map[string]monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceDisruption || interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey] != backend {
+			continue
+		}
+		instance := interval.Locator.Keys[monitorapi.LocatorDisruptionKey]
+		byInstance[instance] = append(byInstance[instance], interval)
+	}
+	if len(byInstance) == 0 || runDuration <= 0 {
+		return &junitapi.JUnitTestCase{
+			Name:        testName,
+			SkipMessage: &junitapi.SkipMessage{Message: fmt.Sprintf("no samples were recorded for %s", backend)},
+		}
+	}
+
+	instances := make([]string, 0, len(byInstance))
+	for instance := range byInstance {
+		instances = append(instances, instance)
+	}
+	sort.Strings(instances)
+
+	worstInstance := ""
+	var worstDisruption time.Duration
+	var worstIntervals monitorapi.Intervals
+	for _, instance := range instances {
+		// disruption while a load generator was running is expected and does not count against the SLO.
+		disrupted := byInstance[instance].Filter(monitorapi.And(monitorapi.IsErrorEvent, monitorapi.Not(monitorapi.IsDuringIntentionalLoad)))
+		disruption := disrupted.Duration(1 * time.Second)
+		if len(worstInstance) == 0 || disruption > worstDisruption {
+			worstInstance, worstDisruption, worstIntervals = instance, disruption, disrupted
+		}
+	}
+	if worstDisruption > runDuration {
+		worstDisruption = runDuration
+	}
+
+	observed := 100 * (1 - worstDisruption.Seconds()/runDuration.Seconds())
+	threshold := junitapi.JUnitThreshold{
+		Name:     "availability",
+		Limit:    100 * t.availabilitySLO,
+		Observed: observed,
+		Unit:     "percent",
+		Exceeded: observed < 100*t.availabilitySLO,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:      testName,
+			SystemOut: fmt.Sprintf("least available instance %s was unreachable for %s of %s", worstInstance, worstDisruption.Round(time.Second), runDuration.Round(time.Second)),
+			Details:   &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+
+	junit := junitfailure.NewFailure(sloTemplate, "AvailabilitySLOMissed").
+		Threshold(threshold).
+		Field("target", t.name).
+		Field("instance", worstInstance).
+		Field("connection", string(connectionType)).
+		Field("disruption", worstDisruption.Round(time.Second).String()).
+		Field("duration", runDuration.Round(time.Second).String()).
+		Intervals(worstIntervals...).
+		TestCase(testName)
+	// a warning until we know the SLOs hold on every platform and upgrade path.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
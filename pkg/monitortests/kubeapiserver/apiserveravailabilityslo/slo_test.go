@@ -0,0 +1,93 @@
+package apiserveravailabilityslo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func sample(p poller, t target, connectionType monitorapi.BackendConnectionType, instance string, level monitorapi.IntervalLevel, from, to time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, level).
+		Locator(monitorapi.NewLocator().LocateDisruptionCheck(backendName(p, t, connectionType), instance, connectionType)).
+		Message(monitorapi.NewMessage().HumanMessage("sample")).
+		Build(start.Add(from), start.Add(to))
+}
+
+func junitNamed(junits []*junitapi.JUnitTestCase, name string) *junitapi.JUnitTestCase {
+	for _, junit := range junits {
+		if junit.Name == name {
+			return junit
+		}
+	}
+	return nil
+}
+
+func TestEvaluateSLO(t *testing.T) {
+	end := start.Add(1000 * time.Second)
+	intervals := monitorapi.Intervals{
+		// external new connections: 10s of 1000s is 99.0%, below the 99.9% kube-api SLO.
+		sample(externalPoller, kubeAPITarget, monitorapi.NewConnectionType, "openshift-tests", monitorapi.Info, 0, 100*time.Second),
+		sample(externalPoller, kubeAPITarget, monitorapi.NewConnectionType, "openshift-tests", monitorapi.Error, 100*time.Second, 110*time.Second),
+		// external reused connections: 1s of 1000s is 99.9%, which meets the SLO.
+		sample(externalPoller, kubeAPITarget, monitorapi.ReusedConnectionType, "openshift-tests", monitorapi.Error, 100*time.Second, 101*time.Second),
+		// in-cluster new connections: one node is fine, the other is not, the worst node decides.
+		sample(inClusterPoller, kubeAPITarget, monitorapi.NewConnectionType, "node-a", monitorapi.Info, 0, 1000*time.Second),
+		sample(inClusterPoller, kubeAPITarget, monitorapi.NewConnectionType, "node-b", monitorapi.Error, 200*time.Second, 230*time.Second),
+		// in-cluster reused connections recorded nothing.
+	}
+
+	junits := evaluateSLO([]poller{externalPoller, inClusterPoller}, []target{kubeAPITarget}, intervals, start, end)
+	if len(junits) != 4 {
+		t.Fatalf("expected one test per poller and connection type, got %d", len(junits))
+	}
+
+	externalNew := junitNamed(junits, sloTestName(externalPoller, kubeAPITarget, monitorapi.NewConnectionType))
+	if externalNew == nil || externalNew.FailureOutput == nil {
+		t.Fatalf("expected external new connections to miss the SLO, got %#v", externalNew)
+	}
+	if externalNew.Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", externalNew.Details.Severity)
+	}
+	if observed := externalNew.Details.Thresholds[0].Observed; observed < 98.99 || observed > 99.01 {
+		t.Errorf("expected 99%% availability, got %v", observed)
+	}
+
+	externalReused := junitNamed(junits, sloTestName(externalPoller, kubeAPITarget, monitorapi.ReusedConnectionType))
+	if externalReused == nil || externalReused.FailureOutput != nil || externalReused.SkipMessage != nil {
+		t.Errorf("expected external reused connections to meet the SLO, got %#v", externalReused)
+	}
+
+	inClusterNew := junitNamed(junits, sloTestName(inClusterPoller, kubeAPITarget, monitorapi.NewConnectionType))
+	if inClusterNew == nil || inClusterNew.FailureOutput == nil {
+		t.Fatalf("expected in-cluster new connections to miss the SLO, got %#v", inClusterNew)
+	}
+	if !strings.Contains(inClusterNew.FailureOutput.Output, "node-b") {
+		t.Errorf("expected the failure to name the least available node, got %q", inClusterNew.FailureOutput.Output)
+	}
+
+	inClusterReused := junitNamed(junits, sloTestName(inClusterPoller, kubeAPITarget, monitorapi.ReusedConnectionType))
+	if inClusterReused == nil || inClusterReused.SkipMessage == nil {
+		t.Errorf("expected in-cluster reused connections without samples to be skipped, got %#v", inClusterReused)
+	}
+}
+
+func TestEvaluateSLODuringIntentionalLoad(t *testing.T) {
+	end := start.Add(1000 * time.Second)
+	duringLoad := sample(externalPoller, kubeAPITarget, monitorapi.NewConnectionType, "openshift-tests", monitorapi.Error, 100*time.Second, 200*time.Second)
+	duringLoad.Message.Annotations = map[monitorapi.AnnotationKey]string{monitorapi.AnnotationDuringLoad: "e2e-load"}
+	intervals := monitorapi.Intervals{
+		sample(externalPoller, kubeAPITarget, monitorapi.NewConnectionType, "openshift-tests", monitorapi.Info, 0, 100*time.Second),
+		duringLoad,
+	}
+
+	junits := evaluateSLO([]poller{externalPoller}, []target{kubeAPITarget}, intervals, start, end)
+	externalNew := junitNamed(junits, sloTestName(externalPoller, kubeAPITarget, monitorapi.NewConnectionType))
+	if externalNew == nil || externalNew.FailureOutput != nil {
+		t.Errorf("expected disruption during intentional load not to count against the SLO, got %#v", externalNew)
+	}
+}
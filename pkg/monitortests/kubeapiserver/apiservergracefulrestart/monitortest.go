@@ -27,6 +27,12 @@ func NewGracefulShutdownAnalyzer() monitortestframework.MonitorTest {
 	return &apiserverGracefulShutdownAnalyzer{}
 }
 
+var _ monitortestframework.OfflineMonitorTest = &apiserverGracefulShutdownAnalyzer{}
+
+func (*apiserverGracefulShutdownAnalyzer) SupportsOffline() bool {
+	return true
+}
+
 func (w *apiserverGracefulShutdownAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	return nil
 }
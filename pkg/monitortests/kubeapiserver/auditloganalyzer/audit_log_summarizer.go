@@ -25,6 +25,9 @@ type AuditLogSummary struct {
 	perUserRequestCount       map[string]*PerUserRequestCount
 	perResourceRequestCount   map[schema.GroupVersionResource]*PerResourceRequestCount
 	perHTTPStatusRequestCount map[int32]*PerHTTPStatusRequestCount
+	perStatusTimeline         map[int32]*ResponseTimeline
+	// perComponentAPIVersionUsage holds the use of deprecated and alpha API versions by platform components.
+	perComponentAPIVersionUsage map[string]*ComponentAPIVersionUsage
 }
 
 type RequestCounts struct {
@@ -106,7 +109,13 @@ func (s *AuditLogSummary) Add(auditEvent *auditv1.Event, auditEventInfo auditEve
 			s.perHTTPStatusRequestCount[httpStatus] = NewPerStatusRequestCount(httpStatus)
 		}
 		s.perHTTPStatusRequestCount[httpStatus].Add(auditEvent, auditEventInfo)
+
+		if timeline, ok := s.perStatusTimeline[httpStatus]; ok {
+			timeline.Add(auditEvent)
+		}
 	}
+
+	s.addAPIVersionUsage(auditEvent, auditEventInfo)
 }
 
 func (s *RequestCounts) Add(auditEvent *auditv1.Event) {
@@ -216,6 +225,18 @@ func (s *AuditLogSummary) AddSummary(rhs *AuditLogSummary) {
 		}
 		s.perHTTPStatusRequestCount[k].AddSummary(v)
 	}
+	for k, v := range rhs.perStatusTimeline {
+		if _, ok := s.perStatusTimeline[k]; !ok {
+			s.perStatusTimeline[k] = NewResponseTimeline(k)
+		}
+		s.perStatusTimeline[k].AddSummary(v)
+	}
+	for k, v := range rhs.perComponentAPIVersionUsage {
+		if _, ok := s.perComponentAPIVersionUsage[k]; !ok {
+			s.perComponentAPIVersionUsage[k] = NewComponentAPIVersionUsage(k)
+		}
+		s.perComponentAPIVersionUsage[k].AddSummary(v)
+	}
 }
 
 func (s *RequestCounts) AddSummary(rhs *RequestCounts) {
@@ -295,12 +316,19 @@ func (s *PerResourceRequestCount) AddSummary(rhs *PerResourceRequestCount) {
 }
 
 func NewAuditLogSummary() *AuditLogSummary {
+	perStatusTimeline := map[int32]*ResponseTimeline{}
+	for httpStatus := range stormThresholds {
+		perStatusTimeline[httpStatus] = NewResponseTimeline(httpStatus)
+	}
 	return &AuditLogSummary{
 		lineReadFailureCount:      0,
 		requestCounts:             *NewRequestCounts(),
 		perUserRequestCount:       map[string]*PerUserRequestCount{},
 		perResourceRequestCount:   map[schema.GroupVersionResource]*PerResourceRequestCount{},
 		perHTTPStatusRequestCount: map[int32]*PerHTTPStatusRequestCount{},
+		perStatusTimeline:         perStatusTimeline,
+
+		perComponentAPIVersionUsage: map[string]*ComponentAPIVersionUsage{},
 	}
 }
 func NewRequestCounts() *RequestCounts {
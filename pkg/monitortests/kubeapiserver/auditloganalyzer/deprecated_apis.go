@@ -0,0 +1,168 @@
+package auditloganalyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// deprecatedAnnotation and removedReleaseAnnotation are set by the apiserver on the audit events of requests to
+	// deprecated API versions.
+	deprecatedAnnotation     = "k8s.io/deprecated"
+	removedReleaseAnnotation = "k8s.io/removed-release"
+)
+
+// componentUsers are the platform components that do not run as a service account.
+var componentUsers = map[string]string{
+	"system:kube-controller-manager": "kube-controller-manager",
+	"system:kube-scheduler":          "kube-scheduler",
+}
+
+// componentForUser returns the platform component making requests as a user, the namespace of a service account or
+// the kubelet, or an empty string for anything else.
+func componentForUser(user string) string {
+	if component, ok := componentUsers[user]; ok {
+		return component
+	}
+	if strings.HasPrefix(user, "system:node:") {
+		return "kubelet"
+	}
+	if !strings.HasPrefix(user, "system:serviceaccount:openshift-") && !strings.HasPrefix(user, "system:serviceaccount:kube-") {
+		return ""
+	}
+	parts := strings.Split(user, ":")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[2]
+}
+
+// ComponentAPIVersionUsage counts the requests of a platform component to deprecated and alpha API versions.  Alpha
+// versions are only served while their feature gates are enabled, so requests to them are uses of alpha features.
+type ComponentAPIVersionUsage struct {
+	component string
+	// deprecated counts the requests to each deprecated resource.
+	deprecated map[schema.GroupVersionResource]int
+	// removedRelease is the release that removes a deprecated resource, when the apiserver knows it.
+	removedRelease map[schema.GroupVersionResource]string
+	alpha          map[schema.GroupVersionResource]int
+}
+
+func NewComponentAPIVersionUsage(component string) *ComponentAPIVersionUsage {
+	return &ComponentAPIVersionUsage{
+		component:      component,
+		deprecated:     map[schema.GroupVersionResource]int{},
+		removedRelease: map[schema.GroupVersionResource]string{},
+		alpha:          map[schema.GroupVersionResource]int{},
+	}
+}
+
+// addAPIVersionUsage records the component of every completed request, so that components using no deprecated API
+// still get a passing test, and counts its requests to deprecated and alpha versions.
+func (s *AuditLogSummary) addAPIVersionUsage(auditEvent *auditv1.Event, auditEventInfo auditEventInfo) {
+	if auditEvent.Stage != auditv1.StageResponseComplete {
+		return
+	}
+	component := componentForUser(auditEvent.User.Username)
+	if len(component) == 0 {
+		return
+	}
+	usage, ok := s.perComponentAPIVersionUsage[component]
+	if !ok {
+		usage = NewComponentAPIVersionUsage(component)
+		s.perComponentAPIVersionUsage[component] = usage
+	}
+
+	gvr := auditEventInfo.getGroupVersionResource(auditEvent)
+	if len(gvr.Resource) == 0 {
+		// discovery, which every client does for every version.
+		return
+	}
+	if auditEvent.Annotations[deprecatedAnnotation] == "true" {
+		usage.deprecated[gvr]++
+		if removedRelease := auditEvent.Annotations[removedReleaseAnnotation]; len(removedRelease) > 0 {
+			usage.removedRelease[gvr] = removedRelease
+		}
+	}
+	if strings.Contains(gvr.Version, "alpha") {
+		usage.alpha[gvr]++
+	}
+}
+
+func (s *ComponentAPIVersionUsage) AddSummary(rhs *ComponentAPIVersionUsage) {
+	if s.component != rhs.component {
+		panic(fmt.Sprintf("mismatching key: have %v, need %v", s.component, rhs.component))
+	}
+	for k, v := range rhs.deprecated {
+		s.deprecated[k] += v
+	}
+	for k, v := range rhs.removedRelease {
+		s.removedRelease[k] = v
+	}
+	for k, v := range rhs.alpha {
+		s.alpha[k] += v
+	}
+}
+
+func describeGroupVersionResource(gvr schema.GroupVersionResource) string {
+	if len(gvr.Group) == 0 {
+		return fmt.Sprintf("%s.%s", gvr.Resource, gvr.Version)
+	}
+	return fmt.Sprintf("%s.%s.%s", gvr.Resource, gvr.Version, gvr.Group)
+}
+
+// describe lists the deprecated and alpha resources the component requested, one per line.
+func (s *ComponentAPIVersionUsage) describe() string {
+	lines := []string{}
+	for gvr, count := range s.deprecated {
+		line := fmt.Sprintf("deprecated %s: %d requests", describeGroupVersionResource(gvr), count)
+		if removedRelease, ok := s.removedRelease[gvr]; ok {
+			line += fmt.Sprintf(", removed in %s", removedRelease)
+		}
+		lines = append(lines, line)
+	}
+	for gvr, count := range s.alpha {
+		lines = append(lines, fmt.Sprintf("alpha %s: %d requests", describeGroupVersionResource(gvr), count))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+var apiVersionUsageTemplate = junitfailure.MustParseTemplate("deprecated-api-usage",
+	`{{.Fields.component}} made requests to API versions that are deprecated or only served with an alpha feature gate enabled:
+{{.Fields.usage}}`)
+
+// evaluateAPIVersionUsage produces a test for every platform component that made requests, failing for the ones that
+// requested deprecated or alpha API versions.
+func evaluateAPIVersionUsage(auditLogSummary *AuditLogSummary) []*junitapi.JUnitTestCase {
+	components := []string{}
+	for component := range auditLogSummary.perComponentAPIVersionUsage {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, component := range components {
+		usage := auditLogSummary.perComponentAPIVersionUsage[component]
+		testName := fmt.Sprintf("[sig-api-machinery] %s should not use deprecated or alpha API versions", component)
+		if len(usage.deprecated) == 0 && len(usage.alpha) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+		junit := junitfailure.NewFailure(apiVersionUsageTemplate, "DeprecatedAPIVersionUsed").
+			Field("component", component).
+			Field("usage", usage.describe()).
+			TestCase(testName)
+		// a warning so teams see their deprecation debt without blocking on it.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
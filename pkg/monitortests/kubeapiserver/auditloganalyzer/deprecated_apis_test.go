@@ -0,0 +1,66 @@
+package auditloganalyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestComponentForUser(t *testing.T) {
+	tests := map[string]string{
+		"system:serviceaccount:openshift-monitoring:prometheus-k8s":   "openshift-monitoring",
+		"system:serviceaccount:kube-system:generic-garbage-collector": "kube-system",
+		"system:node:master-0":                        "kubelet",
+		"system:kube-controller-manager":              "kube-controller-manager",
+		"system:serviceaccount:e2e-test-1234:default": "",
+		"system:admin":                                "",
+	}
+	for user, expected := range tests {
+		if actual := componentForUser(user); actual != expected {
+			t.Errorf("expected %q to be component %q, got %q", user, expected, actual)
+		}
+	}
+}
+
+func TestEvaluateAPIVersionUsage(t *testing.T) {
+	// summaries are built per node and per file, then combined.
+	first, second := NewAuditLogSummary(), NewAuditLogSummary()
+
+	deprecated := completedRequest(1, "system:serviceaccount:openshift-monitoring:prometheus-operator", "list", "/apis/policy/v1beta1/podsecuritypolicies", 200, 0)
+	deprecated.Annotations = map[string]string{deprecatedAnnotation: "true", removedReleaseAnnotation: "1.25"}
+	first.Add(deprecated, auditEventInfo{})
+	second.Add(completedRequest(2, "system:serviceaccount:openshift-monitoring:prometheus-operator", "get", "/apis/resource.k8s.io/v1alpha2/namespaces/ns/resourceclaims/claim", 200, 0), auditEventInfo{})
+	second.Add(completedRequest(3, "system:serviceaccount:openshift-etcd-operator:etcd-operator", "get", "/api/v1/namespaces/openshift-etcd/pods/etcd-0", 200, 0), auditEventInfo{})
+	// the discovery of alpha versions is not a use of them.
+	second.Add(completedRequest(4, "system:serviceaccount:openshift-etcd-operator:etcd-operator", "get", "/apis/resource.k8s.io/v1alpha2", 200, 0), auditEventInfo{})
+	// not a platform component.
+	second.Add(completedRequest(5, "system:admin", "get", "/apis/resource.k8s.io/v1alpha2/resourceclasses/gpu", 200, 0), auditEventInfo{})
+
+	summary := NewAuditLogSummary()
+	summary.AddSummary(first)
+	summary.AddSummary(second)
+
+	junits := evaluateAPIVersionUsage(summary)
+	if len(junits) != 2 {
+		t.Fatalf("expected a test for each platform component, got %v", junits)
+	}
+	etcd, monitoring := junits[0], junits[1]
+	if etcd.FailureOutput != nil {
+		t.Errorf("expected the etcd operator to pass, got %v", etcd.FailureOutput.Output)
+	}
+	if monitoring.FailureOutput == nil {
+		t.Fatalf("expected the monitoring component to fail")
+	}
+	if monitoring.Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", monitoring.Details.Severity)
+	}
+	for _, expected := range []string{
+		"deprecated podsecuritypolicies.v1beta1.policy: 1 requests, removed in 1.25",
+		"alpha resourceclaims.v1alpha2.resource.k8s.io: 1 requests",
+	} {
+		if !strings.Contains(monitoring.FailureOutput.Output, expected) {
+			t.Errorf("expected the failure to mention %q, got %v", expected, monitoring.FailureOutput.Output)
+		}
+	}
+}
@@ -17,6 +17,8 @@ type auditLogAnalyzer struct {
 
 	// auditLogSummary is written during CollectData
 	auditLogSummary *AuditLogSummary
+	// beginning and end bound the audit log summary, to turn counts into rates.
+	beginning, end time.Time
 }
 
 func NewAuditLogAnalyzer() monitortestframework.MonitorTest {
@@ -36,6 +38,7 @@ func (w *auditLogAnalyzer) CollectData(ctx context.Context, storageDir string, b
 
 	auditLogSummary, auditEvents, err := intervalsFromAuditLogs(ctx, kubeClient, beginning, end)
 	w.auditLogSummary = auditLogSummary
+	w.beginning, w.end = beginning, end
 
 	return auditEvents, nil, err
 }
@@ -44,8 +47,11 @@ func (*auditLogAnalyzer) ConstructComputedIntervals(ctx context.Context, startin
 	return nil, nil
 }
 
-func (*auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
+func (w *auditLogAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.auditLogSummary == nil {
+		return nil, nil
+	}
+	return evaluateAPIVersionUsage(w.auditLogSummary), nil
 }
 
 func (w *auditLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
@@ -53,6 +59,10 @@ func (w *auditLogAnalyzer) WriteContentToStorage(ctx context.Context, storageDir
 		if currErr := WriteAuditLogSummary(storageDir, timeSuffix, w.auditLogSummary); currErr != nil {
 			return currErr
 		}
+		topActors := NewTopActorSummary(w.auditLogSummary, w.beginning, w.end, topActorLimit)
+		if currErr := writeTopActorSummary(storageDir, timeSuffix, topActors); currErr != nil {
+			return currErr
+		}
 	}
 	return nil
 }
@@ -69,6 +79,7 @@ func intervalsFromAuditLogs(ctx context.Context, kubeClient kubernetes.Interface
 		// TODO report the error AND the best possible summary we have
 		return auditLogSummary, nil, err
 	}
+	ret = append(ret, auditLogSummary.StormIntervals()...)
 
 	return auditLogSummary, ret, nil
 }
@@ -0,0 +1,132 @@
+package auditloganalyzer
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// stormThresholds is how many requests per minute must complete with the status for that minute to be part of a storm.
+// A handful of 429s and 403s is normal: clients back off and controllers probe for permissions they may not have.
+var stormThresholds = map[int32]int{
+	http.StatusTooManyRequests: 100,
+	http.StatusForbidden:       200,
+}
+
+var stormReasons = map[int32]monitorapi.IntervalReason{
+	http.StatusTooManyRequests: monitorapi.AuditTooManyRequestsStormReason,
+	http.StatusForbidden:       monitorapi.AuditForbiddenSpikeReason,
+}
+
+// ResponseTimeline counts the requests that completed with one status per minute, and who made them.
+type ResponseTimeline struct {
+	httpStatus int32
+	perMinute  map[time.Time]*minuteCount
+}
+
+type minuteCount struct {
+	count   int
+	perUser map[string]int
+}
+
+func NewResponseTimeline(httpStatus int32) *ResponseTimeline {
+	return &ResponseTimeline{
+		httpStatus: httpStatus,
+		perMinute:  map[time.Time]*minuteCount{},
+	}
+}
+
+func (s *ResponseTimeline) Add(auditEvent *auditv1.Event) {
+	if auditEvent.Stage != auditv1.StageResponseComplete {
+		return
+	}
+	if auditEvent.ResponseStatus == nil || auditEvent.ResponseStatus.Code != s.httpStatus {
+		return
+	}
+
+	minute := auditEvent.RequestReceivedTimestamp.Time.UTC().Truncate(time.Minute)
+	if _, ok := s.perMinute[minute]; !ok {
+		s.perMinute[minute] = &minuteCount{perUser: map[string]int{}}
+	}
+	s.perMinute[minute].count++
+	s.perMinute[minute].perUser[auditEvent.User.Username]++
+}
+
+func (s *ResponseTimeline) AddSummary(rhs *ResponseTimeline) {
+	if s.httpStatus != rhs.httpStatus {
+		panic(fmt.Sprintf("mismatching key: have %v, need %v", s.httpStatus, rhs.httpStatus))
+	}
+	for minute, v := range rhs.perMinute {
+		if _, ok := s.perMinute[minute]; !ok {
+			s.perMinute[minute] = &minuteCount{perUser: map[string]int{}}
+		}
+		s.perMinute[minute].count += v.count
+		for user, count := range v.perUser {
+			s.perMinute[minute].perUser[user] += count
+		}
+	}
+}
+
+// stormIntervals merges consecutive minutes at or above the threshold of the status into one interval naming the
+// user that made the most of those requests.
+func (s *ResponseTimeline) stormIntervals(threshold int) monitorapi.Intervals {
+	minutes := []time.Time{}
+	for minute, v := range s.perMinute {
+		if v.count >= threshold {
+			minutes = append(minutes, minute)
+		}
+	}
+	sort.Slice(minutes, func(i, j int) bool {
+		return minutes[i].Before(minutes[j])
+	})
+
+	ret := monitorapi.Intervals{}
+	for i := 0; i < len(minutes); {
+		from, to := minutes[i], minutes[i].Add(time.Minute)
+		storm := &minuteCount{perUser: map[string]int{}}
+		for ; i < len(minutes) && !minutes[i].After(to); i++ {
+			to = minutes[i].Add(time.Minute)
+			storm.count += s.perMinute[minutes[i]].count
+			for user, count := range s.perMinute[minutes[i]].perUser {
+				storm.perUser[user] += count
+			}
+		}
+
+		topUser, topCount := "", 0
+		for user, count := range storm.perUser {
+			if count > topCount || count == topCount && user < topUser {
+				topUser, topCount = user, count
+			}
+		}
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceAuditLog, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().KubeAPIServerWithLB("")).
+			Message(monitorapi.NewMessage().Reason(stormReasons[s.httpStatus]).
+				HumanMessage(fmt.Sprintf("%d requests completed with %d %s, %d of them from %s",
+					storm.count, s.httpStatus, http.StatusText(int(s.httpStatus)), topCount, topUser))).
+			Display().
+			Build(from, to))
+	}
+	return ret
+}
+
+// StormIntervals returns the periods where too many requests were throttled or forbidden.
+func (s *AuditLogSummary) StormIntervals() monitorapi.Intervals {
+	statuses := []int32{}
+	for httpStatus := range s.perStatusTimeline {
+		statuses = append(statuses, httpStatus)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i] < statuses[j]
+	})
+
+	ret := monitorapi.Intervals{}
+	for _, httpStatus := range statuses {
+		ret = append(ret, s.perStatusTimeline[httpStatus].stormIntervals(stormThresholds[httpStatus])...)
+	}
+	sort.Sort(ret)
+	return ret
+}
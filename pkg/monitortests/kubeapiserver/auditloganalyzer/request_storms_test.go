@@ -0,0 +1,112 @@
+package auditloganalyzer
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func completedRequest(id int, user, verb, uri string, httpStatus int32, at time.Duration) *auditv1.Event {
+	return &auditv1.Event{
+		AuditID:                  types.UID(fmt.Sprintf("%d", id)),
+		Stage:                    auditv1.StageResponseComplete,
+		RequestURI:               uri,
+		Verb:                     verb,
+		User:                     authenticationv1.UserInfo{Username: user},
+		ResponseStatus:           &metav1.Status{Code: httpStatus},
+		RequestReceivedTimestamp: metav1.NewMicroTime(start.Add(at)),
+	}
+}
+
+func TestStormIntervals(t *testing.T) {
+	// summaries are built per node and per file, then combined.
+	first, second := NewAuditLogSummary(), NewAuditLogSummary()
+	id := 0
+	addRequests := func(summary *AuditLogSummary, count int, user string, httpStatus int32, minute int) {
+		for i := 0; i < count; i++ {
+			id++
+			summary.Add(completedRequest(id, user, "list", "/api/v1/pods", httpStatus, time.Duration(minute)*time.Minute+time.Duration(i)*time.Millisecond), auditEventInfo{})
+		}
+	}
+	// two consecutive minutes of throttling, the second split across nodes.
+	addRequests(first, 150, "system:serviceaccount:openshift-monitoring:prometheus", http.StatusTooManyRequests, 1)
+	addRequests(first, 60, "system:serviceaccount:openshift-monitoring:prometheus", http.StatusTooManyRequests, 2)
+	addRequests(second, 60, "system:admin", http.StatusTooManyRequests, 2)
+	// too few to be a storm.
+	addRequests(second, 50, "system:admin", http.StatusTooManyRequests, 5)
+	// a forbidden spike.
+	addRequests(second, 250, "system:serviceaccount:e2e:default", http.StatusForbidden, 10)
+	// plenty of successful requests.
+	addRequests(second, 1000, "system:admin", http.StatusOK, 3)
+
+	summary := NewAuditLogSummary()
+	summary.AddSummary(first)
+	summary.AddSummary(second)
+
+	storms := summary.StormIntervals()
+	if len(storms) != 2 {
+		t.Fatalf("expected two storms, got %d: %v", len(storms), storms)
+	}
+
+	throttled := storms[0]
+	if throttled.Message.Reason != monitorapi.AuditTooManyRequestsStormReason {
+		t.Errorf("expected a throttling storm first, got %v", throttled.Message.Reason)
+	}
+	if !throttled.From.Equal(start.Add(time.Minute)) || !throttled.To.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("expected the storm to span minutes 1 and 2, got %v to %v", throttled.From, throttled.To)
+	}
+	if !strings.Contains(throttled.Message.HumanMessage, "270 requests") || !strings.Contains(throttled.Message.HumanMessage, "prometheus") {
+		t.Errorf("unexpected message %q", throttled.Message.HumanMessage)
+	}
+
+	forbidden := storms[1]
+	if forbidden.Message.Reason != monitorapi.AuditForbiddenSpikeReason || !forbidden.From.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("unexpected forbidden spike %v", forbidden)
+	}
+}
+
+func TestTopActorSummary(t *testing.T) {
+	summary := NewAuditLogSummary()
+	id := 0
+	for i := 0; i < 600; i++ {
+		id++
+		summary.Add(completedRequest(id, "system:admin", "list", "/apis/apps/v1/namespaces/foo/deployments", http.StatusOK, time.Second), auditEventInfo{})
+	}
+	for i := 0; i < 60; i++ {
+		id++
+		summary.Add(completedRequest(id, "system:node:master-0", "get", "/api/v1/nodes/master-0", http.StatusForbidden, time.Second), auditEventInfo{})
+	}
+	for i := 0; i < 6; i++ {
+		id++
+		summary.Add(completedRequest(id, "system:admin", "get", "/api/v1/namespaces/foo/pods/bar", http.StatusOK, time.Second), auditEventInfo{})
+	}
+
+	topActors := NewTopActorSummary(summary, start, start.Add(time.Minute), 2)
+	if topActors.TotalRequestCount != 666 {
+		t.Errorf("expected 666 requests, got %d", topActors.TotalRequestCount)
+	}
+	if len(topActors.TopActors) != 2 {
+		t.Fatalf("expected the summary to be limited to 2 actors, got %d", len(topActors.TopActors))
+	}
+
+	top := topActors.TopActors[0]
+	if top.User != "system:admin" || top.Verb != "list" || top.Resource != "deployments.apps" {
+		t.Errorf("unexpected top actor %#v", top)
+	}
+	if top.RequestsPerSecond != 10 {
+		t.Errorf("expected 10 requests per second, got %v", top.RequestsPerSecond)
+	}
+	if second := topActors.TopActors[1]; second.User != "system:node:master-0" || second.ClientFailedRequestCount != 60 {
+		t.Errorf("unexpected second actor %#v", second)
+	}
+}
@@ -0,0 +1,104 @@
+package auditloganalyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// topActorLimit is how many user, verb, and resource combinations are kept in the top actor summary.
+const topActorLimit = 50
+
+// TopActor is one user making one kind of request to one resource.
+type TopActor struct {
+	User     string
+	Verb     string
+	Resource string
+
+	RequestCount      int
+	RequestsPerSecond float64
+
+	ClientFailedRequestCount int
+	ServerFailedRequestCount int
+}
+
+type TopActorSummary struct {
+	Beginning time.Time
+	End       time.Time
+
+	TotalRequestCount      int
+	TotalRequestsPerSecond float64
+
+	TopActors []TopActor
+}
+
+// NewTopActorSummary lists the user, verb, and resource combinations that made the most requests between beginning
+// and end, and the rate they made them at.
+func NewTopActorSummary(auditLogSummary *AuditLogSummary, beginning, end time.Time, limit int) TopActorSummary {
+	seconds := end.Sub(beginning).Seconds()
+	perSecond := func(count int) float64 {
+		if seconds <= 0 {
+			return 0
+		}
+		return float64(count) / seconds
+	}
+
+	actors := []TopActor{}
+	for _, uv := range auditLogSummary.perUserRequestCount {
+		for gvr, rv := range uv.perResourceRequestCount {
+			resource := gvr.Resource
+			if len(gvr.Group) > 0 {
+				resource = fmt.Sprintf("%s.%s", gvr.Resource, gvr.Group)
+			}
+			for verb, vv := range rv.perVerbRequestCount {
+				actors = append(actors, TopActor{
+					User:                     uv.user,
+					Verb:                     verb,
+					Resource:                 resource,
+					RequestCount:             vv.requestFinishedCount,
+					RequestsPerSecond:        perSecond(vv.requestFinishedCount),
+					ClientFailedRequestCount: vv.clientFailedRequestCount,
+					ServerFailedRequestCount: vv.serverFailedRequestCount,
+				})
+			}
+		}
+	}
+	sort.Slice(actors, func(i, j int) bool {
+		if actors[i].RequestCount != actors[j].RequestCount {
+			return actors[i].RequestCount > actors[j].RequestCount
+		}
+		if actors[i].User != actors[j].User {
+			return actors[i].User < actors[j].User
+		}
+		if actors[i].Resource != actors[j].Resource {
+			return actors[i].Resource < actors[j].Resource
+		}
+		return actors[i].Verb < actors[j].Verb
+	})
+	if len(actors) > limit {
+		actors = actors[:limit]
+	}
+
+	return TopActorSummary{
+		Beginning:              beginning,
+		End:                    end,
+		TotalRequestCount:      auditLogSummary.requestCounts.requestFinishedCount,
+		TotalRequestsPerSecond: perSecond(auditLogSummary.requestCounts.requestFinishedCount),
+		TopActors:              actors,
+	}
+}
+
+func writeTopActorSummary(artifactDir, timeSuffix string, topActors TopActorSummary) error {
+	summaryBytes, err := json.MarshalIndent(topActors, "", "    ")
+	if err != nil {
+		return err
+	}
+	summaryPath := filepath.Join(artifactDir, fmt.Sprintf("audit-log-top-actors_%s.json", timeSuffix))
+	if err := os.WriteFile(summaryPath, summaryBytes, 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", summaryPath, err)
+	}
+	return nil
+}
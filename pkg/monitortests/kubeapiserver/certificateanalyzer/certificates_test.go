@@ -0,0 +1,122 @@
+package certificateanalyzer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func tlsSecret(t *testing.T, namespace, name, issuer string, serial int64, notBefore time.Time, validity time.Duration) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		Issuer:       pkix.Name{CommonName: issuer},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(validity),
+	}
+	parent := &x509.Certificate{Subject: pkix.Name{CommonName: issuer}}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		},
+	}
+}
+
+func TestRotationTracker(t *testing.T) {
+	const day = 24 * time.Hour
+	recorder := monitor.NewRecorder()
+	tracker := newRotationTracker(recorder, clocktesting.NewFakePassiveClock(start))
+
+	tracker.observe(tlsSecret(t, "openshift-etcd", "serving-cert", "etcd-signer", 1, start.Add(-20*day), 30*day), start)
+	// past half of its validity.
+	tracker.observe(tlsSecret(t, "openshift-etcd", "serving-cert", "etcd-signer", 2, start, 30*day), start)
+
+	tracker.observe(tlsSecret(t, "openshift-ingress", "router-certs", "ingress-signer", 1, start.Add(-day), 30*day), start)
+	// a day into thirty, by the same signer.
+	tracker.observe(tlsSecret(t, "openshift-ingress", "router-certs", "ingress-signer", 2, start, 30*day), start)
+
+	tracker.observe(tlsSecret(t, "openshift-apiserver", "serving-cert", "old-signer", 1, start.Add(-day), 30*day), start)
+	tracker.observe(tlsSecret(t, "openshift-apiserver", "serving-cert", "new-signer", 2, start, 30*day), start)
+
+	// not a platform namespace.
+	tracker.observe(tlsSecret(t, "e2e-test", "serving-cert", "signer", 1, start.Add(-day), 30*day), start)
+	tracker.observe(tlsSecret(t, "e2e-test", "serving-cert", "signer", 2, start, 30*day), start)
+
+	causes := map[string]string{}
+	for _, interval := range recorder.Intervals(time.Time{}, time.Time{}) {
+		causes[interval.Locator.Keys[monitorapi.LocatorNamespaceKey]] = interval.Message.Annotations[monitorapi.AnnotationCause]
+	}
+	expected := map[string]string{
+		"openshift-etcd":      rotationDue,
+		"openshift-ingress":   rotationEarly,
+		"openshift-apiserver": rotationSignerChanged,
+	}
+	if len(causes) != len(expected) {
+		t.Fatalf("expected rotations %v, got %v", expected, causes)
+	}
+	for namespace, cause := range expected {
+		if causes[namespace] != cause {
+			t.Errorf("expected the rotation in %s to be %s, got %q", namespace, cause, causes[namespace])
+		}
+	}
+
+	junit := evaluateRotations(recorder.Intervals(time.Time{}, time.Time{}))
+	if junit.FailureOutput == nil {
+		t.Fatalf("expected the early rotation to fail")
+	}
+	if junit.Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", junit.Details.Severity)
+	}
+}
+
+func TestEvaluateExpiry(t *testing.T) {
+	const day = 24 * time.Hour
+	inventory := &Inventory{
+		Time: start,
+		Certificates: []Certificate{
+			{Namespace: "openshift-etcd", Secret: "expiring", NotBefore: start.Add(-360 * day), NotAfter: start.Add(5 * day), InUse: true},
+			{Namespace: "openshift-etcd", Secret: "unused", NotBefore: start.Add(-360 * day), NotAfter: start.Add(5 * day)},
+			// short-lived, it rotates more often than the horizon.
+			{Namespace: "openshift-kube-apiserver", Secret: "short-lived", NotBefore: start.Add(-day), NotAfter: start.Add(day), InUse: true},
+			{Namespace: "openshift-ingress", Secret: "fresh", NotBefore: start, NotAfter: start.Add(365 * day), InUse: true},
+		},
+	}
+
+	junit := evaluateExpiry(inventory, DefaultExpiryHorizon)
+	if junit.FailureOutput == nil {
+		t.Fatalf("expected the expiring certificate to fail")
+	}
+	if junit.Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", junit.Details.Severity)
+	}
+
+	if junit := evaluateExpiry(inventory, 3*day); junit.FailureOutput != nil {
+		t.Errorf("expected no failure within a shorter horizon, got %v", junit.FailureOutput.Output)
+	}
+}
@@ -0,0 +1,79 @@
+package certificateanalyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	expiryTestName   = "[sig-api-machinery] in-use platform certificates should not be close to expiring"
+	rotationTestName = "[sig-api-machinery] platform certificates should only rotate when they are due"
+
+	// DefaultExpiryHorizon is how long before they expire in-use certificates must have been rotated, unless the
+	// run configures another horizon.
+	DefaultExpiryHorizon = 7 * 24 * time.Hour
+)
+
+var expiryTemplate = junitfailure.MustParseTemplate("certificate-expiry-horizon",
+	`{{.Fields.count}} in-use platform certificates expire within {{.Fields.horizon}} of the end of the run.  Their rotation is overdue.
+{{.Fields.certificates}}`)
+
+var rotationTemplate = junitfailure.MustParseTemplate("certificate-rotated-unexpectedly",
+	`{{len .Intervals}} platform certificates were rotated before they were due or only after they expired.  Rotating early breaks clients that have not picked up the new signer yet.
+{{.IntervalList}}`)
+
+// evaluateExpiry fails for in-use certificates expiring within the horizon of the end of the run.  Certificates
+// valid for less than twice the horizon are short-lived on purpose and rotate faster than the horizon, so they are
+// not held to it.
+func evaluateExpiry(inventory *Inventory, horizon time.Duration) *junitapi.JUnitTestCase {
+	expiring := []string{}
+	for _, certificate := range inventory.Certificates {
+		if !certificate.InUse || certificate.validity() < 2*horizon {
+			continue
+		}
+		if remaining := certificate.NotAfter.Sub(inventory.Time); remaining < horizon {
+			expiring = append(expiring, fmt.Sprintf("secret/%s -n %s (%s) expires at %s, in %s",
+				certificate.Secret, certificate.Namespace, certificate.Subject, certificate.NotAfter.Format(time.RFC3339), remaining.Round(time.Minute)))
+		}
+	}
+	if len(expiring) == 0 {
+		return &junitapi.JUnitTestCase{Name: expiryTestName}
+	}
+
+	junit := junitfailure.NewFailure(expiryTemplate, "CertificateCloseToExpiry").
+		Field("count", len(expiring)).
+		Field("horizon", horizon.String()).
+		Field("certificates", strings.Join(expiring, "\n")).
+		TestCase(expiryTestName)
+	// a warning until we know which platform certificates get this close today.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+// evaluateRotations fails for certificates that rotated early or only once they had expired.
+func evaluateRotations(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	unexpected := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceCertificateRotation || interval.Message.Reason != monitorapi.CertificateRotatedReason {
+			continue
+		}
+		switch interval.Message.Annotations[monitorapi.AnnotationCause] {
+		case rotationEarly, rotationExpired:
+			unexpected = append(unexpected, interval)
+		}
+	}
+	if len(unexpected) == 0 {
+		return &junitapi.JUnitTestCase{Name: rotationTestName}
+	}
+
+	junit := junitfailure.NewFailure(rotationTemplate, "CertificateRotatedUnexpectedly").
+		Intervals(unexpected...).
+		TestCase(rotationTestName)
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,117 @@
+package certificateanalyzer
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformnamespaces"
+)
+
+// tlsSecretFieldSelector selects the secrets holding a serving or client certificate and its key.
+const tlsSecretFieldSelector = "type=" + string(corev1.SecretTypeTLS)
+
+// Certificate is the leaf certificate of a TLS secret in a platform namespace.
+type Certificate struct {
+	Namespace    string    `json:"namespace"`
+	Secret       string    `json:"secret"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serialNumber"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	InUse        bool      `json:"inUse"`
+}
+
+func (c Certificate) key() string {
+	return c.Namespace + "/" + c.Secret
+}
+
+func (c Certificate) validity() time.Duration {
+	return c.NotAfter.Sub(c.NotBefore)
+}
+
+// certificateFromSecret parses the first certificate of the tls.crt of a secret, the one the key belongs to.
+func certificateFromSecret(secret *corev1.Secret) (*Certificate, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("secret %s/%s has no PEM certificate", secret.Namespace, secret.Name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	return &Certificate{
+		Namespace:    secret.Namespace,
+		Secret:       secret.Name,
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+	}, nil
+}
+
+// Inventory is every certificate of the TLS secrets in platform namespaces at one point of the run.
+type Inventory struct {
+	Time         time.Time     `json:"time"`
+	Certificates []Certificate `json:"certificates"`
+}
+
+// takeInventory lists the TLS secrets in platform namespaces and marks the ones a pod mounts as in use.  Secrets
+// without a parseable certificate, which some operators create empty and fill later, are skipped.
+func takeInventory(ctx context.Context, kubeClient kubernetes.Interface, now time.Time) (*Inventory, error) {
+	secrets, err := kubeClient.CoreV1().Secrets("").List(ctx, metav1.ListOptions{FieldSelector: tlsSecretFieldSelector})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	inUse := mountedSecrets(pods.Items)
+
+	ret := &Inventory{Time: now}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !platformnamespaces.IsPlatformNamespace(secret.Namespace) {
+			continue
+		}
+		certificate, err := certificateFromSecret(secret)
+		if err != nil {
+			continue
+		}
+		certificate.InUse = inUse[certificate.key()]
+		ret.Certificates = append(ret.Certificates, *certificate)
+	}
+	sort.Slice(ret.Certificates, func(i, j int) bool { return ret.Certificates[i].key() < ret.Certificates[j].key() })
+	return ret, nil
+}
+
+// mountedSecrets returns namespace/name of every secret a pod mounts directly or through a projected volume.
+func mountedSecrets(pods []corev1.Pod) map[string]bool {
+	ret := map[string]bool{}
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Secret != nil {
+				ret[pod.Namespace+"/"+volume.Secret.SecretName] = true
+			}
+			if volume.Projected == nil {
+				continue
+			}
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					ret[pod.Namespace+"/"+source.Secret.Name] = true
+				}
+			}
+		}
+	}
+	return ret
+}
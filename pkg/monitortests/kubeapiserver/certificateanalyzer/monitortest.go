@@ -0,0 +1,130 @@
+package certificateanalyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type certificateAnalyzer struct {
+	expiryHorizon time.Duration
+	clock         clock.PassiveClock
+
+	kubeClient kubernetes.Interface
+	tracker    *rotationTracker
+
+	startInventory *Inventory
+	endInventory   *Inventory
+}
+
+// NewCertificateAnalyzer inventories the certificates of platform TLS secrets at the start and end of the run,
+// records their rotations and fails when in-use certificates are close to expiring or rotated when they were not due.
+func NewCertificateAnalyzer(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	expiryHorizon := info.CertificateExpiryHorizon
+	if expiryHorizon == 0 {
+		expiryHorizon = DefaultExpiryHorizon
+	}
+	return &certificateAnalyzer{
+		expiryHorizon: expiryHorizon,
+		clock:         clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &certificateAnalyzer{}
+
+func (w *certificateAnalyzer) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *certificateAnalyzer) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.WriteContentToStorageStage,
+		},
+		TestNames: []string{expiryTestName, rotationTestName},
+	}
+}
+
+func (w *certificateAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.kubeClient = kubeClient
+
+	w.startInventory, err = takeInventory(ctx, kubeClient, w.clock.Now())
+	if err != nil {
+		return fmt.Errorf("unable to inventory certificates: %w", err)
+	}
+	w.tracker = newRotationTracker(recorder, w.clock)
+	startRotationMonitoring(ctx, w.tracker, kubeClient)
+	return nil
+}
+
+func (w *certificateAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.kubeClient == nil {
+		return nil, nil, nil
+	}
+	// the rotations are in the recorder, only the inventory at the end of the run is left to take.
+	var err error
+	w.endInventory, err = takeInventory(ctx, w.kubeClient, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to inventory certificates: %w", err)
+	}
+	return nil, []*junitapi.JUnitTestCase{evaluateExpiry(w.endInventory, w.expiryHorizon)}, nil
+}
+
+func (*certificateAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *certificateAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.kubeClient == nil {
+		return nil, nil
+	}
+	return []*junitapi.JUnitTestCase{evaluateRotations(finalIntervals)}, nil
+}
+
+// certificateInventories is the content of certificate-inventory_<suffix>.json.
+type certificateInventories struct {
+	ExpiryHorizon string     `json:"expiryHorizon"`
+	Start         *Inventory `json:"start,omitempty"`
+	End           *Inventory `json:"end,omitempty"`
+}
+
+func (w *certificateAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.startInventory == nil && w.endInventory == nil {
+		return nil
+	}
+	inventoryBytes, err := json.MarshalIndent(certificateInventories{
+		ExpiryHorizon: w.expiryHorizon.String(),
+		Start:         w.startInventory,
+		End:           w.endInventory,
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	inventoryPath := filepath.Join(storageDir, fmt.Sprintf("certificate-inventory_%s.json", timeSuffix))
+	if err := os.WriteFile(inventoryPath, inventoryBytes, 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", inventoryPath, err)
+	}
+	return nil
+}
+
+func (*certificateAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,118 @@
+package certificateanalyzer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformnamespaces"
+)
+
+// causes of a certificate rotation.
+const (
+	// rotationDue is a certificate replaced after it passed its refresh point.
+	rotationDue = "Due"
+	// rotationExpired is a certificate replaced after it expired.
+	rotationExpired = "Expired"
+	// rotationSignerChanged is a certificate reissued by a new signer, after the signer itself rotated.
+	rotationSignerChanged = "SignerChanged"
+	// rotationEarly is a certificate replaced before it was due, by the same signer.
+	rotationEarly = "Early"
+
+	// refreshPoint is the fraction of its validity after which a certificate is due for rotation.  The library-go
+	// certificate rotation controllers refresh at half of the validity.
+	refreshPoint = 0.5
+)
+
+// rotationCause explains why previous was replaced by current at the time now.
+func rotationCause(previous, current Certificate, now time.Time) string {
+	switch {
+	case !now.Before(previous.NotAfter):
+		return rotationExpired
+	case previous.Issuer != current.Issuer:
+		return rotationSignerChanged
+	case now.Sub(previous.NotBefore) >= time.Duration(float64(previous.validity())*refreshPoint):
+		return rotationDue
+	default:
+		return rotationEarly
+	}
+}
+
+// rotationTracker records an interval whenever the certificate of a TLS secret in a platform namespace changes.
+type rotationTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock  sync.Mutex
+	known map[string]Certificate
+}
+
+func newRotationTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *rotationTracker {
+	return &rotationTracker{
+		recorder: recorder,
+		clock:    clock,
+		known:    map[string]Certificate{},
+	}
+}
+
+func startRotationMonitoring(ctx context.Context, tracker *rotationTracker, kubeClient kubernetes.Interface) {
+	kubeInformers := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = tlsSecretFieldSelector
+		}))
+	kubeInformers.Core().V1().Secrets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if secret, ok := obj.(*corev1.Secret); ok {
+				tracker.observe(secret, tracker.clock.Now())
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if secret, ok := obj.(*corev1.Secret); ok {
+				tracker.observe(secret, tracker.clock.Now())
+			}
+		},
+	})
+	kubeInformers.Start(ctx.Done())
+}
+
+func (t *rotationTracker) observe(secret *corev1.Secret, now time.Time) {
+	if !platformnamespaces.IsPlatformNamespace(secret.Namespace) {
+		return
+	}
+	current, err := certificateFromSecret(secret)
+	if err != nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	previous, ok := t.known[current.key()]
+	t.known[current.key()] = *current
+	if !ok || previous.SerialNumber == current.SerialNumber {
+		return
+	}
+
+	cause := rotationCause(previous, *current, now)
+	level := monitorapi.Info
+	if cause == rotationEarly || cause == rotationExpired {
+		level = monitorapi.Warning
+	}
+	t.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceCertificateRotation, level).
+			Locator(monitorapi.NewLocator().Secret(current.Namespace, current.Secret)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.CertificateRotatedReason).
+				Cause(cause).
+				HumanMessagef("%s rotated, the previous certificate was valid from %s until %s, the new one until %s",
+					current.Subject, previous.NotBefore.Format(time.RFC3339), previous.NotAfter.Format(time.RFC3339), current.NotAfter.Format(time.RFC3339))).
+			Display().
+			Build(now, now),
+	)
+}
@@ -0,0 +1,135 @@
+package rolloutanalyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	nodeUpdateTestName = "[sig-mco] machine config daemon node updates should complete within the historical P99"
+	degradedTestName   = "[sig-mco] machine config pools should not be degraded"
+
+	// historicalGrace is added on top of the P99, so that a run at the edge of the distribution does not fail.
+	historicalGrace = 0.2
+)
+
+// defaultPools are always evaluated, other pools only when they updated during the run.
+var defaultPools = []string{"master", "worker"}
+
+func poolRolloutTestName(pool string) string {
+	return fmt.Sprintf("[sig-mco] machine config pool %s rollouts should complete within the historical P99", pool)
+}
+
+var rolloutDurationTemplate = junitfailure.MustParseTemplate("machine-config-rollout-duration",
+	`the longest {{.Fields.rollout}} took {{.Fields.observed}}, longer than the {{.Fields.allowed}} allowed.  {{.Fields.details}}
+{{.IntervalList}}`)
+
+// evaluateRolloutDuration compares the longest of the intervals to the P99 of the rollout for similar jobs.
+func evaluateRolloutDuration(baseline historicaldata.BaselineProvider, jobType *platformidentification.JobType, rolloutName, testName string, intervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	if len(intervals) == 0 {
+		return &junitapi.JUnitTestCase{Name: testName}
+	}
+	if jobType == nil || len(jobType.Platform) == 0 {
+		return &junitapi.JUnitTestCase{
+			Name:        testName,
+			SkipMessage: &junitapi.SkipMessage{Message: "Unknown platform, skipping rollout duration testing"},
+		}
+	}
+	p99, details, err := historicaldata.BaselineP99(baseline, rolloutName, platformidentification.ClusterData{JobType: *jobType})
+	if err != nil || p99 == nil {
+		return &junitapi.JUnitTestCase{
+			Name:        testName,
+			SkipMessage: &junitapi.SkipMessage{Message: fmt.Sprintf("No historical data for %s %s %v", rolloutName, details, err)},
+		}
+	}
+
+	longest := intervals[0]
+	for _, interval := range intervals[1:] {
+		if interval.To.Sub(interval.From) > longest.To.Sub(longest.From) {
+			longest = interval
+		}
+	}
+	observed := longest.To.Sub(longest.From)
+	allowed := time.Duration(float64(*p99) * (1 + historicalGrace)).Round(time.Second)
+	threshold := junitapi.JUnitThreshold{
+		Name:     rolloutName,
+		Limit:    allowed.Seconds(),
+		Observed: observed.Seconds(),
+		Unit:     "seconds",
+		Exceeded: observed > allowed,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    testName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+
+	junit := junitfailure.NewFailure(rolloutDurationTemplate, "RolloutExceededHistoricalP99").
+		Threshold(threshold).
+		Field("rollout", rolloutName).
+		Field("observed", observed.Round(time.Second).String()).
+		Field("allowed", allowed.String()).
+		Field("details", fmt.Sprintf("P99 from historical data for similar jobs is %s %s", p99.Round(time.Second), details)).
+		Intervals(longest).
+		TestCase(testName)
+	// a warning until the historical data covers the common job types.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+var degradedTemplate = junitfailure.MustParseTemplate("machine-config-pool-degraded",
+	`{{len .Intervals}} times a machine config pool was degraded.  A degraded pool stops rolling out configuration to its nodes.
+{{.IntervalList}}`)
+
+func evaluateRollouts(baseline historicaldata.BaselineProvider, jobType *platformidentification.JobType, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	updatingByPool := map[string]monitorapi.Intervals{}
+	for _, pool := range defaultPools {
+		updatingByPool[pool] = monitorapi.Intervals{}
+	}
+	nodeUpdates := monitorapi.Intervals{}
+	degraded := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceMachineConfigRollout {
+			continue
+		}
+		switch interval.Message.Reason {
+		case monitorapi.MachineConfigPoolUpdatingReason:
+			pool := interval.Locator.Keys[monitorapi.LocatorMachineConfigPoolKey]
+			updatingByPool[pool] = append(updatingByPool[pool], interval)
+		case monitorapi.MachineConfigPoolDegradedReason:
+			degraded = append(degraded, interval)
+		case monitorapi.MachineConfigDaemonUpdateReason:
+			nodeUpdates = append(nodeUpdates, interval)
+		}
+	}
+
+	pools := make([]string, 0, len(updatingByPool))
+	for pool := range updatingByPool {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, pool := range pools {
+		ret = append(ret, evaluateRolloutDuration(baseline, jobType, poolRolloutName(pool), poolRolloutTestName(pool), updatingByPool[pool]))
+	}
+	ret = append(ret, evaluateRolloutDuration(baseline, jobType, nodeUpdateName, nodeUpdateTestName, nodeUpdates))
+
+	if len(degraded) == 0 {
+		return append(ret, &junitapi.JUnitTestCase{Name: degradedTestName})
+	}
+	junit := junitfailure.NewFailure(degradedTemplate, "MachineConfigPoolDegraded").
+		Intervals(degraded...).
+		TestCase(degradedTestName)
+	// a warning until we know how often pools degrade briefly and recover on their own.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return append(ret, junit)
+}
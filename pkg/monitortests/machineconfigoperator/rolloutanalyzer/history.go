@@ -0,0 +1,42 @@
+package rolloutanalyzer
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+)
+
+// rollout_durations.json holds the percentiles of rollout durations in seconds, in the same form as the backend
+// disruption data.  The BackendName of an entry names the rollout, see poolRolloutName and nodeUpdateName.  Until
+// enough runs have been collected for a job type, the percentile tests are skipped for it.
+//
+//go:embed rollout_durations.json
+var rolloutDurationsJSON []byte
+
+var (
+	readRolloutDurations sync.Once
+	rolloutDurations     *historicaldata.DisruptionBestMatcher
+)
+
+// getRolloutDurations returns the baseline configured in the environment, or the rollout durations compiled into the
+// binary.
+func getRolloutDurations() (historicaldata.BaselineProvider, error) {
+	readRolloutDurations.Do(func() {
+		var err error
+		rolloutDurations, err = historicaldata.NewDisruptionMatcher(rolloutDurationsJSON)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return historicaldata.BaselineProviderOr(rolloutDurations)
+}
+
+// poolRolloutName is the historical data key of the longest Updating window of a pool.
+func poolRolloutName(pool string) string {
+	return fmt.Sprintf("machine-config-pool-%s-updating", pool)
+}
+
+// nodeUpdateName is the historical data key of the longest update of a node, from cordon to uncordon.
+const nodeUpdateName = "machine-config-daemon-node-update"
@@ -0,0 +1,109 @@
+package rolloutanalyzer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mcfgclient "github.com/openshift/client-go/machineconfiguration/clientset/versioned"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type rolloutAnalyzer struct {
+	notSupportedReason error
+	jobType            *platformidentification.JobType
+	clock              clock.PassiveClock
+}
+
+// NewRolloutAnalyzer constructs the Updating and Degraded windows of machine config pools and the phases the machine
+// config daemon takes each node through, and compares rollout durations to historical data.
+func NewRolloutAnalyzer() monitortestframework.MonitorTest {
+	return &rolloutAnalyzer{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &rolloutAnalyzer{}
+
+func (w *rolloutAnalyzer) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *rolloutAnalyzer) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{nodeUpdateTestName, degradedTestName}
+	// pools other than the default ones are only evaluated when they updated during the run.
+	for _, pool := range defaultPools {
+		testNames = append(testNames, poolRolloutTestName(pool))
+	}
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *rolloutAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	mcoInstalled, err := exutil.DoesApiResourceExist(adminRESTConfig, "machineconfigpools", "machineconfiguration.openshift.io")
+	if err != nil {
+		return err
+	}
+	if !mcoInstalled {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the machine config operator is not installed"}
+		return w.notSupportedReason
+	}
+
+	w.jobType, err = platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		// the percentile tests are skipped.
+		fmt.Printf("unable to determine the job type for rollout durations: %v\n", err)
+	}
+
+	client, err := mcfgclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	startPoolMonitoring(ctx, newPoolTracker(recorder, w.clock), client)
+	return nil
+}
+
+func (w *rolloutAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, w.notSupportedReason
+}
+
+func (w *rolloutAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	ret := poolWindowIntervals(startingIntervals, beginning, end)
+	ret = append(ret, daemonPhaseIntervals(startingIntervals, end)...)
+	return ret, nil
+}
+
+func (w *rolloutAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	baseline, err := getRolloutDurations()
+	if err != nil {
+		return nil, err
+	}
+	return evaluateRollouts(baseline, w.jobType, finalIntervals), nil
+}
+
+func (w *rolloutAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *rolloutAnalyzer) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,108 @@
+package rolloutanalyzer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// phaseByEventReason maps the node events of an update to the phase they start.  The machine config daemon emits
+// all but Starting, which the kubelet emits once the node is back from the reboot.
+var phaseByEventReason = map[monitorapi.IntervalReason]string{
+	"Cordon":          "Cordon",
+	"Drain":           "Drain",
+	"OSUpdateStarted": "OperatingSystemUpdate",
+	"Reboot":          "Reboot",
+	"Starting":        "Uncordon",
+}
+
+// uncordonEventReason ends the update of a node.
+const uncordonEventReason monitorapi.IntervalReason = "Uncordon"
+
+type openPhase struct {
+	name string
+	from time.Time
+}
+
+// daemonPhaseIntervals constructs the phases the machine config daemon took each node through from the events of
+// the node, and an interval for the whole update from cordon to uncordon.  Events are best effort, so an update
+// starts at the first cordon or drain and every later event ends the phase before it.
+func daemonPhaseIntervals(startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	eventsByNode := map[string]monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourceKubeEvent || interval.Locator.Type != monitorapi.LocatorTypeNode {
+			continue
+		}
+		if _, ok := phaseByEventReason[interval.Message.Reason]; !ok && interval.Message.Reason != uncordonEventReason {
+			continue
+		}
+		node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+		eventsByNode[node] = append(eventsByNode[node], interval)
+	}
+
+	ret := monitorapi.Intervals{}
+	for node, events := range eventsByNode {
+		sort.Sort(events)
+		var updateFrom *time.Time
+		var phase *openPhase
+		for _, event := range events {
+			if phase != nil {
+				ret = append(ret, daemonPhaseInterval(node, phase.name, phase.from, event.From))
+				phase = nil
+			}
+
+			if event.Message.Reason == uncordonEventReason {
+				if updateFrom != nil {
+					ret = append(ret, daemonUpdateInterval(node, *updateFrom, event.From, true))
+					updateFrom = nil
+				}
+				continue
+			}
+
+			name := phaseByEventReason[event.Message.Reason]
+			if updateFrom == nil {
+				// a kubelet starting outside of an update is not part of one.
+				if name != "Cordon" && name != "Drain" {
+					continue
+				}
+				from := event.From
+				updateFrom = &from
+			}
+			phase = &openPhase{name: name, from: event.From}
+		}
+		if phase != nil {
+			ret = append(ret, daemonPhaseInterval(node, phase.name, phase.from, end))
+		}
+		if updateFrom != nil {
+			ret = append(ret, daemonUpdateInterval(node, *updateFrom, end, false))
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func daemonPhaseInterval(node, phase string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigDaemonPhaseReason).
+			Constructed(monitorapi.ConstructionOwnerMachineConfig).
+			WithAnnotation(monitorapi.AnnotationPhase, phase).
+			HumanMessagef("machine config daemon phase %s", phase)).
+		Display().
+		Build(from, to)
+}
+
+func daemonUpdateInterval(node string, from, to time.Time, finished bool) monitorapi.Interval {
+	humanMessage := "node updated from cordon to uncordon"
+	if !finished {
+		humanMessage = "node update did not finish"
+	}
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigDaemonUpdateReason).
+			Constructed(monitorapi.ConstructionOwnerMachineConfig).
+			HumanMessage(humanMessage)).
+		Display().
+		Build(from, to)
+}
@@ -0,0 +1,173 @@
+package rolloutanalyzer
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
+	mcfgclient "github.com/openshift/client-go/machineconfiguration/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// trackedPoolConditions are the pool conditions that become windows.
+var trackedPoolConditions = map[mcfgv1.MachineConfigPoolConditionType]monitorapi.IntervalReason{
+	mcfgv1.MachineConfigPoolUpdating: monitorapi.MachineConfigPoolUpdatingReason,
+	mcfgv1.MachineConfigPoolDegraded: monitorapi.MachineConfigPoolDegradedReason,
+}
+
+type poolCondition struct {
+	pool      string
+	condition mcfgv1.MachineConfigPoolConditionType
+}
+
+// poolTracker records an interval whenever a tracked condition of a pool changes, and the state of every tracked
+// condition when a pool is first seen.
+type poolTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock  sync.Mutex
+	known map[poolCondition]corev1.ConditionStatus
+}
+
+func newPoolTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *poolTracker {
+	return &poolTracker{
+		recorder: recorder,
+		clock:    clock,
+		known:    map[poolCondition]corev1.ConditionStatus{},
+	}
+}
+
+func startPoolMonitoring(ctx context.Context, tracker *poolTracker, client mcfgclient.Interface) {
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.MachineconfigurationV1().MachineConfigPools().List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.MachineconfigurationV1().MachineConfigPools().Watch(ctx, options)
+			},
+		},
+		&mcfgv1.MachineConfigPool{},
+		time.Hour,
+		nil,
+	)
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pool, ok := obj.(*mcfgv1.MachineConfigPool); ok {
+				tracker.observe(pool, tracker.clock.Now())
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pool, ok := obj.(*mcfgv1.MachineConfigPool); ok {
+				tracker.observe(pool, tracker.clock.Now())
+			}
+		},
+	})
+
+	go informer.Run(ctx.Done())
+}
+
+func (t *poolTracker) observe(pool *mcfgv1.MachineConfigPool, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, condition := range pool.Status.Conditions {
+		if _, ok := trackedPoolConditions[condition.Type]; !ok {
+			continue
+		}
+		key := poolCondition{pool: pool.Name, condition: condition.Type}
+		if previous, ok := t.known[key]; ok && previous == condition.Status {
+			continue
+		}
+		t.known[key] = condition.Status
+
+		level := monitorapi.Info
+		if condition.Status == corev1.ConditionTrue {
+			level = monitorapi.Warning
+			if condition.Type == mcfgv1.MachineConfigPoolDegraded {
+				level = monitorapi.Error
+			}
+		}
+		t.recorder.AddIntervals(
+			monitorapi.NewInterval(monitorapi.SourceMachineConfigPool, level).
+				Locator(monitorapi.NewLocator().MachineConfigPool(pool.Name)).
+				Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigPoolConditionChangedReason).
+					WithAnnotation(monitorapi.AnnotationCondition, string(condition.Type)).
+					WithAnnotation(monitorapi.AnnotationStatus, string(condition.Status)).
+					HumanMessage(condition.Message)).
+				Build(now, now),
+		)
+	}
+}
+
+// poolWindowIntervals turns the condition changes of every pool into windows in which Updating or Degraded was
+// true.  A condition true when the run started is true from the beginning, one still true at the end lasts until it.
+func poolWindowIntervals(startingIntervals monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	changesByCondition := map[poolCondition]monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourceMachineConfigPool || interval.Message.Reason != monitorapi.MachineConfigPoolConditionChangedReason {
+			continue
+		}
+		key := poolCondition{
+			pool:      interval.Locator.Keys[monitorapi.LocatorMachineConfigPoolKey],
+			condition: mcfgv1.MachineConfigPoolConditionType(interval.Message.Annotations[monitorapi.AnnotationCondition]),
+		}
+		changesByCondition[key] = append(changesByCondition[key], interval)
+	}
+
+	ret := monitorapi.Intervals{}
+	for key, changes := range changesByCondition {
+		reason, ok := trackedPoolConditions[key.condition]
+		if !ok {
+			continue
+		}
+		sort.Sort(changes)
+		var trueSince *time.Time
+		for _, change := range changes {
+			isTrue := change.Message.Annotations[monitorapi.AnnotationStatus] == string(corev1.ConditionTrue)
+			switch {
+			case isTrue && trueSince == nil:
+				from := change.From
+				if from.Before(beginning) {
+					from = beginning
+				}
+				trueSince = &from
+			case !isTrue && trueSince != nil:
+				ret = append(ret, poolWindowInterval(key.pool, reason, *trueSince, change.From))
+				trueSince = nil
+			}
+		}
+		if trueSince != nil {
+			ret = append(ret, poolWindowInterval(key.pool, reason, *trueSince, end))
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func poolWindowInterval(pool string, reason monitorapi.IntervalReason, from, to time.Time) monitorapi.Interval {
+	level := monitorapi.Warning
+	humanMessage := "pool was updating"
+	if reason == monitorapi.MachineConfigPoolDegradedReason {
+		level = monitorapi.Error
+		humanMessage = "pool was degraded"
+	}
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, level).
+		Locator(monitorapi.NewLocator().MachineConfigPool(pool)).
+		Message(monitorapi.NewMessage().Reason(reason).
+			Constructed(monitorapi.ConstructionOwnerMachineConfig).
+			HumanMessage(humanMessage)).
+		Display().
+		Build(from, to)
+}
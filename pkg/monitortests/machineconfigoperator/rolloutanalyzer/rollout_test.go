@@ -0,0 +1,122 @@
+package rolloutanalyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/historicaldata"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func conditionChange(pool, condition, status string, at time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceMachineConfigPool, monitorapi.Info).
+		Locator(monitorapi.NewLocator().MachineConfigPool(pool)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigPoolConditionChangedReason).
+			WithAnnotation(monitorapi.AnnotationCondition, condition).
+			WithAnnotation(monitorapi.AnnotationStatus, status)).
+		Build(start.Add(at), start.Add(at))
+}
+
+func nodeEvent(node string, reason monitorapi.IntervalReason, at time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(reason)).
+		Build(start.Add(at), start.Add(at))
+}
+
+func TestPoolWindowIntervals(t *testing.T) {
+	windows := poolWindowIntervals(monitorapi.Intervals{
+		conditionChange("worker", "Updating", "False", -time.Minute),
+		conditionChange("worker", "Updating", "True", 10*time.Minute),
+		conditionChange("worker", "Updating", "False", 40*time.Minute),
+		// still degraded when the run ended.
+		conditionChange("master", "Degraded", "True", 50*time.Minute),
+	}, start, start.Add(time.Hour))
+
+	if len(windows) != 2 {
+		t.Fatalf("expected an updating and a degraded window, got %v", windows)
+	}
+	if windows[0].Message.Reason != monitorapi.MachineConfigPoolUpdatingReason ||
+		!windows[0].From.Equal(start.Add(10*time.Minute)) || !windows[0].To.Equal(start.Add(40*time.Minute)) {
+		t.Errorf("expected the worker pool to update for 30 minutes, got %v", windows[0])
+	}
+	if windows[1].Message.Reason != monitorapi.MachineConfigPoolDegradedReason || !windows[1].To.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected the master pool to be degraded until the end, got %v", windows[1])
+	}
+}
+
+func TestDaemonPhaseIntervals(t *testing.T) {
+	intervals := daemonPhaseIntervals(monitorapi.Intervals{
+		// a kubelet start outside of an update.
+		nodeEvent("worker-a", "Starting", 0),
+		nodeEvent("worker-a", "Cordon", 10*time.Minute),
+		nodeEvent("worker-a", "Drain", 10*time.Minute+time.Second),
+		nodeEvent("worker-a", "OSUpdateStarted", 13*time.Minute),
+		nodeEvent("worker-a", "Reboot", 15*time.Minute),
+		nodeEvent("worker-a", "Starting", 18*time.Minute),
+		nodeEvent("worker-a", "Uncordon", 19*time.Minute),
+	}, start.Add(time.Hour))
+
+	phases := map[string]time.Duration{}
+	var update *monitorapi.Interval
+	for i, interval := range intervals {
+		switch interval.Message.Reason {
+		case monitorapi.MachineConfigDaemonPhaseReason:
+			phases[interval.Message.Annotations[monitorapi.AnnotationPhase]] = interval.To.Sub(interval.From)
+		case monitorapi.MachineConfigDaemonUpdateReason:
+			update = &intervals[i]
+		}
+	}
+	expected := map[string]time.Duration{
+		"Cordon":                time.Second,
+		"Drain":                 3*time.Minute - time.Second,
+		"OperatingSystemUpdate": 2 * time.Minute,
+		"Reboot":                3 * time.Minute,
+		"Uncordon":              time.Minute,
+	}
+	for phase, duration := range expected {
+		if phases[phase] != duration {
+			t.Errorf("expected phase %s to take %v, got %v", phase, duration, phases[phase])
+		}
+	}
+	if len(phases) != len(expected) {
+		t.Errorf("expected only the phases of the update, got %v", phases)
+	}
+	if update == nil || update.To.Sub(update.From) != 9*time.Minute {
+		t.Errorf("expected a nine minute update, got %v", update)
+	}
+}
+
+func TestEvaluateRolloutDuration(t *testing.T) {
+	jobType := platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	matcher := historicaldata.NewDisruptionMatcherWithHistoricalData(map[historicaldata.DataKey]historicaldata.DisruptionStatisticalData{
+		{BackendName: nodeUpdateName, JobType: jobType}: {
+			DataKey: historicaldata.DataKey{BackendName: nodeUpdateName, JobType: jobType},
+			P99:     600,
+			JobRuns: 500,
+		},
+	})
+	update := func(duration time.Duration) monitorapi.Intervals {
+		return monitorapi.Intervals{
+			monitorapi.NewInterval(monitorapi.SourceMachineConfigRollout, monitorapi.Info).
+				Locator(monitorapi.NewLocator().NodeFromName("worker-a")).
+				Message(monitorapi.NewMessage().Reason(monitorapi.MachineConfigDaemonUpdateReason)).
+				Build(start, start.Add(duration)),
+		}
+	}
+
+	if junit := evaluateRolloutDuration(matcher, &jobType, nodeUpdateName, nodeUpdateTestName, update(11*time.Minute)); junit.FailureOutput != nil {
+		t.Errorf("expected an update within the grace of the P99 to pass, got %v", junit.FailureOutput.Output)
+	}
+	if junit := evaluateRolloutDuration(matcher, &jobType, nodeUpdateName, nodeUpdateTestName, update(13*time.Minute)); junit.FailureOutput == nil {
+		t.Errorf("expected an update beyond the grace of the P99 to fail")
+	}
+	otherJobType := jobType
+	otherJobType.Platform = "metal"
+	if junit := evaluateRolloutDuration(matcher, &otherJobType, nodeUpdateName, nodeUpdateTestName, update(13*time.Minute)); junit.SkipMessage == nil {
+		t.Errorf("expected a job type without historical data to be skipped")
+	}
+}
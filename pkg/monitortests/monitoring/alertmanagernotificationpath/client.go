@@ -0,0 +1,131 @@
+package alertmanagernotificationpath
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// alert is the subset of an alert of the alertmanager v2 API the prober posts and reads back.
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt,omitempty"`
+	EndsAt      time.Time         `json:"endsAt,omitempty"`
+
+	Status    *alertStatus `json:"status,omitempty"`
+	Receivers []receiver   `json:"receivers,omitempty"`
+}
+
+type alertStatus struct {
+	// State is unprocessed until alertmanager has routed the alert, then active or suppressed.
+	State      string   `json:"state"`
+	SilencedBy []string `json:"silencedBy"`
+}
+
+type receiver struct {
+	Name string `json:"name"`
+}
+
+type matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type silence struct {
+	Matchers  []matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+// alertmanagerClient calls the alertmanager v2 API through the alertmanager-main route with a bearer token.
+type alertmanagerClient struct {
+	client *http.Client
+	token  string
+}
+
+func (c *alertmanagerClient) do(ctx context.Context, method, host, path string, query url.Values, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		content, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(content)
+	}
+	target := host + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, truncate(string(content)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(content, out)
+}
+
+func (c *alertmanagerClient) postAlerts(ctx context.Context, host string, alerts []alert) error {
+	return c.do(ctx, http.MethodPost, host, "/api/v2/alerts", nil, alerts, nil)
+}
+
+// getAlerts returns the alerts matching every label.
+func (c *alertmanagerClient) getAlerts(ctx context.Context, host string, labels map[string]string) ([]alert, error) {
+	query := url.Values{}
+	for name, value := range labels {
+		query.Add("filter", fmt.Sprintf("%s=%q", name, value))
+	}
+	ret := []alert{}
+	if err := c.do(ctx, http.MethodGet, host, "/api/v2/alerts", query, nil, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (c *alertmanagerClient) createSilence(ctx context.Context, host string, s silence) (string, error) {
+	out := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	if err := c.do(ctx, http.MethodPost, host, "/api/v2/silences", nil, s, &out); err != nil {
+		return "", err
+	}
+	return out.SilenceID, nil
+}
+
+func (c *alertmanagerClient) deleteSilence(ctx context.Context, host, id string) error {
+	return c.do(ctx, http.MethodDelete, host, "/api/v2/silence/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func truncate(s string) string {
+	if len(s) > 200 {
+		return s[:200] + "..."
+	}
+	return s
+}
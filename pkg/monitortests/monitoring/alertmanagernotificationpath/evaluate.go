@@ -0,0 +1,72 @@
+package alertmanagernotificationpath
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// maxBrokenDuration allows for the alertmanager pods of a single replica cluster to restart during an upgrade.  With
+// more replicas the route sends the alerts to the remaining pods and the path should not break at all.
+const maxBrokenDuration = 3 * time.Minute
+
+var notificationPathTestName = fmt.Sprintf("[sig-instrumentation] alertmanager notification path should not be broken for longer than %v", maxBrokenDuration)
+
+var brokenPathTemplate = junitfailure.MustParseTemplate("alertmanager-notification-path",
+	`the alertmanager notification path was broken for {{printf "%.0f" .Threshold.Observed}}s at once, longer than the {{printf "%.0f" .Threshold.Limit}}s allowed.  Alerts fired while it is broken are not routed, silenced or sent to receivers.
+{{.IntervalList}}`)
+
+// evaluateNotificationPath fails when the synthetic alerts could not be routed for longer than maxBrokenDuration in a
+// row.
+func evaluateNotificationPath(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	broken := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceAlertmanagerPath &&
+			interval.Message.Reason == monitorapi.AlertmanagerNotificationPathBrokenReason
+	})
+
+	// a path that keeps breaking differently is one outage, so consecutive intervals are added up.
+	var longest, current time.Duration
+	var currentEnd time.Time
+	tooLong := monitorapi.Intervals{}
+	currentIntervals := monitorapi.Intervals{}
+	for _, interval := range broken {
+		if !interval.From.Equal(currentEnd) {
+			current = 0
+			currentIntervals = monitorapi.Intervals{}
+		}
+		current += interval.To.Sub(interval.From)
+		currentEnd = interval.To
+		currentIntervals = append(currentIntervals, interval)
+		if current > longest {
+			longest = current
+		}
+		if current > maxBrokenDuration {
+			tooLong = append(tooLong, currentIntervals...)
+			currentIntervals = monitorapi.Intervals{}
+		}
+	}
+
+	threshold := junitapi.JUnitThreshold{
+		Name:     "alertmanager-notification-path-broken-seconds",
+		Limit:    maxBrokenDuration.Seconds(),
+		Observed: longest.Seconds(),
+		Unit:     "seconds",
+		Exceeded: longest > maxBrokenDuration,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    notificationPathTestName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+	junit := junitfailure.NewFailure(brokenPathTemplate, "AlertmanagerNotificationPathBroken").
+		Threshold(threshold).
+		Intervals(tooLong...).
+		TestCase(notificationPathTestName)
+	// a warning until we know how long the path breaks during upgrades on every topology.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,256 @@
+package alertmanagernotificationpath
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"sync"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+const (
+	monitoringNamespace = "openshift-monitoring"
+	routeName           = "alertmanager-main"
+	serviceAccountName  = "alertmanager-prober"
+)
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace   *corev1.Namespace
+	roleBinding *rbacv1.RoleBinding
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	roleBinding = resourceread.ReadRoleBindingV1OrDie(yamlOrDie("rolebinding.yaml"))
+}
+
+type alertmanagerNotificationPath struct {
+	notSupportedReason error
+	clock              clock.PassiveClock
+
+	kubeClient      kubernetes.Interface
+	namespaceName   string
+	roleBindingName string
+
+	prober    *prober
+	host      string
+	silenceID string
+	cancel    context.CancelFunc
+	done      sync.WaitGroup
+}
+
+// NewAlertmanagerNotificationPath sends synthetic alerts to alertmanager through its route while the tests run, and
+// checks they are routed to a receiver or suppressed by a silence within a deadline.
+func NewAlertmanagerNotificationPath() monitortestframework.MonitorTest {
+	return &alertmanagerNotificationPath{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &alertmanagerNotificationPath{}
+
+func (w *alertmanagerNotificationPath) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *alertmanagerNotificationPath) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: []string{notificationPathTestName},
+	}
+}
+
+func (w *alertmanagerNotificationPath) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+	routeClient, err := routeclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	// the route is missing when the monitoring capability is disabled or alertmanager is turned off.
+	_, err = routeClient.RouteV1().Routes(monitoringNamespace).Get(ctx, routeName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the cluster has no alertmanager route"}
+		return w.notSupportedReason
+	case err != nil:
+		return err
+	}
+
+	token, err := w.createServiceAccount(ctx)
+	if err != nil {
+		return err
+	}
+
+	hostGetter := backenddisruption.NewRouteHostGetter(adminRESTConfig, monitoringNamespace, routeName)
+	w.prober = newProber(token, w.namespaceName, hostGetter, recorder, w.clock)
+	w.host, err = hostGetter.GetHost()
+	if err != nil {
+		return err
+	}
+	// the role binding takes a moment to reach the proxy in front of alertmanager.
+	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
+		w.silenceID, err = w.prober.client.createSilence(ctx, w.host, silenceFor(w.namespaceName, w.clock.Now()))
+		if err != nil {
+			klog.Infof("unable to create the alertmanager silence, retrying: %v", err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create the alertmanager silence: %w", err)
+	}
+
+	probeCtx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done.Add(1)
+	go func() {
+		defer w.done.Done()
+		w.prober.run(probeCtx, w.silenceID)
+	}()
+	return nil
+}
+
+// createServiceAccount creates the service account the prober calls alertmanager as, and returns a token for it.
+func (w *alertmanagerNotificationPath) createServiceAccount(ctx context.Context) (string, error) {
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName}}
+	if _, err := w.kubeClient.CoreV1().ServiceAccounts(w.namespaceName).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil {
+		return "", err
+	}
+	binding := roleBinding.DeepCopy()
+	binding.Subjects[0].Namespace = w.namespaceName
+	actualBinding, err := w.kubeClient.RbacV1().RoleBindings(monitoringNamespace).Create(ctx, binding, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	w.roleBindingName = actualBinding.Name
+
+	expirationSeconds := int64(24 * time.Hour / time.Second)
+	tokenRequest, err := w.kubeClient.CoreV1().ServiceAccounts(w.namespaceName).CreateToken(ctx, serviceAccountName,
+		&authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return tokenRequest.Status.Token, nil
+}
+
+func (w *alertmanagerNotificationPath) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	// the prober records directly, it only has to stop.
+	w.cancel()
+	w.done.Wait()
+	return nil, nil, nil
+}
+
+func (w *alertmanagerNotificationPath) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *alertmanagerNotificationPath) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return []*junitapi.JUnitTestCase{evaluateNotificationPath(finalIntervals)}, nil
+}
+
+func (w *alertmanagerNotificationPath) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *alertmanagerNotificationPath) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+	return false, nil
+}
+
+func (w *alertmanagerNotificationPath) Cleanup(ctx context.Context) error {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	// the silence expires on its own, it only matches the alerts of this run.
+	if len(w.silenceID) > 0 {
+		if err := w.prober.client.deleteSilence(ctx, w.host, w.silenceID); err != nil {
+			klog.Errorf("Error deleting the alertmanager silence %s: %v", w.silenceID, err)
+		}
+	}
+	if len(w.roleBindingName) > 0 {
+		err := w.kubeClient.RbacV1().RoleBindings(monitoringNamespace).Delete(ctx, w.roleBindingName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		if err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted); err != nil {
+			return err
+		}
+	}
+	return w.notSupportedReason
+}
@@ -0,0 +1,227 @@
+package alertmanagernotificationpath
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	syntheticAlertName = "E2EAlertmanagerNotificationPath"
+	silencedLabel      = "e2e_silenced"
+	runLabel           = "e2e_run"
+	probeLabel         = "e2e_probe"
+
+	// probeInterval is how often a pair of synthetic alerts is sent.  Every probe is a new alert, so alertmanager
+	// has to route it again rather than remember a previous one.
+	probeInterval = 30 * time.Second
+	// deliveryDeadline is how long alertmanager has to route the alerts of a probe.  Prometheus sends alerts every
+	// minute and alertmanager groups them for 30s before notifying, so a path slower than this misses notifications.
+	deliveryDeadline = 30 * time.Second
+	requestTimeout   = 10 * time.Second
+)
+
+// prober sends a pair of synthetic alerts every probeInterval, one the silence matches and one it does not, and
+// records when the alerts stop reaching the state they must be routed to.
+type prober struct {
+	client     *alertmanagerClient
+	hostGetter backenddisruption.HostGetter
+	recorder   monitorapi.RecorderWriter
+	clock      clock.PassiveClock
+	runID      string
+	// deadline is how long a probe waits for its alerts to be routed.
+	deadline time.Duration
+
+	probes             int
+	previousError      error
+	previousIntervalID int
+	lastProbe          time.Time
+}
+
+func newProber(token, runID string, hostGetter backenddisruption.HostGetter, recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *prober {
+	return &prober{
+		client: &alertmanagerClient{
+			token: token,
+			client: &http.Client{
+				Timeout: requestTimeout,
+				Transport: &http.Transport{
+					// routes are served with the certificate of the ingress operator, which the test does not trust.
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+					Proxy:           http.ProxyFromEnvironment,
+				},
+			},
+		},
+		hostGetter:         hostGetter,
+		recorder:           recorder,
+		clock:              clock,
+		runID:              runID,
+		deadline:           deliveryDeadline,
+		previousIntervalID: -1,
+	}
+}
+
+func alertmanagerLocator() monitorapi.Locator {
+	return monitorapi.NewLocator().KindInNamespace("Alertmanager", "openshift-monitoring", "main")
+}
+
+// silenceFor matches the silenced alerts of the run.
+func silenceFor(runID string, now time.Time) silence {
+	return silence{
+		Matchers: []matcher{
+			{Name: "alertname", Value: syntheticAlertName, IsEqual: true},
+			{Name: runLabel, Value: runID, IsEqual: true},
+			{Name: silencedLabel, Value: "true", IsEqual: true},
+		},
+		StartsAt:  now,
+		EndsAt:    now.Add(24 * time.Hour),
+		CreatedBy: "openshift-tests",
+		Comment:   "silences the synthetic alerts of the alertmanager notification path monitor test",
+	}
+}
+
+// syntheticAlerts returns the alerts of a probe.  They resolve on their own shortly after the deadline.
+func syntheticAlerts(runID, probeID string, now time.Time) []alert {
+	ret := []alert{}
+	for _, silenced := range []bool{false, true} {
+		ret = append(ret, alert{
+			Labels: map[string]string{
+				"alertname":   syntheticAlertName,
+				"severity":    "none",
+				runLabel:      runID,
+				probeLabel:    probeID,
+				silencedLabel: strconv.FormatBool(silenced),
+			},
+			Annotations: map[string]string{
+				"summary": "synthetic alert sent by openshift-tests to check the alertmanager notification path",
+			},
+			StartsAt: now,
+			EndsAt:   now.Add(deliveryDeadline + probeInterval),
+		})
+	}
+	return ret
+}
+
+// checkRouted returns why the alerts of a probe are not yet routed: the alert without a silence must be active with a
+// receiver, and the silenced alert must be suppressed by the silence.
+func checkRouted(alerts []alert, silenceID string) error {
+	var active, suppressed bool
+	for _, curr := range alerts {
+		if curr.Status == nil {
+			continue
+		}
+		switch curr.Labels[silencedLabel] {
+		case "false":
+			active = curr.Status.State == "active" && len(curr.Receivers) > 0
+		case "true":
+			for _, silencedBy := range curr.Status.SilencedBy {
+				if silencedBy == silenceID {
+					suppressed = curr.Status.State == "suppressed"
+				}
+			}
+		}
+	}
+	switch {
+	case !active && !suppressed:
+		return fmt.Errorf("neither synthetic alert was routed")
+	case !active:
+		return fmt.Errorf("the synthetic alert was not routed to a receiver")
+	case !suppressed:
+		return fmt.Errorf("the synthetic alert was not suppressed by its silence")
+	}
+	return nil
+}
+
+// probe sends the alerts of a probe and waits until they are routed or the deadline passes.
+func (p *prober) probe(ctx context.Context, host, silenceID string) error {
+	p.probes++
+	probeID := strconv.Itoa(p.probes)
+	if err := p.client.postAlerts(ctx, host, syntheticAlerts(p.runID, probeID, p.clock.Now())); err != nil {
+		return fmt.Errorf("unable to send the synthetic alerts: %w", err)
+	}
+
+	deadline := p.clock.Now().Add(p.deadline)
+	for {
+		alerts, err := p.client.getAlerts(ctx, host, map[string]string{"alertname": syntheticAlertName, runLabel: p.runID, probeLabel: probeID})
+		if err == nil {
+			err = checkRouted(alerts, silenceID)
+		} else {
+			err = fmt.Errorf("unable to read the synthetic alerts: %w", err)
+		}
+		if err == nil || p.clock.Now().After(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// run probes until the context is done, then closes the open interval.
+func (p *prober) run(ctx context.Context, silenceID string) {
+	defer func() {
+		if p.previousIntervalID != -1 {
+			p.recorder.EndInterval(p.previousIntervalID, p.lastProbe.Add(probeInterval))
+		}
+	}()
+
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+	for {
+		start := p.clock.Now()
+		host, err := p.hostGetter.GetHost()
+		if err == nil {
+			err = p.probe(ctx, host, silenceID)
+		}
+		// a probe cut short by the end of the run did not fail.
+		if ctx.Err() != nil {
+			return
+		}
+		p.observe(start, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// observe records the result of the probe started at the time.  An interval is open while the path is broken, and a
+// new one starts when it breaks differently.
+func (p *prober) observe(at time.Time, err error) {
+	p.lastProbe = at
+	previousError := p.previousError
+	p.previousError = err
+
+	switch {
+	case err == nil && previousError == nil:
+		return
+	case err != nil && previousError != nil && err.Error() == previousError.Error():
+		return
+	}
+
+	if p.previousIntervalID != -1 {
+		p.recorder.EndInterval(p.previousIntervalID, at)
+		p.previousIntervalID = -1
+	}
+	if err == nil {
+		return
+	}
+	p.previousIntervalID = p.recorder.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourceAlertmanagerPath, monitorapi.Error).
+			Locator(alertmanagerLocator()).
+			Message(monitorapi.NewMessage().Reason(monitorapi.AlertmanagerNotificationPathBrokenReason).
+				HumanMessagef("alertmanager notification path is broken: %v", err)).
+			Display().
+			Build(at, time.Time{}))
+}
@@ -0,0 +1,92 @@
+package alertmanagernotificationpath
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fakeAlertmanager routes every alert to a receiver, and suppresses the silenced ones when silencing works.
+func fakeAlertmanager(t *testing.T, silencing bool) *httptest.Server {
+	posted := []alert{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/alerts":
+			alerts := []alert{}
+			if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+				t.Error(err)
+				return
+			}
+			posted = append(posted, alerts...)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/alerts":
+			ret := []alert{}
+			for _, curr := range posted {
+				curr.Status = &alertStatus{State: "active"}
+				curr.Receivers = []receiver{{Name: "Default"}}
+				if silencing && curr.Labels[silencedLabel] == "true" {
+					curr.Status = &alertStatus{State: "suppressed", SilencedBy: []string{"silence"}}
+				}
+				ret = append(ret, curr)
+			}
+			json.NewEncoder(w).Encode(ret)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestProbe(t *testing.T) {
+	for _, silencing := range []bool{true, false} {
+		server := fakeAlertmanager(t, silencing)
+		p := newProber("token", "run", nil, nil, clock.RealClock{})
+		p.deadline = time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := p.probe(ctx, server.URL, "silence")
+		cancel()
+		server.Close()
+		if silencing && err != nil {
+			t.Errorf("expected the alerts to be routed, got %v", err)
+		}
+		if !silencing && (err == nil || err.Error() != "the synthetic alert was not suppressed by its silence") {
+			t.Errorf("expected the silence to be missed, got %v", err)
+		}
+	}
+}
+
+func TestObserveAndEvaluate(t *testing.T) {
+	recorder := monitor.NewRecorder()
+	p := newProber("token", "run", nil, recorder, clock.RealClock{})
+	notRouted := checkRouted(nil, "silence")
+	for i, err := range []error{nil, notRouted, notRouted, nil, notRouted, notRouted, notRouted, notRouted, notRouted, notRouted, notRouted, nil} {
+		p.observe(start.Add(time.Duration(i)*probeInterval), err)
+	}
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 2 {
+		t.Fatalf("expected an interval for every outage, got %v", intervals)
+	}
+	if !intervals[1].From.Equal(start.Add(4*probeInterval)) || !intervals[1].To.Equal(start.Add(11*probeInterval)) {
+		t.Errorf("expected the second outage to last until the path recovered, got %v", intervals[1])
+	}
+
+	junit := evaluateNotificationPath(intervals)
+	if junit.FailureOutput == nil || len(junit.Details.IntervalIDs) != 1 {
+		t.Errorf("expected only the outage over %v to fail, got %#v", maxBrokenDuration, junit)
+	}
+	if junit := evaluateNotificationPath(intervals[:1]); junit.FailureOutput != nil {
+		t.Errorf("expected a short outage to pass, got %v", junit.FailureOutput.Output)
+	}
+}
@@ -0,0 +1,316 @@
+package connectivitymesh
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	k8simage "k8s.io/kubernetes/test/utils/image"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+	"github.com/openshift/origin/test/extended/util/image"
+)
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace                   *corev1.Namespace
+	pollerRoleBinding           *rbacv1.RoleBinding
+	pollerDeploymentTemplate    *appsv1.Deployment
+	podNetworkTargetDeployment  *appsv1.Deployment
+	podNetworkTargetService     *corev1.Service
+	hostNetworkTargetDeployment *appsv1.Deployment
+	hostNetworkTargetService    *corev1.Service
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	pollerRoleBinding = resourceread.ReadRoleBindingV1OrDie(yamlOrDie("poller-rolebinding.yaml"))
+	pollerDeploymentTemplate = resourceread.ReadDeploymentV1OrDie(yamlOrDie("poller-deployment.yaml"))
+	podNetworkTargetDeployment = resourceread.ReadDeploymentV1OrDie(yamlOrDie("pod-network-target-deployment.yaml"))
+	podNetworkTargetService = resourceread.ReadServiceV1OrDie(yamlOrDie("pod-network-target-service.yaml"))
+	hostNetworkTargetDeployment = resourceread.ReadDeploymentV1OrDie(yamlOrDie("host-network-target-deployment.yaml"))
+	hostNetworkTargetService = resourceread.ReadServiceV1OrDie(yamlOrDie("host-network-target-service.yaml"))
+}
+
+type connectivityMesh struct {
+	payloadImagePullSpec string
+	notSupportedReason   error
+
+	kubeClient    kubernetes.Interface
+	namespaceName string
+}
+
+// NewConnectivityMesh connects from the pod and host network of every node to the pod and host network of every
+// other node, and fails when a path loses connectivity without a network rollout or node update to explain it.
+func NewConnectivityMesh(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &connectivityMesh{
+		payloadImagePullSpec: info.UpgradeTargetPayloadImagePullSpec,
+	}
+}
+
+func (w *connectivityMesh) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, path := range meshPaths {
+		testNames = append(testNames, pollerLogsTestName(path), outageTestName(path))
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *connectivityMesh) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: "platform MicroShift not supported",
+		}
+		return w.notSupportedReason
+	}
+
+	openshiftTestsImagePullSpec, err := disruptionpodnetwork.GetOpenshiftTestsImagePullSpec(ctx, adminRESTConfig, w.payloadImagePullSpec, nil)
+	if err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("unable to determine openshift-tests image: %v", err),
+		}
+		return w.notSupportedReason
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	if _, err := w.kubeClient.RbacV1().RoleBindings(w.namespaceName).Create(ctx, pollerRoleBinding, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	// our pods tolerate masters, so create one for each node.
+	nodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	numNodes := int32(len(nodes.Items))
+
+	// force the image to use the "normal" global mapping.
+	originalAgnhost := k8simage.GetOriginalImageConfigs()[k8simage.Agnhost]
+	podTargets := podNetworkTargetDeployment.DeepCopy()
+	podTargets.Spec.Replicas = &numNodes
+	podTargets.Spec.Template.Spec.Containers[0].Image = image.LocationFor(originalAgnhost.GetE2EImage())
+	hostTargets := hostNetworkTargetDeployment.DeepCopy()
+	hostTargets.Spec.Replicas = &numNodes
+	hostTargets.Spec.Template.Spec.Containers[0].Image = image.LimitedShellImage()
+	for _, deployment := range []*appsv1.Deployment{podTargets, hostTargets} {
+		if _, err := w.kubeClient.AppsV1().Deployments(w.namespaceName).Create(ctx, deployment, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+	for _, service := range []*corev1.Service{podNetworkTargetService, hostNetworkTargetService} {
+		if _, err := w.kubeClient.CoreV1().Services(w.namespaceName).Create(ctx, service, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+
+	// the pollers follow the endpoints of the targets as they come and go, so they can start right away.
+	deploymentID := uuid.New().String()
+	for _, path := range meshPaths {
+		deployment := pollerDeployment(path, numNodes, openshiftTestsImagePullSpec, deploymentID)
+		if _, err := w.kubeClient.AppsV1().Deployments(w.namespaceName).Create(ctx, deployment, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *connectivityMesh) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	// create the stop collecting configmap and wait for 30s for the pollers to have stopped.  the 30s is just a guess
+	if _, err := w.kubeClient.CoreV1().ConfigMaps(w.namespaceName).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: stopConfigMapName},
+	}, metav1.CreateOptions{}); err != nil {
+		return nil, nil, err
+	}
+	select {
+	case <-time.After(30 * time.Second):
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	retIntervals := monitorapi.Intervals{}
+	junits := []*junitapi.JUnitTestCase{}
+	errs := []error{}
+	for _, path := range meshPaths {
+		intervals, junit, err := w.collectPollerIntervals(ctx, path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		retIntervals = append(retIntervals, intervals...)
+		junits = append(junits, junit)
+	}
+	return retIntervals, junits, utilerrors.NewAggregate(errs)
+}
+
+func pollerLogsTestName(path meshPath) string {
+	return fmt.Sprintf("[sig-network] can collect %s connectivity mesh poller pod logs", path.name)
+}
+
+// collectPollerIntervals scrapes the intervals the pollers of a path logged.  Pollers log every connection they
+// start, so a poller without intervals did not poll.
+func (w *connectivityMesh) collectPollerIntervals(ctx context.Context, path meshPath) (monitorapi.Intervals, *junitapi.JUnitTestCase, error) {
+	logJunit := &junitapi.JUnitTestCase{
+		Name: pollerLogsTestName(path),
+	}
+	pollerPods, err := w.kubeClient.CoreV1().Pods(w.namespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=poller,%s=%s", meshActorLabel, meshPathLabel, path.name),
+	})
+	if err != nil {
+		logJunit.FailureOutput = &junitapi.FailureOutput{Output: err.Error()}
+		return nil, logJunit, err
+	}
+
+	retIntervals := monitorapi.Intervals{}
+	errs := []error{}
+	logs := &strings.Builder{}
+	podsWithoutIntervals := []string{}
+	for _, pollerPod := range pollerPods.Items {
+		fmt.Fprintf(logs, "\n\nLogs for -n %v pod/%v\n", pollerPod.Namespace, pollerPod.Name)
+		logStream, err := w.kubeClient.CoreV1().Pods(w.namespaceName).GetLogs(pollerPod.Name, &corev1.PodLogOptions{}).Stream(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		foundInterval := false
+		scanner := bufio.NewScanner(logStream)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			logs.Write(line)
+			logs.WriteString("\n")
+			if len(line) == 0 {
+				continue
+			}
+			// not all lines are json, ignore errors.
+			if currInterval, err := monitorserialization.IntervalFromJSON(line); err == nil {
+				retIntervals = append(retIntervals, *currInterval)
+				foundInterval = true
+			}
+		}
+		logStream.Close()
+		if !foundInterval {
+			podsWithoutIntervals = append(podsWithoutIntervals, pollerPod.Name)
+		}
+	}
+
+	failures := []string{}
+	if len(podsWithoutIntervals) > 0 {
+		failures = append(failures, fmt.Sprintf("%d pods lacked sampler output: [%v]", len(podsWithoutIntervals), strings.Join(podsWithoutIntervals, ", ")))
+	}
+	if len(pollerPods.Items) == 0 {
+		failures = append(failures, fmt.Sprintf("no pods found for the %s pollers", path.name))
+	}
+	logJunit.SystemOut = logs.String()
+	if len(failures) > 0 {
+		logJunit.FailureOutput = &junitapi.FailureOutput{
+			Output: strings.Join(failures, "\n"),
+		}
+	}
+	return retIntervals, logJunit, utilerrors.NewAggregate(errs)
+}
+
+func (w *connectivityMesh) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return pathOutageIntervals(startingIntervals), nil
+}
+
+func (w *connectivityMesh) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateOutages(finalIntervals), nil
+}
+
+func (w *connectivityMesh) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *connectivityMesh) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (w *connectivityMesh) Cleanup(ctx context.Context) error {
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		if err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted); err != nil {
+			return err
+		}
+
+		klog.Infof("Deleting namespace: %s took %.2f seconds", w.namespaceName, time.Now().Sub(startTime).Seconds())
+	}
+	return w.notSupportedReason
+}
@@ -0,0 +1,181 @@
+package connectivitymesh
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// mergeGap is how far apart failures of a path may be and still be one outage.  The new and reused connection
+	// samplers of a path fail at slightly different times during the same outage.
+	mergeGap = time.Second
+
+	// networkPodRestartGrace is how long after a network pod on a node is deleted the node may lose connectivity.
+	// The pod is replaced and programs the node again in that time.
+	networkPodRestartGrace = 2 * time.Minute
+)
+
+// instanceRegex parses the disruption instance of a poller, see the watch-endpoint-slice command.
+var instanceRegex = regexp.MustCompile(`^` + backendPrefix + `(.+)-from-node-(.+)-to-node-(.+)-endpoint-(.+)$`)
+
+// networkNamespaces run the pods programming the network of the nodes, for OVN-Kubernetes and OpenShift SDN.
+var networkNamespaces = map[string]bool{
+	"openshift-ovn-kubernetes": true,
+	"openshift-sdn":            true,
+}
+
+type pathKey struct {
+	path     string
+	fromNode string
+	toNode   string
+}
+
+// pathOutageIntervals merges the failures the pollers of the mesh recorded into one interval for every outage of a
+// path from one node to another, regardless of the endpoint or the connection type that failed.
+func pathOutageIntervals(startingIntervals monitorapi.Intervals) monitorapi.Intervals {
+	failuresByPath := map[pathKey]monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		if !monitorapi.IsDisruptionEvent(interval) || interval.Level != monitorapi.Error {
+			continue
+		}
+		if !strings.HasPrefix(interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey], backendPrefix) {
+			continue
+		}
+		match := instanceRegex.FindStringSubmatch(interval.Locator.Keys[monitorapi.LocatorDisruptionKey])
+		if match == nil {
+			continue
+		}
+		key := pathKey{path: match[1], fromNode: match[2], toNode: match[3]}
+		failuresByPath[key] = append(failuresByPath[key], interval)
+	}
+
+	ret := monitorapi.Intervals{}
+	for key, failures := range failuresByPath {
+		sort.Sort(failures)
+		from, to := failures[0].From, failures[0].To
+		for _, failure := range failures[1:] {
+			if failure.From.After(to.Add(mergeGap)) {
+				ret = append(ret, pathOutageInterval(key, from, to))
+				from = failure.From
+			}
+			if failure.To.After(to) {
+				to = failure.To
+			}
+		}
+		ret = append(ret, pathOutageInterval(key, from, to))
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func pathOutageInterval(key pathKey, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceNetworkMesh, monitorapi.Error).
+		Locator(monitorapi.NewLocator().NetworkPath(key.path, key.fromNode, key.toNode)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NetworkPathOutageReason).
+			Constructed(monitorapi.ConstructionOwnerNetworkMesh).
+			HumanMessagef("%s connections from node/%s to node/%s failed", key.path, key.fromNode, key.toNode)).
+		Display().
+		Build(from, to)
+}
+
+// explanations are the times connectivity between nodes is allowed to fail.
+type explanations struct {
+	// byNode holds the updates of a node and the restarts of the network pods on it.
+	byNode map[string]monitorapi.Intervals
+	// networkRollouts are the times the network operator was progressing, rolling out to every node.
+	networkRollouts monitorapi.Intervals
+}
+
+func explanationsFrom(finalIntervals monitorapi.Intervals) explanations {
+	ret := explanations{byNode: map[string]monitorapi.Intervals{}}
+	for _, interval := range finalIntervals {
+		switch {
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeUpdateReason:
+			node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+			ret.byNode[node] = append(ret.byNode[node], interval)
+
+		case interval.Source == monitorapi.SourcePodMonitor && interval.Message.Reason == monitorapi.PodReasonGracefulDeleteStarted:
+			node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+			if len(node) == 0 || !networkNamespaces[interval.Locator.Keys[monitorapi.LocatorNamespaceKey]] {
+				continue
+			}
+			restart := interval
+			restart.To = interval.From.Add(networkPodRestartGrace)
+			ret.byNode[node] = append(ret.byNode[node], restart)
+
+		case interval.Source == monitorapi.SourceOperatorState && interval.Locator.Keys[monitorapi.LocatorClusterOperatorKey] == "network":
+			if interval.Message.Annotations[monitorapi.AnnotationCondition] == string(configv1.OperatorProgressing) &&
+				interval.Message.Annotations[monitorapi.AnnotationStatus] == string(configv1.ConditionTrue) {
+				ret.networkRollouts = append(ret.networkRollouts, interval)
+			}
+		}
+	}
+	return ret
+}
+
+// explain returns whether a network rollout or an update or network pod restart on either node explains the outage.
+func (e explanations) explain(outage monitorapi.Interval) bool {
+	return startedDuring(outage, e.networkRollouts) ||
+		startedDuring(outage, e.byNode[outage.Locator.Keys[monitorapi.LocatorNodeKey]]) ||
+		startedDuring(outage, e.byNode[outage.Locator.Keys[monitorapi.LocatorToNodeKey]])
+}
+
+func startedDuring(window monitorapi.Interval, explanations monitorapi.Intervals) bool {
+	for _, explanation := range explanations {
+		if !window.From.Before(explanation.From) && !window.From.After(explanation.To) {
+			return true
+		}
+	}
+	return false
+}
+
+func outageTestName(path meshPath) string {
+	return fmt.Sprintf("[sig-network] %s connectivity between nodes should only be lost during network rollouts or node updates", path.name)
+}
+
+var unexplainedOutageTemplate = junitfailure.MustParseTemplate("unexplained-network-path-outage",
+	`{{len .Intervals}} {{.Fields.path}} outages started while neither node was updating, no network pod on either node was restarting, and the network operator was not rolling out.
+{{.IntervalList}}`)
+
+// evaluateOutages produces a test for every path of the mesh, failing for outages nothing explains.
+func evaluateOutages(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	explanations := explanationsFrom(finalIntervals)
+
+	unexplainedByPath := map[string]monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceNetworkMesh || interval.Message.Reason != monitorapi.NetworkPathOutageReason {
+			continue
+		}
+		if !explanations.explain(interval) {
+			path := interval.Locator.Keys[monitorapi.LocatorNetworkPathKey]
+			unexplainedByPath[path] = append(unexplainedByPath[path], interval)
+		}
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, path := range meshPaths {
+		testName := outageTestName(path)
+		unexplained := unexplainedByPath[path.name]
+		if len(unexplained) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+		junit := junitfailure.NewFailure(unexplainedOutageTemplate, "UnexplainedNetworkPathOutage").
+			Field("path", path.name).
+			Intervals(unexplained...).
+			TestCase(testName)
+		// a warning until we know how often the mesh loses connectivity for other reasons.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
@@ -0,0 +1,103 @@
+package connectivitymesh
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func pollerFailure(path, fromNode, toNode string, connectionType monitorapi.BackendConnectionType, from, to time.Duration) monitorapi.Interval {
+	backend := backendPrefix + path + "-" + string(connectionType) + "-connections"
+	instance := backendPrefix + path + "-from-node-" + fromNode + "-to-node-" + toNode + "-endpoint-10.128.0.10"
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+		Locator(monitorapi.NewLocator().LocateDisruptionCheck(backend, instance, connectionType)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).HumanMessage("stopped responding")).
+		Build(start.Add(from), start.Add(to))
+}
+
+func TestPathOutageIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		pollerFailure("pod-to-pod", "worker-a", "worker-b", monitorapi.NewConnectionType, 10*time.Second, 20*time.Second),
+		pollerFailure("pod-to-pod", "worker-a", "worker-b", monitorapi.ReusedConnectionType, 11*time.Second, 25*time.Second),
+		pollerFailure("pod-to-pod", "worker-a", "worker-b", monitorapi.NewConnectionType, 5*time.Minute, 5*time.Minute+time.Second),
+		pollerFailure("host-to-pod", "worker-b", "worker-a", monitorapi.NewConnectionType, 10*time.Second, 12*time.Second),
+	}
+
+	outages := pathOutageIntervals(intervals)
+	if len(outages) != 3 {
+		t.Fatalf("expected the overlapping failures of a path to be one outage, got %v", outages)
+	}
+	var first *monitorapi.Interval
+	for i := range outages {
+		if outages[i].Locator.Keys[monitorapi.LocatorNetworkPathKey] == "pod-to-pod" {
+			first = &outages[i]
+			break
+		}
+	}
+	if first == nil {
+		t.Fatalf("expected a pod-to-pod outage, got %v", outages)
+	}
+	if !first.From.Equal(start.Add(10*time.Second)) || !first.To.Equal(start.Add(25*time.Second)) {
+		t.Errorf("expected the first outage to last from the first failure to the last, got %v to %v", first.From, first.To)
+	}
+	if keys := first.Locator.Keys; keys[monitorapi.LocatorNodeKey] != "worker-a" || keys[monitorapi.LocatorToNodeKey] != "worker-b" {
+		t.Errorf("expected the outage to locate both nodes, got %v", keys)
+	}
+}
+
+func TestEvaluateOutages(t *testing.T) {
+	outages := pathOutageIntervals(monitorapi.Intervals{
+		// explained by the update of the target node.
+		pollerFailure("pod-to-pod", "worker-a", "worker-b", monitorapi.NewConnectionType, 10*time.Minute, 11*time.Minute),
+		// explained by the ovnkube-node pod of the source node restarting.
+		pollerFailure("pod-to-host", "worker-c", "worker-a", monitorapi.NewConnectionType, 21*time.Minute, 22*time.Minute),
+		// explained by the network operator rolling out.
+		pollerFailure("host-to-pod", "worker-a", "worker-c", monitorapi.NewConnectionType, 31*time.Minute, 32*time.Minute),
+		// unexplained.
+		pollerFailure("host-to-host", "worker-a", "worker-c", monitorapi.NewConnectionType, 41*time.Minute, 42*time.Minute),
+	})
+
+	ovnPod := monitorapi.NewLocator().PodFromNames("openshift-ovn-kubernetes", "ovnkube-node-abcde", "uid")
+	ovnPod.Keys[monitorapi.LocatorNodeKey] = "worker-c"
+	explanations := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("worker-b")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason)).
+			Build(start.Add(9*time.Minute), start.Add(15*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Info).
+			Locator(ovnPod).
+			Message(monitorapi.NewMessage().Reason(monitorapi.PodReasonGracefulDeleteStarted)).
+			Build(start.Add(20*time.Minute), start.Add(20*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceOperatorState, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().ClusterOperator("network")).
+			Message(monitorapi.NewMessage().Reason("Deploying").
+				WithAnnotation(monitorapi.AnnotationCondition, string(configv1.OperatorProgressing)).
+				WithAnnotation(monitorapi.AnnotationStatus, string(configv1.ConditionTrue))).
+			Build(start.Add(30*time.Minute), start.Add(35*time.Minute)),
+	}
+
+	junits := evaluateOutages(append(outages, explanations...))
+	if len(junits) != len(meshPaths) {
+		t.Fatalf("expected a test per path, got %v", junits)
+	}
+	for _, junit := range junits {
+		switch junit.Name {
+		case "[sig-network] host-to-host connectivity between nodes should only be lost during network rollouts or node updates":
+			if junit.FailureOutput == nil {
+				t.Errorf("expected the unexplained host-to-host outage to fail")
+			} else if junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning, got %q", junit.Details.Severity)
+			}
+		default:
+			if junit.FailureOutput != nil {
+				t.Errorf("expected %q to pass, got %v", junit.Name, junit.FailureOutput.Output)
+			}
+		}
+	}
+}
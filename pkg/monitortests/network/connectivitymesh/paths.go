@@ -0,0 +1,67 @@
+package connectivitymesh
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	meshPathLabel     = "network.openshift.io/mesh-path"
+	meshActorLabel    = "network.openshift.io/mesh-actor"
+	backendPrefix     = "mesh-"
+	stopConfigMapName = "stop-collecting"
+)
+
+// meshPath is one kind of connection the mesh checks from every node to every other node.
+type meshPath struct {
+	// name is the path, for instance pod-to-host.
+	name string
+	// hostNetwork is true when the pollers connect from the host network.
+	hostNetwork bool
+	// targetService selects the endpoints, one on every node, the pollers connect to.
+	targetService string
+
+	scheme             string
+	requestPath        string
+	expectedStatusCode int
+}
+
+var meshPaths = []meshPath{
+	{name: "pod-to-pod", targetService: "mesh-pod-network-service", scheme: "http"},
+	{name: "pod-to-host", targetService: "mesh-host-network-service", scheme: "https", requestPath: "/healthz", expectedStatusCode: 401},
+	{name: "host-to-pod", hostNetwork: true, targetService: "mesh-pod-network-service", scheme: "http"},
+	{name: "host-to-host", hostNetwork: true, targetService: "mesh-host-network-service", scheme: "https", requestPath: "/healthz", expectedStatusCode: 401},
+}
+
+// pollerDeployment returns the pollers of the path, one for every node, from the poller template.
+func pollerDeployment(path meshPath, numNodes int32, image, deploymentID string) *appsv1.Deployment {
+	deployment := pollerDeploymentTemplate.DeepCopy()
+	deployment.Name = fmt.Sprintf("mesh-%s-poller", path.name)
+	deployment.Spec.Replicas = &numNodes
+	deployment.Spec.Selector.MatchLabels[meshPathLabel] = path.name
+	deployment.Spec.Template.Labels[meshPathLabel] = path.name
+	deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector.MatchLabels[meshPathLabel] = path.name
+	deployment.Spec.Template.Spec.HostNetwork = path.hostNetwork
+
+	container := &deployment.Spec.Template.Spec.Containers[0]
+	container.Image = image
+	container.Command = append(container.Command,
+		fmt.Sprintf("--output-file=/var/log/persistent-logs/mesh-%s-$(DEPLOYMENT_ID).jsonl", path.name),
+		fmt.Sprintf("--disruption-backend-prefix=%s%s", backendPrefix, path.name),
+		fmt.Sprintf("--disruption-target-service-name=%s", path.targetService),
+		fmt.Sprintf("--request-scheme=%s", path.scheme),
+	)
+	if len(path.requestPath) > 0 {
+		container.Command = append(container.Command, fmt.Sprintf("--request-path=%s", path.requestPath))
+	}
+	if path.expectedStatusCode > 0 {
+		container.Command = append(container.Command, fmt.Sprintf("--expected-status-code=%d", path.expectedStatusCode))
+	}
+	for i, env := range container.Env {
+		if env.Name == "DEPLOYMENT_ID" {
+			container.Env[i].Value = deploymentID
+		}
+	}
+	return deployment
+}
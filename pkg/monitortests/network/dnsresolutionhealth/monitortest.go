@@ -0,0 +1,308 @@
+package dnsresolutionhealth
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace         *corev1.Namespace
+	proberRoleBinding *rbacv1.RoleBinding
+	proberDeployment  *appsv1.Deployment
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	proberRoleBinding = resourceread.ReadRoleBindingV1OrDie(yamlOrDie("prober-rolebinding.yaml"))
+	proberDeployment = resourceread.ReadDeploymentV1OrDie(yamlOrDie("prober-deployment.yaml"))
+}
+
+const (
+	stopConfigMapName  = "stop-collecting"
+	proberLogsTestName = "[sig-network] can collect in-cluster DNS prober pod logs"
+)
+
+type dnsResolutionHealth struct {
+	payloadImagePullSpec string
+	notSupportedReason   error
+
+	targets []target
+
+	kubeClient    kubernetes.Interface
+	namespaceName string
+}
+
+// NewDNSResolutionHealth resolves cluster service names and external names from a deployment with a pod on every
+// node, and fails when a name cannot be resolved on a node for longer than DNS outages are tolerated.
+func NewDNSResolutionHealth(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &dnsResolutionHealth{
+		payloadImagePullSpec: info.UpgradeTargetPayloadImagePullSpec,
+	}
+}
+
+func (w *dnsResolutionHealth) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{proberLogsTestName}
+	// the external targets are only resolved when the cluster has them.
+	for _, t := range []target{kubernetesServiceTarget, dnsServiceTarget, {name: externalAPIServerTargetName}, {name: externalIngressTargetName}} {
+		testNames = append(testNames, outageTestName(t))
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *dnsResolutionHealth) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: "platform MicroShift not supported",
+		}
+		return w.notSupportedReason
+	}
+
+	openshiftTestsImagePullSpec, err := disruptionpodnetwork.GetOpenshiftTestsImagePullSpec(ctx, adminRESTConfig, w.payloadImagePullSpec, nil)
+	if err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("unable to determine openshift-tests image: %v", err),
+		}
+		return w.notSupportedReason
+	}
+
+	w.targets, err = availableTargets(ctx, adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	if _, err := w.kubeClient.RbacV1().RoleBindings(w.namespaceName).Create(ctx, proberRoleBinding, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	// our pods tolerate masters, so create one for each node.
+	nodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	numNodes := int32(len(nodes.Items))
+
+	deployment := proberDeployment.DeepCopy()
+	deployment.Spec.Replicas = &numNodes
+	container := &deployment.Spec.Template.Spec.Containers[0]
+	container.Image = openshiftTestsImagePullSpec
+	for _, t := range w.targets {
+		container.Command = append(container.Command, fmt.Sprintf("--name=%s=%s", t.name, t.hostname))
+	}
+	for i, env := range container.Env {
+		if env.Name == "DEPLOYMENT_ID" {
+			container.Env[i].Value = uuid.New().String()
+		}
+	}
+	if _, err := w.kubeClient.AppsV1().Deployments(w.namespaceName).Create(ctx, deployment, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// availableTargets adds the external names of this cluster to the cluster service names.  The apiserver name is
+// skipped when clients reach it by address, and the ingress name when the cluster has no ingress domain.
+func availableTargets(ctx context.Context, adminRESTConfig *rest.Config) ([]target, error) {
+	ret := []target{kubernetesServiceTarget, dnsServiceTarget}
+
+	apiURL, err := url.Parse(adminRESTConfig.Host)
+	if err != nil {
+		return nil, err
+	}
+	if apiHost := apiURL.Hostname(); len(apiHost) > 0 && net.ParseIP(apiHost) == nil {
+		ret = append(ret, target{name: externalAPIServerTargetName, hostname: apiHost})
+	}
+
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	ingress, err := configClient.ConfigV1().Ingresses().Get(ctx, "cluster", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	case len(ingress.Spec.Domain) > 0:
+		// the ingress domain is a wildcard, any name under it resolves.
+		ret = append(ret, target{name: externalIngressTargetName, hostname: "dns-probe." + ingress.Spec.Domain})
+	}
+	return ret, nil
+}
+
+func (w *dnsResolutionHealth) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	// create the stop collecting configmap and wait for 30s for the probers to have stopped.  the 30s is just a guess
+	if _, err := w.kubeClient.CoreV1().ConfigMaps(w.namespaceName).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: stopConfigMapName},
+	}, metav1.CreateOptions{}); err != nil {
+		return nil, nil, err
+	}
+	select {
+	case <-time.After(30 * time.Second):
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	return w.collectProberIntervals(ctx)
+}
+
+// collectProberIntervals scrapes the intervals the probers logged.  Probers only log failures, so unlike the
+// availability pollers a prober without intervals is healthy.
+func (w *dnsResolutionHealth) collectProberIntervals(ctx context.Context) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	proberPods, err := w.kubeClient.CoreV1().Pods(w.namespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: "dns.openshift.io/probe-actor=prober",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	retIntervals := monitorapi.Intervals{}
+	errs := []error{}
+	logs := &strings.Builder{}
+	for _, proberPod := range proberPods.Items {
+		fmt.Fprintf(logs, "\n\nLogs for -n %v pod/%v\n", proberPod.Namespace, proberPod.Name)
+		logStream, err := w.kubeClient.CoreV1().Pods(w.namespaceName).GetLogs(proberPod.Name, &corev1.PodLogOptions{}).Stream(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		scanner := bufio.NewScanner(logStream)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			logs.Write(line)
+			logs.WriteString("\n")
+			if len(line) == 0 {
+				continue
+			}
+			// not all lines are json, ignore errors.
+			if currInterval, err := monitorserialization.IntervalFromJSON(line); err == nil {
+				retIntervals = append(retIntervals, *currInterval)
+			}
+		}
+		logStream.Close()
+	}
+
+	logJunit := &junitapi.JUnitTestCase{
+		Name:      proberLogsTestName,
+		SystemOut: logs.String(),
+	}
+	if len(proberPods.Items) == 0 {
+		logJunit.FailureOutput = &junitapi.FailureOutput{
+			Output: "no in-cluster DNS prober pods found",
+		}
+	}
+
+	return retIntervals, []*junitapi.JUnitTestCase{logJunit}, utilerrors.NewAggregate(errs)
+}
+
+func (w *dnsResolutionHealth) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *dnsResolutionHealth) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateOutages(w.targets, finalIntervals), nil
+}
+
+func (w *dnsResolutionHealth) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *dnsResolutionHealth) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (w *dnsResolutionHealth) Cleanup(ctx context.Context) error {
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		if err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted); err != nil {
+			return err
+		}
+
+		klog.Infof("Deleting namespace: %s took %.2f seconds", w.namespaceName, time.Now().Sub(startTime).Seconds())
+	}
+	return w.notSupportedReason
+}
@@ -0,0 +1,94 @@
+package dnsresolutionhealth
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// maxOutage is the longest a name may fail to resolve on a node.  Clients retry lookups for a few seconds, so a
+// longer outage fails requests rather than slowing them down.
+const maxOutage = 15 * time.Second
+
+// target is a name the probers resolve.  Cluster names are answered by the cluster DNS, external names are forwarded
+// to the upstream resolvers.
+type target struct {
+	name     string
+	hostname string
+}
+
+var (
+	kubernetesServiceTarget = target{name: "cluster-kubernetes-service", hostname: "kubernetes.default.svc.cluster.local"}
+	dnsServiceTarget        = target{name: "cluster-dns-service", hostname: "dns-default.openshift-dns.svc.cluster.local"}
+)
+
+// the external targets are named after what they resolve, their hostnames are those of the cluster.
+const (
+	externalAPIServerTargetName = "external-apiserver"
+	externalIngressTargetName   = "external-ingress"
+)
+
+func outageTestName(t target) string {
+	return fmt.Sprintf("[sig-network] DNS resolution of %s should not fail for long on any node", t.name)
+}
+
+var outageTemplate = junitfailure.MustParseTemplate("dns-resolution-outage",
+	`{{.Fields.hostname}} failed to resolve for up to {{.Threshold.Observed}}s on {{.Fields.nodes}} nodes, longer than the {{.Threshold.Limit}}s tolerated.
+{{.IntervalList}}`)
+
+// evaluateOutages returns one test per target.  Every node probes on its own, so each failure interval is the outage
+// one node saw, and the longest one decides.
+func evaluateOutages(targets []target, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, t := range targets {
+		ret = append(ret, evaluateTargetOutages(t, finalIntervals))
+	}
+	return ret
+}
+
+func evaluateTargetOutages(t target, finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	testName := outageTestName(t)
+
+	tooLong := monitorapi.Intervals{}
+	nodes := map[string]bool{}
+	var longest time.Duration
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceDNSProbe || interval.Message.Reason != monitorapi.DNSResolutionFailedReason {
+			continue
+		}
+		if interval.Locator.Keys[monitorapi.LocatorTargetKey] != t.name {
+			continue
+		}
+		duration := interval.To.Sub(interval.From)
+		if duration > longest {
+			longest = duration
+		}
+		if duration > maxOutage {
+			tooLong = append(tooLong, interval)
+			nodes[interval.Locator.Keys[monitorapi.LocatorNodeKey]] = true
+		}
+	}
+	if len(tooLong) == 0 {
+		return &junitapi.JUnitTestCase{Name: testName}
+	}
+	sort.Sort(tooLong)
+
+	junit := junitfailure.NewFailure(outageTemplate, "DNSResolutionOutage").
+		Threshold(junitapi.JUnitThreshold{
+			Name:     "outage",
+			Observed: longest.Seconds(),
+			Limit:    maxOutage.Seconds(),
+			Unit:     "seconds",
+		}).
+		Field("hostname", t.hostname).
+		Field("nodes", len(nodes)).
+		Intervals(tooLong...).
+		TestCase(testName)
+	// a warning until we know how often DNS fails this long today.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,52 @@
+package dnsresolutionhealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func failure(t target, node string, from, duration time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceDNSProbe, monitorapi.Error).
+		Locator(monitorapi.NewLocator().DNSProbe(t.name, t.hostname, node)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.DNSResolutionFailedReason).HumanMessage("failed to resolve")).
+		Build(start.Add(from), start.Add(from+duration))
+}
+
+func TestEvaluateOutages(t *testing.T) {
+	finalIntervals := monitorapi.Intervals{
+		// many short outages are tolerated.
+		failure(kubernetesServiceTarget, "worker-0", time.Minute, 5*time.Second),
+		failure(kubernetesServiceTarget, "worker-0", 2*time.Minute, 5*time.Second),
+		failure(kubernetesServiceTarget, "worker-1", 3*time.Minute, maxOutage),
+		failure(dnsServiceTarget, "worker-0", time.Minute, time.Minute),
+		failure(dnsServiceTarget, "worker-1", time.Minute, 20*time.Second),
+		failure(dnsServiceTarget, "worker-2", time.Minute, time.Second),
+	}
+
+	junits := evaluateOutages([]target{kubernetesServiceTarget, dnsServiceTarget}, finalIntervals)
+	if len(junits) != 2 {
+		t.Fatalf("expected one test per target, got %d", len(junits))
+	}
+	if junits[0].Name != outageTestName(kubernetesServiceTarget) || junits[0].FailureOutput != nil {
+		t.Errorf("expected %q to pass, got %v", junits[0].Name, junits[0].FailureOutput)
+	}
+
+	dnsService := junits[1]
+	if dnsService.FailureOutput == nil {
+		t.Fatalf("expected %q to fail", dnsService.Name)
+	}
+	if dnsService.Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", dnsService.Details.Severity)
+	}
+	if got := len(dnsService.Details.IntervalIDs); got != 2 {
+		t.Errorf("expected the outages of the two nodes over the limit, got %d", got)
+	}
+	if thresholds := dnsService.Details.Thresholds; len(thresholds) != 1 || thresholds[0].Observed != 60 {
+		t.Errorf("expected the longest outage to be observed, got %v", thresholds)
+	}
+}
@@ -0,0 +1,104 @@
+package ingressreachability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/test/e2e/framework/service"
+	k8simage "k8s.io/kubernetes/test/utils/image"
+
+	"github.com/openshift/origin/test/extended/util/image"
+)
+
+const (
+	loadBalancerServiceName = "ingress-reachability"
+	loadBalancerPath        = "/echo?msg=hello"
+)
+
+// loadBalancerPlatforms program LoadBalancer services for clients outside the cluster.  Other platforms either have
+// no cloud to program or need the service to be checked from inside the cluster before it is reachable.
+var loadBalancerPlatforms = map[configv1.PlatformType]bool{
+	configv1.AWSPlatformType:   true,
+	configv1.AzurePlatformType: true,
+	configv1.GCPPlatformType:   true,
+}
+
+// createLoadBalancer creates a LoadBalancer service in front of pods on two nodes and returns its base URL once it
+// answers.  The pods go unready before they stop, so updates that move them should not be seen from outside.
+func createLoadBalancer(ctx context.Context, kubeClient kubernetes.Interface, namespaceName string) (string, error) {
+	jig := service.NewTestJig(kubeClient, namespaceName, loadBalancerServiceName)
+
+	tcpService, err := jig.CreateTCPService(ctx, func(s *corev1.Service) {
+		s.Spec.Type = corev1.ServiceTypeLoadBalancer
+		s.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+		if s.Annotations == nil {
+			s.Annotations = map[string]string{}
+		}
+		// match the GCP health check interval, so all platforms notice unready backends at a similar pace.
+		s.Annotations["service.beta.kubernetes.io/aws-load-balancer-healthcheck-interval"] = "8"
+		s.Annotations["service.beta.kubernetes.io/aws-load-balancer-healthcheck-unhealthy-threshold"] = "3"
+		s.Annotations["service.beta.kubernetes.io/aws-load-balancer-healthcheck-healthy-threshold"] = "2"
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating load balancer service: %w", err)
+	}
+	tcpService, err = jig.WaitForLoadBalancer(ctx, service.GetServiceLoadBalancerCreationTimeout(ctx, kubeClient))
+	if err != nil {
+		return "", fmt.Errorf("error waiting for load balancer: %w", err)
+	}
+
+	rc, err := jig.Run(ctx, func(rc *corev1.ReplicationController) {
+		// longer than the slowest health checks above, so a new pod is in the load balancer before the old one goes.
+		rc.Spec.MinReadySeconds = 33
+		rc.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Path = "/readyz"
+		rc.Spec.Template.Spec.Containers[0].Args = append(rc.Spec.Template.Spec.Containers[0].Args, "--delay-shutdown=80")
+		originalAgnhost := k8simage.GetOriginalImageConfigs()[k8simage.Agnhost]
+		rc.Spec.Template.Spec.Containers[0].Image = image.LocationFor(originalAgnhost.GetE2EImage())
+		gracePeriod := int64(90)
+		rc.Spec.Template.Spec.TerminationGracePeriodSeconds = &gracePeriod
+		jig.AddRCAntiAffinity(rc)
+	})
+	if err != nil {
+		return "", fmt.Errorf("error waiting for load balancer pods: %w", err)
+	}
+	if _, err := jig.CreatePDB(ctx, rc); err != nil {
+		return "", fmt.Errorf("error creating PDB: %w", err)
+	}
+
+	ingressIP := service.GetIngressPoint(&tcpService.Status.LoadBalancer.Ingress[0])
+	baseURL := fmt.Sprintf("http://%s", net.JoinHostPort(ingressIP, strconv.Itoa(int(tcpService.Spec.Ports[0].Port))))
+	if err := waitForReachable(ctx, baseURL+loadBalancerPath); err != nil {
+		return "", err
+	}
+	return baseURL, nil
+}
+
+// waitForReachable waits for the load balancer to answer, so the time its DNS and health checks take to settle is
+// not counted as disruption.
+func waitForReachable(ctx context.Context, url string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 10*time.Minute, true, func(ctx context.Context) (bool, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			klog.Infof("load balancer %s is not reachable yet: %v", url, err)
+			return false, nil
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode == http.StatusOK, nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not reach %v: %w", url, err)
+	}
+	return nil
+}
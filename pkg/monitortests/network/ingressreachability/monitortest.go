@@ -0,0 +1,263 @@
+package ingressreachability
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"sync"
+	"time"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+var (
+	//go:embed namespace.yaml
+	namespaceYaml []byte
+
+	namespace *corev1.Namespace
+)
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(namespaceYaml)
+}
+
+const (
+	canaryRouteNamespace = "openshift-ingress-canary"
+	canaryRouteName      = "canary"
+)
+
+type ingressReachability struct {
+	notSupportedReason error
+	platform           string
+
+	targets  []target
+	samplers []*backenddisruption.BackendSampler
+
+	kubeClient    kubernetes.Interface
+	namespaceName string
+
+	beginning, end time.Time
+}
+
+// NewIngressReachability probes the router canary route and a LoadBalancer service from outside the cluster, and
+// holds each to the availability SLO of the platform.
+func NewIngressReachability() monitortestframework.MonitorTest {
+	return &ingressReachability{}
+}
+
+func (w *ingressReachability) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	// a target is only checked when the cluster has it.
+	for _, t := range []target{canaryTarget, loadBalancerTarget} {
+		for _, connectionType := range connectionTypes {
+			testNames = append(testNames, t.sloTestName(connectionType))
+		}
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *ingressReachability) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		// the default SLOs are used.
+		fmt.Printf("unable to determine the platform for ingress SLOs: %v\n", err)
+	} else {
+		w.platform = jobType.Platform
+	}
+
+	if err := w.startCanarySamplers(ctx, adminRESTConfig, recorder); err != nil {
+		return err
+	}
+	if err := w.startLoadBalancerSamplers(ctx, adminRESTConfig, recorder); err != nil {
+		return err
+	}
+	if len(w.targets) == 0 {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the cluster has neither the ingress canary nor load balancers"}
+		return w.notSupportedReason
+	}
+	return nil
+}
+
+// startCanarySamplers probes the canary route, unless ingress is disabled.
+func (w *ingressReachability) startCanarySamplers(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	routeClient, err := routeclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	_, err = routeClient.RouteV1().Routes(canaryRouteNamespace).Get(ctx, canaryRouteName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, connectionType := range connectionTypes {
+		sampler := backenddisruption.NewRouteBackend(adminRESTConfig, canaryRouteNamespace, canaryRouteName, canaryTarget.backendPrefix(), "/", connectionType).
+			WithExpectedBody("Healthcheck requested")
+		if err := w.startSampler(ctx, sampler, recorder); err != nil {
+			return err
+		}
+	}
+	w.targets = append(w.targets, canaryTarget)
+	return nil
+}
+
+// startLoadBalancerSamplers creates a LoadBalancer service and probes it, on platforms that program them.
+func (w *ingressReachability) startLoadBalancerSamplers(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	infra, err := configClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if infra.Status.PlatformStatus == nil || !loadBalancerPlatforms[infra.Status.PlatformStatus.Type] {
+		return nil
+	}
+	nodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	// the pods behind the load balancer need two nodes to move between.
+	if len(nodes.Items) < 2 {
+		return nil
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	baseURL, err := createLoadBalancer(ctx, w.kubeClient, w.namespaceName)
+	if err != nil {
+		return err
+	}
+	for _, connectionType := range connectionTypes {
+		sampler := backenddisruption.NewSimpleBackendFromOpenshiftTests(baseURL, loadBalancerTarget.backendName(connectionType), loadBalancerPath, connectionType).
+			WithExpectedBody("hello")
+		if err := w.startSampler(ctx, sampler, recorder); err != nil {
+			return err
+		}
+	}
+	w.targets = append(w.targets, loadBalancerTarget)
+	return nil
+}
+
+func (w *ingressReachability) startSampler(ctx context.Context, sampler *backenddisruption.BackendSampler, recorder monitorapi.RecorderWriter) error {
+	sampler = sampler.WithUserAgent(fmt.Sprintf("openshift-external-backend-sampler-%s", sampler.GetDisruptionBackendName()))
+	if err := sampler.StartEndpointMonitoring(ctx, recorder, nil); err != nil {
+		return err
+	}
+	w.samplers = append(w.samplers, sampler)
+	return nil
+}
+
+func (w *ingressReachability) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	w.beginning, w.end = beginning, end
+
+	// the samplers record directly, they only have to drain.  Stop in parallel.
+	wg := sync.WaitGroup{}
+	for i := range w.samplers {
+		wg.Add(1)
+		go func(sampler *backenddisruption.BackendSampler) {
+			defer wg.Done()
+			sampler.Stop()
+		}(w.samplers[i])
+	}
+	wg.Wait()
+	return nil, nil, nil
+}
+
+func (w *ingressReachability) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return unreachableIntervals(w.targets, startingIntervals), nil
+}
+
+func (w *ingressReachability) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateSLO(w.platform, w.targets, finalIntervals, w.beginning, w.end), nil
+}
+
+func (w *ingressReachability) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *ingressReachability) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (w *ingressReachability) Cleanup(ctx context.Context) error {
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		if err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted); err != nil {
+			return err
+		}
+
+		klog.Infof("Deleting namespace: %s took %.2f seconds", w.namespaceName, time.Now().Sub(startTime).Seconds())
+	}
+	return w.notSupportedReason
+}
@@ -0,0 +1,62 @@
+package ingressreachability
+
+import (
+	"fmt"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// target is an endpoint reached from outside the cluster, and the fraction of the run it must be available for.
+type target struct {
+	name string
+	// defaultSLO applies on platforms without their own.
+	defaultSLO float64
+	// sloByPlatform allows for load balancers whose health checks take longer to notice a backend going away.
+	sloByPlatform map[string]float64
+}
+
+var (
+	// canaryTarget is the route the ingress operator serves from every router, so it is unavailable only when the
+	// routers or the load balancer in front of them are.
+	canaryTarget = target{
+		name:       "canary",
+		defaultSLO: 0.999,
+		sloByPlatform: map[string]float64{
+			"azure": 0.998,
+			"gcp":   0.998,
+		},
+	}
+	// loadBalancerTarget is a LoadBalancer service the test creates, reaching pods on two nodes.
+	loadBalancerTarget = target{
+		name:       "load-balancer",
+		defaultSLO: 0.998,
+		sloByPlatform: map[string]float64{
+			// GCP needs three failed health checks 8s apart to take a backend out.
+			"gcp":   0.995,
+			"azure": 0.997,
+		},
+	}
+)
+
+var connectionTypes = []monitorapi.BackendConnectionType{monitorapi.NewConnectionType, monitorapi.ReusedConnectionType}
+
+func (t target) sloFor(platform string) float64 {
+	if slo, ok := t.sloByPlatform[platform]; ok {
+		return slo
+	}
+	return t.defaultSLO
+}
+
+// backendPrefix is the disruption backend a target is reported under, the samplers append the connection type.  It
+// is distinct from the API disruption backends so ingress disruption is never counted against them.
+func (t target) backendPrefix() string {
+	return fmt.Sprintf("ingress-reachability-%s", t.name)
+}
+
+func (t target) backendName(connectionType monitorapi.BackendConnectionType) string {
+	return fmt.Sprintf("%s-%v-connections", t.backendPrefix(), connectionType)
+}
+
+func (t target) sloTestName(connectionType monitorapi.BackendConnectionType) string {
+	return fmt.Sprintf("[sig-network-edge] disruption/ingress-reachability-%s connection/%s should meet the platform availability SLO", t.name, connectionType)
+}
@@ -0,0 +1,127 @@
+package ingressreachability
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// mergeGap is how far apart failures of a target may be and still be one outage.  The new and reused connection
+// samplers fail at slightly different times during the same outage.
+const mergeGap = time.Second
+
+// unreachableIntervals merges the failures of both samplers of every target into one interval per outage, so ingress
+// outages have their own row, apart from the API disruption backends.
+func unreachableIntervals(targets []target, startingIntervals monitorapi.Intervals) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, t := range targets {
+		backends := map[string]bool{}
+		for _, connectionType := range connectionTypes {
+			backends[t.backendName(connectionType)] = true
+		}
+		failures := startingIntervals.Filter(func(interval monitorapi.Interval) bool {
+			return monitorapi.IsDisruptionEvent(interval) && interval.Level == monitorapi.Error &&
+				backends[interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey]]
+		})
+		if len(failures) == 0 {
+			continue
+		}
+		sort.Sort(failures)
+		from, to := failures[0].From, failures[0].To
+		for _, failure := range failures[1:] {
+			if failure.From.After(to.Add(mergeGap)) {
+				ret = append(ret, unreachableInterval(t, from, to))
+				from = failure.From
+			}
+			if failure.To.After(to) {
+				to = failure.To
+			}
+		}
+		ret = append(ret, unreachableInterval(t, from, to))
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func unreachableInterval(t target, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceIngressReachability, monitorapi.Error).
+		Locator(monitorapi.NewLocator().IngressTarget(t.name)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.IngressUnreachableReason).
+			Constructed(monitorapi.ConstructionOwnerIngress).
+			HumanMessagef("%s was unreachable from outside the cluster", t.name)).
+		Display().
+		Build(from, to)
+}
+
+var sloTemplate = junitfailure.MustParseTemplate("ingress-reachability-slo",
+	`{{.Fields.target}} was available for {{printf "%.3f" .Threshold.Observed}}% of the run over {{.Fields.connection}} connections, below the {{printf "%.3f" .Threshold.Limit}}% SLO on {{.Fields.platform}}.  It was unreachable from outside the cluster for {{.Fields.disruption}} of {{.Fields.duration}}.
+{{.IntervalList}}`)
+
+// evaluateSLO returns one test per target and connection type.  A target without any samples is skipped, the sampler
+// logs explain why.
+func evaluateSLO(platform string, targets []target, finalIntervals monitorapi.Intervals, beginning, end time.Time) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, t := range targets {
+		for _, connectionType := range connectionTypes {
+			ret = append(ret, evaluateBackendSLO(platform, t, connectionType, finalIntervals, end.Sub(beginning)))
+		}
+	}
+	return ret
+}
+
+func evaluateBackendSLO(platform string, t target, connectionType monitorapi.BackendConnectionType, finalIntervals monitorapi.Intervals, runDuration time.Duration) *junitapi.JUnitTestCase {
+	testName := t.sloTestName(connectionType)
+	backend := t.backendName(connectionType)
+	samples := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceDisruption && interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey] == backend
+	})
+	if len(samples) == 0 || runDuration <= 0 {
+		return &junitapi.JUnitTestCase{
+			Name:        testName,
+			SkipMessage: &junitapi.SkipMessage{Message: fmt.Sprintf("no samples were recorded for %s", backend)},
+		}
+	}
+
+	disrupted := samples.Filter(monitorapi.IsErrorEvent)
+	disruption := disrupted.Duration(1 * time.Second)
+	if disruption > runDuration {
+		disruption = runDuration
+	}
+
+	slo := t.sloFor(platform)
+	observed := 100 * (1 - disruption.Seconds()/runDuration.Seconds())
+	threshold := junitapi.JUnitThreshold{
+		Name:     "availability",
+		Limit:    100 * slo,
+		Observed: observed,
+		Unit:     "percent",
+		Exceeded: observed < 100*slo,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:      testName,
+			SystemOut: fmt.Sprintf("unreachable for %s of %s", disruption.Round(time.Second), runDuration.Round(time.Second)),
+			Details:   &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+
+	if len(platform) == 0 {
+		platform = "this platform"
+	}
+	junit := junitfailure.NewFailure(sloTemplate, "IngressAvailabilitySLOMissed").
+		Threshold(threshold).
+		Field("target", t.name).
+		Field("connection", string(connectionType)).
+		Field("platform", platform).
+		Field("disruption", disruption.Round(time.Second).String()).
+		Field("duration", runDuration.Round(time.Second).String()).
+		Intervals(disrupted...).
+		TestCase(testName)
+	// a warning until we know the SLOs hold on every platform and upgrade path.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,85 @@
+package ingressreachability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/backenddisruption"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func sample(t target, connectionType monitorapi.BackendConnectionType, level monitorapi.IntervalLevel, from, to time.Duration) monitorapi.Interval {
+	reason := monitorapi.DisruptionEndedEventReason
+	if level == monitorapi.Error {
+		reason = monitorapi.DisruptionBeganEventReason
+	}
+	return monitorapi.NewInterval(monitorapi.SourceDisruption, level).
+		Locator(monitorapi.NewLocator().LocateDisruptionCheck(t.backendName(connectionType), backenddisruption.OpenshiftTestsSource, connectionType)).
+		Message(monitorapi.NewMessage().Reason(reason)).
+		Build(start.Add(from), start.Add(to))
+}
+
+func TestUnreachableIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		sample(canaryTarget, monitorapi.NewConnectionType, monitorapi.Error, 10*time.Second, 20*time.Second),
+		sample(canaryTarget, monitorapi.ReusedConnectionType, monitorapi.Error, 12*time.Second, 25*time.Second),
+		sample(canaryTarget, monitorapi.NewConnectionType, monitorapi.Info, 25*time.Second, time.Hour),
+		sample(loadBalancerTarget, monitorapi.NewConnectionType, monitorapi.Error, 30*time.Minute, 31*time.Minute),
+	}
+
+	unreachable := unreachableIntervals([]target{canaryTarget, loadBalancerTarget}, intervals)
+	if len(unreachable) != 2 {
+		t.Fatalf("expected one outage per target, got %v", unreachable)
+	}
+	canary := unreachable[0]
+	if canary.Locator.Keys[monitorapi.LocatorIngressTargetKey] != canaryTarget.name {
+		t.Fatalf("expected the canary outage first, got %v", canary)
+	}
+	if !canary.From.Equal(start.Add(10*time.Second)) || !canary.To.Equal(start.Add(25*time.Second)) {
+		t.Errorf("expected the overlapping failures to merge, got %v to %v", canary.From, canary.To)
+	}
+	if canary.Source != monitorapi.SourceIngressReachability {
+		t.Errorf("expected ingress outages to have their own source, got %v", canary.Source)
+	}
+}
+
+func TestEvaluateSLO(t *testing.T) {
+	// 9s of an hour is 99.75% available, within the GCP load balancer SLO and below the default one.
+	intervals := monitorapi.Intervals{
+		sample(loadBalancerTarget, monitorapi.NewConnectionType, monitorapi.Error, 0, 9*time.Second),
+		sample(loadBalancerTarget, monitorapi.NewConnectionType, monitorapi.Info, 9*time.Second, time.Hour),
+		sample(loadBalancerTarget, monitorapi.ReusedConnectionType, monitorapi.Info, 0, time.Hour),
+	}
+	newConnectionTestName := loadBalancerTarget.sloTestName(monitorapi.NewConnectionType)
+
+	tests := []struct {
+		platform string
+		fails    bool
+	}{
+		{platform: "gcp"},
+		{platform: "aws", fails: true},
+	}
+	for _, test := range tests {
+		t.Run(test.platform, func(t *testing.T) {
+			junits := evaluateSLO(test.platform, []target{loadBalancerTarget}, intervals, start, start.Add(time.Hour))
+			if len(junits) != len(connectionTypes) {
+				t.Fatalf("expected a test per connection type, got %v", junits)
+			}
+			for _, junit := range junits {
+				failed := junit.FailureOutput != nil
+				if junit.Name == newConnectionTestName && failed != test.fails {
+					t.Errorf("expected failure=%v for new connections, got %v", test.fails, junit.FailureOutput)
+				}
+				if junit.Name != newConnectionTestName && failed {
+					t.Errorf("expected %q to pass, got %v", junit.Name, junit.FailureOutput.Output)
+				}
+				if failed && junit.Details.Severity != junitapi.SeverityWarn {
+					t.Errorf("expected a warning, got %q", junit.Details.Severity)
+				}
+			}
+		})
+	}
+}
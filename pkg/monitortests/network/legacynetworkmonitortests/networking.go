@@ -72,18 +72,12 @@ func testPodSandboxCreation(events monitorapi.Intervals, clientConfig *rest.Conf
 
 	failures := []string{}
 	flakes := []string{}
-	networkOperatorProgressing := events.Filter(func(ev monitorapi.Interval) bool {
-		annotations := ev.Message.Annotations
-		if annotations[monitorapi.AnnotationCondition] != string(configv1.OperatorProgressing) {
-			return false
-		}
-		isNetwork := ev.Locator.Keys[monitorapi.LocatorClusterOperatorKey] == "network"
-		isMCO := ev.Locator.Keys[monitorapi.LocatorClusterOperatorKey] == "machine-config"
-		if isNetwork || isMCO {
-			return true
-		}
-		return false
-	})
+	networkOperatorProgressing := events.Filter(monitorapi.And(
+		func(ev monitorapi.Interval) bool {
+			return ev.Message.Annotations[monitorapi.AnnotationCondition] == string(configv1.OperatorProgressing)
+		},
+		monitorapi.LocatorKeyIn(monitorapi.LocatorClusterOperatorKey, "network", "machine-config"),
+	))
 	eventsForPods := getEventsByPodName(events)
 
 	var platform configv1.PlatformType
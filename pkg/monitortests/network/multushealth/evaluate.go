@@ -0,0 +1,103 @@
+package multushealth
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// maxAttachFailureDuration is how long the ADDs of a pod's secondary network may keep failing.  A pod created before
+// its network attachment definition fails until the definition exists, which should take seconds.
+const maxAttachFailureDuration = 2 * time.Minute
+
+var (
+	attachTestName = fmt.Sprintf("[sig-network][Feature:Multus] pods should not fail to attach secondary networks for longer than %v", maxAttachFailureDuration)
+	detachTestName = "[sig-network][Feature:Multus] pods should not fail to detach secondary networks"
+)
+
+var attachTemplate = junitfailure.MustParseTemplate("multus-attach",
+	`the secondary networks of {{len .Intervals}} pods failed to attach for longer than {{.Fields.limit}}.  The pods could not start until multus could add every network they requested.
+{{.IntervalList}}`)
+
+var detachTemplate = junitfailure.MustParseTemplate("multus-detach",
+	`the secondary networks of {{len .Intervals}} pods failed to detach.  A failed DEL can leak the addresses and interfaces of the attachment on the node.
+{{.IntervalList}}`)
+
+// evaluateAttach fails when the ADDs of a secondary network kept failing for longer than maxAttachFailureDuration.
+func evaluateAttach(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	failed := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceMultus &&
+			interval.Message.Reason == monitorapi.MultusAttachFailedReason &&
+			isSecondary(interval)
+	})
+	longest := time.Duration(0)
+	tooLong := monitorapi.Intervals{}
+	for _, interval := range failed {
+		duration := interval.To.Sub(interval.From)
+		if duration > longest {
+			longest = duration
+		}
+		if duration > maxAttachFailureDuration {
+			tooLong = append(tooLong, interval)
+		}
+	}
+	threshold := junitapi.JUnitThreshold{
+		Name:     "multus-attach-failure-seconds",
+		Limit:    maxAttachFailureDuration.Seconds(),
+		Observed: longest.Seconds(),
+		Unit:     "seconds",
+		Exceeded: len(tooLong) > 0,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    attachTestName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+	junit := junitfailure.NewFailure(attachTemplate, "MultusAttachFailed").
+		Threshold(threshold).
+		Field("limit", maxAttachFailureDuration.String()).
+		Intervals(tooLong...).
+		TestCase(attachTestName)
+	// a warning until we know how often e2e tests deliberately request networks that do not exist.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+// evaluateDetach fails when any DEL of a secondary network failed.
+func evaluateDetach(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	failed := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceMultus &&
+			interval.Message.Reason == monitorapi.MultusDetachFailedReason &&
+			isSecondary(interval)
+	})
+	observed := 0
+	for _, interval := range failed {
+		count, _ := strconv.Atoi(interval.Message.Annotations[monitorapi.AnnotationCount])
+		observed += count
+	}
+	threshold := junitapi.JUnitThreshold{
+		Name:     "multus-detach-failures",
+		Limit:    0,
+		Observed: float64(observed),
+		Unit:     "failures",
+		Exceeded: len(failed) > 0,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    detachTestName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+	junit := junitfailure.NewFailure(detachTemplate, "MultusDetachFailed").
+		Threshold(threshold).
+		Intervals(failed...).
+		TestCase(detachTestName)
+	// a warning until we know how often DELs fail for sandboxes that are already gone.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,142 @@
+package multushealth
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
+)
+
+// cniFailureRegex matches the line CRI-O logs when a CNI ADD or DEL of a pod sandbox fails.  The pod is namespace_name.
+var cniFailureRegex = regexp.MustCompile(`(?i)error (adding|deleting) pod ([^_\s]+)_(\S+) (?:to|from) CNI network \\?"([^"\\]+)\\?"`)
+
+// attachmentRegexes match the network multus was delegating to when the ADD or DEL failed.  Multus reports the name of
+// the network attachment on ADD, and only the plugin it invoked on DEL.
+var attachmentRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`error adding container to network \\?"([^"\\]+)\\?"`),
+	regexp.MustCompile(`error invoking (?:Conflist|Delegate)Del - \\?"([^"\\]+)\\?"`),
+}
+
+// primaryNetworks are the names multus gives the cluster default network.  Failures to attach them are failures of the
+// pod network, which other tests cover.
+var primaryNetworks = map[string]bool{
+	"ovn-kubernetes": true,
+	"openshift-sdn":  true,
+}
+
+const (
+	operationAdd    = "add"
+	operationDelete = "delete"
+)
+
+// mergeWindow is how close together the failures of an attachment must be to share an interval.  The kubelet retries
+// a failed sandbox with a backoff, and every retry fails the same way until the cause is gone.
+const mergeWindow = 2 * time.Minute
+
+type failures struct {
+	from, to   time.Time
+	count      int
+	operation  string
+	namespace  string
+	pod        string
+	attachment string
+	message    string
+}
+
+// scanCRIOJournal returns an interval for every run of failed multus ADDs or DELs of a pod's network on a node.
+func scanCRIOJournal(nodeName string, journal []byte, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	open := map[string]*failures{}
+	flush := func(key string) {
+		curr := open[key]
+		delete(open, key)
+		reason, level := monitorapi.MultusAttachFailedReason, monitorapi.Error
+		if curr.operation == operationDelete {
+			reason, level = monitorapi.MultusDetachFailedReason, monitorapi.Warning
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceMultus, level).
+				Locator(monitorapi.NewLocator().PodFromNames(curr.namespace, curr.pod, "")).
+				Message(monitorapi.NewMessage().Reason(reason).
+					WithAnnotation(monitorapi.AnnotationNode, nodeName).
+					WithAnnotation(monitorapi.AnnotationOperation, curr.operation).
+					WithAnnotation(monitorapi.AnnotationNetwork, curr.attachment).
+					WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(curr.count)).
+					HumanMessagef("multus %s of network %q failed %d times: %s", curr.operation, curr.attachment, curr.count, curr.message)).
+				Display().
+				Build(curr.from, curr.to.Add(time.Second)),
+		)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(journal))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		m := cniFailureRegex.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		if !strings.Contains(line[m[8]:m[9]], "multus") {
+			continue
+		}
+		logTime := nodeaccess.SystemdJournalLogTime(line)
+		if logTime.Before(beginning) || logTime.After(end) {
+			continue
+		}
+
+		curr := &failures{
+			from:      logTime,
+			to:        logTime,
+			count:     1,
+			operation: operationAdd,
+			namespace: line[m[4]:m[5]],
+			pod:       line[m[6]:m[7]],
+			message:   truncate(strings.TrimRight(line[m[0]:], `"`)),
+		}
+		if strings.EqualFold(line[m[2]:m[3]], "deleting") {
+			curr.operation = operationDelete
+		}
+		for _, attachmentRegex := range attachmentRegexes {
+			if sub := attachmentRegex.FindStringSubmatch(line); sub != nil {
+				curr.attachment = sub[1]
+				break
+			}
+		}
+
+		key := curr.operation + "/" + curr.namespace + "/" + curr.pod + "/" + curr.attachment
+		if prev, ok := open[key]; ok {
+			if logTime.Sub(prev.to) <= mergeWindow {
+				prev.to = logTime
+				prev.count++
+				continue
+			}
+			flush(key)
+		}
+		open[key] = curr
+	}
+	for key := range open {
+		flush(key)
+	}
+	return ret
+}
+
+// maxMessageLength bounds the CNI error kept on an interval.  The errors embed the whole delegate configuration.
+const maxMessageLength = 512
+
+func truncate(message string) string {
+	if len(message) <= maxMessageLength {
+		return message
+	}
+	return message[:maxMessageLength] + "..."
+}
+
+// isSecondary is true when the failure was not of the cluster default network.  A failure multus did not name a
+// network for is counted, since it may have been of any of them.
+func isSecondary(interval monitorapi.Interval) bool {
+	return !primaryNetworks[interval.Message.Annotations[monitorapi.AnnotationNetwork]]
+}
@@ -0,0 +1,119 @@
+package multushealth
+
+import (
+	"testing"
+	"time"
+
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestScanCRIOJournal(t *testing.T) {
+	journal := []byte(`Jan 01 00:01:00.000000 worker-0 crio[1]: time="2024-01-01T00:01:00Z" level=error msg="Error adding pod e2e-test_client to CNI network \"multus-cni-network\": plugin type=\"multus-shim\" name=\"multus-cni-network\" failed (add): [e2e-test/client/uid:macvlan]: error adding container to network \"macvlan\": failed to find master"
+Jan 01 00:02:00.000000 worker-0 crio[1]: time="2024-01-01T00:02:00Z" level=error msg="Error adding pod e2e-test_client to CNI network \"multus-cni-network\": plugin type=\"multus-shim\" name=\"multus-cni-network\" failed (add): [e2e-test/client/uid:macvlan]: error adding container to network \"macvlan\": failed to find master"
+Jan 01 00:03:30.000000 worker-0 crio[1]: time="2024-01-01T00:03:30Z" level=error msg="Error adding pod e2e-test_client to CNI network \"multus-cni-network\": plugin type=\"multus-shim\" name=\"multus-cni-network\" failed (add): [e2e-test/client/uid:macvlan]: error adding container to network \"macvlan\": failed to find master"
+Jan 01 00:04:00.000000 worker-0 crio[1]: time="2024-01-01T00:04:00Z" level=error msg="Error adding pod openshift-dns_dns-default-abcde to CNI network \"multus-cni-network\": plugin type=\"multus-shim\" name=\"multus-cni-network\" failed (add): [openshift-dns/dns-default-abcde/uid:ovn-kubernetes]: error adding container to network \"ovn-kubernetes\": timed out"
+Jan 01 00:10:00.000000 worker-0 crio[1]: time="2024-01-01T00:10:00Z" level=info msg="Error deleting pod e2e-test_client from CNI network \"multus-cni-network\": plugin type=\"multus-shim\" name=\"multus-cni-network\" failed (delete): DelegateDel: error invoking DelegateDel - \"whereabouts\": lease lost"
+Jan 01 00:11:00.000000 worker-0 crio[1]: time="2024-01-01T00:11:00Z" level=error msg="Error adding pod e2e-test_other to CNI network \"other-network\": failed"
+Jan 01 05:00:00.000000 worker-0 crio[1]: time="2024-01-01T05:00:00Z" level=error msg="Error adding pod e2e-test_late to CNI network \"multus-cni-network\": failed"
+`)
+	beginning := time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	intervals := scanCRIOJournal("worker-0", journal, beginning, beginning.Add(time.Hour))
+	if len(intervals) != 3 {
+		t.Fatalf("expected a failure for each attachment, got %v", intervals)
+	}
+
+	attached, detached, primary := 0, 0, 0
+	for _, interval := range intervals {
+		if interval.Message.Annotations[monitorapi.AnnotationNode] != "worker-0" {
+			t.Errorf("expected the node on %v", interval)
+		}
+		switch {
+		case !isSecondary(interval):
+			primary++
+		case interval.Message.Reason == monitorapi.MultusAttachFailedReason:
+			attached++
+			// the retries are one run of failures.
+			if count := interval.Message.Annotations[monitorapi.AnnotationCount]; count != "3" {
+				t.Errorf("expected three failures, got %v", count)
+			}
+			if network := interval.Message.Annotations[monitorapi.AnnotationNetwork]; network != "macvlan" {
+				t.Errorf("expected the macvlan network, got %v", network)
+			}
+		case interval.Message.Reason == monitorapi.MultusDetachFailedReason:
+			detached++
+			if network := interval.Message.Annotations[monitorapi.AnnotationNetwork]; network != "whereabouts" {
+				t.Errorf("expected the whereabouts plugin, got %v", network)
+			}
+		}
+	}
+	if attached != 1 || detached != 1 || primary != 1 {
+		t.Errorf("expected one failure of each kind, got %d attach, %d detach, %d primary", attached, detached, primary)
+	}
+
+	// the ADDs failed for 2m30s.
+	if junit := evaluateAttach(intervals); junit.FailureOutput == nil {
+		t.Errorf("expected the attachment to fail, got %v", junit)
+	}
+	if junit := evaluateDetach(intervals); junit.FailureOutput == nil || junit.Details.Thresholds[0].Observed != 1 {
+		t.Errorf("expected the detachment to fail once, got %v", junit)
+	}
+	if junit := evaluateAttach(nil); junit.FailureOutput != nil {
+		t.Errorf("expected no failure without intervals, got %v", junit)
+	}
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	definitions := []netattdefv1.NetworkAttachmentDefinition{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "e2e-test", Name: "macvlan"},
+			Spec:       netattdefv1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion":"0.4.0","type":"macvlan"}`},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bridge"},
+			Spec:       netattdefv1.NetworkAttachmentDefinitionSpec{Config: `{"cniVersion":"0.4.0","plugins":[{"type":"bridge"},{"type":"tuning"}]}`},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unused"},
+			Spec:       netattdefv1.NetworkAttachmentDefinitionSpec{Config: `{"type":"ipvlan"}`},
+		},
+	}
+	pod := func(name, networks string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "e2e-test",
+			Name:        name,
+			Annotations: map[string]string{networksAnnotation: networks},
+		}}
+	}
+	pods := monitorapi.InstanceMap{
+		{Namespace: "e2e-test", Name: "client"}: pod("client", "macvlan@net1, default/bridge"),
+		{Namespace: "e2e-test", Name: "server"}: pod("server", `[{"name":"macvlan"},{"name":"gone","namespace":"other"}]`),
+		{Namespace: "e2e-test", Name: "plain"}:  pod("plain", ""),
+	}
+	failures := monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceMultus, monitorapi.Error).
+			Locator(monitorapi.NewLocator().PodFromNames("e2e-test", "client", "")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.MultusAttachFailedReason).
+				WithAnnotation(monitorapi.AnnotationNetwork, "macvlan")).
+			Build(time.Time{}, time.Time{}),
+	}
+
+	usage := summarizeUsage(definitions, pods, failures)
+	expected := []attachmentUsage{
+		{Namespace: "default", Name: "bridge", Type: "bridge", Pods: 1, AttachFailures: 1},
+		{Namespace: "default", Name: "unused", Type: "ipvlan"},
+		{Namespace: "e2e-test", Name: "macvlan", Type: "macvlan", Pods: 2, AttachFailures: 1},
+		{Namespace: "other", Name: "gone", Pods: 1},
+	}
+	if len(usage) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, usage)
+	}
+	for i := range expected {
+		if usage[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], usage[i])
+		}
+	}
+}
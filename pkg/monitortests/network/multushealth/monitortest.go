@@ -0,0 +1,167 @@
+package multushealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	nadclient "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type multusHealth struct {
+	notSupportedReason error
+
+	kubeClient kubernetes.Interface
+	nadClient  nadclient.Interface
+
+	definitions []netattdefv1.NetworkAttachmentDefinition
+	usage       []attachmentUsage
+}
+
+// NewMultusHealth finds the multus ADDs and DELs that failed in the CRI-O journal of every node, and records which
+// network attachment definitions the pods of the run requested.
+func NewMultusHealth() monitortestframework.MonitorTest {
+	return &multusHealth{}
+}
+
+func (w *multusHealth) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.WriteContentToStorageStage,
+		},
+		TestNames: []string{attachTestName, detachTestName},
+	}
+}
+
+func (w *multusHealth) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	// MicroShift does not have a proper journal for the node logs api.
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+
+	w.nadClient, err = nadclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	// the network attachment definitions are only served when multus is deployed.
+	_, err = w.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions("").List(ctx, metav1.ListOptions{Limit: 1})
+	if apierrors.IsNotFound(err) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "multus is not deployed"}
+		return w.notSupportedReason
+	}
+	return err
+}
+
+func (w *multusHealth) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	definitions, err := w.nadClient.K8sCniCncfIoV1().NetworkAttachmentDefinitions("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	w.definitions = definitions.Items
+
+	nodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	ret := monitorapi.Intervals{}
+	errs := []error{}
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, node := range nodes.Items {
+		wg.Add(1)
+		go func(nodeName string) {
+			defer wg.Done()
+			journal, err := nodeaccess.GetNodeJournal(ctx, w.kubeClient, nodeName, "crio")
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to read the crio journal of %s: %w", nodeName, err))
+				return
+			}
+			ret = append(ret, scanCRIOJournal(nodeName, journal, beginning, end)...)
+		}(node.Name)
+	}
+	wg.Wait()
+	return ret, nil, utilerrors.NewAggregate(errs)
+}
+
+func (w *multusHealth) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	failures := startingIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceMultus
+	})
+	w.usage = summarizeUsage(w.definitions, recordedResources["pods"], failures)
+	return nil, nil
+}
+
+func (w *multusHealth) ConstructComputedIntervalsFromStore(ctx context.Context, startingIntervals monitorapi.Intervals, startingStore *monitorapi.IntervalStore, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	failures := startingStore.BySource(monitorapi.SourceMultus, time.Time{}, time.Time{})
+	w.usage = summarizeUsage(w.definitions, recordedResources["pods"], failures)
+	return nil, nil
+}
+
+func (w *multusHealth) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return []*junitapi.JUnitTestCase{evaluateAttach(finalIntervals), evaluateDetach(finalIntervals)}, nil
+}
+
+func (w *multusHealth) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.notSupportedReason != nil {
+		return w.notSupportedReason
+	}
+	content, err := json.MarshalIndent(w.usage, "", "    ")
+	if err != nil {
+		return err
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("multus-attachments%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
+
+func (w *multusHealth) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,139 @@
+package multushealth
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	netattdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// networksAnnotation is how a pod requests secondary networks from multus.
+const networksAnnotation = "k8s.v1.cni.cncf.io/networks"
+
+// attachmentUsage is what the run did with a network attachment definition, and is written as an artifact so changes
+// in how the suites use secondary networks can be compared between runs.
+type attachmentUsage struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Type is the CNI plugin of the definition, or empty when it was gone before the end of the run.
+	Type string `json:"type,omitempty"`
+	// Pods is how many pods seen during the run requested the network.
+	Pods           int `json:"pods"`
+	AttachFailures int `json:"attachFailures"`
+	DetachFailures int `json:"detachFailures"`
+}
+
+// requestedNetworks returns namespace/name of every network the pod requested.  The annotation is either a comma
+// separated list of [namespace/]name[@interface], or a JSON list of selection elements.
+func requestedNetworks(pod *corev1.Pod) []string {
+	value := strings.TrimSpace(pod.Annotations[networksAnnotation])
+	if len(value) == 0 {
+		return nil
+	}
+
+	ret := []string{}
+	if strings.HasPrefix(value, "[") {
+		selections := []netattdefv1.NetworkSelectionElement{}
+		if err := json.Unmarshal([]byte(value), &selections); err != nil {
+			return nil
+		}
+		for _, selection := range selections {
+			namespace := selection.Namespace
+			if len(namespace) == 0 {
+				namespace = pod.Namespace
+			}
+			ret = append(ret, namespace+"/"+selection.Name)
+		}
+		return ret
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		item, _, _ = strings.Cut(strings.TrimSpace(item), "@")
+		if len(item) == 0 {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			item = pod.Namespace + "/" + item
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// pluginType returns the CNI plugin a definition configures, which is the type of its config or of the first plugin
+// of its config list.
+func pluginType(definition netattdefv1.NetworkAttachmentDefinition) string {
+	config := struct {
+		Type    string `json:"type"`
+		Plugins []struct {
+			Type string `json:"type"`
+		} `json:"plugins"`
+	}{}
+	if err := json.Unmarshal([]byte(definition.Spec.Config), &config); err != nil {
+		return ""
+	}
+	if len(config.Type) == 0 && len(config.Plugins) > 0 {
+		return config.Plugins[0].Type
+	}
+	return config.Type
+}
+
+// summarizeUsage counts the pods requesting each definition and the failures of the pods' secondary networks.  Failures
+// are counted against every definition the pod requested, since multus names the network and not the definition.
+func summarizeUsage(definitions []netattdefv1.NetworkAttachmentDefinition, pods monitorapi.InstanceMap, failures monitorapi.Intervals) []attachmentUsage {
+	usage := map[string]*attachmentUsage{}
+	get := func(key string) *attachmentUsage {
+		if curr, ok := usage[key]; ok {
+			return curr
+		}
+		namespace, name, _ := strings.Cut(key, "/")
+		usage[key] = &attachmentUsage{Namespace: namespace, Name: name}
+		return usage[key]
+	}
+	for _, definition := range definitions {
+		get(definition.Namespace + "/" + definition.Name).Type = pluginType(definition)
+	}
+
+	requestedByPod := map[string][]string{}
+	for _, obj := range pods {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			continue
+		}
+		networks := requestedNetworks(pod)
+		for _, network := range networks {
+			get(network).Pods++
+		}
+		requestedByPod[pod.Namespace+"/"+pod.Name] = networks
+	}
+
+	for _, failure := range failures {
+		if !isSecondary(failure) {
+			continue
+		}
+		for _, network := range requestedByPod[failure.Locator.Keys[monitorapi.LocatorNamespaceKey]+"/"+failure.Locator.Keys[monitorapi.LocatorPodKey]] {
+			switch failure.Message.Reason {
+			case monitorapi.MultusAttachFailedReason:
+				get(network).AttachFailures++
+			case monitorapi.MultusDetachFailedReason:
+				get(network).DetachFailures++
+			}
+		}
+	}
+
+	ret := []attachmentUsage{}
+	for _, curr := range usage {
+		ret = append(ret, *curr)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Namespace != ret[j].Namespace {
+			return ret[i].Namespace < ret[j].Namespace
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
@@ -0,0 +1,125 @@
+package clockskew
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+const (
+	queryStep = 30 * time.Second
+
+	// offsetQuery is how far the clock of each node is from the clock of the prometheus scraping it.  node-exporter
+	// reports the node clock at the time of the scrape, which prometheus timestamps with its own clock.
+	offsetQuery = `max by (instance) (abs(node_time_seconds{job="node-exporter"} - timestamp(node_time_seconds{job="node-exporter"})))`
+	// syncStatusQuery is whether the kernel considers the clock synchronized by chrony.
+	syncStatusQuery = `min by (instance) (node_timex_sync_status{job="node-exporter"})`
+)
+
+type clockSkew struct {
+	notSupportedReason error
+
+	adminRESTConfig *rest.Config
+	windows         []skewWindow
+}
+
+// NewClockSkew samples the clock offset of every node through the run, reports when it is skewed or not synchronized,
+// and annotates the intervals of other monitor tests that began on a node while its clock was skewed.
+func NewClockSkew() monitortestframework.MonitorTest {
+	return &clockSkew{}
+}
+
+var _ monitortestframework.IntervalAnnotator = &clockSkew{}
+
+func (w *clockSkew) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{clockSkewTestName},
+	}
+}
+
+func (w *clockSkew) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+	return nil
+}
+
+func (w *clockSkew) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, w.adminRESTConfig)
+	if errors.Is(err, prometheusaccess.ErrMonitoringNotInstalled) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: err.Error()}
+		return nil, nil, w.notSupportedReason
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeRange := prometheusv1.Range{Start: beginning, End: end, Step: queryStep}
+	offsets, err := prometheusaccess.QueryRange(ctx, prometheusClient, offsetQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.windows = skewWindows(offsets, queryStep)
+	ret := skewIntervals(w.windows)
+
+	syncStatus, err := prometheusaccess.QueryRange(ctx, prometheusClient, syncStatusQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret = append(ret, unsynchronizedIntervals(syncStatus, queryStep)...)
+	return ret, nil, nil
+}
+
+func (w *clockSkew) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *clockSkew) AnnotateInterval(interval monitorapi.Interval) map[monitorapi.AnnotationKey]string {
+	return skewAt(w.windows, interval)
+}
+
+func (w *clockSkew) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return []*junitapi.JUnitTestCase{evaluateSkew(finalIntervals)}, nil
+}
+
+func (w *clockSkew) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *clockSkew) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,155 @@
+package clockskew
+
+import (
+	"fmt"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// skewedSeconds is when the order of intervals from different nodes can no longer be trusted.  Most intervals are
+	// compared at a second of resolution.
+	skewedSeconds = 1.0
+	// maxSkewSeconds is when the skew breaks the cluster rather than just the intervals.  Leases, tokens and
+	// certificates are checked against clocks that are expected to agree within seconds.
+	maxSkewSeconds = 5.0
+)
+
+var clockSkewTestName = fmt.Sprintf("[sig-node] node clocks should not be skewed by more than %vs", maxSkewSeconds)
+
+var clockSkewTemplate = junitfailure.MustParseTemplate("node-clock-skew",
+	`the clocks of {{.Fields.nodes}} nodes were off by up to {{printf "%.1f" .Threshold.Observed}}s, more than the {{printf "%.0f" .Threshold.Limit}}s allowed.  Intervals from those nodes are annotated with the skew, their order relative to other nodes cannot be trusted.
+{{.IntervalList}}`)
+
+// skewWindow is a span of time in which the clock of a node was off by more than skewedSeconds.
+type skewWindow struct {
+	node   string
+	window prometheusaccess.Window
+}
+
+// skewWindows returns the windows in which each node's clock was off by more than skewedSeconds.  The series are the
+// offset of the node clock from the clock of the prometheus that scraped it, by instance, which is the node name.
+func skewWindows(offsets prometheustypes.Matrix, step time.Duration) []skewWindow {
+	ret := []skewWindow{}
+	for _, series := range offsets {
+		node := string(series.Metric["instance"])
+		for _, window := range prometheusaccess.Windows(series.Values, step, func(value float64) bool { return value > skewedSeconds }) {
+			ret = append(ret, skewWindow{node: node, window: window})
+		}
+	}
+	return ret
+}
+
+// skewIntervals reports every skew window, as an error when the skew passed maxSkewSeconds.
+func skewIntervals(windows []skewWindow) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, curr := range windows {
+		level := monitorapi.Warning
+		if curr.window.Peak > maxSkewSeconds {
+			level = monitorapi.Error
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceNodeClock, level).
+				Locator(monitorapi.NewLocator().NodeFromName(curr.node)).
+				Message(monitorapi.NewMessage().Reason(monitorapi.NodeClockSkewReason).
+					WithAnnotation(monitorapi.AnnotationClockSkew, formatSkew(curr.window.Peak)).
+					HumanMessagef("node clock was off by up to %s", formatSkew(curr.window.Peak))).
+				Display().
+				Build(curr.window.From, curr.window.To),
+		)
+	}
+	return ret
+}
+
+// unsynchronizedIntervals reports when the kernel of a node says its clock is not synchronized to a time source.
+func unsynchronizedIntervals(syncStatus prometheustypes.Matrix, step time.Duration) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, series := range syncStatus {
+		node := string(series.Metric["instance"])
+		for _, window := range prometheusaccess.Windows(series.Values, step, func(value float64) bool { return value == 0 }) {
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceNodeClock, monitorapi.Warning).
+					Locator(monitorapi.NewLocator().NodeFromName(node)).
+					Message(monitorapi.NewMessage().Reason(monitorapi.NodeClockUnsynchronizedReason).
+						HumanMessage("node clock was not synchronized by NTP")).
+					Display().
+					Build(window.From, window.To),
+			)
+		}
+	}
+	return ret
+}
+
+func formatSkew(seconds float64) string {
+	return fmt.Sprintf("%.1fs", seconds)
+}
+
+// skewAt returns the annotations for an interval that began on a node while its clock was skewed.  The skew intervals
+// themselves are left alone.
+func skewAt(windows []skewWindow, interval monitorapi.Interval) map[monitorapi.AnnotationKey]string {
+	if interval.Source == monitorapi.SourceNodeClock {
+		return nil
+	}
+	node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+	if len(node) == 0 {
+		return nil
+	}
+	for _, curr := range windows {
+		if curr.node != node || interval.From.Before(curr.window.From) || !interval.From.Before(curr.window.To) {
+			continue
+		}
+		return map[monitorapi.AnnotationKey]string{monitorapi.AnnotationClockSkew: formatSkew(curr.window.Peak)}
+	}
+	return nil
+}
+
+// evaluateSkew fails when the clock of any node was off by more than maxSkewSeconds.
+func evaluateSkew(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	skewed := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceNodeClock && interval.Message.Reason == monitorapi.NodeClockSkewReason
+	})
+	var peak time.Duration
+	tooSkewed := monitorapi.Intervals{}
+	nodes := map[string]bool{}
+	for _, interval := range skewed {
+		skew, err := time.ParseDuration(interval.Message.Annotations[monitorapi.AnnotationClockSkew])
+		if err != nil {
+			continue
+		}
+		if skew > peak {
+			peak = skew
+		}
+		if interval.Level == monitorapi.Error {
+			tooSkewed = append(tooSkewed, interval)
+			nodes[interval.Locator.Keys[monitorapi.LocatorNodeKey]] = true
+		}
+	}
+
+	threshold := junitapi.JUnitThreshold{
+		Name:     "node-clock-skew-seconds",
+		Limit:    maxSkewSeconds,
+		Observed: peak.Seconds(),
+		Unit:     "seconds",
+		Exceeded: len(tooSkewed) > 0,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    clockSkewTestName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+	junit := junitfailure.NewFailure(clockSkewTemplate, "NodeClockSkew").
+		Threshold(threshold).
+		Field("nodes", len(nodes)).
+		Intervals(tooSkewed...).
+		TestCase(clockSkewTestName)
+	// a warning until we know how far the clocks of CI nodes drift on every platform.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
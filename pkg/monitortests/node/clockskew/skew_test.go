@@ -0,0 +1,76 @@
+package clockskew
+
+import (
+	"testing"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func series(node string, values ...float64) *prometheustypes.SampleStream {
+	ret := &prometheustypes.SampleStream{Metric: prometheustypes.Metric{"instance": prometheustypes.LabelValue(node)}}
+	for i, value := range values {
+		ret.Values = append(ret.Values, prometheustypes.SamplePair{
+			Timestamp: prometheustypes.TimeFromUnixNano(start.Add(time.Duration(i) * queryStep).UnixNano()),
+			Value:     prometheustypes.SampleValue(value),
+		})
+	}
+	return ret
+}
+
+func TestSkew(t *testing.T) {
+	offsets := prometheustypes.Matrix{
+		series("master-0", 0.01, 0.02, 0.01, 0.01),
+		series("worker-0", 0.1, 1.5, 2.5, 0.1, 0.1, 7.0, 0.1),
+	}
+	windows := skewWindows(offsets, queryStep)
+	if len(windows) != 2 {
+		t.Fatalf("expected two skew windows on worker-0, got %v", windows)
+	}
+
+	intervals := skewIntervals(windows)
+	if intervals[0].Level != monitorapi.Warning || intervals[1].Level != monitorapi.Error {
+		t.Errorf("expected only the skew over %vs to be an error, got %v and %v", maxSkewSeconds, intervals[0].Level, intervals[1].Level)
+	}
+	if skew := intervals[0].Message.Annotations[monitorapi.AnnotationClockSkew]; skew != "2.5s" {
+		t.Errorf("expected the peak skew, got %v", skew)
+	}
+
+	onNode := func(node string, offset time.Duration) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourcePodLog, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName(node)).
+			Message(monitorapi.NewMessage().HumanMessage("log line")).
+			Build(start.Add(offset), start.Add(offset+time.Second))
+	}
+	if annotations := skewAt(windows, onNode("worker-0", 45*time.Second)); annotations[monitorapi.AnnotationClockSkew] != "2.5s" {
+		t.Errorf("expected an interval in the skew window to be annotated, got %v", annotations)
+	}
+	if annotations := skewAt(windows, onNode("worker-0", 2*time.Minute)); annotations != nil {
+		t.Errorf("expected an interval after the skew window not to be annotated, got %v", annotations)
+	}
+	if annotations := skewAt(windows, onNode("master-0", 45*time.Second)); annotations != nil {
+		t.Errorf("expected an interval on another node not to be annotated, got %v", annotations)
+	}
+	if annotations := skewAt(windows, intervals[0]); annotations != nil {
+		t.Errorf("expected the skew interval not to be annotated, got %v", annotations)
+	}
+
+	junit := evaluateSkew(intervals)
+	if junit.FailureOutput == nil || len(junit.Details.IntervalIDs) != 1 {
+		t.Errorf("expected the skew over %vs to fail, got %#v", maxSkewSeconds, junit)
+	}
+	if junit := evaluateSkew(intervals[:1]); junit.FailureOutput != nil {
+		t.Errorf("expected a skew under %vs to pass, got %v", maxSkewSeconds, junit.FailureOutput.Output)
+	}
+}
+
+func TestUnsynchronizedIntervals(t *testing.T) {
+	intervals := unsynchronizedIntervals(prometheustypes.Matrix{series("worker-0", 1, 0, 0, 1)}, queryStep)
+	if len(intervals) != 1 || !intervals[0].From.Equal(start.Add(queryStep)) || !intervals[0].To.Equal(start.Add(3*queryStep)) {
+		t.Errorf("expected one unsynchronized interval, got %v", intervals)
+	}
+}
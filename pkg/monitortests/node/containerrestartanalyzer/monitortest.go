@@ -0,0 +1,80 @@
+package containerrestartanalyzer
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+type containerRestartAnalyzer struct {
+	adminRESTConfig *rest.Config
+
+	// namespaceCreation is when every namespace that still exists at the end of the run was created.
+	namespaceCreation map[string]time.Time
+}
+
+func NewContainerRestartAnalyzer() monitortestframework.MonitorTest {
+	return &containerRestartAnalyzer{}
+}
+
+func (w *containerRestartAnalyzer) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{restartBudgetTestName, oomKillTestName},
+	}
+}
+
+func (w *containerRestartAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *containerRestartAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w.namespaceCreation = map[string]time.Time{}
+	for _, namespace := range namespaces.Items {
+		w.namespaceCreation[namespace.Name] = namespace.CreationTimestamp.Time
+	}
+	return nil, nil, nil
+}
+
+func (*containerRestartAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return intervalsFromContainerExits(startingIntervals, end), nil
+}
+
+func (w *containerRestartAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	excludedNamespaces := namespacesCreatedDuringDisruptiveTests(w.namespaceCreation, finalIntervals)
+	if len(excludedNamespaces) > 0 {
+		logrus.Infof("not evaluating container restarts in namespaces created by disruptive tests: %s", describeNamespaces(excludedNamespaces))
+	}
+	return evaluateRestarts(finalIntervals, excludedNamespaces), nil
+}
+
+func (*containerRestartAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*containerRestartAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,186 @@
+package containerrestartanalyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformnamespaces"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	restartBudgetTestName = "[sig-node] platform containers should not restart more often than their budget"
+	oomKillTestName       = "[sig-node] platform containers should not be OOMKilled"
+
+	// restartBudget is how many times a platform container may exit non-zero during the run.  A container that loses
+	// its lease or its connection to the apiserver may exit on purpose to start over, but not repeatedly.
+	restartBudget = 3
+
+	oomKilledCause = "OOMKilled"
+)
+
+// intervalsFromContainerExits pairs every time a container exited non-zero with the next time it started into a
+// restart interval.  The pod watcher reports an exit both when the container terminates and when the kubelet records
+// it as the last termination state, so exits before the next start count once.
+func intervalsFromContainerExits(intervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	exited := map[monitorapi.ContainerReference]monitorapi.Interval{}
+	containers := []monitorapi.ContainerReference{}
+
+	ret := monitorapi.Intervals{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourcePodMonitor {
+			continue
+		}
+		container := monitorapi.ContainerFrom(interval.Locator)
+		if len(container.ContainerName) == 0 {
+			continue
+		}
+
+		switch interval.Message.Reason {
+		case monitorapi.ContainerReasonContainerExit:
+			if interval.Message.Annotations[monitorapi.AnnotationContainerExitCode] == "0" {
+				continue
+			}
+			if _, ok := exited[container]; !ok {
+				exited[container] = interval
+				containers = append(containers, container)
+			}
+		case monitorapi.ContainerReasonContainerStart:
+			if exit, ok := exited[container]; ok {
+				ret = append(ret, restartInterval(exit, interval.From))
+				delete(exited, container)
+			}
+		}
+	}
+	for _, container := range containers {
+		if exit, ok := exited[container]; ok {
+			ret = append(ret, restartInterval(exit, end))
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func restartInterval(exit monitorapi.Interval, to time.Time) monitorapi.Interval {
+	exitCode := exit.Message.Annotations[monitorapi.AnnotationContainerExitCode]
+	cause := exit.Message.Annotations[monitorapi.AnnotationCause]
+	return monitorapi.NewInterval(monitorapi.SourcePodState, monitorapi.Warning).
+		Locator(exit.Locator).
+		Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonRestarting).
+			Cause(cause).
+			WithAnnotation(monitorapi.AnnotationContainerExitCode, exitCode).
+			HumanMessagef("container exited with code %s and restarted", exitCode).
+			Constructed(monitorapi.ConstructionOwnerPodLifecycle)).
+		Display().
+		Build(exit.From, to)
+}
+
+var restartBudgetTemplate = junitfailure.MustParseTemplate("container-restart-budget",
+	`{{.Fields.containers}} platform containers restarted more than {{.Threshold.Limit}} times, the most {{.Threshold.Observed}} times.  Platform containers restarting repeatedly are crashlooping or being killed by their liveness probes.
+{{.IntervalList}}`)
+
+var oomKillTemplate = junitfailure.MustParseTemplate("container-oomkilled",
+	`{{len .Intervals}} platform containers were OOMKilled.  The memory requests and limits of platform containers must fit their usage during a run.
+{{.IntervalList}}`)
+
+// evaluateRestarts fails for platform containers restarting more than the budget or being OOMKilled.  Namespaces
+// in excludedNamespaces, created by destructive tests that kill containers on purpose, are not evaluated.
+func evaluateRestarts(finalIntervals monitorapi.Intervals, excludedNamespaces map[string]bool) []*junitapi.JUnitTestCase {
+	restartsByContainer := map[monitorapi.ContainerReference]monitorapi.Intervals{}
+	oomKills := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourcePodState || interval.Message.Reason != monitorapi.ContainerReasonRestarting {
+			continue
+		}
+		namespace := interval.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		if !platformnamespaces.IsPlatformNamespace(namespace) || excludedNamespaces[namespace] {
+			continue
+		}
+		container := monitorapi.ContainerFrom(interval.Locator)
+		restartsByContainer[container] = append(restartsByContainer[container], interval)
+		if interval.Message.Annotations[monitorapi.AnnotationCause] == oomKilledCause {
+			oomKills = append(oomKills, interval)
+		}
+	}
+
+	overBudget := monitorapi.Intervals{}
+	containersOverBudget, mostRestarts := 0, 0
+	for _, restarts := range restartsByContainer {
+		if len(restarts) > mostRestarts {
+			mostRestarts = len(restarts)
+		}
+		if len(restarts) <= restartBudget {
+			continue
+		}
+		containersOverBudget++
+		overBudget = append(overBudget, restarts...)
+	}
+	sort.Sort(overBudget)
+
+	ret := []*junitapi.JUnitTestCase{}
+	if containersOverBudget == 0 {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: restartBudgetTestName})
+	} else {
+		junit := junitfailure.NewFailure(restartBudgetTemplate, "ContainerRestartBudgetExceeded").
+			Threshold(junitapi.JUnitThreshold{
+				Name:     "restarts",
+				Observed: float64(mostRestarts),
+				Limit:    restartBudget,
+				Unit:     "restarts",
+			}).
+			Field("containers", containersOverBudget).
+			Intervals(overBudget...).
+			TestCase(restartBudgetTestName)
+		// a warning until the budget is tuned against what platform containers do today.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+
+	if len(oomKills) == 0 {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: oomKillTestName})
+	} else {
+		junit := junitfailure.NewFailure(oomKillTemplate, "ContainerOOMKilled").
+			Intervals(oomKills...).
+			TestCase(oomKillTestName)
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
+
+// namespacesCreatedDuringDisruptiveTests returns the namespaces that were created while a disruptive test was running.
+func namespacesCreatedDuringDisruptiveTests(namespaceCreation map[string]time.Time, finalIntervals monitorapi.Intervals) map[string]bool {
+	disruptiveTests := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceE2ETest || !interval.To.After(interval.From) {
+			continue
+		}
+		if testName, ok := monitorapi.E2ETestFromLocator(interval.Locator); ok && strings.Contains(testName, "[Disruptive]") {
+			disruptiveTests = append(disruptiveTests, interval)
+		}
+	}
+
+	ret := map[string]bool{}
+	for namespace, created := range namespaceCreation {
+		for _, test := range disruptiveTests {
+			if !created.Before(test.From) && !created.After(test.To) {
+				ret[namespace] = true
+				break
+			}
+		}
+	}
+	return ret
+}
+
+func describeNamespaces(namespaces map[string]bool) string {
+	names := []string{}
+	for namespace := range namespaces {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%v", names)
+}
@@ -0,0 +1,112 @@
+package containerrestartanalyzer
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func containerInterval(namespace, pod string, reason monitorapi.IntervalReason, exitCode, cause string, at time.Duration) monitorapi.Interval {
+	message := monitorapi.NewMessage().Reason(reason).HumanMessage(string(reason))
+	if reason == monitorapi.ContainerReasonContainerExit {
+		message = message.WithAnnotation(monitorapi.AnnotationContainerExitCode, exitCode).Cause(cause)
+	}
+	return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().ContainerFromNames(namespace, pod, pod+"-uid", "container")).
+		Message(message).
+		Build(start.Add(at), start.Add(at))
+}
+
+func crashloop(namespace, pod string, times int, cause string) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for i := 0; i < times; i++ {
+		at := time.Duration(i) * time.Minute
+		ret = append(ret,
+			// reported when the container terminates and again when it becomes the last termination state.
+			containerInterval(namespace, pod, monitorapi.ContainerReasonContainerExit, "137", cause, at),
+			containerInterval(namespace, pod, monitorapi.ContainerReasonContainerExit, "137", cause, at+time.Second),
+			containerInterval(namespace, pod, monitorapi.ContainerReasonContainerStart, "", "", at+10*time.Second),
+		)
+	}
+	return ret
+}
+
+func TestIntervalsFromContainerExits(t *testing.T) {
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, crashloop("openshift-etcd", "etcd-0", 2, "Error")...)
+	// a clean exit is not a restart.
+	intervals = append(intervals, containerInterval("openshift-etcd", "etcd-1", monitorapi.ContainerReasonContainerExit, "0", "Completed", time.Minute))
+	// never started again.
+	intervals = append(intervals, containerInterval("openshift-etcd", "etcd-2", monitorapi.ContainerReasonContainerExit, "1", "Error", time.Minute))
+	sort.Sort(intervals)
+
+	end := start.Add(time.Hour)
+	restarts := intervalsFromContainerExits(intervals, end)
+	if len(restarts) != 3 {
+		t.Fatalf("expected 3 restarts, got %d: %v", len(restarts), restarts)
+	}
+	if from, to := restarts[0].From, restarts[0].To; !from.Equal(start) || !to.Equal(start.Add(10*time.Second)) {
+		t.Errorf("expected the first restart to last from the exit to the start, got %v to %v", from, to)
+	}
+	last := restarts[len(restarts)-1]
+	if last.Locator.Keys[monitorapi.LocatorPodKey] != "etcd-2" || !last.To.Equal(end) {
+		t.Errorf("expected etcd-2 to be restarting until the end, got %v", last)
+	}
+}
+
+func TestEvaluateRestarts(t *testing.T) {
+	intervals := monitorapi.Intervals{}
+	intervals = append(intervals, crashloop("openshift-etcd", "etcd-0", restartBudget+1, "Error")...)
+	intervals = append(intervals, crashloop("openshift-etcd", "etcd-1", restartBudget, "Error")...)
+	intervals = append(intervals, crashloop("openshift-monitoring", "prometheus-0", 1, oomKilledCause)...)
+	// not a platform namespace.
+	intervals = append(intervals, crashloop("e2e-test-crashloop", "crasher", 10, oomKilledCause)...)
+	// a platform namespace created by a disruptive test.
+	intervals = append(intervals, crashloop("openshift-test-disruption", "victim", 10, "Error")...)
+	sort.Sort(intervals)
+	finalIntervals := append(intervals, intervalsFromContainerExits(intervals, start.Add(time.Hour))...)
+
+	junits := evaluateRestarts(finalIntervals, map[string]bool{"openshift-test-disruption": true})
+	if len(junits) != 2 {
+		t.Fatalf("expected two tests, got %d", len(junits))
+	}
+	for _, junit := range junits {
+		if junit.FailureOutput == nil {
+			t.Errorf("expected %q to fail", junit.Name)
+			continue
+		}
+		if junit.Details.Severity != junitapi.SeverityWarn {
+			t.Errorf("expected %q to be a warning, got %q", junit.Name, junit.Details.Severity)
+		}
+		switch junit.Name {
+		case restartBudgetTestName:
+			if got := len(junit.Details.IntervalIDs); got != restartBudget+1 {
+				t.Errorf("expected only the restarts of etcd-0, got %d", got)
+			}
+		case oomKillTestName:
+			if got := len(junit.Details.IntervalIDs); got != 1 {
+				t.Errorf("expected only prometheus to be OOMKilled, got %d", got)
+			}
+		}
+	}
+}
+
+func TestNamespacesCreatedDuringDisruptiveTests(t *testing.T) {
+	disruptiveTest := monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+		Locator(monitorapi.NewLocator().E2ETest("[sig-node][Disruptive] kill platform containers")).
+		Message(monitorapi.NewMessage().HumanMessage("e2e test finished")).
+		Build(start.Add(10*time.Minute), start.Add(20*time.Minute))
+
+	excluded := namespacesCreatedDuringDisruptiveTests(map[string]time.Time{
+		"openshift-etcd":            start.Add(-time.Hour),
+		"openshift-test-disruption": start.Add(15 * time.Minute),
+	}, monitorapi.Intervals{disruptiveTest})
+	if len(excluded) != 1 || !excluded["openshift-test-disruption"] {
+		t.Errorf("expected only the namespace created by the disruptive test to be excluded, got %v", excluded)
+	}
+}
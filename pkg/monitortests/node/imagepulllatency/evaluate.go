@@ -0,0 +1,61 @@
+package imagepulllatency
+
+import (
+	"fmt"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var (
+	pullDurationTestName   = fmt.Sprintf("[sig-node] image pulls should complete within %v", maxPullDuration)
+	mirrorFallbackTestName = "[sig-node] image pulls should not fall back from the preferred mirror"
+)
+
+var pullDurationTemplate = junitfailure.MustParseTemplate("image-pull-duration",
+	`{{len .Intervals}} image pulls took longer than {{.Fields.limit}}.  Slow pulls delay every pod on the node that waits for the image, and usually point at the registry or the network to it.
+{{.IntervalList}}`)
+
+var mirrorFallbackTemplate = junitfailure.MustParseTemplate("image-mirror-fallback",
+	`image pulls fell back from the preferred mirror {{len .Intervals}} times.  The preferred mirror failed, and on a disconnected cluster the remaining locations may not have the image either.
+{{.IntervalList}}`)
+
+// evaluatePulls fails when a pull took longer than maxPullDuration.  It is a warning until we know how long pulls
+// take on every platform.
+func evaluatePulls(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	tooSlow := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceImagePull &&
+			interval.Message.Reason == monitorapi.ImagePullSlowReason &&
+			interval.Level == monitorapi.Error
+	})
+	if len(tooSlow) == 0 {
+		return &junitapi.JUnitTestCase{Name: pullDurationTestName}
+	}
+	junit := junitfailure.NewFailure(pullDurationTemplate, "ImagePullTooSlow").
+		Field("limit", maxPullDuration.String()).
+		Intervals(tooSlow...).
+		TestCase(pullDurationTestName)
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+// evaluateMirrorFallbacks fails when a pull did not come from the preferred mirror.  Without digest mirrors there is
+// nothing to fall back from, and no test.
+func evaluateMirrorFallbacks(sets []mirrorSet, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	if len(sets) == 0 {
+		return nil
+	}
+	fellBack := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceImagePull && interval.Message.Reason == monitorapi.ImageMirrorFallbackReason
+	})
+	if len(fellBack) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: mirrorFallbackTestName}}
+	}
+	junit := junitfailure.NewFailure(mirrorFallbackTemplate, "ImageMirrorFallback").
+		Intervals(fellBack...).
+		TestCase(mirrorFallbackTestName)
+	// a warning until we know how often mirrors in CI are briefly unavailable.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return []*junitapi.JUnitTestCase{junit}
+}
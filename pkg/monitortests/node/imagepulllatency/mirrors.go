@@ -0,0 +1,163 @@
+package imagepulllatency
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	operatorclient "github.com/openshift/client-go/operator/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
+)
+
+// mirrorSet is the locations an image of the source is pulled from, in the order CRI-O tries them.  The source is
+// last, and is only tried when every mirror failed.
+type mirrorSet struct {
+	source    string
+	locations []string
+}
+
+// mirrorSets reads the digest mirrors of the ImageDigestMirrorSets and of the older ImageContentSourcePolicies.
+// Either may not be served, for instance on MicroShift.
+func mirrorSets(ctx context.Context, adminRESTConfig *rest.Config) ([]mirrorSet, error) {
+	ret := []mirrorSet{}
+
+	configClient, err := configclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	digestMirrorSets, err := configClient.ConfigV1().ImageDigestMirrorSets().List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	default:
+		for _, digestMirrorSet := range digestMirrorSets.Items {
+			for _, digestMirrors := range digestMirrorSet.Spec.ImageDigestMirrors {
+				locations := []string{}
+				for _, mirror := range digestMirrors.Mirrors {
+					locations = append(locations, string(mirror))
+				}
+				ret = append(ret, mirrorSet{source: digestMirrors.Source, locations: append(locations, digestMirrors.Source)})
+			}
+		}
+	}
+
+	operatorClient, err := operatorclient.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := operatorClient.OperatorV1alpha1().ImageContentSourcePolicies().List(ctx, metav1.ListOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	default:
+		for _, policy := range policies.Items {
+			for _, digestMirrors := range policy.Spec.RepositoryDigestMirrors {
+				locations := append([]string{}, digestMirrors.Mirrors...)
+				ret = append(ret, mirrorSet{source: digestMirrors.Source, locations: append(locations, digestMirrors.Source)})
+			}
+		}
+	}
+	return ret, nil
+}
+
+// matchesLocation is true when the repository is the location or in it, the way registries.conf matches prefixes.  A
+// location starting with *. matches every host under the domain.
+func matchesLocation(repository, location string) bool {
+	if strings.HasPrefix(location, "*.") {
+		host, _, _ := strings.Cut(repository, "/")
+		return strings.HasSuffix(host, location[1:])
+	}
+	return repository == location || strings.HasPrefix(repository, location+"/")
+}
+
+// fallbackPosition returns the source of the mirror set the repository is a location of, and how many locations CRI-O
+// tried before it.  Zero is the preferred mirror.
+func fallbackPosition(sets []mirrorSet, repository string) (string, int, bool) {
+	for _, set := range sets {
+		for i, location := range set.locations {
+			if matchesLocation(repository, location) {
+				return set.source, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// tryingToAccessRegex matches the line CRI-O logs for every location it tries to pull an image from.
+var tryingToAccessRegex = regexp.MustCompile(`Trying to access \\?"([^"\\]+)\\?"`)
+
+// mergeWindow is how close together the fallbacks of a source on a node must be to share an interval.  Pulls of the
+// payload images happen in bursts, every one of which falls back while a mirror is down.
+const mergeWindow = time.Minute
+
+type fallbacks struct {
+	from, to  time.Time
+	count     int
+	location  string
+	reference string
+}
+
+// scanCRIOJournal returns an interval for every burst of pulls on a node that fell back from the preferred mirror of a
+// source.  Only references by digest use the digest mirrors.
+func scanCRIOJournal(nodeName string, journal []byte, sets []mirrorSet, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	open := map[string]*fallbacks{}
+	flush := func(source string) {
+		curr := open[source]
+		delete(open, source)
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceImagePull, monitorapi.Warning).
+				Locator(monitorapi.NewLocator().NodeFromName(nodeName)).
+				Message(monitorapi.NewMessage().Reason(monitorapi.ImageMirrorFallbackReason).
+					WithAnnotation(monitorapi.AnnotationImage, curr.reference).
+					WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(curr.count)).
+					HumanMessagef("%d pulls of images from %s fell back to %s, the first was %s", curr.count, source, curr.location, curr.reference)).
+				Display().
+				Build(curr.from, curr.to.Add(time.Second)),
+		)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(journal))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		m := tryingToAccessRegex.FindStringSubmatch(line)
+		if m == nil || !strings.Contains(m[1], "@") {
+			continue
+		}
+		logTime := nodeaccess.SystemdJournalLogTime(line)
+		if logTime.Before(beginning) || logTime.After(end) {
+			continue
+		}
+		repository, _, _ := strings.Cut(m[1], "@")
+		source, position, ok := fallbackPosition(sets, repository)
+		if !ok || position == 0 {
+			continue
+		}
+		if curr, ok := open[source]; ok {
+			if logTime.Sub(curr.to) <= mergeWindow {
+				curr.to = logTime
+				curr.count++
+				continue
+			}
+			flush(source)
+		}
+		open[source] = &fallbacks{from: logTime, to: logTime, count: 1, location: repository, reference: m[1]}
+	}
+	for source := range open {
+		flush(source)
+	}
+	return ret
+}
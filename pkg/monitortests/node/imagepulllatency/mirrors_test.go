@@ -0,0 +1,53 @@
+package imagepulllatency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestScanCRIOJournal(t *testing.T) {
+	sets := []mirrorSet{
+		{
+			source:    "quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+			locations: []string{"mirror-a.local/ocp/release", "mirror-b.local/ocp/release", "quay.io/openshift-release-dev/ocp-v4.0-art-dev"},
+		},
+		{
+			source:    "*.redhat.io",
+			locations: []string{"mirror-a.local/redhat", "*.redhat.io"},
+		},
+	}
+	journal := []byte(`Jan 01 00:00:30.000000 worker-0 crio[1]: time="2024-01-01T00:00:30Z" level=info msg="Trying to access \"mirror-a.local/ocp/release@sha256:aaa\""
+Jan 01 00:01:00.000000 worker-0 crio[1]: time="2024-01-01T00:01:00Z" level=info msg="Trying to access \"mirror-a.local/ocp/release@sha256:bbb\""
+Jan 01 00:01:05.000000 worker-0 crio[1]: time="2024-01-01T00:01:05Z" level=info msg="Trying to access \"mirror-b.local/ocp/release@sha256:bbb\""
+Jan 01 00:01:10.000000 worker-0 crio[1]: time="2024-01-01T00:01:10Z" level=info msg="Trying to access \"quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:bbb\""
+Jan 01 00:05:00.000000 worker-0 crio[1]: time="2024-01-01T00:05:00Z" level=info msg="Trying to access \"registry.redhat.io/product/repo@sha256:ccc\""
+Jan 01 00:06:00.000000 worker-0 crio[1]: time="2024-01-01T00:06:00Z" level=info msg="Trying to access \"registry.redhat.io/product/repo:latest\""
+Jan 01 00:07:00.000000 worker-0 crio[1]: time="2024-01-01T00:07:00Z" level=info msg="Trying to access \"docker.io/library/busybox@sha256:ddd\""
+`)
+	beginning := time.Date(time.Now().Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	intervals := scanCRIOJournal("worker-0", journal, sets, beginning, beginning.Add(time.Hour))
+	if len(intervals) != 2 {
+		t.Fatalf("expected a fallback for each source, got %v", intervals)
+	}
+	for _, interval := range intervals {
+		switch interval.Message.Annotations[monitorapi.AnnotationImage] {
+		case "mirror-b.local/ocp/release@sha256:bbb":
+			// the second mirror and the source are one burst of fallbacks.
+			if count := interval.Message.Annotations[monitorapi.AnnotationCount]; count != "2" {
+				t.Errorf("expected two fallbacks, got %v", count)
+			}
+		case "registry.redhat.io/product/repo@sha256:ccc":
+		default:
+			t.Errorf("unexpected fallback %v", interval)
+		}
+	}
+
+	if junits := evaluateMirrorFallbacks(sets, intervals); len(junits) != 1 || junits[0].FailureOutput == nil {
+		t.Errorf("expected the fallbacks to fail, got %v", junits)
+	}
+	if junits := evaluateMirrorFallbacks(nil, nil); len(junits) != 0 {
+		t.Errorf("expected no test without mirrors, got %v", junits)
+	}
+}
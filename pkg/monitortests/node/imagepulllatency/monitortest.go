@@ -0,0 +1,141 @@
+package imagepulllatency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type imagePullLatency struct {
+	notSupportedReason error
+
+	kubeClient kubernetes.Interface
+	mirrorSets []mirrorSet
+
+	summaries []nodePullSummary
+}
+
+// NewImagePullLatency measures how long the image pulls the kubelets report take on each node, and finds pulls that
+// fell back from the preferred digest mirror in the CRI-O journal.
+func NewImagePullLatency() monitortestframework.MonitorTest {
+	return &imagePullLatency{}
+}
+
+func (w *imagePullLatency) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.WriteContentToStorageStage,
+		},
+		TestNames: []string{pullDurationTestName, mirrorFallbackTestName},
+	}
+}
+
+func (w *imagePullLatency) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	var err error
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	// MicroShift does not have a proper journal for the node logs api, nor mirror sets.
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "platform MicroShift not supported"}
+		return w.notSupportedReason
+	}
+
+	w.mirrorSets, err = mirrorSets(ctx, adminRESTConfig)
+	return err
+}
+
+func (w *imagePullLatency) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	if len(w.mirrorSets) == 0 {
+		return nil, nil, nil
+	}
+
+	nodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	ret := monitorapi.Intervals{}
+	errs := []error{}
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, node := range nodes.Items {
+		wg.Add(1)
+		go func(nodeName string) {
+			defer wg.Done()
+			journal, err := nodeaccess.GetNodeJournal(ctx, w.kubeClient, nodeName, "crio")
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to read the crio journal of %s: %w", nodeName, err))
+				return
+			}
+			ret = append(ret, scanCRIOJournal(nodeName, journal, w.mirrorSets, beginning, end)...)
+		}(node.Name)
+	}
+	wg.Wait()
+	return ret, nil, utilerrors.NewAggregate(errs)
+}
+
+func (w *imagePullLatency) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	pulls := pullsFromEvents(startingIntervals)
+	w.summaries = summarizeByNode(pulls)
+	return slowPullIntervals(pulls), nil
+}
+
+func (w *imagePullLatency) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	ret := []*junitapi.JUnitTestCase{evaluatePulls(finalIntervals)}
+	return append(ret, evaluateMirrorFallbacks(w.mirrorSets, finalIntervals)...), nil
+}
+
+func (w *imagePullLatency) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.notSupportedReason != nil {
+		return w.notSupportedReason
+	}
+	content, err := json.MarshalIndent(w.summaries, "", "    ")
+	if err != nil {
+		return err
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("image-pull-latency%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
+
+func (w *imagePullLatency) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,133 @@
+package imagepulllatency
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// slowPullDuration is when a pull is recorded as slow.  Most payload images pull in well under a minute from a
+	// registry in the same cloud.
+	slowPullDuration = time.Minute
+	// maxPullDuration is when a slow pull fails the test, it is long enough for the largest test images.
+	maxPullDuration = 5 * time.Minute
+)
+
+// pulledRegex matches the message of the Pulled event the kubelet emits after a pull.  Newer kubelets add the time the
+// pull spent waiting for other pulls, which is left out, because it is the kubelet being busy, not the registry.
+//
+//	Successfully pulled image "quay.io/foo/bar@sha256:abc" in 2.345s (2.345s including waiting)
+var pulledRegex = regexp.MustCompile(`^Successfully pulled image "([^"]+)" in ([0-9.]+[hmsµun0-9.]*)`)
+
+// pull is one image pull the kubelet reported.
+type pull struct {
+	Node     string        `json:"node"`
+	Image    string        `json:"image"`
+	Finished time.Time     `json:"finished"`
+	Duration time.Duration `json:"duration"`
+
+	locator monitorapi.Locator
+}
+
+// pullsFromEvents finds the pulls in the Pulled events.  Pulls of images already present on the node are not pulls.
+func pullsFromEvents(startingIntervals monitorapi.Intervals) []pull {
+	ret := []pull{}
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourceKubeEvent || interval.Message.Reason != "Pulled" {
+			continue
+		}
+		m := pulledRegex.FindStringSubmatch(interval.Message.HumanMessage)
+		if m == nil {
+			continue
+		}
+		duration, err := time.ParseDuration(m[2])
+		if err != nil {
+			continue
+		}
+		ret = append(ret, pull{
+			Node:     interval.Locator.Keys[monitorapi.LocatorNodeKey],
+			Image:    m[1],
+			Finished: interval.From,
+			Duration: duration,
+			locator:  interval.Locator,
+		})
+	}
+	return ret
+}
+
+// slowPullIntervals covers every pull that took longer than slowPullDuration, from when it started to when it
+// finished.
+func slowPullIntervals(pulls []pull) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, curr := range pulls {
+		if curr.Duration <= slowPullDuration {
+			continue
+		}
+		level := monitorapi.Warning
+		if curr.Duration > maxPullDuration {
+			level = monitorapi.Error
+		}
+		ret = append(ret,
+			monitorapi.NewInterval(monitorapi.SourceImagePull, level).
+				Locator(curr.locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.ImagePullSlowReason).
+					Constructed(monitorapi.ConstructionOwnerImagePull).
+					WithAnnotation(monitorapi.AnnotationImage, curr.Image).
+					WithAnnotation(monitorapi.AnnotationDuration, fmt.Sprintf("%.3fs", curr.Duration.Seconds())).
+					HumanMessagef("pulling %s took %v, more than %v", curr.Image, curr.Duration.Round(time.Second), slowPullDuration)).
+				Display().
+				Build(curr.Finished.Add(-curr.Duration), curr.Finished),
+		)
+	}
+	return ret
+}
+
+// nodePullSummary is the distribution of pull durations on a node, written as the artifact.
+type nodePullSummary struct {
+	Node  string        `json:"node"`
+	Pulls int           `json:"pulls"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	Max   time.Duration `json:"max"`
+	// Slowest is the image of the longest pull.
+	Slowest string `json:"slowest"`
+}
+
+func summarizeByNode(pulls []pull) []nodePullSummary {
+	byNode := map[string][]pull{}
+	for _, curr := range pulls {
+		byNode[curr.Node] = append(byNode[curr.Node], curr)
+	}
+
+	ret := []nodePullSummary{}
+	for node, nodePulls := range byNode {
+		sort.Slice(nodePulls, func(i, j int) bool { return nodePulls[i].Duration < nodePulls[j].Duration })
+		slowest := nodePulls[len(nodePulls)-1]
+		ret = append(ret, nodePullSummary{
+			Node:    node,
+			Pulls:   len(nodePulls),
+			P50:     nodePulls[percentileIndex(len(nodePulls), 0.50)].Duration,
+			P95:     nodePulls[percentileIndex(len(nodePulls), 0.95)].Duration,
+			Max:     slowest.Duration,
+			Slowest: slowest.Image,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Node < ret[j].Node })
+	return ret
+}
+
+// percentileIndex is the nearest rank of the percentile in a sorted list of length n.
+func percentileIndex(n int, percentile float64) int {
+	ret := int(float64(n)*percentile+0.5) - 1
+	if ret < 0 {
+		return 0
+	}
+	if ret >= n {
+		return n - 1
+	}
+	return ret
+}
@@ -0,0 +1,59 @@
+package imagepulllatency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func pulledEvent(offset time.Duration, node, message string) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+		Locator(monitorapi.Locator{Type: monitorapi.LocatorTypeKind, Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorNamespaceKey: "e2e-test",
+			monitorapi.LocatorPodKey:       "pod",
+			monitorapi.LocatorNodeKey:      node,
+		}}).
+		Message(monitorapi.NewMessage().Reason("Pulled").HumanMessage(message)).
+		Build(start.Add(offset), start.Add(offset))
+}
+
+func TestPulls(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		pulledEvent(time.Minute, "worker-0", `Successfully pulled image "quay.io/a/b@sha256:1" in 2.5s (2.5s including waiting)`),
+		pulledEvent(2*time.Minute, "worker-0", `Successfully pulled image "quay.io/a/c@sha256:2" in 1m30.5s (3m0s including waiting)`),
+		pulledEvent(10*time.Minute, "worker-1", `Successfully pulled image "quay.io/a/d:latest" in 6m0.25s`),
+		pulledEvent(11*time.Minute, "worker-1", `Container image "quay.io/a/d:latest" already present on machine`),
+	}
+
+	pulls := pullsFromEvents(intervals)
+	if len(pulls) != 3 {
+		t.Fatalf("expected three pulls, got %v", pulls)
+	}
+	if pulls[1].Duration != 90500*time.Millisecond {
+		t.Errorf("expected the pull without waiting, got %v", pulls[1].Duration)
+	}
+
+	slow := slowPullIntervals(pulls)
+	if len(slow) != 2 {
+		t.Fatalf("expected two slow pulls, got %v", slow)
+	}
+	if !slow[0].From.Equal(start.Add(30*time.Second-500*time.Millisecond)) || !slow[0].To.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected the slow pull to cover the pull, got %v to %v", slow[0].From, slow[0].To)
+	}
+	if slow[0].Level != monitorapi.Warning || slow[1].Level != monitorapi.Error {
+		t.Errorf("expected only the pull over %v to be an error, got %v and %v", maxPullDuration, slow[0].Level, slow[1].Level)
+	}
+
+	junit := evaluatePulls(slow)
+	if junit.FailureOutput == nil || len(junit.Details.IntervalIDs) != 1 {
+		t.Errorf("expected the pull over %v to fail, got %#v", maxPullDuration, junit.FailureOutput)
+	}
+
+	summaries := summarizeByNode(pulls)
+	if len(summaries) != 2 || summaries[0].Node != "worker-0" || summaries[0].Pulls != 2 || summaries[0].Slowest != "quay.io/a/c@sha256:2" {
+		t.Errorf("unexpected summaries %#v", summaries)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"regexp"
 	"strconv"
@@ -17,6 +16,7 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -38,7 +38,7 @@ func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 			defer wg.Done()
 
 			// TODO limit by begin/end here instead of post-processing
-			nodeLogs, err := getNodeLog(ctx, kubeClient, nodeName, "kubelet")
+			nodeLogs, err := nodeaccess.GetNodeJournal(ctx, kubeClient, nodeName, "kubelet")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error getting node logs from %s: %s", nodeName, err.Error())
 				errCh <- err
@@ -46,7 +46,7 @@ func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 			}
 			newEvents := eventsFromKubeletLogs(nodeName, nodeLogs)
 
-			ovsVswitchdLogs, err := getNodeLog(ctx, kubeClient, nodeName, "ovs-vswitchd")
+			ovsVswitchdLogs, err := nodeaccess.GetNodeJournal(ctx, kubeClient, nodeName, "ovs-vswitchd")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error getting node ovs-vswitchd logs from %s: %s", nodeName, err.Error())
 				errCh <- err
@@ -54,7 +54,7 @@ func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 			}
 			newOVSEvents := eventsFromOVSVswitchdLogs(nodeName, ovsVswitchdLogs)
 
-			networkManagerLogs, err := getNodeLog(ctx, kubeClient, nodeName, "NetworkManager")
+			networkManagerLogs, err := nodeaccess.GetNodeJournal(ctx, kubeClient, nodeName, "NetworkManager")
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error getting node NetworkManager logs from %s: %s", nodeName, err.Error())
 				errCh <- err
@@ -101,6 +101,8 @@ func eventsFromKubeletLogs(nodeName string, kubeletLog []byte) monitorapi.Interv
 		ret = append(ret, failedToDeleteCGroupsPath(nodeLocator, currLine)...)
 		ret = append(ret, anonymousCertConnectionError(nodeLocator, currLine)...)
 		ret = append(ret, leaseUpdateError(nodeLocator, currLine)...)
+		ret = append(ret, kubeletServiceLifecycle(nodeLocator, currLine)...)
+		ret = append(ret, killingPlatformContainer(nodeName, currLine)...)
 	}
 
 	return ret
@@ -130,7 +132,7 @@ func unreasonablyLongPollInterval(logLine string, nodeLocator monitorapi.Locator
 		return nil
 	}
 
-	toTime := systemdJournalLogTime(logLine)
+	toTime := nodeaccess.SystemdJournalLogTime(logLine)
 
 	// Extract the number of millis and use it for the interval, starting from the point we logged
 	// and looking backwards.
@@ -181,7 +183,7 @@ func tooManyNetlinkEvents(logLine string, nodeLocator monitorapi.Locator) monito
 		return nil
 	}
 
-	logTime := systemdJournalLogTime(logLine)
+	logTime := nodeaccess.SystemdJournalLogTime(logLine)
 
 	message := logLine[strings.Index(logLine, "NetworkManager"):]
 	return monitorapi.Intervals{
@@ -216,7 +218,7 @@ func readinessFailure(nodeName, logLine string) monitorapi.Intervals {
 	}
 
 	containerRef := probeProblemToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -243,7 +245,7 @@ func readinessError(nodeName, logLine string) monitorapi.Intervals {
 	message, _ = strconv.Unquote(`"` + message + `"`)
 
 	containerRef := probeProblemToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -270,7 +272,7 @@ func errParsingSignature(nodeName, logLine string) monitorapi.Intervals {
 	}
 
 	containerRef := errImagePullToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -316,7 +318,7 @@ func startupProbeError(nodeName, logLine string) monitorapi.Intervals {
 	}
 
 	containerRef := probeProblemToContainerReference(logLine)
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(containerRef).
@@ -421,7 +423,7 @@ func failedToDeleteCGroupsPath(nodeLocator monitorapi.Locator, logLine string) m
 		return nil
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Error).
@@ -432,12 +434,72 @@ func failedToDeleteCGroupsPath(nodeLocator monitorapi.Locator, logLine string) m
 	}
 }
 
+// kubeletServiceLifecycle records systemd stopping and starting the kubelet, so that kubelet restarts can be told
+// apart from a node that stopped reporting.
+//
+// Apr 12 11:53:51.395838 ci-op-xs3rnrtc-2d4c7-4mhm7-worker-b-dwc7w systemd[1]: Stopping Kubernetes Kubelet...
+// Apr 12 11:53:58.012345 ci-op-xs3rnrtc-2d4c7-4mhm7-worker-b-dwc7w systemd[1]: Started Kubernetes Kubelet.
+func kubeletServiceLifecycle(nodeLocator monitorapi.Locator, logLine string) monitorapi.Intervals {
+	if !strings.Contains(logLine, "systemd[1]:") {
+		return nil
+	}
+
+	var reason monitorapi.IntervalReason
+	switch {
+	case strings.Contains(logLine, "Stopping Kubernetes Kubelet"), strings.Contains(logLine, "Stopping kubelet.service"):
+		reason = monitorapi.KubeletStoppedReason
+	case strings.Contains(logLine, "Started Kubernetes Kubelet"), strings.Contains(logLine, "Started kubelet.service"):
+		reason = monitorapi.KubeletStartedReason
+	default:
+		return nil
+	}
+
+	eventTime := nodeaccess.SystemdJournalLogTime(logLine)
+
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
+			Locator(nodeLocator).
+			Message(monitorapi.NewMessage().Reason(reason).HumanMessage(logLine)).
+			Build(eventTime, eventTime.Add(1*time.Second)),
+	}
+}
+
+var killingGracePeriodRegex = regexp.MustCompile(`gracePeriod=(\d+)`)
+
+// killingPlatformContainer records the kubelet stopping a container of the platform, with the grace period the
+// container has to exit before it is killed.  Only platform namespaces are recorded, every pod deletion stops containers.
+//
+// Sep 27 08:59:59.857303 ci-op-747jjqn3-b3af3-f45pk-master-0 kubenswrapper[2397]: I0927 08:59:59.850662    2397 kuberuntime_container.go:742] "Killing container with a grace period" pod="openshift-kube-apiserver/kube-apiserver-master-0" podUID="a1947638-25c2-4fd8-b3c8-4dbaa666bc61" containerName="kube-apiserver" containerID="cri-o://4c2d" gracePeriod=135
+func killingPlatformContainer(nodeName, logLine string) monitorapi.Intervals {
+	if !strings.Contains(logLine, `"Killing container with a grace period"`) {
+		return nil
+	}
+	if !strings.Contains(logLine, `pod="openshift-`) {
+		return nil
+	}
+	gracePeriod := killingGracePeriodRegex.FindStringSubmatch(logLine)
+	if gracePeriod == nil {
+		return nil
+	}
+
+	containerRef := probeProblemToContainerReference(logLine)
+	killTime := nodeaccess.SystemdJournalLogTime(logLine)
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
+			Locator(containerRef).
+			Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonKilling).Node(nodeName).
+				WithAnnotation(monitorapi.AnnotationGracePeriod, gracePeriod[1]).
+				HumanMessagef("stopping container with a grace period of %ss", gracePeriod[1])).
+			Build(killTime, killTime),
+	}
+}
+
 func anonymousCertConnectionError(nodeLocator monitorapi.Locator, logLine string) monitorapi.Intervals {
 	if !strings.Contains(logLine, "User \"system:anonymous\"") {
 		return nil
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Error).
@@ -462,7 +524,7 @@ func leaseUpdateError(nodeLocator monitorapi.Locator, logLine string) monitorapi
 		return nil
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 	url := ""
 	msg := ""
 
@@ -533,7 +595,7 @@ func commonErrorInterval(nodeName, logLine string, messageExp *regexp.Regexp, re
 		message = unquotedMessage
 	}
 
-	failureTime := systemdJournalLogTime(logLine)
+	failureTime := nodeaccess.SystemdJournalLogTime(logLine)
 	return monitorapi.Intervals{
 		monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
 			Locator(locator()).
@@ -544,61 +606,3 @@ func commonErrorInterval(nodeName, logLine string, messageExp *regexp.Regexp, re
 			Build(failureTime, failureTime),
 	}
 }
-
-var kubeletTimeRegex = regexp.MustCompile(`^(?P<MONTH>\S+)\s(?P<DAY>\S+)\s(?P<TIME>\S+)`)
-
-// systemdJournalLogTime returns Now if there is trouble reading the time.  This will stack the event intervals without
-// parsable times at the end of the run, which will be more clearly visible as a problem than not reporting them.
-func systemdJournalLogTime(logLine string) time.Time {
-	kubeletTimeRegex.MatchString(logLine)
-	if !kubeletTimeRegex.MatchString(logLine) {
-		return time.Now()
-	}
-
-	month := ""
-	day := ""
-	year := fmt.Sprintf("%d", time.Now().Year())
-	timeOfDay := ""
-	subMatches := kubeletTimeRegex.FindStringSubmatch(logLine)
-	subNames := kubeletTimeRegex.SubexpNames()
-	for i, name := range subNames {
-		switch name {
-		case "MONTH":
-			month = subMatches[i]
-		case "DAY":
-			day = subMatches[i]
-		case "TIME":
-			timeOfDay = subMatches[i]
-		}
-	}
-
-	timeString := fmt.Sprintf("%s %s %s %s UTC", day, month, year, timeOfDay)
-	ret, err := time.Parse("02 Jan 2006 15:04:05.999999999 MST", timeString)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failure parsing time format: %v for %q\n", err, timeString)
-		return time.Now()
-	}
-
-	return ret
-}
-
-// getNodeLog returns logs for a particular systemd service on a given node.
-// We're count on these logs to fit into some reasonable memory size.
-func getNodeLog(ctx context.Context, client kubernetes.Interface, nodeName, systemdServiceName string) ([]byte, error) {
-	path := client.CoreV1().RESTClient().Get().
-		Namespace("").Name(nodeName).
-		Resource("nodes").SubResource("proxy", "logs").Suffix("journal").URL().Path
-
-	req := client.CoreV1().RESTClient().Get().RequestURI(path).
-		SetHeader("Accept", "text/plain, */*")
-	req.Param("since", "-1d")
-	req.Param("unit", systemdServiceName)
-
-	in, err := req.Stream(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer in.Close()
-
-	return ioutil.ReadAll(in)
-}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,8 +45,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Sep 27 08:59:59.857303"),
-				To:   systemdJournalLogTime("Sep 27 08:59:59.857303"),
+				From: nodeaccess.SystemdJournalLogTime("Sep 27 08:59:59.857303"),
+				To:   nodeaccess.SystemdJournalLogTime("Sep 27 08:59:59.857303"),
 			},
 		},
 		{
@@ -72,8 +73,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Sep 27 08:59:59.853216"),
-				To:   systemdJournalLogTime("Sep 27 08:59:59.853216"),
+				From: nodeaccess.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
+				To:   nodeaccess.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
 			},
 		},
 		{
@@ -98,8 +99,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Sep 27 08:59:59.853216"),
-				To:   systemdJournalLogTime("Sep 27 08:59:59.853216"),
+				From: nodeaccess.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
+				To:   nodeaccess.SystemdJournalLogTime("Sep 27 08:59:59.853216"),
 			},
 		},
 		{
@@ -123,8 +124,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("May 19 19:10:03.753983"),
-				To:   systemdJournalLogTime("May 19 19:10:04.753983"),
+				From: nodeaccess.SystemdJournalLogTime("May 19 19:10:03.753983"),
+				To:   nodeaccess.SystemdJournalLogTime("May 19 19:10:04.753983"),
 			},
 		},
 		{
@@ -148,8 +149,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Jun 29 05:16:54.197389"),
-				To:   systemdJournalLogTime("Jun 29 05:16:55.197389"),
+				From: nodeaccess.SystemdJournalLogTime("Jun 29 05:16:54.197389"),
+				To:   nodeaccess.SystemdJournalLogTime("Jun 29 05:16:55.197389"),
 			},
 		},
 		{
@@ -177,8 +178,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Jul 05 17:47:52.807876"),
-				To:   systemdJournalLogTime("Jul 05 17:47:52.807876"),
+				From: nodeaccess.SystemdJournalLogTime("Jul 05 17:47:52.807876"),
+				To:   nodeaccess.SystemdJournalLogTime("Jul 05 17:47:52.807876"),
 			},
 		},
 		{
@@ -206,8 +207,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Jul 05 17:43:12.908344"),
-				To:   systemdJournalLogTime("Jul 05 17:43:12.908344"),
+				From: nodeaccess.SystemdJournalLogTime("Jul 05 17:43:12.908344"),
+				To:   nodeaccess.SystemdJournalLogTime("Jul 05 17:43:12.908344"),
 			},
 		},
 		{
@@ -237,8 +238,8 @@ func TestMonitorApiIntervals(t *testing.T) {
 						},
 					},
 				},
-				From: systemdJournalLogTime("Feb 01 05:37:45.731611"),
-				To:   systemdJournalLogTime("Feb 01 05:37:45.731611"),
+				From: nodeaccess.SystemdJournalLogTime("Feb 01 05:37:45.731611"),
+				To:   nodeaccess.SystemdJournalLogTime("Feb 01 05:37:45.731611"),
 			},
 		},
 		{
@@ -261,8 +262,33 @@ func TestMonitorApiIntervals(t *testing.T) {
 						Annotations:  map[monitorapi.AnnotationKey]string{},
 					},
 				},
-				From: systemdJournalLogTime("Apr 12 11:49:49.188086"),
-				To:   systemdJournalLogTime("Apr 12 11:49:50.188086"),
+				From: nodeaccess.SystemdJournalLogTime("Apr 12 11:49:49.188086"),
+				To:   nodeaccess.SystemdJournalLogTime("Apr 12 11:49:50.188086"),
+			},
+		},
+		{
+			name:          "kubelet started",
+			logLine:       `Apr 12 11:53:58.012345 ci-op-xs3rnrtc-2d4c7-4mhm7-worker-b-dwc7w systemd[1]: Started Kubernetes Kubelet.`,
+			generatorFunc: eventsFromKubeletLogs,
+			want: monitorapi.Interval{
+				Condition: monitorapi.Condition{
+					Level: monitorapi.Info,
+					Locator: monitorapi.Locator{
+						Type: monitorapi.LocatorTypeNode,
+						Keys: map[monitorapi.LocatorKey]string{
+							"node": "testName",
+						},
+					},
+					Message: monitorapi.Message{
+						Reason:       "KubeletStarted",
+						HumanMessage: `Apr 12 11:53:58.012345 ci-op-xs3rnrtc-2d4c7-4mhm7-worker-b-dwc7w systemd[1]: Started Kubernetes Kubelet.`,
+						Annotations: map[monitorapi.AnnotationKey]string{
+							monitorapi.AnnotationReason: "KubeletStarted",
+						},
+					},
+				},
+				From: nodeaccess.SystemdJournalLogTime("Apr 12 11:53:58.012345"),
+				To:   nodeaccess.SystemdJournalLogTime("Apr 12 11:53:59.012345"),
 			},
 		},
 	}
@@ -489,8 +515,8 @@ func Test_messageTime(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := systemdJournalLogTime(tt.args.logLine); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("systemdJournalLogTime() = %v, want %v", got, tt.want)
+			if got := nodeaccess.SystemdJournalLogTime(tt.args.logLine); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nodeaccess.SystemdJournalLogTime() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -554,3 +580,61 @@ func Test_readinessFailure(t *testing.T) {
 		})
 	}
 }
+
+func Test_killingPlatformContainer(t *testing.T) {
+	tests := []struct {
+		name    string
+		logLine string
+		want    monitorapi.Intervals
+	}{
+		{
+			name:    "platform container",
+			logLine: `Sep 27 08:59:59.857303 ci-op-747jjqn3-b3af3-f45pk-master-0 kubenswrapper[2397]: I0927 08:59:59.850662    2397 kuberuntime_container.go:742] "Killing container with a grace period" pod="openshift-kube-apiserver/kube-apiserver-master-0" podUID="a1947638-25c2-4fd8-b3c8-4dbaa666bc61" containerName="kube-apiserver" containerID="cri-o://4c2d" gracePeriod=135`,
+			want: monitorapi.Intervals{
+				{
+					Condition: monitorapi.Condition{
+						Level: monitorapi.Info,
+						Locator: monitorapi.Locator{
+							Type: monitorapi.LocatorTypeContainer,
+							Keys: map[monitorapi.LocatorKey]string{
+								"namespace": "openshift-kube-apiserver",
+								"pod":       "kube-apiserver-master-0",
+								"uid":       "a1947638-25c2-4fd8-b3c8-4dbaa666bc61",
+								"container": "kube-apiserver",
+							},
+						},
+						Message: monitorapi.Message{
+							Reason:       monitorapi.ContainerReasonKilling,
+							HumanMessage: "stopping container with a grace period of 135s",
+							Annotations: map[monitorapi.AnnotationKey]string{
+								monitorapi.AnnotationReason:      string(monitorapi.ContainerReasonKilling),
+								monitorapi.AnnotationNode:        "fakenode",
+								monitorapi.AnnotationGracePeriod: "135",
+							},
+						},
+					},
+					From: mustTime(fmt.Sprintf("27 Sep %d 08:59:59.857303 UTC", time.Now().Year())),
+					To:   mustTime(fmt.Sprintf("27 Sep %d 08:59:59.857303 UTC", time.Now().Year())),
+				},
+			},
+		},
+		{
+			name:    "workload container",
+			logLine: `Sep 27 08:59:59.857303 ci-op-747jjqn3-b3af3-f45pk-worker-0 kubenswrapper[2397]: I0927 08:59:59.850662    2397 kuberuntime_container.go:742] "Killing container with a grace period" pod="e2e-test-abcde/pause" podUID="b1947638-25c2-4fd8-b3c8-4dbaa666bc61" containerName="pause" containerID="cri-o://5d3e" gracePeriod=30`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := killingPlatformContainer("fakenode", tt.logLine)
+			if len(tt.want) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want[0].Locator, got[0].Locator)
+			assert.Equal(t, tt.want[0].Message, got[0].Message)
+			assert.Equal(t, tt.want[0].Level, got[0].Level)
+			assert.Equal(t, tt.want[0].From, got[0].From)
+			assert.Equal(t, tt.want[0].To, got[0].To)
+		})
+	}
+}
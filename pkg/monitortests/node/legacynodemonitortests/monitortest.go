@@ -20,6 +20,12 @@ func NewLegacyTests() monitortestframework.MonitorTest {
 	return &legacyMonitorTests{}
 }
 
+var _ monitortestframework.OfflineMonitorTest = &legacyMonitorTests{}
+
+func (*legacyMonitorTests) SupportsOffline() bool {
+	return true
+}
+
 func (w *legacyMonitorTests) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	return nil
 }
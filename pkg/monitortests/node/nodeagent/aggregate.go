@@ -0,0 +1,60 @@
+package nodeagent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const streamTestName = "[sig-node] node agents should stream intervals from every node"
+
+// nodeResult is what the agent of one node delivered.
+type nodeResult struct {
+	// intervals counts the intervals received from the agent.
+	intervals int
+	// flushed is set once the agent confirmed every interval it observed was received.
+	flushed bool
+	// flushErr is why the agent could not be flushed.
+	flushErr error
+}
+
+// evaluateNodes fails when no agent delivered its intervals, and flakes when only some nodes did, because the intervals
+// of those nodes are incomplete rather than wrong.
+func evaluateNodes(nodeNames []string, results map[string]*nodeResult) []*junitapi.JUnitTestCase {
+	nodeNames = append([]string{}, nodeNames...)
+	sort.Strings(nodeNames)
+
+	summary := &strings.Builder{}
+	missing := []string{}
+	for _, nodeName := range nodeNames {
+		result, ok := results[nodeName]
+		switch {
+		case !ok:
+			missing = append(missing, fmt.Sprintf("node/%s: no agent was reached", nodeName))
+			fmt.Fprintf(summary, "node/%s: no agent\n", nodeName)
+		case !result.flushed:
+			missing = append(missing, fmt.Sprintf("node/%s: %v", nodeName, result.flushErr))
+			fmt.Fprintf(summary, "node/%s: %d intervals, incomplete\n", nodeName, result.intervals)
+		default:
+			fmt.Fprintf(summary, "node/%s: %d intervals\n", nodeName, result.intervals)
+		}
+	}
+
+	passed := &junitapi.JUnitTestCase{Name: streamTestName, SystemOut: summary.String()}
+	if len(missing) == 0 {
+		return []*junitapi.JUnitTestCase{passed}
+	}
+	failed := &junitapi.JUnitTestCase{
+		Name:      streamTestName,
+		SystemOut: summary.String(),
+		FailureOutput: &junitapi.FailureOutput{
+			Output: fmt.Sprintf("intervals of %d of %d nodes are incomplete:\n%s", len(missing), len(nodeNames), strings.Join(missing, "\n")),
+		},
+	}
+	if len(missing) == len(nodeNames) {
+		return []*junitapi.JUnitTestCase{failed}
+	}
+	return []*junitapi.JUnitTestCase{failed, passed}
+}
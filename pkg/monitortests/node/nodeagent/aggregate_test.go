@@ -0,0 +1,55 @@
+package nodeagent
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEvaluateNodes(t *testing.T) {
+	nodeNames := []string{"worker-1", "worker-0"}
+	tests := []struct {
+		name     string
+		results  map[string]*nodeResult
+		failures int
+		passes   int
+	}{
+		{
+			name: "every node delivered",
+			results: map[string]*nodeResult{
+				"worker-0": {intervals: 3, flushed: true},
+				"worker-1": {flushed: true},
+			},
+			passes: 1,
+		},
+		{
+			name: "a node is missing",
+			results: map[string]*nodeResult{
+				"worker-0": {intervals: 3, flushed: true},
+			},
+			failures: 1,
+			passes:   1,
+		},
+		{
+			name: "no node delivered",
+			results: map[string]*nodeResult{
+				"worker-0": {intervals: 3, flushErr: fmt.Errorf("timed out")},
+			},
+			failures: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures, passes := 0, 0
+			for _, junit := range evaluateNodes(nodeNames, tt.results) {
+				if junit.FailureOutput != nil {
+					failures++
+				} else {
+					passes++
+				}
+			}
+			if failures != tt.failures || passes != tt.passes {
+				t.Errorf("expected %d failures and %d passes, got %d and %d", tt.failures, tt.passes, failures, passes)
+			}
+		})
+	}
+}
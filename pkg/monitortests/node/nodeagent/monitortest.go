@@ -0,0 +1,343 @@
+package nodeagent
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitor/nodeagent"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortests/network/disruptionpodnetwork"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+var (
+	//go:embed *.yaml
+	yamls embed.FS
+
+	namespace      *corev1.Namespace
+	agentDaemonSet *appsv1.DaemonSet
+)
+
+func yamlOrDie(name string) []byte {
+	ret, err := yamls.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
+func init() {
+	namespace = resourceread.ReadNamespaceV1OrDie(yamlOrDie("namespace.yaml"))
+	agentDaemonSet = resourceread.ReadDaemonSetV1OrDie(yamlOrDie("agent-daemonset.yaml"))
+}
+
+const (
+	agentLabelSelector = "monitor.openshift.io/node-agent=agent"
+	// syncInterval is how often agent pods are looked for, so agents on nodes that join or pods that are replaced
+	// are collected from.
+	syncInterval = 15 * time.Second
+	flushTimeout = 2 * time.Minute
+)
+
+// agentCollector streams the intervals of the agent in one pod.
+type agentCollector struct {
+	nodeName  string
+	collector *nodeagent.Collector
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+type nodeAgentMonitor struct {
+	payloadImagePullSpec string
+	notSupportedReason   error
+
+	adminRESTConfig *rest.Config
+	kubeClient      kubernetes.Interface
+	namespaceName   string
+	recorder        monitorapi.RecorderWriter
+
+	stopSyncing context.CancelFunc
+	syncingDone chan struct{}
+
+	lock sync.Mutex
+	// collectors are keyed by the name of the agent pod.
+	collectors map[string]*agentCollector
+	results    map[string]*nodeResult
+}
+
+// NewNodeAgent deploys an agent on every node that probes disk latency, conntrack saturation and DNS resolution from
+// the node, and streams the intervals of the failing probes to the recorder while the monitor runs.
+func NewNodeAgent(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	ret := &nodeAgentMonitor{
+		payloadImagePullSpec: info.UpgradeTargetPayloadImagePullSpec,
+		collectors:           map[string]*agentCollector{},
+		results:              map[string]*nodeResult{},
+	}
+	if !info.NodeAgent {
+		ret.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "the node agent was not requested"}
+	}
+	return ret
+}
+
+func (w *nodeAgentMonitor) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: []string{streamTestName},
+	}
+}
+
+func (w *nodeAgentMonitor) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	if w.notSupportedReason != nil {
+		return w.notSupportedReason
+	}
+	var err error
+	w.adminRESTConfig = adminRESTConfig
+	w.recorder = recorder
+	w.kubeClient, err = kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	isMicroShift, err := exutil.IsMicroShiftCluster(w.kubeClient)
+	if err != nil {
+		return fmt.Errorf("unable to determine if cluster is MicroShift: %v", err)
+	}
+	if isMicroShift {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: "platform MicroShift not supported",
+		}
+		return w.notSupportedReason
+	}
+
+	openshiftTestsImagePullSpec, err := disruptionpodnetwork.GetOpenshiftTestsImagePullSpec(ctx, adminRESTConfig, w.payloadImagePullSpec, nil)
+	if err != nil {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("unable to determine openshift-tests image: %v", err),
+		}
+		return w.notSupportedReason
+	}
+
+	actualNamespace, err := w.kubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	w.namespaceName = actualNamespace.Name
+
+	daemonSet := agentDaemonSet.DeepCopy()
+	daemonSet.Spec.Template.Spec.Containers[0].Image = openshiftTestsImagePullSpec
+	if _, err := w.kubeClient.AppsV1().DaemonSets(w.namespaceName).Create(ctx, daemonSet, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	syncCtx, cancel := context.WithCancel(ctx)
+	w.stopSyncing = cancel
+	w.syncingDone = make(chan struct{})
+	go func() {
+		defer close(w.syncingDone)
+		wait.UntilWithContext(syncCtx, w.syncCollectors, syncInterval)
+		w.stopCollectors(func(string) bool { return true })
+	}()
+	return nil
+}
+
+// syncCollectors starts collecting from new agent pods, and stops collecting from deleted ones.
+func (w *nodeAgentMonitor) syncCollectors(ctx context.Context) {
+	pods, err := w.kubeClient.CoreV1().Pods(w.namespaceName).List(ctx, metav1.ListOptions{LabelSelector: agentLabelSelector})
+	if err != nil {
+		klog.Errorf("Unable to list node agents: %v", err)
+		return
+	}
+
+	existing := map[string]bool{}
+	for _, pod := range pods.Items {
+		existing[pod.Name] = true
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Spec.NodeName) == 0 {
+			continue
+		}
+		w.startCollector(ctx, pod.Name, pod.Spec.NodeName)
+	}
+	w.stopCollectors(func(podName string) bool { return !existing[podName] })
+}
+
+func (w *nodeAgentMonitor) startCollector(ctx context.Context, podName, nodeName string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, ok := w.collectors[podName]; ok {
+		return
+	}
+	if _, ok := w.results[nodeName]; !ok {
+		w.results[nodeName] = &nodeResult{}
+	}
+
+	collectorCtx, cancel := context.WithCancel(ctx)
+	collector := &agentCollector{
+		nodeName:  nodeName,
+		collector: nodeagent.NewCollector(podDialer(w.adminRESTConfig, w.kubeClient, w.namespaceName, podName), w.record),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	w.collectors[podName] = collector
+	go func() {
+		defer close(collector.done)
+		collector.collector.Run(collectorCtx)
+	}()
+}
+
+func (w *nodeAgentMonitor) stopCollectors(shouldStop func(podName string) bool) {
+	w.lock.Lock()
+	stopped := []*agentCollector{}
+	for podName, collector := range w.collectors {
+		if shouldStop(podName) {
+			collector.cancel()
+			stopped = append(stopped, collector)
+			delete(w.collectors, podName)
+		}
+	}
+	w.lock.Unlock()
+
+	for _, collector := range stopped {
+		<-collector.done
+	}
+}
+
+// record adds an interval streamed by an agent to the recorder, and counts it for the node.
+func (w *nodeAgentMonitor) record(nodeName string, interval monitorapi.Interval) {
+	w.recorder.AddIntervals(interval)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, ok := w.results[nodeName]; !ok {
+		w.results[nodeName] = &nodeResult{}
+	}
+	w.results[nodeName].intervals++
+}
+
+// flush waits until every agent has delivered the intervals it observed.  An agent that is not connected yet is
+// retried until the timeout.
+func (w *nodeAgentMonitor) flush(ctx context.Context) {
+	w.lock.Lock()
+	collectors := []*agentCollector{}
+	for _, collector := range w.collectors {
+		collectors = append(collectors, collector)
+	}
+	w.lock.Unlock()
+
+	wg := sync.WaitGroup{}
+	for _, collector := range collectors {
+		wg.Add(1)
+		go func(collector *agentCollector) {
+			defer wg.Done()
+			var lastErr error
+			err := wait.PollUntilContextTimeout(ctx, 2*time.Second, flushTimeout, true, func(ctx context.Context) (bool, error) {
+				lastErr = collector.collector.Flush(ctx)
+				return lastErr == nil, nil
+			})
+			if err != nil && lastErr != nil {
+				err = lastErr
+			}
+
+			w.lock.Lock()
+			defer w.lock.Unlock()
+			result := w.results[collector.nodeName]
+			if err != nil {
+				result.flushErr = err
+				return
+			}
+			result.flushed = true
+			result.flushErr = nil
+		}(collector)
+	}
+	wg.Wait()
+}
+
+func (w *nodeAgentMonitor) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	// pick up agents that started since the last sync.
+	w.syncCollectors(ctx)
+	w.flush(ctx)
+	w.stopSyncing()
+	<-w.syncingDone
+
+	nodes, err := w.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	nodeNames := []string{}
+	for _, node := range nodes.Items {
+		nodeNames = append(nodeNames, node.Name)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	// the intervals were added to the recorder as they were streamed.
+	return nil, evaluateNodes(nodeNames, w.results), nil
+}
+
+func (w *nodeAgentMonitor) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *nodeAgentMonitor) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *nodeAgentMonitor) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *nodeAgentMonitor) namespaceDeleted(ctx context.Context) (bool, error) {
+	_, err := w.kubeClient.CoreV1().Namespaces().Get(ctx, w.namespaceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		klog.Errorf("Error checking for deleted namespace: %s, %s", w.namespaceName, err.Error())
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (w *nodeAgentMonitor) Cleanup(ctx context.Context) error {
+	if w.stopSyncing != nil {
+		w.stopSyncing()
+		<-w.syncingDone
+	}
+	if len(w.namespaceName) > 0 && w.kubeClient != nil {
+		if err := w.kubeClient.CoreV1().Namespaces().Delete(ctx, w.namespaceName, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+
+		startTime := time.Now()
+		if err := wait.PollUntilContextTimeout(ctx, 15*time.Second, 20*time.Minute, true, w.namespaceDeleted); err != nil {
+			return err
+		}
+
+		klog.Infof("Deleting namespace: %s took %.2f seconds", w.namespaceName, time.Now().Sub(startTime).Seconds())
+	}
+	return w.notSupportedReason
+}
@@ -0,0 +1,74 @@
+package nodeagent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/openshift/origin/pkg/monitor/nodeagent"
+)
+
+// agentPort is the port the agents serve on, see agent-daemonset.yaml.
+const agentPort = 9797
+
+// podDialer connects to the agent in a pod through a port forward, because the monitor usually runs outside the
+// cluster and cannot reach pods directly.
+func podDialer(adminRESTConfig *rest.Config, kubeClient kubernetes.Interface, namespace, pod string) nodeagent.Dialer {
+	return func(ctx context.Context) (grpc.ClientConnInterface, func(), error) {
+		u := kubeClient.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward").URL()
+		transport, upgrader, err := spdy.RoundTripperFor(adminRESTConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", u)
+
+		stopCh, readyCh := make(chan struct{}), make(chan struct{})
+		forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("0:%d", agentPort)}, stopCh, readyCh, io.Discard, io.Discard)
+		if err != nil {
+			return nil, nil, err
+		}
+		forwardErrCh := make(chan error, 1)
+		go func() {
+			forwardErrCh <- forwarder.ForwardPorts()
+		}()
+		stopForwarding := func() {
+			close(stopCh)
+			<-forwardErrCh
+		}
+
+		select {
+		case <-readyCh:
+		case err := <-forwardErrCh:
+			// put it back for stopForwarding.
+			forwardErrCh <- err
+			stopForwarding()
+			return nil, nil, fmt.Errorf("unable to forward port %d of pod/%s: %w", agentPort, pod, err)
+		case <-ctx.Done():
+			stopForwarding()
+			return nil, nil, ctx.Err()
+		}
+		ports, err := forwarder.GetPorts()
+		if err != nil {
+			stopForwarding()
+			return nil, nil, err
+		}
+
+		conn, err := grpc.DialContext(ctx, fmt.Sprintf("localhost:%d", ports[0].Local), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			stopForwarding()
+			return nil, nil, err
+		}
+		return conn, func() {
+			conn.Close()
+			stopForwarding()
+		}, nil
+	}
+}
@@ -0,0 +1,95 @@
+package nodejournalscanner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var journalPatternTemplate = junitfailure.MustParseTemplate("node-journal-pattern",
+	`{{.Fields.unit}} logged lines matching {{.Fields.pattern}} more than {{.Fields.allowed}} times on a node:
+{{range .Fields.nodes}}{{.}}
+{{end}}{{if .Fields.bug}}This is a known issue on this cluster, see {{.Fields.bug}}: {{.Fields.reason}}
+{{end}}
+{{.IntervalList}}`)
+
+func testNameFor(pattern journalPattern) string {
+	return fmt.Sprintf("[sig-node] node journals should not report %s", pattern.Description)
+}
+
+// evaluatePatterns produces a test for every pattern of the library.  A pattern matching more often on a node than
+// it allows fails, unless a known issue for the cluster allows as many matches, which flakes instead.
+func evaluatePatterns(library *patternLibrary, jobType platformidentification.JobType, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	matchesByPattern := map[string]map[string]int{}
+	intervalsByPattern := map[string]monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceNodeJournal {
+			continue
+		}
+		pattern := string(interval.Message.Reason)
+		count, err := strconv.Atoi(interval.Message.Annotations[monitorapi.AnnotationCount])
+		if err != nil {
+			continue
+		}
+		if matchesByPattern[pattern] == nil {
+			matchesByPattern[pattern] = map[string]int{}
+		}
+		matchesByPattern[pattern][interval.Locator.Keys[monitorapi.LocatorNodeKey]] += count
+		intervalsByPattern[pattern] = append(intervalsByPattern[pattern], interval)
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for i := range library.Patterns {
+		pattern := &library.Patterns[i]
+		testName := testNameFor(*pattern)
+
+		nodes := []string{}
+		mostMatches := 0
+		for node, count := range matchesByPattern[pattern.Name] {
+			if count <= pattern.MaxMatchesPerNode {
+				continue
+			}
+			nodes = append(nodes, fmt.Sprintf("%d lines on node/%s", count, node))
+			if count > mostMatches {
+				mostMatches = count
+			}
+		}
+		if len(nodes) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+		sort.Strings(nodes)
+
+		failure := junitfailure.NewFailure(journalPatternTemplate, pattern.Name).
+			Threshold(junitapi.JUnitThreshold{
+				Name:     "matches-per-node",
+				Observed: float64(mostMatches),
+				Limit:    float64(pattern.MaxMatchesPerNode),
+				Unit:     "lines",
+			}).
+			Field("unit", pattern.Unit).
+			Field("pattern", pattern.Name).
+			Field("allowed", pattern.MaxMatchesPerNode).
+			Field("nodes", nodes).
+			Field("bug", "").
+			Field("reason", "").
+			Intervals(intervalsByPattern[pattern.Name]...)
+
+		issue := pattern.knownIssueFor(jobType)
+		if issue != nil && mostMatches <= issue.MaxMatchesPerNode {
+			failure.Field("bug", issue.Bug).Field("reason", issue.Reason)
+			ret = append(ret, failure.TestCase(testName), &junitapi.JUnitTestCase{Name: testName})
+			continue
+		}
+		junit := failure.TestCase(testName)
+		// a warning until the limits are known to hold in CI.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
@@ -0,0 +1,107 @@
+package nodejournalscanner
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type nodeJournalScanner struct {
+	adminRESTConfig *rest.Config
+	jobType         platformidentification.JobType
+	library         *patternLibrary
+	notSupported    bool
+}
+
+// NewNodeJournalScanner searches the kubelet and crio journals of every node for a library of known error patterns
+// and produces a test per pattern.
+func NewNodeJournalScanner() monitortestframework.MonitorTest {
+	return &nodeJournalScanner{}
+}
+
+func (w *nodeJournalScanner) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	// an invalid library fails the collection instead.
+	if library, err := parsePatternLibrary(defaultJournalPatternsYAML); err == nil {
+		for _, pattern := range library.Patterns {
+			testNames = append(testNames, testNameFor(pattern))
+		}
+	}
+	return monitortestframework.MonitorTestDescription{
+		UnsupportedPlatforms: []string{"MicroShift"},
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *nodeJournalScanner) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+
+	var err error
+	w.library, err = parsePatternLibrary(defaultJournalPatternsYAML)
+	if err != nil {
+		return err
+	}
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		// only known issues that match every cluster apply.
+		logrus.WithError(err).Warning("unable to determine the job type for the node journal known issues")
+		return nil
+	}
+	w.jobType = *jobType
+	return nil
+}
+
+func (w *nodeJournalScanner) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.library == nil {
+		return nil, nil, nil
+	}
+	kubeClient, err := kubernetes.NewForConfig(w.adminRESTConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	// MicroShift does not have a proper journal for the node logs api.
+	isMicroShift, err := exutil.IsMicroShiftCluster(kubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isMicroShift {
+		w.notSupported = true
+		return nil, nil, nil
+	}
+
+	intervals, err := scanNodes(ctx, kubeClient, w.library, beginning, end)
+	return intervals, nil, err
+}
+
+func (*nodeJournalScanner) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+func (w *nodeJournalScanner) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.library == nil || w.notSupported {
+		return nil, nil
+	}
+	return evaluatePatterns(w.library, w.jobType, finalIntervals), nil
+}
+
+func (*nodeJournalScanner) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*nodeJournalScanner) Cleanup(ctx context.Context) error {
+	return nil
+}
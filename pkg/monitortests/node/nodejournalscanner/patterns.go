@@ -0,0 +1,115 @@
+package nodejournalscanner
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+//go:embed journal_patterns.yaml
+var defaultJournalPatternsYAML []byte
+
+type patternLibrary struct {
+	Patterns []journalPattern `json:"patterns"`
+}
+
+type journalPattern struct {
+	// Name is the reason of the intervals for the matching lines.
+	Name string `json:"name"`
+	// Unit is the systemd unit whose journal is searched, kubelet or crio.
+	Unit  string `json:"unit"`
+	Regex string `json:"regex"`
+	// Description completes the test name, "node journals should not report ...".
+	Description       string       `json:"description"`
+	MaxMatchesPerNode int          `json:"maxMatchesPerNode"`
+	KnownIssues       []knownIssue `json:"knownIssues,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// knownIssue allows a pattern to match more often on some clusters until the bug is fixed.
+type knownIssue struct {
+	Bug               string   `json:"bug"`
+	Platforms         []string `json:"platforms,omitempty"`
+	Topologies        []string `json:"topologies,omitempty"`
+	MaxMatchesPerNode int      `json:"maxMatchesPerNode"`
+	Reason            string   `json:"reason"`
+}
+
+func parsePatternLibrary(content []byte) (*patternLibrary, error) {
+	ret := &patternLibrary{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for i := range ret.Patterns {
+		pattern := &ret.Patterns[i]
+		switch {
+		case len(pattern.Name) == 0:
+			return nil, fmt.Errorf("pattern %d must have a name", i)
+		case names[pattern.Name]:
+			return nil, fmt.Errorf("pattern %v is defined more than once", pattern.Name)
+		case len(pattern.Unit) == 0:
+			return nil, fmt.Errorf("pattern %v must name the unit whose journal it searches", pattern.Name)
+		case len(pattern.Description) == 0:
+			return nil, fmt.Errorf("pattern %v must have a description", pattern.Name)
+		}
+		names[pattern.Name] = true
+
+		regex, err := regexp.Compile(pattern.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %v: %w", pattern.Name, err)
+		}
+		pattern.regex = regex
+
+		for _, issue := range pattern.KnownIssues {
+			if len(issue.Bug) == 0 {
+				return nil, fmt.Errorf("known issue of pattern %v must link its bug", pattern.Name)
+			}
+			if issue.MaxMatchesPerNode < pattern.MaxMatchesPerNode {
+				return nil, fmt.Errorf("known issue %v of pattern %v must allow at least as many matches as the pattern", issue.Bug, pattern.Name)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// units returns the systemd units any pattern searches, in the order they first appear.
+func (l *patternLibrary) units() []string {
+	ret := []string{}
+	seen := map[string]bool{}
+	for _, pattern := range l.Patterns {
+		if !seen[pattern.Unit] {
+			seen[pattern.Unit] = true
+			ret = append(ret, pattern.Unit)
+		}
+	}
+	return ret
+}
+
+func matchesOrEmpty(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, curr := range values {
+		if curr == value {
+			return true
+		}
+	}
+	return false
+}
+
+// knownIssueFor returns the first known issue of the pattern matching the cluster, or nil.
+func (p *journalPattern) knownIssueFor(jobType platformidentification.JobType) *knownIssue {
+	for i := range p.KnownIssues {
+		issue := &p.KnownIssues[i]
+		if matchesOrEmpty(issue.Platforms, jobType.Platform) && matchesOrEmpty(issue.Topologies, jobType.Topology) {
+			return issue
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package nodejournalscanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/nodeaccess"
+)
+
+// mergeWindow is how close together matches of a pattern on a node must be to share an interval.  Most of these
+// errors repeat every few seconds for as long as the problem lasts.
+const mergeWindow = time.Minute
+
+// maxConcurrentJournalReads bounds the node proxy requests for journals, so large clusters do not send hundreds of
+// them to the apiserver at once.
+const maxConcurrentJournalReads = 10
+
+// scanNodes searches the journals of every node for the patterns of the library.
+func scanNodes(ctx context.Context, kubeClient kubernetes.Interface, library *patternLibrary, beginning, end time.Time) (monitorapi.Intervals, error) {
+	allNodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	type nodeUnit struct {
+		nodeName, unit string
+	}
+	journals := []nodeUnit{}
+	for _, node := range allNodes.Items {
+		for _, unit := range library.units() {
+			journals = append(journals, nodeUnit{nodeName: node.Name, unit: unit})
+		}
+	}
+
+	ret := monitorapi.Intervals{}
+	lock := sync.Mutex{}
+	errCh := make(chan error, len(journals))
+	workqueue.ParallelizeUntil(ctx, maxConcurrentJournalReads, len(journals), func(i int) {
+		nodeName, unit := journals[i].nodeName, journals[i].unit
+		journal, err := nodeaccess.GetNodeJournal(ctx, kubeClient, nodeName, unit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting node %s logs from %s: %s\n", unit, nodeName, err.Error())
+			errCh <- err
+			return
+		}
+		intervals := scanJournal(nodeName, unit, journal, library, beginning, end)
+
+		lock.Lock()
+		defer lock.Unlock()
+		ret = append(ret, intervals...)
+	})
+	close(errCh)
+
+	errs := []error{}
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	return ret, utilerrors.NewAggregate(errs)
+}
+
+// patternMatches are matches of one pattern close enough together to share an interval.
+type patternMatches struct {
+	from      time.Time
+	to        time.Time
+	count     int
+	firstLine string
+}
+
+// scanJournal returns an interval for every run of matches of a pattern in the journal of the unit, ignoring lines
+// logged outside of the run.
+func scanJournal(nodeName, unit string, journal []byte, library *patternLibrary, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	open := map[string]*patternMatches{}
+	flush := func(pattern *journalPattern) {
+		matches := open[pattern.Name]
+		if matches == nil {
+			return
+		}
+		delete(open, pattern.Name)
+		ret = append(ret, monitorapi.NewInterval(monitorapi.SourceNodeJournal, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().NodeFromName(nodeName)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.IntervalReason(pattern.Name)).
+				WithAnnotation(monitorapi.AnnotationUnit, unit).
+				WithAnnotation(monitorapi.AnnotationCount, strconv.Itoa(matches.count)).
+				HumanMessagef("%d %s lines matched, the first: %s", matches.count, unit, matches.firstLine)).
+			Display().
+			Build(matches.from, matches.to.Add(time.Second)))
+	}
+
+	patterns := []*journalPattern{}
+	for i := range library.Patterns {
+		if library.Patterns[i].Unit == unit {
+			patterns = append(patterns, &library.Patterns[i])
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewBuffer(journal))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, pattern := range patterns {
+			if !pattern.regex.MatchString(line) {
+				continue
+			}
+			logTime := nodeaccess.SystemdJournalLogTime(line)
+			if logTime.Before(beginning) || (!end.IsZero() && logTime.After(end)) {
+				continue
+			}
+			if matches := open[pattern.Name]; matches != nil && logTime.Sub(matches.to) > mergeWindow {
+				flush(pattern)
+			}
+			if matches := open[pattern.Name]; matches != nil {
+				matches.to = logTime
+				matches.count++
+				continue
+			}
+			open[pattern.Name] = &patternMatches{
+				from:      logTime,
+				to:        logTime,
+				count:     1,
+				firstLine: journalMessage(line),
+			}
+		}
+	}
+	for _, pattern := range patterns {
+		flush(pattern)
+	}
+	return ret
+}
+
+// journalMessage drops the time and host in front of a journal line, for instance
+//
+// Apr 12 11:53:51.395838 ci-op-xs3rnrtc-2d4c7-4mhm7-worker-b-dwc7w kubenswrapper[2000]: E0412 ...
+func journalMessage(line string) string {
+	if fields := strings.SplitN(line, " ", 5); len(fields) == 5 {
+		return fields[4]
+	}
+	return line
+}
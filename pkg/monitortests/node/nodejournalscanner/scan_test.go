@@ -0,0 +1,97 @@
+package nodejournalscanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const testLibrary = `
+patterns:
+- name: PLEGNotHealthy
+  unit: kubelet
+  regex: 'PLEG is not healthy'
+  description: that the pod lifecycle event generator is not healthy
+  maxMatchesPerNode: 1
+  knownIssues:
+  - bug: https://issues.example.com/PLEG
+    topologies: [single]
+    maxMatchesPerNode: 5
+    reason: the relist stalls while the only node reboots
+- name: StorageLayerMissing
+  unit: crio
+  regex: 'layer not known'
+  description: that container storage is missing image layers
+  maxMatchesPerNode: 0
+`
+
+func TestDefaultPatternLibrary(t *testing.T) {
+	library, err := parsePatternLibrary(defaultJournalPatternsYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if units := library.units(); len(units) != 2 || units[0] != "kubelet" || units[1] != "crio" {
+		t.Errorf("expected the kubelet and crio journals to be searched, got %v", units)
+	}
+}
+
+func TestParsePatternLibraryRequiresBug(t *testing.T) {
+	_, err := parsePatternLibrary([]byte(`
+patterns:
+- name: PLEGNotHealthy
+  unit: kubelet
+  regex: 'PLEG is not healthy'
+  description: that the pod lifecycle event generator is not healthy
+  knownIssues:
+  - maxMatchesPerNode: 5
+`))
+	if err == nil {
+		t.Fatal("expected a known issue without a bug to be rejected")
+	}
+}
+
+func TestScanAndEvaluate(t *testing.T) {
+	library, err := parsePatternLibrary([]byte(testLibrary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	year := time.Now().Year()
+	beginning := time.Date(year, time.April, 12, 11, 0, 0, 0, time.UTC)
+	end := beginning.Add(2 * time.Hour)
+
+	kubeletJournal := []byte(`Apr 12 10:59:00.000000 master-0 kubenswrapper[2000]: E0412 skipping pod synchronization - PLEG is not healthy: pleg was last seen active 3m0s ago
+Apr 12 11:30:00.000000 master-0 kubenswrapper[2000]: I0412 nothing to see here
+Apr 12 11:30:01.000000 master-0 kubelet[2000]: E0412 skipping pod synchronization - PLEG is not healthy: pleg was last seen active 3m0s ago
+Apr 12 11:30:31.000000 master-0 kubelet[2000]: E0412 skipping pod synchronization - PLEG is not healthy: pleg was last seen active 3m30s ago
+Apr 12 11:50:00.000000 master-0 kubelet[2000]: E0412 skipping pod synchronization - PLEG is not healthy: pleg was last seen active 3m0s ago
+`)
+	intervals := scanJournal("master-0", "kubelet", kubeletJournal, library, beginning, end)
+	if len(intervals) != 2 {
+		t.Fatalf("expected matches a minute apart to share an interval and the one before the run to be ignored, got %v", intervals)
+	}
+	if count := intervals[0].Message.Annotations[monitorapi.AnnotationCount]; count != "2" {
+		t.Errorf("expected the first interval to count two lines, got %q", count)
+	}
+	if reason := intervals[0].Message.Reason; reason != "PLEGNotHealthy" {
+		t.Errorf("expected the pattern name as the reason, got %q", reason)
+	}
+
+	junits := evaluatePatterns(library, platformidentification.JobType{Topology: "ha"}, intervals)
+	if len(junits) != 2 {
+		t.Fatalf("expected a test per pattern, got %v", junits)
+	}
+	if junits[0].FailureOutput == nil || junits[0].Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected three PLEG lines to warn, got %v", junits[0])
+	}
+	if junits[1].FailureOutput != nil {
+		t.Errorf("expected no crio failure, got %v", junits[1].FailureOutput.Output)
+	}
+
+	junits = evaluatePatterns(library, platformidentification.JobType{Topology: "single"}, intervals)
+	if len(junits) != 3 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil || junits[0].Name != junits[1].Name {
+		t.Fatalf("expected the known issue to flake, got %v", junits)
+	}
+}
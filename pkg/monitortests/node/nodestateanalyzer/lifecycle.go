@@ -0,0 +1,134 @@
+package nodestateanalyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const unexplainedNotReadyTestName = "[sig-node] node NotReady windows should be explained by a node update or a disruptive test"
+
+// notReadyGrace allows a node to report NotReady shortly after the update that caused it finished, because the
+// MachineConfigReached event races the node status.
+const notReadyGrace = 1 * time.Minute
+
+// intervalsFromKubeletLogs_KubeletRestarts pairs the kubelet being stopped and started from the journal into restart
+// intervals.  A start without a stop, for instance after a crash or a reboot, or after a stop before beginning, is a
+// short interval at the start.
+func intervalsFromKubeletLogs_KubeletRestarts(events *monitorapi.IntervalStore, beginning, end time.Time) monitorapi.Intervals {
+	lifecycleByNode := map[string]monitorapi.Intervals{}
+	for _, event := range events.BySource(monitorapi.SourceKubeletLog, time.Time{}, time.Time{}) {
+		switch event.Message.Reason {
+		case monitorapi.KubeletStoppedReason, monitorapi.KubeletStartedReason:
+		default:
+			continue
+		}
+		if event.From.Before(beginning) || event.From.After(end) {
+			continue
+		}
+		node := event.Locator.Keys[monitorapi.LocatorNodeKey]
+		lifecycleByNode[node] = append(lifecycleByNode[node], event)
+	}
+
+	nodes := make([]string, 0, len(lifecycleByNode))
+	for node := range lifecycleByNode {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	ret := monitorapi.Intervals{}
+	for _, node := range nodes {
+		lifecycle := lifecycleByNode[node]
+		sort.SliceStable(lifecycle, func(i, j int) bool {
+			return lifecycle[i].From.Before(lifecycle[j].From)
+		})
+
+		var stopped *time.Time
+		for _, event := range lifecycle {
+			if event.Message.Reason == monitorapi.KubeletStoppedReason {
+				if stopped == nil {
+					from := event.From
+					stopped = &from
+				}
+				continue
+			}
+
+			from, to, message := event.From, event.From.Add(time.Second), "kubelet started"
+			if stopped != nil {
+				from, to, message = *stopped, event.From, "kubelet restarted"
+			}
+			stopped = nil
+			ret = append(ret, kubeletRestartInterval(node, message, from, to))
+		}
+		if stopped != nil {
+			ret = append(ret, kubeletRestartInterval(node, "kubelet stopped and did not start again", *stopped, end))
+		}
+	}
+	return ret
+}
+
+func kubeletRestartInterval(node, message string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeKubeletRestartReason).
+			HumanMessage(message).
+			WithAnnotation(monitorapi.AnnotationConstructed, monitorapi.ConstructionOwnerNodeLifecycle)).
+		Display().
+		Build(from, to)
+}
+
+var unexplainedNotReadyTemplate = junitfailure.MustParseTemplate("unexplained-node-not-ready",
+	`{{len .Intervals}} NotReady windows started while the node was not being updated and no disruptive test was running.  An unreachable node outside of an update usually means the kubelet, the network, or the machine failed.
+{{.IntervalList}}`)
+
+// evaluateNotReadyExplained fails for every NotReady window that did not start during an update of the same node or
+// while a disruptive test, which may reboot or isolate nodes, was running.
+func evaluateNotReadyExplained(finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	updatesByNode := map[string]monitorapi.Intervals{}
+	disruptiveTests := monitorapi.Intervals{}
+	notReady := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		switch {
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeUpdateReason:
+			node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+			updatesByNode[node] = append(updatesByNode[node], interval)
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeNotReadyReason:
+			notReady = append(notReady, interval)
+		case interval.Source == monitorapi.SourceE2ETest && interval.To.After(interval.From):
+			if testName, ok := monitorapi.E2ETestFromLocator(interval.Locator); ok && strings.Contains(testName, "[Disruptive]") {
+				disruptiveTests = append(disruptiveTests, interval)
+			}
+		}
+	}
+
+	unexplained := monitorapi.Intervals{}
+	for _, window := range notReady {
+		node := window.Locator.Keys[monitorapi.LocatorNodeKey]
+		if !startedDuring(window, updatesByNode[node]) && !startedDuring(window, disruptiveTests) {
+			unexplained = append(unexplained, window)
+		}
+	}
+	if len(unexplained) == 0 {
+		return &junitapi.JUnitTestCase{Name: unexplainedNotReadyTestName}
+	}
+
+	junit := junitfailure.NewFailure(unexplainedNotReadyTemplate, "UnexplainedNodeNotReady").
+		Intervals(unexplained...).
+		TestCase(unexplainedNotReadyTestName)
+	// a warning until we know which other test-initiated actions take nodes down.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+func startedDuring(window monitorapi.Interval, explanations monitorapi.Intervals) bool {
+	for _, explanation := range explanations {
+		if !window.From.Before(explanation.From) && !window.From.After(explanation.To.Add(notReadyGrace)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package nodestateanalyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func nodeInterval(source monitorapi.IntervalSource, reason monitorapi.IntervalReason, node string, from, to time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(source, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage(string(reason))).
+		Build(start.Add(from), start.Add(to))
+}
+
+func TestKubeletRestarts(t *testing.T) {
+	events := monitorapi.Intervals{
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.KubeletStoppedReason, "node-a", time.Minute, time.Minute+time.Second),
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.KubeletStartedReason, "node-a", 2*time.Minute, 2*time.Minute+time.Second),
+		// a start without a stop, after a crash.
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.KubeletStartedReason, "node-b", 3*time.Minute, 3*time.Minute+time.Second),
+		// before the run, ignored.
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.KubeletStartedReason, "node-b", -time.Hour, -time.Hour+time.Second),
+		// a stop that never started again.
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.KubeletStoppedReason, "node-c", 4*time.Minute, 4*time.Minute+time.Second),
+	}
+
+	restarts := intervalsFromKubeletLogs_KubeletRestarts(monitorapi.NewIntervalStore(events...), start, start.Add(10*time.Minute))
+	if len(restarts) != 3 {
+		t.Fatalf("expected 3 restarts, got %d: %v", len(restarts), restarts)
+	}
+	if from, to := restarts[0].From, restarts[0].To; !from.Equal(start.Add(time.Minute)) || !to.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("expected node-a to restart from the stop to the start, got %v to %v", from, to)
+	}
+	if from := restarts[1].From; !from.Equal(start.Add(3 * time.Minute)) {
+		t.Errorf("expected node-b to start at 3m, got %v", from)
+	}
+	if to := restarts[2].To; !to.Equal(start.Add(10 * time.Minute)) {
+		t.Errorf("expected node-c to stay down until the end, got %v", to)
+	}
+	for _, restart := range restarts {
+		if restart.Message.Reason != monitorapi.NodeKubeletRestartReason || restart.Source != monitorapi.SourceNodeState {
+			t.Errorf("unexpected restart interval %v", restart)
+		}
+	}
+}
+
+func TestKubeletRestartsStraddlingBeginning(t *testing.T) {
+	// the stop is before the run, so the start is reported as a start without a stop.
+	events := monitorapi.Intervals{
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.KubeletStoppedReason, "node-a", -30*time.Second, -29*time.Second),
+		nodeInterval(monitorapi.SourceKubeletLog, monitorapi.KubeletStartedReason, "node-a", 30*time.Second, 31*time.Second),
+	}
+
+	restarts := intervalsFromKubeletLogs_KubeletRestarts(monitorapi.NewIntervalStore(events...), start, start.Add(10*time.Minute))
+	if len(restarts) != 1 {
+		t.Fatalf("expected 1 restart, got %d: %v", len(restarts), restarts)
+	}
+	if from, to := restarts[0].From, restarts[0].To; !from.Equal(start.Add(30*time.Second)) || !to.Equal(start.Add(31*time.Second)) {
+		t.Errorf("expected a short start at 30s, got %v to %v", from, to)
+	}
+}
+
+func TestEvaluateNotReadyExplained(t *testing.T) {
+	disruptiveTest := monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+		Locator(monitorapi.NewLocator().E2ETest("[sig-etcd][Disruptive] etcd should recover from a lost member")).
+		Message(monitorapi.NewMessage().HumanMessage("e2e test finished")).
+		Build(start.Add(20*time.Minute), start.Add(30*time.Minute))
+
+	tests := []struct {
+		name            string
+		intervals       monitorapi.Intervals
+		wantUnexplained int
+	}{
+		{
+			name: "during an update of the same node",
+			intervals: monitorapi.Intervals{
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeUpdateReason, "node-a", 0, 10*time.Minute),
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeNotReadyReason, "node-a", 5*time.Minute, 7*time.Minute),
+			},
+		},
+		{
+			name: "shortly after an update of the same node",
+			intervals: monitorapi.Intervals{
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeUpdateReason, "node-a", 0, 10*time.Minute),
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeNotReadyReason, "node-a", 10*time.Minute+30*time.Second, 11*time.Minute),
+			},
+		},
+		{
+			name: "during an update of another node",
+			intervals: monitorapi.Intervals{
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeUpdateReason, "node-a", 0, 10*time.Minute),
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeNotReadyReason, "node-b", 5*time.Minute, 7*time.Minute),
+			},
+			wantUnexplained: 1,
+		},
+		{
+			name: "during a disruptive test",
+			intervals: monitorapi.Intervals{
+				disruptiveTest,
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeNotReadyReason, "node-b", 25*time.Minute, 26*time.Minute),
+			},
+		},
+		{
+			name: "after everything",
+			intervals: monitorapi.Intervals{
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeUpdateReason, "node-a", 0, 10*time.Minute),
+				disruptiveTest,
+				nodeInterval(monitorapi.SourceNodeState, monitorapi.NodeNotReadyReason, "node-a", 40*time.Minute, 41*time.Minute),
+			},
+			wantUnexplained: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junit := evaluateNotReadyExplained(tt.intervals)
+			if tt.wantUnexplained == 0 {
+				if junit.FailureOutput != nil {
+					t.Fatalf("expected a pass, got %v", junit.FailureOutput.Output)
+				}
+				return
+			}
+			if junit.FailureOutput == nil {
+				t.Fatalf("expected a failure")
+			}
+			if got := len(junit.Details.IntervalIDs); got != tt.wantUnexplained {
+				t.Errorf("expected %d unexplained windows, got %d", tt.wantUnexplained, got)
+			}
+			if junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning, got %q", junit.Details.Severity)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -18,6 +19,12 @@ func NewAnalyzer() monitortestframework.MonitorTest {
 	return &nodeStateAnalyzer{}
 }
 
+var _ monitortestframework.OfflineMonitorTest = &nodeStateAnalyzer{}
+
+func (*nodeStateAnalyzer) SupportsOffline() bool {
+	return true
+}
+
 func (w *nodeStateAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	return nil
 }
@@ -26,15 +33,22 @@ func (w *nodeStateAnalyzer) CollectData(ctx context.Context, storageDir string,
 	return nil, nil, nil
 }
 
-func (*nodeStateAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+func (w *nodeStateAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return w.ConstructComputedIntervalsFromStore(ctx, startingIntervals, monitorapi.NewIntervalStore(startingIntervals...), recordedResources, beginning, end)
+}
+
+func (*nodeStateAnalyzer) ConstructComputedIntervalsFromStore(ctx context.Context, startingIntervals monitorapi.Intervals, startingStore *monitorapi.IntervalStore, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
 	ret := monitorapi.Intervals{}
-	ret = append(ret, intervalsFromEvents_NodeChanges(startingIntervals, nil, beginning, end)...)
+	nodeChanges := intervalsFromEvents_NodeChanges(startingIntervals, nil, beginning, end)
+	ret = append(ret, nodeChanges...)
+	ret = append(ret, platformidentification.UpdateTopologyIntervals(nodeChanges)...)
+	ret = append(ret, intervalsFromKubeletLogs_KubeletRestarts(startingStore, beginning, end)...)
 
 	return ret, nil
 }
 
 func (*nodeStateAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
+	return []*junitapi.JUnitTestCase{evaluateNotReadyExplained(finalIntervals)}, nil
 }
 
 func (*nodeStateAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
@@ -0,0 +1,85 @@
+package pdbanalyzer
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type pdbAnalyzer struct {
+	tracker *pdbTracker
+	clock   clock.PassiveClock
+}
+
+// NewPDBAnalyzer records when PodDisruptionBudgets in platform namespaces drop below minAvailable and fails when a
+// node update draining the guarded pods is to blame.
+func NewPDBAnalyzer() monitortestframework.MonitorTest {
+	return &pdbAnalyzer{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &pdbAnalyzer{}
+
+func (w *pdbAnalyzer) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *pdbAnalyzer) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{pdbViolationTestName},
+	}
+}
+
+var _ monitortestframework.OfflineMonitorTest = &pdbAnalyzer{}
+
+func (*pdbAnalyzer) SupportsOffline() bool {
+	return true
+}
+
+func (w *pdbAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.tracker = newPDBTracker(recorder, w.clock)
+	startPDBMonitoring(ctx, w.tracker, kubeClient)
+	return nil
+}
+
+func (w *pdbAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// the intervals are in the recorder, only the budgets still violated have to be closed.
+	if w.tracker != nil {
+		w.tracker.finish(end)
+	}
+	return nil, nil, nil
+}
+
+func (*pdbAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return violationIntervals(startingIntervals, end), nil
+}
+
+func (*pdbAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return evaluateViolations(finalIntervals), nil
+}
+
+func (*pdbAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*pdbAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,116 @@
+package pdbanalyzer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformnamespaces"
+)
+
+type pdbKey struct {
+	namespace, name string
+}
+
+// pdbTracker records an interval for as long as a PodDisruptionBudget has fewer healthy pods than it requires.
+type pdbTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock sync.Mutex
+	// belowMinAvailable maps a budget that is violated to the recorder ID of its open interval.
+	belowMinAvailable map[pdbKey]int
+}
+
+func newPDBTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *pdbTracker {
+	return &pdbTracker{
+		recorder:          recorder,
+		clock:             clock,
+		belowMinAvailable: map[pdbKey]int{},
+	}
+}
+
+func startPDBMonitoring(ctx context.Context, tracker *pdbTracker, client kubernetes.Interface) {
+	kubeInformers := informers.NewSharedInformerFactory(client, 0)
+	kubeInformers.Policy().V1().PodDisruptionBudgets().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pdb, ok := obj.(*policyv1.PodDisruptionBudget); ok {
+				tracker.observe(pdb, tracker.clock.Now())
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pdb, ok := obj.(*policyv1.PodDisruptionBudget); ok {
+				tracker.observe(pdb, tracker.clock.Now())
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pdb, ok := obj.(*policyv1.PodDisruptionBudget); ok {
+				tracker.end(pdbKey{namespace: pdb.Namespace, name: pdb.Name}, tracker.clock.Now())
+			}
+		},
+	})
+	kubeInformers.Start(ctx.Done())
+}
+
+// belowMinAvailable returns true when the disruption controller has counted fewer healthy pods than the budget
+// requires.  A status for an older generation of the budget may compare against a stale requirement.
+func belowMinAvailable(pdb *policyv1.PodDisruptionBudget) bool {
+	if pdb.Status.ObservedGeneration < pdb.Generation || pdb.Status.ExpectedPods == 0 {
+		return false
+	}
+	return pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy
+}
+
+func (t *pdbTracker) observe(pdb *policyv1.PodDisruptionBudget, now time.Time) {
+	if !platformnamespaces.IsPlatformNamespace(pdb.Namespace) {
+		return
+	}
+	key := pdbKey{namespace: pdb.Namespace, name: pdb.Name}
+	if !belowMinAvailable(pdb) {
+		t.end(key, now)
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, ok := t.belowMinAvailable[key]; ok {
+		return
+	}
+	t.belowMinAvailable[key] = t.recorder.StartInterval(
+		monitorapi.NewInterval(monitorapi.SourcePDB, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().PDB(pdb.Namespace, pdb.Name)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.PDBBelowMinAvailableReason).
+				HumanMessagef("%d of %d pods healthy, %d required", pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods, pdb.Status.DesiredHealthy)).
+			Display().
+			Build(now, time.Time{}),
+	)
+}
+
+func (t *pdbTracker) end(key pdbKey, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if id, ok := t.belowMinAvailable[key]; ok {
+		t.recorder.EndInterval(id, now)
+		delete(t.belowMinAvailable, key)
+	}
+}
+
+// finish ends the intervals of budgets still violated at the end of the run.
+func (t *pdbTracker) finish(end time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for key, id := range t.belowMinAvailable {
+		t.recorder.EndInterval(id, end)
+		delete(t.belowMinAvailable, key)
+	}
+}
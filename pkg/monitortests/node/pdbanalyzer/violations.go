@@ -0,0 +1,152 @@
+package pdbanalyzer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const pdbViolationTestName = "[sig-apps] platform workloads guarded by a PodDisruptionBudget should not drop below minAvailable during node updates"
+
+// nodeUpdateWindows returns the times every node was updating, from the machine config changing to the node
+// reaching it, or to the end of the run if it never did.
+func nodeUpdateWindows(intervals monitorapi.Intervals, end time.Time) map[string]monitorapi.Intervals {
+	ret := map[string]monitorapi.Intervals{}
+	updating := map[string]monitorapi.Interval{}
+	for _, interval := range intervals {
+		if interval.Source != monitorapi.SourceNodeMonitor {
+			continue
+		}
+		node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+		if len(node) == 0 {
+			continue
+		}
+		switch interval.Message.Reason {
+		case monitorapi.MachineConfigChangeReason:
+			if _, ok := updating[node]; !ok {
+				updating[node] = interval
+			}
+		case monitorapi.MachineConfigReachedReason:
+			if started, ok := updating[node]; ok {
+				started.To = interval.From
+				ret[node] = append(ret[node], started)
+				delete(updating, node)
+			}
+		}
+	}
+	for node, started := range updating {
+		started.To = end
+		ret[node] = append(ret[node], started)
+	}
+	return ret
+}
+
+// isEviction returns true for a pod being removed from its node, by the eviction API during a drain or by the kubelet.
+func isEviction(interval monitorapi.Interval) bool {
+	if interval.Source != monitorapi.SourcePodMonitor {
+		return false
+	}
+	return interval.Message.Reason == monitorapi.PodReasonGracefulDeleteStarted || interval.Message.Reason == monitorapi.PodReasonEvicted
+}
+
+func overlaps(a, b monitorapi.Interval) bool {
+	return !a.From.After(b.To) && !b.From.After(a.To)
+}
+
+// violationIntervals pairs every time a budget in a platform namespace dropped below minAvailable with the node
+// updates that drained its pods while it did.  A node update only counts when a pod of the namespace was removed from
+// the updating node between the update starting and the budget recovering, so a budget that dropped for an unrelated
+// reason while some other node updated is not blamed on the drain.
+func violationIntervals(intervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	updates := nodeUpdateWindows(intervals, end)
+
+	evictionsByNamespaceNode := map[string]map[string]monitorapi.Intervals{}
+	violations := monitorapi.Intervals{}
+	for _, interval := range intervals {
+		switch {
+		case interval.Source == monitorapi.SourcePDB && interval.Message.Reason == monitorapi.PDBBelowMinAvailableReason:
+			violations = append(violations, interval)
+		case isEviction(interval):
+			namespace := interval.Locator.Keys[monitorapi.LocatorNamespaceKey]
+			node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+			if len(node) == 0 {
+				continue
+			}
+			if _, ok := evictionsByNamespaceNode[namespace]; !ok {
+				evictionsByNamespaceNode[namespace] = map[string]monitorapi.Intervals{}
+			}
+			evictionsByNamespaceNode[namespace][node] = append(evictionsByNamespaceNode[namespace][node], interval)
+		}
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, violation := range violations {
+		namespace := violation.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		nodes := []string{}
+		for node := range updates {
+			nodes = append(nodes, node)
+		}
+		sort.Strings(nodes)
+
+		for _, node := range nodes {
+			for _, update := range updates[node] {
+				if !overlaps(update, violation) {
+					continue
+				}
+				evicted := 0
+				for _, eviction := range evictionsByNamespaceNode[namespace][node] {
+					if !eviction.From.Before(update.From) && !eviction.From.After(violation.To) {
+						evicted++
+					}
+				}
+				if evicted == 0 {
+					continue
+				}
+
+				from, to := violation.From, violation.To
+				if update.From.After(from) {
+					from = update.From
+				}
+				if update.To.Before(to) {
+					to = update.To
+				}
+				ret = append(ret, monitorapi.NewInterval(monitorapi.SourcePDB, monitorapi.Error).
+					Locator(violation.Locator).
+					Message(monitorapi.NewMessage().Reason(monitorapi.PDBViolatedDuringNodeUpdateReason).
+						Node(node).
+						HumanMessagef("below minAvailable while node/%s was updating, %d pods of the namespace removed from the node: %s", node, evicted, violation.Message.HumanMessage).
+						Constructed(monitorapi.ConstructionOwnerPDB)).
+					Display().
+					Build(from, to))
+			}
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+var pdbViolationTemplate = junitfailure.MustParseTemplate("pdb-violated-during-node-update",
+	`{{len .Intervals}} times a platform workload dropped below the minAvailable of its PodDisruptionBudget while a node draining its pods was updating.  Drains must wait for the budget to allow each eviction.
+{{.IntervalList}}`)
+
+func evaluateViolations(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	violations := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source == monitorapi.SourcePDB && interval.Message.Reason == monitorapi.PDBViolatedDuringNodeUpdateReason {
+			violations = append(violations, interval)
+		}
+	}
+	if len(violations) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: pdbViolationTestName}}
+	}
+
+	junit := junitfailure.NewFailure(pdbViolationTemplate, "PodDisruptionBudgetViolated").
+		Intervals(violations...).
+		TestCase(pdbViolationTestName)
+	// a warning until we know which platform budgets drop during updates today.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return []*junitapi.JUnitTestCase{junit}
+}
@@ -0,0 +1,109 @@
+package pdbanalyzer
+
+import (
+	"testing"
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func nodeEvent(node string, reason monitorapi.IntervalReason, at time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceNodeMonitor, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage(string(reason))).
+		Build(start.Add(at), start.Add(at))
+}
+
+func podDeleted(namespace, pod, node string, at time.Duration) monitorapi.Interval {
+	locator := monitorapi.NewLocator().PodFromNames(namespace, pod, pod+"-uid")
+	locator.Keys[monitorapi.LocatorNodeKey] = node
+	return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Info).
+		Locator(locator).
+		Message(monitorapi.NewMessage().Reason(monitorapi.PodReasonGracefulDeleteStarted)).
+		Build(start.Add(at), start.Add(at))
+}
+
+func belowMin(namespace, name string, from, duration time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourcePDB, monitorapi.Warning).
+		Locator(monitorapi.NewLocator().PDB(namespace, name)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.PDBBelowMinAvailableReason).HumanMessage("1 of 3 pods healthy, 2 required")).
+		Build(start.Add(from), start.Add(from+duration))
+}
+
+func TestViolationIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		nodeEvent("master-0", monitorapi.MachineConfigChangeReason, 10*time.Minute),
+		nodeEvent("master-1", monitorapi.MachineConfigChangeReason, 10*time.Minute),
+		podDeleted("openshift-etcd", "guard-0", "master-0", 11*time.Minute),
+		belowMin("openshift-etcd", "etcd-guard-pdb", 11*time.Minute, 5*time.Minute),
+		nodeEvent("master-0", monitorapi.MachineConfigReachedReason, 20*time.Minute),
+		nodeEvent("master-1", monitorapi.MachineConfigReachedReason, 20*time.Minute),
+		// below minAvailable without any node updating.
+		belowMin("openshift-etcd", "etcd-guard-pdb", 40*time.Minute, 5*time.Minute),
+		// below minAvailable while a node updated, without any pod of the namespace removed from it.
+		nodeEvent("worker-0", monitorapi.MachineConfigChangeReason, 50*time.Minute),
+		belowMin("openshift-ingress", "router-default", 51*time.Minute, 5*time.Minute),
+	}
+
+	violations := violationIntervals(intervals, start.Add(time.Hour))
+	if len(violations) != 1 {
+		t.Fatalf("expected only the drain of master-0 to be blamed, got %v", violations)
+	}
+	if node := violations[0].Message.Annotations[monitorapi.AnnotationNode]; node != "master-0" {
+		t.Errorf("expected the violating drain to be master-0, got %q", node)
+	}
+	if from, to := violations[0].From, violations[0].To; !from.Equal(start.Add(11*time.Minute)) || !to.Equal(start.Add(16*time.Minute)) {
+		t.Errorf("expected the violation to last while the budget was below minAvailable, got %v to %v", from, to)
+	}
+
+	junits := evaluateViolations(append(intervals, violations...))
+	if len(junits) != 1 || junits[0].FailureOutput == nil {
+		t.Fatalf("expected a failure, got %v", junits)
+	}
+	if junits[0].Details.Severity != junitapi.SeverityWarn {
+		t.Errorf("expected a warning, got %q", junits[0].Details.Severity)
+	}
+}
+
+func TestPDBTracker(t *testing.T) {
+	recorder := monitor.NewRecorder()
+	tracker := newPDBTracker(recorder, clock.RealClock{})
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "etcd-guard-pdb", Generation: 1},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			ObservedGeneration: 1,
+			ExpectedPods:       3,
+			DesiredHealthy:     2,
+			CurrentHealthy:     1,
+		},
+	}
+	tracker.observe(pdb, start)
+	tracker.observe(pdb, start.Add(time.Minute))
+
+	healthy := pdb.DeepCopy()
+	healthy.Status.CurrentHealthy = 3
+	tracker.observe(healthy, start.Add(2*time.Minute))
+
+	// not a platform namespace.
+	testPDB := pdb.DeepCopy()
+	testPDB.Namespace = "e2e-test"
+	tracker.observe(testPDB, start)
+	tracker.finish(start.Add(time.Hour))
+
+	intervals := recorder.Intervals(time.Time{}, time.Time{})
+	if len(intervals) != 1 {
+		t.Fatalf("expected one violation, got %v", intervals)
+	}
+	if to := intervals[0].To; !to.Equal(start.Add(2 * time.Minute)) {
+		t.Errorf("expected the violation to end when the pods were healthy again, got %v", to)
+	}
+}
@@ -0,0 +1,73 @@
+package resourceconsumption
+
+import (
+	_ "embed"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed consumption_baseline.yaml
+var defaultBaselineYAML []byte
+
+type baselineFile struct {
+	Baselines []componentBaseline `json:"baselines"`
+}
+
+// componentBaseline is the consumption of a component on healthy runs of a topology.
+type componentBaseline struct {
+	// Component is the namespace the component runs in.
+	Component string `json:"component"`
+	Topology  string `json:"topology"`
+
+	MeanCPUCores    float64 `json:"meanCPUCores"`
+	PeakMemoryBytes float64 `json:"peakMemoryBytes"`
+}
+
+func parseBaselines(content []byte) (*baselineFile, error) {
+	ret := &baselineFile{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for i, baseline := range ret.Baselines {
+		key := baseline.Component + "/" + baseline.Topology
+		switch {
+		case len(baseline.Component) == 0 || len(baseline.Topology) == 0:
+			return nil, fmt.Errorf("baseline %d must have a component and a topology", i)
+		case seen[key]:
+			return nil, fmt.Errorf("baseline of %v on %v is defined more than once", baseline.Component, baseline.Topology)
+		case baseline.MeanCPUCores <= 0 || baseline.PeakMemoryBytes <= 0:
+			return nil, fmt.Errorf("baseline of %v on %v must have a positive CPU and memory", baseline.Component, baseline.Topology)
+		}
+		seen[key] = true
+	}
+	return ret, nil
+}
+
+var (
+	readDefaultBaselines sync.Once
+	defaultBaselines     *baselineFile
+)
+
+func getDefaultBaselines() *baselineFile {
+	readDefaultBaselines.Do(func() {
+		var err error
+		defaultBaselines, err = parseBaselines(defaultBaselineYAML)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return defaultBaselines
+}
+
+// baselineFor returns the baseline of a component on a topology, or nil if there is none.
+func (f *baselineFile) baselineFor(component, topology string) *componentBaseline {
+	for i := range f.Baselines {
+		if f.Baselines[i].Component == component && f.Baselines[i].Topology == topology {
+			return &f.Baselines[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package resourceconsumption
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+)
+
+const (
+	queryStep = 30 * time.Second
+
+	// the instance of node-exporter is the name of its node.
+	nodeCPUQuery    = `1 - avg by (instance) (rate(node_cpu_seconds_total{mode="idle"}[2m]))`
+	nodeMemoryQuery = `1 - node_memory_MemAvailable_bytes / node_memory_MemTotal_bytes`
+
+	// componentCPUQuery and componentMemoryQuery sum the containers of the platform by namespace, the namespace of a
+	// component.  The pause containers and the cgroups of whole pods are left out.
+	componentCPUQuery    = `sum by (namespace) (rate(container_cpu_usage_seconds_total{namespace=~"openshift-.*|kube-.*", container!="", container!="POD"}[2m]))`
+	componentMemoryQuery = `sum by (namespace) (container_memory_working_set_bytes{namespace=~"openshift-.*|kube-.*", container!="", container!="POD"})`
+
+	// saturationThreshold is the share of the CPU or memory of a node in use above which the node is saturated.
+	saturationThreshold = 0.9
+)
+
+// componentConsumption is the CPU and memory a component used during the run.
+type componentConsumption struct {
+	Component string
+
+	MeanCPUCores float64
+	PeakCPUCores float64
+
+	MeanMemoryBytes float64
+	PeakMemoryBytes float64
+}
+
+type sampleStats struct {
+	mean, peak float64
+}
+
+func statsOf(samples []prometheustypes.SamplePair) sampleStats {
+	ret := sampleStats{}
+	count := 0
+	for _, sample := range samples {
+		value := float64(sample.Value)
+		if math.IsNaN(value) {
+			continue
+		}
+		ret.mean += value
+		ret.peak = math.Max(ret.peak, value)
+		count++
+	}
+	if count > 0 {
+		ret.mean /= float64(count)
+	}
+	return ret
+}
+
+// summarizeConsumption returns the consumption of every component with CPU or memory samples, sorted by component.
+func summarizeConsumption(cpu, memory prometheustypes.Matrix) []componentConsumption {
+	byComponent := map[string]*componentConsumption{}
+	get := func(series *prometheustypes.SampleStream) *componentConsumption {
+		component := string(series.Metric["namespace"])
+		if _, ok := byComponent[component]; !ok {
+			byComponent[component] = &componentConsumption{Component: component}
+		}
+		return byComponent[component]
+	}
+	for _, series := range cpu {
+		stats := statsOf(series.Values)
+		consumption := get(series)
+		consumption.MeanCPUCores, consumption.PeakCPUCores = stats.mean, stats.peak
+	}
+	for _, series := range memory {
+		stats := statsOf(series.Values)
+		consumption := get(series)
+		consumption.MeanMemoryBytes, consumption.PeakMemoryBytes = stats.mean, stats.peak
+	}
+
+	ret := []componentConsumption{}
+	for _, consumption := range byComponent {
+		ret = append(ret, *consumption)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Component < ret[j].Component })
+	return ret
+}
+
+// saturationIntervals reports the windows in which the CPU or memory in use on a node was over the threshold.
+func saturationIntervals(matrix prometheustypes.Matrix, reason monitorapi.IntervalReason, resource string, step time.Duration) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	saturated := func(value float64) bool { return value > saturationThreshold }
+	for _, series := range matrix {
+		node := string(series.Metric["instance"])
+		for _, window := range prometheusaccess.Windows(series.Values, step, saturated) {
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceResourceConsumption, monitorapi.Warning).
+					Locator(monitorapi.NewLocator().NodeFromName(node)).
+					Message(monitorapi.NewMessage().Reason(reason).
+						WithAnnotation(monitorapi.AnnotationUtilization, fmt.Sprintf("%.3f", window.Peak)).
+						HumanMessagef("%s utilization peaked at %.1f%%", resource, window.Peak*100)).
+					Display().
+					Build(window.From, window.To),
+			)
+		}
+	}
+	return ret
+}
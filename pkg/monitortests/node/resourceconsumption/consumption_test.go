@@ -0,0 +1,116 @@
+package resourceconsumption
+
+import (
+	"testing"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func series(labels prometheustypes.Metric, values ...float64) *prometheustypes.SampleStream {
+	ret := &prometheustypes.SampleStream{Metric: labels}
+	for i, value := range values {
+		ret.Values = append(ret.Values, prometheustypes.SamplePair{
+			Timestamp: prometheustypes.TimeFromUnixNano(start.Add(time.Duration(i) * queryStep).UnixNano()),
+			Value:     prometheustypes.SampleValue(value),
+		})
+	}
+	return ret
+}
+
+func TestDefaultBaselines(t *testing.T) {
+	getDefaultBaselines()
+}
+
+func TestParseBaselinesRejectsDuplicates(t *testing.T) {
+	_, err := parseBaselines([]byte(`
+baselines:
+- component: openshift-etcd
+  topology: ha
+  meanCPUCores: 1
+  peakMemoryBytes: 1
+- component: openshift-etcd
+  topology: ha
+  meanCPUCores: 2
+  peakMemoryBytes: 2
+`))
+	if err == nil {
+		t.Errorf("expected a duplicate baseline to be rejected")
+	}
+}
+
+func TestSaturationIntervals(t *testing.T) {
+	cpu := prometheustypes.Matrix{
+		series(prometheustypes.Metric{"instance": "master-0"}, 0.5, 0.95, 0.97, 0.6),
+		series(prometheustypes.Metric{"instance": "worker-0"}, 0.2, 0.3),
+	}
+	intervals := saturationIntervals(cpu, monitorapi.NodeCPUSaturatedReason, "CPU", queryStep)
+	if len(intervals) != 1 {
+		t.Fatalf("expected one saturation window, got %v", intervals)
+	}
+	if node := intervals[0].Locator.Keys[monitorapi.LocatorNodeKey]; node != "master-0" {
+		t.Errorf("expected master-0 to be saturated, got %q", node)
+	}
+	if !intervals[0].From.Equal(start.Add(queryStep)) || !intervals[0].To.Equal(start.Add(3*queryStep)) {
+		t.Errorf("expected the window of the saturated samples, got %v to %v", intervals[0].From, intervals[0].To)
+	}
+}
+
+func TestEvaluateConsumption(t *testing.T) {
+	baselines, err := parseBaselines([]byte(`
+baselines:
+- component: openshift-etcd
+  topology: ha
+  meanCPUCores: 0.5
+  peakMemoryBytes: 1000
+- component: openshift-kube-apiserver
+  topology: ha
+  meanCPUCores: 1
+  peakMemoryBytes: 1000
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumption := summarizeConsumption(
+		prometheustypes.Matrix{
+			// a mean of 1 core, twice the baseline.
+			series(prometheustypes.Metric{"namespace": "openshift-etcd"}, 0.5, 1.5),
+			series(prometheustypes.Metric{"namespace": "openshift-kube-apiserver"}, 1, 1),
+		},
+		prometheustypes.Matrix{
+			series(prometheustypes.Metric{"namespace": "openshift-etcd"}, 500, 900),
+			series(prometheustypes.Metric{"namespace": "openshift-kube-apiserver"}, 800, 1200),
+		},
+	)
+	if consumption[0].Component != "openshift-etcd" || consumption[0].MeanCPUCores != 1 || consumption[0].PeakMemoryBytes != 900 {
+		t.Fatalf("unexpected consumption %+v", consumption[0])
+	}
+
+	junits := evaluateConsumption(baselines, "ha", consumption)
+	if len(junits) != len(controlPlaneComponents) {
+		t.Fatalf("expected a test per control plane component, got %v", junits)
+	}
+	for _, junit := range junits {
+		switch junit.Name {
+		case consumptionTestName("openshift-etcd"):
+			if junit.FailureOutput == nil {
+				t.Errorf("expected etcd to fail for twice its baseline CPU")
+			} else if junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning, got %q", junit.Details.Severity)
+			}
+		case consumptionTestName("openshift-kube-apiserver"):
+			if junit.FailureOutput != nil || junit.SkipMessage != nil {
+				t.Errorf("expected the kube-apiserver to pass within its baseline, got %+v", junit)
+			}
+		default:
+			if junit.SkipMessage == nil {
+				t.Errorf("expected %q to be skipped without a baseline", junit.Name)
+			}
+		}
+	}
+}
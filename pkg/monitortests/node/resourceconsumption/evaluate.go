@@ -0,0 +1,102 @@
+package resourceconsumption
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// regressionFactor is how far over its baseline a component may be before it fails.
+const regressionFactor = 1.5
+
+// controlPlaneComponents are held to their baselines, by the namespace they run in.
+var controlPlaneComponents = []string{
+	"openshift-etcd",
+	"openshift-kube-apiserver",
+	"openshift-kube-controller-manager",
+	"openshift-kube-scheduler",
+	"openshift-apiserver",
+	"openshift-oauth-apiserver",
+}
+
+func consumptionTestName(component string) string {
+	return fmt.Sprintf("[sig-node] %s CPU and memory consumption should not regress from its baseline", component)
+}
+
+var regressionTemplate = junitfailure.MustParseTemplate("resource-consumption-regression",
+	`{{.Fields.component}} used more than {{.Fields.factor}} times its baseline on {{.Fields.topology}}: {{.Fields.details}}`)
+
+// evaluateConsumption produces a test for every control plane component, failing when its mean CPU or peak memory
+// exceeds its baseline for the topology by more than the regression factor.
+func evaluateConsumption(baselines *baselineFile, topology string, consumption []componentConsumption) []*junitapi.JUnitTestCase {
+	byComponent := map[string]componentConsumption{}
+	for _, curr := range consumption {
+		byComponent[curr.Component] = curr
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	for _, component := range controlPlaneComponents {
+		testName := consumptionTestName(component)
+		baseline := baselines.baselineFor(component, topology)
+		observed, ok := byComponent[component]
+		switch {
+		case baseline == nil:
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:        testName,
+				SkipMessage: &junitapi.SkipMessage{Message: fmt.Sprintf("No baseline for %s on topology %q", component, topology)},
+			})
+			continue
+		case !ok:
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:        testName,
+				SkipMessage: &junitapi.SkipMessage{Message: fmt.Sprintf("No consumption was sampled for %s", component)},
+			})
+			continue
+		}
+
+		thresholds := []junitapi.JUnitThreshold{
+			{
+				Name:     "mean-cpu-cores",
+				Limit:    baseline.MeanCPUCores * regressionFactor,
+				Observed: observed.MeanCPUCores,
+				Unit:     "cores",
+				Exceeded: observed.MeanCPUCores > baseline.MeanCPUCores*regressionFactor,
+			},
+			{
+				Name:     "peak-memory-bytes",
+				Limit:    baseline.PeakMemoryBytes * regressionFactor,
+				Observed: observed.PeakMemoryBytes,
+				Unit:     "bytes",
+				Exceeded: observed.PeakMemoryBytes > baseline.PeakMemoryBytes*regressionFactor,
+			},
+		}
+		exceeded := []string{}
+		for _, threshold := range thresholds {
+			if threshold.Exceeded {
+				exceeded = append(exceeded, fmt.Sprintf("%s was %.3g %s, over the limit of %.3g", threshold.Name, threshold.Observed, threshold.Unit, threshold.Limit))
+			}
+		}
+		if len(exceeded) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:    testName,
+				Details: &junitapi.JUnitTestCaseDetails{Thresholds: thresholds},
+			})
+			continue
+		}
+
+		junit := junitfailure.NewFailure(regressionTemplate, "ResourceConsumptionRegressed").
+			Threshold(thresholds[0]).
+			Threshold(thresholds[1]).
+			Field("component", component).
+			Field("factor", regressionFactor).
+			Field("topology", topology).
+			Field("details", strings.Join(exceeded, ", ")).
+			TestCase(testName)
+		// a warning until the baselines have been collected across topologies.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
@@ -0,0 +1,138 @@
+package resourceconsumption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/dataloader"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type resourceConsumption struct {
+	adminRESTConfig    *rest.Config
+	topology           string
+	notSupportedReason error
+
+	// consumption is written during CollectData.
+	consumption []componentConsumption
+}
+
+// NewResourceConsumption samples the CPU and memory used by the nodes and the platform components during the run,
+// records when nodes were saturated, and holds the control plane components to their baselines.
+func NewResourceConsumption() monitortestframework.MonitorTest {
+	return &resourceConsumption{}
+}
+
+func (w *resourceConsumption) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, component := range controlPlaneComponents {
+		testNames = append(testNames, consumptionTestName(component))
+	}
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.WriteContentToStorageStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *resourceConsumption) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		// the baselines are skipped without a topology.
+		fmt.Printf("unable to determine the topology for resource consumption baselines: %v\n", err)
+		return nil
+	}
+	w.topology = jobType.Topology
+	return nil
+}
+
+func (w *resourceConsumption) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, w.adminRESTConfig)
+	if errors.Is(err, prometheusaccess.ErrMonitoringNotInstalled) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: err.Error()}
+		return nil, nil, w.notSupportedReason
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeRange := prometheusv1.Range{Start: beginning, End: end, Step: queryStep}
+	results := map[string]prometheustypes.Matrix{}
+	for _, query := range []string{nodeCPUQuery, nodeMemoryQuery, componentCPUQuery, componentMemoryQuery} {
+		matrix, err := prometheusaccess.QueryRange(ctx, prometheusClient, query, timeRange)
+		if err != nil {
+			return nil, nil, err
+		}
+		results[query] = matrix
+	}
+
+	w.consumption = summarizeConsumption(results[componentCPUQuery], results[componentMemoryQuery])
+	ret := saturationIntervals(results[nodeCPUQuery], monitorapi.NodeCPUSaturatedReason, "CPU", queryStep)
+	ret = append(ret, saturationIntervals(results[nodeMemoryQuery], monitorapi.NodeMemorySaturatedReason, "memory", queryStep)...)
+	return ret, nil, nil
+}
+
+func (w *resourceConsumption) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *resourceConsumption) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateConsumption(getDefaultBaselines(), w.topology, w.consumption), nil
+}
+
+func (w *resourceConsumption) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.notSupportedReason != nil {
+		return w.notSupportedReason
+	}
+
+	rows := []map[string]string{}
+	for _, consumption := range w.consumption {
+		rows = append(rows, map[string]string{
+			"Component":       consumption.Component,
+			"Topology":        w.topology,
+			"MeanCPUCores":    strconv.FormatFloat(consumption.MeanCPUCores, 'f', -1, 64),
+			"PeakCPUCores":    strconv.FormatFloat(consumption.PeakCPUCores, 'f', -1, 64),
+			"MeanMemoryBytes": strconv.FormatFloat(consumption.MeanMemoryBytes, 'f', -1, 64),
+			"PeakMemoryBytes": strconv.FormatFloat(consumption.PeakMemoryBytes, 'f', -1, 64),
+		})
+	}
+	dataFile := dataloader.DataFile{
+		TableName: "platform_resource_consumption",
+		Schema: map[string]dataloader.DataType{
+			"Component":       dataloader.DataTypeString,
+			"Topology":        dataloader.DataTypeString,
+			"MeanCPUCores":    dataloader.DataTypeFloat64,
+			"PeakCPUCores":    dataloader.DataTypeFloat64,
+			"MeanMemoryBytes": dataloader.DataTypeFloat64,
+			"PeakMemoryBytes": dataloader.DataTypeFloat64,
+		},
+		Rows: rows,
+	}
+	fileName := filepath.Join(storageDir, fmt.Sprintf("platform-resource-consumption%s-%s", timeSuffix, dataloader.AutoDataLoaderSuffix))
+	return dataloader.WriteDataFile(fileName, dataFile)
+}
+
+func (w *resourceConsumption) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
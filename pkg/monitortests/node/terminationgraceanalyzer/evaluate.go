@@ -0,0 +1,122 @@
+package terminationgraceanalyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	apiserverTestName = "[sig-api-machinery] apiservers and etcd should not be killed before graceful termination completes during node updates"
+	platformTestName  = "[sig-node] platform containers should honor their termination grace period during node updates"
+)
+
+// apiserverNamespaces run the servers whose lame-duck period keeps clients from seeing disruption while a node drains.
+var apiserverNamespaces = map[string]bool{
+	"openshift-kube-apiserver":  true,
+	"openshift-apiserver":       true,
+	"openshift-oauth-apiserver": true,
+	"openshift-etcd":            true,
+}
+
+var apiserverTerminationTemplate = junitfailure.MustParseTemplate("ungraceful-apiserver-termination",
+	`{{len .Intervals}} apiserver or etcd terminations during node updates did not complete gracefully.  Disrupted backends during these terminations: {{.Fields.disruptedBackends}}.
+{{.IntervalList}}`)
+
+var platformTerminationTemplate = junitfailure.MustParseTemplate("ungraceful-platform-termination",
+	`{{len .Intervals}} platform containers were killed during node updates instead of exiting within their grace period.  Disrupted backends during these terminations: {{.Fields.disruptedBackends}}.
+{{.IntervalList}}`)
+
+// evaluateTerminations fails for the platform containers killed while their node was updating, when the node is
+// drained and every container is expected to stop on its own.  Terminations outside node updates are liveness probe
+// failures and test actions, which other tests cover.
+func evaluateTerminations(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	updatesByNode := map[string]monitorapi.Intervals{}
+	disruptions := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		switch {
+		case interval.Source == monitorapi.SourceNodeState && interval.Message.Reason == monitorapi.NodeUpdateReason:
+			node := interval.Locator.Keys[monitorapi.LocatorNodeKey]
+			updatesByNode[node] = append(updatesByNode[node], interval)
+		case interval.Source == monitorapi.SourceDisruption && interval.Level == monitorapi.Error:
+			disruptions = append(disruptions, interval)
+		}
+	}
+
+	apiserverFailures, platformFailures := monitorapi.Intervals{}, monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		switch {
+		case interval.Source == monitorapi.SourceTerminationGrace && interval.Message.Reason == monitorapi.ContainerReasonUngracefulTermination:
+			if !during(interval.To, updatesByNode[interval.Locator.Keys[monitorapi.LocatorNodeKey]]) {
+				continue
+			}
+			if apiserverNamespaces[interval.Locator.Keys[monitorapi.LocatorNamespaceKey]] {
+				apiserverFailures = append(apiserverFailures, interval)
+			} else {
+				platformFailures = append(platformFailures, interval)
+			}
+
+		case interval.Source == monitorapi.APIServerGracefulShutdown && interval.Message.Reason == monitorapi.IncompleteAPIServerShutdown:
+			if during(interval.From, updatesByNode[interval.Locator.Keys[monitorapi.LocatorNodeKey]]) {
+				apiserverFailures = append(apiserverFailures, interval)
+			}
+		}
+	}
+
+	return []*junitapi.JUnitTestCase{
+		terminationTestCase(apiserverTestName, apiserverTerminationTemplate, apiserverFailures, disruptions),
+		terminationTestCase(platformTestName, platformTerminationTemplate, platformFailures, disruptions),
+	}
+}
+
+func terminationTestCase(testName string, tmpl *junitfailure.Template, failures, disruptions monitorapi.Intervals) *junitapi.JUnitTestCase {
+	if len(failures) == 0 {
+		return &junitapi.JUnitTestCase{Name: testName}
+	}
+	sort.Sort(failures)
+	junit := junitfailure.NewFailure(tmpl, "UngracefulTermination").
+		Field("disruptedBackends", disruptedBackends(failures, disruptions)).
+		Intervals(failures...).
+		TestCase(testName)
+	// a warning until we know how often platform containers outlive their grace period during drains.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+// during returns whether the time falls in one of the windows.
+func during(t time.Time, windows monitorapi.Intervals) bool {
+	for _, window := range windows {
+		if !t.Before(window.From) && (window.To.IsZero() || !t.After(window.To)) {
+			return true
+		}
+	}
+	return false
+}
+
+// disruptedBackends names the backends that were disrupted while one of the failures happened.  An incomplete
+// shutdown never ended, so it overlaps every disruption after it began.
+func disruptedBackends(failures, disruptions monitorapi.Intervals) string {
+	backends := map[string]bool{}
+	for _, disruption := range disruptions {
+		for _, failure := range failures {
+			if disruption.To.Before(failure.From) || (!failure.To.IsZero() && disruption.From.After(failure.To)) {
+				continue
+			}
+			backends[disruption.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey]] = true
+			break
+		}
+	}
+	if len(backends) == 0 {
+		return "none"
+	}
+	ret := []string{}
+	for backend := range backends {
+		ret = append(ret, backend)
+	}
+	sort.Strings(ret)
+	return strings.Join(ret, ", ")
+}
@@ -0,0 +1,61 @@
+package terminationgraceanalyzer
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+// terminationGraceAnalyzer correlates the kubelet stopping platform containers with the pod watcher seeing them exit
+// to find the containers killed before they finished terminating gracefully.
+type terminationGraceAnalyzer struct {
+}
+
+func NewTerminationGraceAnalyzer() monitortestframework.MonitorTest {
+	return &terminationGraceAnalyzer{}
+}
+
+func (*terminationGraceAnalyzer) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{apiserverTestName, platformTestName},
+	}
+}
+
+var _ monitortestframework.OfflineMonitorTest = &terminationGraceAnalyzer{}
+
+func (*terminationGraceAnalyzer) SupportsOffline() bool {
+	return true
+}
+
+func (*terminationGraceAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (*terminationGraceAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (*terminationGraceAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return ungracefulTerminationIntervals(startingIntervals), nil
+}
+
+func (*terminationGraceAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return evaluateTerminations(finalIntervals), nil
+}
+
+func (*terminationGraceAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*terminationGraceAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
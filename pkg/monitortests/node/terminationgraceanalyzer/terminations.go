@@ -0,0 +1,106 @@
+package terminationgraceanalyzer
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const (
+	// sigkillExitCode is the exit code of a container killed with SIGKILL, 128 plus the signal.
+	sigkillExitCode = "137"
+
+	oomKilledCause = "OOMKilled"
+)
+
+// containerKey identifies a container by name.  The kubelet reports static pods with the UID of their manifest, not
+// the UID of their mirror pod, so the UID cannot be used to match the kubelet logs to the pod watcher.
+type containerKey struct {
+	namespace string
+	pod       string
+	container string
+}
+
+func containerKeyFrom(locator monitorapi.Locator) containerKey {
+	return containerKey{
+		namespace: locator.Keys[monitorapi.LocatorNamespaceKey],
+		pod:       locator.Keys[monitorapi.LocatorPodKey],
+		container: locator.Keys[monitorapi.LocatorContainerKey],
+	}
+}
+
+// ungracefulTerminationIntervals produces an interval for every platform container killed with SIGKILL, from the time
+// the kubelet asked it to stop until the pod watcher saw it exit.  Containers killed by the kernel for running out of
+// memory are reported by the container restart analyzer.
+func ungracefulTerminationIntervals(startingIntervals monitorapi.Intervals) monitorapi.Intervals {
+	killsByContainer := map[containerKey]monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		if interval.Source == monitorapi.SourceKubeletLog && interval.Message.Reason == monitorapi.ContainerReasonKilling {
+			key := containerKeyFrom(interval.Locator)
+			killsByContainer[key] = append(killsByContainer[key], interval)
+		}
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourcePodMonitor || interval.Message.Reason != monitorapi.ContainerReasonContainerExit {
+			continue
+		}
+		if !strings.HasPrefix(interval.Locator.Keys[monitorapi.LocatorNamespaceKey], "openshift-") {
+			continue
+		}
+		if interval.Message.Annotations[monitorapi.AnnotationContainerExitCode] != sigkillExitCode ||
+			interval.Message.Annotations[monitorapi.AnnotationCause] == oomKilledCause {
+			continue
+		}
+		ret = append(ret, ungracefulTerminationInterval(interval, lastKillBefore(killsByContainer[containerKeyFrom(interval.Locator)], interval.From)))
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+// lastKillBefore returns the last time the kubelet asked the container to stop before it exited, or nil.
+func lastKillBefore(kills monitorapi.Intervals, exit time.Time) *monitorapi.Interval {
+	var ret *monitorapi.Interval
+	for i := range kills {
+		if kills[i].From.After(exit) {
+			continue
+		}
+		if ret == nil || kills[i].From.After(ret.From) {
+			ret = &kills[i]
+		}
+	}
+	return ret
+}
+
+func ungracefulTerminationInterval(exit monitorapi.Interval, kill *monitorapi.Interval) monitorapi.Interval {
+	message := monitorapi.NewMessage().Reason(monitorapi.ContainerReasonUngracefulTermination).
+		Constructed(monitorapi.ConstructionOwnerTermination).
+		WithAnnotation(monitorapi.AnnotationContainerExitCode, sigkillExitCode)
+
+	from := exit.From
+	switch {
+	case kill == nil:
+		message = message.HumanMessage("container was killed without the kubelet asking it to stop gracefully")
+	default:
+		from = kill.From
+		gracePeriod := kill.Message.Annotations[monitorapi.AnnotationGracePeriod]
+		message = message.WithAnnotation(monitorapi.AnnotationGracePeriod, gracePeriod)
+		seconds, err := strconv.Atoi(gracePeriod)
+		if err == nil && exit.From.Sub(kill.From) < time.Duration(seconds)*time.Second {
+			message = message.HumanMessagef("container was killed %s after it was asked to stop, before its %ss grace period ended",
+				exit.From.Sub(kill.From).Round(time.Second), gracePeriod)
+		} else {
+			message = message.HumanMessagef("container did not stop within its %ss grace period and was killed", gracePeriod)
+		}
+	}
+
+	return monitorapi.NewInterval(monitorapi.SourceTerminationGrace, monitorapi.Warning).
+		Locator(exit.Locator).
+		Message(message).
+		Display().
+		Build(from, exit.From)
+}
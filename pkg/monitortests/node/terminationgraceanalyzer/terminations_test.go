@@ -0,0 +1,120 @@
+package terminationgraceanalyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func killing(namespace, pod, container, gracePeriod string, at time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceKubeletLog, monitorapi.Info).
+		Locator(monitorapi.NewLocator().ContainerFromNames(namespace, pod, "static-uid", container)).
+		Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonKilling).
+			WithAnnotation(monitorapi.AnnotationGracePeriod, gracePeriod)).
+		Build(start.Add(at), start.Add(at))
+}
+
+func exit(namespace, pod, container, node, code, cause string, at time.Duration) monitorapi.Interval {
+	locator := monitorapi.NewLocator().ContainerFromNames(namespace, pod, "mirror-uid", container)
+	locator.Keys[monitorapi.LocatorNodeKey] = node
+	return monitorapi.NewInterval(monitorapi.SourcePodMonitor, monitorapi.Error).
+		Locator(locator).
+		Message(monitorapi.NewMessage().Reason(monitorapi.ContainerReasonContainerExit).
+			WithAnnotation(monitorapi.AnnotationContainerExitCode, code).
+			Cause(cause)).
+		Build(start.Add(at), start.Add(at))
+}
+
+func TestUngracefulTerminationIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		// killed before its grace period ended.
+		killing("openshift-kube-apiserver", "kube-apiserver-master-0", "kube-apiserver", "135", 0),
+		exit("openshift-kube-apiserver", "kube-apiserver-master-0", "kube-apiserver", "master-0", "137", "Error", 10*time.Second),
+		// did not stop within its grace period.
+		killing("openshift-dns", "dns-default-abcde", "dns", "30", time.Minute),
+		exit("openshift-dns", "dns-default-abcde", "dns", "worker-0", "137", "Error", 2*time.Minute),
+		// exited gracefully.
+		killing("openshift-etcd", "etcd-master-0", "etcd", "30", 3*time.Minute),
+		exit("openshift-etcd", "etcd-master-0", "etcd", "master-0", "0", "Completed", 3*time.Minute+10*time.Second),
+		// OOMKilled and test namespaces are not ours to evaluate.
+		exit("openshift-monitoring", "prometheus-k8s-0", "prometheus", "worker-0", "137", "OOMKilled", 4*time.Minute),
+		exit("e2e-test-abcde", "pause", "pause", "worker-0", "137", "Error", 4*time.Minute),
+		// killed without being asked to stop.
+		exit("openshift-ingress", "router-default-abcde", "router", "worker-1", "137", "Error", 5*time.Minute),
+	}
+
+	terminations := ungracefulTerminationIntervals(intervals)
+	if len(terminations) != 3 {
+		t.Fatalf("expected three ungraceful terminations, got %v", terminations)
+	}
+	expected := []struct {
+		namespace string
+		from      time.Time
+		message   string
+	}{
+		{namespace: "openshift-kube-apiserver", from: start, message: "before its 135s grace period ended"},
+		{namespace: "openshift-dns", from: start.Add(time.Minute), message: "did not stop within its 30s grace period"},
+		{namespace: "openshift-ingress", from: start.Add(5 * time.Minute), message: "without the kubelet asking it to stop"},
+	}
+	for i, want := range expected {
+		got := terminations[i]
+		if got.Locator.Keys[monitorapi.LocatorNamespaceKey] != want.namespace {
+			t.Errorf("expected termination %d in %s, got %v", i, want.namespace, got.Locator)
+			continue
+		}
+		if !got.From.Equal(want.from) {
+			t.Errorf("expected the termination in %s to start at %v, got %v", want.namespace, want.from, got.From)
+		}
+		if !strings.Contains(got.Message.HumanMessage, want.message) {
+			t.Errorf("expected the termination in %s to say %q, got %q", want.namespace, want.message, got.Message.HumanMessage)
+		}
+	}
+}
+
+func TestEvaluateTerminations(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		killing("openshift-kube-apiserver", "kube-apiserver-master-0", "kube-apiserver", "135", 10*time.Minute),
+		exit("openshift-kube-apiserver", "kube-apiserver-master-0", "kube-apiserver", "master-0", "137", "Error", 10*time.Minute+10*time.Second),
+		// not during a node update, a liveness probe failure for instance.
+		exit("openshift-dns", "dns-default-abcde", "dns", "worker-0", "137", "Error", 10*time.Minute),
+	}
+	finalIntervals := append(intervals, ungracefulTerminationIntervals(intervals)...)
+	finalIntervals = append(finalIntervals,
+		monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+			Locator(monitorapi.NewLocator().NodeFromName("master-0")).
+			Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason)).
+			Build(start.Add(5*time.Minute), start.Add(15*time.Minute)),
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(monitorapi.NewLocator().LocateDisruptionCheck("kube-api-new-connections", "kube-api-new-connections", monitorapi.NewConnectionType)).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason)).
+			Build(start.Add(10*time.Minute+5*time.Second), start.Add(10*time.Minute+8*time.Second)),
+	)
+
+	junits := evaluateTerminations(finalIntervals)
+	if len(junits) != 2 {
+		t.Fatalf("expected two tests, got %v", junits)
+	}
+	for _, junit := range junits {
+		switch junit.Name {
+		case apiserverTestName:
+			if junit.FailureOutput == nil {
+				t.Fatalf("expected the apiserver killed during the update of its node to fail")
+			}
+			if junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning, got %q", junit.Details.Severity)
+			}
+			if !strings.Contains(junit.FailureOutput.Output, "kube-api-new-connections") {
+				t.Errorf("expected the failure to name the disrupted backend, got %q", junit.FailureOutput.Output)
+			}
+		case platformTestName:
+			if junit.FailureOutput != nil {
+				t.Errorf("expected terminations outside node updates to pass, got %v", junit.FailureOutput.Output)
+			}
+		}
+	}
+}
@@ -19,6 +19,12 @@ func NewPodWatcher() monitortestframework.MonitorTest {
 	return &podWatcher{}
 }
 
+var _ monitortestframework.OfflineMonitorTest = &podWatcher{}
+
+func (*podWatcher) SupportsOffline() bool {
+	return true
+}
+
 func (w *podWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	kubeClient, err := kubernetes.NewForConfig(adminRESTConfig)
 	if err != nil {
@@ -0,0 +1,73 @@
+package olmhealth
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// olmCheck is one test, failing for the intervals of a reason that lasted longer than its tolerance.
+type olmCheck struct {
+	testName string
+	reason   monitorapi.IntervalReason
+	// tolerance allows for the catalog pods and OLM itself moving between nodes during control plane updates.
+	tolerance   time.Duration
+	explanation string
+}
+
+var olmChecks = []olmCheck{
+	{
+		testName:    "[sig-operator] OLM catalog sources should stay ready",
+		reason:      monitorapi.CatalogSourceNotReadyReason,
+		tolerance:   5 * time.Minute,
+		explanation: "Operators cannot be installed or upgraded from a catalog OLM cannot connect to.",
+	},
+	{
+		testName:    "[sig-operator] OLM subscriptions should resolve",
+		reason:      monitorapi.SubscriptionResolutionFailedReason,
+		tolerance:   5 * time.Minute,
+		explanation: "A subscription that does not resolve cannot install or upgrade its operator.",
+	},
+	{
+		testName:    "[sig-operator] OLM install plans should not stall",
+		reason:      monitorapi.InstallPlanPendingReason,
+		tolerance:   10 * time.Minute,
+		explanation: "An install plan that does not complete leaves its operator partially installed.",
+	},
+	{
+		testName:    "[sig-operator] OLM install plans should not fail",
+		reason:      monitorapi.InstallPlanFailedReason,
+		explanation: "A failed install plan is not retried until the subscription is changed.",
+	},
+}
+
+var olmTemplate = junitfailure.MustParseTemplate("olm-health",
+	`{{len .Intervals}} times {{.Fields.reason}} lasted longer than {{.Fields.tolerance}}.  {{.Fields.explanation}}
+{{.IntervalList}}`)
+
+// evaluateOLM returns a test for every check.
+func evaluateOLM(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, check := range olmChecks {
+		exceeded := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+			return interval.Source == monitorapi.SourceOLM && interval.Message.Reason == check.reason &&
+				interval.To.Sub(interval.From) > check.tolerance
+		})
+		if len(exceeded) == 0 {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: check.testName})
+			continue
+		}
+		junit := junitfailure.NewFailure(olmTemplate, string(check.reason)).
+			Field("reason", string(check.reason)).
+			Field("tolerance", check.tolerance.String()).
+			Field("explanation", check.explanation).
+			Intervals(exceeded...).
+			TestCase(check.testName)
+		// a warning until we know how often catalogs and install plans recover on their own during upgrades.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
@@ -0,0 +1,97 @@
+package olmhealth
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	exutil "github.com/openshift/origin/test/extended/util"
+)
+
+type olmHealth struct {
+	notSupportedReason error
+	tracker            *olmTracker
+	clock              clock.PassiveClock
+}
+
+// NewOLMHealth records when catalog sources are not ready, subscriptions do not resolve, and install plans stall or
+// fail, so that upgrades can show OLM-managed operators stayed installable.
+func NewOLMHealth() monitortestframework.MonitorTest {
+	return &olmHealth{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &olmHealth{}
+
+func (w *olmHealth) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *olmHealth) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, check := range olmChecks {
+		testNames = append(testNames, check.testName)
+	}
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *olmHealth) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	olmInstalled, err := exutil.DoesApiResourceExist(adminRESTConfig, catalogSourceResource.Resource, catalogSourceResource.Group)
+	if err != nil {
+		return err
+	}
+	if !olmInstalled {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "OLM is not installed"}
+		return w.notSupportedReason
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.tracker = newOLMTracker(recorder, w.clock)
+	startOLMMonitoring(ctx, w.tracker, dynamicClient)
+	return nil
+}
+
+func (w *olmHealth) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	// the intervals are in the recorder, only the problems still open have to be closed.
+	w.tracker.finish(end)
+	return nil, nil, nil
+}
+
+func (w *olmHealth) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *olmHealth) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	return evaluateOLM(finalIntervals), nil
+}
+
+func (w *olmHealth) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *olmHealth) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,96 @@
+package olmhealth
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+var (
+	catalogSourceResource = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "catalogsources"}
+	subscriptionResource  = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "subscriptions"}
+	installPlanResource   = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "installplans"}
+)
+
+// problem is what is wrong with an OLM object, recorded as an interval for as long as it lasts.
+type problem struct {
+	reason  monitorapi.IntervalReason
+	level   monitorapi.IntervalLevel
+	message string
+}
+
+// problemFunc returns what is wrong with an object, or nil when it is healthy.
+type problemFunc func(obj *unstructured.Unstructured) *problem
+
+var problemFuncs = map[schema.GroupVersionResource]problemFunc{
+	catalogSourceResource: catalogSourceProblem,
+	subscriptionResource:  subscriptionProblem,
+	installPlanResource:   installPlanProblem,
+}
+
+// catalogSourceProblem reports a catalog whose registry the catalog operator cannot connect to.  A new catalog has
+// no connection state until the first attempt, which is not yet a problem.
+func catalogSourceProblem(obj *unstructured.Unstructured) *problem {
+	state, _, _ := unstructured.NestedString(obj.Object, "status", "connectionState", "lastObservedState")
+	if len(state) == 0 || state == "READY" {
+		return nil
+	}
+	return &problem{
+		reason:  monitorapi.CatalogSourceNotReadyReason,
+		level:   monitorapi.Warning,
+		message: fmt.Sprintf("catalog registry connection is %s", state),
+	}
+}
+
+// subscriptionProblem reports a subscription OLM cannot resolve to an installable bundle.
+func subscriptionProblem(obj *unstructured.Unstructured) *problem {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, curr := range conditions {
+		condition, ok := curr.(map[string]interface{})
+		if !ok || condition["type"] != "ResolutionFailed" || condition["status"] != "True" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return &problem{
+			reason:  monitorapi.SubscriptionResolutionFailedReason,
+			level:   monitorapi.Error,
+			message: fmt.Sprintf("resolution failed: %s", message),
+		}
+	}
+	return nil
+}
+
+// installPlanProblem reports an install plan that failed, or that is still being worked on.  Plans waiting for
+// manual approval are waiting on purpose.
+func installPlanProblem(obj *unstructured.Unstructured) *problem {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Complete", "RequiresApproval":
+		return nil
+	case "Failed":
+		message := ""
+		conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		for _, curr := range conditions {
+			if condition, ok := curr.(map[string]interface{}); ok && condition["type"] == "Installed" {
+				message, _ = condition["message"].(string)
+			}
+		}
+		return &problem{
+			reason:  monitorapi.InstallPlanFailedReason,
+			level:   monitorapi.Error,
+			message: fmt.Sprintf("install plan failed: %s", message),
+		}
+	default:
+		if len(phase) == 0 {
+			phase = "Planning"
+		}
+		return &problem{
+			reason:  monitorapi.InstallPlanPendingReason,
+			level:   monitorapi.Info,
+			message: fmt.Sprintf("install plan is %s", phase),
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package olmhealth
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func object(status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+}
+
+func TestProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		problem  problemFunc
+		obj      *unstructured.Unstructured
+		expected monitorapi.IntervalReason
+	}{
+		{
+			name:    "catalog ready",
+			problem: catalogSourceProblem,
+			obj:     object(map[string]interface{}{"connectionState": map[string]interface{}{"lastObservedState": "READY"}}),
+		},
+		{
+			name:    "catalog not yet connected",
+			problem: catalogSourceProblem,
+			obj:     object(map[string]interface{}{}),
+		},
+		{
+			name:     "catalog failing",
+			problem:  catalogSourceProblem,
+			obj:      object(map[string]interface{}{"connectionState": map[string]interface{}{"lastObservedState": "TRANSIENT_FAILURE"}}),
+			expected: monitorapi.CatalogSourceNotReadyReason,
+		},
+		{
+			name:    "subscription resolved",
+			problem: subscriptionProblem,
+			obj: object(map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "ResolutionFailed", "status": "False"},
+			}}),
+		},
+		{
+			name:    "subscription not resolved",
+			problem: subscriptionProblem,
+			obj: object(map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "CatalogSourcesUnhealthy", "status": "False"},
+				map[string]interface{}{"type": "ResolutionFailed", "status": "True", "message": "no operators found"},
+			}}),
+			expected: monitorapi.SubscriptionResolutionFailedReason,
+		},
+		{
+			name:    "install plan waiting for approval",
+			problem: installPlanProblem,
+			obj:     object(map[string]interface{}{"phase": "RequiresApproval"}),
+		},
+		{
+			name:     "install plan installing",
+			problem:  installPlanProblem,
+			obj:      object(map[string]interface{}{"phase": "Installing"}),
+			expected: monitorapi.InstallPlanPendingReason,
+		},
+		{
+			name:     "install plan failed",
+			problem:  installPlanProblem,
+			obj:      object(map[string]interface{}{"phase": "Failed"}),
+			expected: monitorapi.InstallPlanFailedReason,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.problem(test.obj)
+			switch {
+			case len(test.expected) == 0 && actual != nil:
+				t.Errorf("expected no problem, got %v", actual.reason)
+			case len(test.expected) > 0 && actual == nil:
+				t.Errorf("expected %v, got no problem", test.expected)
+			case len(test.expected) > 0 && actual.reason != test.expected:
+				t.Errorf("expected %v, got %v", test.expected, actual.reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateOLM(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	olmInterval := func(reason monitorapi.IntervalReason, duration time.Duration) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceOLM, monitorapi.Warning).
+			Locator(monitorapi.NewLocator().KindInNamespace("CatalogSource", "openshift-marketplace", "redhat-operators")).
+			Message(monitorapi.NewMessage().Reason(reason)).
+			Build(start, start.Add(duration))
+	}
+
+	junits := evaluateOLM(monitorapi.Intervals{
+		// a catalog pod moving during a node reboot.
+		olmInterval(monitorapi.CatalogSourceNotReadyReason, 2*time.Minute),
+		olmInterval(monitorapi.InstallPlanPendingReason, 20*time.Minute),
+	})
+	if len(junits) != len(olmChecks) {
+		t.Fatalf("expected a test per check, got %v", junits)
+	}
+	for _, junit := range junits {
+		failed := junit.FailureOutput != nil
+		if stalled := junit.Name == "[sig-operator] OLM install plans should not stall"; failed != stalled {
+			t.Errorf("expected %q to fail=%v, got %v", junit.Name, stalled, junit.FailureOutput)
+		}
+	}
+}
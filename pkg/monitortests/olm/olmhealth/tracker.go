@@ -0,0 +1,119 @@
+package olmhealth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+type objectKey struct {
+	resource        schema.GroupVersionResource
+	namespace, name string
+}
+
+type openProblem struct {
+	reason monitorapi.IntervalReason
+	id     int
+}
+
+// olmTracker records an interval for as long as an OLM object has a problem.  A problem that changes, for instance an
+// install plan going from pending to failed, ends one interval and starts the next.
+type olmTracker struct {
+	recorder monitorapi.RecorderWriter
+	clock    clock.PassiveClock
+
+	lock sync.Mutex
+	open map[objectKey]openProblem
+}
+
+func newOLMTracker(recorder monitorapi.RecorderWriter, clock clock.PassiveClock) *olmTracker {
+	return &olmTracker{
+		recorder: recorder,
+		clock:    clock,
+		open:     map[objectKey]openProblem{},
+	}
+}
+
+func startOLMMonitoring(ctx context.Context, tracker *olmTracker, client dynamic.Interface) {
+	dynamicInformers := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	for resource := range problemFuncs {
+		resource := resource
+		dynamicInformers.ForResource(resource).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if u, ok := obj.(*unstructured.Unstructured); ok {
+					tracker.observe(resource, u, tracker.clock.Now())
+				}
+			},
+			UpdateFunc: func(_, obj interface{}) {
+				if u, ok := obj.(*unstructured.Unstructured); ok {
+					tracker.observe(resource, u, tracker.clock.Now())
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				if u, ok := obj.(*unstructured.Unstructured); ok {
+					tracker.end(objectKey{resource: resource, namespace: u.GetNamespace(), name: u.GetName()}, tracker.clock.Now())
+				}
+			},
+		})
+	}
+	dynamicInformers.Start(ctx.Done())
+}
+
+func (t *olmTracker) observe(resource schema.GroupVersionResource, obj *unstructured.Unstructured, now time.Time) {
+	key := objectKey{resource: resource, namespace: obj.GetNamespace(), name: obj.GetName()}
+	current := problemFuncs[resource](obj)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if existing, ok := t.open[key]; ok {
+		if current != nil && current.reason == existing.reason {
+			return
+		}
+		t.recorder.EndInterval(existing.id, now)
+		delete(t.open, key)
+	}
+	if current == nil {
+		return
+	}
+	t.open[key] = openProblem{
+		reason: current.reason,
+		id: t.recorder.StartInterval(
+			monitorapi.NewInterval(monitorapi.SourceOLM, current.level).
+				Locator(monitorapi.NewLocator().KindInNamespace(obj.GetKind(), obj.GetNamespace(), obj.GetName())).
+				Message(monitorapi.NewMessage().Reason(current.reason).HumanMessage(current.message)).
+				Display().
+				Build(now, time.Time{}),
+		),
+	}
+}
+
+func (t *olmTracker) end(key objectKey, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if existing, ok := t.open[key]; ok {
+		t.recorder.EndInterval(existing.id, now)
+		delete(t.open, key)
+	}
+}
+
+// finish ends the intervals of problems still open at the end of the run.
+func (t *olmTracker) finish(end time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for key, existing := range t.open {
+		t.recorder.EndInterval(existing.id, end)
+		delete(t.open, key)
+	}
+}
@@ -0,0 +1,122 @@
+package volumeoperationlatency
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const drainBudgetTestName = "[sig-storage] volume operations should complete within the platform budget during node drains"
+
+// drainBudgetByPlatform is how long an operation may take while pods move off a draining node.  The volumes of the
+// evicted pods are detached from the draining node and attached to the new one, which takes longer on platforms
+// with slower cloud volume APIs.
+var drainBudgetByPlatform = map[string]time.Duration{
+	"aws":       3 * time.Minute,
+	"gcp":       3 * time.Minute,
+	"azure":     5 * time.Minute,
+	"vsphere":   5 * time.Minute,
+	"openstack": 5 * time.Minute,
+}
+
+const defaultDrainBudget = 3 * time.Minute
+
+// drainSettle is how long after a drain the evicted pods are still starting on other nodes.
+const drainSettle = 5 * time.Minute
+
+func drainBudgetFor(platform string) time.Duration {
+	if budget, ok := drainBudgetByPlatform[platform]; ok {
+		return budget
+	}
+	return defaultDrainBudget
+}
+
+// drainWindows returns the times nodes were draining, extended by the time their pods take to start elsewhere.
+func drainWindows(finalIntervals monitorapi.Intervals) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceNodeState || interval.Message.Reason != monitorapi.NodeUpdateReason {
+			continue
+		}
+		if interval.Message.Annotations[monitorapi.AnnotationPhase] != "Drain" {
+			continue
+		}
+		window := interval
+		window.To = interval.To.Add(drainSettle)
+		ret = append(ret, window)
+	}
+	return ret
+}
+
+func overlapsAny(interval monitorapi.Interval, windows monitorapi.Intervals) bool {
+	for _, window := range windows {
+		if !interval.From.After(window.To) && !window.From.After(interval.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// operationDuration is the duration the stuck or slow interval recorded, or zero if it recorded none.
+func operationDuration(interval monitorapi.Interval) time.Duration {
+	duration, err := time.ParseDuration(interval.Message.Annotations[monitorapi.AnnotationDuration])
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+var drainBudgetTemplate = junitfailure.MustParseTemplate("volume-operation-drain-budget",
+	`{{len .Intervals}} volume operations took longer than the {{.Fields.budget}} allowed on {{.Fields.platform}} while nodes were draining.  Pods moved off a draining node cannot start until their volumes are attached and mounted on the new node.
+{{.IntervalList}}`)
+
+// evaluateDrainBudget fails for operations overlapping a drain that took longer than the budget of the platform.
+func evaluateDrainBudget(platform string, finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	budget := drainBudgetFor(platform)
+	drains := drainWindows(finalIntervals)
+
+	overBudget := monitorapi.Intervals{}
+	var longest time.Duration
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceVolumeOperation {
+			continue
+		}
+		duration := operationDuration(interval)
+		if duration <= budget || !overlapsAny(interval, drains) {
+			continue
+		}
+		overBudget = append(overBudget, interval)
+		if duration > longest {
+			longest = duration
+		}
+	}
+
+	threshold := junitapi.JUnitThreshold{
+		Name:     "volume-operation-seconds-during-drain",
+		Limit:    budget.Seconds(),
+		Observed: longest.Seconds(),
+		Unit:     "seconds",
+		Exceeded: len(overBudget) > 0,
+	}
+	if !threshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name:    drainBudgetTestName,
+			Details: &junitapi.JUnitTestCaseDetails{Thresholds: []junitapi.JUnitThreshold{threshold}},
+		}
+	}
+
+	if len(platform) == 0 {
+		platform = "this platform"
+	}
+	junit := junitfailure.NewFailure(drainBudgetTemplate, "VolumeOperationOverDrainBudget").
+		Threshold(threshold).
+		Field("budget", budget.String()).
+		Field("platform", platform).
+		Intervals(overBudget...).
+		TestCase(drainBudgetTestName)
+	// a warning until we know the budgets hold across platforms.
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
@@ -0,0 +1,53 @@
+package volumeoperationlatency
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+)
+
+const (
+	queryStep = 30 * time.Second
+
+	// operationDurationQuery covers the operations of the attach/detach controller, the kubelet, and the
+	// provisioners, by the plugin that performed them.
+	operationDurationQuery = `histogram_quantile(0.99, sum by (le, operation_name, volume_plugin) (rate(storage_operation_duration_seconds_bucket{operation_name=~"volume_attach|volume_detach|volume_mount|volume_unmount|volume_provision"}[5m])))`
+
+	// largestBucket is the largest finite bucket of storage_operation_duration_seconds.  The quantile is infinite
+	// when the slowest operations are in the +Inf bucket, and those took at least this long.
+	largestBucket = 600 * time.Second
+)
+
+// slowOperationIntervals reports the windows in which the 99th percentile duration of an operation of a plugin was
+// longer than the stuck threshold.
+func slowOperationIntervals(matrix prometheustypes.Matrix, step time.Duration) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, series := range matrix {
+		operation := strings.TrimPrefix(string(series.Metric["operation_name"]), "volume_")
+		plugin := string(series.Metric["volume_plugin"])
+		matches := func(value float64) bool { return value > stuckThreshold.Seconds() }
+		for _, window := range prometheusaccess.Windows(series.Values, step, matches) {
+			peak := time.Duration(window.Peak * float64(time.Second))
+			if math.IsInf(window.Peak, 1) {
+				peak = largestBucket
+			}
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceVolumeOperation, monitorapi.Warning).
+					Locator(monitorapi.NewLocator().VolumePlugin(plugin)).
+					Message(monitorapi.NewMessage().Reason(monitorapi.VolumeOperationSlowReason).
+						WithAnnotation(monitorapi.AnnotationOperation, operation).
+						WithAnnotation(monitorapi.AnnotationDuration, fmt.Sprintf("%.3fs", peak.Seconds())).
+						HumanMessagef("99th percentile %s duration peaked at %s, more than %s", operation, peak.Round(time.Second), stuckThreshold)).
+					Display().
+					Build(window.From, window.To),
+			)
+		}
+	}
+	return ret
+}
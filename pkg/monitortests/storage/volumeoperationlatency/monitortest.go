@@ -0,0 +1,87 @@
+package volumeoperationlatency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type volumeOperationLatency struct {
+	adminRESTConfig *rest.Config
+	platform        string
+}
+
+// NewVolumeOperationLatency tracks how long volumes take to attach, mount, and provision, and holds them to a
+// platform budget while nodes drain.
+func NewVolumeOperationLatency() monitortestframework.MonitorTest {
+	return &volumeOperationLatency{}
+}
+
+func (w *volumeOperationLatency) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{drainBudgetTestName},
+	}
+}
+
+func (w *volumeOperationLatency) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
+
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		// the default budget is used.
+		fmt.Printf("unable to determine the platform for volume operation budgets: %v\n", err)
+		return nil
+	}
+	w.platform = jobType.Platform
+	return nil
+}
+
+func (w *volumeOperationLatency) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, w.adminRESTConfig)
+	if errors.Is(err, prometheusaccess.ErrMonitoringNotInstalled) {
+		// the stuck operations from the events are still evaluated.
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeRange := prometheusv1.Range{Start: beginning, End: end, Step: queryStep}
+	durations, err := prometheusaccess.QueryRange(ctx, prometheusClient, operationDurationQuery, timeRange)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slowOperationIntervals(durations, queryStep), nil, nil
+}
+
+func (*volumeOperationLatency) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return stuckOperationIntervals(startingIntervals), nil
+}
+
+func (w *volumeOperationLatency) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return []*junitapi.JUnitTestCase{evaluateDrainBudget(w.platform, finalIntervals)}, nil
+}
+
+func (*volumeOperationLatency) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*volumeOperationLatency) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,152 @@
+package volumeoperationlatency
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// stuckThreshold is how long an operation may keep failing before it is reported as stuck.  Attach and provision
+// retries back off to about two minutes, so an operation stuck longer than that failed several times.
+const stuckThreshold = 2 * time.Minute
+
+// volumeOperation describes how the events of one kind of volume operation mark it failing and succeeding.
+type volumeOperation struct {
+	name string
+	// startReasons mark the operation waiting, or failing and being retried.
+	startReasons sets.String
+	// endReasons mark the operation succeeding.  Operations without one end with their last failure.
+	endReasons sets.String
+}
+
+var volumeOperations = []volumeOperation{
+	{
+		name:         "attach",
+		startReasons: sets.NewString("FailedAttachVolume"),
+		endReasons:   sets.NewString("SuccessfulAttachVolume"),
+	},
+	{
+		name:         "mount",
+		startReasons: sets.NewString("FailedMount", "FailedMapVolume"),
+		endReasons:   sets.NewString(),
+	},
+	{
+		name:         "provision",
+		startReasons: sets.NewString("ExternalProvisioning", "Provisioning", "ProvisioningFailed"),
+		endReasons:   sets.NewString("ProvisioningSucceeded"),
+	},
+}
+
+// eventTimes returns when a kube event was first and last seen, falling back to the interval when the event did not
+// record them.
+func eventTimes(interval monitorapi.Interval) (time.Time, time.Time) {
+	first, last := interval.From, interval.To
+	if t, err := time.Parse(time.RFC3339, interval.Message.Annotations["firstTimestamp"]); err == nil && !t.IsZero() {
+		first = t
+	}
+	if t, err := time.Parse(time.RFC3339, interval.Message.Annotations["lastTimestamp"]); err == nil && !t.IsZero() {
+		last = t
+	}
+	if last.Before(first) {
+		last = first
+	}
+	return first, last
+}
+
+// objectLocator drops the message hash from the locator of an event, so every event about an object is grouped.
+func objectLocator(locator monitorapi.Locator) monitorapi.Locator {
+	ret := monitorapi.Locator{Type: locator.Type, Keys: map[monitorapi.LocatorKey]string{}}
+	for k, v := range locator.Keys {
+		if k != monitorapi.LocatorHmsgKey {
+			ret.Keys[k] = v
+		}
+	}
+	return ret
+}
+
+type operationEvent struct {
+	first, last time.Time
+	end         bool
+}
+
+type objectOperation struct {
+	operation string
+	object    string
+}
+
+// stuckOperationIntervals finds the operations on every pod and claim that kept failing for longer than the stuck
+// threshold.  An operation starts with its first failure and ends when it succeeds, or with its last failure if it
+// never did.
+func stuckOperationIntervals(startingIntervals monitorapi.Intervals) monitorapi.Intervals {
+	eventsByObject := map[objectOperation][]operationEvent{}
+	locators := map[objectOperation]monitorapi.Locator{}
+	for _, interval := range startingIntervals {
+		if interval.Source != monitorapi.SourceKubeEvent {
+			continue
+		}
+		for _, operation := range volumeOperations {
+			reason := string(interval.Message.Reason)
+			if !operation.startReasons.Has(reason) && !operation.endReasons.Has(reason) {
+				continue
+			}
+			locator := objectLocator(interval.Locator)
+			key := objectOperation{operation: operation.name, object: locator.OldLocator()}
+			first, last := eventTimes(interval)
+			eventsByObject[key] = append(eventsByObject[key], operationEvent{first: first, last: last, end: operation.endReasons.Has(reason)})
+			locators[key] = locator
+		}
+	}
+
+	ret := monitorapi.Intervals{}
+	for key, events := range eventsByObject {
+		sort.SliceStable(events, func(i, j int) bool { return events[i].first.Before(events[j].first) })
+		var from, to time.Time
+		for _, event := range events {
+			if event.end {
+				if !from.IsZero() {
+					ret = append(ret, stuckOperationInterval(key.operation, locators[key], from, event.first, true)...)
+					from, to = time.Time{}, time.Time{}
+				}
+				continue
+			}
+			if from.IsZero() {
+				from, to = event.first, event.last
+				continue
+			}
+			if event.last.After(to) {
+				to = event.last
+			}
+		}
+		if !from.IsZero() {
+			ret = append(ret, stuckOperationInterval(key.operation, locators[key], from, to, false)...)
+		}
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func stuckOperationInterval(operation string, locator monitorapi.Locator, from, to time.Time, succeeded bool) monitorapi.Intervals {
+	duration := to.Sub(from)
+	if duration < stuckThreshold {
+		return nil
+	}
+	humanMessage := fmt.Sprintf("%s succeeded after failing for %s", operation, duration.Round(time.Second))
+	if !succeeded {
+		humanMessage = fmt.Sprintf("%s was still failing after %s", operation, duration.Round(time.Second))
+	}
+	return monitorapi.Intervals{
+		monitorapi.NewInterval(monitorapi.SourceVolumeOperation, monitorapi.Warning).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.VolumeOperationStuckReason).
+				Constructed(monitorapi.ConstructionOwnerVolumeOps).
+				WithAnnotation(monitorapi.AnnotationOperation, operation).
+				WithAnnotation(monitorapi.AnnotationDuration, fmt.Sprintf("%.3fs", duration.Seconds())).
+				HumanMessage(humanMessage)).
+			Display().
+			Build(from, to),
+	}
+}
@@ -0,0 +1,106 @@
+package volumeoperationlatency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func podEvent(pod, hmsg string, reason monitorapi.IntervalReason, first, last time.Duration) monitorapi.Interval {
+	locator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeKind,
+		Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorNamespaceKey: "e2e-test",
+			"pod":                          pod,
+			monitorapi.LocatorHmsgKey:      hmsg,
+		},
+	}
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Warning).
+		Locator(locator).
+		Message(monitorapi.NewMessage().Reason(reason).
+			WithAnnotation("firstTimestamp", start.Add(first).Format(time.RFC3339)).
+			WithAnnotation("lastTimestamp", start.Add(last).Format(time.RFC3339))).
+		Build(start.Add(last), start.Add(last))
+}
+
+func TestStuckOperationIntervals(t *testing.T) {
+	intervals := monitorapi.Intervals{
+		// attach failed with two different messages for four minutes, then succeeded.
+		podEvent("db-0", "aaaaaaaaaa", "FailedAttachVolume", 0, time.Minute),
+		podEvent("db-0", "bbbbbbbbbb", "FailedAttachVolume", 2*time.Minute, 3*time.Minute),
+		podEvent("db-0", "cccccccccc", "SuccessfulAttachVolume", 4*time.Minute, 4*time.Minute),
+		// a brief attach failure is not stuck.
+		podEvent("web-0", "aaaaaaaaaa", "FailedAttachVolume", 0, 30*time.Second),
+		podEvent("web-0", "cccccccccc", "SuccessfulAttachVolume", time.Minute, time.Minute),
+		// mount never succeeded.
+		podEvent("web-1", "dddddddddd", "FailedMount", 10*time.Minute, 15*time.Minute),
+	}
+
+	stuck := stuckOperationIntervals(intervals)
+	if len(stuck) != 2 {
+		t.Fatalf("expected the db-0 attach and the web-1 mount to be stuck, got %v", stuck)
+	}
+
+	attach := stuck[0]
+	if attach.Message.Annotations[monitorapi.AnnotationOperation] != "attach" || attach.Locator.Keys["pod"] != "db-0" {
+		t.Fatalf("expected the attach of db-0 first, got %v", attach)
+	}
+	if !attach.From.Equal(start) || !attach.To.Equal(start.Add(4*time.Minute)) {
+		t.Errorf("expected the attach to last from its first failure to its success, got %v to %v", attach.From, attach.To)
+	}
+	if _, ok := attach.Locator.Keys[monitorapi.LocatorHmsgKey]; ok {
+		t.Errorf("expected the message hash to be dropped from the locator, got %v", attach.Locator.Keys)
+	}
+
+	mount := stuck[1]
+	if mount.Message.Annotations[monitorapi.AnnotationOperation] != "mount" || operationDuration(mount) != 5*time.Minute {
+		t.Errorf("expected the mount to be stuck for five minutes, got %v", mount)
+	}
+}
+
+func TestEvaluateDrainBudget(t *testing.T) {
+	drain := monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Info).
+		Locator(monitorapi.NewLocator().NodeFromName("worker-a")).
+		Message(monitorapi.NewMessage().Reason(monitorapi.NodeUpdateReason).WithAnnotation(monitorapi.AnnotationPhase, "Drain")).
+		Build(start.Add(9*time.Minute), start.Add(11*time.Minute))
+
+	tests := []struct {
+		name      string
+		platform  string
+		intervals monitorapi.Intervals
+		fails     bool
+	}{
+		{
+			name:      "slow mount after a drain",
+			platform:  "aws",
+			intervals: monitorapi.Intervals{podEvent("web-1", "dddddddddd", "FailedMount", 12*time.Minute, 16*time.Minute)},
+			fails:     true,
+		},
+		{
+			name:      "within the azure budget",
+			platform:  "azure",
+			intervals: monitorapi.Intervals{podEvent("web-1", "dddddddddd", "FailedMount", 12*time.Minute, 16*time.Minute)},
+		},
+		{
+			name:      "slow mount long after the drain",
+			platform:  "aws",
+			intervals: monitorapi.Intervals{podEvent("web-1", "dddddddddd", "FailedMount", 30*time.Minute, 40*time.Minute)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			finalIntervals := append(stuckOperationIntervals(test.intervals), drain)
+			junit := evaluateDrainBudget(test.platform, finalIntervals)
+			if test.fails != (junit.FailureOutput != nil) {
+				t.Fatalf("expected failure=%v, got %v", test.fails, junit.FailureOutput)
+			}
+			if test.fails && junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning, got %q", junit.Details.Severity)
+			}
+		})
+	}
+}
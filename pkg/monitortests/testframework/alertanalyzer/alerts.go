@@ -2,41 +2,28 @@ package alertanalyzer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
 	"strings"
 	"time"
 
-	routeclient "github.com/openshift/client-go/route/clientset/versioned"
-	"github.com/openshift/library-go/test/library/metrics"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
 	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	prometheustypes "github.com/prometheus/common/model"
 	"github.com/sirupsen/logrus"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
-func fetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Config, startTime time.Time) ([]monitorapi.Interval, error) {
-	kubeClient, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		return nil, err
-	}
-	routeClient, err := routeclient.NewForConfig(restConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = kubeClient.CoreV1().Namespaces().Get(ctx, "openshift-monitoring", metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
+// fetchEventIntervalsForAllAlerts converts the pending and firing ranges of every alert during the run into intervals.
+func fetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Config, startTime, endTime time.Time) ([]monitorapi.Interval, error) {
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, restConfig)
+	if errors.Is(err, prometheusaccess.ErrMonitoringNotInstalled) {
 		return []monitorapi.Interval{}, nil
 	}
-
-	prometheusClient, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +58,7 @@ func fetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Confi
 
 	timeRange := prometheusv1.Range{
 		Start: startTime,
-		End:   time.Now(),
+		End:   endTime,
 		Step:  2 * time.Second,
 	}
 	alerts, warningsForQuery, err := prometheusClient.QueryRange(ctx, `ALERTS{alertstate="firing"}`, timeRange)
@@ -18,7 +18,7 @@ import (
 )
 
 func writeAlertDataForJobRun(artifactDir string, _ monitorapi.ResourcesMap, events monitorapi.Intervals, timeSuffix string) error {
-	alertData := computeAlertData(events)
+	alertData := ComputeAlertData(events)
 	addMissingAlertsForLevel(alertData, WarningAlertLevel)
 	addMissingAlertsForLevel(alertData, CriticalAlertLevel)
 
@@ -126,7 +126,8 @@ func getAlertLevelFromEvent(event monitorapi.Interval) AlertLevel {
 	return UnknownAlertLevel
 }
 
-func computeAlertData(events monitorapi.Intervals) *AlertList {
+// ComputeAlertData sums how long every warning and critical alert fired, by name, namespace and level.
+func ComputeAlertData(events monitorapi.Intervals) *AlertList {
 	alertEvents := events.Filter(
 		func(eventInterval monitorapi.Interval) bool {
 			alertName := eventInterval.Locator.Keys[monitorapi.LocatorAlertKey]
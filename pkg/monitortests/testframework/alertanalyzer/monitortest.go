@@ -4,7 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -13,6 +16,8 @@ import (
 
 type alertSummarySerializer struct {
 	adminRESTConfig *rest.Config
+	jobType         platformidentification.JobType
+	policy          *criticalAlertPolicy
 }
 
 func NewAlertSummarySerializer() monitortestframework.MonitorTest {
@@ -21,11 +26,24 @@ func NewAlertSummarySerializer() monitortestframework.MonitorTest {
 
 func (w *alertSummarySerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	w.adminRESTConfig = adminRESTConfig
+
+	var err error
+	w.policy, err = parseCriticalAlertPolicy(defaultCriticalAlertPolicyYAML)
+	if err != nil {
+		return err
+	}
+	jobType, err := platformidentification.GetJobType(ctx, adminRESTConfig)
+	if err != nil {
+		// only allowances that match every cluster apply.
+		logrus.WithError(err).Warning("unable to determine the job type for the critical alert policy")
+		return nil
+	}
+	w.jobType = *jobType
 	return nil
 }
 
 func (w *alertSummarySerializer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
-	intervals, err := fetchEventIntervalsForAllAlerts(ctx, w.adminRESTConfig, beginning)
+	intervals, err := fetchEventIntervalsForAllAlerts(ctx, w.adminRESTConfig, beginning, end)
 	return intervals, nil, err
 }
 
@@ -33,8 +51,11 @@ func (*alertSummarySerializer) ConstructComputedIntervals(ctx context.Context, s
 	return nil, nil
 }
 
-func (*alertSummarySerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
+func (w *alertSummarySerializer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.policy == nil {
+		return nil, nil
+	}
+	return []*junitapi.JUnitTestCase{w.policy.evaluate(w.jobType, finalIntervals)}, nil
 }
 
 func (*alertSummarySerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
@@ -0,0 +1,164 @@
+package alertanalyzer
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/allowedalerts"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+//go:embed critical_alert_policy.yaml
+var defaultCriticalAlertPolicyYAML []byte
+
+const criticalAlertsTestName = "[sig-instrumentation] critical alerts should not fire beyond the platform and release policy"
+
+type criticalAlertPolicy struct {
+	Allowances []criticalAlertAllowance `json:"allowances"`
+}
+
+type criticalAlertAllowance struct {
+	AlertName string `json:"alertName"`
+	// Namespace of the alert, empty matches every namespace.
+	Namespace  string   `json:"namespace,omitempty"`
+	Platforms  []string `json:"platforms,omitempty"`
+	Topologies []string `json:"topologies,omitempty"`
+	// Releases are major.minor versions, for instance 4.16.
+	Releases []string `json:"releases,omitempty"`
+	// MaxDuration is how long the alert may fire in total during a run.
+	MaxDuration string `json:"maxDuration"`
+	Reason      string `json:"reason"`
+
+	maxDuration time.Duration
+}
+
+func parseCriticalAlertPolicy(content []byte) (*criticalAlertPolicy, error) {
+	ret := &criticalAlertPolicy{}
+	if err := yaml.UnmarshalStrict(content, ret); err != nil {
+		return nil, err
+	}
+	for i := range ret.Allowances {
+		allowance := &ret.Allowances[i]
+		if len(allowance.AlertName) == 0 {
+			return nil, fmt.Errorf("allowance %d must name an alert", i)
+		}
+		if len(allowance.Reason) == 0 {
+			return nil, fmt.Errorf("allowance for %v must explain why the alert is expected", allowance.AlertName)
+		}
+		maxDuration, err := time.ParseDuration(allowance.MaxDuration)
+		if err != nil {
+			return nil, fmt.Errorf("allowance for %v: %w", allowance.AlertName, err)
+		}
+		allowance.maxDuration = maxDuration
+	}
+	return ret, nil
+}
+
+func matchesOrEmpty(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, curr := range values {
+		if curr == value {
+			return true
+		}
+	}
+	return false
+}
+
+// allowanceFor returns the first allowance matching the alert and the cluster, or nil.
+func (p *criticalAlertPolicy) allowanceFor(jobType platformidentification.JobType, alertName, namespace string) *criticalAlertAllowance {
+	for i := range p.Allowances {
+		allowance := &p.Allowances[i]
+		switch {
+		case allowance.AlertName != alertName:
+		case len(allowance.Namespace) > 0 && allowance.Namespace != namespace:
+		case !matchesOrEmpty(allowance.Platforms, jobType.Platform):
+		case !matchesOrEmpty(allowance.Topologies, jobType.Topology):
+		case !matchesOrEmpty(allowance.Releases, jobType.Release):
+		default:
+			return allowance
+		}
+	}
+	return nil
+}
+
+var criticalAlertsTemplate = junitfailure.MustParseTemplate("critical-alerts",
+	`critical alerts fired beyond what the policy allows on platform={{.Fields.platform}} topology={{.Fields.topology}} release={{.Fields.release}}:
+{{range .Fields.violations}}{{.}}
+{{end}}
+{{.IntervalList}}`)
+
+// evaluate totals the firing time of each critical alert per namespace and compares it to the policy.  It is a
+// warning until the allowances are known to cover CI; the failure budget decides whether it fails the job.
+func (p *criticalAlertPolicy) evaluate(jobType platformidentification.JobType, finalIntervals monitorapi.Intervals) *junitapi.JUnitTestCase {
+	type alertKey struct {
+		name      string
+		namespace string
+	}
+	firingTime := map[alertKey]time.Duration{}
+	intervalsByAlert := map[alertKey]monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceAlert ||
+			!monitorapi.AlertFiring()(interval) ||
+			interval.Message.Annotations[monitorapi.AnnotationSeverity] != "critical" {
+			continue
+		}
+		key := alertKey{
+			name:      interval.Locator.Keys[monitorapi.LocatorAlertKey],
+			namespace: interval.Locator.Keys[monitorapi.LocatorNamespaceKey],
+		}
+		if isAlwaysAllowed(key.name) {
+			continue
+		}
+		firingTime[key] += interval.To.Sub(interval.From)
+		intervalsByAlert[key] = append(intervalsByAlert[key], interval)
+	}
+
+	violations := []string{}
+	violatingIntervals := monitorapi.Intervals{}
+	for key, duration := range firingTime {
+		allowance := p.allowanceFor(jobType, key.name, key.namespace)
+		switch {
+		case allowance == nil:
+			violations = append(violations, fmt.Sprintf("alert/%s ns/%s fired for %v and is not allowed", key.name, key.namespace, duration.Round(time.Second)))
+		case duration > allowance.maxDuration:
+			violations = append(violations, fmt.Sprintf("alert/%s ns/%s fired for %v, more than the %v allowed because %s",
+				key.name, key.namespace, duration.Round(time.Second), allowance.maxDuration, allowance.Reason))
+		default:
+			continue
+		}
+		violatingIntervals = append(violatingIntervals, intervalsByAlert[key]...)
+	}
+	if len(violations) == 0 {
+		return &junitapi.JUnitTestCase{Name: criticalAlertsTestName}
+	}
+	sort.Strings(violations)
+	sort.Sort(violatingIntervals)
+
+	junit := junitfailure.NewFailure(criticalAlertsTemplate, "CriticalAlertsFiring").
+		Field("platform", jobType.Platform).
+		Field("topology", jobType.Topology).
+		Field("release", jobType.Release).
+		Field("violations", violations).
+		Intervals(violatingIntervals...).
+		TestCase(criticalAlertsTestName)
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+func isAlwaysAllowed(alertName string) bool {
+	for _, name := range allowedalerts.AllowedAlertNames {
+		if name == alertName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package alertanalyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func TestDefaultCriticalAlertPolicyParses(t *testing.T) {
+	if _, err := parseCriticalAlertPolicy(defaultCriticalAlertPolicyYAML); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseCriticalAlertPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{
+			name: "missing reason",
+			content: `
+allowances:
+- alertName: KubeAPIDown
+  maxDuration: 1m
+`,
+			expectedErr: "must explain why",
+		},
+		{
+			name: "invalid duration",
+			content: `
+allowances:
+- alertName: KubeAPIDown
+  maxDuration: forever
+  reason: because
+`,
+			expectedErr: "invalid duration",
+		},
+		{
+			name: "unknown field",
+			content: `
+allowances:
+- alertName: KubeAPIDown
+  maxDuration: 1m
+  reason: because
+  severity: critical
+`,
+			expectedErr: "unknown field",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCriticalAlertPolicy([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func criticalAlert(name, namespace string, from time.Time, duration time.Duration) monitorapi.Interval {
+	locator := monitorapi.Locator{
+		Type: monitorapi.LocatorTypeAlert,
+		Keys: map[monitorapi.LocatorKey]string{
+			monitorapi.LocatorAlertKey:     name,
+			monitorapi.LocatorNamespaceKey: namespace,
+		},
+	}
+	return monitorapi.NewInterval(monitorapi.SourceAlert, monitorapi.Error).
+		Locator(locator).
+		Message(monitorapi.NewMessage().
+			WithAnnotation(monitorapi.AnnotationAlertState, "firing").
+			WithAnnotation(monitorapi.AnnotationSeverity, "critical").
+			HumanMessage(name)).
+		Build(from, from.Add(duration))
+}
+
+func TestCriticalAlertPolicyEvaluate(t *testing.T) {
+	policy, err := parseCriticalAlertPolicy([]byte(`
+allowances:
+- alertName: KubeAPIDown
+  topologies: [single]
+  releases: ["4.16"]
+  maxDuration: 10m
+  reason: the only kube-apiserver restarts
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	single := platformidentification.JobType{Platform: "aws", Topology: "single", Release: "4.16"}
+	ha := platformidentification.JobType{Platform: "aws", Topology: "ha", Release: "4.16"}
+
+	tests := []struct {
+		name      string
+		jobType   platformidentification.JobType
+		intervals monitorapi.Intervals
+		expectErr string
+	}{
+		{
+			name:    "allowed on the matching topology",
+			jobType: single,
+			intervals: monitorapi.Intervals{
+				criticalAlert("KubeAPIDown", "openshift-kube-apiserver", start, 4*time.Minute),
+				criticalAlert("KubeAPIDown", "openshift-kube-apiserver", start.Add(time.Hour), 4*time.Minute),
+			},
+		},
+		{
+			name:    "firing time is totalled",
+			jobType: single,
+			intervals: monitorapi.Intervals{
+				criticalAlert("KubeAPIDown", "openshift-kube-apiserver", start, 6*time.Minute),
+				criticalAlert("KubeAPIDown", "openshift-kube-apiserver", start.Add(time.Hour), 6*time.Minute),
+			},
+			expectErr: "fired for 12m0s, more than the 10m0s allowed",
+		},
+		{
+			name:    "not allowed on other topologies",
+			jobType: ha,
+			intervals: monitorapi.Intervals{
+				criticalAlert("KubeAPIDown", "openshift-kube-apiserver", start, time.Minute),
+			},
+			expectErr: "is not allowed",
+		},
+		{
+			name:    "always allowed alerts are ignored",
+			jobType: ha,
+			intervals: monitorapi.Intervals{
+				criticalAlert("Watchdog", "openshift-monitoring", start, time.Hour),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junit := policy.evaluate(tt.jobType, tt.intervals)
+			switch {
+			case len(tt.expectErr) == 0 && junit.FailureOutput != nil:
+				t.Fatalf("unexpected failure: %v", junit.FailureOutput.Output)
+			case len(tt.expectErr) > 0 && junit.FailureOutput == nil:
+				t.Fatalf("expected a failure containing %q", tt.expectErr)
+			case len(tt.expectErr) > 0 && !strings.Contains(junit.FailureOutput.Output, tt.expectErr):
+				t.Fatalf("expected a failure containing %q, got %v", tt.expectErr, junit.FailureOutput.Output)
+			case len(tt.expectErr) > 0 && junit.Details.Severity != junitapi.SeverityWarn:
+				t.Fatalf("expected a warning, got %v", junit.Details.Severity)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package bigqueryexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// SchemaVersion is the version of the rows this code writes.  Bump it whenever a column changes meaning or is
+// removed.  Adding a column does not need a new version.
+const SchemaVersion = 1
+
+const (
+	intervalsFilePrefix   = "bigquery-intervals"
+	junitFilePrefix       = "bigquery-junit"
+	clusterDataFilePrefix = "bigquery-cluster-data"
+)
+
+// Partition is on every row, so every table can be partitioned by time and clustered by job without joins.  The
+// pipeline rejects rows without a job name, a job run name, or a partition time.
+type Partition struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	JobName       string `json:"jobName"`
+	JobRunName    string `json:"jobRunName"`
+	// PartitionTime is when the run began, the earliest interval.  It is the same on every row of a run.
+	PartitionTime time.Time `json:"partitionTime"`
+}
+
+// IntervalRow is one interval.  Locator keys and annotations are repeated key value records, which BigQuery can
+// query where it cannot query maps.
+type IntervalRow struct {
+	Partition
+	Source      string     `json:"source"`
+	Display     bool       `json:"display"`
+	Level       string     `json:"level"`
+	LocatorType string     `json:"locatorType"`
+	Locator     string     `json:"locator"`
+	LocatorKeys []KeyValue `json:"locatorKeys"`
+	Reason      string     `json:"reason"`
+	Message     string     `json:"message"`
+	Annotations []KeyValue `json:"annotations"`
+	From        time.Time  `json:"from"`
+	// To is nil for intervals that had not ended when the run ended.
+	To *time.Time `json:"to"`
+}
+
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// JUnitRow is one junit test case.  A test that flaked has a failed and a passed row.
+type JUnitRow struct {
+	Partition
+	TestName        string  `json:"testName"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	FailureOutput   string  `json:"failureOutput"`
+	MonitorTest     string  `json:"monitorTest"`
+	JiraComponent   string  `json:"jiraComponent"`
+	Severity        string  `json:"severity"`
+}
+
+const (
+	junitPassed  = "Passed"
+	junitFailed  = "Failed"
+	junitSkipped = "Skipped"
+)
+
+// ClusterDataRow is the one row describing the cluster of the run.  The job type is flattened for filtering, and
+// the whole ClusterData is kept as a JSON string, because its maps have no BigQuery equivalent.
+type ClusterDataRow struct {
+	Partition
+	Release      string `json:"release"`
+	FromRelease  string `json:"fromRelease"`
+	Platform     string `json:"platform"`
+	Architecture string `json:"architecture"`
+	Network      string `json:"network"`
+	Topology     string `json:"topology"`
+	ClusterData  string `json:"clusterData"`
+}
+
+// ClusterDataSource returns the cluster data of the run.
+type ClusterDataSource interface {
+	ClusterData() platformidentification.ClusterData
+}
+
+type bigQueryExport struct {
+	clusterData ClusterDataSource
+	junits      []*junitapi.JUnitTestCase
+	getenv      func(string) string
+}
+
+// NewBigQueryExport returns a registry output that writes the intervals, the junits of the monitor tests, and the
+// cluster data as newline delimited JSON the TRT data pipeline loads into BigQuery.  The job name and job run name
+// are read from the JOB_NAME and BUILD_ID variables prow sets, and writing fails without them.
+func NewBigQueryExport(clusterData ClusterDataSource) monitortestframework.RegistryOutput {
+	return &bigQueryExport{
+		clusterData: clusterData,
+		getenv:      os.Getenv,
+	}
+}
+
+func (o *bigQueryExport) SetTestOutcomes(junits []*junitapi.JUnitTestCase) {
+	o.junits = junits
+}
+
+func (o *bigQueryExport) WriteRegistryOutput(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	jobName, jobRunName := o.getenv("JOB_NAME"), o.getenv("BUILD_ID")
+	if len(jobName) == 0 || len(jobRunName) == 0 {
+		return fmt.Errorf("JOB_NAME and BUILD_ID must be set to export to BigQuery, got %q and %q", jobName, jobRunName)
+	}
+	partition := Partition{
+		SchemaVersion: SchemaVersion,
+		JobName:       jobName,
+		JobRunName:    jobRunName,
+		PartitionTime: partitionTime(finalIntervals),
+	}
+
+	clusterDataRow, err := ClusterDataRowFor(partition, o.clusterData.ClusterData())
+	if err != nil {
+		return err
+	}
+	files := map[string][]interface{}{
+		intervalsFilePrefix:   {},
+		junitFilePrefix:       {},
+		clusterDataFilePrefix: {clusterDataRow},
+	}
+	for _, row := range IntervalRowsFor(partition, finalIntervals) {
+		files[intervalsFilePrefix] = append(files[intervalsFilePrefix], row)
+	}
+	for _, row := range JUnitRowsFor(partition, o.junits) {
+		files[junitFilePrefix] = append(files[junitFilePrefix], row)
+	}
+
+	for prefix, rows := range files {
+		if err := writeNDJSON(storageDir, fmt.Sprintf("%s%s.json", prefix, timeSuffix), rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *bigQueryExport) ArtifactSchema(relativePath string) string {
+	for _, prefix := range []string{intervalsFilePrefix, junitFilePrefix, clusterDataFilePrefix} {
+		if strings.HasPrefix(path.Base(relativePath), prefix) {
+			return fmt.Sprintf("%s/v%d", prefix, SchemaVersion)
+		}
+	}
+	return ""
+}
+
+// partitionTime is the earliest time an interval began, or now for a run without intervals.
+func partitionTime(intervals monitorapi.Intervals) time.Time {
+	var earliest time.Time
+	for _, interval := range intervals {
+		if interval.From.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || interval.From.Before(earliest) {
+			earliest = interval.From
+		}
+	}
+	if earliest.IsZero() {
+		return time.Now().UTC()
+	}
+	return earliest.UTC()
+}
+
+// IntervalRowsFor returns a row for every interval.
+func IntervalRowsFor(partition Partition, intervals monitorapi.Intervals) []IntervalRow {
+	rows := []IntervalRow{}
+	for _, interval := range intervals {
+		row := IntervalRow{
+			Partition:   partition,
+			Source:      string(interval.Source),
+			Display:     interval.Display,
+			Level:       interval.Level.String(),
+			LocatorType: string(interval.Locator.Type),
+			Locator:     interval.Locator.OldLocator(),
+			LocatorKeys: []KeyValue{},
+			Reason:      string(interval.Message.Reason),
+			Message:     interval.Message.HumanMessage,
+			Annotations: []KeyValue{},
+			From:        interval.From.UTC(),
+		}
+		for key, value := range interval.Locator.Keys {
+			row.LocatorKeys = append(row.LocatorKeys, KeyValue{Key: string(key), Value: value})
+		}
+		for key, value := range interval.Message.Annotations {
+			row.Annotations = append(row.Annotations, KeyValue{Key: string(key), Value: value})
+		}
+		sortKeyValues(row.LocatorKeys)
+		sortKeyValues(row.Annotations)
+		if !interval.To.IsZero() {
+			to := interval.To.UTC()
+			row.To = &to
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// JUnitRowsFor returns a row for every junit.
+func JUnitRowsFor(partition Partition, junits []*junitapi.JUnitTestCase) []JUnitRow {
+	rows := []JUnitRow{}
+	for _, junit := range junits {
+		row := JUnitRow{
+			Partition:       partition,
+			TestName:        junit.Name,
+			Status:          junitPassed,
+			DurationSeconds: junit.Duration,
+		}
+		switch {
+		case junit.FailureOutput != nil:
+			row.Status = junitFailed
+			row.FailureOutput = junit.FailureOutput.Output
+		case junit.SkipMessage != nil:
+			row.Status = junitSkipped
+		}
+		if junit.Details != nil {
+			row.MonitorTest = junit.Details.MonitorTest
+			row.JiraComponent = junit.Details.JiraComponent
+			row.Severity = string(junit.Details.Severity)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// ClusterDataRowFor returns the row describing the cluster.
+func ClusterDataRowFor(partition Partition, clusterData platformidentification.ClusterData) (ClusterDataRow, error) {
+	content, err := json.Marshal(clusterData)
+	if err != nil {
+		return ClusterDataRow{}, err
+	}
+	return ClusterDataRow{
+		Partition:    partition,
+		Release:      clusterData.JobType.Release,
+		FromRelease:  clusterData.JobType.FromRelease,
+		Platform:     clusterData.JobType.Platform,
+		Architecture: clusterData.JobType.Architecture,
+		Network:      clusterData.JobType.Network,
+		Topology:     clusterData.JobType.Topology,
+		ClusterData:  string(content),
+	}, nil
+}
+
+func sortKeyValues(keyValues []KeyValue) {
+	sort.Slice(keyValues, func(i, j int) bool {
+		return keyValues[i].Key < keyValues[j].Key
+	})
+}
+
+// writeNDJSON writes one row per line, which is the only JSON format BigQuery loads.
+func writeNDJSON(storageDir, name string, rows []interface{}) error {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	for _, row := range rows {
+		if err := encoder.Encode(row); err != nil {
+			return err
+		}
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0644)
+}
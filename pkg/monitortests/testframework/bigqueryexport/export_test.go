@@ -0,0 +1,152 @@
+package bigqueryexport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type fixedClusterData platformidentification.ClusterData
+
+func (d fixedClusterData) ClusterData() platformidentification.ClusterData {
+	return platformidentification.ClusterData(d)
+}
+
+func readRows[T any](t *testing.T, filename string) []T {
+	t.Helper()
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows := []T{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var row T
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
+
+func TestWriteRegistryOutput(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Minute)
+	clusterData := platformidentification.ClusterData{
+		SchemaVersion: platformidentification.ClusterDataSchemaVersion,
+		JobType:       platformidentification.JobType{Release: "4.16", Platform: "aws", Topology: "ha"},
+	}
+	env := map[string]string{"JOB_NAME": "periodic-ci-e2e-aws", "BUILD_ID": "1234"}
+	output := &bigQueryExport{clusterData: fixedClusterData(clusterData), getenv: func(key string) string { return env[key] }}
+	output.SetTestOutcomes([]*junitapi.JUnitTestCase{
+		{Name: "passes", Duration: 2, Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "a", JiraComponent: "A"}},
+		{Name: "fails", FailureOutput: &junitapi.FailureOutput{Output: "failed"}, Details: &junitapi.JUnitTestCaseDetails{Severity: junitapi.SeverityFail}},
+		{Name: "skipped", SkipMessage: &junitapi.SkipMessage{Message: "not applicable"}},
+	})
+	intervals := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{
+				Level: monitorapi.Warning,
+				Locator: monitorapi.Locator{
+					Type: monitorapi.LocatorTypePod,
+					Keys: map[monitorapi.LocatorKey]string{monitorapi.LocatorPodKey: "etcd-0", monitorapi.LocatorNamespaceKey: "openshift-etcd"},
+				},
+				Message: monitorapi.Message{
+					Reason:       "Killing",
+					HumanMessage: "stopping container",
+					Annotations:  map[monitorapi.AnnotationKey]string{monitorapi.AnnotationReason: "Killing"},
+				},
+			},
+			Source:  monitorapi.SourcePodState,
+			Display: true,
+			From:    start.Add(time.Second),
+			To:      end,
+		},
+		{
+			Condition: monitorapi.Condition{Level: monitorapi.Info},
+			Source:    monitorapi.SourceE2ETest,
+			From:      start,
+		},
+	}
+
+	storageDir := t.TempDir()
+	if err := output.WriteRegistryOutput(context.Background(), storageDir, "_suffix", intervals, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	partition := Partition{SchemaVersion: SchemaVersion, JobName: "periodic-ci-e2e-aws", JobRunName: "1234", PartitionTime: start}
+	intervalRows := readRows[IntervalRow](t, filepath.Join(storageDir, "bigquery-intervals_suffix.json"))
+	expectedIntervalRows := []IntervalRow{
+		{
+			Partition:   partition,
+			Source:      string(monitorapi.SourcePodState),
+			Display:     true,
+			Level:       "Warning",
+			LocatorType: string(monitorapi.LocatorTypePod),
+			Locator:     intervals[0].Locator.OldLocator(),
+			LocatorKeys: []KeyValue{{Key: "namespace", Value: "openshift-etcd"}, {Key: "pod", Value: "etcd-0"}},
+			Reason:      "Killing",
+			Message:     "stopping container",
+			Annotations: []KeyValue{{Key: "reason", Value: "Killing"}},
+			From:        start.Add(time.Second),
+			To:          &end,
+		},
+		{
+			Partition:   partition,
+			Source:      string(monitorapi.SourceE2ETest),
+			Level:       "Info",
+			LocatorKeys: []KeyValue{},
+			Annotations: []KeyValue{},
+			From:        start,
+		},
+	}
+	if !reflect.DeepEqual(expectedIntervalRows, intervalRows) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expectedIntervalRows, intervalRows)
+	}
+
+	junitRows := readRows[JUnitRow](t, filepath.Join(storageDir, "bigquery-junit_suffix.json"))
+	expectedJUnitRows := []JUnitRow{
+		{Partition: partition, TestName: "passes", Status: "Passed", DurationSeconds: 2, MonitorTest: "a", JiraComponent: "A"},
+		{Partition: partition, TestName: "fails", Status: "Failed", FailureOutput: "failed", Severity: string(junitapi.SeverityFail)},
+		{Partition: partition, TestName: "skipped", Status: "Skipped"},
+	}
+	if !reflect.DeepEqual(expectedJUnitRows, junitRows) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expectedJUnitRows, junitRows)
+	}
+
+	clusterDataRows := readRows[ClusterDataRow](t, filepath.Join(storageDir, "bigquery-cluster-data_suffix.json"))
+	if len(clusterDataRows) != 1 {
+		t.Fatalf("expected one cluster data row, got %d", len(clusterDataRows))
+	}
+	if row := clusterDataRows[0]; row.Partition != partition || row.Release != "4.16" || row.Platform != "aws" || row.Topology != "ha" {
+		t.Errorf("unexpected cluster data row %#v", row)
+	}
+	actualClusterData := platformidentification.ClusterData{}
+	if err := json.Unmarshal([]byte(clusterDataRows[0].ClusterData), &actualClusterData); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(clusterData, actualClusterData) {
+		t.Errorf("expected\n%#v\ngot\n%#v", clusterData, actualClusterData)
+	}
+}
+
+func TestWriteRegistryOutputRequiresJob(t *testing.T) {
+	output := &bigQueryExport{clusterData: fixedClusterData{}, getenv: func(string) string { return "" }}
+	if err := output.WriteRegistryOutput(context.Background(), t.TempDir(), "", nil, nil); err == nil {
+		t.Fatal("expected an error without JOB_NAME and BUILD_ID")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"path/filepath"
 	"time"
 
 	"github.com/openshift/origin/pkg/clioptions/clusterinfo"
@@ -21,14 +20,27 @@ import (
 
 type clusterInfoSerializer struct {
 	adminRESTConfig *rest.Config
+	// startClusterData is the cluster data before the run, so upgrades can show which fields changed.
+	startClusterData platformidentification.ClusterData
+	// endClusterData is the cluster data written to storage.
+	endClusterData platformidentification.ClusterData
 }
 
-func NewClusterInfoSerializer() monitortestframework.MonitorTest {
+// ClusterDataSerializer is a monitor test that writes the cluster data, and hands it to registry outputs that report
+// on the run.
+type ClusterDataSerializer interface {
+	monitortestframework.MonitorTest
+	// ClusterData returns the cluster data written to storage, or empty cluster data before it is written.
+	ClusterData() platformidentification.ClusterData
+}
+
+func NewClusterInfoSerializer() ClusterDataSerializer {
 	return &clusterInfoSerializer{}
 }
 
 func (w *clusterInfoSerializer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	w.adminRESTConfig = adminRESTConfig
+	w.startClusterData = w.collectClusterData("N")
 	return nil
 }
 
@@ -45,10 +57,30 @@ func (*clusterInfoSerializer) EvaluateTestsFromConstructedIntervals(ctx context.
 }
 
 func (w *clusterInfoSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return writeClusterData(
-		filepath.Join(storageDir, fmt.Sprintf("cluster-data%s.json", timeSuffix)),
-		w.collectClusterData(clusterinfo.WasMasterNodeUpdated(finalIntervals)),
-	)
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("cluster-data%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	updateTopology := platformidentification.AnalyzeUpdateTopology(finalIntervals)
+	endClusterData := w.collectClusterData(updateTopology.MasterNodesUpdated())
+	endClusterData.UpdateTopology = updateTopology
+	if err := writeClusterData(filename, endClusterData); err != nil {
+		return err
+	}
+	w.endClusterData = endClusterData
+
+	snapshotsFilename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("cluster-data-snapshots%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return writeJSON(snapshotsFilename, newClusterDataSnapshots(
+		ClusterDataSnapshot{Phase: PhaseStart, ClusterData: w.startClusterData},
+		ClusterDataSnapshot{Phase: PhaseEnd, ClusterData: endClusterData},
+	))
+}
+
+func (w *clusterInfoSerializer) ClusterData() platformidentification.ClusterData {
+	return w.endClusterData
 }
 
 func (*clusterInfoSerializer) Cleanup(ctx context.Context) error {
@@ -56,8 +88,16 @@ func (*clusterInfoSerializer) Cleanup(ctx context.Context) error {
 	return nil
 }
 
+// writeClusterData refuses to write cluster data its consumers would misread.
 func writeClusterData(filename string, clusterData platformidentification.ClusterData) error {
-	jsonContent, err := json.MarshalIndent(clusterData, "", "    ")
+	if err := clusterData.Validate(); err != nil {
+		return fmt.Errorf("invalid cluster data: %w", err)
+	}
+	return writeJSON(filename, clusterData)
+}
+
+func writeJSON(filename string, obj interface{}) error {
+	jsonContent, err := json.MarshalIndent(obj, "", "    ")
 	if err != nil {
 		return err
 	}
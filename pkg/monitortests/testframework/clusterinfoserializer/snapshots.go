@@ -0,0 +1,64 @@
+package clusterinfoserializer
+
+import (
+	"reflect"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+const (
+	// PhaseStart is the cluster data collected when the monitor started, before an upgrade began.
+	PhaseStart = "Start"
+	// PhaseEnd is the cluster data collected when the monitor wrote its content, after an upgrade finished.
+	PhaseEnd = "End"
+)
+
+// ClusterDataSnapshot is the cluster data as it was at one phase of the run.
+type ClusterDataSnapshot struct {
+	Phase       string                             `json:"phase"`
+	ClusterData platformidentification.ClusterData `json:"clusterData"`
+}
+
+// ClusterDataChange is a field of the cluster data that changed during the run.
+type ClusterDataChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ClusterDataSnapshots are the snapshots of the run, in order, and what changed between the first and the last.
+type ClusterDataSnapshots struct {
+	Snapshots []ClusterDataSnapshot `json:"snapshots"`
+	Changes   []ClusterDataChange   `json:"changes"`
+}
+
+func newClusterDataSnapshots(snapshots ...ClusterDataSnapshot) ClusterDataSnapshots {
+	ret := ClusterDataSnapshots{Snapshots: snapshots, Changes: []ClusterDataChange{}}
+	if len(snapshots) > 1 {
+		ret.Changes = diffClusterData(snapshots[0].ClusterData, snapshots[len(snapshots)-1].ClusterData)
+	}
+	return ret
+}
+
+// diffClusterData returns the fields that differ, in the order they are declared.  The fields of the embedded JobType
+// are compared one by one, since they are what identifies the job.
+func diffClusterData(before, after platformidentification.ClusterData) []ClusterDataChange {
+	changes := []ClusterDataChange{}
+	var diffFields func(before, after reflect.Value)
+	diffFields = func(before, after reflect.Value) {
+		for i := 0; i < before.NumField(); i++ {
+			field := before.Type().Field(i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				diffFields(before.Field(i), after.Field(i))
+				continue
+			}
+			beforeValue, afterValue := before.Field(i).Interface(), after.Field(i).Interface()
+			if reflect.DeepEqual(beforeValue, afterValue) {
+				continue
+			}
+			changes = append(changes, ClusterDataChange{Field: field.Name, Before: beforeValue, After: afterValue})
+		}
+	}
+	diffFields(reflect.ValueOf(before), reflect.ValueOf(after))
+	return changes
+}
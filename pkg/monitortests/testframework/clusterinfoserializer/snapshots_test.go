@@ -0,0 +1,38 @@
+package clusterinfoserializer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+)
+
+func TestClusterDataSnapshots(t *testing.T) {
+	before := platformidentification.ClusterData{
+		JobType:            platformidentification.JobType{Release: "4.15", Platform: "aws"},
+		Capabilities:       []string{"Console"},
+		MasterNodesUpdated: "N",
+	}
+	after := platformidentification.ClusterData{
+		JobType:            platformidentification.JobType{Release: "4.16", FromRelease: "4.15", Platform: "aws"},
+		Capabilities:       []string{"Console"},
+		MasterNodesUpdated: "Y",
+	}
+
+	snapshots := newClusterDataSnapshots(
+		ClusterDataSnapshot{Phase: PhaseStart, ClusterData: before},
+		ClusterDataSnapshot{Phase: PhaseEnd, ClusterData: after},
+	)
+	expected := []ClusterDataChange{
+		{Field: "Release", Before: "4.15", After: "4.16"},
+		{Field: "FromRelease", Before: "", After: "4.15"},
+		{Field: "MasterNodesUpdated", Before: "N", After: "Y"},
+	}
+	if !reflect.DeepEqual(expected, snapshots.Changes) {
+		t.Errorf("expected %v, got %v", expected, snapshots.Changes)
+	}
+
+	if unchanged := newClusterDataSnapshots(ClusterDataSnapshot{Phase: PhaseEnd, ClusterData: after}); len(unchanged.Changes) != 0 {
+		t.Errorf("expected no changes from a single snapshot, got %v", unchanged.Changes)
+	}
+}
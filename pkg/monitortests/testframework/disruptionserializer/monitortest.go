@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -40,8 +39,12 @@ func (*disruptionSummarySerializer) EvaluateTestsFromConstructedIntervals(ctx co
 }
 
 func (*disruptionSummarySerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	backendDisruption := computeDisruptionData(finalIntervals)
-	return writeDisruptionData(filepath.Join(storageDir, fmt.Sprintf("backend-disruption%s.json", timeSuffix)), backendDisruption)
+	backendDisruption := ComputeDisruptionData(finalIntervals)
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("backend-disruption%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return writeDisruptionData(filename, backendDisruption)
 }
 
 func (*disruptionSummarySerializer) Cleanup(ctx context.Context) error {
@@ -83,7 +86,8 @@ func writeDisruptionData(filename string, disruption *BackendDisruptionList) err
 	return ioutil.WriteFile(filename, jsonContent, 0644)
 }
 
-func computeDisruptionData(eventIntervals monitorapi.Intervals) *BackendDisruptionList {
+// ComputeDisruptionData sums the disruption of every backend.
+func ComputeDisruptionData(eventIntervals monitorapi.Intervals) *BackendDisruptionList {
 	ret := &BackendDisruptionList{
 		BackendDisruptions: map[string]*BackendDisruption{},
 	}
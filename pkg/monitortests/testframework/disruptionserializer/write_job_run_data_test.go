@@ -439,7 +439,7 @@ func TestComputeDisruptionData(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			disruptions := computeDisruptionData(tt.intervals)
+			disruptions := ComputeDisruptionData(tt.intervals)
 			for backend, expectedDisruption := range tt.expected {
 				if !assert.Contains(t, disruptions.BackendDisruptions, backend) {
 					continue
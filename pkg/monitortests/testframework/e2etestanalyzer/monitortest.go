@@ -18,6 +18,12 @@ func NewAnalyzer() monitortestframework.MonitorTest {
 	return &e2eTestAnalyzer{}
 }
 
+var _ monitortestframework.OfflineMonitorTest = &e2eTestAnalyzer{}
+
+func (*e2eTestAnalyzer) SupportsOffline() bool {
+	return true
+}
+
 func (w *e2eTestAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
 	return nil
 }
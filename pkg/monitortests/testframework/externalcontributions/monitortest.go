@@ -0,0 +1,113 @@
+package externalcontributions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/contribution"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const wellFormedTestName = `[Jira:"Test Framework"] external contributions should be well formed`
+
+type externalContributions struct {
+	notSupportedReason error
+
+	dir string
+}
+
+// NewExternalContributions adds the intervals and junits test binaries outside of openshift-tests wrote to
+// info.ContributionDir to the results, so their tests are part of the shared timeline.  See the contribution package
+// for the protocol.
+func NewExternalContributions(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &externalContributions{
+		dir: info.ContributionDir,
+	}
+}
+
+func (w *externalContributions) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+		},
+		TestNames: []string{wellFormedTestName},
+	}
+}
+
+func (w *externalContributions) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	if len(w.dir) == 0 {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "no contribution directory is configured"}
+		return w.notSupportedReason
+	}
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return err
+	}
+	// test processes started from here on inherit the directory.
+	return os.Setenv(contribution.DirEnvVar, w.dir)
+}
+
+func (w *externalContributions) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	contributions, err := contribution.ReadDir(w.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contributions.Intervals, append(contributions.JUnits, reportProblems(contributions.Problems)...), nil
+}
+
+// reportProblems flakes on records that could not be read, the rest of the contribution is still used.
+func reportProblems(problems map[string][]string) []*junitapi.JUnitTestCase {
+	passed := &junitapi.JUnitTestCase{Name: wellFormedTestName}
+	if len(problems) == 0 {
+		return []*junitapi.JUnitTestCase{passed}
+	}
+
+	contributors := []string{}
+	for contributor := range problems {
+		contributors = append(contributors, contributor)
+	}
+	sort.Strings(contributors)
+	out := &strings.Builder{}
+	for _, contributor := range contributors {
+		fmt.Fprintf(out, "%s:\n", contributor)
+		for _, problem := range problems[contributor] {
+			fmt.Fprintf(out, "  %s\n", problem)
+		}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: wellFormedTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("records of %d contributors could not be read and were skipped:\n%s", len(contributors), out.String()),
+			},
+		},
+		passed,
+	}
+}
+
+func (w *externalContributions) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *externalContributions) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *externalContributions) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *externalContributions) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
@@ -0,0 +1,68 @@
+package injectedfaults
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	excusedTestName = `[Jira:"Test Framework"] symptoms of injected faults should be excused`
+	// maxListedIntervals bounds how many excused intervals of one fault are listed in the junit.
+	maxListedIntervals = 20
+)
+
+// faultsFrom returns the faults recorded in the intervals, earliest first.  A fault declared twice, for instance both
+// in a file and through the API, is kept once.
+func faultsFrom(intervals monitorapi.Intervals) []monitortestframework.InjectedFault {
+	ret := []monitortestframework.InjectedFault{}
+	seen := map[string]bool{}
+	for _, interval := range intervals {
+		fault, ok := monitortestframework.InjectedFaultFromInterval(interval)
+		if !ok || seen[fault.Name] {
+			continue
+		}
+		seen[fault.Name] = true
+		ret = append(ret, fault)
+	}
+	monitortestframework.SortInjectedFaults(ret)
+	return ret
+}
+
+// excusedIntervals returns the intervals each fault excuses by name.  An interval is excused by the earliest fault
+// that correlates with it, the same one that annotates it.
+func excusedIntervals(faults []monitortestframework.InjectedFault, intervals monitorapi.Intervals) map[string]monitorapi.Intervals {
+	ret := map[string]monitorapi.Intervals{}
+	for _, interval := range intervals {
+		for _, fault := range faults {
+			if fault.Excuses(interval) {
+				ret[fault.Name] = append(ret[fault.Name], interval)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// reportExcused lists what every fault excused, so a reviewer can tell whether a fault hid more than it caused.
+func reportExcused(faults []monitortestframework.InjectedFault, excused map[string]monitorapi.Intervals) *junitapi.JUnitTestCase {
+	out := &strings.Builder{}
+	for _, fault := range faults {
+		intervals := excused[fault.Name]
+		fmt.Fprintf(out, "%s excused %d intervals\n", fault.Name, len(intervals))
+		for i, interval := range intervals {
+			if i == maxListedIntervals {
+				fmt.Fprintf(out, "  ... and %d more\n", len(intervals)-maxListedIntervals)
+				break
+			}
+			fmt.Fprintf(out, "  %s\n", interval.String())
+		}
+	}
+	return &junitapi.JUnitTestCase{
+		Name:      excusedTestName,
+		SystemOut: out.String(),
+	}
+}
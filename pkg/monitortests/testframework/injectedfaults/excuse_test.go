@@ -0,0 +1,42 @@
+package injectedfaults
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+func TestExcusedIntervals(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	early := monitortestframework.InjectedFault{Name: "kill-worker-0", From: from, Nodes: []string{"worker-0"}}
+	late := monitortestframework.InjectedFault{Name: "kill-cluster", From: from.Add(time.Minute)}
+	symptom := monitorapi.NewInterval(monitorapi.SourceNodeState, monitorapi.Error).
+		Locator(monitorapi.NewLocator().NodeFromName("worker-0")).
+		Message(monitorapi.NewMessage().HumanMessage("not ready")).
+		Build(from.Add(2*time.Minute), from.Add(3*time.Minute))
+
+	// the late fault is declared twice and listed first.
+	intervals := monitorapi.Intervals{late.Interval(), early.Interval(), late.Interval(), symptom}
+	faults := faultsFrom(intervals)
+	if len(faults) != 2 || faults[0].Name != early.Name || faults[1].Name != late.Name {
+		t.Fatalf("expected each fault once, earliest first, got %v", faults)
+	}
+
+	excused := excusedIntervals(faults, intervals)
+	if len(excused[early.Name]) != 1 || len(excused[late.Name]) != 0 {
+		t.Errorf("expected the symptom to be excused by the earliest fault only, got %v", excused)
+	}
+
+	w := &injectedFaults{faults: faults}
+	if excusedBy := w.AnnotateInterval(symptom)[monitorapi.AnnotationExcusedBy]; excusedBy != early.Name {
+		t.Errorf("expected the symptom to be annotated with %q, got %q", early.Name, excusedBy)
+	}
+
+	report := reportExcused(faults, excused)
+	if report.FailureOutput != nil || !strings.Contains(report.SystemOut, "kill-worker-0 excused 1 intervals") {
+		t.Errorf("unexpected report: %v", report.SystemOut)
+	}
+}
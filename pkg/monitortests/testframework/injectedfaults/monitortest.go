@@ -0,0 +1,98 @@
+package injectedfaults
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type injectedFaults struct {
+	faultDir string
+
+	faults  []monitortestframework.InjectedFault
+	excused map[string]monitorapi.Intervals
+}
+
+// NewInjectedFaults reads the faults chaos tooling declared, in info.InjectedFaultDir or through the live monitor API,
+// and excuses the warnings and errors that correlate with them, so invariants are not failed by faults injected on
+// purpose.
+func NewInjectedFaults(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	return &injectedFaults{
+		faultDir: info.InjectedFaultDir,
+	}
+}
+
+var _ monitortestframework.IntervalAnnotator = &injectedFaults{}
+
+func (w *injectedFaults) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{excusedTestName},
+	}
+}
+
+var _ monitortestframework.OfflineMonitorTest = &injectedFaults{}
+
+func (*injectedFaults) SupportsOffline() bool {
+	return true
+}
+
+func (w *injectedFaults) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+// CollectData records the faults dropped in the directory.  Faults declared through the live API were recorded when
+// they were declared.
+func (w *injectedFaults) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if len(w.faultDir) == 0 {
+		return nil, nil, nil
+	}
+	faults, err := monitortestframework.LoadInjectedFaultDir(w.faultDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret := monitorapi.Intervals{}
+	for _, fault := range faults {
+		ret = append(ret, fault.Interval())
+	}
+	return ret, nil, nil
+}
+
+func (w *injectedFaults) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	w.faults = faultsFrom(startingIntervals)
+	w.excused = excusedIntervals(w.faults, startingIntervals)
+	return nil, nil
+}
+
+func (w *injectedFaults) AnnotateInterval(interval monitorapi.Interval) map[monitorapi.AnnotationKey]string {
+	for _, fault := range w.faults {
+		if fault.Excuses(interval) {
+			return map[monitorapi.AnnotationKey]string{monitorapi.AnnotationExcusedBy: fault.Name}
+		}
+	}
+	return nil
+}
+
+func (w *injectedFaults) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if len(w.faults) == 0 {
+		return nil, nil
+	}
+	return []*junitapi.JUnitTestCase{reportExcused(w.faults, w.excused)}, nil
+}
+
+func (w *injectedFaults) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (w *injectedFaults) Cleanup(ctx context.Context) error {
+	return nil
+}
@@ -3,7 +3,6 @@ package intervalserializer
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 	"time"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
@@ -39,7 +38,11 @@ func (*intervalSerializer) EvaluateTestsFromConstructedIntervals(ctx context.Con
 }
 
 func (*intervalSerializer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
-	return monitorserialization.EventsToFile(filepath.Join(storageDir, fmt.Sprintf("e2e-events%s.json", timeSuffix)), finalIntervals)
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("e2e-events%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return monitorserialization.EventsToFile(filename, finalIntervals)
 }
 
 func (*intervalSerializer) Cleanup(ctx context.Context) error {
@@ -0,0 +1,71 @@
+package loadgeneratoranalyzer
+
+import (
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IsLoadWindow matches the intervals produced by this analyzer.
+func IsLoadWindow(eventInterval monitorapi.Interval) bool {
+	return eventInterval.Source == monitorapi.SourceLoadGenerator
+}
+
+// loadNamespaceDuring returns the namespace of the load window a warning or error began in, or "" if it began
+// outside of every load window.
+func loadNamespaceDuring(windows monitorapi.Intervals, interval monitorapi.Interval) string {
+	if IsLoadWindow(interval) || (interval.Level != monitorapi.Warning && interval.Level != monitorapi.Error) {
+		return ""
+	}
+	for _, window := range windows {
+		if !interval.From.Before(window.From) && !interval.From.After(window.To) {
+			return window.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		}
+	}
+	return ""
+}
+
+func intervalsFromEvents_LoadGenerator(events monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	namespaceToStart := map[string]monitorapi.Interval{}
+
+	for _, event := range events {
+		if event.Source != monitorapi.SourceKubeEvent {
+			continue
+		}
+		namespace := event.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		if len(namespace) == 0 {
+			continue
+		}
+		switch event.Message.Reason {
+		case monitorapi.LoadGeneratorStarted:
+			namespaceToStart[namespace] = event
+		case monitorapi.LoadGeneratorStopped:
+			from := beginning
+			message := event.Message.HumanMessage
+			if start, ok := namespaceToStart[namespace]; ok {
+				from = start.From
+				message = start.Message.HumanMessage
+			}
+			delete(namespaceToStart, namespace)
+			ret = append(ret, loadWindow(namespace, message, from, event.From))
+		}
+	}
+
+	// load that never stopped was running until the end of the monitor.
+	for namespace, start := range namespaceToStart {
+		ret = append(ret, loadWindow(namespace, start.Message.HumanMessage, start.From, end))
+	}
+
+	return ret
+}
+
+func loadWindow(namespace, message string, from, to time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceLoadGenerator, monitorapi.Info).
+		Locator(monitorapi.NewLocator().LocateNamespace(namespace)).
+		Message(monitorapi.NewMessage().
+			HumanMessage(message).
+			Constructed(monitorapi.ConstructionOwnerLoadGenerator)).
+		Display().
+		Build(from, to)
+}
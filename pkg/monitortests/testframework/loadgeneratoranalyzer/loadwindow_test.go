@@ -0,0 +1,87 @@
+package loadgeneratoranalyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func loadEvent(namespace string, reason monitorapi.IntervalReason, at time.Time) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceKubeEvent, monitorapi.Info).
+		Locator(monitorapi.NewLocator().LocateNamespace(namespace)).
+		Message(monitorapi.NewMessage().Reason(reason).HumanMessage("load profile object-churn "+string(reason))).
+		Build(at, at)
+}
+
+func TestIntervalsFromEvents_LoadGenerator(t *testing.T) {
+	beginning := time.Unix(0, 0)
+	end := time.Unix(1000, 0)
+	events := monitorapi.Intervals{
+		loadEvent("e2e-load-a", monitorapi.LoadGeneratorStarted, time.Unix(10, 0)),
+		loadEvent("e2e-load-b", monitorapi.LoadGeneratorStarted, time.Unix(20, 0)),
+		loadEvent("e2e-load-a", monitorapi.LoadGeneratorStopped, time.Unix(30, 0)),
+		loadEvent("e2e-other", monitorapi.E2ETestStarted, time.Unix(40, 0)),
+	}
+
+	actual := intervalsFromEvents_LoadGenerator(events, beginning, end)
+	if len(actual) != 2 {
+		t.Fatalf("expected 2 load windows, got %d: %v", len(actual), actual)
+	}
+	expected := map[string][2]time.Time{
+		"e2e-load-a": {time.Unix(10, 0), time.Unix(30, 0)},
+		"e2e-load-b": {time.Unix(20, 0), end},
+	}
+	for _, window := range actual {
+		if !IsLoadWindow(window) {
+			t.Errorf("expected load window source, got %v", window.Source)
+		}
+		namespace := window.Locator.Keys[monitorapi.LocatorNamespaceKey]
+		bounds, ok := expected[namespace]
+		if !ok {
+			t.Errorf("unexpected window for %q", namespace)
+			continue
+		}
+		if !window.From.Equal(bounds[0]) || !window.To.Equal(bounds[1]) {
+			t.Errorf("%q: expected %v-%v, got %v-%v", namespace, bounds[0], bounds[1], window.From, window.To)
+		}
+	}
+}
+
+func TestAnnotateIntervalDuringLoad(t *testing.T) {
+	analyzer := &loadGeneratorAnalyzer{}
+	events := monitorapi.Intervals{
+		loadEvent("e2e-load-a", monitorapi.LoadGeneratorStarted, time.Unix(10, 0)),
+		loadEvent("e2e-load-a", monitorapi.LoadGeneratorStopped, time.Unix(30, 0)),
+	}
+	windows, err := analyzer.ConstructComputedIntervals(context.Background(), events, nil, time.Unix(0, 0), time.Unix(1000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disruption := func(level monitorapi.IntervalLevel, at int64) monitorapi.Interval {
+		return monitorapi.NewInterval(monitorapi.SourceDisruption, level).
+			Locator(monitorapi.NewLocator().DisruptionRequiredOnly("kube-api-new-connections", "openshift-tests")).
+			Message(monitorapi.NewMessage().HumanMessage("disrupted")).
+			Build(time.Unix(at, 0), time.Unix(at+5, 0))
+	}
+	tests := []struct {
+		name     string
+		interval monitorapi.Interval
+		expected string
+	}{
+		{name: "error during load", interval: disruption(monitorapi.Error, 20), expected: "e2e-load-a"},
+		{name: "error after load", interval: disruption(monitorapi.Error, 40)},
+		{name: "info during load", interval: disruption(monitorapi.Info, 20)},
+		{name: "load window", interval: windows[0]},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := analyzer.AnnotateInterval(test.interval)[monitorapi.AnnotationDuringLoad]
+			if actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
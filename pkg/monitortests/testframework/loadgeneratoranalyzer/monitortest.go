@@ -0,0 +1,71 @@
+package loadgeneratoranalyzer
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+type loadGeneratorAnalyzer struct {
+	windows monitorapi.Intervals
+}
+
+// NewAnalyzer turns the events recorded by the load generator test utility into load window intervals, and annotates
+// the warnings and errors that began during them with monitorapi.AnnotationDuringLoad, so disruption and latency
+// evaluation can account for the load.
+func NewAnalyzer() monitortestframework.MonitorTest {
+	return &loadGeneratorAnalyzer{}
+}
+
+var _ monitortestframework.IntervalAnnotator = &loadGeneratorAnalyzer{}
+
+func (w *loadGeneratorAnalyzer) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.ConstructComputedIntervalsStage,
+		},
+	}
+}
+
+var _ monitortestframework.OfflineMonitorTest = &loadGeneratorAnalyzer{}
+
+func (*loadGeneratorAnalyzer) SupportsOffline() bool {
+	return true
+}
+
+func (w *loadGeneratorAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	return nil
+}
+
+func (w *loadGeneratorAnalyzer) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	return nil, nil, nil
+}
+
+func (w *loadGeneratorAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	w.windows = intervalsFromEvents_LoadGenerator(startingIntervals, beginning, end)
+	return w.windows, nil
+}
+
+func (w *loadGeneratorAnalyzer) AnnotateInterval(interval monitorapi.Interval) map[monitorapi.AnnotationKey]string {
+	if namespace := loadNamespaceDuring(w.windows, interval); len(namespace) > 0 {
+		return map[monitorapi.AnnotationKey]string{monitorapi.AnnotationDuringLoad: namespace}
+	}
+	return nil
+}
+
+func (*loadGeneratorAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return nil, nil
+}
+
+func (*loadGeneratorAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (*loadGeneratorAnalyzer) Cleanup(ctx context.Context) error {
+	return nil
+}
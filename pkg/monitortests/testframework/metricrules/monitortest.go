@@ -0,0 +1,109 @@
+package metricrules
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type metricRules struct {
+	notSupportedReason error
+
+	rules           []monitortestframework.MetricRule
+	adminRESTConfig *rest.Config
+}
+
+// NewMetricRules queries the series of the rules in info.MetricRules from the in-cluster prometheus and records an
+// interval whenever a series crosses the threshold of its rule.  Rules with failAfter are invariants, and fail when a
+// series stays past the threshold for too long.
+func NewMetricRules(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	ret := &metricRules{}
+	if info.MetricRules != nil {
+		ret.rules = info.MetricRules.Rules
+	}
+	return ret
+}
+
+func (w *metricRules) Describe() monitortestframework.MonitorTestDescription {
+	testNames := []string{}
+	for _, rule := range w.rules {
+		testNames = append(testNames, testName(rule))
+	}
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: testNames,
+	}
+}
+
+func (w *metricRules) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	if len(w.rules) == 0 {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: "no metric rules are configured"}
+		return w.notSupportedReason
+	}
+	w.adminRESTConfig = adminRESTConfig
+	return nil
+}
+
+func (w *metricRules) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+
+	prometheusClient, err := prometheusaccess.NewPrometheusClient(ctx, w.adminRESTConfig)
+	if errors.Is(err, prometheusaccess.ErrMonitoringNotInstalled) {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{Reason: err.Error()}
+		return nil, nil, w.notSupportedReason
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// a rule that cannot be queried does not keep the others from being recorded.
+	ret := monitorapi.Intervals{}
+	errs := []error{}
+	for _, rule := range w.rules {
+		matrix, err := prometheusaccess.QueryRange(ctx, prometheusClient, rule.Query, prometheusv1.Range{Start: beginning, End: end, Step: rule.SampleStep()})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ret = append(ret, ruleIntervals(rule, matrix)...)
+	}
+	return ret, nil, utilerrors.NewAggregate(errs)
+}
+
+func (w *metricRules) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *metricRules) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	ret := []*junitapi.JUnitTestCase{}
+	for _, rule := range w.rules {
+		ret = append(ret, evaluateRule(rule, finalIntervals)...)
+	}
+	return ret, nil
+}
+
+func (w *metricRules) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return w.notSupportedReason
+}
+
+func (w *metricRules) Cleanup(ctx context.Context) error {
+	return w.notSupportedReason
+}
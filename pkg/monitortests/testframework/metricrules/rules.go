@@ -0,0 +1,111 @@
+package metricrules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/prometheusaccess"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// ruleIntervals returns an interval for every window in which a series of the rule was past its threshold.
+func ruleIntervals(rule monitortestframework.MetricRule, matrix prometheustypes.Matrix) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	step := rule.SampleStep()
+	for _, series := range matrix {
+		keys := map[monitorapi.LocatorKey]string{}
+		for key, label := range rule.Locator {
+			if value := series.Metric[prometheustypes.LabelName(label)]; len(value) > 0 {
+				keys[monitorapi.LocatorKey(key)] = string(value)
+			}
+		}
+		for _, window := range prometheusaccess.Windows(series.Values, step, rule.Crossed) {
+			peak := formatValue(extremeValue(rule, series.Values, window))
+			message := rule.Message
+			if len(message) == 0 {
+				message = fmt.Sprintf("%s was %s", rule.Name, rule.Threshold())
+			}
+			ret = append(ret,
+				monitorapi.NewInterval(monitorapi.SourceMetricRule, rule.IntervalLevel()).
+					Locator(monitorapi.NewLocator().MetricRule(rule.Name, keys)).
+					Message(monitorapi.NewMessage().Reason(monitorapi.MetricThresholdCrossedReason).
+						WithAnnotation(monitorapi.AnnotationPeak, peak).
+						HumanMessagef("%s, reaching %s", message, peak)).
+					Display().
+					Build(window.From, window.To),
+			)
+		}
+	}
+	return ret
+}
+
+// extremeValue is the value furthest past the threshold in the window: the largest for above, the smallest for below.
+func extremeValue(rule monitortestframework.MetricRule, samples []prometheustypes.SamplePair, window prometheusaccess.Window) float64 {
+	if rule.Above != nil {
+		return window.Peak
+	}
+	extreme := *rule.Below
+	for _, sample := range samples {
+		sampleTime := sample.Timestamp.Time()
+		if sampleTime.Before(window.From) || !sampleTime.Before(window.To) {
+			continue
+		}
+		if value := float64(sample.Value); value < extreme {
+			extreme = value
+		}
+	}
+	return extreme
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
+
+func testName(rule monitortestframework.MetricRule) string {
+	if len(rule.TestName) > 0 {
+		return rule.TestName
+	}
+	return fmt.Sprintf("[sig-arch] metric rule %s should not stay %s for more than %v", rule.Name, rule.Threshold(), rule.FailAfter.Duration)
+}
+
+// evaluateRule fails when a series of the rule stayed past its threshold for longer than the rule allows.  Rules
+// without FailAfter only record intervals and have no test.
+func evaluateRule(rule monitortestframework.MetricRule, finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	if rule.FailAfter == nil {
+		return nil
+	}
+	name := testName(rule)
+	tooLong := finalIntervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Source == monitorapi.SourceMetricRule &&
+			interval.Locator.Keys[monitorapi.LocatorMetricRuleKey] == rule.Name &&
+			interval.To.Sub(interval.From) > rule.FailAfter.Duration
+	})
+	if len(tooLong) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: name}}
+	}
+
+	sort.Slice(tooLong, func(i, j int) bool {
+		return tooLong[i].From.Before(tooLong[j].From)
+	})
+	lines := []string{}
+	for _, interval := range tooLong {
+		lines = append(lines, fmt.Sprintf("%v for %v: %v", interval.From.UTC().Format(time.RFC3339), interval.To.Sub(interval.From), interval.String()))
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: name,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d series of %q stayed %s for more than %v:\n%s",
+					len(tooLong), rule.Query, rule.Threshold(), rule.FailAfter.Duration, strings.Join(lines, "\n")),
+			},
+			SystemOut: strings.Join(lines, "\n"),
+		},
+	}
+}
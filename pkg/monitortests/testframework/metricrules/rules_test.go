@@ -0,0 +1,105 @@
+package metricrules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	prometheustypes "github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func series(instance string, step time.Duration, values ...float64) *prometheustypes.SampleStream {
+	ret := &prometheustypes.SampleStream{Metric: prometheustypes.Metric{"instance": prometheustypes.LabelValue(instance)}}
+	for i, value := range values {
+		ret.Values = append(ret.Values, prometheustypes.SamplePair{
+			Timestamp: prometheustypes.TimeFromUnixNano(start.Add(time.Duration(i) * step).UnixNano()),
+			Value:     prometheustypes.SampleValue(value),
+		})
+	}
+	return ret
+}
+
+func threshold(value float64) *float64 {
+	return &value
+}
+
+func TestRuleIntervals(t *testing.T) {
+	rule := monitortestframework.MetricRule{
+		Name:    "etcd-slow-fsync",
+		Query:   "fsync",
+		Above:   threshold(0.5),
+		Level:   "Error",
+		Locator: map[string]string{"node": "instance"},
+	}
+	step := rule.SampleStep()
+	intervals := ruleIntervals(rule, prometheustypes.Matrix{
+		series("master-0", step, 0.1, 0.2, 0.1),
+		series("master-1", step, 0.1, 0.7, 0.9, 0.1, 0.6),
+	})
+	if len(intervals) != 2 {
+		t.Fatalf("expected two intervals on master-1, got %v", intervals)
+	}
+
+	first := intervals[0]
+	if first.Level != monitorapi.Error || first.Source != monitorapi.SourceMetricRule || first.Message.Reason != monitorapi.MetricThresholdCrossedReason {
+		t.Errorf("unexpected interval %v", first)
+	}
+	if first.Locator.Keys[monitorapi.LocatorNodeKey] != "master-1" || first.Locator.Keys[monitorapi.LocatorMetricRuleKey] != "etcd-slow-fsync" {
+		t.Errorf("expected the locator to name the rule and the node, got %v", first.Locator.Keys)
+	}
+	if !first.From.Equal(start.Add(step)) || !first.To.Equal(start.Add(3*step)) {
+		t.Errorf("expected the window to last two steps, got %v to %v", first.From, first.To)
+	}
+	if peak := first.Message.Annotations[monitorapi.AnnotationPeak]; peak != "0.9" {
+		t.Errorf("expected a peak of 0.9, got %v", peak)
+	}
+}
+
+func TestRuleIntervalsBelow(t *testing.T) {
+	rule := monitortestframework.MetricRule{Name: "few-routers", Query: "routers", Below: threshold(2), Message: "too few routers were available"}
+	step := rule.SampleStep()
+	intervals := ruleIntervals(rule, prometheustypes.Matrix{series("", step, 2, 1, 0, 1, 2)})
+	if len(intervals) != 1 {
+		t.Fatalf("expected one interval, got %v", intervals)
+	}
+	if intervals[0].Level != monitorapi.Warning {
+		t.Errorf("expected the default level, got %v", intervals[0].Level)
+	}
+	if peak := intervals[0].Message.Annotations[monitorapi.AnnotationPeak]; peak != "0" {
+		t.Errorf("expected the lowest value, got %v", peak)
+	}
+	if message := intervals[0].Message.HumanMessage; message != "too few routers were available, reaching 0" {
+		t.Errorf("unexpected message %q", message)
+	}
+}
+
+func TestEvaluateRule(t *testing.T) {
+	rule := monitortestframework.MetricRule{Name: "etcd-slow-fsync", Query: "fsync", Above: threshold(0.5)}
+	step := rule.SampleStep()
+	intervals := ruleIntervals(rule, prometheustypes.Matrix{series("master-1", step, 0.6, 0.6, 0.6, 0.6, 0.1)})
+
+	if junits := evaluateRule(rule, intervals); len(junits) != 0 {
+		t.Errorf("expected no test for a rule without failAfter, got %v", junits)
+	}
+
+	rule.FailAfter = &metav1.Duration{Duration: 5 * step}
+	junits := evaluateRule(rule, intervals)
+	if len(junits) != 1 || junits[0].FailureOutput != nil {
+		t.Errorf("expected a passing test, got %v", junits)
+	}
+
+	rule.FailAfter = &metav1.Duration{Duration: 3 * step}
+	junits = evaluateRule(rule, intervals)
+	if len(junits) != 1 || junits[0].FailureOutput == nil {
+		t.Fatalf("expected a failing test, got %v", junits)
+	}
+	if !strings.Contains(junits[0].Name, "etcd-slow-fsync should not stay above 0.5 for more than 1m30s") {
+		t.Errorf("unexpected test name %q", junits[0].Name)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/pathologicaleventlibrary"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -14,6 +16,7 @@ import (
 )
 
 type pathologicalEventAnalyzer struct {
+	adminRESTConfig *rest.Config
 }
 
 func NewAnalyzer() monitortestframework.MonitorTest {
@@ -21,6 +24,7 @@ func NewAnalyzer() monitortestframework.MonitorTest {
 }
 
 func (w *pathologicalEventAnalyzer) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	w.adminRESTConfig = adminRESTConfig
 	return nil
 }
 
@@ -29,11 +33,27 @@ func (w *pathologicalEventAnalyzer) CollectData(ctx context.Context, storageDir
 }
 
 func (*pathologicalEventAnalyzer) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
-	return markMissedPathologicalEvents(startingIntervals), nil
+	ret := markMissedPathologicalEvents(startingIntervals)
+	ret = append(ret, pathologicaleventlibrary.RepeatedEventIntervals(pathologicaleventlibrary.FindRepeatedEvents(startingIntervals))...)
+	return ret, nil
 }
 
-func (*pathologicalEventAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
-	return nil, nil
+func (w *pathologicalEventAnalyzer) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	// upgrades have their own, longer, list of known exceptions.
+	var registry *pathologicaleventlibrary.AllowedPathologicalEventRegistry
+	if platformidentification.DidUpgradeHappenDuringCollection(finalIntervals, time.Time{}, time.Time{}) {
+		registry = pathologicaleventlibrary.NewUpgradePathologicalEventMatchers(w.adminRESTConfig, finalIntervals)
+	} else {
+		registry = pathologicaleventlibrary.NewUniversalPathologicalEventMatchers(w.adminRESTConfig, finalIntervals)
+	}
+
+	_, topology, err := pathologicaleventlibrary.GetClusterInfraInfo(w.adminRESTConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine the cluster topology: %w", err)
+	}
+
+	repeated := pathologicaleventlibrary.FindRepeatedEvents(finalIntervals)
+	return pathologicaleventlibrary.EvaluateRepeatedEvents(repeated, registry, topology), nil
 }
 
 func (*pathologicalEventAnalyzer) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
@@ -0,0 +1,167 @@
+package prsummary
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// Marker is the first line of every summary, so whatever relays it to the pull request can find and replace the
+	// comment of an earlier run.
+	Marker = "<!-- openshift-tests-monitor-summary -->"
+
+	// maxFailedTests is how many failed tests the summary lists before it only counts them.
+	maxFailedTests = 15
+	// maxFailureOutput is how much of the output of a failed test the summary holds.
+	maxFailureOutput = 600
+	// maxMessage is how much of the message of an interval the summary holds.
+	maxMessage = 120
+)
+
+type prSummary struct {
+	maxIntervals int
+	junits       []*junitapi.JUnitTestCase
+	getenv       func(string) string
+}
+
+// NewPRSummary returns a registry output that writes the failed monitor tests and the maxIntervals most interesting
+// intervals as compact markdown to pr-summary<timeSuffix>.md, for CI to post to the pull request that triggered the
+// presubmit.  The job is named from the JOB_NAME and BUILD_ID variables prow sets, if they are set.
+func NewPRSummary(maxIntervals int) monitortestframework.RegistryOutput {
+	return &prSummary{
+		maxIntervals: maxIntervals,
+		getenv:       os.Getenv,
+	}
+}
+
+func (o *prSummary) SetTestOutcomes(junits []*junitapi.JUnitTestCase) {
+	o.junits = junits
+}
+
+func (o *prSummary) WriteRegistryOutput(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	title := "Monitor summary"
+	if jobName, jobRunName := o.getenv("JOB_NAME"), o.getenv("BUILD_ID"); len(jobName) > 0 && len(jobRunName) > 0 {
+		title = fmt.Sprintf("Monitor summary of %s #%s", jobName, jobRunName)
+	}
+	content, err := RenderSummary(title, o.junits, finalIntervals, o.maxIntervals)
+	if err != nil {
+		return err
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("pr-summary%s.md", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, []byte(content), 0644)
+}
+
+// RenderSummary renders the tests that failed and never passed, and the maxIntervals most interesting intervals, as
+// GitHub flavored markdown.
+func RenderSummary(title string, junits []*junitapi.JUnitTestCase, finalIntervals monitorapi.Intervals, maxIntervals int) (string, error) {
+	results, err := monitor.BuildResultsSummary("", junits, nil)
+	if err != nil {
+		return "", err
+	}
+	failed := []monitor.TestResult{}
+	for _, test := range results.Tests {
+		if test.Status == monitor.TestFailed {
+			failed = append(failed, test)
+		}
+	}
+
+	out := &strings.Builder{}
+	fmt.Fprintln(out, Marker)
+	fmt.Fprintf(out, "### %s\n\n", title)
+	switch len(failed) {
+	case 0:
+		fmt.Fprintf(out, ":white_check_mark: No monitor test failed")
+	default:
+		fmt.Fprintf(out, ":x: %d monitor tests failed", len(failed))
+	}
+	fmt.Fprintf(out, " (%d tests, %d flaked).\n", results.NumTests, results.NumFlaked)
+
+	for i, test := range failed {
+		if i == maxFailedTests {
+			fmt.Fprintf(out, "\nand %d more.\n", len(failed)-maxFailedTests)
+			break
+		}
+		fmt.Fprintf(out, "\n<details><summary><code>%s</code>", escapeHTML(test.Name))
+		if len(test.JiraComponent) > 0 {
+			fmt.Fprintf(out, " (%s)", escapeHTML(test.JiraComponent))
+		}
+		fmt.Fprintf(out, "</summary>\n\n```\n%s\n```\n</details>\n", failureOutput(test))
+	}
+
+	interesting := InterestingIntervals(finalIntervals, maxIntervals)
+	if len(interesting) > 0 {
+		fmt.Fprintf(out, "\n#### Most interesting intervals\n\n")
+		fmt.Fprintf(out, "| Level | From | Duration | Locator | Message |\n")
+		fmt.Fprintf(out, "| --- | --- | --- | --- | --- |\n")
+		for _, interval := range interesting {
+			fmt.Fprintf(out, "| %s | %s | %s | `%s` | %s |\n",
+				interval.Level,
+				interval.From.UTC().Format(time.RFC3339),
+				interval.To.Sub(interval.From).Round(time.Second),
+				escapeCell(interval.Locator.OldLocator()),
+				escapeCell(truncate(interval.Message.HumanMessage, maxMessage)),
+			)
+		}
+	}
+	return out.String(), nil
+}
+
+// InterestingIntervals returns at most max of the error and warning intervals, errors first and the longest first
+// within a level.  The intervals of e2e tests are left out, because the failed tests are listed on their own.
+func InterestingIntervals(intervals monitorapi.Intervals, max int) monitorapi.Intervals {
+	ret := intervals.Filter(func(interval monitorapi.Interval) bool {
+		return interval.Level >= monitorapi.Warning && interval.Source != monitorapi.SourceE2ETest
+	})
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Level != ret[j].Level {
+			return ret[i].Level > ret[j].Level
+		}
+		if lengthI, lengthJ := ret[i].To.Sub(ret[i].From), ret[j].To.Sub(ret[j].From); lengthI != lengthJ {
+			return lengthI > lengthJ
+		}
+		return ret[i].From.Before(ret[j].From)
+	})
+	if len(ret) > max {
+		ret = ret[:max]
+	}
+	return ret
+}
+
+// failureOutput is the beginning of the first failure of the test, which fits in a code block.
+func failureOutput(test monitor.TestResult) string {
+	for _, testCase := range test.Cases {
+		if testCase.Status == monitor.TestFailed {
+			return strings.ReplaceAll(truncate(testCase.FailureMessage, maxFailureOutput), "```", "'''")
+		}
+	}
+	return ""
+}
+
+func truncate(s string, length int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= length {
+		return s
+	}
+	return s[:length] + "..."
+}
+
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// escapeCell keeps a value in its table cell.
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ", "`", "'").Replace(s)
+}
@@ -0,0 +1,94 @@
+package prsummary
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func interval(source monitorapi.IntervalSource, level monitorapi.IntervalLevel, node, message string, from time.Time, length time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(source, level).
+		Locator(monitorapi.NewLocator().NodeFromName(node)).
+		Message(monitorapi.NewMessage().HumanMessage(message)).
+		Build(from, from.Add(length))
+}
+
+func TestInterestingIntervals(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	intervals := monitorapi.Intervals{
+		interval(monitorapi.SourceNodeMonitor, monitorapi.Info, "info", "", start, time.Hour),
+		interval(monitorapi.SourceNodeMonitor, monitorapi.Warning, "long-warning", "", start, time.Hour),
+		interval(monitorapi.SourceNodeMonitor, monitorapi.Error, "short-error", "", start, time.Second),
+		interval(monitorapi.SourceE2ETest, monitorapi.Error, "e2e-test", "", start, time.Hour),
+		interval(monitorapi.SourceNodeMonitor, monitorapi.Error, "long-error", "", start, time.Minute),
+	}
+
+	actual := []string{}
+	for _, interval := range InterestingIntervals(intervals, 3) {
+		actual = append(actual, interval.Locator.Keys[monitorapi.LocatorNodeKey])
+	}
+	expected := []string{"long-error", "short-error", "long-warning"}
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestWriteRegistryOutput(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	output := NewPRSummary(5).(*prSummary)
+	env := map[string]string{"JOB_NAME": "pull-ci-openshift-origin-main-e2e-aws", "BUILD_ID": "1234"}
+	output.getenv = func(key string) string { return env[key] }
+	output.SetTestOutcomes([]*junitapi.JUnitTestCase{
+		{Name: "passes"},
+		{Name: "flakes", FailureOutput: &junitapi.FailureOutput{Output: "failed once"}},
+		{Name: "flakes"},
+		{
+			Name:          "[sig-node] nodes should not go <NotReady>",
+			FailureOutput: &junitapi.FailureOutput{Output: "node/worker-0 went NotReady\n```"},
+			Details:       &junitapi.JUnitTestCaseDetails{MonitorTest: "node-state-analyzer", JiraComponent: "Node / Kubelet"},
+		},
+	})
+	intervals := monitorapi.Intervals{
+		interval(monitorapi.SourceNodeMonitor, monitorapi.Error, "worker-0", "went NotReady | rebooted", start, 90*time.Second),
+	}
+
+	storageDir := t.TempDir()
+	if err := output.WriteRegistryOutput(context.Background(), storageDir, "_20240101-000000", intervals, nil); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(storageDir, "pr-summary_20240101-000000.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		Marker,
+		"### Monitor summary of pull-ci-openshift-origin-main-e2e-aws #1234",
+		"",
+		":x: 1 monitor tests failed (3 tests, 1 flaked).",
+		"",
+		"<details><summary><code>[sig-node] nodes should not go &lt;NotReady&gt;</code> (Node / Kubelet)</summary>",
+		"",
+		"```",
+		"node/worker-0 went NotReady",
+		"'''",
+		"```",
+		"</details>",
+		"",
+		"#### Most interesting intervals",
+		"",
+		"| Level | From | Duration | Locator | Message |",
+		"| --- | --- | --- | --- | --- |",
+		"| Error | 2024-01-01T00:00:00Z | 1m30s | `node/worker-0` | went NotReady \\| rebooted |",
+		"",
+	}, "\n")
+	if string(content) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
@@ -0,0 +1,61 @@
+package resourceleaks
+
+import (
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	leakedTestName = "[sig-arch] tests should not leak namespaces, custom resource definitions, cluster RBAC, or webhooks"
+	stuckTestName  = "[sig-arch] objects deleted by tests should not be stuck on their finalizers"
+)
+
+var leakedTemplate = junitfailure.MustParseTemplate("resource-leaked",
+	`{{len .Intervals}} objects created by tests were still present at the end of the run.  Each interval spans the tests running when the object was created.
+{{.IntervalList}}`)
+
+var stuckTemplate = junitfailure.MustParseTemplate("resource-stuck-deleting",
+	`{{len .Intervals}} objects deleted during the run were still waiting on their finalizers after {{.Fields.threshold}}.  Each interval spans the tests running when the object was deleted.
+{{.IntervalList}}`)
+
+// evaluateLeaks fails for objects tests left behind and for deletions stuck on finalizers.
+func evaluateLeaks(finalIntervals monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	leaked, stuck := monitorapi.Intervals{}, monitorapi.Intervals{}
+	for _, interval := range finalIntervals {
+		if interval.Source != monitorapi.SourceResourceLeak {
+			continue
+		}
+		switch interval.Message.Reason {
+		case monitorapi.ResourceLeakedReason:
+			leaked = append(leaked, interval)
+		case monitorapi.ResourceStuckDeletingReason:
+			stuck = append(stuck, interval)
+		}
+	}
+
+	ret := []*junitapi.JUnitTestCase{}
+	if len(leaked) == 0 {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: leakedTestName})
+	} else {
+		junit := junitfailure.NewFailure(leakedTemplate, "ResourcesLeaked").
+			Intervals(leaked...).
+			TestCase(leakedTestName)
+		// a warning until the tests that leak are fixed.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+
+	if len(stuck) == 0 {
+		ret = append(ret, &junitapi.JUnitTestCase{Name: stuckTestName})
+	} else {
+		junit := junitfailure.NewFailure(stuckTemplate, "ResourcesStuckDeleting").
+			Field("threshold", stuckDeletionThreshold.String()).
+			Intervals(stuck...).
+			TestCase(stuckTestName)
+		// a warning until we know how often finalizers hold deletions for reasons outside the tests.
+		junit.Details.Severity = junitapi.SeverityWarn
+		ret = append(ret, junit)
+	}
+	return ret
+}
@@ -0,0 +1,116 @@
+package resourceleaks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// stuckDeletionThreshold is how long the deletion of an object may wait on its finalizers.
+const stuckDeletionThreshold = 10 * time.Minute
+
+// findLeaks compares the snapshots from the beginning and the end of the run.  Leaked objects were created during the
+// run and are still there, stuck objects were deleted during the run and are still waiting on their finalizers.
+func findLeaks(before, after snapshot, beginning, end time.Time) (leaked, stuck []objectState) {
+	for uid, object := range after {
+		if !object.deletionStarted.IsZero() {
+			if !object.deletionStarted.Before(beginning) && end.Sub(object.deletionStarted) >= stuckDeletionThreshold && len(object.finalizers) > 0 {
+				stuck = append(stuck, object)
+			}
+			continue
+		}
+		// only the namespaces the e2e framework created for tests are expected to be cleaned up.
+		if object.managed || (object.kind == "Namespace" && !object.e2eNamespace) {
+			continue
+		}
+		if _, existed := before[uid]; !existed {
+			leaked = append(leaked, object)
+		}
+	}
+	return leaked, stuck
+}
+
+// testsRunningAt returns the tests running at a time.
+func testsRunningAt(at time.Time, e2eTests monitorapi.Intervals) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	for _, test := range e2eTests {
+		if !at.Before(test.From) && !at.After(test.To) {
+			ret = append(ret, test)
+		}
+	}
+	return ret
+}
+
+// leakIntervals returns an interval for every leaked and stuck object spanning the tests that were running when it was
+// created or deleted, the window in which a test leaked it.  Leaked cluster scoped objects created while no test was
+// running were not created by a test and are left out.
+func leakIntervals(leaked, stuck []objectState, startingIntervals monitorapi.Intervals, end time.Time) monitorapi.Intervals {
+	e2eTests := monitorapi.Intervals{}
+	for _, interval := range startingIntervals {
+		if interval.Source == monitorapi.SourceE2ETest && interval.To.After(interval.From) {
+			e2eTests = append(e2eTests, interval)
+		}
+	}
+
+	ret := monitorapi.Intervals{}
+	for _, object := range leaked {
+		tests := testsRunningAt(object.created, e2eTests)
+		if len(tests) == 0 && object.kind != "Namespace" {
+			continue
+		}
+		ret = append(ret, leakInterval(object, monitorapi.ResourceLeakedReason, object.created, tests,
+			fmt.Sprintf("created during %s and still present at the end of the run", describeTests(tests))))
+	}
+	for _, object := range stuck {
+		tests := testsRunningAt(object.deletionStarted, e2eTests)
+		ret = append(ret, leakInterval(object, monitorapi.ResourceStuckDeletingReason, object.deletionStarted, tests,
+			fmt.Sprintf("deleted during %s and still waiting on finalizers after %s", describeTests(tests), end.Sub(object.deletionStarted).Round(time.Second))))
+	}
+	sort.Sort(ret)
+	return ret
+}
+
+func leakInterval(object objectState, reason monitorapi.IntervalReason, at time.Time, tests monitorapi.Intervals, humanMessage string) monitorapi.Interval {
+	from, to := at, at
+	for _, test := range tests {
+		if test.From.Before(from) {
+			from = test.From
+		}
+		if test.To.After(to) {
+			to = test.To
+		}
+	}
+
+	locator := monitorapi.NewLocator().ClusterScopedKind(object.kind, object.name)
+	if object.kind == "Namespace" {
+		locator = monitorapi.NewLocator().LocateNamespace(object.name)
+	}
+	message := monitorapi.NewMessage().Reason(reason).
+		Constructed(monitorapi.ConstructionOwnerResourceLeaks).
+		HumanMessage(humanMessage)
+	if reason == monitorapi.ResourceStuckDeletingReason {
+		message = message.WithAnnotation(monitorapi.AnnotationFinalizers, strings.Join(object.finalizers, ","))
+	}
+	return monitorapi.NewInterval(monitorapi.SourceResourceLeak, monitorapi.Warning).
+		Locator(locator).
+		Message(message).
+		Display().
+		Build(from, to)
+}
+
+func describeTests(tests monitorapi.Intervals) string {
+	if len(tests) == 0 {
+		return "no test"
+	}
+	names := []string{}
+	for _, test := range tests {
+		if name, ok := monitorapi.E2ETestFromLocator(test.Locator); ok {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,128 @@
+package resourceleaks
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func object(kind, name string, created time.Duration, mutate func(obj *unstructured.Unstructured)) objectState {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetName(name)
+	obj.SetUID(types.UID(kind + "/" + name))
+	obj.SetCreationTimestamp(metav1.NewTime(start.Add(created)))
+	if mutate != nil {
+		mutate(obj)
+	}
+	return objectStateFrom(kind, obj)
+}
+
+func deleted(at time.Duration, finalizers ...string) func(obj *unstructured.Unstructured) {
+	return func(obj *unstructured.Unstructured) {
+		deletion := metav1.NewTime(start.Add(at))
+		obj.SetDeletionTimestamp(&deletion)
+		obj.SetFinalizers(finalizers)
+	}
+}
+
+func e2eNamespace(obj *unstructured.Unstructured) {
+	obj.SetLabels(map[string]string{e2eNamespaceLabel: "sig-apps"})
+}
+
+func e2eTest(name string, from, to time.Duration) monitorapi.Interval {
+	return monitorapi.NewInterval(monitorapi.SourceE2ETest, monitorapi.Info).
+		Locator(monitorapi.NewLocator().E2ETest(name)).
+		Message(monitorapi.NewMessage().HumanMessage("e2e test finished")).
+		Build(start.Add(from), start.Add(to))
+}
+
+func TestFindLeaks(t *testing.T) {
+	before := snapshot{}
+	for _, obj := range []objectState{
+		object("ClusterRole", "admin", -time.Hour, nil),
+		object("Namespace", "e2e-old-ns", -time.Hour, e2eNamespace),
+	} {
+		before[obj.uid] = obj
+	}
+
+	after := snapshot{}
+	for _, obj := range []objectState{
+		object("ClusterRole", "admin", -time.Hour, nil),
+		object("Namespace", "e2e-old-ns", -time.Hour, e2eNamespace),
+		// leaked by tests.
+		object("Namespace", "e2e-deployment-1234", 10*time.Minute, e2eNamespace),
+		object("CustomResourceDefinition", "e2e-test-crd.example.com", 10*time.Minute, nil),
+		// not created by the e2e framework or kept by an owner.
+		object("Namespace", "e2e-connectivity-mesh-abcde", 0, nil),
+		object("ClusterRole", "operator-role", 10*time.Minute, func(obj *unstructured.Unstructured) {
+			obj.SetAnnotations(map[string]string{"include.release.openshift.io/self-managed-high-availability": "true"})
+		}),
+		// stuck on a finalizer.
+		object("Namespace", "e2e-stuck-5678", 5*time.Minute, func(obj *unstructured.Unstructured) {
+			e2eNamespace(obj)
+			deleted(20 * time.Minute)(obj)
+			obj.Object["spec"] = map[string]interface{}{"finalizers": []interface{}{"kubernetes"}}
+		}),
+		// still being deleted, but not for long.
+		object("ValidatingWebhookConfiguration", "e2e-webhook", 40*time.Minute, deleted(55*time.Minute, "example.com/cleanup")),
+	} {
+		after[obj.uid] = obj
+	}
+
+	leaked, stuck := findLeaks(before, after, start, start.Add(time.Hour))
+	if len(leaked) != 2 {
+		t.Errorf("expected the e2e namespace and the CRD to leak, got %v", leaked)
+	}
+	if len(stuck) != 1 || stuck[0].name != "e2e-stuck-5678" || stuck[0].finalizers[0] != "kubernetes" {
+		t.Errorf("expected the namespace stuck on its spec finalizer, got %v", stuck)
+	}
+}
+
+func TestLeakIntervals(t *testing.T) {
+	leaked := []objectState{
+		object("Namespace", "e2e-deployment-1234", 10*time.Minute, e2eNamespace),
+		object("ClusterRoleBinding", "e2e-binding", 12*time.Minute, nil),
+		// created while no test was running.
+		object("ClusterRole", "installer-role", 30*time.Minute, nil),
+	}
+	stuck := []objectState{
+		object("Namespace", "e2e-stuck-5678", 5*time.Minute, deleted(20*time.Minute, "kubernetes")),
+	}
+	startingIntervals := monitorapi.Intervals{
+		e2eTest("[sig-apps] Deployment should roll back", 8*time.Minute, 11*time.Minute),
+		e2eTest("[sig-auth] bindings should be created", 9*time.Minute, 13*time.Minute),
+		e2eTest("[sig-node] namespace cleanup", 19*time.Minute, 21*time.Minute),
+	}
+
+	intervals := leakIntervals(leaked, stuck, startingIntervals, start.Add(time.Hour))
+	if len(intervals) != 3 {
+		t.Fatalf("expected the leak no test explains to be left out, got %v", intervals)
+	}
+	namespaceLeak := intervals[0]
+	if namespaceLeak.Locator.Keys[monitorapi.LocatorNamespaceKey] != "e2e-deployment-1234" {
+		t.Fatalf("expected the namespace leak first, got %v", namespaceLeak)
+	}
+	if !namespaceLeak.From.Equal(start.Add(8*time.Minute)) || !namespaceLeak.To.Equal(start.Add(13*time.Minute)) {
+		t.Errorf("expected the leak to span both tests running when the namespace was created, got %v to %v", namespaceLeak.From, namespaceLeak.To)
+	}
+
+	junits := evaluateLeaks(intervals)
+	if len(junits) != 2 {
+		t.Fatalf("expected a leak and a stuck test, got %v", junits)
+	}
+	for _, junit := range junits {
+		if junit.FailureOutput == nil {
+			t.Errorf("expected %q to fail", junit.Name)
+		} else if junit.Details.Severity != junitapi.SeverityWarn {
+			t.Errorf("expected a warning, got %q", junit.Details.Severity)
+		}
+	}
+}
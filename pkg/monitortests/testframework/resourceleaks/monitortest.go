@@ -0,0 +1,80 @@
+package resourceleaks
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type resourceLeaks struct {
+	dynamicClient dynamic.Interface
+	before        snapshot
+
+	leaked []objectState
+	stuck  []objectState
+}
+
+// NewResourceLeaks snapshots the namespaces, custom resource definitions, cluster RBAC, and webhooks before and after
+// the run, and fails for the objects tests left behind and the deletions stuck on finalizers.
+func NewResourceLeaks() monitortestframework.MonitorTest {
+	return &resourceLeaks{}
+}
+
+func (w *resourceLeaks) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.ConstructComputedIntervalsStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{leakedTestName, stuckTestName},
+	}
+}
+
+func (w *resourceLeaks) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	dynamicClient, err := dynamic.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	w.dynamicClient = dynamicClient
+
+	w.before, err = takeSnapshot(ctx, w.dynamicClient)
+	return err
+}
+
+func (w *resourceLeaks) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.before == nil {
+		// the snapshot at the beginning failed, there is nothing to compare to.
+		return nil, nil, nil
+	}
+	after, err := takeSnapshot(ctx, w.dynamicClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.leaked, w.stuck = findLeaks(w.before, after, beginning, end)
+	return nil, nil, nil
+}
+
+func (w *resourceLeaks) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	// the e2e test intervals are needed to find the tests that leaked, so the intervals are built here.
+	return leakIntervals(w.leaked, w.stuck, startingIntervals, end), nil
+}
+
+func (w *resourceLeaks) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	return evaluateLeaks(finalIntervals), nil
+}
+
+func (w *resourceLeaks) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	return nil
+}
+
+func (w *resourceLeaks) Cleanup(ctx context.Context) error {
+	return nil
+}
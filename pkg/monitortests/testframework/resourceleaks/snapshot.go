@@ -0,0 +1,95 @@
+package resourceleaks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// trackedResource is a kind of object that outlives the tests creating it unless they clean it up.
+type trackedResource struct {
+	resource schema.GroupVersionResource
+	kind     string
+}
+
+var trackedResources = []trackedResource{
+	{resource: schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, kind: "Namespace"},
+	{resource: schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}, kind: "CustomResourceDefinition"},
+	{resource: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, kind: "ClusterRole"},
+	{resource: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterrolebindings"}, kind: "ClusterRoleBinding"},
+	{resource: schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations"}, kind: "ValidatingWebhookConfiguration"},
+	{resource: schema.GroupVersionResource{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "mutatingwebhookconfigurations"}, kind: "MutatingWebhookConfiguration"},
+}
+
+// e2eNamespaceLabel is set on every namespace the e2e framework creates for a test.
+const e2eNamespaceLabel = "e2e-framework"
+
+// objectState is what a snapshot keeps of a tracked object.
+type objectState struct {
+	kind string
+	name string
+	uid  types.UID
+
+	created time.Time
+	// deletionStarted is zero until the object is deleted.
+	deletionStarted time.Time
+	// finalizers hold the deletion of the object, for namespaces these include the finalizers of the spec.
+	finalizers []string
+
+	// managed is true for objects kept by an owner, the cluster version operator, or OLM.  Those are not left behind
+	// by tests.
+	managed bool
+	// e2eNamespace is true for the namespaces the e2e framework creates for tests.
+	e2eNamespace bool
+}
+
+// snapshot holds the tracked objects by uid, so an object recreated with the same name is a different object.
+type snapshot map[types.UID]objectState
+
+func takeSnapshot(ctx context.Context, client dynamic.Interface) (snapshot, error) {
+	ret := snapshot{}
+	for _, tracked := range trackedResources {
+		list, err := client.Resource(tracked.resource).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s: %w", tracked.resource.Resource, err)
+		}
+		for i := range list.Items {
+			state := objectStateFrom(tracked.kind, &list.Items[i])
+			ret[state.uid] = state
+		}
+	}
+	return ret, nil
+}
+
+func objectStateFrom(kind string, obj *unstructured.Unstructured) objectState {
+	ret := objectState{
+		kind:       kind,
+		name:       obj.GetName(),
+		uid:        obj.GetUID(),
+		created:    obj.GetCreationTimestamp().Time,
+		finalizers: obj.GetFinalizers(),
+	}
+	if deletion := obj.GetDeletionTimestamp(); deletion != nil {
+		ret.deletionStarted = deletion.Time
+	}
+	if specFinalizers, found, _ := unstructured.NestedStringSlice(obj.Object, "spec", "finalizers"); found {
+		ret.finalizers = append(ret.finalizers, specFinalizers...)
+	}
+
+	_, ret.e2eNamespace = obj.GetLabels()[e2eNamespaceLabel]
+	_, olmOwned := obj.GetLabels()["olm.owner"]
+	ret.managed = len(obj.GetOwnerReferences()) > 0 || olmOwned
+	for key := range obj.GetAnnotations() {
+		if strings.HasPrefix(key, "include.release.openshift.io/") {
+			ret.managed = true
+		}
+	}
+	return ret
+}
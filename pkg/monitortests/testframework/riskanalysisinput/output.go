@@ -0,0 +1,146 @@
+package riskanalysisinput
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/monitortests/testframework/alertanalyzer"
+	"github.com/openshift/origin/pkg/monitortests/testframework/disruptionserializer"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// SchemaVersion is the version of RiskAnalysisInput this code writes.  Bump it whenever a field changes meaning or
+// is removed.  Adding a field does not need a new version.
+const SchemaVersion = 1
+
+// RiskAnalysisInput is everything risk analysis needs to compare a job run against the history of similar job runs.
+// It is written to risk-analysis-input<timeSuffix>.json.
+type RiskAnalysisInput struct {
+	// SchemaVersion is the version of this format, see SchemaVersion.
+	SchemaVersion int `json:"schemaVersion"`
+	// ClusterData is the kind of cluster the job ran on, which selects the job runs to compare against.
+	ClusterData platformidentification.ClusterData `json:"clusterData"`
+	// Tests is the outcome of every monitor test, by name.
+	Tests []TestOutcome `json:"tests"`
+	// DisruptionSeconds is how long every backend was disrupted, by backend name.  Backends that were checked and
+	// never disrupted are zero.
+	DisruptionSeconds map[string]float64 `json:"disruptionSeconds"`
+	// Alerts is how long every warning and critical alert fired, ordered by name, namespace and level.
+	Alerts []AlertSummary `json:"alerts"`
+}
+
+// TestOutcome is the outcome of every junit with the same name.  A test that both passed and failed flaked.
+type TestOutcome struct {
+	Name          string             `json:"name"`
+	Status        monitor.TestStatus `json:"status"`
+	MonitorTest   string             `json:"monitorTest,omitempty"`
+	JiraComponent string             `json:"jiraComponent,omitempty"`
+}
+
+type AlertSummary struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	// Level is Warning or Critical.
+	Level         string  `json:"level"`
+	FiringSeconds float64 `json:"firingSeconds"`
+}
+
+// ClusterDataSource returns the cluster data of the run.
+type ClusterDataSource interface {
+	ClusterData() platformidentification.ClusterData
+}
+
+type riskAnalysisInput struct {
+	clusterData ClusterDataSource
+	junits      []*junitapi.JUnitTestCase
+}
+
+// NewRiskAnalysisInput returns a registry output that consolidates what risk analysis reads into one file.  The
+// cluster data is read from the source after every monitor test has written to storage.
+func NewRiskAnalysisInput(clusterData ClusterDataSource) monitortestframework.RegistryOutput {
+	return &riskAnalysisInput{
+		clusterData: clusterData,
+	}
+}
+
+func (o *riskAnalysisInput) SetTestOutcomes(junits []*junitapi.JUnitTestCase) {
+	o.junits = junits
+}
+
+func (o *riskAnalysisInput) WriteRegistryOutput(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	input, err := BuildRiskAnalysisInput(o.clusterData.ClusterData(), o.junits, finalIntervals)
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(input, "", "    ")
+	if err != nil {
+		return err
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("risk-analysis-input%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
+
+func (o *riskAnalysisInput) ArtifactSchema(relativePath string) string {
+	if strings.HasPrefix(path.Base(relativePath), "risk-analysis-input") {
+		return fmt.Sprintf("risk-analysis-input/v%d", SchemaVersion)
+	}
+	return ""
+}
+
+// BuildRiskAnalysisInput consolidates the cluster data, the junits of the monitor tests and the final intervals.
+func BuildRiskAnalysisInput(clusterData platformidentification.ClusterData, junits []*junitapi.JUnitTestCase, finalIntervals monitorapi.Intervals) (*RiskAnalysisInput, error) {
+	summary, err := monitor.BuildResultsSummary("", junits, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &RiskAnalysisInput{
+		SchemaVersion:     SchemaVersion,
+		ClusterData:       clusterData,
+		Tests:             []TestOutcome{},
+		DisruptionSeconds: map[string]float64{},
+		Alerts:            []AlertSummary{},
+	}
+	for _, test := range summary.Tests {
+		ret.Tests = append(ret.Tests, TestOutcome{
+			Name:          test.Name,
+			Status:        test.Status,
+			MonitorTest:   test.MonitorTest,
+			JiraComponent: test.JiraComponent,
+		})
+	}
+	for name, disruption := range disruptionserializer.ComputeDisruptionData(finalIntervals).BackendDisruptions {
+		ret.DisruptionSeconds[name] = disruption.DisruptedDuration.Seconds()
+	}
+	for _, alert := range alertanalyzer.ComputeAlertData(finalIntervals).Alerts {
+		ret.Alerts = append(ret.Alerts, AlertSummary{
+			Name:          alert.Name,
+			Namespace:     alert.Namespace,
+			Level:         string(alert.Level),
+			FiringSeconds: alert.Duration.Seconds(),
+		})
+	}
+	// the alert data is only sorted by its first differing key, which is not a total order.
+	sort.SliceStable(ret.Alerts, func(i, j int) bool {
+		if ret.Alerts[i].Name != ret.Alerts[j].Name {
+			return ret.Alerts[i].Name < ret.Alerts[j].Name
+		}
+		if ret.Alerts[i].Namespace != ret.Alerts[j].Namespace {
+			return ret.Alerts[i].Namespace < ret.Alerts[j].Namespace
+		}
+		return ret.Alerts[i].Level < ret.Alerts[j].Level
+	})
+	return ret, nil
+}
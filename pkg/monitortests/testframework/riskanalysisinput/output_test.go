@@ -0,0 +1,117 @@
+package riskanalysisinput
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestlibrary/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+type fixedClusterData platformidentification.ClusterData
+
+func (d fixedClusterData) ClusterData() platformidentification.ClusterData {
+	return platformidentification.ClusterData(d)
+}
+
+func disruptionInterval(level monitorapi.IntervalLevel, reason monitorapi.IntervalReason, from, to time.Time) monitorapi.Interval {
+	return monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level: level,
+			Locator: monitorapi.Locator{
+				Type: monitorapi.LocatorTypeDisruption,
+				Keys: map[monitorapi.LocatorKey]string{
+					monitorapi.LocatorBackendDisruptionNameKey: "kube-api-new-connections",
+					monitorapi.LocatorDisruptionKey:            "kube-api",
+					monitorapi.LocatorConnectionKey:            "new",
+				},
+			},
+			Message: monitorapi.Message{
+				Reason:      reason,
+				Annotations: map[monitorapi.AnnotationKey]string{monitorapi.AnnotationReason: string(reason)},
+			},
+		},
+		Source: monitorapi.SourceDisruption,
+		From:   from,
+		To:     to,
+	}
+}
+
+func alertInterval(name, namespace string, level monitorapi.IntervalLevel, from, to time.Time) monitorapi.Interval {
+	return monitorapi.Interval{
+		Condition: monitorapi.Condition{
+			Level: level,
+			Locator: monitorapi.Locator{
+				Type: monitorapi.LocatorTypeAlert,
+				Keys: map[monitorapi.LocatorKey]string{
+					monitorapi.LocatorAlertKey:     name,
+					monitorapi.LocatorNamespaceKey: namespace,
+				},
+			},
+		},
+		Source: monitorapi.SourceAlert,
+		From:   from,
+		To:     to,
+	}
+}
+
+func TestWriteRegistryOutput(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clusterData := platformidentification.ClusterData{
+		SchemaVersion: platformidentification.ClusterDataSchemaVersion,
+		JobType:       platformidentification.JobType{Release: "4.16", Platform: "aws", Topology: "ha"},
+	}
+	output := NewRiskAnalysisInput(fixedClusterData(clusterData))
+	output.(*riskAnalysisInput).SetTestOutcomes([]*junitapi.JUnitTestCase{
+		{Name: "passes", Details: &junitapi.JUnitTestCaseDetails{MonitorTest: "a", JiraComponent: "A"}},
+		{Name: "flakes", FailureOutput: &junitapi.FailureOutput{Output: "failed"}},
+		{Name: "flakes"},
+		{Name: "fails", FailureOutput: &junitapi.FailureOutput{Output: "failed"}},
+	})
+	intervals := monitorapi.Intervals{
+		disruptionInterval(monitorapi.Info, monitorapi.DisruptionEndedEventReason, start, start.Add(time.Minute)),
+		disruptionInterval(monitorapi.Error, monitorapi.DisruptionBeganEventReason, start.Add(time.Minute), start.Add(time.Minute+5*time.Second)),
+		alertInterval("KubePodNotReady", "openshift-etcd", monitorapi.Warning, start, start.Add(30*time.Second)),
+		alertInterval("KubePodNotReady", "openshift-etcd", monitorapi.Warning, start.Add(time.Minute), start.Add(90*time.Second)),
+		alertInterval("KubePodNotReady", "openshift-apiserver", monitorapi.Warning, start, start.Add(10*time.Second)),
+		alertInterval("Watchdog", "openshift-monitoring", monitorapi.Warning, start, start.Add(time.Hour)),
+	}
+
+	storageDir := t.TempDir()
+	if err := output.WriteRegistryOutput(context.Background(), storageDir, "_suffix", intervals, nil); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(storageDir, "risk-analysis-input_suffix.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := &RiskAnalysisInput{}
+	if err := json.Unmarshal(content, actual); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &RiskAnalysisInput{
+		SchemaVersion: SchemaVersion,
+		ClusterData:   clusterData,
+		Tests: []TestOutcome{
+			{Name: "fails", Status: monitor.TestFailed},
+			{Name: "flakes", Status: monitor.TestFlaked},
+			{Name: "passes", Status: monitor.TestPassed, MonitorTest: "a", JiraComponent: "A"},
+		},
+		DisruptionSeconds: map[string]float64{"kube-api-new-connections": 5},
+		Alerts: []AlertSummary{
+			{Name: "KubePodNotReady", Namespace: "openshift-apiserver", Level: "Warning", FiringSeconds: 10},
+			{Name: "KubePodNotReady", Namespace: "openshift-etcd", Level: "Warning", FiringSeconds: 60},
+		},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expected, actual)
+	}
+}
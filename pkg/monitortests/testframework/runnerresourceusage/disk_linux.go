@@ -0,0 +1,15 @@
+package runnerresourceusage
+
+import (
+	"syscall"
+)
+
+func diskUsage(path string) (used, total uint64) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0
+	}
+	total = stat.Blocks * uint64(stat.Bsize)
+	free := stat.Bfree * uint64(stat.Bsize)
+	return total - free, total
+}
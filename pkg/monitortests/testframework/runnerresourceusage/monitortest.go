@@ -0,0 +1,262 @@
+package runnerresourceusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/junitfailure"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	samplingInterval = 15 * time.Second
+
+	// limitRatio is how close to a limit the runner may get before we report it.
+	limitRatio = 0.9
+
+	memoryTestName = "[sig-arch] openshift-tests runner should stay below its memory limit"
+	diskTestName   = "[sig-arch] openshift-tests runner should not fill its disk"
+)
+
+// errNotSampled is returned when the run is evaluated without collection having been started, for instance when the
+// artifacts of an earlier run are analyzed.
+var errNotSampled = &monitortestframework.NotSupportedError{
+	Reason: "runner resource usage was not sampled because collection was not started",
+}
+
+type runnerResourceUsage struct {
+	notSupportedReason error
+	clock              clock.PassiveClock
+
+	reader *usageReader
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	lock   sync.Mutex
+	report *usageReport
+}
+
+// NewRunnerResourceUsage records the cpu, memory, and disk used by openshift-tests and its children.
+// Runners that are OOM killed look like mysterious job interruptions, this makes the cause visible.
+func NewRunnerResourceUsage() monitortestframework.MonitorTest {
+	return &runnerResourceUsage{
+		clock: clock.RealClock{},
+	}
+}
+
+var _ monitortestframework.ClockedMonitorTest = &runnerResourceUsage{}
+
+func (w *runnerResourceUsage) SetClock(clock clock.PassiveClock) {
+	w.clock = clock
+}
+
+func (w *runnerResourceUsage) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.CollectDataStage,
+			monitortestframework.EvaluateTestsStage,
+			monitortestframework.WriteContentToStorageStage,
+			monitortestframework.CleanupStage,
+		},
+		TestNames: []string{memoryTestName, diskTestName},
+	}
+}
+
+func (w *runnerResourceUsage) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	if runtime.GOOS != "linux" {
+		w.notSupportedReason = &monitortestframework.NotSupportedError{
+			Reason: fmt.Sprintf("runner resource usage is not available on %v", runtime.GOOS),
+		}
+		return w.notSupportedReason
+	}
+
+	diskPath, err := os.Getwd()
+	if err != nil {
+		diskPath = os.TempDir()
+	}
+	w.reader = newUsageReader(diskPath)
+	w.report = &usageReport{
+		MemoryLimitBytes: w.reader.memoryLimit(),
+		CPULimitCores:    w.reader.cpuLimit(),
+		DiskPath:         diskPath,
+	}
+
+	samplingCtx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(samplingInterval)
+		defer ticker.Stop()
+		for {
+			w.recordSample()
+			select {
+			case <-samplingCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *runnerResourceUsage) recordSample() {
+	sample, err := w.reader.sample(w.clock.Now())
+	if err != nil {
+		logrus.WithError(err).Warn("unable to sample runner resource usage")
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.report.Samples = append(w.report.Samples, sample)
+	if sample.RSSBytes > w.report.PeakRSSBytes {
+		w.report.PeakRSSBytes = sample.RSSBytes
+	}
+	if sample.CgroupMemoryBytes > w.report.PeakCgroupMemoryBytes {
+		w.report.PeakCgroupMemoryBytes = sample.CgroupMemoryBytes
+	}
+	if sample.DiskUsedBytes > w.report.PeakDiskUsedBytes {
+		w.report.PeakDiskUsedBytes = sample.DiskUsedBytes
+	}
+}
+
+// stopSampling is safe to call multiple times.
+func (w *runnerResourceUsage) stopSampling() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+	w.cancel = nil
+}
+
+func (w *runnerResourceUsage) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, nil, w.notSupportedReason
+	}
+	if w.report == nil {
+		return nil, nil, errNotSampled
+	}
+	w.stopSampling()
+	// one last sample so the report covers the whole run.
+	w.recordSample()
+	return nil, nil, nil
+}
+
+func (w *runnerResourceUsage) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, w.notSupportedReason
+}
+
+func (w *runnerResourceUsage) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if w.notSupportedReason != nil {
+		return nil, w.notSupportedReason
+	}
+	if w.report == nil {
+		return nil, errNotSampled
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return evaluateUsage(w.report), nil
+}
+
+func evaluateUsage(report *usageReport) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	if report.MemoryLimitBytes > 0 {
+		ret = append(ret, evaluateLimit(memoryTestName, "RunnerNearMemoryLimit", "rss-bytes", report.PeakRSSBytes, report.MemoryLimitBytes))
+	}
+
+	var diskTotal uint64
+	for _, sample := range report.Samples {
+		if sample.DiskTotalBytes > diskTotal {
+			diskTotal = sample.DiskTotalBytes
+		}
+	}
+	if diskTotal > 0 {
+		ret = append(ret, evaluateLimit(diskTestName, "RunnerNearDiskFull", "disk-used-bytes", report.PeakDiskUsedBytes, diskTotal))
+	}
+	return ret
+}
+
+var nearLimitTemplate = junitfailure.MustParseTemplate("runner-near-limit",
+	`openshift-tests runner peaked at {{printf "%.0f" .Threshold.Observed}} bytes, which is more than {{.Fields.limitPercent}}% of its limit of {{.Fields.limitBytes}} bytes.  The runner may be killed, interrupting the job.`)
+
+// evaluateLimit reports a warn severity failure when the peak gets close to the limit.  The failure budget policy
+// decides whether that fails the job.
+func evaluateLimit(testName, reason, thresholdName string, peak, limit uint64) *junitapi.JUnitTestCase {
+	threshold := limitRatio * float64(limit)
+	limitThreshold := junitapi.JUnitThreshold{
+		Name:     thresholdName,
+		Limit:    threshold,
+		Observed: float64(peak),
+		Unit:     "bytes",
+		Exceeded: float64(peak) >= threshold,
+	}
+	if !limitThreshold.Exceeded {
+		return &junitapi.JUnitTestCase{
+			Name: testName,
+			Details: &junitapi.JUnitTestCaseDetails{
+				Thresholds: []junitapi.JUnitThreshold{limitThreshold},
+			},
+		}
+	}
+
+	junit := junitfailure.NewFailure(nearLimitTemplate, reason).
+		Threshold(limitThreshold).
+		Field("limitPercent", limitRatio*100).
+		Field("limitBytes", limit).
+		TestCase(testName)
+	junit.Details.Severity = junitapi.SeverityWarn
+	return junit
+}
+
+func (w *runnerResourceUsage) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	if w.notSupportedReason != nil {
+		return w.notSupportedReason
+	}
+	if w.report == nil {
+		return errNotSampled
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	content, err := json.MarshalIndent(w.report, "", "    ")
+	if err != nil {
+		return err
+	}
+	filename, err := monitortestframework.StoragePath(storageDir, fmt.Sprintf("runner-resource-usage%s.json", timeSuffix))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
+
+// ArtifactSchema describes the report for the artifact index.  The report may be in a subdirectory, depending on the
+// storage layout.
+func (w *runnerResourceUsage) ArtifactSchema(relativePath string) string {
+	if strings.HasPrefix(path.Base(relativePath), "runner-resource-usage") {
+		return "runner-resource-usage/v1"
+	}
+	return ""
+}
+
+func (w *runnerResourceUsage) Cleanup(ctx context.Context) error {
+	w.stopSampling()
+	return w.notSupportedReason
+}
@@ -0,0 +1,221 @@
+package runnerresourceusage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userHZ is the kernel clock tick used for the cpu times in /proc/<pid>/stat.  It is 100 on every platform we run on.
+const userHZ = 100
+
+type usageSample struct {
+	Time         time.Time `json:"time"`
+	NumProcesses int       `json:"numProcesses"`
+	// RSSBytes is the resident memory of openshift-tests and every child process.
+	RSSBytes uint64 `json:"rssBytes"`
+	// CPUSeconds is the cumulative user and system cpu time of the processes alive at the time of the sample.
+	CPUSeconds float64 `json:"cpuSeconds"`
+	// CgroupMemoryBytes is the memory charged to the container, including page cache.
+	CgroupMemoryBytes uint64 `json:"cgroupMemoryBytes,omitempty"`
+	DiskUsedBytes     uint64 `json:"diskUsedBytes,omitempty"`
+	DiskTotalBytes    uint64 `json:"diskTotalBytes,omitempty"`
+}
+
+type usageReport struct {
+	// MemoryLimitBytes is zero when the container has no memory limit.
+	MemoryLimitBytes uint64 `json:"memoryLimitBytes,omitempty"`
+	// CPULimitCores is zero when the container has no cpu limit.
+	CPULimitCores float64 `json:"cpuLimitCores,omitempty"`
+	DiskPath      string  `json:"diskPath,omitempty"`
+
+	PeakRSSBytes          uint64 `json:"peakRSSBytes"`
+	PeakCgroupMemoryBytes uint64 `json:"peakCgroupMemoryBytes,omitempty"`
+	PeakDiskUsedBytes     uint64 `json:"peakDiskUsedBytes,omitempty"`
+
+	Samples []usageSample `json:"samples"`
+}
+
+// usageReader reads resource usage from procfs and cgroupfs.  The roots are configurable for testing.
+type usageReader struct {
+	procRoot   string
+	cgroupRoot string
+	diskPath   string
+	rootPID    int
+}
+
+func newUsageReader(diskPath string) *usageReader {
+	return &usageReader{
+		procRoot:   "/proc",
+		cgroupRoot: "/sys/fs/cgroup",
+		diskPath:   diskPath,
+		rootPID:    os.Getpid(),
+	}
+}
+
+func (r *usageReader) sample(now time.Time) (usageSample, error) {
+	ret := usageSample{Time: now}
+
+	processes, err := r.readProcesses()
+	if err != nil {
+		return ret, err
+	}
+	for _, pid := range descendantsOf(r.rootPID, processes) {
+		process := processes[pid]
+		ret.NumProcesses++
+		ret.RSSBytes += process.rssPages * uint64(os.Getpagesize())
+		ret.CPUSeconds += float64(process.cpuTicks) / userHZ
+	}
+
+	// cgroup v2, then v1
+	if current, ok := r.readCgroupUint("memory.current"); ok {
+		ret.CgroupMemoryBytes = current
+	} else if current, ok := r.readCgroupUint("memory/memory.usage_in_bytes"); ok {
+		ret.CgroupMemoryBytes = current
+	}
+
+	if len(r.diskPath) > 0 {
+		ret.DiskUsedBytes, ret.DiskTotalBytes = diskUsage(r.diskPath)
+	}
+	return ret, nil
+}
+
+// memoryLimit returns the memory limit of the container, or zero if there is none.
+func (r *usageReader) memoryLimit() uint64 {
+	if limit, ok := r.readCgroupUint("memory.max"); ok {
+		return limit
+	}
+	// cgroup v1 reports no limit as a very large number.
+	if limit, ok := r.readCgroupUint("memory/memory.limit_in_bytes"); ok && limit < 1<<60 {
+		return limit
+	}
+	return 0
+}
+
+// cpuLimit returns the cpu limit of the container in cores, or zero if there is none.
+func (r *usageReader) cpuLimit() float64 {
+	if content, err := os.ReadFile(filepath.Join(r.cgroupRoot, "cpu.max")); err == nil {
+		fields := strings.Fields(string(content))
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, quotaErr := strconv.ParseFloat(fields[0], 64)
+			period, periodErr := strconv.ParseFloat(fields[1], 64)
+			if quotaErr == nil && periodErr == nil && period > 0 {
+				return quota / period
+			}
+		}
+		return 0
+	}
+	quota, quotaErr := os.ReadFile(filepath.Join(r.cgroupRoot, "cpu", "cpu.cfs_quota_us"))
+	period, periodErr := os.ReadFile(filepath.Join(r.cgroupRoot, "cpu", "cpu.cfs_period_us"))
+	if quotaErr != nil || periodErr != nil {
+		return 0
+	}
+	quotaValue, quotaErr := strconv.ParseFloat(strings.TrimSpace(string(quota)), 64)
+	periodValue, periodErr := strconv.ParseFloat(strings.TrimSpace(string(period)), 64)
+	if quotaErr != nil || periodErr != nil || quotaValue <= 0 || periodValue <= 0 {
+		return 0
+	}
+	return quotaValue / periodValue
+}
+
+// readCgroupUint returns false if the file is missing or holds "max".
+func (r *usageReader) readCgroupUint(name string) (uint64, bool) {
+	content, err := os.ReadFile(filepath.Join(r.cgroupRoot, name))
+	if err != nil {
+		return 0, false
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+type processStat struct {
+	ppid     int
+	cpuTicks uint64
+	rssPages uint64
+}
+
+func (r *usageReader) readProcesses() (map[int]processStat, error) {
+	entries, err := os.ReadDir(r.procRoot)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[int]processStat{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(r.procRoot, entry.Name(), "stat"))
+		if err != nil {
+			// processes exit all the time
+			continue
+		}
+		stat, err := parseProcessStat(string(content))
+		if err != nil {
+			continue
+		}
+		ret[pid] = stat
+	}
+	return ret, nil
+}
+
+// parseProcessStat parses /proc/<pid>/stat.  The command may contain spaces and parentheses, so fields are
+// counted from the last closing parenthesis.
+func parseProcessStat(content string) (processStat, error) {
+	end := strings.LastIndex(content, ")")
+	if end < 0 {
+		return processStat{}, fmt.Errorf("malformed stat: %q", content)
+	}
+	// fields[0] is the state, which is field 3 in proc(5)
+	fields := strings.Fields(content[end+1:])
+	if len(fields) < 22 {
+		return processStat{}, fmt.Errorf("malformed stat: %q", content)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return processStat{}, err
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return processStat{}, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return processStat{}, err
+	}
+	rss, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return processStat{}, err
+	}
+	if rss < 0 {
+		rss = 0
+	}
+	return processStat{ppid: ppid, cpuTicks: utime + stime, rssPages: uint64(rss)}, nil
+}
+
+// descendantsOf returns root and every process below it.
+func descendantsOf(root int, processes map[int]processStat) []int {
+	children := map[int][]int{}
+	for pid, process := range processes {
+		children[process.ppid] = append(children[process.ppid], pid)
+	}
+
+	ret := []int{}
+	if _, ok := processes[root]; !ok {
+		return ret
+	}
+	queue := []int{root}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		ret = append(ret, pid)
+		queue = append(queue, children[pid]...)
+	}
+	return ret
+}
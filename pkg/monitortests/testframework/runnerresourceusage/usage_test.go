@@ -0,0 +1,107 @@
+package runnerresourceusage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// stat builds a /proc/<pid>/stat line with the fields we read filled in.
+func stat(pid, ppid string, command, utime, stime, rss string) string {
+	return pid + " (" + command + ") S " + ppid + " 0 0 0 -1 0 0 0 0 0 " + utime + " " + stime + " 0 0 20 0 1 0 0 0 " + rss + " 0\n"
+}
+
+func TestUsageReaderSample(t *testing.T) {
+	procRoot := t.TempDir()
+	writeFile(t, filepath.Join(procRoot, "10", "stat"), stat("10", "1", "openshift-tests", "100", "50", "100"))
+	writeFile(t, filepath.Join(procRoot, "11", "stat"), stat("11", "10", "openshift tests (child)", "200", "0", "50"))
+	writeFile(t, filepath.Join(procRoot, "12", "stat"), stat("12", "11", "grandchild", "0", "0", "10"))
+	writeFile(t, filepath.Join(procRoot, "20", "stat"), stat("20", "1", "unrelated", "1000", "0", "1000"))
+	writeFile(t, filepath.Join(procRoot, "self", "stat"), "ignored")
+
+	cgroupRoot := t.TempDir()
+	writeFile(t, filepath.Join(cgroupRoot, "memory.max"), "1073741824\n")
+	writeFile(t, filepath.Join(cgroupRoot, "memory.current"), "524288000\n")
+	writeFile(t, filepath.Join(cgroupRoot, "cpu.max"), "200000 100000\n")
+
+	reader := &usageReader{procRoot: procRoot, cgroupRoot: cgroupRoot, rootPID: 10}
+	sample, err := reader.sample(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sample.NumProcesses != 3 {
+		t.Errorf("expected 3 processes, got %d", sample.NumProcesses)
+	}
+	if expected := uint64(160 * os.Getpagesize()); sample.RSSBytes != expected {
+		t.Errorf("expected rss %d, got %d", expected, sample.RSSBytes)
+	}
+	if sample.CPUSeconds != 3.5 {
+		t.Errorf("expected 3.5 cpu seconds, got %v", sample.CPUSeconds)
+	}
+	if sample.CgroupMemoryBytes != 524288000 {
+		t.Errorf("unexpected cgroup memory %d", sample.CgroupMemoryBytes)
+	}
+	if limit := reader.memoryLimit(); limit != 1073741824 {
+		t.Errorf("unexpected memory limit %d", limit)
+	}
+	if limit := reader.cpuLimit(); limit != 2 {
+		t.Errorf("unexpected cpu limit %v", limit)
+	}
+}
+
+func TestUsageReaderCgroupV1Unlimited(t *testing.T) {
+	cgroupRoot := t.TempDir()
+	writeFile(t, filepath.Join(cgroupRoot, "memory", "memory.limit_in_bytes"), "9223372036854771712\n")
+	writeFile(t, filepath.Join(cgroupRoot, "cpu", "cpu.cfs_quota_us"), "-1\n")
+	writeFile(t, filepath.Join(cgroupRoot, "cpu", "cpu.cfs_period_us"), "100000\n")
+
+	reader := &usageReader{cgroupRoot: cgroupRoot}
+	if limit := reader.memoryLimit(); limit != 0 {
+		t.Errorf("expected no memory limit, got %d", limit)
+	}
+	if limit := reader.cpuLimit(); limit != 0 {
+		t.Errorf("expected no cpu limit, got %v", limit)
+	}
+}
+
+func TestEvaluateUsage(t *testing.T) {
+	report := &usageReport{
+		MemoryLimitBytes:  1000,
+		PeakRSSBytes:      950,
+		PeakDiskUsedBytes: 10,
+		Samples: []usageSample{
+			{DiskTotalBytes: 100},
+		},
+	}
+	junits := evaluateUsage(report)
+	if len(junits) != 2 {
+		t.Fatalf("expected 2 junits, got %d", len(junits))
+	}
+	for _, junit := range junits {
+		switch junit.Name {
+		case memoryTestName:
+			if junit.FailureOutput == nil || junit.Details.Severity != junitapi.SeverityWarn {
+				t.Errorf("expected a warning for memory, got %#v", junit)
+			}
+		case diskTestName:
+			if junit.FailureOutput != nil {
+				t.Errorf("expected disk to pass, got %v", junit.FailureOutput.Output)
+			}
+		default:
+			t.Errorf("unexpected junit %q", junit.Name)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 
+	configv1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/openshift/origin/pkg/monitortestframework"
 
@@ -14,6 +16,7 @@ import (
 )
 
 type operatorWatcher struct {
+	configClient configclient.Interface
 }
 
 func NewOperatorWatcher() monitortestframework.MonitorTest {
@@ -26,6 +29,7 @@ func (w *operatorWatcher) StartCollection(ctx context.Context, adminRESTConfig *
 		return err
 	}
 
+	w.configClient = configClient
 	startClusterOperatorMonitoring(ctx, recorder, configClient)
 
 	return nil
@@ -36,6 +40,34 @@ func (w *operatorWatcher) CollectData(ctx context.Context, storageDir string, be
 	return nil, nil, nil
 }
 
+// BackfillData reconstructs the condition changes of the operators before collection began from their status.  Only
+// the last transition of every condition is kept by an operator, earlier changes in the window are lost.
+func (w *operatorWatcher) BackfillData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.configClient == nil {
+		return nil, nil, &monitortestframework.NotSupportedError{Reason: "collection did not start"}
+	}
+	clusterOperators, err := w.configClient.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return backfillConditionChanges(clusterOperators.Items, beginning, end), nil, nil
+}
+
+func backfillConditionChanges(clusterOperators []configv1.ClusterOperator, beginning, end time.Time) monitorapi.Intervals {
+	intervals := monitorapi.Intervals{}
+	for _, co := range clusterOperators {
+		for i := range co.Status.Conditions {
+			c := &co.Status.Conditions[i]
+			transitionTime := c.LastTransitionTime.Time
+			if transitionTime.Before(beginning) || !transitionTime.Before(end) {
+				continue
+			}
+			intervals = append(intervals, conditionChangedInterval(co.Name, c, transitionTime))
+		}
+	}
+	return intervals
+}
+
 func (*operatorWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
 	constructedIntervals := monitorapi.Intervals{}
 
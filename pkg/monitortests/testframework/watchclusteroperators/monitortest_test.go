@@ -0,0 +1,43 @@
+package watchclusteroperators
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func Test_backfillConditionChanges(t *testing.T) {
+	beginning := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := beginning.Add(time.Hour)
+	clusterOperators := []configv1.ClusterOperator{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver"},
+			Status: configv1.ClusterOperatorStatus{
+				Conditions: []configv1.ClusterOperatorStatusCondition{
+					// before the run.
+					{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue, LastTransitionTime: metav1.NewTime(beginning.Add(-time.Minute))},
+					{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Reason: "NodeInstallerDegraded", Message: "1 nodes are failing", LastTransitionTime: metav1.NewTime(beginning.Add(10 * time.Minute))},
+					// after collection began, the watcher saw it.
+					{Type: configv1.OperatorProgressing, Status: configv1.ConditionFalse, LastTransitionTime: metav1.NewTime(end)},
+				},
+			},
+		},
+	}
+
+	intervals := backfillConditionChanges(clusterOperators, beginning, end)
+	if len(intervals) != 1 {
+		t.Fatalf("expected only the change during the window, got %v", intervals)
+	}
+	interval := intervals[0]
+	if !interval.From.Equal(beginning.Add(10*time.Minute)) || interval.Level != monitorapi.Error || interval.Source != monitorapi.SourceClusterOperatorMonitor {
+		t.Errorf("unexpected interval %v", interval)
+	}
+	condition := monitorapi.GetOperatorConditionStatus(interval)
+	if condition.Type != configv1.OperatorDegraded || condition.Status != configv1.ConditionTrue || condition.Reason != "NodeInstallerDegraded" {
+		t.Errorf("expected the interval to be read as the condition change, got %#v", condition)
+	}
+}
@@ -42,35 +42,7 @@ func startClusterOperatorMonitoring(ctx context.Context, m monitorapi.RecorderWr
 				// If we don't have a previous state, then we should always mark the starting state with an event.
 				// We recently had a PR that caused the kube-apiserver operator be permanently degraded and it didn't show up.
 				if previousCondition == nil || c.Status != previousCondition.Status {
-
-					msg := monitorapi.NewMessage().
-						WithAnnotations(
-							map[monitorapi.AnnotationKey]string{
-								monitorapi.AnnotationCondition: string(c.Type),
-								monitorapi.AnnotationStatus:    string(c.Status),
-							}).
-						HumanMessagef("%s", c.Message)
-
-					if len(c.Reason) > 0 {
-						msg = msg.Reason(monitorapi.IntervalReason(c.Reason))
-					}
-
-					level := monitorapi.Warning
-					if c.Type == configv1.OperatorDegraded && c.Status == configv1.ConditionTrue {
-						level = monitorapi.Error
-					}
-					if c.Type == configv1.OperatorAvailable && c.Status == configv1.ConditionFalse {
-						level = monitorapi.Error
-					}
-					if c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionTrue {
-						level = monitorapi.Warning
-					}
-					if c.Type == configv1.ClusterStatusConditionType("Failing") && c.Status == configv1.ConditionTrue {
-						level = monitorapi.Error
-					}
-					intervals = append(intervals, monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, level).
-						Locator(monitorapi.NewLocator().ClusterOperator(co.Name)).
-						Message(msg).Build(intervalTime, intervalTime))
+					intervals = append(intervals, conditionChangedInterval(co.Name, c, intervalTime))
 				}
 			}
 			if changes := findOperatorVersionChange(oldCO.Status.Versions, co.Status.Versions); len(changes) > 0 {
@@ -252,6 +224,38 @@ func startClusterOperatorMonitoring(ctx context.Context, m monitorapi.RecorderWr
 	go cvInformer.Run(ctx.Done())
 }
 
+// conditionChangedInterval is the interval of the condition of the operator changing at the time.
+func conditionChangedInterval(operatorName string, c *configv1.ClusterOperatorStatusCondition, intervalTime time.Time) monitorapi.Interval {
+	msg := monitorapi.NewMessage().
+		WithAnnotations(
+			map[monitorapi.AnnotationKey]string{
+				monitorapi.AnnotationCondition: string(c.Type),
+				monitorapi.AnnotationStatus:    string(c.Status),
+			}).
+		HumanMessagef("%s", c.Message)
+
+	if len(c.Reason) > 0 {
+		msg = msg.Reason(monitorapi.IntervalReason(c.Reason))
+	}
+
+	level := monitorapi.Warning
+	if c.Type == configv1.OperatorDegraded && c.Status == configv1.ConditionTrue {
+		level = monitorapi.Error
+	}
+	if c.Type == configv1.OperatorAvailable && c.Status == configv1.ConditionFalse {
+		level = monitorapi.Error
+	}
+	if c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionTrue {
+		level = monitorapi.Warning
+	}
+	if c.Type == configv1.ClusterStatusConditionType("Failing") && c.Status == configv1.ConditionTrue {
+		level = monitorapi.Error
+	}
+	return monitorapi.NewInterval(monitorapi.SourceClusterOperatorMonitor, level).
+		Locator(monitorapi.NewLocator().ClusterOperator(operatorName)).
+		Message(msg).Build(intervalTime, intervalTime)
+}
+
 func versionOrImage(h configv1.UpdateHistory) string {
 	if len(h.Version) == 0 {
 		return h.Image
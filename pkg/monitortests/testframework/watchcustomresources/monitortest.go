@@ -0,0 +1,138 @@
+package watchcustomresources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const servedTestName = "[sig-arch] tracked resources are served by the cluster"
+
+type customResourceWatcher struct {
+	trackedResources []monitortestframework.TrackedResource
+
+	// unserved are the tracked resources the cluster does not serve, because their CRD is not installed.
+	unserved []string
+}
+
+// NewCustomResourceWatcher records the resources listed in info.TrackedResources as they change, alongside the fixed
+// set of resources the monitor records, so invariants of layered products can read their operators' custom resources.
+func NewCustomResourceWatcher(info monitortestframework.MonitorTestInitializationInfo) monitortestframework.MonitorTest {
+	ret := &customResourceWatcher{}
+	if info.TrackedResources != nil {
+		ret.trackedResources = info.TrackedResources.Resources
+	}
+	return ret
+}
+
+func (w *customResourceWatcher) Describe() monitortestframework.MonitorTestDescription {
+	return monitortestframework.MonitorTestDescription{
+		Stages: []monitortestframework.Stage{
+			monitortestframework.StartCollectionStage,
+			monitortestframework.EvaluateTestsStage,
+		},
+		TestNames: []string{servedTestName},
+	}
+}
+
+func (w *customResourceWatcher) StartCollection(ctx context.Context, adminRESTConfig *rest.Config, recorder monitorapi.RecorderWriter) error {
+	if len(w.trackedResources) == 0 {
+		return &monitortestframework.NotSupportedError{Reason: "no additional resources are tracked"}
+	}
+	dynamicClient, err := dynamic.NewForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(adminRESTConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, trackedResource := range w.trackedResources {
+		served, err := isServed(discoveryClient, trackedResource)
+		if err != nil {
+			return err
+		}
+		if !served {
+			logrus.Warnf("Not tracking %s, the cluster does not serve it", trackedResource.GroupVersionResource())
+			w.unserved = append(w.unserved, trackedResource.GroupVersionResource().String())
+			continue
+		}
+
+		informer := dynamicinformer.NewFilteredDynamicInformer(dynamicClient, trackedResource.GroupVersionResource(), trackedResource.Namespace, 0, cache.Indexers{}, nil)
+		if _, err := informer.Informer().AddEventHandler(newResourceRecorder(trackedResource, recorder)); err != nil {
+			return err
+		}
+		go informer.Informer().Run(ctx.Done())
+	}
+	return nil
+}
+
+func isServed(discoveryClient discovery.DiscoveryInterface, trackedResource monitortestframework.TrackedResource) (bool, error) {
+	gvr := trackedResource.GroupVersionResource()
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to discover %s: %w", gvr.GroupVersion(), err)
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == gvr.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (w *customResourceWatcher) CollectData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	// because we are sharing a recorder that we're streaming into, we don't need to have a separate data collection step.
+	return nil, nil, nil
+}
+
+func (*customResourceWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
+	return nil, nil
+}
+
+// EvaluateTestsFromConstructedIntervals flakes when tracked resources were not served, which is expected when the
+// layered product tracking them is not installed, and otherwise means the tracked resources are wrong.
+func (w *customResourceWatcher) EvaluateTestsFromConstructedIntervals(ctx context.Context, finalIntervals monitorapi.Intervals) ([]*junitapi.JUnitTestCase, error) {
+	if len(w.trackedResources) == 0 {
+		return nil, nil
+	}
+	if len(w.unserved) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: servedTestName}}, nil
+	}
+	output := fmt.Sprintf("the cluster does not serve these tracked resources, they were not recorded:\n%s", strings.Join(w.unserved, "\n"))
+	return []*junitapi.JUnitTestCase{
+		{
+			Name:          servedTestName,
+			FailureOutput: &junitapi.FailureOutput{Output: output},
+			SystemOut:     output,
+		},
+		{Name: servedTestName},
+	}, nil
+}
+
+func (*customResourceWatcher) WriteContentToStorage(ctx context.Context, storageDir, timeSuffix string, finalIntervals monitorapi.Intervals, finalResourceState monitorapi.ResourcesMap) error {
+	// the tracked-resources-serializer writes the recorded resources.
+	return nil
+}
+
+func (*customResourceWatcher) Cleanup(ctx context.Context) error {
+	// the informers stop with the context collection was started with.
+	return nil
+}
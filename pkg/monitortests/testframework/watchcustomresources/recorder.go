@@ -0,0 +1,64 @@
+package watchcustomresources
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+// resourceRecorder records the pruned resources its informer sees.
+type resourceRecorder struct {
+	resourceType string
+	pruneFields  [][]string
+	recorder     monitorapi.RecorderWriter
+}
+
+func newResourceRecorder(trackedResource monitortestframework.TrackedResource, recorder monitorapi.RecorderWriter) *resourceRecorder {
+	pruneFields := [][]string{{"metadata", "managedFields"}}
+	for _, field := range trackedResource.PruneFields {
+		pruneFields = append(pruneFields, strings.Split(field, "."))
+	}
+	return &resourceRecorder{
+		resourceType: trackedResource.ResourceType(),
+		pruneFields:  pruneFields,
+		recorder:     recorder,
+	}
+}
+
+var _ cache.ResourceEventHandler = &resourceRecorder{}
+
+func (r *resourceRecorder) OnAdd(obj interface{}, isInInitialList bool) {
+	r.record(obj)
+}
+
+func (r *resourceRecorder) OnUpdate(oldObj, newObj interface{}) {
+	r.record(newObj)
+}
+
+// OnDelete records the final state, deletion is not tracked by the recorded resources.
+func (r *resourceRecorder) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	r.record(obj)
+}
+
+func (r *resourceRecorder) record(obj interface{}) {
+	resource, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	r.recorder.RecordResource(r.resourceType, r.prune(resource))
+}
+
+func (r *resourceRecorder) prune(resource *unstructured.Unstructured) *unstructured.Unstructured {
+	pruned := resource.DeepCopy()
+	for _, field := range r.pruneFields {
+		unstructured.RemoveNestedField(pruned.Object, field...)
+	}
+	return pruned
+}
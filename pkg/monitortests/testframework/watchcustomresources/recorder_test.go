@@ -0,0 +1,69 @@
+package watchcustomresources
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+type resourceTracker struct {
+	monitorapi.RecorderWriter
+	resourceTypes []string
+	resources     []*unstructured.Unstructured
+}
+
+func (r *resourceTracker) RecordResource(resourceType string, obj runtime.Object) {
+	r.resourceTypes = append(r.resourceTypes, resourceType)
+	r.resources = append(r.resources, obj.(*unstructured.Unstructured))
+}
+
+func TestResourceRecorder(t *testing.T) {
+	subscription := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "operators.coreos.com/v1alpha1",
+		"kind":       "Subscription",
+		"metadata": map[string]interface{}{
+			"name":          "openshift-gitops-operator",
+			"namespace":     "openshift-operators",
+			"managedFields": []interface{}{map[string]interface{}{"manager": "olm"}},
+		},
+		"status": map[string]interface{}{
+			"state":         "AtLatestKnown",
+			"catalogHealth": []interface{}{map[string]interface{}{"healthy": true}},
+		},
+	}}
+
+	tracker := &resourceTracker{}
+	recorder := newResourceRecorder(monitortestframework.TrackedResource{
+		Group:       "operators.coreos.com",
+		Version:     "v1alpha1",
+		Resource:    "subscriptions",
+		PruneFields: []string{"status.catalogHealth"},
+	}, tracker)
+	recorder.OnAdd(subscription, true)
+	recorder.OnDelete(cache.DeletedFinalStateUnknown{Key: "openshift-operators/openshift-gitops-operator", Obj: subscription})
+
+	if len(tracker.resources) != 2 {
+		t.Fatalf("expected the add and the delete to be recorded, got %d", len(tracker.resources))
+	}
+	if tracker.resourceTypes[0] != "subscriptions.operators.coreos.com" {
+		t.Errorf("unexpected resource type %q", tracker.resourceTypes[0])
+	}
+	recorded := tracker.resources[0]
+	if _, found, _ := unstructured.NestedFieldNoCopy(recorded.Object, "metadata", "managedFields"); found {
+		t.Error("expected managed fields to always be pruned")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(recorded.Object, "status", "catalogHealth"); found {
+		t.Error("expected the configured field to be pruned")
+	}
+	if state, _, _ := unstructured.NestedString(recorded.Object, "status", "state"); state != "AtLatestKnown" {
+		t.Errorf("expected the rest of the status to be kept, got %v", recorded.Object["status"])
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(subscription.Object, "status", "catalogHealth"); !found {
+		t.Error("expected the informer's copy to be left alone")
+	}
+}
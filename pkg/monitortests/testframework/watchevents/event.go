@@ -16,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -230,6 +231,35 @@ func recordAddOrUpdateEvent(
 	recorder.AddIntervals(interval)
 }
 
+// intervalCollector keeps the intervals added to it, and drops resources.
+type intervalCollector struct {
+	monitorapi.RecorderWriter
+	intervals monitorapi.Intervals
+}
+
+func (c *intervalCollector) AddIntervals(intervals ...monitorapi.Interval) {
+	c.intervals = append(c.intervals, intervals...)
+}
+
+func (c *intervalCollector) RecordResource(resourceType string, obj runtime.Object) {
+}
+
+// backfillEvents returns the intervals the events last seen between beginning and end would have been recorded as.
+func backfillEvents(ctx context.Context, topology v1.TopologyMode, client kubernetes.Interface, events []corev1.Event, beginning, end time.Time) monitorapi.Intervals {
+	collector := &intervalCollector{}
+	for i := range events {
+		recordAddOrUpdateEvent(ctx, collector, topology, client, beginning, &events[i])
+	}
+	// events seen again after collection began were recorded by the watch.
+	intervals := monitorapi.Intervals{}
+	for _, interval := range collector.intervals {
+		if interval.From.Before(end) {
+			intervals = append(intervals, interval)
+		}
+	}
+	return intervals
+}
+
 func eventForContainer(fieldPath string) (string, bool) {
 	if !strings.HasSuffix(fieldPath, "}") {
 		return "", false
@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func Test_recordAddOrUpdateEvent(t *testing.T) {
@@ -154,3 +155,31 @@ func Test_recordAddOrUpdateEvent(t *testing.T) {
 		})
 	}
 }
+
+func Test_backfillEvents(t *testing.T) {
+	beginning := time.Now().Add(-2 * time.Hour)
+	end := beginning.Add(time.Hour)
+	event := func(name string, lastTimestamp time.Time) corev1.Event {
+		return corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "openshift-etcd", Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "openshift-etcd", Name: "etcd-master-0"},
+			Reason:         "Unhealthy",
+			Message:        name,
+			Type:           corev1.EventTypeWarning,
+			Count:          1,
+			LastTimestamp:  metav1.NewTime(lastTimestamp),
+		}
+	}
+	events := []corev1.Event{
+		event("before the run", beginning.Add(-time.Minute)),
+		event("before collection", beginning.Add(time.Minute)),
+		event("seen by the watch", end.Add(time.Minute)),
+	}
+
+	intervals := backfillEvents(context.TODO(), "", fake.NewSimpleClientset(), events, beginning, end)
+	if assert.Len(t, intervals, 1) {
+		assert.Equal(t, "before collection", intervals[0].Message.HumanMessage)
+		assert.Equal(t, monitorapi.SourceKubeEvent, intervals[0].Source)
+		assert.Equal(t, monitorapi.Warning, intervals[0].Level)
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/monitortestlibrary/pathologicaleventlibrary"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -13,6 +17,8 @@ import (
 )
 
 type eventWatcher struct {
+	adminRESTConfig *rest.Config
+	kubeClient      kubernetes.Interface
 }
 
 func NewEventWatcher() monitortestframework.MonitorTest {
@@ -25,6 +31,8 @@ func (w *eventWatcher) StartCollection(ctx context.Context, adminRESTConfig *res
 		return err
 	}
 
+	w.adminRESTConfig = adminRESTConfig
+	w.kubeClient = kubeClient
 	startEventMonitoring(ctx, recorder, adminRESTConfig, kubeClient)
 
 	return nil
@@ -35,6 +43,23 @@ func (w *eventWatcher) CollectData(ctx context.Context, storageDir string, begin
 	return nil, nil, nil
 }
 
+// BackfillData reconstructs the intervals of the events last seen before collection began.  Events are kept for a few
+// hours, and repeats of an event only keep the last time it was seen.
+func (w *eventWatcher) BackfillData(ctx context.Context, storageDir string, beginning, end time.Time) (monitorapi.Intervals, []*junitapi.JUnitTestCase, error) {
+	if w.kubeClient == nil {
+		return nil, nil, &monitortestframework.NotSupportedError{Reason: "collection did not start"}
+	}
+	_, topology, err := pathologicaleventlibrary.GetClusterInfraInfo(w.adminRESTConfig)
+	if err != nil {
+		logrus.WithError(err).Error("could not fetch cluster infra info")
+	}
+	events, err := w.kubeClient.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return backfillEvents(ctx, topology, w.kubeClient, events.Items, beginning, end), nil, nil
+}
+
 func (*eventWatcher) ConstructComputedIntervals(ctx context.Context, startingIntervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error) {
 	constructedIntervals := monitorapi.Intervals{}
 
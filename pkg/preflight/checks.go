@@ -0,0 +1,157 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"golang.org/x/mod/semver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// DefaultChecks are the checks run before a suite or the monitor starts.  Only reachability is required, a cluster
+// with a degraded operator or an unexpected version can still be worth testing, but the failures of the tests are
+// easier to explain with the check next to them.
+func DefaultChecks(testVersion string) []Check {
+	return []Check{
+		ClusterReachable(),
+		VersionSkew(testVersion, 1),
+		NoDegradedOperators(),
+	}
+}
+
+// ClusterReachable requires the apiserver to answer.
+func ClusterReachable() Check {
+	return Check{
+		Name:     "cluster is reachable",
+		Required: true,
+		Run: func(ctx context.Context, clients *Clients) error {
+			serverVersion, err := clients.Kube.Discovery().ServerVersion()
+			if err != nil {
+				return fmt.Errorf("the apiserver at %s did not answer, check that the kubeconfig points at a running cluster and that this host can connect to it: %w", clients.Host, err)
+			}
+			if len(serverVersion.GitVersion) == 0 {
+				return fmt.Errorf("the apiserver at %s did not report its version, check that the kubeconfig points at a kube-apiserver", clients.Host)
+			}
+			return nil
+		},
+	}
+}
+
+// VersionSkew expects the version of the cluster to be within maxMinorSkew minor versions of testVersion, the version
+// of the tests.  Tests from a far older or newer release test behavior the cluster does not have.
+func VersionSkew(testVersion string, maxMinorSkew int) Check {
+	return Check{
+		Name: "cluster version is supported by the tests",
+		Run: func(ctx context.Context, clients *Clients) error {
+			testMajorMinor := majorMinorVersion(testVersion)
+			if !semver.IsValid(testMajorMinor) {
+				return &SkipError{Reason: fmt.Sprintf("the version of the tests, %q, is not a release version", testVersion)}
+			}
+			clusterVersion, err := clients.Config.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return &SkipError{Reason: "the cluster has no clusterversion"}
+			}
+			if err != nil {
+				return err
+			}
+			clusterMajorMinor := majorMinorVersion(clusterVersion.Status.Desired.Version)
+			if !semver.IsValid(clusterMajorMinor) {
+				return &SkipError{Reason: fmt.Sprintf("the version of the cluster, %q, is not a release version", clusterVersion.Status.Desired.Version)}
+			}
+
+			if semver.Major(testMajorMinor) != semver.Major(clusterMajorMinor) {
+				return fmt.Errorf("the tests are version %s and the cluster is version %s, use the tests of the release of the cluster", testVersion, clusterVersion.Status.Desired.Version)
+			}
+			skew := minorVersion(testMajorMinor) - minorVersion(clusterMajorMinor)
+			if skew < 0 {
+				skew = -skew
+			}
+			if skew > maxMinorSkew {
+				return fmt.Errorf("the tests are version %s and the cluster is version %s, more than %d minor versions apart, use the tests of the release of the cluster", testVersion, clusterVersion.Status.Desired.Version, maxMinorSkew)
+			}
+			return nil
+		},
+	}
+}
+
+// RequiredCapabilities requires the capabilities to be enabled in the cluster.  Suites that only make sense with a
+// capability, like the tests of builds, should require it rather than have every test fail.
+func RequiredCapabilities(capabilities ...configv1.ClusterVersionCapability) Check {
+	return Check{
+		Name:     "required capabilities are enabled",
+		Required: true,
+		Run: func(ctx context.Context, clients *Clients) error {
+			clusterVersion, err := clients.Config.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				return &SkipError{Reason: "the cluster has no clusterversion"}
+			}
+			if err != nil {
+				return err
+			}
+			enabled := sets.NewString()
+			for _, capability := range clusterVersion.Status.Capabilities.EnabledCapabilities {
+				enabled.Insert(string(capability))
+			}
+			missing := []string{}
+			for _, capability := range capabilities {
+				if !enabled.Has(string(capability)) {
+					missing = append(missing, string(capability))
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("the capabilities %s are not enabled, enable them in the clusterversion or run a suite that does not need them", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// NoDegradedOperators expects every cluster operator to be available and not degraded.
+func NoDegradedOperators() Check {
+	return Check{
+		Name: "cluster operators are not degraded",
+		Run: func(ctx context.Context, clients *Clients) error {
+			clusterOperators, err := clients.Config.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+			if apierrors.IsNotFound(err) {
+				return &SkipError{Reason: "the cluster does not serve clusteroperators"}
+			}
+			if err != nil {
+				return err
+			}
+			problems := []string{}
+			for _, clusterOperator := range clusterOperators.Items {
+				for _, condition := range clusterOperator.Status.Conditions {
+					switch {
+					case condition.Type == configv1.OperatorDegraded && condition.Status == configv1.ConditionTrue,
+						condition.Type == configv1.OperatorAvailable && condition.Status == configv1.ConditionFalse:
+						problems = append(problems, fmt.Sprintf("%s is %s=%s: %s: %s", clusterOperator.Name, condition.Type, condition.Status, condition.Reason, condition.Message))
+					}
+				}
+			}
+			if len(problems) > 0 {
+				sort.Strings(problems)
+				return fmt.Errorf("tests of the components of these operators are likely to fail, see the logs of the operators:\n%s", strings.Join(problems, "\n"))
+			}
+			return nil
+		},
+	}
+}
+
+func majorMinorVersion(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	return semver.MajorMinor(version)
+}
+
+// minorVersion returns the minor version of a valid vX.Y.
+func minorVersion(majorMinor string) int {
+	minor, _ := strconv.Atoi(strings.TrimPrefix(majorMinor, semver.Major(majorMinor)+"."))
+	return minor
+}
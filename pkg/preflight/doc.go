@@ -0,0 +1,4 @@
+// Package preflight checks that a cluster is fit to run tests against before a suite or the monitor starts, so a run
+// against an unreachable or broken cluster fails at once with a message saying what is wrong, rather than as
+// hundreds of test timeouts.
+package preflight
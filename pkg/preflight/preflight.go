@@ -0,0 +1,250 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/origin/pkg/test"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// DefaultCheckTimeout is how long a single check may take.  A cluster that cannot answer in that long is not fit to
+// test either.
+const DefaultCheckTimeout = time.Minute
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// Passed checks found nothing wrong.
+	Passed Status = "Passed"
+	// Failed checks found something wrong.  Only failures of required checks stop the run.
+	Failed Status = "Failed"
+	// Skipped checks do not apply to the cluster, or were not run because a required check failed before them.
+	Skipped Status = "Skipped"
+)
+
+// Clients are the clients of the cluster the checks run against.
+type Clients struct {
+	// Host is the apiserver the clients talk to, for messages.
+	Host   string
+	Kube   kubernetes.Interface
+	Config configclient.Interface
+}
+
+// NewClients returns the clients for the cluster of restConfig.  The clients do not retry, a check that needs
+// more than one attempt should say so.
+func NewClients(restConfig *rest.Config) (*Clients, error) {
+	restConfig = rest.CopyConfig(restConfig)
+	restConfig.Timeout = DefaultCheckTimeout
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	configClient, err := configclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Clients{
+		Host:   restConfig.Host,
+		Kube:   kubeClient,
+		Config: configClient,
+	}, nil
+}
+
+// Check is a single precondition of the run.
+type Check struct {
+	// Name says what the check expects of the cluster, for instance "cluster is reachable".
+	Name string
+	// Required checks stop the run when they fail, and the checks after them are skipped.  Failures of other checks
+	// are reported as flakes, to explain failures of the tests that follow without failing the run on their own.
+	Required bool
+	// Run returns nil when the cluster passes the check, and a SkipError when the check does not apply to it.  Other
+	// errors should say how to fix the cluster, they are what the person looking at the failed job reads.
+	Run func(ctx context.Context, clients *Clients) error
+}
+
+// SkipError is returned by a check that does not apply to the cluster.
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("skipped: %s", e.Reason)
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name            string  `json:"name"`
+	Required        bool    `json:"required"`
+	Status          Status  `json:"status"`
+	Message         string  `json:"message,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// Report is the outcome of the checks of a run, in the order they ran.
+type Report struct {
+	Host    string    `json:"host"`
+	Start   time.Time `json:"start"`
+	Results []Result  `json:"results"`
+}
+
+// Run runs the checks in order.  Every check gets DefaultCheckTimeout.  Once a required check fails the remaining
+// checks are skipped, they would only fail in less obvious ways.
+func Run(ctx context.Context, clients *Clients, checks ...Check) *Report {
+	report := &Report{
+		Host:  clients.Host,
+		Start: time.Now(),
+	}
+	var requiredFailure string
+	for _, check := range checks {
+		result := Result{
+			Name:     check.Name,
+			Required: check.Required,
+		}
+		if len(requiredFailure) > 0 {
+			result.Status = Skipped
+			result.Message = fmt.Sprintf("not run because %q failed", requiredFailure)
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		start := time.Now()
+		err := runWithTimeout(ctx, clients, check)
+		result.DurationSeconds = time.Since(start).Seconds()
+		var skipErr *SkipError
+		switch {
+		case err == nil:
+			result.Status = Passed
+		case errors.As(err, &skipErr):
+			result.Status = Skipped
+			result.Message = skipErr.Reason
+		default:
+			result.Status = Failed
+			result.Message = err.Error()
+			if check.Required {
+				requiredFailure = check.Name
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+func runWithTimeout(ctx context.Context, clients *Clients, check Check) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("caught panic: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultCheckTimeout)
+	defer cancel()
+	return check.Run(ctx, clients)
+}
+
+// Err returns an error describing every failed required check, or nil when the run may go on.
+func (r *Report) Err() error {
+	failures := []string{}
+	for _, result := range r.Results {
+		if result.Required && result.Status == Failed {
+			failures = append(failures, fmt.Sprintf("%s: %s", result.Name, result.Message))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("cluster %s failed preflight checks:\n%s", r.Host, strings.Join(failures, "\n"))
+}
+
+// String summarizes the report, a line per check.
+func (r *Report) String() string {
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "Preflight checks of %s:\n", r.Host)
+	for _, result := range r.Results {
+		fmt.Fprintf(out, "  %s: %s", result.Status, result.Name)
+		if len(result.Message) > 0 {
+			fmt.Fprintf(out, ": %s", result.Message)
+		}
+		fmt.Fprintln(out)
+	}
+	return out.String()
+}
+
+// JUnits returns a test case per check.  Failures of checks that are not required are reported as flakes, a failure
+// followed by a success.
+func (r *Report) JUnits() []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
+	for _, result := range r.Results {
+		testName := fmt.Sprintf("[sig-arch] preflight: %s", result.Name)
+		switch result.Status {
+		case Skipped:
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:        testName,
+				Duration:    result.DurationSeconds,
+				SkipMessage: &junitapi.SkipMessage{Message: result.Message},
+			})
+		case Failed:
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:          testName,
+				Duration:      result.DurationSeconds,
+				FailureOutput: &junitapi.FailureOutput{Output: result.Message},
+				SystemOut:     result.Message,
+			})
+			if !result.Required {
+				ret = append(ret, &junitapi.JUnitTestCase{
+					Name:     testName,
+					Duration: result.DurationSeconds,
+				})
+			}
+		default:
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name:     testName,
+				Duration: result.DurationSeconds,
+			})
+		}
+	}
+	return ret
+}
+
+// Write writes the report as preflight<fileSuffix>.json and its test cases as junit_preflight<fileSuffix>.xml into
+// dir.
+func (r *Report) Write(dir, fileSuffix string) error {
+	content, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("preflight%s.json", fileSuffix)), content, 0644); err != nil {
+		return err
+	}
+
+	junitSuite := junitapi.JUnitTestSuite{
+		Name: "preflight",
+	}
+	for _, junit := range r.JUnits() {
+		junitSuite.NumTests++
+		if junit.FailureOutput != nil {
+			junitSuite.NumFailed++
+		} else if junit.SkipMessage != nil {
+			junitSuite.NumSkipped++
+		}
+		junitSuite.Duration += junit.Duration
+		junitSuite.TestCases = append(junitSuite.TestCases, junit)
+	}
+	out, err := xml.MarshalIndent(junitSuite, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fmt.Sprintf("junit_preflight%s.xml", fileSuffix)), test.StripANSI(out), 0640)
+}
@@ -0,0 +1,120 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	fakeconfigclient "github.com/openshift/client-go/config/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+)
+
+func newClients(objects ...runtime.Object) (*Clients, *fake.Clientset) {
+	kubeClient := fake.NewSimpleClientset()
+	return &Clients{
+		Host:   "https://api.example.com:6443",
+		Kube:   kubeClient,
+		Config: fakeconfigclient.NewSimpleClientset(objects...),
+	}, kubeClient
+}
+
+func clusterVersion(version string, capabilities ...configv1.ClusterVersionCapability) *configv1.ClusterVersion {
+	return &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status: configv1.ClusterVersionStatus{
+			Desired:      configv1.Release{Version: version},
+			Capabilities: configv1.ClusterVersionCapabilitiesStatus{EnabledCapabilities: capabilities},
+		},
+	}
+}
+
+func clusterOperator(name string, conditions ...configv1.ClusterOperatorStatusCondition) *configv1.ClusterOperator {
+	return &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     configv1.ClusterOperatorStatus{Conditions: conditions},
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	clients, _ := newClients(
+		clusterVersion("4.16.3", configv1.ClusterVersionCapabilityBuild),
+		clusterOperator("etcd", configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue}),
+		clusterOperator("ingress", configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Reason: "IngressDegraded", Message: "router pods are crashlooping"}),
+	)
+
+	report := Run(ctx, clients, append(DefaultChecks("v4.17.0-0.nightly"), RequiredCapabilities(configv1.ClusterVersionCapabilityBuild))...)
+	if err := report.Err(); err != nil {
+		t.Fatalf("expected only required checks to stop the run, got %v", err)
+	}
+	statuses := []string{}
+	for _, result := range report.Results {
+		statuses = append(statuses, string(result.Status))
+	}
+	if actual := strings.Join(statuses, ","); actual != "Passed,Passed,Failed,Passed" {
+		t.Errorf("unexpected statuses %s\n%s", actual, report)
+	}
+	if !strings.Contains(report.Results[2].Message, "ingress is Degraded=True: IngressDegraded: router pods are crashlooping") {
+		t.Errorf("expected the degraded operator to be named, got %q", report.Results[2].Message)
+	}
+
+	// the failure of a check that is not required is a flake.
+	junits := report.JUnits()
+	if len(junits) != 5 || junits[2].FailureOutput == nil || junits[3].Name != junits[2].Name || junits[3].FailureOutput != nil {
+		t.Errorf("expected the degraded operators to flake, got %#v", junits)
+	}
+
+	// far apart versions, missing capabilities, and clusters without a clusterversion.
+	report = Run(ctx, clients, VersionSkew("4.19.0", 1), VersionSkew("unknown", 1), RequiredCapabilities(configv1.ClusterVersionCapabilityDeploymentConfig))
+	if report.Results[0].Status != Failed || report.Results[1].Status != Skipped || report.Results[2].Status != Failed {
+		t.Errorf("unexpected results\n%s", report)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "DeploymentConfig are not enabled") {
+		t.Errorf("expected the missing capability to stop the run, got %v", err)
+	}
+	bareClients, _ := newClients()
+	report = Run(ctx, bareClients, VersionSkew("4.17.0", 1))
+	if report.Results[0].Status != Skipped {
+		t.Errorf("expected the skew of a cluster without a clusterversion to be skipped\n%s", report)
+	}
+}
+
+func TestRunUnreachable(t *testing.T) {
+	clients, kubeClient := newClients()
+	kubeClient.PrependReactor("get", "version", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("dial tcp 10.0.0.1:6443: connect: connection refused")
+	})
+
+	report := Run(context.Background(), clients, DefaultChecks("4.17.0")...)
+	err := report.Err()
+	if err == nil || !strings.Contains(err.Error(), "connection refused") || !strings.Contains(err.Error(), "https://api.example.com:6443") {
+		t.Fatalf("expected an actionable error, got %v", err)
+	}
+	for _, result := range report.Results[1:] {
+		if result.Status != Skipped || !strings.Contains(result.Message, `"cluster is reachable" failed`) {
+			t.Errorf("expected %q to be skipped, got %#v", result.Name, result)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := report.Write(dir, "_suffix"); err != nil {
+		t.Fatal(err)
+	}
+	junit, err := os.ReadFile(filepath.Join(dir, "junit_preflight_suffix.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(junit), `tests="3" skipped="2" failures="1"`) {
+		t.Errorf("unexpected junit\n%s", junit)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "preflight_suffix.json")); err != nil {
+		t.Error(err)
+	}
+}
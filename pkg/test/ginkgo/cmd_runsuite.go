@@ -22,10 +22,13 @@ import (
 	"github.com/openshift/origin/pkg/defaultmonitortests"
 	"github.com/openshift/origin/pkg/disruption/backend/sampler"
 	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/monitor/contribution"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
 	"github.com/openshift/origin/pkg/monitortestframework"
+	"github.com/openshift/origin/pkg/preflight"
 	"github.com/openshift/origin/pkg/riskanalysis"
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/openshift/origin/pkg/version"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -40,6 +43,9 @@ const (
 	setupEvent       = "Setup"
 	upgradeEvent     = "Upgrade"
 	postUpgradeEvent = "PostUpgrade"
+
+	// defaultPRSummaryIntervals is how many intervals the summary of presubmits lists.
+	defaultPRSummaryIntervals = 10
 )
 
 // GinkgoRunSuiteOptions is used to run a suite of tests by invoking each test
@@ -233,12 +239,6 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		return err
 	}
 
-	// skip tests due to newer k8s
-	tests, err = o.filterOutRebaseTests(restConfig, tests)
-	if err != nil {
-		return err
-	}
-
 	if len(o.JUnitDir) > 0 {
 		if _, err := os.Stat(o.JUnitDir); err != nil {
 			if !os.IsNotExist(err) {
@@ -250,6 +250,17 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 		}
 	}
 
+	// fail before starting anything when the cluster cannot be tested.
+	if err := o.runPreflightChecks(ctx, restConfig, start); err != nil {
+		return err
+	}
+
+	// skip tests due to newer k8s
+	tests, err = o.filterOutRebaseTests(restConfig, tests)
+	if err != nil {
+		return err
+	}
+
 	parallelism := o.Parallelism
 	if parallelism == 0 {
 		parallelism = suite.Parallelism
@@ -277,12 +288,21 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	}()
 	signal.Notify(abortCh, syscall.SIGINT, syscall.SIGTERM)
 
+	// external test binaries contribute next to the other monitor content.
+	if len(monitorTestInfo.ContributionDir) == 0 && len(o.JUnitDir) > 0 {
+		monitorTestInfo.ContributionDir = filepath.Join(o.JUnitDir, contribution.DirName)
+	}
+	// presubmits summarize the run for the pull request that triggered them.
+	if monitorTestInfo.PRSummaryIntervals == 0 && os.Getenv("JOB_TYPE") == "presubmit" {
+		monitorTestInfo.PRSummaryIntervals = defaultPRSummaryIntervals
+	}
 	monitorTests, err := defaultmonitortests.NewMonitorTestsFor(monitorTestInfo)
 	if err != nil {
 		logrus.Errorf("Error getting monitor tests: %v", err)
 	}
 
-	monitorEventRecorder := monitor.NewRecorder()
+	resourceBudget := monitor.NewResourceBudget(monitor.ResourcePruningPolicies{})
+	monitorEventRecorder := monitor.NewRecorderWithResourceBudget(resourceBudget)
 	m := monitor.NewMonitor(
 		monitorEventRecorder,
 		restConfig,
@@ -505,6 +525,11 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	if err := m.SerializeResults(ctx, junitSuiteName, timeSuffix); err != nil {
 		fmt.Fprintf(o.ErrOut, "error: Failed to serialize run-data: %v\n", err)
 	}
+	if len(o.JUnitDir) > 0 {
+		if err := resourceBudget.WriteReport(o.JUnitDir, timeSuffix); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Failed to write the resource budget report: %v\n", err)
+		}
+	}
 
 	// default is empty string as that is what entries prior to adding this will have
 	wasMasterNodeUpdated := ""
@@ -594,6 +619,22 @@ func (o *GinkgoRunSuiteOptions) Run(suite *TestSuite, junitSuiteName string, mon
 	return ctx.Err()
 }
 
+func (o *GinkgoRunSuiteOptions) runPreflightChecks(ctx context.Context, restConfig *rest.Config, start time.Time) error {
+	clients, err := preflight.NewClients(restConfig)
+	if err != nil {
+		return err
+	}
+	report := preflight.Run(ctx, clients, preflight.DefaultChecks(version.Get().GitVersion)...)
+	fmt.Fprint(o.Out, report.String())
+	if len(o.JUnitDir) > 0 {
+		timeSuffix := fmt.Sprintf("_%s", start.UTC().Format("20060102-150405"))
+		if err := report.Write(o.JUnitDir, timeSuffix); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: Unable to write preflight report: %v\n", err)
+		}
+	}
+	return report.Err()
+}
+
 func (o *GinkgoRunSuiteOptions) filterOutRebaseTests(restConfig *rest.Config, tests []*testCase) ([]*testCase, error) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
 	if err != nil {
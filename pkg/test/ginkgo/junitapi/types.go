@@ -77,6 +77,65 @@ type JUnitTestCase struct {
 
 	// SystemErr is output written to stderr during the execution of this test case
 	SystemErr string `xml:"system-err,omitempty"`
+
+	// Details holds structured information about the outcome of the test case.  It is not part of the
+	// jUnit schema and is never marshalled into XML, but it is used to produce machine-readable summaries.
+	Details *JUnitTestCaseDetails `xml:"-"`
+}
+
+// JUnitTestCaseDetails holds structured information about the outcome of a test case so that
+// tooling does not need to scrape failure messages.
+type JUnitTestCaseDetails struct {
+	// MonitorTest is the name of the monitor test that produced the test case, if any.
+	MonitorTest string `json:"monitorTest,omitempty"`
+
+	// JiraComponent is the component responsible for the test case, if known.
+	JiraComponent string `json:"jiraComponent,omitempty"`
+
+	// Reason is a short, machine-readable reason for the outcome, for instance "DisruptionExceeded".
+	Reason string `json:"reason,omitempty"`
+
+	// Severity of a failing test case.  Empty is treated as SeverityFail.
+	Severity Severity `json:"severity,omitempty"`
+
+	// IntervalIDs links the test case to the intervals that caused the outcome.
+	IntervalIDs []string `json:"intervalIDs,omitempty"`
+
+	// Thresholds lists every threshold that was evaluated to produce the outcome.
+	Thresholds []JUnitThreshold `json:"thresholds,omitempty"`
+
+	// QuarantineBug links the bug tracking a quarantined test.  Failures of quarantined tests are reported as flakes.
+	QuarantineBug string `json:"quarantineBug,omitempty"`
+}
+
+// Severity describes how much a failing test case should matter to the job.
+type Severity string
+
+const (
+	// SeverityInfo failures are recorded, but never fail the job.
+	SeverityInfo Severity = "info"
+	// SeverityWarn failures are reported as flakes until a failure budget is exhausted.
+	SeverityWarn Severity = "warn"
+	// SeverityFail failures fail the job.
+	SeverityFail Severity = "fail"
+)
+
+// JUnitThreshold describes a single limit evaluated by a test case.
+type JUnitThreshold struct {
+	// Name identifies what was measured, for instance "disruption-seconds".
+	Name string `json:"name"`
+
+	// Limit is the value the observation was compared against.
+	Limit float64 `json:"limit"`
+
+	// Observed is the value that was measured.
+	Observed float64 `json:"observed"`
+
+	// Unit is the unit of both Limit and Observed, for instance "seconds".
+	Unit string `json:"unit,omitempty"`
+
+	// Exceeded is true when Observed was outside of the Limit.
+	Exceeded bool `json:"exceeded"`
 }
 
 // SkipMessage holds a message explaining why a test was skipped
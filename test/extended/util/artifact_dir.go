@@ -0,0 +1,24 @@
+package util
+
+import (
+	g "github.com/onsi/ginkgo/v2"
+	"k8s.io/kubernetes/test/e2e/framework"
+
+	"github.com/openshift/origin/pkg/monitortestframework"
+)
+
+// TestArtifactDir returns a directory of the current test's own under ARTIFACT_DIR, for files worth keeping with the
+// results of the run.  Files written through it are limited to quota, zero for the default, and are added to the
+// artifact index when the test ends.
+func TestArtifactDir(jiraComponent string, quota int64) *monitortestframework.ArtifactDir {
+	dir, err := monitortestframework.NewArtifactDir(ArtifactDirPath(), g.CurrentSpecReport().FullText(), jiraComponent, quota)
+	if err != nil {
+		FatalErr(err)
+	}
+	g.DeferCleanup(func() {
+		if err := dir.Close(); err != nil {
+			framework.Logf("unable to index the artifacts in %s: %v", dir.Dir(), err)
+		}
+	})
+	return dir
+}
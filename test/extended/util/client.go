@@ -255,7 +255,7 @@ func (c *CLI) ChangeUser(name string) *CLI {
 	requiresTestStart()
 	clientConfig := c.GetClientConfigForUser(name)
 
-	kubeConfig, err := createConfig(c.Namespace(), clientConfig)
+	kubeConfig, err := CreateConfig(c.Namespace(), clientConfig)
 	if err != nil {
 		FatalErr(err)
 	}
@@ -829,19 +829,25 @@ func (c *CLI) Run(commands ...string) *CLI {
 		adminConfigPath: c.adminConfigPath,
 		configPath:      c.configPath,
 		username:        c.username,
-		globalArgs:      commands,
+		globalArgs:      append(c.connectionArgs(), commands...),
+	}
+	nc.stdin, nc.stdout, nc.stderr = in, out, errout
+	return nc.setOutput(c.stdout)
+}
+
+// connectionArgs are the global flags that make a command run as the current user and in the test namespace.
+func (c *CLI) connectionArgs() []string {
+	args := []string{}
+	if !c.withoutNamespace {
+		args = append(args, fmt.Sprintf("--namespace=%s", c.Namespace()))
 	}
 	if len(c.configPath) > 0 {
-		nc.globalArgs = append([]string{fmt.Sprintf("--kubeconfig=%s", c.configPath)}, nc.globalArgs...)
+		args = append(args, fmt.Sprintf("--kubeconfig=%s", c.configPath))
 	}
 	if len(c.configPath) == 0 && len(c.token) > 0 {
-		nc.globalArgs = append([]string{fmt.Sprintf("--token=%s", c.token)}, nc.globalArgs...)
-	}
-	if !c.withoutNamespace {
-		nc.globalArgs = append([]string{fmt.Sprintf("--namespace=%s", c.Namespace())}, nc.globalArgs...)
+		args = append(args, fmt.Sprintf("--token=%s", c.token))
 	}
-	nc.stdin, nc.stdout, nc.stderr = in, out, errout
-	return nc.setOutput(c.stdout)
+	return args
 }
 
 // Executes with the kubeconfig specified from the environment
@@ -908,11 +914,11 @@ func (c *CLI) Background() (*exec.Cmd, *bytes.Buffer, *bytes.Buffer, error) {
 func (c *CLI) start(stdOutBuff, stdErrBuff *bytes.Buffer) (*exec.Cmd, error) {
 	c.finalArgs = append(c.globalArgs, c.commandArgs...)
 	if c.verbose {
-		fmt.Printf("DEBUG: oc %s\n", c.printCmd())
+		fmt.Printf("DEBUG: oc %s\n", RedactTokens(c.printCmd()))
 	}
 	cmd := exec.Command(c.execPath, c.finalArgs...)
 	cmd.Stdin = c.stdin
-	framework.Logf("Running '%s %s'", c.execPath, strings.Join(c.finalArgs, " "))
+	framework.Logf("Running '%s %s'", c.execPath, RedactTokens(strings.Join(c.finalArgs, " ")))
 
 	cmd.Stdout = stdOutBuff
 	cmd.Stderr = stdErrBuff
@@ -950,8 +956,9 @@ func (c *CLI) outputs(stdOutBuff, stdErrBuff *bytes.Buffer) (string, string, err
 		c.stderr = bytes.NewBuffer(stdErrBytes)
 		return stdOut, stdErr, nil
 	case *exec.ExitError:
-		framework.Logf("Error running %v:\nStdOut>\n%s\nStdErr>\n%s\n", cmd, stdOut, stdErr)
-		wrappedErr := fmt.Errorf("Error running %v:\nStdOut>\n%s\nStdErr>\n%s\n%w\n", cmd, stdOut[getStartingIndexForLastN(stdOutBytes, 4096):], stdErr[getStartingIndexForLastN(stdErrBytes, 4096):], err)
+		command := RedactTokens(cmd.String())
+		framework.Logf("Error running %s:\nStdOut>\n%s\nStdErr>\n%s\n", command, stdOut, stdErr)
+		wrappedErr := fmt.Errorf("Error running %s:\nStdOut>\n%s\nStdErr>\n%s\n%w\n", command, stdOut[getStartingIndexForLastN(stdOutBytes, 4096):], stdErr[getStartingIndexForLastN(stdErrBytes, 4096):], err)
 		return stdOut, stdErr, wrappedErr
 	default:
 		FatalErr(fmt.Errorf("unable to execute %q: %v", c.execPath, err))
@@ -0,0 +1,234 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// CommandResult is what one invocation of a command did.  When the command was retried, it is what the last attempt
+// did.
+type CommandResult struct {
+	// Command is the command line with any tokens redacted.  It is safe to log.
+	Command  string
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// Attempts is how many times the command was run.
+	Attempts int
+	Start    time.Time
+	End      time.Time
+}
+
+// Succeeded is true when the command exited zero.
+func (r *CommandResult) Succeeded() bool {
+	return r.ExitCode == 0
+}
+
+// CommandError is returned when the last attempt of a command exited non-zero.
+type CommandError struct {
+	Result *CommandResult
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%q exited %d after %d attempt(s): %s", e.Result.Command, e.Result.ExitCode, e.Result.Attempts, RedactTokens(lastBytes(e.Result.Stderr, 4096)))
+}
+
+// CommandRetryPolicy decides which failed attempts of a command are tried again.
+type CommandRetryPolicy struct {
+	// Attempts is the most times a command is run.  Zero means once.
+	Attempts int
+	// Interval is the wait between attempts.
+	Interval time.Duration
+	// Retriable is true for failures worth another attempt.  Nil retries none.
+	Retriable func(*CommandResult) bool
+}
+
+// RetryTransientAPIServerErrors retries commands that failed because the apiserver could not be reached or could not
+// answer, which happens while it rolls out and is no fault of the command.
+var RetryTransientAPIServerErrors = CommandRetryPolicy{
+	Attempts:  5,
+	Interval:  5 * time.Second,
+	Retriable: IsTransientAPIServerFailure,
+}
+
+var transientAPIServerErrors = []*regexp.Regexp{
+	regexp.MustCompile(`connection refused`),
+	regexp.MustCompile(`connection reset by peer`),
+	regexp.MustCompile(`i/o timeout`),
+	regexp.MustCompile(`TLS handshake timeout`),
+	regexp.MustCompile(`unexpected EOF`),
+	regexp.MustCompile(`http2: client connection lost`),
+	regexp.MustCompile(`the server is currently unable to handle the request`),
+	regexp.MustCompile(`the server was unable to return a response in the time allotted`),
+	regexp.MustCompile(`the server has received too many requests`),
+	regexp.MustCompile(`etcdserver: (leader changed|request timed out|no leader)`),
+}
+
+// IsTransientAPIServerFailure is true when the command failed because the apiserver was unavailable.
+func IsTransientAPIServerFailure(result *CommandResult) bool {
+	if result.Succeeded() {
+		return false
+	}
+	for _, transient := range transientAPIServerErrors {
+		if transient.MatchString(result.Stderr) {
+			return true
+		}
+	}
+	return false
+}
+
+var tokenPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(--(?:token|password)[= ])\S+`), "${1}<redacted>"},
+	{regexp.MustCompile(`(?i)(authorization:\s*bearer\s+)\S+`), "${1}<redacted>"},
+	{regexp.MustCompile(`sha256~[A-Za-z0-9_-]+`), "sha256~<redacted>"},
+	// service account tokens are JWTs, which always begin with an encoded {"
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), "<redacted>"},
+}
+
+// RedactTokens replaces bearer tokens and passwords in s, so s can be logged.
+func RedactTokens(s string) string {
+	for _, token := range tokenPatterns {
+		s = token.pattern.ReplaceAllString(s, token.replacement)
+	}
+	return s
+}
+
+// CommandRunner runs a command and captures what it did.  Failed attempts are retried according to its retry policy,
+// and when it has a recorder every invocation is recorded as an interval, so commands a test ran show up next to
+// what the monitor saw.
+type CommandRunner struct {
+	execPath   string
+	globalArgs []string
+	retry      CommandRetryPolicy
+	recorder   monitorapi.RecorderWriter
+	locator    monitorapi.Locator
+}
+
+// NewCommandRunner returns a runner of execPath that passes globalArgs before the args of every invocation, and does
+// not retry.
+func NewCommandRunner(execPath string, globalArgs ...string) *CommandRunner {
+	return &CommandRunner{
+		execPath:   execPath,
+		globalArgs: globalArgs,
+	}
+}
+
+// WithRetry returns a copy of the runner that retries according to the policy.
+func (r *CommandRunner) WithRetry(policy CommandRetryPolicy) *CommandRunner {
+	ret := *r
+	ret.retry = policy
+	return &ret
+}
+
+// WithRecorder returns a copy of the runner that records every invocation as an interval at the locator.
+func (r *CommandRunner) WithRecorder(recorder monitorapi.RecorderWriter, locator monitorapi.Locator) *CommandRunner {
+	ret := *r
+	ret.recorder = recorder
+	ret.locator = locator
+	return &ret
+}
+
+// Run runs the command with the args until it succeeds, fails in a way the retry policy does not retry, or runs out of
+// attempts.  A *CommandError is returned along with the result when the last attempt exited non-zero.  Other errors,
+// like a missing executable or a cancelled context, return no result.
+func (r *CommandRunner) Run(ctx context.Context, args ...string) (*CommandResult, error) {
+	finalArgs := append(append([]string{}, r.globalArgs...), args...)
+	command := RedactTokens(strings.Join(append([]string{r.execPath}, finalArgs...), " "))
+	attempts := r.retry.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	start := time.Now()
+	var result *CommandResult
+	for attempt := 1; ; attempt++ {
+		framework.Logf("Running '%s'", command)
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, r.execPath, finalArgs...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+		case errors.As(err, &exitErr) && ctx.Err() == nil:
+		case ctx.Err() != nil:
+			return nil, fmt.Errorf("%q was interrupted: %w", command, ctx.Err())
+		default:
+			return nil, fmt.Errorf("unable to run %q: %w", command, err)
+		}
+		result = &CommandResult{
+			Command:  command,
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+			ExitCode: cmd.ProcessState.ExitCode(),
+			Attempts: attempt,
+			Start:    start,
+			End:      time.Now(),
+		}
+		if result.Succeeded() || attempt >= attempts || r.retry.Retriable == nil || !r.retry.Retriable(result) {
+			break
+		}
+
+		framework.Logf("Retrying '%s' in %v, attempt %d of %d exited %d: %s", command, r.retry.Interval, attempt, attempts, result.ExitCode, RedactTokens(strings.TrimSpace(result.Stderr)))
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%q was interrupted: %w", command, ctx.Err())
+		case <-time.After(r.retry.Interval):
+		}
+	}
+
+	r.record(result)
+	if !result.Succeeded() {
+		framework.Logf("Error running '%s':\nStdOut>\n%s\nStdErr>\n%s\n", command, RedactTokens(lastBytes(result.Stdout, 4096)), RedactTokens(lastBytes(result.Stderr, 4096)))
+		return result, &CommandError{Result: result}
+	}
+	return result, nil
+}
+
+func (r *CommandRunner) record(result *CommandResult) {
+	if r.recorder == nil {
+		return
+	}
+	level, reason := monitorapi.Info, monitorapi.CommandSucceededReason
+	if !result.Succeeded() {
+		level, reason = monitorapi.Warning, monitorapi.CommandFailedReason
+	}
+	r.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceCommand, level).
+			Locator(r.locator).
+			Message(monitorapi.NewMessage().
+				Reason(reason).
+				WithAnnotation(monitorapi.AnnotationContainerExitCode, strconv.Itoa(result.ExitCode)).
+				WithAnnotation(monitorapi.AnnotationAttempts, strconv.Itoa(result.Attempts)).
+				HumanMessage(result.Command)).
+			Display().
+			Build(result.Start, result.End),
+	)
+}
+
+// lastBytes is the end of s, at most size bytes of it.
+func lastBytes(s string, size int) string {
+	s = strings.TrimSpace(s)
+	return s[getStartingIndexForLastN([]byte(s), size):]
+}
+
+// CommandRunner returns a runner of the CLI that connects the way Run does, as the current user and in the test
+// namespace.
+func (c *CLI) CommandRunner() *CommandRunner {
+	return NewCommandRunner(c.execPath, c.connectionArgs()...)
+}
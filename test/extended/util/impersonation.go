@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// ImpersonateUser returns a client config and a kubeconfig that act as the user, groups and extra of impersonate.  The
+// credentials of adminConfig authenticate every request, so it must be allowed to impersonate, which cluster-admin is.
+// Nothing is created on the server, which makes it the way to test RBAC for users that do not exist, including on
+// clusters that do not serve the user API.  The server adds system:authenticated to the groups of every impersonated
+// user but system:anonymous.
+func ImpersonateUser(namespace string, adminConfig *rest.Config, impersonate rest.ImpersonationConfig) (*rest.Config, *clientcmdapi.Config, error) {
+	if len(impersonate.UserName) == 0 {
+		return nil, nil, fmt.Errorf("a user is required to impersonate groups %v", impersonate.Groups)
+	}
+
+	impersonatingConfig := turnOffRateLimiting(rest.CopyConfig(adminConfig))
+	impersonatingConfig.Impersonate = rest.ImpersonationConfig{
+		UserName: impersonate.UserName,
+		UID:      impersonate.UID,
+		Groups:   append([]string(nil), impersonate.Groups...),
+	}
+	if len(impersonate.Extra) > 0 {
+		impersonatingConfig.Impersonate.Extra = map[string][]string{}
+		for key, values := range impersonate.Extra {
+			impersonatingConfig.Impersonate.Extra[key] = append([]string(nil), values...)
+		}
+	}
+
+	// the server knows the user by the impersonated name, there is no need to ask.
+	kubeConfig, err := BuildKubeConfig(namespace, impersonate.UserName, impersonatingConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return impersonatingConfig, kubeConfig, nil
+}
+
+// ImpersonateUser switches the CLI to act as the user and groups, impersonated by the admin.  Unlike ChangeUser, no
+// user or token is created.
+func (c *CLI) ImpersonateUser(name string, groups ...string) *CLI {
+	requiresTestStart()
+	_, kubeConfig, err := ImpersonateUser(c.Namespace(), c.AdminConfig(), rest.ImpersonationConfig{UserName: name, Groups: groups})
+	if err != nil {
+		FatalErr(err)
+	}
+
+	f, err := ioutil.TempFile("", "configfile")
+	if err != nil {
+		FatalErr(err)
+	}
+	c.configPath = f.Name()
+	err = clientcmd.WriteToFile(*kubeConfig, c.configPath)
+	if err != nil {
+		FatalErr(err)
+	}
+
+	c.username = name
+	framework.Logf("configPath is now %q, impersonating %q with groups %v", c.configPath, name, groups)
+	return c
+}
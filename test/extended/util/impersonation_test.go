@@ -0,0 +1,45 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestImpersonateUser(t *testing.T) {
+	adminConfig := &rest.Config{Host: "https://api.cluster.example.com:6443", BearerToken: "admin-token"}
+	impersonate := rest.ImpersonationConfig{
+		UserName: "e2e-reader",
+		Groups:   []string{"e2e-readers"},
+		Extra:    map[string][]string{"scopes.authorization.openshift.io": {"user:info"}},
+	}
+
+	impersonatingConfig, kubeConfig, err := ImpersonateUser("e2e-test", adminConfig, impersonate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if impersonatingConfig.BearerToken != "admin-token" || !reflect.DeepEqual(impersonatingConfig.Impersonate, impersonate) {
+		t.Errorf("expected the admin to impersonate %v, got %v", impersonate, impersonatingConfig)
+	}
+	if len(adminConfig.Impersonate.UserName) > 0 {
+		t.Errorf("expected the admin config to be left alone, got %v", adminConfig.Impersonate)
+	}
+	if kubeConfig.CurrentContext != "e2e-test/api-cluster-example-com:6443/e2e-reader" {
+		t.Errorf("unexpected context %q", kubeConfig.CurrentContext)
+	}
+
+	// the kubeconfig impersonates the same way.
+	readConfig, err := clientcmd.NewDefaultClientConfig(*kubeConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(readConfig.Impersonate, impersonate) {
+		t.Errorf("expected %v, got %v", impersonate, readConfig.Impersonate)
+	}
+
+	if _, _, err := ImpersonateUser("e2e-test", adminConfig, rest.ImpersonationConfig{Groups: []string{"system:masters"}}); err == nil {
+		t.Errorf("expected groups without a user to fail")
+	}
+}
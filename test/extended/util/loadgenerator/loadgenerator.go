@@ -0,0 +1,251 @@
+package loadgenerator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/kubernetes/test/e2e/framework"
+	imageutils "k8s.io/kubernetes/test/utils/image"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const reportingController = "openshift-tests.openshift.io/load-generator"
+
+// LoadGenerator repeatedly runs a profile against the cluster between Start and Stop.  Every object it creates
+// lives in a namespace owned by the generator, so Stop can clean up by deleting the namespace.
+//
+// The load window is recorded as a pair of events on that namespace.  The monitor turns those events into a
+// single interval so that disruption and latency evaluation can account for intentional load.
+type LoadGenerator struct {
+	profile *Profile
+
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+
+	namespace string
+
+	lock   sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewLoadGenerator creates a generator for the profile.  The rest config should be an admin config, it is copied
+// and rate limited to the qps and burst of the profile.
+func NewLoadGenerator(adminRESTConfig *rest.Config, profile *Profile) (*LoadGenerator, error) {
+	config := rest.CopyConfig(adminRESTConfig)
+	config.QPS = profile.QPS
+	config.Burst = profile.Burst
+
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoadGenerator{
+		profile:       profile,
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+		mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		namespace:     fmt.Sprintf("e2e-load-%s-%s", profile.Name, utilrand.String(5)),
+	}, nil
+}
+
+// Namespace is the namespace holding every object created by the generator.
+func (g *LoadGenerator) Namespace() string {
+	return g.namespace
+}
+
+// Start creates the namespace, records the start of the load window, and runs the profile in the background
+// until Stop is called.
+func (g *LoadGenerator) Start(ctx context.Context) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.cancel != nil {
+		return fmt.Errorf("load generator %q already started", g.namespace)
+	}
+
+	_, err := g.kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: g.namespace,
+			Labels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to create load generator namespace: %w", err)
+	}
+	g.recordEvent(ctx, monitorapi.LoadGeneratorStarted, fmt.Sprintf("load profile %s started", g.profile.Name))
+
+	loadCtx, cancel := context.WithCancel(context.Background())
+	g.cancel = cancel
+	g.done = make(chan struct{})
+	go func() {
+		defer close(g.done)
+		g.run(loadCtx)
+	}()
+	return nil
+}
+
+// Stop ends the load, records the end of the load window, and deletes the namespace.
+func (g *LoadGenerator) Stop(ctx context.Context) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.cancel == nil {
+		return fmt.Errorf("load generator %q not started", g.namespace)
+	}
+	g.cancel()
+	<-g.done
+	g.cancel = nil
+
+	g.recordEvent(ctx, monitorapi.LoadGeneratorStopped, fmt.Sprintf("load profile %s stopped", g.profile.Name))
+
+	err := g.kubeClient.CoreV1().Namespaces().Delete(ctx, g.namespace, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete load generator namespace: %w", err)
+	}
+	return nil
+}
+
+// RunDuring generates load with the named profile while fn runs.
+func RunDuring(ctx context.Context, adminRESTConfig *rest.Config, profileName string, fn func()) error {
+	profile, err := LoadProfile(profileName)
+	if err != nil {
+		return err
+	}
+	generator, err := NewLoadGenerator(adminRESTConfig, profile)
+	if err != nil {
+		return err
+	}
+	if err := generator.Start(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := generator.Stop(ctx); err != nil {
+			framework.Logf("Unable to stop load generator: %v", err)
+		}
+	}()
+
+	fn()
+	return nil
+}
+
+func (g *LoadGenerator) run(ctx context.Context) {
+	// created tracks the objects of each job iteration so they can be churned on the next pass.
+	created := map[string][]*unstructured.Unstructured{}
+	for pass := 0; ctx.Err() == nil; pass++ {
+		for _, job := range g.profile.Jobs {
+			for iteration := 0; iteration < job.Iterations; iteration++ {
+				if ctx.Err() != nil {
+					return
+				}
+				key := fmt.Sprintf("%s/%d", job.Name, iteration)
+				for _, obj := range created[key] {
+					g.deleteObject(ctx, obj)
+				}
+				created[key] = g.createIteration(ctx, job, iteration)
+			}
+		}
+		framework.Logf("Load generator %q finished pass %d of profile %s", g.namespace, pass, g.profile.Name)
+	}
+}
+
+func (g *LoadGenerator) createIteration(ctx context.Context, job Job, iteration int) []*unstructured.Unstructured {
+	ret := []*unstructured.Unstructured{}
+	for _, object := range job.Objects {
+		for replica := 0; replica < object.Replicas; replica++ {
+			obj, err := object.render(TemplateData{
+				Namespace:  g.namespace,
+				Job:        job.Name,
+				Iteration:  iteration,
+				Replica:    replica,
+				PauseImage: imageutils.GetPauseImageName(),
+			})
+			if err != nil {
+				framework.Logf("Load generator %q unable to render %s: %v", g.namespace, job.Name, err)
+				continue
+			}
+			resource, err := g.resourceFor(obj)
+			if err != nil {
+				framework.Logf("Load generator %q unable to map %s: %v", g.namespace, obj.GetKind(), err)
+				continue
+			}
+			if _, err := resource.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+				if ctx.Err() == nil {
+					framework.Logf("Load generator %q unable to create %s/%s: %v", g.namespace, obj.GetKind(), obj.GetName(), err)
+				}
+				continue
+			}
+			ret = append(ret, obj)
+		}
+	}
+	return ret
+}
+
+func (g *LoadGenerator) deleteObject(ctx context.Context, obj *unstructured.Unstructured) {
+	resource, err := g.resourceFor(obj)
+	if err != nil {
+		return
+	}
+	err = resource.Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) && ctx.Err() == nil {
+		framework.Logf("Load generator %q unable to delete %s/%s: %v", g.namespace, obj.GetKind(), obj.GetName(), err)
+	}
+}
+
+func (g *LoadGenerator) resourceFor(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := g.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	return g.dynamicClient.Resource(mapping.Resource).Namespace(g.namespace), nil
+}
+
+// recordEvent attempts to record an event on the load namespace so the monitor can see the load window.
+func (g *LoadGenerator) recordEvent(ctx context.Context, reason monitorapi.IntervalReason, note string) {
+	currentTime := metav1.MicroTime{Time: time.Now()}
+	ctx, cancelFn := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelFn()
+	_, err := g.kubeClient.EventsV1().Events(g.namespace).Create(ctx, &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%v.%x", "load-generator", currentTime.UnixNano()),
+		},
+		Regarding:           corev1.ObjectReference{Kind: "Namespace", Name: g.namespace, APIVersion: "v1"},
+		Action:              "GenerateLoad",
+		Reason:              string(reason),
+		Note:                note,
+		Type:                corev1.EventTypeNormal,
+		EventTime:           currentTime,
+		ReportingController: reportingController,
+		ReportingInstance:   g.namespace,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		framework.Logf("Unable to record load generator event: %v", err)
+	}
+}
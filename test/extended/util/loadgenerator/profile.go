@@ -0,0 +1,127 @@
+package loadgenerator
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed profiles/*.yaml
+var profileFS embed.FS
+
+// Profile describes a kube-burner style load: a rate limit for the apiserver and a list of jobs, each of which
+// creates a set of objects per iteration.
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// QPS and Burst bound the rate of requests the load generator makes against the apiserver.
+	QPS   float32 `json:"qps"`
+	Burst int     `json:"burst"`
+
+	Jobs []Job `json:"jobs"`
+}
+
+// Job creates every object, Replicas times, for each iteration.  Objects from an iteration are deleted before
+// the same iteration is created again on the next pass through the profile.
+type Job struct {
+	Name       string   `json:"name"`
+	Iterations int      `json:"iterations"`
+	Objects    []Object `json:"objects"`
+}
+
+// Object is a text/template for a namespaced manifest.  The template is rendered with TemplateData.
+type Object struct {
+	Replicas int    `json:"replicas"`
+	Template string `json:"template"`
+}
+
+// TemplateData is available to every object template.
+type TemplateData struct {
+	Namespace  string
+	Job        string
+	Iteration  int
+	Replica    int
+	PauseImage string
+}
+
+// ListProfiles returns the names of the profiles embedded in origin.
+func ListProfiles() []string {
+	entries, err := profileFS.ReadDir("profiles")
+	if err != nil {
+		panic(err)
+	}
+	ret := []string{}
+	for _, entry := range entries {
+		ret = append(ret, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// LoadProfile returns the embedded profile with the given name.
+func LoadProfile(name string) (*Profile, error) {
+	content, err := profileFS.ReadFile(path.Join("profiles", name+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown load profile %q, must be one of %v", name, ListProfiles())
+	}
+	return ParseProfile(content)
+}
+
+// ParseProfile decodes and validates a profile.
+func ParseProfile(content []byte) (*Profile, error) {
+	profile := &Profile{}
+	if err := yaml.UnmarshalStrict(content, profile); err != nil {
+		return nil, err
+	}
+	if len(profile.Name) == 0 {
+		return nil, fmt.Errorf("load profile must have a name")
+	}
+	if profile.QPS <= 0 || profile.Burst <= 0 {
+		return nil, fmt.Errorf("load profile %q must have a positive qps and burst", profile.Name)
+	}
+	if len(profile.Jobs) == 0 {
+		return nil, fmt.Errorf("load profile %q must have at least one job", profile.Name)
+	}
+	for _, job := range profile.Jobs {
+		if job.Iterations <= 0 {
+			return nil, fmt.Errorf("load profile %q job %q must have at least one iteration", profile.Name, job.Name)
+		}
+		for i, object := range job.Objects {
+			if object.Replicas <= 0 {
+				return nil, fmt.Errorf("load profile %q job %q object %d must have at least one replica", profile.Name, job.Name, i)
+			}
+			// render once so that broken templates are caught before any load is generated.
+			if _, err := object.render(TemplateData{Namespace: "validate", Job: job.Name}); err != nil {
+				return nil, fmt.Errorf("load profile %q job %q object %d: %w", profile.Name, job.Name, i, err)
+			}
+		}
+	}
+	return profile, nil
+}
+
+func (o Object) render(data TemplateData) (*unstructured.Unstructured, error) {
+	tmpl, err := template.New("object").Option("missingkey=error").Parse(o.Template)
+	if err != nil {
+		return nil, err
+	}
+	out := &bytes.Buffer{}
+	if err := tmpl.Execute(out, data); err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(out.Bytes(), &obj.Object); err != nil {
+		return nil, err
+	}
+	if len(obj.GetKind()) == 0 || len(obj.GetName()) == 0 {
+		return nil, fmt.Errorf("rendered object must have a kind and a name")
+	}
+	return obj, nil
+}
@@ -0,0 +1,76 @@
+package loadgenerator
+
+import (
+	"testing"
+)
+
+func TestEmbeddedProfiles(t *testing.T) {
+	names := ListProfiles()
+	if len(names) == 0 {
+		t.Fatal("expected embedded profiles")
+	}
+	for _, name := range names {
+		if _, err := LoadProfile(name); err != nil {
+			t.Errorf("profile %q: %v", name, err)
+		}
+	}
+	if _, err := LoadProfile("missing"); err == nil {
+		t.Errorf("expected an error for an unknown profile")
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			content: `name: test
+qps: 1
+burst: 1
+jobs:
+- name: cm
+  iterations: 1
+  objects:
+  - replicas: 1
+    template: |
+      apiVersion: v1
+      kind: ConfigMap
+      metadata:
+        name: cm-{{.Iteration}}
+`,
+		},
+		{
+			name: "unknown template key",
+			content: `name: test
+qps: 1
+burst: 1
+jobs:
+- name: cm
+  iterations: 1
+  objects:
+  - replicas: 1
+    template: |
+      kind: ConfigMap
+      metadata:
+        name: cm-{{.Missing}}
+`,
+			wantErr: true,
+		},
+		{
+			name:    "no jobs",
+			content: "name: test\nqps: 1\nburst: 1\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProfile([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package util
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// MustGatherTimeout is how long a must-gather or an inspect run from a test may take.
+const MustGatherTimeout = 20 * time.Minute
+
+// MustGather is the output of a must-gather or an inspect in a directory.  The accessors read files that were
+// compressed by the gather as if they were not.
+type MustGather struct {
+	// Dir is the root of the output.  must-gather writes into a directory named after its image, when there is exactly
+	// one directory in the destination it is that directory.
+	Dir string
+}
+
+// RunMustGather runs oc adm must-gather with the args into dir and opens the output.
+func RunMustGather(ctx context.Context, runner *CommandRunner, dir string, args ...string) (*MustGather, error) {
+	if _, err := runner.Run(ctx, append([]string{"adm", "must-gather", "--dest-dir", dir}, args...)...); err != nil {
+		return nil, err
+	}
+	return OpenMustGather(dir)
+}
+
+// RunInspect runs oc adm inspect of the namespaces into dir and opens the output.  An inspect is much quicker than a
+// must-gather, and is enough when only the operands in a few namespaces matter.
+func RunInspect(ctx context.Context, runner *CommandRunner, dir string, namespaces ...string) (*MustGather, error) {
+	args := []string{"adm", "inspect", "--dest-dir", dir}
+	for _, namespace := range namespaces {
+		args = append(args, "ns/"+namespace)
+	}
+	if _, err := runner.Run(ctx, args...); err != nil {
+		return nil, err
+	}
+	return OpenMustGather(dir)
+}
+
+// OpenMustGather opens the output of a must-gather or an inspect in dir.
+func OpenMustGather(dir string) (*MustGather, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	subdirs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			subdirs = append(subdirs, entry.Name())
+		}
+	}
+	// inspects and old must-gathers write directly into dir, and always have more than one directory.
+	if len(subdirs) == 1 {
+		return &MustGather{Dir: filepath.Join(dir, subdirs[0])}, nil
+	}
+	return &MustGather{Dir: dir}, nil
+}
+
+// UnpackMustGather unpacks a must-gather archive, like the must-gather.tar CI collects, into dir and opens it.
+// Archives may be gzipped.
+func UnpackMustGather(archive, dir string) (*MustGather, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	if strings.HasSuffix(archive, ".gz") || strings.HasSuffix(archive, ".tgz") {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", archive, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", archive, err)
+		}
+		target := filepath.Join(dir, header.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("%s has %q, which is outside of the archive", archive, header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return nil, err
+			}
+			_, err = io.Copy(out, tarReader)
+			out.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return OpenMustGather(dir)
+}
+
+// ReadFile reads the file at the path relative to the root of the output, or its gzipped copy when the gather
+// compressed it.
+func (m *MustGather) ReadFile(elem ...string) (string, error) {
+	path := filepath.Join(append([]string{m.Dir}, elem...)...)
+	content, err := os.ReadFile(path)
+	if err == nil {
+		return string(content), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	f, gzErr := os.Open(path + ".gz")
+	if gzErr != nil {
+		// the file is missing, not its compressed copy.
+		return "", err
+	}
+	defer f.Close()
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s.gz: %w", path, err)
+	}
+	defer gzipReader.Close()
+	content, err = io.ReadAll(gzipReader)
+	return string(content), err
+}
+
+// PodLog returns the log of the container of the pod, or of its previous instance.
+func (m *MustGather) PodLog(namespace, pod, container string, previous bool) (string, error) {
+	file := "current.log"
+	if previous {
+		file = "previous.log"
+	}
+	return m.ReadFile("namespaces", namespace, "pods", pod, container, container, "logs", file)
+}
+
+// OperatorLogs returns the current logs of every container of every pod in the namespace of an operator, keyed by
+// pod/container.
+func (m *MustGather) OperatorLogs(namespace string) (map[string]string, error) {
+	pods, err := os.ReadDir(filepath.Join(m.Dir, "namespaces", namespace, "pods"))
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]string{}
+	for _, pod := range pods {
+		if !pod.IsDir() {
+			continue
+		}
+		containers, err := os.ReadDir(filepath.Join(m.Dir, "namespaces", namespace, "pods", pod.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, container := range containers {
+			if !container.IsDir() {
+				continue
+			}
+			log, err := m.PodLog(namespace, pod.Name(), container.Name(), false)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			ret[pod.Name()+"/"+container.Name()] = log
+		}
+	}
+	return ret, nil
+}
+
+// HostServiceLog returns the journal of the systemd service, like kubelet or crio, on the nodes of the role, masters
+// or workers.
+func (m *MustGather) HostServiceLog(role, service string) (string, error) {
+	return m.ReadFile("host_service_logs", role, service+"_service.log")
+}
+
+// NodeKubeletLog returns the kubelet journal of the node.
+func (m *MustGather) NodeKubeletLog(node string) (string, error) {
+	return m.ReadFile("nodes", node, node+"_logs_kubelet")
+}
+
+// Nodes returns the names of the nodes with logs in the output, sorted.
+func (m *MustGather) Nodes() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(m.Dir, "nodes"))
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ret = append(ret, entry.Name())
+		}
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// ResourceYAML returns the resources of the group in the namespace, or cluster scoped ones when namespace is empty.
+// Core resources are in the group "core".
+func (m *MustGather) ResourceYAML(namespace, group, resource string) (string, error) {
+	if len(namespace) == 0 {
+		return m.ReadFile("cluster-scoped-resources", group, resource+".yaml")
+	}
+	return m.ReadFile("namespaces", namespace, group, resource+".yaml")
+}
+
+var unsafePathCharacters = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// mustGatherDir is a directory for a gather of the current test, under the artifacts of the run.
+func mustGatherDir(kind string) string {
+	name := unsafePathCharacters.ReplaceAllString(g.CurrentSpecReport().FullText(), "_")
+	if len(name) > 120 {
+		name = name[:120]
+	}
+	return ArtifactPath(kind, fmt.Sprintf("%s-%d", name, time.Now().Unix()))
+}
+
+// MustGather runs oc adm must-gather with the args into the artifacts of the test, so they are kept with the results of
+// the run.
+func (c *CLI) MustGather(args ...string) *MustGather {
+	ctx, cancel := context.WithTimeout(context.Background(), MustGatherTimeout)
+	defer cancel()
+	dir := mustGatherDir("must-gather")
+	gather, err := RunMustGather(ctx, c.WithoutNamespace().CommandRunner(), dir, args...)
+	if err != nil {
+		FatalErr(err)
+	}
+	framework.Logf("must-gather of %q is in %s", g.CurrentSpecReport().FullText(), dir)
+	return gather
+}
+
+// Inspect runs oc adm inspect of the namespaces into the artifacts of the test.
+func (c *CLI) Inspect(namespaces ...string) *MustGather {
+	ctx, cancel := context.WithTimeout(context.Background(), MustGatherTimeout)
+	defer cancel()
+	dir := mustGatherDir("inspect")
+	gather, err := RunInspect(ctx, c.WithoutNamespace().CommandRunner(), dir, namespaces...)
+	if err != nil {
+		FatalErr(err)
+	}
+	framework.Logf("inspect of %v for %q is in %s", namespaces, g.CurrentSpecReport().FullText(), dir)
+	return gather
+}
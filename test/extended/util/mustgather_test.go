@@ -0,0 +1,108 @@
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func gzipped(t *testing.T, content string) string {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestUnpackMustGather(t *testing.T) {
+	root := "registry-example-com-must-gather-sha256-abc/"
+	files := map[string]string{
+		root + "namespaces/openshift-etcd-operator/pods/etcd-operator-1/etcd-operator/etcd-operator/logs/current.log":  "started\n",
+		root + "namespaces/openshift-etcd-operator/pods/etcd-operator-1/etcd-operator/etcd-operator/logs/previous.log": "crashed\n",
+		root + "namespaces/openshift-etcd-operator/core/pods.yaml":                                                     "items: []\n",
+		root + "cluster-scoped-resources/config.openshift.io/clusteroperators.yaml":                                    "items: []\n",
+		root + "host_service_logs/masters/kubelet_service.log":                                                         "kubelet\n",
+		root + "nodes/master-0/master-0_logs_kubelet.gz":                                                               gzipped(t, "kubelet of master-0\n"),
+	}
+
+	archive := filepath.Join(t.TempDir(), "must-gather.tar.gz")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		if err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, closer := range []interface{ Close() error }{tarWriter, gzipWriter, f} {
+		if err := closer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gather, err := UnpackMustGather(archive, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(gather.Dir) != filepath.Base(root) {
+		t.Errorf("expected the directory of the image to be the root, got %s", gather.Dir)
+	}
+
+	logs, err := gather.OperatorLogs("openshift-etcd-operator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(logs, map[string]string{"etcd-operator-1/etcd-operator": "started\n"}) {
+		t.Errorf("unexpected operator logs %v", logs)
+	}
+	if log, err := gather.PodLog("openshift-etcd-operator", "etcd-operator-1", "etcd-operator", true); err != nil || log != "crashed\n" {
+		t.Errorf("unexpected previous log %q: %v", log, err)
+	}
+	if log, err := gather.HostServiceLog("masters", "kubelet"); err != nil || log != "kubelet\n" {
+		t.Errorf("unexpected host service log %q: %v", log, err)
+	}
+	if nodes, err := gather.Nodes(); err != nil || !reflect.DeepEqual(nodes, []string{"master-0"}) {
+		t.Errorf("unexpected nodes %v: %v", nodes, err)
+	}
+	if log, err := gather.NodeKubeletLog("master-0"); err != nil || log != "kubelet of master-0\n" {
+		t.Errorf("expected the compressed log to be read, got %q: %v", log, err)
+	}
+	if yaml, err := gather.ResourceYAML("", "config.openshift.io", "clusteroperators"); err != nil || yaml != "items: []\n" {
+		t.Errorf("unexpected cluster scoped resources %q: %v", yaml, err)
+	}
+	if _, err := gather.ResourceYAML("openshift-etcd-operator", "apps", "deployments"); !os.IsNotExist(err) {
+		t.Errorf("expected a missing file to be missing, got %v", err)
+	}
+}
+
+func TestUnpackMustGatherOutsideOfArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "must-gather.tar")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarWriter := tar.NewWriter(f)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "../escaped", Mode: 0644, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	tarWriter.Close()
+	f.Close()
+
+	if _, err := UnpackMustGather(archive, t.TempDir()); err == nil {
+		t.Errorf("expected a file outside of the archive to fail")
+	}
+}
@@ -0,0 +1,347 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/storage/names"
+	kclientset "k8s.io/client-go/kubernetes"
+	e2e "k8s.io/kubernetes/test/e2e/framework"
+	admissionapi "k8s.io/pod-security-admission/api"
+)
+
+const (
+	// namespacePoolLabel is the name of the pool a namespace belongs to.
+	namespacePoolLabel = "e2e.openshift.io/namespace-pool"
+	// namespaceClaimedByAnnotation is the test holding the namespace.  Namespaces without it are free.
+	namespaceClaimedByAnnotation = "e2e.openshift.io/namespace-pool-claimed-by"
+	// namespaceClaimedAtAnnotation is when the namespace was claimed, in RFC3339.
+	namespaceClaimedAtAnnotation = "e2e.openshift.io/namespace-pool-claimed-at"
+)
+
+// MaxNamespaceClaimDuration is how long a test may hold a namespace from a pool.  Claims older than this are from test
+// processes that exited without releasing, and the namespaces are deleted when the pool is filled.
+const MaxNamespaceClaimDuration = 2 * time.Hour
+
+// NamespacePool hands out namespaces created ahead of time, so tests do not wait for a namespace to be provisioned.
+// Namespaces are claimed with an optimistic update of the namespace itself, which makes a pool safe to share between
+// the processes of a parallel suite run.  A namespace is returned to the pool when it is released clean, and deleted
+// when the test left anything behind in it.
+type NamespacePool struct {
+	client           kclientset.Interface
+	name             string
+	size             int
+	podSecurityLevel admissionapi.Level
+}
+
+// NewNamespacePool returns a pool of size namespaces with the restricted pod security level.
+func NewNamespacePool(client kclientset.Interface, name string, size int) *NamespacePool {
+	return NewNamespacePoolWithPodSecurityLevel(client, name, size, admissionapi.LevelRestricted)
+}
+
+// NewNamespacePoolWithPodSecurityLevel returns a pool of size namespaces with the pod security level.
+func NewNamespacePoolWithPodSecurityLevel(client kclientset.Interface, name string, size int, level admissionapi.Level) *NamespacePool {
+	return &NamespacePool{
+		client:           client,
+		name:             name,
+		size:             size,
+		podSecurityLevel: level,
+	}
+}
+
+// Fill deletes the namespaces of claims older than MaxNamespaceClaimDuration, and creates namespaces until the pool
+// has size free ones.  It does not wait for the new namespaces to be provisioned.
+func (p *NamespacePool) Fill(ctx context.Context) error {
+	namespaces, err := p.list(ctx)
+	if err != nil {
+		return err
+	}
+	free := 0
+	for _, ns := range namespaces {
+		switch claimedAt, claimed := p.claimedAt(ns); {
+		case !claimed:
+			free++
+		case time.Since(claimedAt) > MaxNamespaceClaimDuration:
+			e2e.Logf("Deleting namespace %q of pool %q, claimed by %q at %v and never released", ns.Name, p.name, ns.Annotations[namespaceClaimedByAnnotation], claimedAt)
+			if err := p.client.CoreV1().Namespaces().Delete(ctx, ns.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	for ; free < p.size; free++ {
+		if _, err := p.create(ctx, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Claim returns a provisioned namespace of the pool for the owner, usually the name of the test, and a func that
+// releases it.  When no namespace is free one is created.  Releasing returns the namespace to the pool when the test
+// left nothing behind in it, and deletes it otherwise.  Releasing more than once does nothing.
+func (p *NamespacePool) Claim(ctx context.Context, owner string) (*corev1.Namespace, func(context.Context) error, error) {
+	namespaces, err := p.list(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var claimed *corev1.Namespace
+	for i := range namespaces {
+		ns := &namespaces[i]
+		if _, ok := p.claimedAt(*ns); ok || !namespaceProvisioned(ns) {
+			continue
+		}
+		ns.Annotations[namespaceClaimedByAnnotation] = owner
+		ns.Annotations[namespaceClaimedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		// the resourceVersion of the list makes this fail when another process claimed it first.
+		claimed, err = p.client.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+		switch {
+		case apierrors.IsConflict(err) || apierrors.IsNotFound(err):
+			claimed = nil
+			continue
+		case err != nil:
+			return nil, nil, err
+		}
+		break
+	}
+
+	if claimed == nil {
+		e2e.Logf("No namespace of pool %q is free, creating one for %q", p.name, owner)
+		claimed, err = p.create(ctx, map[string]string{
+			namespaceClaimedByAnnotation: owner,
+			namespaceClaimedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		claimed, err = p.waitForProvisioned(ctx, claimed.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	baseline, err := p.snapshot(ctx, claimed.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	released := false
+	release := func(ctx context.Context) error {
+		if released {
+			return nil
+		}
+		released = true
+		return p.release(ctx, claimed.Name, baseline)
+	}
+	return claimed, release, nil
+}
+
+func (p *NamespacePool) list(ctx context.Context) ([]corev1.Namespace, error) {
+	namespaces, err := p.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{namespacePoolLabel: p.name}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret := []corev1.Namespace{}
+	for _, ns := range namespaces.Items {
+		if ns.DeletionTimestamp != nil || ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ret = append(ret, ns)
+	}
+	return ret, nil
+}
+
+func (p *NamespacePool) claimedAt(ns corev1.Namespace) (time.Time, bool) {
+	value, ok := ns.Annotations[namespaceClaimedByAnnotation]
+	if !ok || len(value) == 0 {
+		return time.Time{}, false
+	}
+	// a claim without a time is as old as it gets.
+	claimedAt, _ := time.Parse(time.RFC3339, ns.Annotations[namespaceClaimedAtAnnotation])
+	return claimedAt, true
+}
+
+// create creates a namespace of the pool, with the labels SetupProject gives test namespaces.
+func (p *NamespacePool) create(ctx context.Context, annotations map[string]string) (*corev1.Namespace, error) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: names.SimpleNameGenerator.GenerateName(fmt.Sprintf("e2e-pool-%s-", p.name)),
+			Labels: map[string]string{
+				namespacePoolLabel:                               p.name,
+				admissionapi.EnforceLevelLabel:                   string(p.podSecurityLevel),
+				admissionapi.WarnLevelLabel:                      string(p.podSecurityLevel),
+				admissionapi.AuditLevelLabel:                     string(p.podSecurityLevel),
+				"security.openshift.io/scc.podSecurityLabelSync": "false",
+			},
+			Annotations: annotations,
+		},
+	}
+	return p.client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+}
+
+// namespaceProvisioned is true once the SCC allocation controller gave the namespace its ranges.
+func namespaceProvisioned(ns *corev1.Namespace) bool {
+	return len(ns.Annotations[securityv1.UIDRangeAnnotation]) > 0
+}
+
+func (p *NamespacePool) waitForProvisioned(ctx context.Context, name string) (*corev1.Namespace, error) {
+	var ret *corev1.Namespace
+	err := wait.PollUntilContextTimeout(ctx, 250*time.Millisecond, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
+		ns, err := p.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		ret = ns
+		return namespaceProvisioned(ns), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("namespace %q was not provisioned: %w", name, err)
+	}
+	return ret, nil
+}
+
+// namespaceContents is what a test may have left in a namespace.  Objects the platform creates in every namespace,
+// like the default service accounts and the CA config maps, are in it from the start and are not left behind.
+type namespaceContents struct {
+	labels      map[string]string
+	annotations map[string]string
+	objects     sets.Set[string]
+}
+
+func (p *NamespacePool) snapshot(ctx context.Context, name string) (*namespaceContents, error) {
+	ns, err := p.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ret := &namespaceContents{
+		labels:      ns.Labels,
+		annotations: map[string]string{},
+		objects:     sets.New[string](),
+	}
+	for key, value := range ns.Annotations {
+		if key == namespaceClaimedByAnnotation || key == namespaceClaimedAtAnnotation {
+			continue
+		}
+		ret.annotations[key] = value
+	}
+
+	add := func(kind string, objectNames ...string) {
+		for _, objectName := range objectNames {
+			ret.objects.Insert(kind + "/" + objectName)
+		}
+	}
+	core := p.client.CoreV1()
+	pods, err := core.Pods(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range pods.Items {
+		add("pods", obj.Name)
+	}
+	services, err := core.Services(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range services.Items {
+		add("services", obj.Name)
+	}
+	configMaps, err := core.ConfigMaps(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range configMaps.Items {
+		add("configmaps", obj.Name)
+	}
+	secrets, err := core.Secrets(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range secrets.Items {
+		// the platform rotates the tokens and pull secrets of service accounts, the names change on their own.
+		if obj.Type == corev1.SecretTypeServiceAccountToken || obj.Type == corev1.SecretTypeDockercfg {
+			continue
+		}
+		add("secrets", obj.Name)
+	}
+	serviceAccounts, err := core.ServiceAccounts(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range serviceAccounts.Items {
+		add("serviceaccounts", obj.Name)
+	}
+	claims, err := core.PersistentVolumeClaims(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range claims.Items {
+		add("persistentvolumeclaims", obj.Name)
+	}
+	roleBindings, err := p.client.RbacV1().RoleBindings(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range roleBindings.Items {
+		add("rolebindings", obj.Name)
+	}
+	roles, err := p.client.RbacV1().Roles(name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range roles.Items {
+		add("roles", obj.Name)
+	}
+	return ret, nil
+}
+
+// dirt returns what was changed in the namespace since the baseline, sorted.
+func (c *namespaceContents) dirt(baseline *namespaceContents) []string {
+	ret := sets.List(c.objects.Difference(baseline.objects))
+	if !reflect.DeepEqual(c.labels, baseline.labels) {
+		ret = append(ret, "labels")
+	}
+	if !reflect.DeepEqual(c.annotations, baseline.annotations) {
+		ret = append(ret, "annotations")
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (p *NamespacePool) release(ctx context.Context, name string, baseline *namespaceContents) error {
+	current, err := p.snapshot(ctx, name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if dirt := current.dirt(baseline); len(dirt) > 0 {
+		e2e.Logf("Deleting namespace %q of pool %q, it was left with %v", name, p.name, dirt)
+		if err := p.client.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	ns, err := p.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	delete(ns.Annotations, namespaceClaimedByAnnotation)
+	delete(ns.Annotations, namespaceClaimedAtAnnotation)
+	_, err = p.client.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+	return err
+}
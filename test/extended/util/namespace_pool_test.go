@@ -0,0 +1,106 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+)
+
+// provisioningClient is a fake client that provisions namespaces the way the SCC allocation controller would.
+func provisioningClient(objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.PrependReactor("create", "namespaces", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		ns := action.(clientgotesting.CreateAction).GetObject().(*corev1.Namespace)
+		if ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		ns.Annotations[securityv1.UIDRangeAnnotation] = "1000/10000"
+		return false, nil, nil
+	})
+	return client
+}
+
+func TestNamespacePool(t *testing.T) {
+	ctx := context.Background()
+	abandoned := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "e2e-pool-test-abandoned",
+		Labels: map[string]string{namespacePoolLabel: "test"},
+		Annotations: map[string]string{
+			namespaceClaimedByAnnotation: "a test that crashed",
+			namespaceClaimedAtAnnotation: time.Now().Add(-2 * MaxNamespaceClaimDuration).Format(time.RFC3339),
+		},
+	}}
+	other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{namespacePoolLabel: "other"}}}
+	client := provisioningClient(abandoned, other)
+	pool := NewNamespacePool(client, "test", 2)
+
+	if err := pool.Fill(ctx); err != nil {
+		t.Fatal(err)
+	}
+	namespaces, err := pool.list(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 2 {
+		t.Fatalf("expected the abandoned namespace replaced by two free ones, got %v", namespaces)
+	}
+
+	first, releaseFirst, err := pool.Claim(ctx, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, releaseSecond, err := pool.Claim(ctx, "second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	third, releaseThird, err := pool.Claim(ctx, "third")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Name == second.Name || second.Name == third.Name || first.Name == third.Name {
+		t.Fatalf("expected every claim to get its own namespace, got %v, %v and %v", first.Name, second.Name, third.Name)
+	}
+	if first.Labels["pod-security.kubernetes.io/enforce"] != "restricted" {
+		t.Errorf("expected the pod security labels of test namespaces, got %v", first.Labels)
+	}
+
+	// the first test leaves a pod behind, the second cleans up after itself.
+	if _, err := client.CoreV1().Pods(first.Name).Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "left-behind"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CoreV1().ConfigMaps(second.Name).Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cleaned-up"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CoreV1().ConfigMaps(second.Name).Delete(ctx, "cleaned-up", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, release := range []func(context.Context) error{releaseFirst, releaseSecond, releaseThird, releaseFirst} {
+		if err := release(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := client.CoreV1().Namespaces().Get(ctx, first.Name, metav1.GetOptions{}); err == nil {
+		t.Errorf("expected the dirty namespace %q to be deleted", first.Name)
+	}
+	namespaces, err = pool.list(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 2 {
+		t.Fatalf("expected the clean namespaces back in the pool, got %v", namespaces)
+	}
+	for _, ns := range namespaces {
+		if _, claimed := pool.claimedAt(ns); claimed {
+			t.Errorf("expected %q to be free, got %v", ns.Name, ns.Annotations)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/kubernetes/test/e2e/framework"
+	e2essh "k8s.io/kubernetes/test/e2e/framework/ssh"
+	admissionapi "k8s.io/pod-security-admission/api"
+
+	"github.com/openshift/origin/test/extended/util/image"
+)
+
+// NodeAccessMethod is how a command reached a node.
+type NodeAccessMethod string
+
+const (
+	NodeAccessDebugPod NodeAccessMethod = "debug-pod"
+	NodeAccessSSH      NodeAccessMethod = "ssh"
+)
+
+// DefaultNodeCommandConcurrency is how many nodes a NodeCommander runs commands on at once when not told otherwise.
+const DefaultNodeCommandConcurrency = 10
+
+// nodeDebugPodStartTimeout is how long a debug pod may take to start before the node is considered unreachable by
+// debug pods.
+const nodeDebugPodStartTimeout = 3 * time.Minute
+
+// nodeDebugLabel is on the debug pods of a NodeCommander.
+const nodeDebugLabel = "e2e.openshift.io/node-debug"
+
+// NodeCommandResult is what a command did on a node.
+type NodeCommandResult struct {
+	Node     string
+	Method   NodeAccessMethod
+	Command  string
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Start    time.Time
+	End      time.Time
+}
+
+// Succeeded is true when the command exited zero.
+func (r *NodeCommandResult) Succeeded() bool {
+	return r.ExitCode == 0
+}
+
+// NodeCommandError is returned when a command exited non-zero on a node.
+type NodeCommandError struct {
+	Result *NodeCommandResult
+}
+
+func (e *NodeCommandError) Error() string {
+	return fmt.Sprintf("%q exited %d on node/%s over %s: %s", e.Result.Command, e.Result.ExitCode, e.Result.Node, e.Result.Method, lastBytes(e.Result.Stderr, 4096))
+}
+
+// NodeCommander runs commands as root in the host namespaces of nodes.  Commands run in a privileged debug pod on the
+// node, which is kept for the next command, the way oc debug node does.  When a debug pod cannot be started on the
+// node, which happens when the node is in trouble, and KUBE_SSH_BASTION is set, the command runs over SSH through the
+// bastion instead.  Commands on different nodes run concurrently, up to a limit.
+type NodeCommander struct {
+	client    kubernetes.Interface
+	streaming *StreamingHarness
+	namespace string
+	image     string
+	slots     chan struct{}
+
+	lock      sync.Mutex
+	debugPods map[string]string
+
+	// ssh is replaced by unit tests.
+	ssh func(ctx context.Context, cmd, host, provider string) (e2essh.Result, error)
+}
+
+// NewNodeCommander returns a commander that starts debug pods with the image in the namespace, which must allow
+// privileged pods.  CreateNodeCommandNamespace creates one.  Up to concurrency commands run at once.
+func NewNodeCommander(adminConfig *rest.Config, adminClient kubernetes.Interface, namespace, image string, concurrency int) *NodeCommander {
+	if concurrency < 1 {
+		concurrency = DefaultNodeCommandConcurrency
+	}
+	return &NodeCommander{
+		client:    adminClient,
+		streaming: NewStreamingHarness(adminConfig, adminClient),
+		namespace: namespace,
+		image:     image,
+		slots:     make(chan struct{}, concurrency),
+		debugPods: map[string]string{},
+		ssh:       e2essh.SSH,
+	}
+}
+
+// CreateNodeCommandNamespace creates a namespace that allows the debug pods of a NodeCommander on every node.
+func CreateNodeCommandNamespace(ctx context.Context, adminClient kubernetes.Interface) (string, error) {
+	ns, err := adminClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "e2e-node-commands-",
+			Labels: map[string]string{
+				admissionapi.EnforceLevelLabel:                   string(admissionapi.LevelPrivileged),
+				admissionapi.WarnLevelLabel:                      string(admissionapi.LevelPrivileged),
+				admissionapi.AuditLevelLabel:                     string(admissionapi.LevelPrivileged),
+				"security.openshift.io/scc.podSecurityLabelSync": "false",
+			},
+			Annotations: map[string]string{
+				// the default node selector of projects would keep debug pods off of the control plane.
+				"openshift.io/node-selector": "",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return ns.Name, nil
+}
+
+// Run runs the command with /bin/sh on the node.  A *NodeCommandError is returned along with the result when the
+// command exited non-zero.  Other errors mean the command could not be run.
+func (n *NodeCommander) Run(ctx context.Context, node, command string) (*NodeCommandResult, error) {
+	select {
+	case n.slots <- struct{}{}:
+		defer func() { <-n.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	result, err := n.runInDebugPod(ctx, node, command)
+	if err != nil && len(os.Getenv("KUBE_SSH_BASTION")) > 0 {
+		framework.Logf("Unable to run %q on node/%s in a debug pod, falling back to SSH: %v", command, node, err)
+		result, err = n.runOverSSH(ctx, node, command)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !result.Succeeded() {
+		return result, &NodeCommandError{Result: result}
+	}
+	return result, nil
+}
+
+// RunOnNodes runs the command on every node and returns the results by node.  Nodes the command could not be run on
+// have no result, the errors of every node are aggregated.
+func (n *NodeCommander) RunOnNodes(ctx context.Context, nodes []string, command string) (map[string]*NodeCommandResult, error) {
+	lock := sync.Mutex{}
+	results := map[string]*NodeCommandResult{}
+	errs := []error{}
+	wg := sync.WaitGroup{}
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node string) {
+			defer wg.Done()
+			result, err := n.Run(ctx, node, command)
+			lock.Lock()
+			defer lock.Unlock()
+			if result != nil {
+				results[node] = result
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}(node)
+	}
+	wg.Wait()
+	return results, utilerrors.NewAggregate(errs)
+}
+
+// Close deletes the debug pods.  The namespace is left to whoever created it.
+func (n *NodeCommander) Close(ctx context.Context) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	errs := []error{}
+	for node, pod := range n.debugPods {
+		if err := n.client.CoreV1().Pods(n.namespace).Delete(ctx, pod, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+		delete(n.debugPods, node)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (n *NodeCommander) runInDebugPod(ctx context.Context, node, command string) (*NodeCommandResult, error) {
+	pod, err := n.debugPod(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NodeCommandResult{Node: node, Method: NodeAccessDebugPod, Command: command, Start: time.Now()}
+	session, err := n.streaming.Exec(ctx, n.namespace, pod, "debug", []string{"chroot", "/host", "/bin/sh", "-c", command}, nil)
+	result.End = time.Now()
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitStatus()
+	default:
+		// the pod may be what is broken, the next command gets a new one.
+		n.forgetDebugPod(ctx, node, pod)
+		return nil, fmt.Errorf("unable to exec in debug pod %s/%s on node/%s: %w", n.namespace, pod, node, err)
+	}
+	result.Stdout = session.Transcript.Stream("stdout")
+	result.Stderr = session.Transcript.Stream("stderr")
+	return result, nil
+}
+
+// debugPod returns the running debug pod on the node, starting one when there is none.
+func (n *NodeCommander) debugPod(ctx context.Context, node string) (string, error) {
+	n.lock.Lock()
+	pod, ok := n.debugPods[node]
+	n.lock.Unlock()
+	if ok {
+		return pod, nil
+	}
+
+	created, err := n.client.CoreV1().Pods(n.namespace).Create(ctx, n.newDebugPod(node), metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to create a debug pod on node/%s: %w", node, err)
+	}
+	err = wait.PollUntilContextTimeout(ctx, time.Second, nodeDebugPodStartTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := n.client.CoreV1().Pods(n.namespace).Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch current.Status.Phase {
+		case corev1.PodRunning:
+			return true, nil
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return false, fmt.Errorf("debug pod %s/%s exited: %s", n.namespace, created.Name, current.Status.Message)
+		}
+		return false, nil
+	})
+	if err != nil {
+		n.forgetDebugPod(ctx, node, created.Name)
+		return "", fmt.Errorf("debug pod %s/%s did not start on node/%s: %w", n.namespace, created.Name, node, err)
+	}
+
+	n.lock.Lock()
+	existing, ok := n.debugPods[node]
+	if !ok {
+		n.debugPods[node] = created.Name
+	}
+	n.lock.Unlock()
+	if ok {
+		// another command on the node started one at the same time, use that one.
+		n.forgetDebugPod(ctx, node, created.Name)
+		return existing, nil
+	}
+	return created.Name, nil
+}
+
+func (n *NodeCommander) forgetDebugPod(ctx context.Context, node, pod string) {
+	n.lock.Lock()
+	if n.debugPods[node] == pod {
+		delete(n.debugPods, node)
+	}
+	n.lock.Unlock()
+	if err := n.client.CoreV1().Pods(n.namespace).Delete(ctx, pod, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		framework.Logf("Unable to delete debug pod %s/%s: %v", n.namespace, pod, err)
+	}
+}
+
+// newDebugPod is a pod like the one oc debug node starts: privileged, in the host namespaces, with the root of the
+// host at /host.
+func (n *NodeCommander) newDebugPod(node string) *corev1.Pod {
+	privileged := true
+	root := int64(0)
+	gracePeriod := int64(0)
+	hostPathDirectory := corev1.HostPathDirectory
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "node-debug-",
+			Labels:       map[string]string{nodeDebugLabel: "true"},
+			Annotations:  map[string]string{"debug.openshift.io/source-resource": "/v1, Resource=nodes/" + node},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:                      node,
+			HostNetwork:                   true,
+			HostPID:                       true,
+			HostIPC:                       true,
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &gracePeriod,
+			Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:    "debug",
+				Image:   n.image,
+				Command: []string{"/bin/sh", "-c", "trap 'exit 0' TERM; sleep infinity & wait"},
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: &privileged,
+					RunAsUser:  &root,
+				},
+				VolumeMounts: []corev1.VolumeMount{{Name: "host", MountPath: "/host"}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "host",
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/", Type: &hostPathDirectory},
+				},
+			}},
+		},
+	}
+}
+
+func (n *NodeCommander) runOverSSH(ctx context.Context, node, command string) (*NodeCommandResult, error) {
+	nodeObj, err := n.client.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	address := ""
+	for _, nodeAddress := range nodeObj.Status.Addresses {
+		if nodeAddress.Type == corev1.NodeInternalIP {
+			address = nodeAddress.Address
+			break
+		}
+	}
+	if len(address) == 0 {
+		return nil, fmt.Errorf("node/%s has no internal IP to SSH to", node)
+	}
+
+	result := &NodeCommandResult{Node: node, Method: NodeAccessSSH, Command: command, Start: time.Now()}
+	// debug pods run commands as root, so does SSH.
+	sshResult, err := n.ssh(ctx, "sudo /bin/sh -c "+shellQuote(command), net.JoinHostPort(address, e2essh.SSHPort), framework.TestContext.Provider)
+	result.End = time.Now()
+	if err != nil {
+		return nil, fmt.Errorf("unable to run %q on node/%s over SSH: %w", command, node, err)
+	}
+	result.Stdout = sshResult.Stdout
+	result.Stderr = sshResult.Stderr
+	result.ExitCode = sshResult.Code
+	return result, nil
+}
+
+// shellQuote quotes s as a single argument of sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// NodeCommander returns a commander that runs commands on nodes as the admin, with debug pods in a namespace that is
+// deleted with the test.
+func (c *CLI) NodeCommander() *NodeCommander {
+	namespace, err := CreateNodeCommandNamespace(context.Background(), c.AdminKubeClient())
+	if err != nil {
+		FatalErr(err)
+	}
+	c.AddExplicitResourceToDelete(corev1.SchemeGroupVersion.WithResource("namespaces"), "", namespace)
+	return NewNodeCommander(c.AdminConfig(), c.AdminKubeClient(), namespace, image.ShellImage(), DefaultNodeCommandConcurrency)
+}
@@ -0,0 +1,125 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	e2essh "k8s.io/kubernetes/test/e2e/framework/ssh"
+)
+
+// schedulingClient is a fake client that names pods and starts them, unless they are for an unschedulable node.
+func schedulingClient(unschedulable string, objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	created := 0
+	client.PrependReactor("create", "pods", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		pod := action.(clientgotesting.CreateAction).GetObject().(*corev1.Pod)
+		created++
+		pod.Name = fmt.Sprintf("%s%d", pod.GenerateName, created)
+		pod.Status.Phase = corev1.PodRunning
+		if pod.Spec.NodeName == unschedulable {
+			pod.Status.Phase = corev1.PodFailed
+			pod.Status.Message = "node is not ready"
+		}
+		return false, nil, nil
+	})
+	return client
+}
+
+func TestNodeCommander(t *testing.T) {
+	ctx := context.Background()
+	client := schedulingClient("")
+	streaming, _ := fakeStreamingHarness(t, map[StreamingProtocol]*fakeExecutor{
+		StreamingWebSocket: {stream: func(ctx context.Context, options remotecommand.StreamOptions) error {
+			fmt.Fprint(options.Stdout, "out")
+			fmt.Fprint(options.Stderr, "err")
+			return utilexec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: 3}
+		}},
+	})
+	commander := NewNodeCommander(nil, client, "e2e-node-commands", "tools", 2)
+	commander.streaming = streaming
+
+	results, err := commander.RunOnNodes(ctx, []string{"master-0", "worker-0"}, "systemctl is-active kubelet")
+	if err == nil {
+		t.Fatalf("expected the exit codes to be errors")
+	}
+	for _, node := range []string{"master-0", "worker-0"} {
+		result := results[node]
+		if result == nil || result.Method != NodeAccessDebugPod || result.ExitCode != 3 || result.Stdout != "out" || result.Stderr != "err" {
+			t.Errorf("unexpected result on %s: %#v", node, result)
+		}
+	}
+
+	pods, err := client.CoreV1().Pods("e2e-node-commands").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 2 {
+		t.Fatalf("expected a debug pod per node, got %d", len(pods.Items))
+	}
+	for _, pod := range pods.Items {
+		container := pod.Spec.Containers[0]
+		if !pod.Spec.HostNetwork || !pod.Spec.HostPID || !*container.SecurityContext.Privileged || container.Image != "tools" {
+			t.Errorf("expected a privileged debug pod in the host namespaces, got %v", pod.Spec)
+		}
+	}
+
+	// debug pods are reused.
+	var commandErr *NodeCommandError
+	if _, err := commander.Run(ctx, "master-0", "true"); !errors.As(err, &commandErr) {
+		t.Fatal(err)
+	}
+	if pods, _ := client.CoreV1().Pods("e2e-node-commands").List(ctx, metav1.ListOptions{}); len(pods.Items) != 2 {
+		t.Errorf("expected the debug pod to be reused, got %d pods", len(pods.Items))
+	}
+
+	if err := commander.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pods, _ := client.CoreV1().Pods("e2e-node-commands").List(ctx, metav1.ListOptions{}); len(pods.Items) != 0 {
+		t.Errorf("expected the debug pods to be deleted, got %d pods", len(pods.Items))
+	}
+}
+
+func TestNodeCommanderSSHFallback(t *testing.T) {
+	ctx := context.Background()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+		Status:     corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.5"}}},
+	}
+	client := schedulingClient("worker-0", node)
+	commander := NewNodeCommander(nil, client, "e2e-node-commands", "tools", 1)
+	var sshCommand, sshHost string
+	commander.ssh = func(ctx context.Context, cmd, host, provider string) (e2essh.Result, error) {
+		sshCommand, sshHost = cmd, host
+		return e2essh.Result{Stdout: "active\n"}, nil
+	}
+
+	if _, err := commander.Run(ctx, "worker-0", "systemctl is-active kubelet"); err == nil {
+		t.Fatalf("expected a node without debug pods to fail without a bastion")
+	}
+
+	t.Setenv("KUBE_SSH_BASTION", "bastion.example.com:22")
+	result, err := commander.Run(ctx, "worker-0", "echo 'it''s' active")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Method != NodeAccessSSH || result.Stdout != "active\n" {
+		t.Errorf("expected the command to run over SSH, got %#v", result)
+	}
+	if sshHost != "10.0.0.5:22" || !strings.HasPrefix(sshCommand, "sudo /bin/sh -c 'echo '\\''it'\\'''\\''s'\\'' active'") {
+		t.Errorf("unexpected SSH of %q to %s", sshCommand, sshHost)
+	}
+	if pods, _ := client.CoreV1().Pods("e2e-node-commands").List(ctx, metav1.ListOptions{}); len(pods.Items) != 0 {
+		t.Errorf("expected the failed debug pods to be deleted, got %d pods", len(pods.Items))
+	}
+}
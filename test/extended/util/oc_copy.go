@@ -2,16 +2,27 @@ package util
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	userv1 "github.com/openshift/api/user/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/third_party/forked/golang/netutil"
+	"k8s.io/client-go/kubernetes"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 
 	userv1typedclient "github.com/openshift/client-go/user/clientset/versioned/typed/user/v1"
@@ -30,89 +41,129 @@ func getClusterNicknameFromConfig(clientCfg *restclient.Config) (string, error)
 	return strings.Replace(hostPort, ".", "-", -1), nil
 }
 
-// getUserNicknameFromConfig returns "username(as known by the server)/getClusterNicknameFromConfig".  This allows tab completion for switching users to
-// work easily and obviously.
-func getUserNicknameFromConfig(clientCfg *restclient.Config) (string, error) {
-	userPartOfNick, err := getUserPartOfNickname(clientCfg)
+// userNameCache remembers the user the credentials of a config are known as, keyed by credentialFingerprint, so tests
+// that change users repeatedly only ask the server once.
+var userNameCache = struct {
+	sync.Mutex
+	names map[string]string
+}{names: map[string]string{}}
+
+func getUserPartOfNickname(clientCfg *restclient.Config) (string, error) {
+	fingerprint := credentialFingerprint(clientCfg)
+	userNameCache.Lock()
+	defer userNameCache.Unlock()
+	if name, ok := userNameCache.names[fingerprint]; ok {
+		return name, nil
+	}
+
+	userClient, err := userv1typedclient.NewForConfig(clientCfg)
 	if err != nil {
 		return "", err
 	}
-
-	clusterNick, err := getClusterNicknameFromConfig(clientCfg)
+	kubeClient, err := kubernetes.NewForConfig(clientCfg)
 	if err != nil {
 		return "", err
 	}
-
-	return userPartOfNick + "/" + clusterNick, nil
-}
-
-func getUserPartOfNickname(clientCfg *restclient.Config) (string, error) {
-	userClient, err := userv1typedclient.NewForConfig(clientCfg)
+	name, err := resolveUserName(context.Background(), userClient, kubeClient.AuthenticationV1(), clientCfg)
 	if err != nil {
 		return "", err
 	}
+	userNameCache.names[fingerprint] = name
+	return name, nil
+}
 
+// resolveUserName returns the user the server knows the credentials of clientCfg as.  The user API is only served by
+// openshift, kube is asked with a SelfSubjectReview.  When neither will say, the name is a best guess consistent with
+// login that never contains the credentials themselves, since it ends up in nicknames and in logs.
+func resolveUserName(ctx context.Context, users userv1typedclient.UsersGetter, reviews authenticationv1client.SelfSubjectReviewsGetter, clientCfg *restclient.Config) (string, error) {
 	var userInfo *userv1.User
-	err = wait.PollUntilContextTimeout(context.Background(), time.Second, time.Minute, true, func(ctx context.Context) (done bool, err error) {
-		userInfo, err = userClient.Users().Get(ctx, "~", metav1.GetOptions{})
+	err := wait.PollUntilContextTimeout(ctx, time.Second, time.Minute, true, func(ctx context.Context) (done bool, err error) {
+		userInfo, err = users.Users().Get(ctx, "~", metav1.GetOptions{})
 		if err != nil && strings.Contains(err.Error(), "connect: connection refused") {
 			return false, nil
 		}
 		return true, err
 	})
-
-	if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) {
-		// if we're talking to kube (or likely talking to kube), take a best guess consistent with login
-		switch {
-		case len(clientCfg.BearerToken) > 0:
-			userInfo.Name = clientCfg.BearerToken
-		case len(clientCfg.Username) > 0:
-			userInfo.Name = clientCfg.Username
-		}
-	} else if err != nil {
+	switch {
+	case err == nil:
+		return userInfo.Name, nil
+	case !kerrors.IsNotFound(err) && !kerrors.IsForbidden(err):
 		return "", err
 	}
-	return userInfo.Name, nil
-}
 
-// getContextNicknameFromConfig returns "namespace/getClusterNicknameFromConfig/username(as known by the server)".  This allows tab completion for switching projects/context
-// to work easily.  First tab is the most selective on project.  Second stanza in the next most selective on cluster name.  The chances of a user trying having
-// one projects on a single server that they want to operate against with two identities is low, so username is last.
-func getContextNicknameFromConfig(namespace string, clientCfg *restclient.Config) (string, error) {
-	userPartOfNick, err := getUserPartOfNickname(clientCfg)
-	if err != nil {
+	review, err := reviews.SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	switch {
+	case err == nil && len(review.Status.UserInfo.Username) > 0:
+		return review.Status.UserInfo.Username, nil
+	case err != nil && !kerrors.IsNotFound(err) && !kerrors.IsForbidden(err):
 		return "", err
 	}
 
-	clusterNick, err := getClusterNicknameFromConfig(clientCfg)
-	if err != nil {
-		return "", err
+	switch {
+	case len(clientCfg.Impersonate.UserName) > 0:
+		return clientCfg.Impersonate.UserName, nil
+	case len(clientCfg.Username) > 0:
+		return clientCfg.Username, nil
 	}
-
-	return namespace + "/" + clusterNick + "/" + userPartOfNick, nil
+	return "unknown-" + credentialFingerprint(clientCfg)[:12], nil
 }
 
-// CreateConfig takes a clientCfg and builds a config (kubeconfig style) from it.
-func createConfig(namespace string, clientCfg *restclient.Config) (*clientcmdapi.Config, error) {
-	clusterNick, err := getClusterNicknameFromConfig(clientCfg)
-	if err != nil {
-		return nil, err
+// credentialFingerprint is a hash of the server and everything that decides who clientCfg authenticates as.  It is
+// safe to show, the credentials cannot be recovered from it.
+func credentialFingerprint(clientCfg *restclient.Config) string {
+	hash := sha256.New()
+	for _, value := range []string{
+		clientCfg.Host,
+		clientCfg.BearerToken,
+		clientCfg.BearerTokenFile,
+		clientCfg.Username,
+		clientCfg.Password,
+		clientCfg.CertFile,
+		string(clientCfg.CertData),
+		clientCfg.Impersonate.UserName,
+	} {
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+	if clientCfg.ExecProvider != nil {
+		hash.Write([]byte(clientCfg.ExecProvider.Command))
+		for _, arg := range clientCfg.ExecProvider.Args {
+			hash.Write([]byte{0})
+			hash.Write([]byte(arg))
+		}
 	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
 
-	userNick, err := getUserNicknameFromConfig(clientCfg)
+// CreateConfig takes a clientCfg and builds a config (kubeconfig style) from it, for the user the server knows the
+// credentials of clientCfg as.
+func CreateConfig(namespace string, clientCfg *restclient.Config) (*clientcmdapi.Config, error) {
+	userName, err := getUserPartOfNickname(clientCfg)
 	if err != nil {
 		return nil, err
 	}
+	return BuildKubeConfig(namespace, userName, clientCfg)
+}
 
-	contextNick, err := getContextNicknameFromConfig(namespace, clientCfg)
+// BuildKubeConfig builds a config (kubeconfig style) from clientCfg, with a single context for the namespace and
+// userName.  Everything the kubeconfig format can hold is kept, including exec and auth provider plugins,
+// impersonation and the proxy of the server.  The nicknames are those oc login uses: the cluster is
+// host:port with .'s replaced by -'s, the user is userName/cluster, and the context is namespace/cluster/userName.
+func BuildKubeConfig(namespace, userName string, clientCfg *restclient.Config) (*clientcmdapi.Config, error) {
+	clusterNick, err := getClusterNicknameFromConfig(clientCfg)
 	if err != nil {
 		return nil, err
 	}
+	userNick := userName + "/" + clusterNick
+	contextNick := namespace + "/" + clusterNick + "/" + userName
 
 	config := clientcmdapi.NewConfig()
 
 	credentials := clientcmdapi.NewAuthInfo()
 	credentials.Token = clientCfg.BearerToken
+	credentials.TokenFile = clientCfg.BearerTokenFile
+	credentials.Username = clientCfg.Username
+	credentials.Password = clientCfg.Password
 	credentials.ClientCertificate = clientCfg.TLSClientConfig.CertFile
 	if len(credentials.ClientCertificate) == 0 {
 		credentials.ClientCertificateData = clientCfg.TLSClientConfig.CertData
@@ -121,6 +172,19 @@ func createConfig(namespace string, clientCfg *restclient.Config) (*clientcmdapi
 	if len(credentials.ClientKey) == 0 {
 		credentials.ClientKeyData = clientCfg.TLSClientConfig.KeyData
 	}
+	// exec plugins are how clusters with an external OIDC provider authenticate, there may be no token at all.
+	if clientCfg.ExecProvider != nil {
+		credentials.Exec = clientCfg.ExecProvider.DeepCopy()
+	}
+	if clientCfg.AuthProvider != nil {
+		credentials.AuthProvider = clientCfg.AuthProvider.DeepCopy()
+	}
+	credentials.Impersonate = clientCfg.Impersonate.UserName
+	credentials.ImpersonateUID = clientCfg.Impersonate.UID
+	credentials.ImpersonateGroups = append([]string(nil), clientCfg.Impersonate.Groups...)
+	for key, values := range clientCfg.Impersonate.Extra {
+		credentials.ImpersonateUserExtra[key] = append([]string(nil), values...)
+	}
 	config.AuthInfos[userNick] = credentials
 
 	cluster := clientcmdapi.NewCluster()
@@ -130,6 +194,11 @@ func createConfig(namespace string, clientCfg *restclient.Config) (*clientcmdapi
 		cluster.CertificateAuthorityData = clientCfg.CAData
 	}
 	cluster.InsecureSkipTLSVerify = clientCfg.Insecure
+	cluster.TLSServerName = clientCfg.ServerName
+	cluster.ProxyURL, err = getProxyURLFromConfig(clientCfg)
+	if err != nil {
+		return nil, err
+	}
 	config.Clusters[clusterNick] = cluster
 
 	context := clientcmdapi.NewContext()
@@ -141,3 +210,206 @@ func createConfig(namespace string, clientCfg *restclient.Config) (*clientcmdapi
 
 	return config, nil
 }
+
+// getProxyURLFromConfig returns the proxy clientCfg uses for its server, or empty when it has no proxy of its own.  The
+// environment is not a proxy of its own, whoever uses the kubeconfig has their own environment.
+func getProxyURLFromConfig(clientCfg *restclient.Config) (string, error) {
+	if clientCfg.Proxy == nil {
+		return "", nil
+	}
+	u, err := url.Parse(clientCfg.Host)
+	if err != nil {
+		return "", err
+	}
+	proxyURL, err := clientCfg.Proxy(&http.Request{URL: u})
+	if err != nil || proxyURL == nil {
+		return "", err
+	}
+	return proxyURL.String(), nil
+}
+
+// kubeConfigLockTimeout is how long to wait for another writer of a kubeconfig to finish.
+const kubeConfigLockTimeout = 30 * time.Second
+
+// MergeKubeConfig merges the clusters, users and contexts of config into the kubeconfig file at path, creating it when
+// it does not exist.  An entry that is already in the file under the same name is shared, and one that conflicts with
+// a different entry of that name is added under a new name, with the contexts of config updated to match.  The current
+// context of the file is kept unless it has none.  It returns the name the current context of config was merged as,
+// for SwitchKubeConfigContext.
+func MergeKubeConfig(path string, config *clientcmdapi.Config) (string, error) {
+	currentContext := ""
+	err := modifyKubeConfig(path, func(existing *clientcmdapi.Config) error {
+		clusters := map[string]string{}
+		for name, cluster := range config.Clusters {
+			clusters[name] = mergeKubeConfigEntry(existing.Clusters, name, cluster)
+		}
+		authInfos := map[string]string{}
+		for name, authInfo := range config.AuthInfos {
+			authInfos[name] = mergeKubeConfigEntry(existing.AuthInfos, name, authInfo)
+		}
+		contexts := map[string]string{}
+		for name, context := range config.Contexts {
+			context = context.DeepCopy()
+			if renamed, ok := clusters[context.Cluster]; ok {
+				context.Cluster = renamed
+			}
+			if renamed, ok := authInfos[context.AuthInfo]; ok {
+				context.AuthInfo = renamed
+			}
+			contexts[name] = mergeKubeConfigEntry(existing.Contexts, name, context)
+		}
+		currentContext = contexts[config.CurrentContext]
+		if len(existing.CurrentContext) == 0 {
+			existing.CurrentContext = currentContext
+		}
+		return nil
+	})
+	return currentContext, err
+}
+
+// mergeKubeConfigEntry adds entry to entries under name, or under the first free name-N when a different entry has
+// the name, and returns the name it is under.
+func mergeKubeConfigEntry[T any](entries map[string]*T, name string, entry *T) string {
+	for i := 1; ; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", name, i)
+		}
+		existing, ok := entries[candidate]
+		if !ok {
+			entries[candidate] = entry
+			return candidate
+		}
+		if sameKubeConfigEntry(existing, entry) {
+			return candidate
+		}
+	}
+}
+
+// sameKubeConfigEntry compares entries of a kubeconfig as they would be written, so where an entry was read from, and
+// empty fields that are not written at all, do not make entries different.
+func sameKubeConfigEntry(a, b interface{}) bool {
+	aContent, aErr := kubeConfigEntryContent(a)
+	bContent, bErr := kubeConfigEntryContent(b)
+	return aErr == nil && bErr == nil && aContent == bContent
+}
+
+func kubeConfigEntryContent(entry interface{}) (string, error) {
+	config := clientcmdapi.NewConfig()
+	switch entry := entry.(type) {
+	case *clientcmdapi.Cluster:
+		config.Clusters["entry"] = entry
+	case *clientcmdapi.AuthInfo:
+		config.AuthInfos["entry"] = entry
+	case *clientcmdapi.Context:
+		config.Contexts["entry"] = entry
+	default:
+		return "", fmt.Errorf("%T is not an entry of a kubeconfig", entry)
+	}
+	content, err := clientcmd.Write(*config)
+	return string(content), err
+}
+
+// SwitchKubeConfigContext makes context the current context of the kubeconfig file at path.
+func SwitchKubeConfigContext(path, context string) error {
+	return modifyKubeConfig(path, func(config *clientcmdapi.Config) error {
+		if _, ok := config.Contexts[context]; !ok {
+			return fmt.Errorf("kubeconfig %s has no context %q", path, context)
+		}
+		config.CurrentContext = context
+		return nil
+	})
+}
+
+// RenameKubeConfigContext renames a context of the kubeconfig file at path, and the current context with it.
+func RenameKubeConfigContext(path, from, to string) error {
+	return modifyKubeConfig(path, func(config *clientcmdapi.Config) error {
+		context, ok := config.Contexts[from]
+		if !ok {
+			return fmt.Errorf("kubeconfig %s has no context %q", path, from)
+		}
+		if _, ok := config.Contexts[to]; ok {
+			return fmt.Errorf("kubeconfig %s already has a context %q", path, to)
+		}
+		delete(config.Contexts, from)
+		config.Contexts[to] = context
+		if config.CurrentContext == from {
+			config.CurrentContext = to
+		}
+		return nil
+	})
+}
+
+// modifyKubeConfig loads the kubeconfig file at path, modifies it and writes it back.  The file is locked the way oc
+// and kubectl lock it, so they and other tests do not lose each other's changes.  Before it is replaced, the file is
+// copied to path.bak, and it is replaced with a rename, so nothing reading it sees half of it.
+func modifyKubeConfig(path string, modify func(*clientcmdapi.Config) error) error {
+	unlock, err := lockKubeConfig(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	config := clientcmdapi.NewConfig()
+	original, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if config, err = clientcmd.Load(original); err != nil {
+			return fmt.Errorf("unable to read kubeconfig %s: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	if err := modify(config); err != nil {
+		return err
+	}
+	content, err := clientcmd.Write(*config)
+	if err != nil {
+		return err
+	}
+
+	if original != nil {
+		if err := os.WriteFile(path+".bak", original, 0600); err != nil {
+			return fmt.Errorf("unable to back up kubeconfig %s: %w", path, err)
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lockKubeConfig creates path.lock, which is how client-go locks a kubeconfig, and returns a func that removes it.
+func lockKubeConfig(path string) (func(), error) {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	err := wait.PollUntilContextTimeout(context.Background(), 100*time.Millisecond, kubeConfigLockTimeout, true, func(ctx context.Context) (bool, error) {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL, 0)
+		switch {
+		case err == nil:
+			return true, f.Close()
+		case os.IsExist(err):
+			return false, nil
+		default:
+			return false, err
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock kubeconfig %s, remove %s if nothing is writing it: %w", path, lockPath, err)
+	}
+	return func() {
+		os.Remove(lockPath)
+	}, nil
+}
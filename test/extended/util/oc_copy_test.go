@@ -0,0 +1,258 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	userv1 "github.com/openshift/api/user/v1"
+	userv1typedclient "github.com/openshift/client-go/user/clientset/versioned/typed/user/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestBuildKubeConfig(t *testing.T) {
+	proxyURL, err := url.Parse("http://proxy.example.com:3128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCfg := &restclient.Config{
+		Host: "https://api.cluster.example.com:6443",
+		TLSClientConfig: restclient.TLSClientConfig{
+			CAData:     []byte("ca"),
+			ServerName: "kubernetes",
+		},
+		ExecProvider: &clientcmdapi.ExecConfig{
+			APIVersion:      "client.authentication.k8s.io/v1",
+			Command:         "oc",
+			Args:            []string{"get-token", "--issuer-url=https://issuer.example.com"},
+			Env:             []clientcmdapi.ExecEnvVar{{Name: "HOME", Value: "/tmp"}},
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		},
+		Impersonate: restclient.ImpersonationConfig{
+			UserName: "e2e-user",
+			UID:      "1234",
+			Groups:   []string{"system:authenticated"},
+			Extra:    map[string][]string{"scopes": {"user:full"}},
+		},
+		Proxy: http.ProxyURL(proxyURL),
+	}
+
+	config, err := BuildKubeConfig("e2e-test", "e2e-user", clientCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "e2e-test/api-cluster-example-com:6443/e2e-user" {
+		t.Errorf("unexpected context %q", config.CurrentContext)
+	}
+
+	// the kubeconfig must survive being written and read back, which is how the CLI uses it.
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := clientcmd.WriteToFile(*config, path); err != nil {
+		t.Fatal(err)
+	}
+	readCfg, err := clientcmd.BuildConfigFromFlags("", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if readCfg.Host != clientCfg.Host || readCfg.ServerName != "kubernetes" || string(readCfg.CAData) != "ca" {
+		t.Errorf("expected the server to be kept, got %v %v %v", readCfg.Host, readCfg.ServerName, string(readCfg.CAData))
+	}
+	if readCfg.ExecProvider == nil {
+		t.Fatalf("expected the exec plugin to be kept")
+	}
+	if readCfg.ExecProvider.Command != "oc" || !reflect.DeepEqual(readCfg.ExecProvider.Args, clientCfg.ExecProvider.Args) ||
+		!reflect.DeepEqual(readCfg.ExecProvider.Env, clientCfg.ExecProvider.Env) {
+		t.Errorf("expected %v, got %v", clientCfg.ExecProvider, readCfg.ExecProvider)
+	}
+	if !reflect.DeepEqual(readCfg.Impersonate, clientCfg.Impersonate) {
+		t.Errorf("expected %v, got %v", clientCfg.Impersonate, readCfg.Impersonate)
+	}
+	if readCfg.Proxy == nil {
+		t.Fatalf("expected the proxy to be kept")
+	}
+	readProxyURL, err := readCfg.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "api.cluster.example.com:6443"}})
+	if err != nil || readProxyURL.String() != proxyURL.String() {
+		t.Errorf("expected %v, got %v: %v", proxyURL, readProxyURL, err)
+	}
+
+	// the environment is not the proxy of the config.
+	clientCfg.Proxy = nil
+	config, err = BuildKubeConfig("e2e-test", "e2e-user", clientCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cluster := range config.Clusters {
+		if len(cluster.ProxyURL) > 0 {
+			t.Errorf("expected no proxy, got %v", cluster.ProxyURL)
+		}
+	}
+}
+
+// fakeUsers answers users/~ with the user, or with the error.
+type fakeUsers struct {
+	userv1typedclient.UserInterface
+	user *userv1.User
+	err  error
+}
+
+func (f *fakeUsers) Users() userv1typedclient.UserInterface {
+	return f
+}
+
+func (f *fakeUsers) Get(ctx context.Context, name string, options metav1.GetOptions) (*userv1.User, error) {
+	return f.user, f.err
+}
+
+func TestResolveUserName(t *testing.T) {
+	notServed := kerrors.NewNotFound(schema.GroupResource{Group: "user.openshift.io", Resource: "users"}, "~")
+	reviewed := func(username string) *fake.Clientset {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "selfsubjectreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+			review := &authenticationv1.SelfSubjectReview{}
+			review.Status.UserInfo.Username = username
+			return true, review, nil
+		})
+		return client
+	}
+	token := "sha256~secret-token"
+
+	tests := []struct {
+		name     string
+		users    *fakeUsers
+		reviews  *fake.Clientset
+		config   *restclient.Config
+		expected string
+	}{
+		{
+			name:     "openshift user",
+			users:    &fakeUsers{user: &userv1.User{ObjectMeta: metav1.ObjectMeta{Name: "e2e-user"}}},
+			reviews:  reviewed("ignored"),
+			config:   &restclient.Config{BearerToken: token},
+			expected: "e2e-user",
+		},
+		{
+			name:     "kube user",
+			users:    &fakeUsers{err: notServed},
+			reviews:  reviewed("system:serviceaccount:e2e-test:default"),
+			config:   &restclient.Config{BearerToken: token},
+			expected: "system:serviceaccount:e2e-test:default",
+		},
+		{
+			name:     "basic auth",
+			users:    &fakeUsers{err: notServed},
+			reviews:  reviewed(""),
+			config:   &restclient.Config{Username: "admin", Password: "secret"},
+			expected: "admin",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, err := resolveUserName(context.Background(), test.users, test.reviews.AuthenticationV1(), test.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, name)
+			}
+		})
+	}
+
+	// a token nobody will name is never the name.
+	name, err := resolveUserName(context.Background(), &fakeUsers{err: notServed}, reviewed("").AuthenticationV1(), &restclient.Config{BearerToken: token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(name, "secret") || !strings.HasPrefix(name, "unknown-") {
+		t.Errorf("expected a name without the token, got %q", name)
+	}
+	other, err := resolveUserName(context.Background(), &fakeUsers{err: notServed}, reviewed("").AuthenticationV1(), &restclient.Config{BearerToken: "sha256~other-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name == other {
+		t.Errorf("expected different tokens to have different names, got %q", name)
+	}
+}
+
+func TestMergeKubeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	admin, err := BuildKubeConfig("default", "admin", &restclient.Config{Host: "https://api.cluster.example.com:6443", BearerToken: "admin-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := MergeKubeConfig(path, admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged != admin.CurrentContext {
+		t.Errorf("expected %q, got %q", admin.CurrentContext, merged)
+	}
+	// merging the same thing again changes nothing.
+	if merged, err = MergeKubeConfig(path, admin); err != nil || merged != admin.CurrentContext {
+		t.Errorf("expected %q, got %q: %v", admin.CurrentContext, merged, err)
+	}
+
+	// the same user with a new token conflicts with the user in the file, and must not replace it.
+	rotated, err := BuildKubeConfig("default", "admin", &restclient.Config{Host: "https://api.cluster.example.com:6443", BearerToken: "rotated-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err = MergeKubeConfig(path, rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged != admin.CurrentContext+"-2" {
+		t.Errorf("expected the conflicting context to be renamed, got %q", merged)
+	}
+
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Clusters) != 1 || len(config.AuthInfos) != 2 || len(config.Contexts) != 2 {
+		t.Fatalf("expected the cluster to be shared and the rest added, got %v %v %v", config.Clusters, config.AuthInfos, config.Contexts)
+	}
+	if config.CurrentContext != admin.CurrentContext {
+		t.Errorf("expected the current context to be kept, got %q", config.CurrentContext)
+	}
+	if token := config.AuthInfos[config.Contexts[merged].AuthInfo].Token; token != "rotated-token" {
+		t.Errorf("expected the renamed context to use the new user, got %q", token)
+	}
+	if _, err := clientcmd.LoadFromFile(path + ".bak"); err != nil {
+		t.Errorf("expected a backup: %v", err)
+	}
+
+	if err := SwitchKubeConfigContext(path, merged); err != nil {
+		t.Fatal(err)
+	}
+	if err := RenameKubeConfigContext(path, merged, "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RenameKubeConfigContext(path, "rotated", admin.CurrentContext); err == nil {
+		t.Errorf("expected renaming onto another context to fail")
+	}
+	if err := SwitchKubeConfigContext(path, "missing"); err == nil {
+		t.Errorf("expected switching to a missing context to fail")
+	}
+	config, err = clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "rotated" {
+		t.Errorf("expected the current context to follow the rename, got %q", config.CurrentContext)
+	}
+}
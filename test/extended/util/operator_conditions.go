@@ -0,0 +1,149 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/test/e2e/framework"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// operatorConditionPollInterval is how often the clusteroperator is read while waiting.  Tests shorten it.
+var operatorConditionPollInterval = 5 * time.Second
+
+// WaitForClusterOperatorAvailable waits for the clusteroperator to be Available, see
+// WaitForCOConditionWithProgressLogging.
+func WaitForClusterOperatorAvailable(ctx context.Context, configClient configv1client.Interface, recorder monitorapi.RecorderWriter, operatorName string, timeout time.Duration) (*configv1.ClusterOperator, error) {
+	return WaitForCOConditionWithProgressLogging(ctx, configClient, recorder, operatorName, configv1.OperatorAvailable, configv1.ConditionTrue, timeout)
+}
+
+// WaitForCOConditionWithProgressLogging waits for the condition of the clusteroperator to have the status, and returns
+// the clusteroperator that has it.  Every transition of a condition of the operator seen while waiting is logged and,
+// when recorder is not nil, every state of a condition is recorded as an interval of the operator.  A test that times
+// out waiting has the history of the operator on its timeline, next to what the rest of the cluster was doing.
+func WaitForCOConditionWithProgressLogging(ctx context.Context, configClient configv1client.Interface, recorder monitorapi.RecorderWriter, operatorName string, conditionType configv1.ClusterStatusConditionType, status configv1.ConditionStatus, timeout time.Duration) (*configv1.ClusterOperator, error) {
+	tracker := newOperatorConditionTracker(operatorName, recorder)
+	defer tracker.finish(time.Now())
+
+	framework.Logf("Waiting up to %v for clusteroperator/%s to be %s=%s", timeout, operatorName, conditionType, status)
+	var operator *configv1.ClusterOperator
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, operatorConditionPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := configClient.ConfigV1().ClusterOperators().Get(ctx, operatorName, metav1.GetOptions{})
+		if err != nil {
+			// the apiserver may be rolling out, which is often why the test waits.
+			lastErr = err
+			return false, nil
+		}
+		operator = current
+		tracker.observe(current, time.Now())
+		for _, condition := range current.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == status {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err == nil {
+		framework.Logf("clusteroperator/%s is %s=%s", operatorName, conditionType, status)
+		return operator, nil
+	}
+
+	if operator == nil {
+		return nil, fmt.Errorf("clusteroperator/%s could not be read within %v: %v", operatorName, timeout, lastErr)
+	}
+	return operator, fmt.Errorf("clusteroperator/%s did not become %s=%s within %v, its conditions are:\n%s", operatorName, conditionType, status, timeout, tracker.describe())
+}
+
+type observedOperatorCondition struct {
+	condition configv1.ClusterOperatorStatusCondition
+	since     time.Time
+}
+
+// operatorConditionTracker keeps the conditions of an operator as they were last seen.
+type operatorConditionTracker struct {
+	operatorName string
+	recorder     monitorapi.RecorderWriter
+	conditions   map[configv1.ClusterStatusConditionType]*observedOperatorCondition
+}
+
+func newOperatorConditionTracker(operatorName string, recorder monitorapi.RecorderWriter) *operatorConditionTracker {
+	return &operatorConditionTracker{
+		operatorName: operatorName,
+		recorder:     recorder,
+		conditions:   map[configv1.ClusterStatusConditionType]*observedOperatorCondition{},
+	}
+}
+
+// observe logs and records the conditions of operator that changed since it was last seen.
+func (t *operatorConditionTracker) observe(operator *configv1.ClusterOperator, now time.Time) {
+	for _, condition := range operator.Status.Conditions {
+		previous, ok := t.conditions[condition.Type]
+		if ok && previous.condition.Status == condition.Status && previous.condition.Reason == condition.Reason {
+			previous.condition = condition
+			continue
+		}
+		if ok {
+			framework.Logf("clusteroperator/%s %s changed from %s to %s (%s): %s", t.operatorName, condition.Type, previous.condition.Status, condition.Status, condition.Reason, condition.Message)
+			t.record(previous, now)
+		} else {
+			framework.Logf("clusteroperator/%s is %s=%s (%s): %s", t.operatorName, condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+		t.conditions[condition.Type] = &observedOperatorCondition{condition: condition, since: now}
+	}
+}
+
+// finish records the conditions as they were last seen.
+func (t *operatorConditionTracker) finish(now time.Time) {
+	for _, observed := range t.conditions {
+		t.record(observed, now)
+	}
+}
+
+func (t *operatorConditionTracker) record(observed *observedOperatorCondition, to time.Time) {
+	if t.recorder == nil {
+		return
+	}
+	condition := observed.condition
+	level := monitorapi.Info
+	switch {
+	case condition.Type == configv1.OperatorAvailable && condition.Status == configv1.ConditionFalse,
+		condition.Type == configv1.OperatorDegraded && condition.Status == configv1.ConditionTrue:
+		level = monitorapi.Error
+	case condition.Type == configv1.OperatorProgressing && condition.Status == configv1.ConditionTrue:
+		level = monitorapi.Warning
+	}
+	message := monitorapi.NewMessage().
+		WithAnnotations(map[monitorapi.AnnotationKey]string{
+			monitorapi.AnnotationCondition: string(condition.Type),
+			monitorapi.AnnotationStatus:    string(condition.Status),
+		}).
+		HumanMessagef("%s", condition.Message)
+	if len(condition.Reason) > 0 {
+		message = message.Reason(monitorapi.IntervalReason(condition.Reason))
+	}
+	t.recorder.AddIntervals(monitorapi.NewInterval(monitorapi.SourceOperatorConditionWait, level).
+		Locator(monitorapi.NewLocator().ClusterOperator(t.operatorName)).
+		Message(message).
+		Display().
+		Build(observed.since, to))
+}
+
+// describe lists the conditions as they were last seen, sorted by type.
+func (t *operatorConditionTracker) describe() string {
+	lines := []string{}
+	for _, observed := range t.conditions {
+		condition := observed.condition
+		lines = append(lines, fmt.Sprintf("  %s=%s since %s (%s): %s", condition.Type, condition.Status, condition.LastTransitionTime.UTC().Format(time.RFC3339), condition.Reason, condition.Message))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	fakeconfigv1client "github.com/openshift/client-go/config/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func operatorWithConditions(conditions ...configv1.ClusterOperatorStatusCondition) *configv1.ClusterOperator {
+	return &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress"},
+		Status:     configv1.ClusterOperatorStatus{Conditions: conditions},
+	}
+}
+
+// sequencedClient returns the operators in order, then the last one forever.
+func sequencedClient(operators ...*configv1.ClusterOperator) *fakeconfigv1client.Clientset {
+	client := fakeconfigv1client.NewSimpleClientset()
+	gets := 0
+	client.PrependReactor("get", "clusteroperators", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		operator := operators[len(operators)-1]
+		if gets < len(operators) {
+			operator = operators[gets]
+		}
+		gets++
+		return true, operator.DeepCopy(), nil
+	})
+	return client
+}
+
+func TestWaitForCOConditionWithProgressLogging(t *testing.T) {
+	operatorConditionPollInterval = time.Millisecond
+	defer func() { operatorConditionPollInterval = 5 * time.Second }()
+
+	unavailable := configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorAvailable, Status: configv1.ConditionFalse, Reason: "RouterDown", Message: "no routers are running"}
+	progressing := configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorProgressing, Status: configv1.ConditionTrue, Reason: "Rolling"}
+	available := configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue, Reason: "AsExpected"}
+	client := sequencedClient(
+		operatorWithConditions(unavailable, progressing),
+		operatorWithConditions(unavailable, progressing),
+		operatorWithConditions(available, progressing),
+	)
+
+	recorder := &intervalRecorder{}
+	operator, err := WaitForClusterOperatorAvailable(context.Background(), client, recorder, "ingress", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operator.Status.Conditions[0].Status != configv1.ConditionTrue {
+		t.Errorf("expected the available operator to be returned, got %#v", operator.Status)
+	}
+
+	// unavailable, then available, and progressing the whole time.
+	states := []string{}
+	for _, interval := range recorder.intervals {
+		if interval.Source != monitorapi.SourceOperatorConditionWait || interval.Locator.Keys[monitorapi.LocatorClusterOperatorKey] != "ingress" {
+			t.Errorf("unexpected interval %v", interval)
+		}
+		condition := monitorapi.GetOperatorConditionStatus(interval)
+		states = append(states, string(condition.Type)+"="+string(condition.Status))
+	}
+	if len(states) != 3 || states[0] != "Available=False" || !strings.Contains(strings.Join(states, ","), "Available=True") || !strings.Contains(strings.Join(states, ","), "Progressing=True") {
+		t.Errorf("unexpected intervals %v", states)
+	}
+	if recorder.intervals[0].Level != monitorapi.Error || recorder.intervals[0].Message.Reason != "RouterDown" {
+		t.Errorf("expected the operator being unavailable to be an error, got %v", recorder.intervals[0])
+	}
+
+	// timing out describes the conditions, and works without a recorder.
+	client = sequencedClient(operatorWithConditions(unavailable))
+	operator, err = WaitForCOConditionWithProgressLogging(context.Background(), client, nil, "ingress", configv1.OperatorAvailable, configv1.ConditionTrue, 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "Available=False") || !strings.Contains(err.Error(), "no routers are running") {
+		t.Errorf("expected the conditions in the error, got %v", err)
+	}
+	if operator == nil {
+		t.Error("expected the last seen operator on timeout")
+	}
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	kclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultServiceAccountTokenLifetime is how long the tokens of scoped service accounts are valid for when the caller
+// does not say.  It outlasts any single test.
+const DefaultServiceAccountTokenLifetime = time.Hour
+
+// minServiceAccountTokenLifetime is the shortest lifetime the TokenRequest API accepts.
+const minServiceAccountTokenLifetime = 10 * time.Minute
+
+// CreateScopedServiceAccount creates a service account in the namespace that may do only what the rules allow there,
+// and requests a token for it that expires after tokenLifetime.  It returns a client config and a kubeconfig that
+// authenticate as the service account, and a func that deletes everything it created.  The func may be called more
+// than once.  If an error is returned, nothing is left behind.
+func CreateScopedServiceAccount(ctx context.Context, adminClient kclientset.Interface, adminConfig *rest.Config, namespace, name string, rules []rbacv1.PolicyRule, tokenLifetime time.Duration) (*rest.Config, *clientcmdapi.Config, func(context.Context) error, error) {
+	if tokenLifetime == 0 {
+		tokenLifetime = DefaultServiceAccountTokenLifetime
+	}
+	if tokenLifetime < minServiceAccountTokenLifetime {
+		return nil, nil, nil, fmt.Errorf("service account tokens must live at least %v, got %v", minServiceAccountTokenLifetime, tokenLifetime)
+	}
+
+	created := []func(context.Context) error{}
+	cleanup := func(ctx context.Context) error {
+		errs := []error{}
+		// the binding goes first, so the service account never has the rules of a role left behind.
+		for i := len(created) - 1; i >= 0; i-- {
+			if err := created[i](ctx); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+		}
+		return utilerrors.NewAggregate(errs)
+	}
+	fail := func(err error) (*rest.Config, *clientcmdapi.Config, func(context.Context) error, error) {
+		if cleanupErr := cleanup(ctx); cleanupErr != nil {
+			return nil, nil, nil, utilerrors.NewAggregate([]error{err, cleanupErr})
+		}
+		return nil, nil, nil, err
+	}
+
+	_, err := adminClient.CoreV1().ServiceAccounts(namespace).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fail(fmt.Errorf("unable to create service account %s/%s: %w", namespace, name, err))
+	}
+	created = append(created, func(ctx context.Context) error {
+		return adminClient.CoreV1().ServiceAccounts(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	})
+
+	if len(rules) > 0 {
+		_, err = adminClient.RbacV1().Roles(namespace).Create(ctx, &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules:      rules,
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fail(fmt.Errorf("unable to create role %s/%s: %w", namespace, name, err))
+		}
+		created = append(created, func(ctx context.Context) error {
+			return adminClient.RbacV1().Roles(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		})
+
+		_, err = adminClient.RbacV1().RoleBindings(namespace).Create(ctx, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Subjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Namespace: namespace, Name: name},
+			},
+			RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fail(fmt.Errorf("unable to create role binding %s/%s: %w", namespace, name, err))
+		}
+		created = append(created, func(ctx context.Context) error {
+			return adminClient.RbacV1().RoleBindings(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		})
+	}
+
+	expirationSeconds := int64(tokenLifetime.Seconds())
+	tokenRequest, err := adminClient.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fail(fmt.Errorf("unable to request a token for service account %s/%s: %w", namespace, name, err))
+	}
+
+	saClientConfig := rest.AnonymousClientConfig(turnOffRateLimiting(rest.CopyConfig(adminConfig)))
+	saClientConfig.BearerToken = tokenRequest.Status.Token
+
+	// the server knows the service account by its username, there is no need to ask.
+	kubeConfig, err := BuildKubeConfig(namespace, serviceaccount.MakeUsername(namespace, name), saClientConfig)
+	if err != nil {
+		return fail(err)
+	}
+
+	return saClientConfig, kubeConfig, cleanup, nil
+}
+
+// CreateScopedServiceAccount creates a service account in the test namespace that may do only what the rules allow
+// there, and returns a client config and a kubeconfig that authenticate as it.  Everything created is deleted with the
+// test namespace.
+func (c *CLI) CreateScopedServiceAccount(name string, rules ...rbacv1.PolicyRule) (*rest.Config, *clientcmdapi.Config) {
+	saClientConfig, kubeConfig, _, err := CreateScopedServiceAccount(context.Background(), c.AdminKubeClient(), c.AdminConfig(), c.Namespace(), name, rules, DefaultServiceAccountTokenLifetime)
+	if err != nil {
+		FatalErr(err)
+	}
+	return saClientConfig, kubeConfig
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clientgotesting "k8s.io/client-go/testing"
+)
+
+func TestCreateScopedServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	adminConfig := &rest.Config{Host: "https://api.cluster.example.com:6443", BearerToken: "admin-token"}
+	rules := []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}}}
+
+	client := fake.NewSimpleClientset()
+	var expirationSeconds int64
+	client.PrependReactor("create", "serviceaccounts", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		tokenRequest := action.(clientgotesting.CreateAction).GetObject().(*authenticationv1.TokenRequest)
+		expirationSeconds = *tokenRequest.Spec.ExpirationSeconds
+		tokenRequest.Status.Token = "scoped-token"
+		return true, tokenRequest, nil
+	})
+
+	saClientConfig, kubeConfig, cleanup, err := CreateScopedServiceAccount(ctx, client, adminConfig, "e2e-test", "reader", rules, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saClientConfig.BearerToken != "scoped-token" || saClientConfig.Host != adminConfig.Host {
+		t.Errorf("expected the token of the service account on the same server, got %v", saClientConfig)
+	}
+	if expirationSeconds != int64(DefaultServiceAccountTokenLifetime.Seconds()) {
+		t.Errorf("expected the default lifetime, got %ds", expirationSeconds)
+	}
+	if kubeConfig.CurrentContext != "e2e-test/api-cluster-example-com:6443/system:serviceaccount:e2e-test:reader" {
+		t.Errorf("unexpected context %q", kubeConfig.CurrentContext)
+	}
+	for _, authInfo := range kubeConfig.AuthInfos {
+		if authInfo.Token != "scoped-token" {
+			t.Errorf("expected the token of the service account, got %q", authInfo.Token)
+		}
+	}
+
+	binding, err := client.RbacV1().RoleBindings("e2e-test").Get(ctx, "reader", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.RoleRef.Kind != "Role" || binding.Subjects[0].Name != "reader" {
+		t.Errorf("expected the service account bound to its role, got %v", binding)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := cleanup(ctx); err != nil {
+			t.Fatalf("expected cleanup to be repeatable, got %v", err)
+		}
+	}
+	if accounts, _ := client.CoreV1().ServiceAccounts("e2e-test").List(ctx, metav1.ListOptions{}); len(accounts.Items) != 0 {
+		t.Errorf("expected the service account to be deleted, got %v", accounts.Items)
+	}
+	if roles, _ := client.RbacV1().Roles("e2e-test").List(ctx, metav1.ListOptions{}); len(roles.Items) != 0 {
+		t.Errorf("expected the role to be deleted, got %v", roles.Items)
+	}
+}
+
+func TestCreateScopedServiceAccountFailure(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "rolebindings", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("forbidden")
+	})
+	rules := []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"pods"}}}
+
+	if _, _, _, err := CreateScopedServiceAccount(ctx, client, &rest.Config{}, "e2e-test", "reader", rules, time.Hour); err == nil {
+		t.Fatalf("expected the failed binding to fail")
+	}
+	if accounts, _ := client.CoreV1().ServiceAccounts("e2e-test").List(ctx, metav1.ListOptions{}); len(accounts.Items) != 0 {
+		t.Errorf("expected nothing left behind, got %v", accounts.Items)
+	}
+	if roles, _ := client.RbacV1().Roles("e2e-test").List(ctx, metav1.ListOptions{}); len(roles.Items) != 0 {
+		t.Errorf("expected nothing left behind, got %v", roles.Items)
+	}
+
+	if _, _, _, err := CreateScopedServiceAccount(ctx, client, &rest.Config{}, "e2e-test", "reader", nil, time.Minute); err == nil {
+		t.Errorf("expected a lifetime the TokenRequest API refuses to fail")
+	}
+}
@@ -0,0 +1,339 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/kubernetes/test/e2e/framework"
+)
+
+// StreamingKind is a kind of session that streams to and from a pod through the apiserver and the kubelet.
+type StreamingKind string
+
+const (
+	StreamingExec        StreamingKind = "exec"
+	StreamingAttach      StreamingKind = "attach"
+	StreamingPortForward StreamingKind = "port-forward"
+)
+
+// StreamingProtocol is how a session is streamed.  Websockets are tried first, the way oc does, and SPDY is used when
+// the server will not upgrade to them.  Port-forwards are always SPDY.
+type StreamingProtocol string
+
+const (
+	StreamingWebSocket StreamingProtocol = "websocket"
+	StreamingSPDY      StreamingProtocol = "spdy"
+)
+
+// DefaultStreamingTimeout is how long a session may take when the harness is not given a timeout.
+const DefaultStreamingTimeout = time.Minute
+
+// StreamingTranscriptEntry is what was read from or written to one stream of a session at once.
+type StreamingTranscriptEntry struct {
+	At     time.Time
+	Stream string
+	Data   string
+}
+
+// StreamingTranscript is everything that went over the streams of a session, in order.
+type StreamingTranscript struct {
+	lock    sync.Mutex
+	entries []StreamingTranscriptEntry
+}
+
+// Writer returns a writer that adds what is written to it to the transcript as the stream.
+func (t *StreamingTranscript) Writer(stream string) io.Writer {
+	return &transcriptWriter{transcript: t, stream: stream}
+}
+
+type transcriptWriter struct {
+	transcript *StreamingTranscript
+	stream     string
+}
+
+func (w *transcriptWriter) Write(p []byte) (int, error) {
+	w.transcript.lock.Lock()
+	defer w.transcript.lock.Unlock()
+	w.transcript.entries = append(w.transcript.entries, StreamingTranscriptEntry{At: time.Now(), Stream: w.stream, Data: string(p)})
+	return len(p), nil
+}
+
+// Entries returns a copy of the entries of the transcript.
+func (t *StreamingTranscript) Entries() []StreamingTranscriptEntry {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]StreamingTranscriptEntry(nil), t.entries...)
+}
+
+// Stream returns everything that went over the stream.
+func (t *StreamingTranscript) Stream(stream string) string {
+	ret := strings.Builder{}
+	for _, entry := range t.Entries() {
+		if entry.Stream == stream {
+			ret.WriteString(entry.Data)
+		}
+	}
+	return ret.String()
+}
+
+func (t *StreamingTranscript) String() string {
+	ret := strings.Builder{}
+	for _, entry := range t.Entries() {
+		fmt.Fprintf(&ret, "%s %s> %s\n", entry.At.Format(time.RFC3339Nano), entry.Stream, strings.TrimRight(entry.Data, "\n"))
+	}
+	return ret.String()
+}
+
+// StreamingSession is what happened in one session with a pod.
+type StreamingSession struct {
+	Kind      StreamingKind
+	Protocol  StreamingProtocol
+	Namespace string
+	Pod       string
+	Container string
+	Start     time.Time
+	End       time.Time
+	// Transcript has the stdin, stdout and stderr of exec and attach sessions.  Port-forwards have the output of the
+	// forwarder, and whatever the probe wrote to the transcript.
+	Transcript *StreamingTranscript
+	Err        error
+}
+
+// StreamingHarness opens exec, attach and port-forward sessions with pods, each bounded by a timeout and captured in
+// a transcript.  When it has a recorder, every session is recorded as a disruption interval of a pod-streaming
+// backend, so sessions failing during an upgrade show up as disruption rather than only as a failed test.
+type StreamingHarness struct {
+	config   *rest.Config
+	client   kubernetes.Interface
+	timeout  time.Duration
+	recorder monitorapi.RecorderWriter
+
+	// newExecutor is replaced by unit tests.
+	newExecutor func(protocol StreamingProtocol, method string, u *url.URL) (remotecommand.Executor, error)
+}
+
+// NewStreamingHarness returns a harness that opens sessions as the user of config, with DefaultStreamingTimeout.
+func NewStreamingHarness(config *rest.Config, client kubernetes.Interface) *StreamingHarness {
+	h := &StreamingHarness{
+		config:  config,
+		client:  client,
+		timeout: DefaultStreamingTimeout,
+	}
+	h.newExecutor = h.executor
+	return h
+}
+
+// WithTimeout returns a copy of the harness whose sessions may take at most timeout.
+func (h *StreamingHarness) WithTimeout(timeout time.Duration) *StreamingHarness {
+	ret := *h
+	ret.timeout = timeout
+	return &ret
+}
+
+// WithRecorder returns a copy of the harness that records every session as an interval.
+func (h *StreamingHarness) WithRecorder(recorder monitorapi.RecorderWriter) *StreamingHarness {
+	ret := *h
+	ret.recorder = recorder
+	return &ret
+}
+
+// Exec runs the command in the container and waits for it to exit.  stdin may be nil.  The error is the error of the
+// session, which includes the command exiting non-zero.
+func (h *StreamingHarness) Exec(ctx context.Context, namespace, pod, container string, command []string, stdin io.Reader) (*StreamingSession, error) {
+	session := h.newSession(StreamingExec, namespace, pod, container)
+	u := h.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("exec").VersionedParams(&corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     stdin != nil,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec).URL()
+
+	options := remotecommand.StreamOptions{
+		Stdout: session.Transcript.Writer("stdout"),
+		Stderr: session.Transcript.Writer("stderr"),
+	}
+	if stdin != nil {
+		options.Stdin = io.TeeReader(stdin, session.Transcript.Writer("stdin"))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	session.Err = h.stream(ctx, session, u, options)
+	return h.finish(session)
+}
+
+// Attach attaches to the output of the container for duration, which must be shorter than the timeout of the harness.
+// The session succeeds when it lasts for duration or the container exits.
+func (h *StreamingHarness) Attach(ctx context.Context, namespace, pod, container string, duration time.Duration) (*StreamingSession, error) {
+	session := h.newSession(StreamingAttach, namespace, pod, container)
+	u := h.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("attach").VersionedParams(&corev1.PodAttachOptions{
+		Container: container,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec).URL()
+
+	options := remotecommand.StreamOptions{
+		Stdout: session.Transcript.Writer("stdout"),
+		Stderr: session.Transcript.Writer("stderr"),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	attachCtx, detach := context.WithTimeout(ctx, duration)
+	defer detach()
+	session.Err = h.stream(attachCtx, session, u, options)
+	// detaching after duration is the point of the session, running out of the timeout is not.
+	if errors.Is(session.Err, context.DeadlineExceeded) && ctx.Err() == nil {
+		session.Err = nil
+	}
+	return h.finish(session)
+}
+
+// PortForward forwards a local port to the port of the pod, and calls probe with the local port once it is ready.  The
+// session fails when the forward cannot be established or the probe fails.
+func (h *StreamingHarness) PortForward(ctx context.Context, namespace, pod string, port int, probe func(ctx context.Context, localPort uint16, transcript *StreamingTranscript) error) (*StreamingSession, error) {
+	session := h.newSession(StreamingPortForward, namespace, pod, "")
+	session.Protocol = StreamingSPDY
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	session.Err = h.portForward(ctx, session, port, probe)
+	return h.finish(session)
+}
+
+func (h *StreamingHarness) portForward(ctx context.Context, session *StreamingSession, port int, probe func(ctx context.Context, localPort uint16, transcript *StreamingTranscript) error) error {
+	u := h.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(session.Namespace).Name(session.Pod).SubResource("portforward").URL()
+	transport, upgrader, err := spdy.RoundTripperFor(h.config)
+	if err != nil {
+		return err
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", u)
+
+	stopCh, readyCh := make(chan struct{}), make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, []string{fmt.Sprintf("0:%d", port)}, stopCh, readyCh,
+		session.Transcript.Writer("forwarder"), session.Transcript.Writer("forwarder-errors"))
+	if err != nil {
+		return err
+	}
+	forwardErrCh := make(chan error, 1)
+	go func() {
+		forwardErrCh <- forwarder.ForwardPorts()
+	}()
+	defer func() {
+		close(stopCh)
+		<-forwardErrCh
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-forwardErrCh:
+		// put it back for the deferred wait.
+		forwardErrCh <- err
+		return fmt.Errorf("unable to forward port %d: %w", port, err)
+	case <-ctx.Done():
+		return fmt.Errorf("port %d was not forwarded: %w", port, ctx.Err())
+	}
+	ports, err := forwarder.GetPorts()
+	if err != nil {
+		return err
+	}
+	return probe(ctx, ports[0].Local, session.Transcript)
+}
+
+func (h *StreamingHarness) newSession(kind StreamingKind, namespace, pod, container string) *StreamingSession {
+	return &StreamingSession{
+		Kind:       kind,
+		Namespace:  namespace,
+		Pod:        pod,
+		Container:  container,
+		Start:      time.Now(),
+		Transcript: &StreamingTranscript{},
+	}
+}
+
+// stream streams over websockets, and over SPDY when the server will not upgrade to websockets.
+func (h *StreamingHarness) stream(ctx context.Context, session *StreamingSession, u *url.URL, options remotecommand.StreamOptions) error {
+	session.Protocol = StreamingWebSocket
+	executor, err := h.newExecutor(StreamingWebSocket, "GET", u)
+	if err != nil {
+		return err
+	}
+	err = executor.StreamWithContext(ctx, options)
+	if !httpstream.IsUpgradeFailure(err) {
+		return err
+	}
+
+	framework.Logf("Falling back to SPDY for %s of %s/%s: %v", session.Kind, session.Namespace, session.Pod, err)
+	session.Protocol = StreamingSPDY
+	executor, err = h.newExecutor(StreamingSPDY, "POST", u)
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, options)
+}
+
+func (h *StreamingHarness) executor(protocol StreamingProtocol, method string, u *url.URL) (remotecommand.Executor, error) {
+	if protocol == StreamingWebSocket {
+		return remotecommand.NewWebSocketExecutor(h.config, method, u.String())
+	}
+	return remotecommand.NewSPDYExecutor(h.config, method, u)
+}
+
+func (h *StreamingHarness) finish(session *StreamingSession) (*StreamingSession, error) {
+	session.End = time.Now()
+	if session.Err != nil {
+		framework.Logf("%s of %s/%s over %s failed: %v\n%s", session.Kind, session.Namespace, session.Pod, session.Protocol, session.Err, session.Transcript)
+	}
+	h.record(session)
+	return session, session.Err
+}
+
+// StreamingBackendName is the disruption backend that sessions of the kind are recorded as.
+func StreamingBackendName(kind StreamingKind) string {
+	return "pod-streaming-" + string(kind)
+}
+
+func (h *StreamingHarness) record(session *StreamingSession) {
+	if h.recorder == nil {
+		return
+	}
+	locator := monitorapi.NewLocator().LocateDisruptionCheck(
+		StreamingBackendName(session.Kind),
+		fmt.Sprintf("%s over %s", session.Kind, session.Protocol),
+		monitorapi.NewConnectionType,
+	)
+	if session.Err == nil {
+		h.recorder.AddIntervals(
+			monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Info).
+				Locator(locator).
+				Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionEndedEventReason).
+					HumanMessagef("%s of %s/%s over %s succeeded", session.Kind, session.Namespace, session.Pod, session.Protocol)).
+				Build(session.Start, session.End),
+		)
+		return
+	}
+	h.recorder.AddIntervals(
+		monitorapi.NewInterval(monitorapi.SourceDisruption, monitorapi.Error).
+			Locator(locator).
+			Message(monitorapi.NewMessage().Reason(monitorapi.DisruptionBeganEventReason).
+				HumanMessagef("%s of %s/%s over %s failed: %v", session.Kind, session.Namespace, session.Pod, session.Protocol, session.Err)).
+			Display().
+			Build(session.Start, session.End),
+	)
+}
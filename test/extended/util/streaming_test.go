@@ -0,0 +1,109 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// fakeExecutor streams by calling stream.
+type fakeExecutor struct {
+	stream func(ctx context.Context, options remotecommand.StreamOptions) error
+}
+
+func (e *fakeExecutor) Stream(options remotecommand.StreamOptions) error {
+	return e.StreamWithContext(context.Background(), options)
+}
+
+func (e *fakeExecutor) StreamWithContext(ctx context.Context, options remotecommand.StreamOptions) error {
+	return e.stream(ctx, options)
+}
+
+func fakeStreamingHarness(t *testing.T, executors map[StreamingProtocol]*fakeExecutor) (*StreamingHarness, *intervalRecorder) {
+	config := &rest.Config{Host: "https://api.cluster.example.com:6443"}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := &intervalRecorder{}
+	h := NewStreamingHarness(config, client).WithRecorder(recorder)
+	h.newExecutor = func(protocol StreamingProtocol, method string, u *url.URL) (remotecommand.Executor, error) {
+		executor, ok := executors[protocol]
+		if !ok {
+			return nil, fmt.Errorf("no %s executor", protocol)
+		}
+		return executor, nil
+	}
+	return h, recorder
+}
+
+func TestStreamingHarnessExec(t *testing.T) {
+	h, recorder := fakeStreamingHarness(t, map[StreamingProtocol]*fakeExecutor{
+		StreamingWebSocket: {stream: func(ctx context.Context, options remotecommand.StreamOptions) error {
+			return &httpstream.UpgradeFailureError{Cause: errors.New("websockets are not served")}
+		}},
+		StreamingSPDY: {stream: func(ctx context.Context, options remotecommand.StreamOptions) error {
+			in, err := io.ReadAll(options.Stdin)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(options.Stdout, "read %s", in)
+			return nil
+		}},
+	})
+
+	session, err := h.Exec(context.Background(), "e2e-test", "pod", "container", []string{"cat"}, strings.NewReader("input"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Protocol != StreamingSPDY {
+		t.Errorf("expected to fall back to SPDY, got %v", session.Protocol)
+	}
+	if stdin, stdout := session.Transcript.Stream("stdin"), session.Transcript.Stream("stdout"); stdin != "input" || stdout != "read input" {
+		t.Errorf("unexpected transcript:\n%s", session.Transcript)
+	}
+	if len(recorder.intervals) != 1 {
+		t.Fatalf("expected the session to be recorded, got %v", recorder.intervals)
+	}
+	interval := recorder.intervals[0]
+	if interval.Locator.Keys[monitorapi.LocatorBackendDisruptionNameKey] != "pod-streaming-exec" || interval.Message.Reason != monitorapi.DisruptionEndedEventReason {
+		t.Errorf("unexpected interval %v", interval)
+	}
+}
+
+func TestStreamingHarnessAttach(t *testing.T) {
+	untilDetached := &fakeExecutor{stream: func(ctx context.Context, options remotecommand.StreamOptions) error {
+		fmt.Fprintln(options.Stdout, "log line")
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	h, recorder := fakeStreamingHarness(t, map[StreamingProtocol]*fakeExecutor{StreamingWebSocket: untilDetached})
+
+	session, err := h.Attach(context.Background(), "e2e-test", "pod", "container", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected detaching after the duration to succeed, got %v", err)
+	}
+	if session.Protocol != StreamingWebSocket || session.Transcript.Stream("stdout") != "log line\n" {
+		t.Errorf("unexpected session %v:\n%s", session.Protocol, session.Transcript)
+	}
+
+	// a session that outlasts the timeout is a failure, and disruption.
+	if _, err := h.WithTimeout(10*time.Millisecond).Attach(context.Background(), "e2e-test", "pod", "container", time.Minute); err == nil {
+		t.Errorf("expected running out of time to fail")
+	}
+	interval := recorder.intervals[len(recorder.intervals)-1]
+	if interval.Level != monitorapi.Error || interval.Message.Reason != monitorapi.DisruptionBeganEventReason {
+		t.Errorf("expected the failure to be recorded as disruption, got %v", interval)
+	}
+}